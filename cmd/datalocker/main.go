@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"DataLocker/internal/bench"
+	"DataLocker/internal/remote"
+)
+
+func main() {
+	if len(os.Args) < 2 || (os.Args[1] != "remote" && os.Args[1] != "bench") {
+		fmt.Fprintln(os.Stderr, "사용법: datalocker remote [옵션] <files|volumes|stats> ...")
+		fmt.Fprintln(os.Stderr, "        datalocker bench [옵션]")
+		os.Exit(remote.ExitUsage)
+	}
+
+	// Ctrl+C로 진행 중인 요청이나 측정을 취소
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	var code int
+	switch os.Args[1] {
+	case "bench":
+		code = bench.Run(ctx, os.Args[2:], bench.Streams{Out: os.Stdout, Err: os.Stderr})
+	default:
+		code = remote.Run(ctx, os.Args[2:], remote.Streams{In: os.Stdin, Out: os.Stdout, Err: os.Stderr})
+	}
+	stop()
+
+	os.Exit(code)
+}
@@ -0,0 +1,627 @@
+// Package app assembles DataLocker server components in one place.
+// This file defines the dependency container and its initialization order.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/database"
+	"DataLocker/internal/handler"
+	"DataLocker/internal/middleware"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+	"DataLocker/pkg/crypto"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Repos 컨테이너가 조립한 저장소
+type Repos struct {
+	Files       repository.FileRepository
+	Encryption  repository.EncryptionRepository
+	Validation  repository.ValidationRepository
+	Metrics     repository.MetricsRepository
+	Idempotency repository.IdempotencyRepository
+	Tx          repository.TxManager
+	Writer      *repository.WriteSerializer // DB_SERIALIZE_WRITES가 꺼져 있으면 nil
+	QueryStats  *repository.QueryStats      // DB_QUERY_METRICS가 꺼져 있으면 nil
+	FileLocker  repository.FileLocker       // 파일 단위 작업 직렬화 락 (FILE_LOCK_BACKEND)
+}
+
+// Services 컨테이너가 조립한 서비스
+type Services struct {
+	Storage           service.StorageService
+	Search            service.SearchService
+	Dedup             service.DedupService
+	Upload            service.UploadService
+	Validation        service.ValidationService
+	ValidationSession service.ValidationSessionService
+	Preview           service.PreviewService
+	Transcode         service.TranscodeService
+	FileCode          service.FileCodeService
+	Integrity         service.IntegrityService
+	Admin             service.AdminService
+	Stats             service.StatsService
+	Activity          service.ActivityService
+	Metrics           service.MetricsService
+	Health            service.HealthService
+	Idempotency       service.IdempotencyService
+	Consistency       service.ConsistencyService
+	FactoryReset      service.FactoryResetService // DB 없이 조립하면 nil
+}
+
+// Handlers 컨테이너가 조립한 HTTP 핸들러
+type Handlers struct {
+	Health       *handler.HealthHandler
+	Search       *handler.SearchHandler
+	Negotiate    *handler.NegotiateHandler
+	Upload       *handler.UploadHandler
+	Admin        *handler.AdminHandler
+	Limits       *handler.LimitsHandler
+	Meta         *handler.MetaHandler
+	Validation   *handler.ValidationHandler
+	Preview      *handler.PreviewHandler
+	Transcode    *handler.TranscodeHandler
+	FileCode     *handler.FileCodeHandler
+	Config       *handler.ConfigHandler
+	Stats        *handler.StatsHandler
+	Activity     *handler.ActivityHandler
+	Consistency  *handler.ConsistencyHandler
+	FactoryReset *handler.FactoryResetHandler // 공장 초기화 서비스가 없으면 nil
+}
+
+// Container 서버 구성요소와 초기화/정리 순서를 관리하는 컨테이너
+//
+// New가 설정 → 로거 → DB → 저장소 → 서비스 → 핸들러 순서로 조립하고, Close는
+// 컨테이너가 직접 만든 자원만 만든 순서의 역순으로 정리합니다.
+type Container struct {
+	Config     *config.Config
+	Reloadable *config.ReloadableConfig
+	Logger     *logrus.Logger
+	Database   *database.Database // WithFileRepository 등으로 DB 없이 조립하면 nil
+	Repos      Repos
+	Services   Services
+	Handlers   Handlers
+
+	// PasswordLimiter 파일+IP별 패스워드 시도 제한 (설정 리로드 시 제한값 교체)
+	PasswordLimiter *middleware.PasswordAttemptLimiter
+
+	// CodeLookupLimiter IP별 짧은 파일 코드 조회 제한 (설정 리로드 시 제한값 교체)
+	CodeLookupLimiter *middleware.PasswordAttemptLimiter
+
+	// AccessLog 상태 코드 그룹별 요청 로그 샘플링 (설정 리로드 시 비율 교체, Server.Start에서 요약 시작)
+	AccessLog *middleware.AccessLogSampler
+
+	// SlowRequests 느린 요청 프로파일 캡처기 (SLOW_REQUEST_PROFILE_ENABLED가 꺼져 있으면 nil)
+	SlowRequests *middleware.SlowRequestProfiler
+
+	// Links 파일 응답 링크 빌더 (Router에서 등록된 라우트에 맞춤)
+	Links *handler.LinkBuilder
+
+	configPath string
+	closers    []closer
+}
+
+// closer 정리 함수와 로그에 남길 구성요소 이름
+type closer struct {
+	name  string
+	close func() error
+}
+
+// Option 컨테이너 조립을 바꾸는 옵션 함수
+//
+// 옵션으로 넣은 구성요소는 컨테이너가 만들지 않으므로 Close에서 정리하지 않습니다.
+type Option func(*Container)
+
+// WithConfigPath 설정 리로드에 사용할 설정 파일 경로를 지정합니다
+func WithConfigPath(path string) Option {
+	return func(c *Container) { c.configPath = path }
+}
+
+// WithLogger 로거를 지정합니다 (기본값은 설정의 레벨/환경에 맞춘 로거)
+func WithLogger(logger *logrus.Logger) Option {
+	return func(c *Container) { c.Logger = logger }
+}
+
+// WithDatabase 이미 연결된 데이터베이스를 사용합니다 (마이그레이션하지 않음)
+func WithDatabase(db *database.Database) Option {
+	return func(c *Container) { c.Database = db }
+}
+
+// WithFileRepository 파일 저장소를 지정합니다 (쓰기 직렬화를 적용하지 않음)
+func WithFileRepository(repo repository.FileRepository) Option {
+	return func(c *Container) { c.Repos.Files = repo }
+}
+
+// WithEncryptionRepository 암호화 메타데이터 저장소를 지정합니다
+func WithEncryptionRepository(repo repository.EncryptionRepository) Option {
+	return func(c *Container) { c.Repos.Encryption = repo }
+}
+
+// WithValidationRepository 검증 세션 저장소를 지정합니다
+func WithValidationRepository(repo repository.ValidationRepository) Option {
+	return func(c *Container) { c.Repos.Validation = repo }
+}
+
+// WithMetricsRepository 일별 통계 스냅샷 저장소를 지정합니다
+func WithMetricsRepository(repo repository.MetricsRepository) Option {
+	return func(c *Container) { c.Repos.Metrics = repo }
+}
+
+// WithIdempotencyRepository Idempotency-Key 기록 저장소를 지정합니다
+func WithIdempotencyRepository(repo repository.IdempotencyRepository) Option {
+	return func(c *Container) { c.Repos.Idempotency = repo }
+}
+
+// WithTxManager 트랜잭션 관리자를 지정합니다
+func WithTxManager(txManager repository.TxManager) Option {
+	return func(c *Container) { c.Repos.Tx = txManager }
+}
+
+// WithStorageService 저장소 볼륨 서비스를 지정합니다
+func WithStorageService(storage service.StorageService) Option {
+	return func(c *Container) { c.Services.Storage = storage }
+}
+
+// WithUploadService 업로드 서비스를 지정합니다
+func WithUploadService(upload service.UploadService) Option {
+	return func(c *Container) { c.Services.Upload = upload }
+}
+
+// WithDedupService 업로드 협상 서비스를 지정합니다
+func WithDedupService(dedup service.DedupService) Option {
+	return func(c *Container) { c.Services.Dedup = dedup }
+}
+
+// WithPreviewService 미리보기 서비스를 지정합니다
+func WithPreviewService(preview service.PreviewService) Option {
+	return func(c *Container) { c.Services.Preview = preview }
+}
+
+// WithTranscodeService 트랜스코드 서비스를 지정합니다
+func WithTranscodeService(transcode service.TranscodeService) Option {
+	return func(c *Container) { c.Services.Transcode = transcode }
+}
+
+// WithFileCodeService 짧은 파일 코드 서비스를 지정합니다
+func WithFileCodeService(fileCode service.FileCodeService) Option {
+	return func(c *Container) { c.Services.FileCode = fileCode }
+}
+
+// WithAdminService 관리 서비스를 지정합니다
+func WithAdminService(admin service.AdminService) Option {
+	return func(c *Container) { c.Services.Admin = admin }
+}
+
+// New 설정으로 서버 구성요소를 조립합니다
+//
+// 중간에 실패하면 그때까지 만든 자원을 정리한 뒤 에러를 반환합니다.
+// 성공하면 사용이 끝난 뒤 Close를 호출해야 합니다.
+func New(cfg *config.Config, opts ...Option) (*Container, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("설정이 필요합니다")
+	}
+
+	c := &Container{Config: cfg}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.Logger == nil {
+		c.Logger = NewLogger(cfg)
+	}
+	c.Reloadable = config.NewReloadableConfig(c.configPath, cfg, c.Logger)
+
+	// 암호화 자체 점검 (실패하면 요청을 받지 않음)
+	if err := crypto.CachedSelfTest(); err != nil {
+		return nil, fmt.Errorf("암호화 자체 점검 실패: %w", err)
+	}
+
+	steps := []struct {
+		name  string
+		build func() error
+	}{
+		{"데이터베이스", c.buildDatabase},
+		{"저장소", c.buildRepos},
+		{"서비스", c.buildServices},
+		{"핸들러", c.buildHandlers},
+		{"공장 초기화", c.buildFactoryReset},
+	}
+	for _, step := range steps {
+		if err := step.build(); err != nil {
+			if closeErr := c.Close(); closeErr != nil {
+				c.Logger.WithError(closeErr).Error("초기화 실패 후 정리 중 오류가 발생했습니다")
+			}
+			return nil, fmt.Errorf("%s 초기화 실패: %w", step.name, err)
+		}
+	}
+
+	return c, nil
+}
+
+// Close 컨테이너가 만든 자원을 만든 순서의 역순으로 정리합니다
+//
+// 하나가 실패해도 나머지를 계속 정리하고 모든 에러를 합쳐 반환합니다.
+// 두 번 호출해도 안전합니다.
+func (c *Container) Close() error {
+	var errs []error
+	for i := len(c.closers) - 1; i >= 0; i-- {
+		if err := c.closers[i].close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s 정리 실패: %w", c.closers[i].name, err))
+		}
+	}
+	c.closers = nil
+	return errors.Join(errs...)
+}
+
+// onClose Close에서 실행할 정리 함수를 등록합니다
+func (c *Container) onClose(name string, fn func() error) {
+	c.closers = append(c.closers, closer{name: name, close: fn})
+}
+
+// needsDatabase 옵션으로 채우지 않은 저장소가 있어 DB 연결이 필요한지 확인합니다
+func (c *Container) needsDatabase() bool {
+	return c.Repos.Files == nil || c.Repos.Encryption == nil || c.Repos.Validation == nil || c.Repos.Metrics == nil ||
+		c.Repos.Idempotency == nil || c.Repos.Tx == nil
+}
+
+// buildDatabase 데이터베이스에 연결하고 설정에 따라 마이그레이션합니다
+func (c *Container) buildDatabase() error {
+	if c.Database != nil || !c.needsDatabase() {
+		return nil
+	}
+
+	db, err := database.NewDatabase(c.Config)
+	if err != nil {
+		return err
+	}
+	c.Database = db
+	c.onClose("데이터베이스", db.Close)
+
+	if !c.Config.Database.AutoMigrate {
+		c.warnPendingMigrations(db.DB)
+		return nil
+	}
+
+	applied, err := model.MigrateUp(db.DB)
+	if err != nil {
+		return fmt.Errorf("마이그레이션 실패: %w", err)
+	}
+	if len(applied) > 0 {
+		c.Logger.WithField("migrations", applied).Info("스키마 마이그레이션을 적용했습니다")
+	}
+
+	return nil
+}
+
+// warnPendingMigrations 자동 마이그레이션이 꺼져 있을 때 적용하지 않은 마이그레이션을 경고합니다
+func (c *Container) warnPendingMigrations(db *gorm.DB) {
+	states, err := model.MigrationStatus(db)
+	if err != nil {
+		c.Logger.WithError(err).Warn("마이그레이션 상태를 확인하지 못했습니다")
+		return
+	}
+
+	var pending []string
+	for _, state := range states {
+		if !state.Applied {
+			pending = append(pending, state.ID)
+		}
+	}
+	if len(pending) > 0 {
+		c.Logger.WithField("migrations", pending).Warn("적용하지 않은 스키마 마이그레이션이 있습니다 (DB_AUTO_MIGRATE=false)")
+	}
+}
+
+// buildRepos 저장소와 트랜잭션 관리자를 생성합니다
+//
+// DB_SERIALIZE_WRITES가 켜져 있으면 직접 만든 저장소와 트랜잭션 관리자의 쓰기를
+// 단일 대기열로 직렬화합니다. 직렬화기는 DB보다 먼저 닫혀 남은 쓰기를 마무리합니다.
+func (c *Container) buildRepos() error {
+	var writer *repository.WriteSerializer
+	if c.Config.Database.SerializeWrites && c.needsDatabase() {
+		writer = repository.NewWriteSerializer(c.Config.Database.WriteQueueSize)
+		c.Repos.Writer = writer
+		c.onClose("DB 쓰기 직렬화기", func() error {
+			writer.Close()
+			return nil
+		})
+		c.Logger.WithField("queue_size", c.Config.Database.WriteQueueSize).Info("DB 쓰기 직렬화를 사용합니다")
+	}
+
+	// 저장소 호출 계측 (설정이 모두 꺼져 있으면 훅이 nil이라 저장소를 감싸지 않음)
+	var instrumenters []repository.Instrumenter
+	if threshold := c.Config.Database.SlowQueryThreshold; threshold > 0 {
+		instrumenters = append(instrumenters, repository.NewLogInstrumenter(c.Logger, threshold))
+	}
+	if c.Config.Database.QueryMetrics {
+		c.Repos.QueryStats = repository.NewQueryStats()
+		instrumenters = append(instrumenters, c.Repos.QueryStats)
+	}
+	repoOpts := []repository.Option{repository.WithInstrumenter(repository.JoinInstrumenters(instrumenters...))}
+
+	if c.Repos.Files == nil {
+		c.Repos.Files = repository.NewFileRepository(c.Database.DB, repoOpts...)
+		if writer != nil {
+			c.Repos.Files = repository.NewSerializedFileRepository(c.Repos.Files, writer)
+		}
+	}
+	if c.Repos.Encryption == nil {
+		c.Repos.Encryption = repository.NewEncryptionRepository(c.Database.DB, repoOpts...)
+	}
+	if c.Repos.Validation == nil {
+		c.Repos.Validation = repository.NewValidationRepository(c.Database.DB)
+		if writer != nil {
+			c.Repos.Validation = repository.NewSerializedValidationRepository(c.Repos.Validation, writer)
+		}
+	}
+	if c.Repos.Metrics == nil {
+		c.Repos.Metrics = repository.NewMetricsRepository(c.Database.DB)
+		if writer != nil {
+			c.Repos.Metrics = repository.NewSerializedMetricsRepository(c.Repos.Metrics, writer)
+		}
+	}
+	if c.Repos.Idempotency == nil {
+		c.Repos.Idempotency = repository.NewIdempotencyRepository(c.Database.DB)
+		if writer != nil {
+			c.Repos.Idempotency = repository.NewSerializedIdempotencyRepository(c.Repos.Idempotency, writer)
+		}
+	}
+	if c.Repos.Tx == nil {
+		c.Repos.Tx = repository.NewTxManager(c.Database.DB, repoOpts...)
+		if writer != nil {
+			c.Repos.Tx = repository.NewSerializedTxManager(c.Repos.Tx, writer)
+		}
+	}
+	if c.Repos.FileLocker == nil {
+		dbCfg := c.Config.Database
+		switch dbCfg.FileLockBackend {
+		case config.FileLockBackendMemory, "":
+			c.Repos.FileLocker = repository.NewMemoryFileLocker(dbCfg.FileLockWait)
+		case config.FileLockBackendDatabase:
+			c.Repos.FileLocker = repository.NewDBFileLocker(c.Database.DB, dbCfg.FileLockTTL, dbCfg.FileLockWait)
+			c.Logger.WithField("ttl", dbCfg.FileLockTTL).Info("데이터베이스 파일 락을 사용합니다")
+		default:
+			return fmt.Errorf("지원하지 않는 파일 락 백엔드입니다: %s", dbCfg.FileLockBackend)
+		}
+	}
+
+	return nil
+}
+
+// buildServices 서비스를 의존 순서대로 생성합니다
+func (c *Container) buildServices() error {
+	cfg, logger, repos := c.Config, c.Logger, c.Repos
+	s := &c.Services
+
+	// 키 유도 비용 결정 (PBKDF2_ITERATIONS=auto이면 호스트에 맞게 보정)
+	cfg.Security.PBKDF2Iterations = resolveIterations(cfg.Security, logger)
+
+	if s.Storage == nil {
+		storage, err := service.NewStorageService(cfg.Storage, repos.Files, repos.FileLocker, logger)
+		if err != nil {
+			return fmt.Errorf("저장소 볼륨 설정이 올바르지 않습니다: %w", err)
+		}
+		s.Storage = storage
+	}
+	if s.Search == nil {
+		s.Search = service.NewSearchService(cfg.Security, repos.Files)
+	}
+	if s.Dedup == nil {
+		s.Dedup = service.NewDedupService(cfg.Security, repos.Files)
+	}
+	if s.Upload == nil {
+		reuse, err := service.NewPasswordReuseService(cfg.Security, repos.Encryption, logger)
+		if err != nil {
+			return fmt.Errorf("패스워드 재사용 감지 설정이 올바르지 않습니다: %w", err)
+		}
+		s.Upload = service.NewUploadService(s.Storage, cfg.Security.PBKDF2Iterations, repos.Tx, reuse, logger)
+	}
+	if s.Validation == nil {
+		s.Validation = service.NewValidationService(cfg.Upload)
+	}
+	if s.ValidationSession == nil {
+		s.ValidationSession = service.NewValidationSessionService(s.Validation, repos.Validation, logger)
+	}
+	if s.Preview == nil {
+		s.Preview = service.NewPreviewService(repos.Files, s.Storage)
+	}
+	if s.Transcode == nil {
+		s.Transcode = service.NewTranscodeService(repos.Files, s.Storage,
+			cfg.Security.PBKDF2Iterations, cfg.Security.MaxConcurrentTranscodes, logger)
+	}
+	if s.FileCode == nil {
+		s.FileCode = service.NewFileCodeService(repos.Files)
+	}
+	if s.Integrity == nil {
+		s.Integrity = service.NewIntegrityService(repos.Files, s.Storage, logger)
+	}
+	if s.Consistency == nil {
+		s.Consistency = service.NewConsistencyService(repos.Files, s.Storage, logger)
+	}
+	if s.Admin == nil {
+		s.Admin = service.NewAdminService(repos.Files, repos.Tx, s.Storage, s.Integrity, repos.FileLocker, logger)
+	}
+	if s.Stats == nil {
+		s.Stats = service.NewStatsService(repos.Files)
+	}
+	if s.Activity == nil {
+		s.Activity = service.NewActivityService(repos.Files)
+	}
+	if s.Metrics == nil {
+		s.Metrics = service.NewMetricsService(cfg.Metrics, repos.Metrics)
+	}
+	if s.Idempotency == nil {
+		s.Idempotency = service.NewIdempotencyService(repos.Idempotency, logger)
+	}
+	if s.Health == nil {
+		checks := map[string]service.HealthCheckFunc{"filesystem": s.Storage.CheckVolumes}
+		if c.Database != nil {
+			checks["database"] = c.Database.HealthCheck
+		}
+		s.Health = service.NewHealthService(cfg.Server.HealthCheckTimeout, checks, logger)
+	}
+
+	return nil
+}
+
+// buildHandlers 핸들러와 요청 단위 제한기를 생성합니다
+func (c *Container) buildHandlers() error {
+	s := c.Services
+
+	c.Handlers = Handlers{
+		Health:      handler.NewHealthHandler(c.Config),
+		Search:      handler.NewSearchHandler(s.Search),
+		Negotiate:   handler.NewNegotiateHandler(s.Dedup),
+		Upload:      handler.NewUploadHandler(s.Dedup, s.Upload, c.Logger),
+		Admin:       handler.NewAdminHandler(s.Admin),
+		Limits:      handler.NewLimitsHandler(s.Validation),
+		Meta:        handler.NewMetaHandler(),
+		Validation:  handler.NewValidationHandler(s.ValidationSession),
+		Preview:     handler.NewPreviewHandler(s.Preview),
+		Transcode:   handler.NewTranscodeHandler(s.Transcode),
+		FileCode:    handler.NewFileCodeHandler(s.FileCode),
+		Config:      handler.NewConfigHandler(c.Reloadable),
+		Stats:       handler.NewStatsHandler(s.Stats, s.Metrics),
+		Activity:    handler.NewActivityHandler(s.Activity),
+		Consistency: handler.NewConsistencyHandler(s.Consistency),
+	}
+	c.Handlers.Health.SetHealthService(s.Health)
+	if c.Repos.Writer != nil {
+		c.Handlers.Health.SetWriteStats(c.Repos.Writer)
+	}
+	if c.Repos.QueryStats != nil {
+		c.Handlers.Health.SetQueryStats(c.Repos.QueryStats)
+	}
+
+	// 파일 응답 링크 (공개 주소가 없으면 신뢰하는 프록시의 전달 헤더로 결정)
+	links, err := handler.NewLinkBuilder(c.Config.Server.PublicBaseURL,
+		middleware.NewProxyTrust(c.Config.Security.TrustedProxies, c.Logger))
+	if err != nil {
+		return err
+	}
+	c.Links = links
+	c.Handlers.Admin.SetLinkBuilder(links)
+	c.Handlers.Upload.SetLinkBuilder(links)
+	c.Handlers.FileCode.SetLinkBuilder(links)
+	c.Handlers.Upload.SetMaxDecodedSize(c.Config.Security.MaxFileSize)
+
+	// 파일+IP별 패스워드 시도 제한 (환경과 무관하게 적용)
+	c.PasswordLimiter = middleware.NewPasswordAttemptLimiter(
+		c.Config.Security.PasswordAttemptsPerMinute, c.Config.Security.PasswordAttemptMaxKeys)
+
+	// IP별 짧은 파일 코드 조회 제한 (코드 추측 방지, 추적 키 수 상한은 패스워드 시도와 같음)
+	c.CodeLookupLimiter = middleware.NewPasswordAttemptLimiter(
+		c.Config.Security.CodeLookupsPerMinute, c.Config.Security.PasswordAttemptMaxKeys)
+
+	// 요청 로그 샘플링 (생략한 수는 주기적으로 요약)
+	c.AccessLog = middleware.NewAccessLogSampler(c.Config.App.AccessLog, c.Logger)
+
+	// 임계값을 넘긴 요청의 고루틴 덤프 (설정된 경우)
+	if c.Config.App.SlowRequest.ProfileEnabled {
+		c.SlowRequests = middleware.NewSlowRequestProfiler(c.Config.App.SlowRequest, c.Logger)
+		if c.Database != nil {
+			c.SlowRequests.SetDBStats(c.Database.GetStats)
+		}
+	}
+
+	return nil
+}
+
+// buildFactoryReset 공장 초기화 서비스와 핸들러를 생성합니다
+//
+// 요청 단위 제한기까지 비우므로 핸들러 다음에 만들며, 지울 DB가 없으면
+// (WithFileRepository 등으로 조립) 만들지 않습니다.
+func (c *Container) buildFactoryReset() error {
+	if c.Database == nil {
+		return nil
+	}
+
+	db := c.Database.DB
+	resetDB := func(ctx context.Context) error {
+		return model.ResetDatabase(db.WithContext(ctx))
+	}
+
+	// 볼륨의 암호화 파일 (수집함 감시도 볼륨에 저장)
+	var dirs []string
+	for _, volume := range c.Config.Storage.EffectiveVolumes() {
+		dirs = append(dirs, volume.Path)
+	}
+
+	resetters := map[string]service.Resetter{
+		"password_attempts": c.PasswordLimiter,
+		"code_lookups":      c.CodeLookupLimiter,
+	}
+	if r, ok := c.Services.Activity.(service.Resetter); ok {
+		resetters["activity_feed"] = r
+	}
+	if r, ok := c.Services.Dedup.(service.Resetter); ok {
+		resetters["upload_sessions"] = r
+	}
+
+	c.Services.FactoryReset = service.NewFactoryResetService(resetDB, dirs, resetters, c.Logger)
+	c.Handlers.FactoryReset = handler.NewFactoryResetHandler(c.Services.FactoryReset)
+	return nil
+}
+
+// NewLogger 설정의 로그 레벨과 환경에 맞춘 로거를 생성합니다
+func NewLogger(cfg *config.Config) *logrus.Logger {
+	logger := logrus.New()
+
+	// 로그 레벨 설정
+	logger.SetLevel(parseLogLevel(cfg.App.LogLevel))
+
+	// 개발환경에서는 텍스트 포맷, 운영환경에서는 JSON 포맷
+	if cfg.App.Environment == "development" {
+		logger.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp: true,
+			ForceColors:   true,
+		})
+	} else {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	}
+
+	return logger
+}
+
+// parseLogLevel 로그 레벨 이름을 변환합니다 (알 수 없는 이름은 info)
+func parseLogLevel(name string) logrus.Level {
+	level, err := logrus.ParseLevel(name)
+	if err != nil {
+		return logrus.InfoLevel
+	}
+	return level
+}
+
+// resolveIterations 새 파일에 사용할 PBKDF2 반복 횟수를 결정합니다
+//
+// 자동 보정에 실패하거나 설정값이 허용 범위를 벗어나면 기본값을 사용합니다.
+// 반복 횟수는 파일마다 기록되므로 값이 바뀌어도 기존 파일 복호화에는 영향이 없습니다.
+func resolveIterations(cfg config.SecurityConfig, logger *logrus.Logger) int {
+	if cfg.PBKDF2AutoCalibrate {
+		iterations, err := crypto.CalibrateIterations(cfg.PBKDF2TargetDuration)
+		if err != nil {
+			logger.WithError(err).Warn("PBKDF2 반복 횟수 보정에 실패해 기본값을 사용합니다")
+			return config.DefaultPBKDF2Iterations
+		}
+
+		logger.WithFields(logrus.Fields{
+			"iterations": iterations,
+			"target":     cfg.PBKDF2TargetDuration.String(),
+		}).Info("PBKDF2 반복 횟수를 호스트에 맞게 보정했습니다")
+		return iterations
+	}
+
+	if cfg.PBKDF2Iterations < crypto.MinIterations || cfg.PBKDF2Iterations > crypto.MaxIterations {
+		logger.WithField("iterations", cfg.PBKDF2Iterations).
+			Warn("PBKDF2 반복 횟수가 허용 범위를 벗어나 기본값을 사용합니다")
+		return config.DefaultPBKDF2Iterations
+	}
+
+	return cfg.PBKDF2Iterations
+}
@@ -0,0 +1,274 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminToken = "admin-secret-token"
+
+// newTestConfig 임시 디렉터리를 사용하는 테스트용 설정을 생성합니다
+func newTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+
+	cfg := config.Load()
+	cfg.App.Environment = "test"
+	cfg.Database.Path = filepath.Join(dir, "app.db")
+	cfg.Database.AutoMigrate = true
+	cfg.Storage = config.StorageConfig{Dir: filepath.Join(dir, "storage")}
+	cfg.Security.AdminAPIToken = testAdminToken
+	cfg.Security.PBKDF2AutoCalibrate = false
+	cfg.Watch.Dirs = nil
+	return cfg
+}
+
+// newSilentLogger 출력을 버리는 테스트용 로거를 생성합니다
+func newSilentLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// stubAdminService 목록 조회만 고정 결과를 반환하는 관리 서비스
+type stubAdminService struct {
+	service.AdminService
+	calls int
+}
+
+func (s *stubAdminService) ListFiles(context.Context, repository.FileFilter, int, int, repository.SortOption) (*service.AdminFileList, error) {
+	s.calls++
+	return &service.AdminFileList{Page: 1, PageSize: 10}, nil
+}
+
+func TestNew_AssemblesAllComponents(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.Database.SerializeWrites = true
+
+	c, err := New(cfg, WithLogger(newSilentLogger()))
+	require.NoError(t, err)
+
+	require.NotNil(t, c.Database)
+	assert.NotNil(t, c.Repos.Writer, "쓰기 직렬화 설정 반영")
+
+	// DB_AUTO_MIGRATE로 모든 스키마 마이그레이션을 적용
+	states, err := model.MigrationStatus(c.Database.DB)
+	require.NoError(t, err)
+	require.NotEmpty(t, states)
+	for _, state := range states {
+		assert.True(t, state.Applied, state.ID)
+	}
+	assert.NotNil(t, c.Repos.Files)
+	assert.NotNil(t, c.Services.Admin)
+	assert.NotNil(t, c.Handlers.Upload)
+	assert.NotNil(t, c.PasswordLimiter)
+	assert.NotNil(t, c.AccessLog)
+
+	rec := httptest.NewRecorder()
+	router := c.Router()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/limits", http.NoBody))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 일별 통계는 관리 API 토큰이 있어야 조회
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stats/history", http.NoBody))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stats/history?days=7", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 활동 피드도 관리 API 토큰이 있어야 조회
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/activity", http.NoBody))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/activity?limit=20", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 파일 레코드를 반환하는 검색과 유사 중복 조회도 관리 API 토큰이 있어야 조회
+	for _, path := range []string{"/api/v1/search?q=report", "/api/v1/files/1/similar"} {
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/search?q=report", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 헬스체크는 실제 DB와 저장소 볼륨을 점검
+	require.NotNil(t, c.Services.Health)
+	results := c.Services.Health.Check(context.Background())
+	assert.Equal(t, service.HealthStatusHealthy, results["database"].Status)
+	assert.Equal(t, service.HealthStatusHealthy, results["filesystem"].Status)
+
+	// 정리 후에는 DB를 사용할 수 없고, 다시 정리해도 안전
+	sqlDB, err := c.Database.DB.DB()
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+	assert.Error(t, sqlDB.Ping())
+	assert.NoError(t, c.Close())
+}
+
+func TestNew_OptionsReplaceComponents(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig(t)
+	base, err := New(cfg, WithLogger(newSilentLogger()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = base.Close() })
+
+	// 저장소를 모두 넣으면 DB를 직접 열지 않음
+	admin := &stubAdminService{}
+	c, err := New(newTestConfig(t),
+		WithLogger(newSilentLogger()),
+		WithFileRepository(base.Repos.Files),
+		WithEncryptionRepository(base.Repos.Encryption),
+		WithValidationRepository(base.Repos.Validation),
+		WithMetricsRepository(base.Repos.Metrics),
+		WithIdempotencyRepository(base.Repos.Idempotency),
+		WithTxManager(base.Repos.Tx),
+		WithAdminService(admin),
+	)
+	require.NoError(t, err)
+	assert.Nil(t, c.Database)
+	assert.Same(t, admin, c.Services.Admin)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/files", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	rec := httptest.NewRecorder()
+	c.Router().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, admin.calls, "핸들러가 대체한 서비스를 사용")
+
+	// 옵션으로 받은 구성요소는 정리하지 않음
+	require.NoError(t, c.Close())
+	_, err = base.Repos.Files.Count(ctx)
+	assert.NoError(t, err)
+}
+
+func TestRouter_FactoryReset(t *testing.T) {
+	post := func(router http.Handler, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/factory-reset", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+testAdminToken)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	cfg := newTestConfig(t)
+	c, err := New(cfg, WithLogger(newSilentLogger()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	router := c.Router()
+
+	require.NoError(t, os.MkdirAll(cfg.Storage.Dir, 0o750))
+	blob := filepath.Join(cfg.Storage.Dir, "blob"+service.EncryptedFileExt)
+	require.NoError(t, os.WriteFile(blob, []byte("ciphertext"), 0o600))
+
+	// 토큰을 받아 다시 보내면 실행
+	rec := post(router, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var issued struct {
+		Data service.FactoryResetToken `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &issued))
+	require.NotEmpty(t, issued.Data.Token)
+
+	rec = post(router, `{"confirmation_token":"`+issued.Data.Token+`"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var reset struct {
+		Data service.FactoryResetResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reset))
+	assert.Equal(t, 1, reset.Data.DeletedFiles)
+	assert.Equal(t, int64(len("ciphertext")), reset.Data.DeletedBytes)
+	assert.Contains(t, reset.Data.ClearedCaches, "activity_feed")
+	assert.NoFileExists(t, blob)
+
+	// 초기화한 DB로 계속 요청을 처리
+	count, err := c.Repos.Files.Count(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	// 운영환경에서는 명시적으로 허용해야 라우트가 있음
+	prod := newTestConfig(t)
+	prod.App.Environment = "production"
+	c, err = New(prod, WithLogger(newSilentLogger()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	assert.Equal(t, http.StatusNotFound, post(c.Router(), "").Code)
+
+	allowed := newTestConfig(t)
+	allowed.App.Environment = "production"
+	allowed.Security.AllowFactoryReset = true
+	c, err = New(allowed, WithLogger(newSilentLogger()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	assert.Equal(t, http.StatusOK, post(c.Router(), "").Code)
+}
+
+func TestNew_FailureCleansUp(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.Storage.Volumes = []config.StorageVolume{{ID: "", Path: t.TempDir()}}
+
+	_, err := New(cfg, WithLogger(newSilentLogger()))
+	require.ErrorIs(t, err, service.ErrInvalidVolumes)
+	assert.ErrorContains(t, err, "서비스 초기화 실패")
+
+	// 실패한 조립이 연 DB는 닫혀 같은 파일로 다시 조립할 수 있음
+	cfg.Storage.Volumes = nil
+	c, err := New(cfg, WithLogger(newSilentLogger()))
+	require.NoError(t, err)
+	assert.NoError(t, c.Close())
+
+	_, err = New(nil)
+	assert.Error(t, err)
+
+	cfg.Server.PublicBaseURL = "files.example.com"
+	_, err = New(cfg, WithLogger(newSilentLogger()))
+	assert.ErrorContains(t, err, "핸들러 초기화 실패")
+}
+
+func TestContainer_CloseInReverseOrder(t *testing.T) {
+	c := &Container{}
+	var order []string
+	for _, name := range []string{"db", "writer", "cache"} {
+		c.onClose(name, func() error {
+			order = append(order, name)
+			if name == "writer" {
+				return errors.New("flush failed")
+			}
+			return nil
+		})
+	}
+
+	err := c.Close()
+	assert.Equal(t, []string{"cache", "writer", "db"}, order, "실패해도 나머지를 계속 정리")
+	assert.ErrorContains(t, err, "writer 정리 실패: flush failed")
+}
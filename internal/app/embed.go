@@ -0,0 +1,232 @@
+// Package app assembles DataLocker server components in one place.
+// This file runs the assembled server so other Go programs can start and stop it in-process.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"DataLocker/internal/config"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// shutdownTimeout Start의 컨텍스트가 취소된 뒤 처리 중인 요청을 기다리는 시간
+const shutdownTimeout = 10 * time.Second
+
+// 서버 실행 에러
+var (
+	// ErrServerStarted 이미 Start를 호출한 서버를 다시 시작하려 함
+	ErrServerStarted = errors.New("서버가 이미 시작되었습니다")
+
+	// ErrServerClosed Shutdown을 호출한 서버를 시작하려 함
+	ErrServerClosed = errors.New("이미 종료된 서버입니다")
+)
+
+// Server 프로세스 안에서 시작하고 멈출 수 있는 DataLocker 서버
+//
+// 데스크톱 셸처럼 서버를 라이브러리로 포함하는 프로그램은 NewServer로 만든 뒤
+// 고루틴에서 Start를 실행하고, 끝낼 때 Shutdown을 호출합니다. 한 서버는 한 번만
+// 시작할 수 있으며 종료한 뒤에는 다시 시작할 수 없습니다.
+type Server struct {
+	container *Container
+	echo      *echo.Echo
+
+	mu       sync.Mutex
+	started  bool
+	closed   bool
+	listener net.Listener
+
+	ready chan struct{} // 요청을 받기 시작하면 닫힘
+	done  chan struct{} // Start가 자원 정리를 마치면 닫힘
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+	closeOnce    sync.Once
+	closeErr     error
+}
+
+// NewServer 설정으로 구성요소를 조립하고 라우트를 등록한 서버를 생성합니다
+//
+// 옵션은 New와 같습니다. 조립에 실패하면 그때까지 만든 자원을 정리한 뒤 에러를
+// 반환하고, 성공하면 Start 여부와 관계없이 Shutdown을 호출해야 자원이 정리됩니다.
+func NewServer(cfg *config.Config, opts ...Option) (*Server, error) {
+	container, err := New(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		container: container,
+		echo:      container.Router(),
+		ready:     make(chan struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Container 서버가 사용하는 구성요소 컨테이너를 반환합니다
+func (s *Server) Container() *Container {
+	return s.container
+}
+
+// Ready 서버가 요청을 받기 시작하면 닫히는 채널을 반환합니다
+//
+// 포트를 열지 못해 Start가 실패하면 닫히지 않으므로 Start의 반환값과 함께 기다려야 합니다.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Addr 서버가 요청을 받는 실제 주소를 반환합니다 (시작 전이면 빈 문자열)
+//
+// 포트를 0으로 설정하면 운영체제가 고른 포트가 담깁니다.
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Start 시작 점검과 백그라운드 작업을 실행하고 서버가 멈출 때까지 요청을 처리합니다
+//
+// Shutdown을 호출하거나 ctx가 취소되면 처리 중인 요청을 마치고 자원을 정리한 뒤
+// 반환합니다. 정상 종료면 nil을 반환하고, 두 번째 호출은 ErrServerStarted를,
+// Shutdown 뒤 호출은 ErrServerClosed를 반환합니다. 종료 신호는 처리하지 않으므로
+// 신호로 멈추려면 signal.NotifyContext로 만든 컨텍스트를 넘깁니다.
+func (s *Server) Start(ctx context.Context) error {
+	s.mu.Lock()
+	switch {
+	case s.closed:
+		s.mu.Unlock()
+		return ErrServerClosed
+	case s.started:
+		s.mu.Unlock()
+		return ErrServerStarted
+	}
+	s.started = true
+	s.mu.Unlock()
+	defer close(s.done)
+
+	c := s.container
+	cfg, logger := c.Config, c.Logger
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(cfg.Server.Host, cfg.Server.Port))
+	if err != nil {
+		return errors.Join(fmt.Errorf("서버 시작 실패: %w", err), s.closeContainer())
+	}
+
+	c.runStartupTasks()
+	stopTasks := c.startBackgroundTasks()
+
+	// 시작 점검 중 Shutdown이 호출되었으면 요청을 받지 않고 정리
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = listener.Close()
+		stopTasks()
+		return s.closeContainer()
+	}
+	s.listener = listener
+	s.echo.Listener = listener
+	s.mu.Unlock()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.echo.Start("")
+	}()
+
+	logger.WithFields(logrus.Fields{
+		"address":     listener.Addr().String(),
+		"environment": cfg.App.Environment,
+		"version":     cfg.App.Version,
+	}).Info("서버를 시작합니다")
+	close(s.ready)
+
+	var runErr error
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			runErr = fmt.Errorf("서버 실행 실패: %w", err)
+		}
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		runErr = s.stopServing(shutdownCtx)
+		cancel()
+		<-serveErr
+	}
+
+	stopTasks()
+	closeErr := s.closeContainer()
+	if runErr == nil && closeErr == nil {
+		logger.Info("서버가 정상적으로 종료되었습니다")
+	}
+	return errors.Join(runErr, closeErr)
+}
+
+// Shutdown 새 요청을 막고 처리 중인 요청과 자원 정리가 끝날 때까지 기다립니다
+//
+// 여러 번 호출해도 안전하며 첫 호출의 결과를 반환합니다. 시작하지 않은 서버면
+// 구성요소만 정리합니다. ctx가 먼저 끝나면 ctx의 에러를 반환합니다.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.stopServing(ctx)
+
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+
+	if !started {
+		return errors.Join(err, s.closeContainer())
+	}
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return errors.Join(err, s.closeErr)
+}
+
+// ReloadOnSignal SIGHUP을 받으면 설정을 리로드하고, 정리 함수를 반환합니다
+//
+// 신호 처리는 프로세스 전체에 영향을 주므로 단독 실행하는 서버에서만 사용합니다.
+func (s *Server) ReloadOnSignal() func() {
+	return handleReloadSignal(s.container.Reloadable)
+}
+
+// stopServing 서버를 닫힌 상태로 표시하고 요청 처리를 멈춥니다 (한 번만 실행)
+func (s *Server) stopServing(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		listening := s.listener != nil
+		s.mu.Unlock()
+
+		if !listening {
+			return
+		}
+
+		s.container.Logger.Info("서버를 종료합니다...")
+		if err := s.echo.Shutdown(ctx); err != nil {
+			s.shutdownErr = fmt.Errorf("서버 종료 중 오류: %w", err)
+		}
+	})
+	return s.shutdownErr
+}
+
+// closeContainer 구성요소를 한 번만 정리하고 결과를 Shutdown에서 돌려줄 수 있게 보관합니다
+func (s *Server) closeContainer() error {
+	s.closeOnce.Do(func() {
+		if err := s.container.Close(); err != nil {
+			s.closeErr = fmt.Errorf("서버 구성요소 정리 실패: %w", err)
+		}
+	})
+	return s.closeErr
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestServer 임의 포트로 서버를 시작하고 요청을 받을 때까지 기다립니다
+//
+// Start의 반환값은 채널로 돌려줍니다.
+func startTestServer(t *testing.T, ctx context.Context) (*Server, <-chan error) {
+	t.Helper()
+	cfg := newTestConfig(t)
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = "0"
+
+	srv, err := NewServer(cfg, WithLogger(newSilentLogger()))
+	require.NoError(t, err)
+	assert.Empty(t, srv.Addr(), "시작 전에는 주소 없음")
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Start(ctx) }()
+
+	select {
+	case <-srv.Ready():
+	case err := <-done:
+		t.Fatalf("서버가 시작되지 않았습니다: %v", err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("서버 시작 대기 시간 초과")
+	}
+	return srv, done
+}
+
+// waitStopped Start가 반환할 때까지 기다립니다
+func waitStopped(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(10 * time.Second):
+		t.Fatal("서버 종료 대기 시간 초과")
+		return nil
+	}
+}
+
+func TestServer_StartAndShutdown(t *testing.T) {
+	srv, done := startTestServer(t, context.Background())
+
+	// 포트 0이면 운영체제가 고른 실제 포트를 알려줌
+	host, port, err := net.SplitHostPort(srv.Addr())
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host)
+	assert.NotEqual(t, "0", port)
+
+	resp, err := http.Get("http://" + srv.Addr() + "/api/v1/limits")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// 실행 중에 다시 시작할 수 없음
+	assert.ErrorIs(t, srv.Start(context.Background()), ErrServerStarted)
+
+	sqlDB, err := srv.Container().Database.DB.DB()
+	require.NoError(t, err)
+	require.NoError(t, srv.Shutdown(context.Background()))
+	assert.NoError(t, waitStopped(t, done), "정상 종료는 에러 없음")
+
+	// 종료는 여러 번 호출해도 안전하고, 종료한 서버는 다시 시작할 수 없음
+	assert.NoError(t, srv.Shutdown(context.Background()))
+	assert.ErrorIs(t, srv.Start(context.Background()), ErrServerClosed)
+
+	// 요청을 더 받지 않고 DB도 닫힘
+	_, err = http.Get("http://" + srv.Addr() + "/api/v1/limits")
+	assert.Error(t, err)
+	assert.Error(t, sqlDB.Ping())
+}
+
+func TestServer_StopsWhenContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	srv, done := startTestServer(t, ctx)
+
+	cancel()
+	assert.NoError(t, waitStopped(t, done))
+	assert.NoError(t, srv.Shutdown(context.Background()), "컨텍스트로 멈춘 뒤 Shutdown도 안전")
+}
+
+func TestServer_ShutdownBeforeStart(t *testing.T) {
+	srv, err := NewServer(newTestConfig(t), WithLogger(newSilentLogger()))
+	require.NoError(t, err)
+	sqlDB, err := srv.Container().Database.DB.DB()
+	require.NoError(t, err)
+
+	// 시작하지 않은 서버는 구성요소만 정리
+	require.NoError(t, srv.Shutdown(context.Background()))
+	assert.ErrorIs(t, srv.Start(context.Background()), ErrServerClosed)
+	assert.Empty(t, srv.Addr())
+	assert.Error(t, sqlDB.Ping())
+}
+
+func TestServer_ListenFailure(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = taken.Close() })
+	_, port, err := net.SplitHostPort(taken.Addr().String())
+	require.NoError(t, err)
+
+	cfg := newTestConfig(t)
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = port
+	srv, err := NewServer(cfg, WithLogger(newSilentLogger()))
+	require.NoError(t, err)
+
+	// 이미 쓰는 포트면 시작 실패를 반환하고 자원을 정리
+	err = srv.Start(context.Background())
+	assert.ErrorContains(t, err, "서버 시작 실패")
+	assert.Empty(t, srv.Addr())
+	select {
+	case <-srv.Ready():
+		t.Fatal("시작에 실패한 서버가 준비 상태가 됨")
+	default:
+	}
+	assert.NoError(t, srv.Shutdown(context.Background()))
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec // 파일 모델의 체크섬 형식(MD5)과 일치
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/handler"
+	"DataLocker/internal/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+// responseKeysPassword 응답 검사용 파일의 암호화 패스워드
+const responseKeysPassword = "response-keys-password"
+
+// serveJSON 라우터에 요청을 보내고 응답을 반환합니다 (admin이면 관리 API 토큰 포함)
+func serveJSON(router http.Handler, method, path string, body io.Reader, admin bool, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Content-Type", "application/json")
+	if admin {
+		req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// negotiateBody content를 올리는 업로드 협상 요청 본문
+func negotiateBody(content []byte) string {
+	sum := md5.Sum(content) //nolint:gosec // 파일 모델의 체크섬 형식(MD5)과 일치
+	return fmt.Sprintf(`{"original_name":"report.txt","size":%d,"mime_type":"text/plain","checksum_md5":%q,`+
+		`"description":"분기 보고서","custom_metadata":{"client":{"name":"acme"}}}`, len(content), hex.EncodeToString(sum[:]))
+}
+
+// uploadThroughRouter 협상과 업로드를 거쳐 암호화 파일을 하나 만들고 업로드 응답을 반환합니다
+func uploadThroughRouter(t *testing.T, router http.Handler, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := serveJSON(router, http.MethodPost, "/api/v1/files/negotiate", strings.NewReader(negotiateBody(content)), false, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	testutil.AssertNoSensitiveKeys(t, rec.Body.Bytes())
+
+	var negotiated struct {
+		Data struct {
+			Session struct {
+				UploadURL string `json:"upload_url"`
+			} `json:"session"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &negotiated))
+	require.NotEmpty(t, negotiated.Data.Session.UploadURL)
+
+	rec = serveJSON(router, http.MethodPut, negotiated.Data.Session.UploadURL, bytes.NewReader(content), false,
+		map[string]string{handler.HeaderEncryptionPassword: responseKeysPassword})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	return rec
+}
+
+// TestRouter_ResponsesOmitSensitiveKeys 주요 엔드포인트 응답에 salt/nonce/패스워드 같은 키가 없는지 검사합니다
+//
+// 새 DTO나 엔드포인트를 추가하면 여기에 요청을 더해 회귀를 막습니다. 금지 키가
+// 필요한 내부 전용 응답은 요청마다 allow에 경로를 명시합니다.
+func TestRouter_ResponsesOmitSensitiveKeys(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.Security.DedupEnabled = true
+	cfg.Security.DedupProofRequired = true
+	c, err := New(cfg, WithLogger(newSilentLogger()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	router := c.Router()
+
+	var content strings.Builder
+	for i := range 200 {
+		fmt.Fprintf(&content, "quarterly report line %d: region %d revenue %d\n", i, i%7, i*37)
+	}
+	rec := uploadThroughRouter(t, router, []byte(content.String()))
+	testutil.AssertNoSensitiveKeys(t, rec.Body.Bytes())
+
+	var uploaded struct {
+		Data struct {
+			ID        uint   `json:"id"`
+			ShortCode string `json:"short_code"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &uploaded))
+	require.NotZero(t, uploaded.Data.ID)
+	require.NotEmpty(t, uploaded.Data.ShortCode)
+	fileURL := fmt.Sprintf("/api/v1/admin/files/%d", uploaded.Data.ID)
+
+	testCases := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		admin   bool
+		headers map[string]string
+		allow   []string
+	}{
+		{
+			// 소유 증명 챌린지는 클라이언트가 응답을 계산하도록 일회용 nonce를 의도적으로 내려줌
+			name: "소유 증명 챌린지", method: http.MethodPost, path: "/api/v1/files/negotiate", body: negotiateBody([]byte(content.String())),
+			allow: []string{"data.challenge.nonce"},
+		},
+		{name: "검색 목록", method: http.MethodGet, path: "/api/v1/search?q=report", admin: true},
+		{name: "짧은 코드 조회", method: http.MethodGet, path: "/api/v1/files/code/" + uploaded.Data.ShortCode},
+		{name: "유사 파일", method: http.MethodGet, path: fmt.Sprintf("/api/v1/files/%d/similar", uploaded.Data.ID), admin: true},
+		{name: "업로드 제한", method: http.MethodGet, path: "/api/v1/limits"},
+		{name: "업로드 정책", method: http.MethodGet, path: "/api/v1/upload-policy"},
+		{name: "열거형", method: http.MethodGet, path: "/api/v1/meta/enums"},
+		{name: "관리 목록", method: http.MethodGet, path: "/api/v1/admin/files", admin: true},
+		{name: "관리 커서 목록", method: http.MethodGet, path: "/api/v1/admin/files?cursor=", admin: true},
+		{name: "관리 메타데이터 검색", method: http.MethodGet, path: "/api/v1/admin/files?meta_key=client.name&meta_value=acme", admin: true},
+		{name: "관리 단건", method: http.MethodGet, path: fileURL, admin: true},
+		{name: "관리 수정", method: http.MethodPatch, path: fileURL, body: `{"description":"수정한 설명"}`, admin: true},
+		{
+			name: "무결성 검사", method: http.MethodPost, path: fileURL + "/verify", admin: true,
+			headers: map[string]string{handler.HeaderEncryptionPassword: responseKeysPassword},
+		},
+		{name: "볼륨", method: http.MethodGet, path: "/api/v1/admin/volumes", admin: true},
+		{name: "저장소 통계", method: http.MethodGet, path: "/api/v1/admin/stats/storage", admin: true},
+		{name: "활동 피드", method: http.MethodGet, path: "/api/v1/activity", admin: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveJSON(router, tc.method, tc.path, strings.NewReader(tc.body), tc.admin, tc.headers)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			testutil.AssertNoSensitiveKeys(t, rec.Body.Bytes(), tc.allow...)
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Package app assembles DataLocker server components in one place.
+// This file registers routes and the background tasks that run alongside the HTTP server.
+package app
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/middleware"
+	"DataLocker/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Router 미들웨어와 라우트를 등록한 Echo 인스턴스를 만듭니다
+//
+// 설정 리로드 시 교체 가능한 항목(레이트 리밋, 패스워드 시도 제한, 업로드 정책,
+// 로그 레벨, 요청 로그 샘플링)을 함께 연결합니다. 컨테이너당 한 번만 호출해야 합니다.
+func (c *Container) Router() *echo.Echo {
+	e := echo.New()
+
+	// 배너 숨기기
+	e.HideBanner = true
+
+	// 미들웨어 설정
+	rateLimitStore := middleware.SetupMiddleware(e, c.Config, c.Logger, c.AccessLog, c.SlowRequests)
+
+	// 에러 핸들러 설정
+	e.HTTPErrorHandler = middleware.ErrorHandlingMiddleware(c.Logger)
+
+	// 라우트 설정
+	c.registerRoutes(e)
+	c.registerAdminRoutes(e)
+
+	// 등록된 라우트만 응답 링크에 포함
+	c.Links.Bind(e)
+
+	// 설정 리로드 시 교체 가능한 항목 적용 (SIGHUP 또는 관리 API)
+	c.Reloadable.OnReload(func(next *config.Config) {
+		rateLimitStore.SetRate(next.Security.RateLimitPerMinute)
+		c.PasswordLimiter.SetLimit(next.Security.PasswordAttemptsPerMinute)
+		c.CodeLookupLimiter.SetLimit(next.Security.CodeLookupsPerMinute)
+		c.Services.Validation.UpdatePolicy(next.Upload)
+		c.Logger.SetLevel(parseLogLevel(next.App.LogLevel))
+		c.AccessLog.SetConfig(next.App.AccessLog)
+	})
+
+	return e
+}
+
+// runStartupTasks 요청을 받기 전에 한 번 실행하는 점검 작업
+//
+// 실패해도 서버는 시작하며, 같은 작업을 관리 API로 다시 실행할 수 있습니다.
+func (c *Container) runStartupTasks() {
+	// 메타데이터를 잃은 파일을 헤더로 복원하거나 손상으로 표시
+	if _, err := c.Services.Admin.CheckMetadata(context.Background()); err != nil {
+		c.Logger.WithError(err).Warn("암호화 메타데이터 점검에 실패했습니다")
+	}
+
+	// 재시작 전에 만료된 디렉터리 검증 결과 정리
+	if _, err := c.Services.ValidationSession.PruneExpired(context.Background()); err != nil {
+		c.Logger.WithError(err).Warn("만료된 검증 세션 정리에 실패했습니다")
+	}
+
+	// 업로드 중에 종료되어 남은 저장 경로 예약과 임시 파일 정리
+	if _, err := c.Services.Storage.PruneReservations(context.Background()); err != nil {
+		c.Logger.WithError(err).Warn("만료된 저장 경로 예약 정리에 실패했습니다")
+	}
+
+	// 보관 기간이 지난 Idempotency-Key 기록 정리
+	if _, err := c.Services.Idempotency.PruneExpired(context.Background()); err != nil {
+		c.Logger.WithError(err).Warn("만료된 Idempotency-Key 기록 정리에 실패했습니다")
+	}
+}
+
+// registerRoutes 공개 API 라우트를 설정합니다
+func (c *Container) registerRoutes(e *echo.Echo) {
+	h := c.Handlers
+
+	// API 버전 그룹
+	api := e.Group("/api/v1")
+
+	// 헬스체크 라우트
+	health := api.Group("/health")
+	health.GET("", h.Health.Health)
+	health.GET("/ready", h.Health.Ready)
+	health.GET("/live", h.Health.Live)
+	health.GET("/metrics", h.Health.Metrics)
+
+	// 업로드 정책 라우트
+	api.GET("/limits", h.Limits.GetLimits)
+	api.GET("/upload-policy", h.Limits.GetUploadPolicy)
+
+	// 클라이언트용 enum 목록 라우트
+	api.GET("/meta/enums", h.Meta.Enums)
+
+	// 디렉터리 검증 라우트 (개별 결과는 세션 ID로 페이지/NDJSON 조회)
+	validations := api.Group("/validations")
+	validations.POST("", h.Validation.ValidateDirectory)
+	validations.GET("/:id/results", h.Validation.Results)
+
+	// 업로드 협상 라우트 (Idempotency-Key가 있으면 재시도에 저장된 응답 반환)
+	// 업로드 본문은 스트리밍하므로 해시하지 않고, 세션 ID가 협상한 내용을 고정함
+	files := api.Group("/files")
+	files.POST("/negotiate", h.Negotiate.Negotiate, middleware.IdempotencyMiddleware(c.Services.Idempotency, c.Logger, true))
+	files.POST("/negotiate/verify", h.Negotiate.VerifyProof, middleware.IdempotencyMiddleware(c.Services.Idempotency, c.Logger, true))
+	files.PUT("/upload/:session_id", h.Upload.Upload, middleware.IdempotencyMiddleware(c.Services.Idempotency, c.Logger, false))
+
+	// 텍스트 미리보기 라우트
+	files.GET("/:id/preview", h.Preview.Preview, middleware.PasswordAttemptMiddleware(c.PasswordLimiter))
+
+	// 다른 패스워드로 재암호화 라우트 (원본 패스워드 시도는 미리보기와 같은 제한)
+	files.POST("/:id/transcode", h.Transcode.Transcode, middleware.PasswordAttemptMiddleware(c.PasswordLimiter))
+
+	// 짧은 파일 코드 조회 라우트 (코드 추측을 막기 위해 IP별 조회 수 제한)
+	files.GET("/code/:code", h.FileCode.Lookup, middleware.CodeLookupMiddleware(c.CodeLookupLimiter))
+
+	// 루트 경로
+	e.GET("/", func(ec echo.Context) error {
+		return ec.JSON(http.StatusOK, map[string]interface{}{
+			"message": "DataLocker API Server",
+			"version": "2.0.0",
+			"status":  "running",
+			"docs":    "/api/v1/health",
+		})
+	})
+
+	// API 문서 경로 (추후 Swagger 연동)
+	e.GET("/docs", func(ec echo.Context) error {
+		return ec.JSON(http.StatusOK, map[string]interface{}{
+			"message": "API Documentation",
+			"endpoints": map[string]interface{}{
+				"health":        "/api/v1/health",
+				"ready":         "/api/v1/health/ready",
+				"live":          "/api/v1/health/live",
+				"metrics":       "/api/v1/health/metrics",
+				"search":        "/api/v1/search",
+				"limits":        "/api/v1/limits",
+				"upload_policy": "/api/v1/upload-policy",
+				"enums":         "/api/v1/meta/enums",
+				"validate":      "/api/v1/validations",
+				"negotiate":     "/api/v1/files/negotiate",
+				"upload":        "/api/v1/files/upload/:session_id",
+				"preview":       "/api/v1/files/:id/preview",
+				"transcode":     "/api/v1/files/:id/transcode",
+				"file_code":     "/api/v1/files/code/:code",
+				"admin":         "/api/v1/admin/files",
+				"reload":        "/api/v1/admin/config/reload",
+				"stats_history": "/api/v1/stats/history",
+				"activity":      "/api/v1/activity",
+			},
+		})
+	})
+}
+
+// registerAdminRoutes 관리 API 라우트를 설정합니다 (토큰이 설정된 경우에만)
+func (c *Container) registerAdminRoutes(e *echo.Echo) {
+	if c.Config.Security.AdminAPIToken == "" {
+		c.Logger.Info("ADMIN_API_TOKEN이 설정되지 않아 관리 API를 비활성화합니다")
+		return
+	}
+
+	h := c.Handlers
+	admin := e.Group("/api/v1/admin", middleware.AdminAuthMiddleware(c.Config.Security.AdminAPIToken))
+	admin.GET("/files", h.Admin.ListFiles)
+	admin.GET("/files/:id", h.Admin.GetFile)
+	admin.PATCH("/files/:id", h.Admin.UpdateFile)
+	admin.POST("/files/:id/verify", h.Admin.VerifyFile)
+	admin.POST("/files/check-metadata", h.Admin.CheckMetadata)
+	admin.POST("/files/reconcile-sizes", h.Consistency.ReconcileSizes)
+	admin.POST("/files/:id/trash", h.Admin.DeleteFile)
+	admin.POST("/files/:id/restore", h.Admin.RestoreFile)
+	admin.POST("/files/:id/code", h.FileCode.Reissue)
+	admin.DELETE("/files/:id", h.Admin.PurgeFile)
+	admin.GET("/volumes", h.Admin.VolumeStats)
+	admin.GET("/stats/storage", h.Stats.Storage)
+	admin.POST("/stats/backfill", h.Stats.Backfill)
+	admin.POST("/volumes/rebalance", h.Admin.RebalanceVolumes)
+	admin.POST("/config/reload", h.Config.Reload)
+
+	// 공장 초기화 (운영환경에서는 ALLOW_FACTORY_RESET=true일 때만 등록하고 그 외에는 404)
+	if h.FactoryReset != nil {
+		if c.Config.App.Environment != "production" || c.Config.Security.AllowFactoryReset {
+			admin.POST("/factory-reset", h.FactoryReset.FactoryReset)
+		} else {
+			c.Logger.Info("운영환경에서 ALLOW_FACTORY_RESET이 꺼져 있어 공장 초기화 API를 비활성화합니다")
+		}
+	}
+
+	// 관리자 대시보드용 일별 통계 (관리 API와 같은 토큰으로 보호)
+	stats := e.Group("/api/v1/stats", middleware.AdminAuthMiddleware(c.Config.Security.AdminAPIToken))
+	stats.GET("/history", h.Stats.History)
+
+	// 대시보드 요약 카드용 최근 파일 활동 (파일 이름과 행위자를 담으므로 같은 토큰으로 보호)
+	e.GET("/api/v1/activity", h.Activity.Recent, middleware.AdminAuthMiddleware(c.Config.Security.AdminAPIToken))
+
+	// 통합 검색과 유사 중복 조회 (파일 레코드 전체를 반환하므로 같은 토큰으로 보호)
+	e.GET("/api/v1/search", h.Search.Search, middleware.AdminAuthMiddleware(c.Config.Security.AdminAPIToken))
+	e.GET("/api/v1/files/:id/similar", h.Search.Similar, middleware.AdminAuthMiddleware(c.Config.Security.AdminAPIToken))
+}
+
+// startBackgroundTasks 서버와 함께 도는 백그라운드 작업을 시작하고, 역순으로 멈추는 정리 함수를 반환합니다
+func (c *Container) startBackgroundTasks() func() {
+	// 샘플링으로 생략한 요청 로그 수 요약 (종료 시 남은 수까지 기록)
+	c.AccessLog.Start()
+
+	// 수집함 감시 시작 (설정된 경우)
+	stopWatch := c.startWatchService()
+
+	// 매일 자정(UTC) 일별 통계 스냅샷 저장 (설정된 경우)
+	stopMetrics := c.startMetricsScheduler()
+
+	return func() {
+		stopMetrics()
+		stopWatch()
+		c.AccessLog.Stop()
+	}
+}
+
+// startWatchService 수집함 자동 암호화 감시를 시작하고 정리 함수를 반환합니다
+func (c *Container) startWatchService() func() {
+	cfg := c.Config
+	if len(cfg.Watch.Dirs) == 0 {
+		return func() {}
+	}
+
+	watchService, err := service.NewWatchService(
+		cfg.Watch, cfg.Security.PBKDF2Iterations, c.Services.Storage, c.Repos.Files, c.Repos.Tx, c.Services.Validation, c.Logger)
+	if err == nil {
+		err = watchService.Start(context.Background())
+	}
+	if err != nil {
+		c.Logger.WithError(err).Error("수집함 감시를 시작하지 못했습니다")
+		return func() {}
+	}
+
+	return func() {
+		if err := watchService.Stop(); err != nil {
+			c.Logger.WithError(err).Error("수집함 감시 종료 중 오류가 발생했습니다")
+		}
+	}
+}
+
+// startMetricsScheduler 일별 통계 스냅샷 스케줄러를 시작하고 정리 함수를 반환합니다
+func (c *Container) startMetricsScheduler() func() {
+	if !c.Config.Metrics.SnapshotEnabled {
+		return func() {}
+	}
+
+	scheduler := service.NewMetricsScheduler(c.Services.Metrics, c.Logger)
+	scheduler.Start()
+	return scheduler.Stop
+}
+
+// handleReloadSignal SIGHUP을 받으면 설정을 리로드하고, 정리 함수를 반환합니다
+//
+// 결과와 실패는 ReloadableConfig가 감사 로그로 남깁니다.
+func handleReloadSignal(reloadable *config.ReloadableConfig) func() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-hup:
+				_, _ = reloadable.Reload(config.ReloadSourceSignal)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(hup)
+		close(done)
+	}
+}
@@ -0,0 +1,331 @@
+// Package bench measures encryption throughput on the current host and
+// recommends chunk size, worker count and PBKDF2 iterations for it.
+// Measurements encrypt synthetic in-memory data and never touch storage or the database.
+package bench
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/pkg/concurrent"
+	"DataLocker/pkg/crypto"
+)
+
+// 측정 기본값
+const (
+	// DefaultMaxDuration 전체 측정 시간 상한
+	DefaultMaxDuration = 60 * time.Second
+
+	// DefaultSampleSize 조합 하나에서 워커마다 암호화하는 데이터 크기 (32MB)
+	DefaultSampleSize = 32 * 1024 * 1024
+
+	// DefaultPBKDF2Target 반복 횟수 권장값을 구할 때 키 유도 한 번의 목표 시간
+	DefaultPBKDF2Target = config.DefaultPBKDF2TargetMillis * time.Millisecond
+
+	// recommendTolerance 최고 처리량 대비 이 비율 이상이면 더 가벼운 조합을 권장
+	recommendTolerance = 0.95
+
+	// syntheticBlockSize 합성 데이터로 반복해서 쓰는 랜덤 블록 크기 (1MB)
+	syntheticBlockSize = 1024 * 1024
+
+	// benchPassword 측정용 스트림의 패스워드 (결과는 버려짐)
+	benchPassword = "datalocker-bench" //nolint:gosec // 측정용 더미 값
+
+	// bytesPerMB 처리량 단위 변환
+	bytesPerMB = 1024 * 1024
+)
+
+// 측정 중단 사유
+const (
+	StopTimeout     = "timeout"
+	StopInterrupted = "interrupted"
+)
+
+// DefaultChunkSizes 기본으로 측정하는 청크 크기 (64KB, 256KB, 1MB)
+var DefaultChunkSizes = []int{64 * 1024, 256 * 1024, crypto.ChunkSize}
+
+// ErrInvalidOptions 측정 옵션이 잘못된 경우
+var ErrInvalidOptions = errors.New("잘못된 벤치마크 옵션입니다")
+
+// Options 측정할 조합과 제한
+type Options struct {
+	ChunkSizes   []int         // 측정할 청크 크기 (비어 있으면 DefaultChunkSizes)
+	Workers      []int         // 측정할 워커 수 (비어 있으면 DefaultWorkers)
+	SampleSize   int64         // 조합 하나에서 워커마다 암호화하는 크기 (0이면 DefaultSampleSize)
+	MaxDuration  time.Duration // 전체 측정 시간 상한 (0이면 DefaultMaxDuration)
+	PBKDF2Target time.Duration // 반복 횟수 보정 목표 시간 (0이면 DefaultPBKDF2Target)
+}
+
+// HostInfo 측정한 호스트 정보
+type HostInfo struct {
+	GOOS       string `json:"goos"`
+	GOARCH     string `json:"goarch"`
+	CPUModel   string `json:"cpu_model,omitempty"`
+	NumCPU     int    `json:"num_cpu"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+	AESNI      bool   `json:"aes_ni"` // 하드웨어 AES-GCM 가속 (x86 AES-NI, ARM64 AES 확장)
+	GoVersion  string `json:"go_version"`
+}
+
+// Trial 조합 하나의 측정 결과
+type Trial struct {
+	ChunkSize      int     `json:"chunk_size"`
+	Workers        int     `json:"workers"`
+	Bytes          int64   `json:"bytes"`
+	ElapsedMillis  int64   `json:"elapsed_ms"`
+	ThroughputMBps float64 `json:"throughput_mb_per_sec"`
+}
+
+// Recommendation 측정 결과로 정한 권장 설정
+type Recommendation struct {
+	ChunkSize        int     `json:"chunk_size"`
+	Workers          int     `json:"workers"`           // WATCH_WORKERS
+	PBKDF2Iterations int     `json:"pbkdf2_iterations"` // PBKDF2_ITERATIONS
+	ThroughputMBps   float64 `json:"throughput_mb_per_sec"`
+}
+
+// Report 벤치마크 리포트
+type Report struct {
+	Host          HostInfo        `json:"host"`
+	StartedAt     time.Time       `json:"started_at"`
+	ElapsedMillis int64           `json:"elapsed_ms"`
+	SampleSize    int64           `json:"sample_size"`
+	Trials        []Trial         `json:"trials"`
+	Recommended   *Recommendation `json:"recommended,omitempty"` // 완료된 조합이 없으면 nil
+	Incomplete    bool            `json:"incomplete"`
+	StopReason    string          `json:"stop_reason,omitempty"` // timeout 또는 interrupted
+}
+
+// DefaultWorkers 기본으로 측정하는 워커 수 (1, 2, 4, ... GOMAXPROCS)
+func DefaultWorkers() []int {
+	limit := runtime.GOMAXPROCS(0)
+	workers := []int{}
+	for n := 1; n < limit; n *= 2 {
+		workers = append(workers, n)
+	}
+	return append(workers, limit)
+}
+
+// Measure 청크 크기와 워커 수 조합마다 합성 데이터 암호화 처리량을 측정합니다
+//
+// 조합은 순서대로 하나씩 측정하며, 워커마다 SampleSize만큼 암호화해 버립니다.
+// 시간 상한에 걸리거나 ctx가 취소되면 진행 중인 조합은 버리고, 완료된 조합만으로
+// Incomplete 리포트를 반환합니다. 에러는 옵션이 잘못되었거나 암호화 자체가
+// 실패한 경우에만 반환합니다.
+func Measure(ctx context.Context, opts Options) (*Report, error) {
+	opts, err := normalize(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Host:       hostInfo(),
+		StartedAt:  time.Now(),
+		SampleSize: opts.SampleSize,
+		Trials:     []Trial{},
+	}
+
+	iterations, err := crypto.CalibrateIterations(opts.PBKDF2Target)
+	if err != nil {
+		return nil, fmt.Errorf("반복 횟수 보정 실패: %w", err)
+	}
+
+	block := make([]byte, syntheticBlockSize)
+	if _, err := rand.Read(block); err != nil {
+		return nil, fmt.Errorf("합성 데이터 생성 실패: %w", err)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, opts.MaxDuration)
+	defer cancel()
+
+measure:
+	for _, chunkSize := range opts.ChunkSizes {
+		for _, workers := range opts.Workers {
+			trial, err := runTrial(runCtx, block, chunkSize, workers, opts.SampleSize)
+			if err != nil {
+				if runCtx.Err() == nil {
+					return nil, fmt.Errorf("청크 %d, 워커 %d 측정 실패: %w", chunkSize, workers, err)
+				}
+				report.Incomplete = true
+				report.StopReason = stopReason(ctx)
+				break measure
+			}
+			report.Trials = append(report.Trials, *trial)
+		}
+	}
+
+	report.ElapsedMillis = time.Since(report.StartedAt).Milliseconds()
+	report.Recommended = recommend(report.Trials, iterations)
+	return report, nil
+}
+
+// normalize 비어 있는 옵션에 기본값을 채우고 값을 검증합니다
+func normalize(opts Options) (Options, error) {
+	if len(opts.ChunkSizes) == 0 {
+		opts.ChunkSizes = DefaultChunkSizes
+	}
+	if len(opts.Workers) == 0 {
+		opts.Workers = DefaultWorkers()
+	}
+	if opts.SampleSize == 0 {
+		opts.SampleSize = DefaultSampleSize
+	}
+	if opts.MaxDuration == 0 {
+		opts.MaxDuration = DefaultMaxDuration
+	}
+	if opts.PBKDF2Target == 0 {
+		opts.PBKDF2Target = DefaultPBKDF2Target
+	}
+
+	for _, size := range opts.ChunkSizes {
+		if size < crypto.MinChunkSize || size > crypto.ChunkSize {
+			return opts, fmt.Errorf("%w: 청크 크기 %d (허용: %d ~ %d)", ErrInvalidOptions, size, crypto.MinChunkSize, crypto.ChunkSize)
+		}
+	}
+	for _, n := range opts.Workers {
+		if n < 1 {
+			return opts, fmt.Errorf("%w: 워커 수 %d", ErrInvalidOptions, n)
+		}
+	}
+	if opts.SampleSize < 0 {
+		return opts, fmt.Errorf("%w: 샘플 크기 %d", ErrInvalidOptions, opts.SampleSize)
+	}
+	if opts.MaxDuration < 0 || opts.PBKDF2Target < 0 {
+		return opts, fmt.Errorf("%w: 시간은 0보다 커야 합니다", ErrInvalidOptions)
+	}
+
+	// 같은 조합을 두 번 측정하지 않도록 정렬 후 중복 제거
+	opts.ChunkSizes = slices.Compact(slices.Sorted(slices.Values(opts.ChunkSizes)))
+	opts.Workers = slices.Compact(slices.Sorted(slices.Values(opts.Workers)))
+	return opts, nil
+}
+
+// runTrial 워커 수만큼 동시에 스트림을 암호화해 처리량을 측정합니다
+func runTrial(ctx context.Context, block []byte, chunkSize, workers int, sampleSize int64) (*Trial, error) {
+	start := time.Now()
+
+	g, _ := concurrent.NewGroup(ctx, concurrent.FailFast, 0)
+	for i := 0; i < workers; i++ {
+		g.Go(func(ctx context.Context) error {
+			return encryptSample(ctx, block, chunkSize, sampleSize)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	// 이미 취소된 그룹은 작업을 건너뛰고 nil을 반환하므로 직접 확인
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	elapsed := time.Since(start)
+	total := sampleSize * int64(workers)
+	return &Trial{
+		ChunkSize:      chunkSize,
+		Workers:        workers,
+		Bytes:          total,
+		ElapsedMillis:  elapsed.Milliseconds(),
+		ThroughputMBps: throughput(total, elapsed),
+	}, nil
+}
+
+// encryptSample 합성 데이터를 스트림 포맷으로 암호화해 버립니다
+//
+// 키 유도 비용이 처리량에 섞이지 않도록 반복 횟수는 최소값을 사용합니다.
+func encryptSample(ctx context.Context, block []byte, chunkSize int, size int64) error {
+	encWriter, err := crypto.NewEncryptWriter(io.Discard, benchPassword,
+		crypto.WithChunkSize(chunkSize), crypto.WithIterations(crypto.MinIterations))
+	if err != nil {
+		return err
+	}
+
+	src := &syntheticReader{ctx: ctx, block: block, remaining: size}
+	if _, err := io.CopyBuffer(encWriter, src, make([]byte, chunkSize)); err != nil {
+		_ = encWriter.Close()
+		return err
+	}
+	return encWriter.Close()
+}
+
+// syntheticReader 랜덤 블록을 반복해 size 바이트를 제공하고 취소를 확인하는 Reader
+type syntheticReader struct {
+	ctx       context.Context
+	block     []byte
+	offset    int
+	remaining int64
+}
+
+// Read 블록을 이어서 채웁니다 (ctx가 취소되면 ctx 에러)
+func (r *syntheticReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n := copy(p, r.block[r.offset:])
+	r.offset = (r.offset + n) % len(r.block)
+	r.remaining -= int64(n)
+	return n, nil
+}
+
+// recommend 처리량이 최고값에 가까운 조합 중 워커 수와 청크 크기가 가장 작은 것을 고릅니다
+//
+// 워커와 청크가 작을수록 메모리 사용량이 적고 다른 요청과 CPU를 덜 다투므로,
+// 최고 처리량의 recommendTolerance 이상이면 가벼운 조합을 우선합니다.
+func recommend(trials []Trial, iterations int) *Recommendation {
+	if len(trials) == 0 {
+		return nil
+	}
+
+	best := 0.0
+	for _, trial := range trials {
+		best = max(best, trial.ThroughputMBps)
+	}
+
+	var chosen *Trial
+	for i := range trials {
+		trial := &trials[i]
+		if trial.ThroughputMBps < best*recommendTolerance {
+			continue
+		}
+		if chosen == nil || trial.Workers < chosen.Workers ||
+			(trial.Workers == chosen.Workers && trial.ChunkSize < chosen.ChunkSize) {
+			chosen = trial
+		}
+	}
+
+	return &Recommendation{
+		ChunkSize:        chosen.ChunkSize,
+		Workers:          chosen.Workers,
+		PBKDF2Iterations: iterations,
+		ThroughputMBps:   chosen.ThroughputMBps,
+	}
+}
+
+// stopReason 측정이 멈춘 원인을 반환합니다 (호출자 취소가 아니면 시간 상한)
+func stopReason(parent context.Context) string {
+	if parent.Err() != nil {
+		return StopInterrupted
+	}
+	return StopTimeout
+}
+
+// throughput 초당 처리량(MB/s)을 계산합니다
+func throughput(bytes int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(bytes) / bytesPerMB / elapsed.Seconds()
+}
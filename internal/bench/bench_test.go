@@ -0,0 +1,147 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smallOptions 테스트용으로 빠르게 끝나는 측정 옵션
+func smallOptions() Options {
+	return Options{
+		ChunkSizes:   []int{4096, 1024, 4096},
+		Workers:      []int{2, 1},
+		SampleSize:   256 * 1024,
+		MaxDuration:  time.Minute,
+		PBKDF2Target: time.Millisecond,
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	report, err := Measure(context.Background(), smallOptions())
+	require.NoError(t, err)
+
+	// 중복을 제거하고 정렬한 조합 순서대로 측정
+	require.Len(t, report.Trials, 4)
+	assert.Equal(t, 1024, report.Trials[0].ChunkSize)
+	assert.Equal(t, 1, report.Trials[0].Workers)
+	assert.Equal(t, 2, report.Trials[1].Workers)
+	assert.Equal(t, int64(2*256*1024), report.Trials[1].Bytes)
+
+	assert.False(t, report.Incomplete)
+	assert.Empty(t, report.StopReason)
+	assert.Positive(t, report.Host.NumCPU)
+	assert.NotEmpty(t, report.Host.GoVersion)
+
+	require.NotNil(t, report.Recommended)
+	assert.GreaterOrEqual(t, report.Recommended.PBKDF2Iterations, 1000)
+	assert.Contains(t, []int{1024, 4096}, report.Recommended.ChunkSize)
+}
+
+func TestMeasure_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := Measure(ctx, smallOptions())
+	require.NoError(t, err)
+
+	assert.True(t, report.Incomplete)
+	assert.Equal(t, StopInterrupted, report.StopReason)
+	assert.Empty(t, report.Trials)
+	assert.Nil(t, report.Recommended)
+}
+
+func TestMeasure_Timeout(t *testing.T) {
+	opts := smallOptions()
+	opts.SampleSize = 1 << 30
+	opts.MaxDuration = 50 * time.Millisecond
+
+	report, err := Measure(context.Background(), opts)
+	require.NoError(t, err)
+
+	assert.True(t, report.Incomplete)
+	assert.Equal(t, StopTimeout, report.StopReason)
+}
+
+func TestMeasure_InvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts Options
+	}{
+		{name: "청크 크기 초과", opts: Options{ChunkSizes: []int{2 * 1024 * 1024}}},
+		{name: "워커 수 0", opts: Options{Workers: []int{0}}},
+		{name: "음수 샘플 크기", opts: Options{SampleSize: -1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Measure(context.Background(), tc.opts)
+			assert.ErrorIs(t, err, ErrInvalidOptions)
+		})
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	trials := []Trial{
+		{ChunkSize: 65536, Workers: 1, ThroughputMBps: 400},
+		{ChunkSize: 65536, Workers: 4, ThroughputMBps: 1000},
+		{ChunkSize: 1048576, Workers: 2, ThroughputMBps: 960},
+		{ChunkSize: 262144, Workers: 2, ThroughputMBps: 970},
+	}
+
+	// 최고값의 95% 이상인 조합 중 워커와 청크가 가장 작은 조합
+	rec := recommend(trials, 120000)
+	require.NotNil(t, rec)
+	assert.Equal(t, 2, rec.Workers)
+	assert.Equal(t, 262144, rec.ChunkSize)
+	assert.Equal(t, 120000, rec.PBKDF2Iterations)
+
+	assert.Nil(t, recommend(nil, 120000))
+}
+
+func TestRun(t *testing.T) {
+	var out, errOut bytes.Buffer
+	code := Run(context.Background(), []string{
+		"--chunk-kb", "1,4", "--workers", "1", "--sample-mb", "1", "--pbkdf2-target", "1ms",
+	}, Streams{Out: &out, Err: &errOut})
+	require.Equal(t, ExitOK, code, errOut.String())
+
+	var report Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Len(t, report.Trials, 2)
+	assert.NotNil(t, report.Recommended)
+}
+
+func TestRun_Usage(t *testing.T) {
+	testCases := [][]string{
+		{"--workers", "a,b"},
+		{"--chunk-kb", "2048"},
+		{"--sample-mb", "0"},
+		{"extra"},
+	}
+
+	for _, args := range testCases {
+		var out, errOut bytes.Buffer
+		assert.Equal(t, ExitUsage, Run(context.Background(), args, Streams{Out: &out, Err: &errOut}), args)
+		assert.Empty(t, out.String())
+	}
+}
+
+func TestRun_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out, errOut bytes.Buffer
+	code := Run(ctx, []string{"--chunk-kb", "1", "--workers", "1", "--pbkdf2-target", "1ms"}, Streams{Out: &out, Err: &errOut})
+	assert.Equal(t, ExitFailure, code)
+
+	// 중단되어도 부분 리포트는 출력
+	var report Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.True(t, report.Incomplete)
+}
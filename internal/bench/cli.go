@@ -0,0 +1,161 @@
+// Package bench measures encryption throughput on the current host and
+// recommends chunk size, worker count and PBKDF2 iterations for it.
+// This file implements the `datalocker bench` subcommand.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 종료 코드 (datalocker remote와 같은 규약)
+const (
+	ExitOK      = 0 // 성공 (시간 상한으로 일부만 측정한 경우 포함)
+	ExitFailure = 1 // 측정 실패 또는 사용자가 중단함
+	ExitUsage   = 2 // 잘못된 인자
+)
+
+// bytesPerKB 청크 크기 플래그 단위 변환
+const bytesPerKB = 1024
+
+// Streams 명령 입출력
+type Streams struct {
+	Out io.Writer
+	Err io.Writer
+}
+
+// Run `datalocker bench` 이후의 인자를 실행하고 종료 코드를 반환합니다
+//
+//	datalocker bench [--duration 60s] [--sample-mb 32] [--chunk-kb 64,256,1024]
+//	                 [--workers 1,2,4] [--pbkdf2-target 250ms] [--output report.json]
+//
+// 리포트는 JSON으로 출력하며, 중단(Ctrl+C)되면 그때까지의 결과를 출력하고
+// ExitFailure로 종료합니다.
+func Run(ctx context.Context, args []string, streams Streams) int {
+	opts, output, err := parseFlags(args, streams.Err)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		fmt.Fprintf(streams.Err, "오류: %s\n", err)
+		return ExitUsage
+	}
+
+	fmt.Fprintln(streams.Err, "합성 데이터로 암호화 처리량을 측정합니다 (저장소와 DB는 사용하지 않음)...")
+
+	report, err := Measure(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(streams.Err, "오류: %s\n", err)
+		if errors.Is(err, ErrInvalidOptions) {
+			return ExitUsage
+		}
+		return ExitFailure
+	}
+
+	if err := writeReport(report, output, streams.Out); err != nil {
+		fmt.Fprintf(streams.Err, "오류: %s\n", err)
+		return ExitFailure
+	}
+
+	switch report.StopReason {
+	case StopInterrupted:
+		fmt.Fprintf(streams.Err, "측정이 중단되었습니다 (완료된 조합 %d개)\n", len(report.Trials))
+		return ExitFailure
+	case StopTimeout:
+		fmt.Fprintf(streams.Err, "시간 상한에 도달해 일부 조합만 측정했습니다 (완료된 조합 %d개)\n", len(report.Trials))
+	}
+	return ExitOK
+}
+
+// parseFlags 플래그를 측정 옵션으로 변환합니다
+func parseFlags(args []string, errOut io.Writer) (Options, string, error) {
+	var (
+		opts       Options
+		sampleMB   int64
+		chunkKB    string
+		workers    string
+		outputPath string
+	)
+
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	fs.DurationVar(&opts.MaxDuration, "duration", DefaultMaxDuration, "전체 측정 시간 상한")
+	fs.Int64Var(&sampleMB, "sample-mb", DefaultSampleSize/bytesPerMB, "조합마다 워커 하나가 암호화할 크기 (MB)")
+	fs.StringVar(&chunkKB, "chunk-kb", "", "측정할 청크 크기 목록 (KB, 쉼표 구분, 기본값: 64,256,1024)")
+	fs.StringVar(&workers, "workers", "", "측정할 워커 수 목록 (쉼표 구분, 기본값: 1,2,4,...,GOMAXPROCS)")
+	fs.DurationVar(&opts.PBKDF2Target, "pbkdf2-target", DefaultPBKDF2Target, "권장 반복 횟수의 키 유도 목표 시간")
+	fs.StringVar(&outputPath, "output", "", "리포트를 저장할 파일 (기본값: 표준 출력)")
+	if err := fs.Parse(args); err != nil {
+		return opts, "", err
+	}
+
+	if fs.NArg() > 0 {
+		return opts, "", fmt.Errorf("알 수 없는 인자입니다: %s", strings.Join(fs.Args(), " "))
+	}
+
+	if opts.MaxDuration <= 0 || opts.PBKDF2Target <= 0 {
+		return opts, "", errors.New("--duration과 --pbkdf2-target은 0보다 커야 합니다")
+	}
+
+	if sampleMB <= 0 {
+		return opts, "", fmt.Errorf("--sample-mb는 양의 정수여야 합니다: %d", sampleMB)
+	}
+	opts.SampleSize = sampleMB * bytesPerMB
+
+	sizes, err := parseIntList(chunkKB, "--chunk-kb")
+	if err != nil {
+		return opts, "", err
+	}
+	for _, kb := range sizes {
+		opts.ChunkSizes = append(opts.ChunkSizes, kb*bytesPerKB)
+	}
+
+	if opts.Workers, err = parseIntList(workers, "--workers"); err != nil {
+		return opts, "", err
+	}
+
+	return opts, outputPath, nil
+}
+
+// parseIntList 쉼표로 구분한 양의 정수 목록을 해석합니다 (빈 값은 nil)
+func parseIntList(value, name string) ([]int, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var list []int
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%s 값은 양의 정수 목록이어야 합니다: %s", name, value)
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+// writeReport 리포트를 들여쓴 JSON으로 파일 또는 out에 기록합니다
+func writeReport(report *Report, path string, out io.Writer) error {
+	if path != "" {
+		file, err := os.Create(path) //nolint:gosec // 운영자가 지정한 출력 경로
+		if err != nil {
+			return fmt.Errorf("리포트 파일 생성 실패: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("리포트 출력 실패: %w", err)
+	}
+	return nil
+}
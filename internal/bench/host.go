@@ -0,0 +1,62 @@
+// Package bench measures encryption throughput on the current host and
+// recommends chunk size, worker count and PBKDF2 iterations for it.
+// This file collects the host details included in the report.
+package bench
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strings"
+
+	"golang.org/x/sys/cpu"
+)
+
+// cpuInfoPath 리눅스 CPU 정보 파일
+const cpuInfoPath = "/proc/cpuinfo"
+
+// hostInfo 현재 호스트 정보를 수집합니다
+func hostInfo() HostInfo {
+	return HostInfo{
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		CPUModel:   cpuModel(),
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		AESNI:      hasAESHardware(),
+		GoVersion:  runtime.Version(),
+	}
+}
+
+// hasAESHardware Go 표준 AES-GCM이 하드웨어 가속 경로를 쓰는지 확인합니다
+//
+// crypto/aes는 x86에서 AES-NI와 PCLMULQDQ, ARM64에서 AES와 PMULL이 모두
+// 있어야 GCM 가속 구현을 사용합니다.
+func hasAESHardware() bool {
+	switch runtime.GOARCH {
+	case "amd64", "386":
+		return cpu.X86.HasAES && cpu.X86.HasPCLMULQDQ
+	case "arm64":
+		return cpu.ARM64.HasAES && cpu.ARM64.HasPMULL
+	default:
+		return false
+	}
+}
+
+// cpuModel CPU 모델명을 반환합니다 (리눅스 외에는 빈 값)
+func cpuModel() string {
+	file, err := os.Open(cpuInfoPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.TrimSpace(key) == "model name" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -3,8 +3,12 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // 서버 설정 관련 상수
@@ -12,6 +16,9 @@ const (
 	// 기본 타임아웃 설정 (초)
 	DefaultReadTimeoutSeconds  = 30
 	DefaultWriteTimeoutSeconds = 30
+
+	// 헬스체크에서 의존 서비스 하나를 기다리는 기본 시간 (밀리초)
+	DefaultHealthCheckTimeoutMillis = 2000
 )
 
 // 파일 크기 관련 상수
@@ -24,12 +31,143 @@ const (
 	DefaultMaxFileSizeBytes = 1 * BytesPerGB
 )
 
+// 패스워드 재사용 경고 관련 상수
+const (
+	// 같은 지문이 이 개수 이상 존재하면 경고
+	DefaultPasswordReuseThreshold = 1
+)
+
+// 유사 중복 탐지 관련 상수
+const (
+	// 유사 파일로 판정하는 기본 최대 해밍 거리 (64비트 SimHash 기준)
+	DefaultSimilarityThreshold = 3
+)
+
+// 업로드 정책 관련 상수
+const (
+	// 어느 MIME 그룹에도 속하지 않는 파일의 기본 최대 크기 (100MB)
+	DefaultUploadMaxSize = 100 * BytesPerMB
+
+	// 기본 이미지 그룹 최대 크기 (20MB)
+	DefaultImageMaxSize = 20 * BytesPerMB
+
+	// 기본 문서 그룹 최대 크기 (100MB)
+	DefaultDocumentMaxSize = 100 * BytesPerMB
+)
+
+// 요청 제한 관련 상수
+const (
+	// 기본 레이트 리밋 (클라이언트당 분당 요청 수)
+	DefaultRateLimitPerMinute = 100
+
+	// 파일+IP 조합당 분당 복호화 패스워드 시도 수
+	DefaultPasswordAttemptsPerMinute = 10
+
+	// IP당 분당 짧은 파일 코드 조회 수 (코드 추측 방지)
+	DefaultCodeLookupsPerMinute = 30
+
+	// 패스워드 시도를 추적하는 파일+IP 조합 수 상한 (넘으면 가장 오래 쓰지 않은 조합부터 제거)
+	DefaultPasswordAttemptMaxKeys = 10000
+
+	// 동시에 진행할 수 있는 트랜스코드 수 (하나당 청크 약 3개를 메모리에 상주)
+	DefaultMaxConcurrentTranscodes = 4
+)
+
+// 요청 로그 샘플링 관련 상수
+const (
+	// 상태 코드 그룹별 기본 샘플링 비율 (N건 중 1건 기록, 1이면 전부 기록)
+	DefaultAccessLogSuccessSampleRate     = 100 // 1xx~3xx
+	DefaultAccessLogClientErrorSampleRate = 10  // 4xx
+	DefaultAccessLogServerErrorSampleRate = 1   // 5xx
+
+	// 샘플링으로 생략한 로그 수를 요약해 남기는 기본 주기 (초)
+	DefaultAccessLogSummarySeconds = 60
+)
+
+// 느린 요청 프로파일 관련 상수
+const (
+	// 프로파일을 남기는 기본 처리 시간 임계값 (밀리초)
+	DefaultSlowRequestThresholdMillis = 3000
+
+	// 프로파일 저장 디렉터리 기본 용량 상한 (넘으면 오래된 파일부터 삭제)
+	DefaultSlowRequestMaxDirBytes = 64 * BytesPerMB
+)
+
+// 일별 통계 스냅샷 관련 상수
+const (
+	// 스냅샷 기본 보존 기간 (일)
+	DefaultMetricsRetentionDays = 365
+
+	// 추이 조회와 backfill의 기본 기간 (일)
+	DefaultMetricsHistoryDays = 30
+)
+
+// 저장소 볼륨 관련 상수
+const (
+	// STORAGE_VOLUMES가 없을 때 STORAGE_DIR을 가리키는 볼륨 ID
+	DefaultStorageVolumeID = "default"
+
+	// 볼륨 배치 기본 가중치
+	DefaultStorageVolumeWeight = 1
+)
+
+// 데이터베이스 관련 상수
+const (
+	// 쓰기 직렬화 시 기본 대기열 크기
+	DefaultDBWriteQueueSize = 256
+
+	// 파일 락 홀더가 갱신하지 않으면 만료되는 기본 시간 (초, database 백엔드)
+	DefaultFileLockTTLSeconds = 30
+
+	// 파일 락을 얻기까지 기다리는 기본 시간 (초)
+	DefaultFileLockWaitSeconds = 10
+)
+
+// 파일 락 백엔드
+const (
+	// FileLockBackendMemory 프로세스 안에서만 유효한 락 (인스턴스가 하나일 때)
+	FileLockBackendMemory = "memory"
+
+	// FileLockBackendDatabase file_locks 테이블로 인스턴스 간에 공유하는 락
+	FileLockBackendDatabase = "database"
+)
+
+// 키 유도 관련 상수
+const (
+	// 기본 PBKDF2 반복 횟수
+	DefaultPBKDF2Iterations = 100000
+
+	// 반복 횟수 자동 보정 시 키 유도 한 번의 목표 시간 (밀리초)
+	DefaultPBKDF2TargetMillis = 250
+
+	// PBKDF2_ITERATIONS에 지정하면 시작 시 호스트에 맞게 보정
+	PBKDF2IterationsAuto = "auto"
+)
+
+// 감시 폴더 관련 상수
+const (
+	// 기본 안정화 대기 시간 (초)
+	DefaultWatchStableSeconds = 2
+
+	// 기본 동시 처리 워커 수
+	DefaultWatchWorkers = 4
+
+	// 원본 파일 처리 정책
+	WatchSourcePolicyMove   = "move"
+	WatchSourcePolicyDelete = "delete"
+	WatchSourcePolicyKeep   = "keep"
+)
+
 // Config 애플리케이션 설정 구조체
 type Config struct {
 	Server   ServerConfig   `json:"server"`
 	Database DatabaseConfig `json:"database"`
 	Security SecurityConfig `json:"security"`
 	App      AppConfig      `json:"app"`
+	Watch    WatchConfig    `json:"watch"`
+	Storage  StorageConfig  `json:"storage"`
+	Upload   UploadConfig   `json:"upload"`
+	Metrics  MetricsConfig  `json:"metrics"`
 }
 
 // ServerConfig 서버 관련 설정
@@ -38,18 +176,76 @@ type ServerConfig struct {
 	Host         string `json:"host"`
 	ReadTimeout  int    `json:"read_timeout"`
 	WriteTimeout int    `json:"write_timeout"`
+
+	// 응답 링크에 사용할 외부 주소 (예: https://files.example.com, 비어 있으면 요청에서 결정)
+	PublicBaseURL string `json:"public_base_url"`
+
+	// 헬스체크가 의존 서비스(DB, 저장소 볼륨) 하나를 기다리는 최대 시간 (넘으면 timeout으로 보고)
+	HealthCheckTimeout time.Duration `json:"health_check_timeout"`
 }
 
 // DatabaseConfig 데이터베이스 설정
 type DatabaseConfig struct {
 	Path        string `json:"path"`
 	AutoMigrate bool   `json:"auto_migrate"`
+
+	// 모든 쓰기를 단일 고루틴 대기열로 직렬화 (SQLite 잠금 경합 완화, 재시작 필요)
+	SerializeWrites bool `json:"serialize_writes"`
+	WriteQueueSize  int  `json:"write_queue_size"` // 쓰기 대기열 크기
+
+	// 파일/암호화 메타데이터 저장소 호출 계측 (재시작 필요)
+	SlowQueryThreshold time.Duration `json:"slow_query_threshold"` // 이 시간 이상 걸린 호출을 경고 로그로 남김 (0이면 끔)
+	QueryMetrics       bool          `json:"query_metrics"`        // 메서드별 호출 시간을 /metrics의 db_queries로 노출
+
+	// 파일 단위 작업(영구 삭제, 볼륨 이동) 직렬화 락 (재시작 필요)
+	FileLockBackend string        `json:"file_lock_backend"` // memory 또는 database (여러 인스턴스가 DB를 공유하면 database)
+	FileLockTTL     time.Duration `json:"file_lock_ttl"`     // 홀더가 비정상 종료했을 때 락이 회수되기까지의 시간
+	FileLockWait    time.Duration `json:"file_lock_wait"`    // 락을 기다리는 최대 시간 (넘기면 409)
 }
 
 // SecurityConfig 보안 설정
 type SecurityConfig struct {
 	AllowedOrigins []string `json:"allowed_origins"`
 	MaxFileSize    int64    `json:"max_file_size"`
+
+	// 패스워드 재사용 경고 (옵트인)
+	PasswordFingerprintEnabled bool   `json:"password_fingerprint_enabled"`
+	PasswordFingerprintSalt    string `json:"-"` // 지문 전용 salt (hex)
+	PasswordReuseThreshold     int    `json:"password_reuse_threshold"`
+
+	// 중복 제거 (같은 체크섬이면 blob 참조 레코드만 생성)
+	DedupEnabled       bool `json:"dedup_enabled"`
+	DedupProofRequired bool `json:"dedup_proof_required"` // 참조 전 소유 증명 챌린지 요구
+
+	// 유사 중복 조회의 기본 최대 해밍 거리 (클수록 오탐 증가)
+	SimilarityThreshold int `json:"similarity_threshold"`
+
+	// 클라이언트당 분당 요청 수 (운영환경에서만 적용, 리로드 가능)
+	RateLimitPerMinute int `json:"rate_limit_per_minute"`
+
+	// 파일+IP 조합당 분당 복호화 패스워드 시도 수 (모든 환경에 적용, 리로드 가능)
+	PasswordAttemptsPerMinute int `json:"password_attempts_per_minute"`
+	PasswordAttemptMaxKeys    int `json:"password_attempt_max_keys"` // 추적하는 조합 수 상한
+
+	// IP당 분당 짧은 파일 코드 조회 수 (모든 환경에 적용, 리로드 가능)
+	CodeLookupsPerMinute int `json:"code_lookups_per_minute"`
+
+	// 동시에 진행할 수 있는 트랜스코드 수 (넘는 요청은 503)
+	MaxConcurrentTranscodes int `json:"max_concurrent_transcodes"`
+
+	// X-Forwarded-For를 신뢰할 프록시 주소 범위 (CIDR, 비어 있으면 연결 주소를 클라이언트 IP로 사용)
+	TrustedProxies []string `json:"trusted_proxies"`
+
+	// 관리 API 토큰 (비어 있으면 관리 API 비활성화)
+	AdminAPIToken string `json:"-"`
+
+	// 운영환경에서 공장 초기화 API 허용 (재시작 필요, 다른 환경에서는 항상 허용)
+	AllowFactoryReset bool `json:"allow_factory_reset"`
+
+	// PBKDF2 반복 횟수 (자동 보정 시 시작할 때 보정한 값으로 대체)
+	PBKDF2Iterations     int           `json:"pbkdf2_iterations"`
+	PBKDF2AutoCalibrate  bool          `json:"pbkdf2_auto_calibrate"`
+	PBKDF2TargetDuration time.Duration `json:"pbkdf2_target_duration"` // 보정 목표 시간
 }
 
 // AppConfig 앱 관련 설정
@@ -58,6 +254,94 @@ type AppConfig struct {
 	Version     string `json:"version"`
 	Environment string `json:"environment"`
 	LogLevel    string `json:"log_level"`
+	WebhookURL  string `json:"webhook_url"` // 이벤트 알림 웹훅 URL (비어 있으면 비활성화)
+
+	// 요청 로그 샘플링 (리로드 가능)
+	AccessLog AccessLogConfig `json:"access_log"`
+
+	// 느린 요청 프로파일 캡처 (재시작 필요)
+	SlowRequest SlowRequestConfig `json:"slow_request"`
+}
+
+// AccessLogConfig 상태 코드 그룹별 요청 로그 샘플링 설정
+//
+// 비율은 N건 중 1건을 기록한다는 뜻이며 1이면 전부 기록합니다. 핸들러가 에러를
+// 반환한 요청과 같은 요청 ID로 이어지는 요청은 비율과 관계없이 기록합니다.
+type AccessLogConfig struct {
+	SuccessSampleRate     int `json:"success_sample_rate"`      // 1xx~3xx
+	ClientErrorSampleRate int `json:"client_error_sample_rate"` // 4xx
+	ServerErrorSampleRate int `json:"server_error_sample_rate"` // 5xx
+	SummarySeconds        int `json:"summary_seconds"`          // 생략한 로그 수 요약 주기
+}
+
+// SlowRequestConfig 느린 요청 프로파일 캡처 설정
+//
+// 처리 시간이 Threshold를 넘긴 요청이 있으면 그 시점의 고루틴 덤프와 주요 메트릭을
+// Dir/<request_id>.txt로 저장합니다. 저장은 분당 한 번으로 제한됩니다.
+type SlowRequestConfig struct {
+	ProfileEnabled bool          `json:"profile_enabled"`
+	Threshold      time.Duration `json:"threshold"`     // 0 이하면 기본값
+	Dir            string        `json:"dir"`           // 프로파일 저장 디렉터리 (없으면 생성)
+	MaxDirBytes    int64         `json:"max_dir_bytes"` // 디렉터리 용량 상한 (0 이하면 기본값)
+}
+
+// UploadConfig 업로드 파일 크기 정책
+type UploadConfig struct {
+	DefaultMaxSize int64                     `json:"default_max_size"` // 어느 그룹에도 속하지 않을 때의 제한
+	MIMEGroups     map[string]MIMEGroupLimit `json:"mime_groups"`      // 그룹 이름별 제한
+
+	// 업로드를 허용하는 MIME 타입 목록 (비어 있으면 기본 목록)
+	AllowedMimeTypes []string `json:"allowed_mime_types"`
+}
+
+// MIMEGroupLimit MIME 패턴 목록과 그 그룹의 최대 크기
+//
+// 패턴은 "image/png"처럼 정확한 타입이거나 "image/*"처럼 상위 타입 전체입니다.
+type MIMEGroupLimit struct {
+	Patterns []string `json:"patterns"`
+	MaxSize  int64    `json:"max_size"`
+}
+
+// StorageConfig 암호화 파일 저장소 설정
+type StorageConfig struct {
+	Dir string `json:"dir"` // 업로드된 파일의 암호화본 저장 디렉터리
+
+	// 새 파일을 나눠 저장할 볼륨 목록 (비어 있으면 Dir 하나를 사용)
+	Volumes []StorageVolume `json:"volumes"`
+}
+
+// StorageVolume 암호화 파일을 저장하는 볼륨 (디스크 또는 마운트 지점)
+type StorageVolume struct {
+	ID       string `json:"id"`        // 파일 레코드에 기록되는 볼륨 식별자 (변경 금지)
+	Path     string `json:"path"`      // 볼륨 루트 디렉터리 (미리 만들어져 있어야 함)
+	Weight   int    `json:"weight"`    // 배치 가중치 (0이면 DefaultStorageVolumeWeight)
+	MaxBytes int64  `json:"max_bytes"` // 저장할 최대 원본 크기 합계 (0이면 무제한)
+}
+
+// EffectiveVolumes 실제로 사용할 볼륨 목록을 반환합니다
+//
+// Volumes가 비어 있으면 Dir을 가리키는 DefaultStorageVolumeID 볼륨 하나를 반환합니다.
+func (s StorageConfig) EffectiveVolumes() []StorageVolume {
+	if len(s.Volumes) == 0 {
+		return []StorageVolume{{ID: DefaultStorageVolumeID, Path: s.Dir, Weight: DefaultStorageVolumeWeight}}
+	}
+	return s.Volumes
+}
+
+// WatchConfig 감시 폴더 자동 수집 설정
+type WatchConfig struct {
+	Dirs         []string      `json:"dirs"`          // 감시할 수집함 디렉터리 목록
+	ArchiveDir   string        `json:"archive_dir"`   // move 정책 시 원본 이동 디렉터리
+	SourcePolicy string        `json:"source_policy"` // 원본 처리 정책 (move, delete, keep)
+	StableDelay  time.Duration `json:"stable_delay"`  // 크기 변화가 없어야 하는 대기 시간
+	Workers      int           `json:"workers"`       // 동시 처리 워커 수
+	Password     string        `json:"-"`             // 자동 암호화에 사용할 패스워드
+}
+
+// MetricsConfig 관리자 대시보드용 일별 통계 스냅샷 설정
+type MetricsConfig struct {
+	SnapshotEnabled bool `json:"snapshot_enabled"` // 매일 자정(UTC)에 전날 스냅샷 저장 (재시작 필요)
+	RetentionDays   int  `json:"retention_days"`   // 보존 기간, 지난 스냅샷은 저장할 때 정리 (0 이하면 기본값)
 }
 
 // Load 환경변수에서 설정을 로드합니다
@@ -68,10 +352,24 @@ func Load() *Config {
 			Host:         getEnv("HOST", "localhost"),
 			ReadTimeout:  getEnvAsInt("READ_TIMEOUT", DefaultReadTimeoutSeconds),
 			WriteTimeout: getEnvAsInt("WRITE_TIMEOUT", DefaultWriteTimeoutSeconds),
+
+			PublicBaseURL: os.Getenv("PUBLIC_BASE_URL"),
+
+			HealthCheckTimeout: time.Duration(getEnvAsInt("HEALTH_CHECK_TIMEOUT_MS", DefaultHealthCheckTimeoutMillis)) * time.Millisecond,
 		},
 		Database: DatabaseConfig{
 			Path:        getEnv("DB_PATH", "./datalocker.db"),
 			AutoMigrate: getEnvAsBool("DB_AUTO_MIGRATE", true),
+
+			SerializeWrites: getEnvAsBool("DB_SERIALIZE_WRITES", false),
+			WriteQueueSize:  getEnvAsInt("DB_WRITE_QUEUE_SIZE", DefaultDBWriteQueueSize),
+
+			SlowQueryThreshold: time.Duration(getEnvAsInt("DB_SLOW_QUERY_MS", 0)) * time.Millisecond,
+			QueryMetrics:       getEnvAsBool("DB_QUERY_METRICS", false),
+
+			FileLockBackend: getEnv("FILE_LOCK_BACKEND", FileLockBackendMemory),
+			FileLockTTL:     time.Duration(getEnvAsInt("FILE_LOCK_TTL_SECONDS", DefaultFileLockTTLSeconds)) * time.Second,
+			FileLockWait:    time.Duration(getEnvAsInt("FILE_LOCK_WAIT_SECONDS", DefaultFileLockWaitSeconds)) * time.Second,
 		},
 		Security: SecurityConfig{
 			AllowedOrigins: []string{
@@ -79,12 +377,110 @@ func Load() *Config {
 				"http://localhost:34115", // Wails dev server
 			},
 			MaxFileSize: getEnvAsInt64("MAX_FILE_SIZE", DefaultMaxFileSizeBytes),
+
+			PasswordFingerprintEnabled: getEnvAsBool("PASSWORD_FINGERPRINT_ENABLED", false),
+			PasswordFingerprintSalt:    os.Getenv("PASSWORD_FINGERPRINT_SALT"),
+			PasswordReuseThreshold:     getEnvAsInt("PASSWORD_REUSE_THRESHOLD", DefaultPasswordReuseThreshold),
+
+			DedupEnabled:       getEnvAsBool("DEDUP_ENABLED", false),
+			DedupProofRequired: getEnvAsBool("DEDUP_PROOF_REQUIRED", true),
+
+			SimilarityThreshold: getEnvAsInt("SIMILARITY_THRESHOLD", DefaultSimilarityThreshold),
+
+			RateLimitPerMinute: getEnvAsInt("RATE_LIMIT_PER_MINUTE", DefaultRateLimitPerMinute),
+
+			PasswordAttemptsPerMinute: getEnvAsInt("PASSWORD_ATTEMPTS_PER_MINUTE", DefaultPasswordAttemptsPerMinute),
+			PasswordAttemptMaxKeys:    getEnvAsInt("PASSWORD_ATTEMPT_MAX_KEYS", DefaultPasswordAttemptMaxKeys),
+			CodeLookupsPerMinute:      getEnvAsInt("CODE_LOOKUPS_PER_MINUTE", DefaultCodeLookupsPerMinute),
+			TrustedProxies:            getEnvAsSlice("TRUSTED_PROXIES"),
+
+			MaxConcurrentTranscodes: getEnvAsInt("TRANSCODE_MAX_CONCURRENT", DefaultMaxConcurrentTranscodes),
+
+			AdminAPIToken:     os.Getenv("ADMIN_API_TOKEN"),
+			AllowFactoryReset: getEnvAsBool("ALLOW_FACTORY_RESET", false),
+
+			PBKDF2Iterations:     getEnvAsInt("PBKDF2_ITERATIONS", DefaultPBKDF2Iterations),
+			PBKDF2AutoCalibrate:  strings.EqualFold(os.Getenv("PBKDF2_ITERATIONS"), PBKDF2IterationsAuto),
+			PBKDF2TargetDuration: time.Duration(getEnvAsInt("PBKDF2_TARGET_MS", DefaultPBKDF2TargetMillis)) * time.Millisecond,
 		},
 		App: AppConfig{
 			Name:        "DataLocker",
 			Version:     "2.0.0",
 			Environment: getEnv("ENVIRONMENT", "development"),
 			LogLevel:    getEnv("LOG_LEVEL", "info"),
+			WebhookURL:  os.Getenv("WEBHOOK_URL"),
+
+			AccessLog: AccessLogConfig{
+				SuccessSampleRate:     getEnvAsInt("ACCESS_LOG_SAMPLE_2XX", DefaultAccessLogSuccessSampleRate),
+				ClientErrorSampleRate: getEnvAsInt("ACCESS_LOG_SAMPLE_4XX", DefaultAccessLogClientErrorSampleRate),
+				ServerErrorSampleRate: getEnvAsInt("ACCESS_LOG_SAMPLE_5XX", DefaultAccessLogServerErrorSampleRate),
+				SummarySeconds:        getEnvAsInt("ACCESS_LOG_SUMMARY_SECONDS", DefaultAccessLogSummarySeconds),
+			},
+
+			SlowRequest: SlowRequestConfig{
+				ProfileEnabled: getEnvAsBool("SLOW_REQUEST_PROFILE_ENABLED", false),
+				Threshold:      time.Duration(getEnvAsInt("SLOW_REQUEST_THRESHOLD_MS", DefaultSlowRequestThresholdMillis)) * time.Millisecond,
+				Dir:            getEnv("SLOW_REQUEST_PROFILE_DIR", "./requests-slow"),
+				MaxDirBytes:    getEnvAsInt64("SLOW_REQUEST_PROFILE_MAX_BYTES", DefaultSlowRequestMaxDirBytes),
+			},
+		},
+		Watch: WatchConfig{
+			Dirs:         getEnvAsSlice("WATCH_DIRS"),
+			ArchiveDir:   getEnv("WATCH_ARCHIVE_DIR", "./storage/archive"),
+			SourcePolicy: getEnv("WATCH_SOURCE_POLICY", WatchSourcePolicyMove),
+			StableDelay:  time.Duration(getEnvAsInt("WATCH_STABLE_SECONDS", DefaultWatchStableSeconds)) * time.Second,
+			Workers:      getEnvAsInt("WATCH_WORKERS", DefaultWatchWorkers),
+			Password:     os.Getenv("WATCH_PASSWORD"),
+		},
+		Storage: StorageConfig{
+			Dir:     getEnv("STORAGE_DIR", "./storage"),
+			Volumes: getEnvAsStorageVolumes("STORAGE_VOLUMES"),
+		},
+		Upload: UploadConfig{
+			DefaultMaxSize: getEnvAsInt64("UPLOAD_DEFAULT_MAX_SIZE", DefaultUploadMaxSize),
+			MIMEGroups:     getEnvAsMIMEGroups("UPLOAD_MIME_GROUPS", DefaultMIMEGroups()),
+
+			AllowedMimeTypes: getEnvAsSlice("UPLOAD_ALLOWED_MIME_TYPES"),
+		},
+		Metrics: MetricsConfig{
+			SnapshotEnabled: getEnvAsBool("METRICS_SNAPSHOT_ENABLED", true),
+			RetentionDays:   getEnvAsInt("METRICS_RETENTION_DAYS", DefaultMetricsRetentionDays),
+		},
+	}
+}
+
+// LoadFile 환경변수 설정 위에 JSON 설정 파일의 값을 덮어씁니다
+//
+// path가 비어 있으면 Load와 같습니다. 파일에 없는 항목은 환경변수 값을 유지하며,
+// 맵 항목(mime_groups)은 기존 값과 병합됩니다.
+func LoadFile(path string) (*Config, error) {
+	cfg := Load()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("설정 파일 읽기 실패: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("설정 파일 파싱 실패 (%s): %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// DefaultMIMEGroups 기본 MIME 그룹별 제한 (이미지 20MB, 문서 100MB)
+func DefaultMIMEGroups() map[string]MIMEGroupLimit {
+	return map[string]MIMEGroupLimit{
+		"image": {
+			Patterns: []string{"image/*"},
+			MaxSize:  DefaultImageMaxSize,
+		},
+		"document": {
+			Patterns: []string{"application/pdf", "text/*", "application/msword", "application/vnd.openxmlformats-officedocument.*"},
+			MaxSize:  DefaultDocumentMaxSize,
 		},
 	}
 }
@@ -126,3 +522,116 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvAsMIMEGroups MIME 그룹 제한 환경변수를 파싱합니다
+//
+// 형식: 이름=패턴,패턴:최대바이트;이름=패턴:최대바이트
+// 예: image=image/*:20971520;document=application/pdf,text/*:104857600
+// 형식이 잘못된 항목이 있으면 기본값을 사용합니다.
+func getEnvAsMIMEGroups(key string, defaultValue map[string]MIMEGroupLimit) map[string]MIMEGroupLimit {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	groups := make(map[string]MIMEGroupLimit)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, rest, ok := strings.Cut(entry, "=")
+		if !ok {
+			return defaultValue
+		}
+
+		patterns, size, ok := strings.Cut(rest, ":")
+		if !ok {
+			return defaultValue
+		}
+
+		maxSize, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+		if err != nil || maxSize <= 0 {
+			return defaultValue
+		}
+
+		var list []string
+		for _, pattern := range strings.Split(patterns, ",") {
+			if trimmed := strings.TrimSpace(pattern); trimmed != "" {
+				list = append(list, trimmed)
+			}
+		}
+		if len(list) == 0 {
+			return defaultValue
+		}
+
+		groups[strings.TrimSpace(name)] = MIMEGroupLimit{Patterns: list, MaxSize: maxSize}
+	}
+
+	return groups
+}
+
+// getEnvAsStorageVolumes "id=경로[,가중치[,최대 바이트]];..." 형식의 볼륨 목록을 파싱
+//
+// 형식이 잘못된 항목이 하나라도 있으면 nil(STORAGE_DIR 단일 볼륨)을 반환합니다.
+func getEnvAsStorageVolumes(key string) []StorageVolume {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	var volumes []StorageVolume
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, rest, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(id) == "" {
+			return nil
+		}
+
+		fields := strings.Split(rest, ",")
+		if len(fields) > 3 || strings.TrimSpace(fields[0]) == "" {
+			return nil
+		}
+
+		volume := StorageVolume{ID: strings.TrimSpace(id), Path: strings.TrimSpace(fields[0])}
+		if len(fields) > 1 {
+			weight, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+			if err != nil || weight < 0 {
+				return nil
+			}
+			volume.Weight = weight
+		}
+		if len(fields) > 2 {
+			maxBytes, err := strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64)
+			if err != nil || maxBytes < 0 {
+				return nil
+			}
+			volume.MaxBytes = maxBytes
+		}
+
+		volumes = append(volumes, volume)
+	}
+
+	return volumes
+}
+
+// getEnvAsSlice 쉼표로 구분된 환경변수를 슬라이스로 변환
+func getEnvAsSlice(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
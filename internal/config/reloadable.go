@@ -0,0 +1,284 @@
+// Package config provides configuration management for DataLocker application.
+// This file implements live reload of the settings that are safe to swap at runtime.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 리로드 요청 출처 (감사 로그에 기록)
+const (
+	ReloadSourceSignal = "sighup"
+	ReloadSourceAPI    = "api"
+)
+
+// 리로드 관련 에러
+var (
+	ErrConfigFileNotSet   = errors.New("리로드할 설정 파일이 지정되지 않았습니다 (CONFIG_FILE)")
+	ErrInvalidReloadValue = errors.New("리로드할 설정 값이 올바르지 않습니다")
+)
+
+// ReloadResult 설정 리로드 결과
+type ReloadResult struct {
+	Source  string   `json:"source"`
+	Changed []string `json:"changed"` // 새 값이 적용된 키
+	Ignored []string `json:"ignored"` // 바뀌었지만 재시작해야 적용되는 키
+}
+
+// configField 리로드 시 비교하는 설정 항목
+type configField struct {
+	key string
+	get func(cfg *Config) any
+	set func(dst, src *Config) // nil이면 실행 중 교체 불가
+}
+
+// reloadFields 리로드 시 비교하는 항목 목록
+//
+// set이 있는 항목만 실행 중에 교체하고, 나머지는 바뀌어도 경고만 남깁니다.
+var reloadFields = []configField{
+	{
+		key: "security.rate_limit_per_minute",
+		get: func(c *Config) any { return c.Security.RateLimitPerMinute },
+		set: func(dst, src *Config) { dst.Security.RateLimitPerMinute = src.Security.RateLimitPerMinute },
+	},
+	{
+		key: "security.password_attempts_per_minute",
+		get: func(c *Config) any { return c.Security.PasswordAttemptsPerMinute },
+		set: func(dst, src *Config) {
+			dst.Security.PasswordAttemptsPerMinute = src.Security.PasswordAttemptsPerMinute
+		},
+	},
+	{
+		key: "security.code_lookups_per_minute",
+		get: func(c *Config) any { return c.Security.CodeLookupsPerMinute },
+		set: func(dst, src *Config) { dst.Security.CodeLookupsPerMinute = src.Security.CodeLookupsPerMinute },
+	},
+	{
+		key: "upload.default_max_size",
+		get: func(c *Config) any { return c.Upload.DefaultMaxSize },
+		set: func(dst, src *Config) { dst.Upload.DefaultMaxSize = src.Upload.DefaultMaxSize },
+	},
+	{
+		key: "upload.mime_groups",
+		get: func(c *Config) any { return c.Upload.MIMEGroups },
+		set: func(dst, src *Config) { dst.Upload.MIMEGroups = src.Upload.MIMEGroups },
+	},
+	{
+		key: "upload.allowed_mime_types",
+		get: func(c *Config) any { return c.Upload.AllowedMimeTypes },
+		set: func(dst, src *Config) { dst.Upload.AllowedMimeTypes = src.Upload.AllowedMimeTypes },
+	},
+	{
+		key: "app.log_level",
+		get: func(c *Config) any { return c.App.LogLevel },
+		set: func(dst, src *Config) { dst.App.LogLevel = src.App.LogLevel },
+	},
+	{
+		key: "app.webhook_url",
+		get: func(c *Config) any { return c.App.WebhookURL },
+		set: func(dst, src *Config) { dst.App.WebhookURL = src.App.WebhookURL },
+	},
+	{
+		key: "app.access_log",
+		get: func(c *Config) any { return c.App.AccessLog },
+		set: func(dst, src *Config) { dst.App.AccessLog = src.App.AccessLog },
+	},
+	{key: "security.trusted_proxies", get: func(c *Config) any { return c.Security.TrustedProxies }},
+	{key: "server.host", get: func(c *Config) any { return c.Server.Host }},
+	{key: "server.port", get: func(c *Config) any { return c.Server.Port }},
+	{key: "server.public_base_url", get: func(c *Config) any { return c.Server.PublicBaseURL }},
+	{key: "database.path", get: func(c *Config) any { return c.Database.Path }},
+	{key: "storage.dir", get: func(c *Config) any { return c.Storage.Dir }},
+	{key: "storage.volumes", get: func(c *Config) any { return c.Storage.Volumes }},
+}
+
+// ReloadableConfig 실행 중에 일부 항목을 교체할 수 있는 설정
+//
+// Get은 항상 완성된 스냅샷을 반환하며, 리로드는 새 스냅샷을 만든 뒤 한 번에
+// 교체합니다. 스냅샷은 공유되므로 호출자가 수정하면 안 됩니다.
+type ReloadableConfig struct {
+	path   string
+	logger *logrus.Logger
+
+	current atomic.Pointer[Config]
+
+	mu        sync.Mutex // 리로드 직렬화
+	listeners []func(cfg *Config)
+}
+
+// NewReloadableConfig 초기 설정으로 리로드 가능한 설정을 생성합니다
+//
+// path는 리로드 시 다시 읽을 설정 파일이며, 비어 있으면 리로드가 거부됩니다.
+func NewReloadableConfig(path string, initial *Config, logger *logrus.Logger) *ReloadableConfig {
+	if initial == nil {
+		panic("initial config cannot be nil")
+	}
+	if logger == nil {
+		panic("logger cannot be nil")
+	}
+
+	r := &ReloadableConfig{path: path, logger: logger}
+	r.current.Store(initial)
+	return r
+}
+
+// Get 현재 설정 스냅샷을 반환합니다
+func (r *ReloadableConfig) Get() *Config {
+	return r.current.Load()
+}
+
+// OnReload 리로드로 설정이 바뀔 때마다 호출할 함수를 등록합니다
+//
+// 함수는 리로드 중에 호출되므로 안에서 Reload를 호출하면 안 됩니다.
+func (r *ReloadableConfig) OnReload(fn func(cfg *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Reload 설정 파일을 다시 읽어 교체 가능한 항목만 적용합니다
+//
+// 값이 하나라도 잘못되면 아무것도 적용하지 않습니다. 포트, DB 경로처럼
+// 재시작이 필요한 항목이 바뀌었으면 경고만 남기고 기존 값을 유지합니다.
+// 결과는 성공/실패와 관계없이 감사 로그에 기록됩니다.
+func (r *ReloadableConfig) Reload(source string) (*ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result, err := r.reload(source)
+	if err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"audit":  "config",
+			"source": source,
+		}).Error("설정 리로드에 실패했습니다")
+		return nil, err
+	}
+
+	for _, key := range result.Ignored {
+		r.logger.WithFields(logrus.Fields{
+			"audit":  "config",
+			"source": source,
+			"key":    key,
+		}).Warn("재시작이 필요한 설정이 변경되어 적용하지 않았습니다")
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"audit":   "config",
+		"source":  source,
+		"changed": result.Changed,
+		"ignored": result.Ignored,
+	}).Info("설정을 리로드했습니다")
+
+	return result, nil
+}
+
+// reload 새 스냅샷을 만들어 교체하고 리스너에 알립니다
+func (r *ReloadableConfig) reload(source string) (*ReloadResult, error) {
+	if r.path == "" {
+		return nil, ErrConfigFileNotSet
+	}
+
+	loaded, err := LoadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateReloadable(loaded); err != nil {
+		return nil, err
+	}
+
+	current := r.current.Load()
+	next := *current
+	result := &ReloadResult{Source: source, Changed: []string{}, Ignored: []string{}}
+
+	for _, field := range reloadFields {
+		if reflect.DeepEqual(field.get(current), field.get(loaded)) {
+			continue
+		}
+		if field.set == nil {
+			result.Ignored = append(result.Ignored, field.key)
+			continue
+		}
+		field.set(&next, loaded)
+		result.Changed = append(result.Changed, field.key)
+	}
+
+	if len(result.Changed) == 0 {
+		return result, nil
+	}
+
+	r.current.Store(&next)
+	for _, listener := range r.listeners {
+		listener(&next)
+	}
+
+	return result, nil
+}
+
+// validateReloadable 실행 중 교체할 항목의 값이 올바른지 확인합니다
+func validateReloadable(cfg *Config) error {
+	if cfg.Security.RateLimitPerMinute <= 0 {
+		return fmt.Errorf("%w: rate_limit_per_minute=%d", ErrInvalidReloadValue, cfg.Security.RateLimitPerMinute)
+	}
+
+	if cfg.Security.PasswordAttemptsPerMinute <= 0 {
+		return fmt.Errorf("%w: password_attempts_per_minute=%d", ErrInvalidReloadValue, cfg.Security.PasswordAttemptsPerMinute)
+	}
+
+	if cfg.Security.CodeLookupsPerMinute <= 0 {
+		return fmt.Errorf("%w: code_lookups_per_minute=%d", ErrInvalidReloadValue, cfg.Security.CodeLookupsPerMinute)
+	}
+
+	if _, err := logrus.ParseLevel(cfg.App.LogLevel); err != nil {
+		return fmt.Errorf("%w: log_level=%q", ErrInvalidReloadValue, cfg.App.LogLevel)
+	}
+
+	if cfg.App.WebhookURL != "" {
+		u, err := url.Parse(cfg.App.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: webhook_url=%q", ErrInvalidReloadValue, cfg.App.WebhookURL)
+		}
+	}
+
+	if err := validateAccessLog(cfg.App.AccessLog); err != nil {
+		return err
+	}
+
+	if cfg.Upload.DefaultMaxSize < 0 {
+		return fmt.Errorf("%w: default_max_size=%d", ErrInvalidReloadValue, cfg.Upload.DefaultMaxSize)
+	}
+
+	for name, group := range cfg.Upload.MIMEGroups {
+		if group.MaxSize <= 0 || len(group.Patterns) == 0 {
+			return fmt.Errorf("%w: mime_groups.%s", ErrInvalidReloadValue, name)
+		}
+	}
+
+	return nil
+}
+
+// validateAccessLog 요청 로그 샘플링 비율과 요약 주기가 1 이상인지 확인합니다
+func validateAccessLog(cfg AccessLogConfig) error {
+	values := []struct {
+		key   string
+		value int
+	}{
+		{"access_log.success_sample_rate", cfg.SuccessSampleRate},
+		{"access_log.client_error_sample_rate", cfg.ClientErrorSampleRate},
+		{"access_log.server_error_sample_rate", cfg.ServerErrorSampleRate},
+		{"access_log.summary_seconds", cfg.SummarySeconds},
+	}
+	for _, v := range values {
+		if v.value < 1 {
+			return fmt.Errorf("%w: %s=%d", ErrInvalidReloadValue, v.key, v.value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupReloadable 설정 파일을 쓰고 그 파일로 시작한 리로드 가능 설정을 생성합니다
+func setupReloadable(t *testing.T, content string) (*ReloadableConfig, string, *bytes.Buffer) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, path, content)
+
+	initial, err := LoadFile(path)
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	return NewReloadableConfig(path, initial, logger), path, &logs
+}
+
+// writeConfigFile 설정 파일 내용을 씁니다
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestLoadFile_OverlaysEnvironment(t *testing.T) {
+	t.Setenv("PORT", "9000")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, path, `{"app": {"log_level": "debug"}, "security": {"rate_limit_per_minute": 30}}`)
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", cfg.App.LogLevel)
+	assert.Equal(t, 30, cfg.Security.RateLimitPerMinute)
+	assert.Equal(t, "9000", cfg.Server.Port, "파일에 없는 항목은 환경변수 값 유지")
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestReloadableConfig_AppliesSafeFields(t *testing.T) {
+	r, path, logs := setupReloadable(t, `{"app": {"log_level": "info"}}`)
+
+	var notified *Config
+	r.OnReload(func(cfg *Config) { notified = cfg })
+
+	writeConfigFile(t, path, `{
+		"app": {"log_level": "warn", "webhook_url": "https://hooks.example.com/datalocker", "access_log": {"success_sample_rate": 1000}},
+		"security": {"rate_limit_per_minute": 10},
+		"upload": {"allowed_mime_types": ["text/csv"]}
+	}`)
+
+	result, err := r.Reload(ReloadSourceAPI)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"security.rate_limit_per_minute",
+		"upload.allowed_mime_types",
+		"app.log_level",
+		"app.webhook_url",
+		"app.access_log",
+	}, result.Changed)
+	assert.Empty(t, result.Ignored)
+
+	cfg := r.Get()
+	assert.Equal(t, "warn", cfg.App.LogLevel)
+	assert.Equal(t, 10, cfg.Security.RateLimitPerMinute)
+	assert.Equal(t, "https://hooks.example.com/datalocker", cfg.App.WebhookURL)
+	assert.Equal(t, 1000, cfg.App.AccessLog.SuccessSampleRate)
+	assert.Equal(t, DefaultAccessLogClientErrorSampleRate, cfg.App.AccessLog.ClientErrorSampleRate, "파일에 없는 항목은 기존 값 유지")
+	assert.Same(t, cfg, notified)
+
+	assert.Contains(t, logs.String(), `"audit":"config"`)
+	assert.Contains(t, logs.String(), "app.webhook_url")
+}
+
+func TestReloadableConfig_IgnoresRestartFields(t *testing.T) {
+	r, path, logs := setupReloadable(t, `{"server": {"port": "8080"}, "database": {"path": "./a.db"}}`)
+
+	writeConfigFile(t, path, `{"server": {"port": "9090"}, "database": {"path": "./b.db"}, "app": {"log_level": "debug"}}`)
+
+	result, err := r.Reload(ReloadSourceSignal)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app.log_level"}, result.Changed)
+	assert.ElementsMatch(t, []string{"server.port", "database.path"}, result.Ignored)
+
+	cfg := r.Get()
+	assert.Equal(t, "8080", cfg.Server.Port)
+	assert.Equal(t, "./a.db", cfg.Database.Path)
+	assert.Equal(t, "debug", cfg.App.LogLevel)
+	assert.Contains(t, logs.String(), "재시작이 필요한 설정")
+}
+
+func TestReloadableConfig_RejectsInvalidValues(t *testing.T) {
+	testCases := map[string]string{
+		"레이트 리밋 0":     `{"security": {"rate_limit_per_minute": 0}}`,
+		"패스워드 시도 0":    `{"security": {"password_attempts_per_minute": 0}}`,
+		"코드 조회 0":      `{"security": {"code_lookups_per_minute": 0}}`,
+		"알 수 없는 로그 레벨": `{"app": {"log_level": "loud"}}`,
+		"잘못된 웹훅 URL":   `{"app": {"webhook_url": "ftp://example.com"}}`,
+		"샘플링 비율 0":     `{"app": {"access_log": {"client_error_sample_rate": 0}}}`,
+		"요약 주기 음수":     `{"app": {"access_log": {"summary_seconds": -1}}}`,
+		"그룹 제한 0":      `{"upload": {"mime_groups": {"image": {"patterns": ["image/*"], "max_size": 0}}}}`,
+	}
+
+	for name, content := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r, path, logs := setupReloadable(t, `{}`)
+			before := r.Get()
+
+			writeConfigFile(t, path, content)
+			_, err := r.Reload(ReloadSourceAPI)
+			assert.ErrorIs(t, err, ErrInvalidReloadValue)
+			assert.Same(t, before, r.Get(), "실패하면 아무것도 적용하지 않음")
+			assert.Contains(t, logs.String(), "설정 리로드에 실패했습니다")
+		})
+	}
+}
+
+func TestReloadableConfig_Errors(t *testing.T) {
+	r := NewReloadableConfig("", Load(), logrus.New())
+	_, err := r.Reload(ReloadSourceAPI)
+	assert.ErrorIs(t, err, ErrConfigFileNotSet)
+
+	r, path, _ := setupReloadable(t, `{}`)
+	writeConfigFile(t, path, `{not json`)
+	_, err = r.Reload(ReloadSourceAPI)
+	assert.Error(t, err)
+
+	// 변경이 없으면 스냅샷을 유지
+	writeConfigFile(t, path, `{}`)
+	before := r.Get()
+	result, err := r.Reload(ReloadSourceAPI)
+	require.NoError(t, err)
+	assert.Empty(t, result.Changed)
+	assert.Same(t, before, r.Get())
+}
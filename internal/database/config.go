@@ -3,6 +3,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -68,6 +69,14 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 	return database, nil
 }
 
+// NowUTC GORM이 생성·수정 시각을 채울 때 쓰는 현재 시각 (UTC)
+//
+// 기간 조회와 키셋 페이지네이션은 저장된 시각 문자열을 그대로 비교하므로 호스트
+// 시간대와 무관하게 UTC로 저장합니다. 테스트용 연결도 같은 함수를 NowFunc로 씁니다.
+func NowUTC() time.Time {
+	return time.Now().UTC()
+}
+
 // connect 데이터베이스에 연결합니다
 func (d *Database) connect() error {
 	// SQLite 연결 문자열 구성
@@ -81,6 +90,7 @@ func (d *Database) connect() error {
 		Logger:                                   gormLogger,
 		DisableForeignKeyConstraintWhenMigrating: false,
 		SkipDefaultTransaction:                   false,
+		NowFunc:                                  NowUTC,
 	}
 
 	// 데이터베이스 연결
@@ -210,7 +220,10 @@ func (d *Database) Close() error {
 }
 
 // HealthCheck 데이터베이스 연결 상태를 확인합니다
-func (d *Database) HealthCheck() error {
+//
+// 연결 확인 뒤 스키마를 한 번 읽어 잠금 대기(busy timeout)에 걸리는지도 확인하며,
+// ctx가 끝나면 진행 중인 쿼리를 중단하고 반환합니다.
+func (d *Database) HealthCheck(ctx context.Context) error {
 	if d.DB == nil {
 		return fmt.Errorf("데이터베이스가 연결되지 않았습니다")
 	}
@@ -220,10 +233,15 @@ func (d *Database) HealthCheck() error {
 		return fmt.Errorf("SQL DB 인스턴스 획득 실패: %w", err)
 	}
 
-	if err := sqlDB.Ping(); err != nil {
+	if err := sqlDB.PingContext(ctx); err != nil {
 		return fmt.Errorf("데이터베이스 핑 실패: %w", err)
 	}
 
+	var tables int64
+	if err := d.DB.WithContext(ctx).Raw("SELECT COUNT(*) FROM sqlite_master").Scan(&tables).Error; err != nil {
+		return fmt.Errorf("데이터베이스 조회 실패: %w", err)
+	}
+
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -82,7 +83,7 @@ func TestDatabase_HealthCheck(t *testing.T) {
 	defer cleanup()
 
 	// 헬스체크 성공
-	err := db.HealthCheck()
+	err := db.HealthCheck(context.Background())
 	assert.NoError(t, err)
 }
 
@@ -95,7 +96,7 @@ func TestDatabase_HealthCheck_ClosedConnection(t *testing.T) {
 	require.NoError(t, err)
 
 	// 헬스체크 실패 확인
-	err = db.HealthCheck()
+	err = db.HealthCheck(context.Background())
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "데이터베이스가 연결되지 않았습니다")
 }
@@ -302,7 +303,7 @@ func BenchmarkDatabase_HealthCheck(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		err := db.HealthCheck()
+		err := db.HealthCheck(context.Background())
 		if err != nil {
 			b.Fatal(err)
 		}
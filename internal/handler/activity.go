@@ -0,0 +1,76 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the recent file activity feed endpoint.
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ActivityHandler 최근 파일 활동 피드 핸들러
+type ActivityHandler struct {
+	activityService service.ActivityService
+}
+
+// NewActivityHandler 새로운 최근 파일 활동 피드 핸들러를 생성합니다
+func NewActivityHandler(activityService service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+	}
+}
+
+// Recent 최근 업로드, 실패, 삭제 이벤트를 최신순으로 반환합니다
+//
+// GET /api/v1/activity?limit=20&offset=0&owner_id=3
+// 이벤트마다 종류, 파일 ID와 이름, 시각, 행위자만 담고 경로나 키 정보는 담지 않습니다.
+// owner_id를 주면 그 사용자의 파일만, 이벤트가 없으면 빈 배열로 200을 응답합니다.
+// 결과는 10초 동안 캐시됩니다.
+func (h *ActivityHandler) Recent(c echo.Context) error {
+	limit, err := parseOptionalInt(c.QueryParam("limit"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 limit 값입니다", err.Error())
+	}
+
+	offset, err := parseOptionalInt(c.QueryParam("offset"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 offset 값입니다", err.Error())
+	}
+
+	ownerID, err := parseOptionalOwnerID(c.QueryParam("owner_id"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 사용자 ID입니다", err.Error())
+	}
+
+	feed, err := h.activityService.Recent(c.Request().Context(), service.ActivityQuery{
+		OwnerID: ownerID,
+		Offset:  offset,
+		Limit:   limit,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidActivityPage) {
+			return response.BadRequest(c, "잘못된 조회 범위입니다", err.Error())
+		}
+		return response.InternalError(c, "활동 피드 조회에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, feed, "활동 피드를 조회했습니다")
+}
+
+// parseOptionalOwnerID 비어 있으면 0(전체)을, 아니면 양의 사용자 ID를 반환합니다
+func parseOptionalOwnerID(value string) (uint, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || parsed == 0 {
+		return 0, fmt.Errorf("양의 정수가 아닙니다: %s", value)
+	}
+	return uint(parsed), nil
+}
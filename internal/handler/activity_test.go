@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"DataLocker/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubActivityService 요청 조건을 기록하고 고정 피드를 반환하는 활동 피드 서비스
+type stubActivityService struct {
+	feed  *service.ActivityFeed
+	err   error
+	query service.ActivityQuery
+}
+
+func (s *stubActivityService) Recent(_ context.Context, query service.ActivityQuery) (*service.ActivityFeed, error) {
+	s.query = query
+	return s.feed, s.err
+}
+
+func TestActivityHandler_Recent(t *testing.T) {
+	activity := &stubActivityService{feed: &service.ActivityFeed{
+		Events: []service.ActivityEvent{{
+			Type:       "deleted",
+			FileID:     3,
+			FileName:   "report.pdf",
+			OccurredAt: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+			Actor:      "admin",
+		}},
+		Total: 1,
+		Limit: 20,
+	}}
+	h := NewActivityHandler(activity)
+
+	c, rec := createTestContext(http.MethodGet, "/api/v1/activity?limit=20&offset=5&owner_id=3")
+	require.NoError(t, h.Recent(c))
+	body := assertSuccessResponse(t, rec)
+	assert.Equal(t, service.ActivityQuery{OwnerID: 3, Offset: 5, Limit: 20}, activity.query)
+
+	data := body["data"].(map[string]interface{})
+	events := data["events"].([]interface{})
+	require.Len(t, events, 1)
+	event := events[0].(map[string]interface{})
+	assert.Equal(t, "deleted", event["type"])
+	assert.Equal(t, "report.pdf", event["file_name"])
+	assert.Equal(t, "admin", event["actor"])
+	assert.Equal(t, "2025-03-01T09:00:00Z", event["occurred_at"])
+	assert.NotContains(t, event, "encrypted_path")
+	assert.NotContains(t, event, "salt")
+
+	// 조건을 생략하면 서비스 기본값에 맡김
+	c, _ = createTestContext(http.MethodGet, "/api/v1/activity")
+	require.NoError(t, h.Recent(c))
+	assert.Equal(t, service.ActivityQuery{}, activity.query)
+
+	// 이벤트가 없으면 빈 배열
+	activity.feed = &service.ActivityFeed{Events: []service.ActivityEvent{}, Limit: 10}
+	c, rec = createTestContext(http.MethodGet, "/api/v1/activity")
+	require.NoError(t, h.Recent(c))
+	body = assertSuccessResponse(t, rec)
+	assert.Equal(t, []interface{}{}, body["data"].(map[string]interface{})["events"])
+
+	for _, query := range []string{"limit=abc", "offset=1.5", "owner_id=0", "owner_id=-1"} {
+		c, rec = createTestContext(http.MethodGet, "/api/v1/activity?"+query)
+		require.NoError(t, h.Recent(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	activity.err = fmt.Errorf("%w: limit 500", service.ErrInvalidActivityPage)
+	c, rec = createTestContext(http.MethodGet, "/api/v1/activity?limit=500")
+	require.NoError(t, h.Recent(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	activity.err = errors.New("db down")
+	c, rec = createTestContext(http.MethodGet, "/api/v1/activity")
+	require.NoError(t, h.Recent(c))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,410 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the token-protected remote admin endpoints.
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+	"DataLocker/pkg/httputil"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminHandler 원격 관리 핸들러
+type AdminHandler struct {
+	adminService service.AdminService
+	links        *LinkBuilder
+}
+
+// adminFileList 링크를 덧붙인 관리용 파일 목록 응답
+type adminFileList struct {
+	Files    []*fileResponse `json:"files"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+}
+
+// adminFileCursorPage 링크를 덧붙인 커서 기반 관리용 파일 목록 응답
+type adminFileCursorPage struct {
+	Files      []*fileResponse `json:"files"`
+	NextCursor string          `json:"next_cursor"` // 비어 있으면 마지막 페이지
+	PageSize   int             `json:"page_size"`
+}
+
+// NewAdminHandler 새로운 원격 관리 핸들러를 생성합니다
+func NewAdminHandler(adminService service.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// SetLinkBuilder 파일 응답에 링크를 포함하도록 설정합니다
+//
+// 호출하지 않으면 응답에 links 항목이 없습니다.
+func (h *AdminHandler) SetLinkBuilder(links *LinkBuilder) {
+	h.links = links
+}
+
+// ListFiles 파일 목록을 조회합니다
+//
+// GET /api/v1/admin/files?page=&page_size=&sort=&order=&status=&mime=&name=&external_id=&meta_key=&meta_value=&min_size=&max_size=&from=&to=
+// sort는 created_at(기본), name, size, status, order는 asc 또는 desc(기본)입니다.
+// 나머지는 repository.FileFilter 조건으로, mime은 MIME 접두사(image/), name은 파일명
+// 부분 일치, meta_key/meta_value는 사용자 메타데이터의 키('.'으로 중첩)와 값,
+// min_size/max_size는 바이트(포함), from/to는 생성 시각 [from, to)입니다.
+//
+// GET /api/v1/admin/files?cursor=&page_size=
+// cursor 파라미터가 있으면(첫 페이지는 빈 값) 최신순 커서 페이지네이션으로 조회하고
+// 응답의 next_cursor를 다음 요청에 넘깁니다. page, sort, order와 함께 쓸 수 없습니다.
+func (h *AdminHandler) ListFiles(c echo.Context) error {
+	if c.QueryParams().Has("cursor") {
+		return h.listFilesAfter(c)
+	}
+
+	page, err := parseOptionalInt(c.QueryParam("page"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 페이지 번호입니다", err.Error())
+	}
+
+	pageSize, err := parseOptionalInt(c.QueryParam("page_size"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 페이지 크기입니다", err.Error())
+	}
+
+	filter, err := parseFileFilter(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 필터 조건입니다", err.Error())
+	}
+
+	sort := repository.SortOption{
+		Field:     c.QueryParam("sort"),
+		Direction: c.QueryParam("order"),
+	}
+
+	list, err := h.adminService.ListFiles(c.Request().Context(), filter, page, pageSize, sort)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidSortOption):
+			return response.BadRequest(c, "잘못된 정렬 조건입니다", err.Error())
+		case errors.Is(err, repository.ErrInvalidFileFilter):
+			return response.BadRequest(c, "잘못된 필터 조건입니다", err.Error())
+		default:
+			return response.InternalError(c, "파일 목록 조회에 실패했습니다", err.Error())
+		}
+	}
+
+	files := make([]*fileResponse, 0, len(list.Files))
+	for _, file := range list.Files {
+		files = append(files, withLinks(c, h.links, file))
+	}
+
+	return response.Success(c, &adminFileList{
+		Files:    files,
+		Total:    list.Total,
+		Page:     list.Page,
+		PageSize: list.PageSize,
+	}, "파일 목록을 조회했습니다")
+}
+
+// listFilesAfter 커서 다음 파일 목록을 조회합니다
+func (h *AdminHandler) listFilesAfter(c echo.Context) error {
+	for _, name := range append([]string{"page", "sort", "order"}, fileFilterParams...) {
+		if c.QueryParam(name) != "" {
+			return response.BadRequest(c, "잘못된 목록 조건입니다", "cursor는 page, sort, order, 필터 조건과 함께 사용할 수 없습니다")
+		}
+	}
+
+	pageSize, err := parseOptionalInt(c.QueryParam("page_size"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 페이지 크기입니다", err.Error())
+	}
+
+	page, err := h.adminService.ListFilesAfter(c.Request().Context(), c.QueryParam("cursor"), pageSize)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return response.BadRequest(c, "잘못된 커서입니다", err.Error())
+		}
+		return response.InternalError(c, "파일 목록 조회에 실패했습니다", err.Error())
+	}
+
+	files := make([]*fileResponse, 0, len(page.Files))
+	for _, file := range page.Files {
+		files = append(files, withLinks(c, h.links, file))
+	}
+
+	return response.Success(c, &adminFileCursorPage{
+		Files:      files,
+		NextCursor: page.NextCursor,
+		PageSize:   page.PageSize,
+	}, "파일 목록을 조회했습니다")
+}
+
+// GetFile 파일 하나를 조회합니다 (소프트 삭제된 파일 포함)
+//
+// GET /api/v1/admin/files/:id
+func (h *AdminHandler) GetFile(c echo.Context) error {
+	id, err := parseFileID(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 파일 ID입니다", err.Error())
+	}
+
+	file, err := h.adminService.GetFile(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrAdminFileNotFound) {
+			return response.NotFound(c, err.Error(), "")
+		}
+		return response.InternalError(c, "파일 조회에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, withLinks(c, h.links, file), "파일을 조회했습니다")
+}
+
+// VerifyFile 파일의 무결성을 검사합니다
+//
+// POST /api/v1/admin/files/:id/verify (패스워드는 X-Encryption-Password 헤더)
+// 손상된 파일도 200으로 응답하며 결과의 valid 필드로 구분합니다.
+func (h *AdminHandler) VerifyFile(c echo.Context) error {
+	id, err := parseFileID(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 파일 ID입니다", err.Error())
+	}
+
+	password := c.Request().Header.Get(HeaderEncryptionPassword)
+	if password == "" {
+		return response.BadRequest(c, "암호화 패스워드가 필요합니다", "")
+	}
+
+	result, err := h.adminService.VerifyFile(c.Request().Context(), id, password)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAdminFileNotFound):
+			return response.NotFound(c, err.Error(), "")
+		case errors.Is(err, service.ErrIntegrityPasswordMismatch):
+			return response.BadRequest(c, "패스워드가 일치하지 않습니다", err.Error())
+		case errors.Is(err, service.ErrVolumeOffline):
+			return response.ServiceUnavailable(c, "파일이 저장된 볼륨을 사용할 수 없습니다", err.Error())
+		default:
+			return response.InternalError(c, "무결성 검사에 실패했습니다", err.Error())
+		}
+	}
+
+	return response.Success(c, result, "무결성 검사가 완료되었습니다")
+}
+
+// UpdateFile 파일 설명과 사용자 메타데이터를 수정합니다
+//
+// PATCH /api/v1/admin/files/:id
+// 본문은 {"description": "...", "custom_metadata": {...}}이며 보내지 않은 필드는
+// 그대로 둡니다. custom_metadata는 통째로 바뀌고 null이면 지워집니다.
+func (h *AdminHandler) UpdateFile(c echo.Context) error {
+	id, err := parseFileID(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 파일 ID입니다", err.Error())
+	}
+
+	var update service.FileDetailsUpdate
+	if err := c.Bind(&update); err != nil {
+		return response.BadRequest(c, "잘못된 요청 형식입니다", err.Error())
+	}
+
+	file, err := h.adminService.UpdateFileDetails(c.Request().Context(), id, update)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAdminFileNotFound):
+			return response.NotFound(c, err.Error(), "")
+		case errors.Is(err, service.ErrInvalidFileDetails):
+			return response.BadRequest(c, "파일 설명이나 메타데이터가 올바르지 않습니다", err.Error())
+		case errors.Is(err, model.ErrStaleRecord):
+			return response.Conflict(c, "다른 요청이 먼저 파일을 수정했습니다. 다시 시도해 주세요", err.Error())
+		default:
+			return response.InternalError(c, "파일 정보 수정에 실패했습니다", err.Error())
+		}
+	}
+
+	return response.Success(c, withLinks(c, h.links, file), "파일 정보를 수정했습니다")
+}
+
+// DeleteFile 파일을 소프트 삭제합니다 (암호화 메타데이터와 암호화본은 보존)
+//
+// POST /api/v1/admin/files/:id/trash
+func (h *AdminHandler) DeleteFile(c echo.Context) error {
+	id, err := parseFileID(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 파일 ID입니다", err.Error())
+	}
+
+	if err := h.adminService.DeleteFile(c.Request().Context(), id); err != nil {
+		if errors.Is(err, service.ErrAdminFileNotFound) {
+			return response.NotFound(c, err.Error(), "")
+		}
+		return response.InternalError(c, "파일 삭제에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, nil, "파일이 삭제되었습니다")
+}
+
+// RestoreFile 소프트 삭제된 파일을 복구합니다
+//
+// POST /api/v1/admin/files/:id/restore
+// 메타데이터가 없어 손상으로 표시된 경우에도 200으로 응답하며 결과의 status로 구분합니다.
+func (h *AdminHandler) RestoreFile(c echo.Context) error {
+	id, err := parseFileID(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 파일 ID입니다", err.Error())
+	}
+
+	file, err := h.adminService.RestoreFile(c.Request().Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAdminFileNotFound):
+			return response.NotFound(c, err.Error(), "")
+		case errors.Is(err, service.ErrFileNotDeleted):
+			return response.Conflict(c, "삭제되지 않은 파일입니다", err.Error())
+		case errors.Is(err, repository.ErrRestoreConflict):
+			return response.Conflict(c, "같은 암호화 경로를 사용하는 파일이 있습니다", err.Error())
+		case errors.Is(err, repository.ErrDuplicate):
+			return response.Conflict(c, "같은 외부 참조 ID를 사용하는 파일이 있습니다", err.Error())
+		case errors.Is(err, model.ErrStaleRecord):
+			return response.Conflict(c, "다른 요청이 먼저 파일을 수정했습니다. 다시 시도해 주세요", err.Error())
+		default:
+			return response.InternalError(c, "파일 복구에 실패했습니다", err.Error())
+		}
+	}
+
+	return response.Success(c, withLinks(c, h.links, file), "파일이 복구되었습니다")
+}
+
+// CheckMetadata 암호화 메타데이터가 없는 파일을 복원하거나 손상으로 표시합니다
+//
+// POST /api/v1/admin/files/check-metadata
+func (h *AdminHandler) CheckMetadata(c echo.Context) error {
+	result, err := h.adminService.CheckMetadata(c.Request().Context())
+	if err != nil {
+		if errors.Is(err, model.ErrStaleRecord) {
+			return response.Conflict(c, "점검 중 다른 요청이 파일을 수정했습니다. 다시 시도해 주세요", err.Error())
+		}
+		return response.InternalError(c, "메타데이터 점검에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, result, "메타데이터 점검이 완료되었습니다")
+}
+
+// PurgeFile 파일을 영구 삭제합니다
+//
+// DELETE /api/v1/admin/files/:id
+func (h *AdminHandler) PurgeFile(c echo.Context) error {
+	id, err := parseFileID(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 파일 ID입니다", err.Error())
+	}
+
+	if err := h.adminService.PurgeFile(c.Request().Context(), id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrAdminFileNotFound):
+			return response.NotFound(c, err.Error(), "")
+		case errors.Is(err, service.ErrFileInUse):
+			return response.Conflict(c, "다른 파일이 참조 중이라 삭제할 수 없습니다", err.Error())
+		case errors.Is(err, service.ErrFileBusy):
+			return response.Conflict(c, "다른 작업이 이 파일을 처리 중입니다. 잠시 후 다시 시도해 주세요", err.Error())
+		case errors.Is(err, service.ErrVolumeOffline):
+			return response.ServiceUnavailable(c, "파일이 저장된 볼륨을 사용할 수 없습니다", err.Error())
+		default:
+			return response.InternalError(c, "파일 영구 삭제에 실패했습니다", err.Error())
+		}
+	}
+
+	return response.Success(c, nil, "파일이 영구 삭제되었습니다")
+}
+
+// VolumeStats 저장소 볼륨별 사용량을 조회합니다
+//
+// GET /api/v1/admin/volumes
+func (h *AdminHandler) VolumeStats(c echo.Context) error {
+	stats, err := h.adminService.VolumeStats(c.Request().Context())
+	if err != nil {
+		return response.InternalError(c, "볼륨 사용량 조회에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, stats, "볼륨 사용량을 조회했습니다")
+}
+
+// RebalanceVolumes 볼륨 간 파일을 재배치합니다
+//
+// POST /api/v1/admin/volumes/rebalance?dry_run=true&max_files=
+// 일부 파일을 옮기지 못해도 200으로 응답하며 결과의 failed 필드에 사유를 담습니다.
+func (h *AdminHandler) RebalanceVolumes(c echo.Context) error {
+	dryRun, err := parseOptionalBool(c.QueryParam("dry_run"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 dry_run 값입니다", err.Error())
+	}
+
+	maxFiles, err := parseOptionalInt(c.QueryParam("max_files"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 최대 파일 수입니다", err.Error())
+	}
+
+	result, err := h.adminService.RebalanceVolumes(c.Request().Context(), dryRun, maxFiles)
+	if err != nil {
+		return response.InternalError(c, "볼륨 재배치에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, result, "볼륨 재배치가 완료되었습니다")
+}
+
+// fileFilterParams 파일 목록 필터 쿼리 파라미터
+var fileFilterParams = []string{"status", "mime", "name", "external_id", "meta_key", "meta_value", "min_size", "max_size", httputil.ParamFrom, httputil.ParamTo}
+
+// parseFileFilter 쿼리 파라미터를 파일 목록 필터로 변환합니다 (값의 모순은 저장소에서 검증)
+func parseFileFilter(c echo.Context) (repository.FileFilter, error) {
+	filter := repository.FileFilter{
+		Status:        c.QueryParam("status"),
+		MimePrefix:    c.QueryParam("mime"),
+		NameContains:  c.QueryParam("name"),
+		ExternalID:    c.QueryParam("external_id"),
+		MetadataKey:   c.QueryParam("meta_key"),
+		MetadataValue: c.QueryParam("meta_value"),
+	}
+
+	var err error
+	if filter.MinSize, err = parseOptionalSize(c.QueryParam("min_size")); err != nil {
+		return repository.FileFilter{}, fmt.Errorf("min_size: %w", err)
+	}
+	if filter.MaxSize, err = parseOptionalSize(c.QueryParam("max_size")); err != nil {
+		return repository.FileFilter{}, fmt.Errorf("max_size: %w", err)
+	}
+
+	// 관리 목록은 전체 기간을 훑을 수 있도록 기간 길이를 제한하지 않음
+	if filter.CreatedAfter, filter.CreatedBefore, err = (httputil.TimeRangeParser{}).Parse(c); err != nil {
+		return repository.FileFilter{}, err
+	}
+
+	return filter, nil
+}
+
+// parseOptionalSize 비어 있으면 0을, 아니면 바이트 크기를 반환합니다
+func parseOptionalSize(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("0 이상의 정수가 아닙니다: %s", value)
+	}
+	return size, nil
+}
+
+// parseFileID 경로의 파일 ID를 파싱합니다
+func parseFileID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		return 0, errors.New("파일 ID는 양의 정수여야 합니다")
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAdminService 고정된 결과를 반환하는 관리 서비스
+type stubAdminService struct {
+	list      *service.AdminFileList
+	page      *service.AdminFileCursorPage
+	result    *service.IntegrityResult
+	rebalance *service.RebalanceResult
+	err       error
+
+	dryRun   bool // RebalanceVolumes에 전달된 값
+	maxFiles int
+	sort     repository.SortOption // ListFiles에 전달된 값
+	filter   repository.FileFilter
+	cursor   string                    // ListFilesAfter에 전달된 값
+	details  service.FileDetailsUpdate // UpdateFileDetails에 전달된 값
+}
+
+func (s *stubAdminService) ListFiles(_ context.Context, filter repository.FileFilter, _, _ int, sort repository.SortOption) (*service.AdminFileList, error) {
+	s.sort, s.filter = sort, filter
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.list, nil
+}
+
+func (s *stubAdminService) ListFilesAfter(_ context.Context, cursor string, _ int) (*service.AdminFileCursorPage, error) {
+	s.cursor = cursor
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.page, nil
+}
+
+func (s *stubAdminService) GetFile(_ context.Context, fileID uint) (*model.File, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.File{ID: fileID, Status: model.FileStatusEncrypted}, nil
+}
+
+func (s *stubAdminService) VerifyFile(_ context.Context, _ uint, _ string) (*service.IntegrityResult, error) {
+	return s.result, s.err
+}
+
+func (s *stubAdminService) DeleteFile(_ context.Context, _ uint) error {
+	return s.err
+}
+
+func (s *stubAdminService) RestoreFile(_ context.Context, fileID uint) (*model.File, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.File{ID: fileID, Status: model.FileStatusEncrypted}, nil
+}
+
+func (s *stubAdminService) UpdateFileDetails(_ context.Context, fileID uint, update service.FileDetailsUpdate) (*model.File, error) {
+	s.details = update
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.File{ID: fileID, CustomMetadata: update.CustomMetadata}, nil
+}
+
+func (s *stubAdminService) PurgeFile(_ context.Context, _ uint) error {
+	return s.err
+}
+
+func (s *stubAdminService) CheckMetadata(_ context.Context) (*service.MetadataCheckResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &service.MetadataCheckResult{Rebuilt: []uint{5}, Corrupted: []uint{3, 8}, Skipped: []uint{}}, nil
+}
+
+func (s *stubAdminService) VolumeStats(_ context.Context) ([]service.VolumeStats, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []service.VolumeStats{{ID: "default", Online: true}}, nil
+}
+
+func (s *stubAdminService) RebalanceVolumes(_ context.Context, dryRun bool, maxFiles int) (*service.RebalanceResult, error) {
+	s.dryRun, s.maxFiles = dryRun, maxFiles
+	return s.rebalance, s.err
+}
+
+func TestAdminHandler_ListFiles(t *testing.T) {
+	h := NewAdminHandler(&stubAdminService{list: &service.AdminFileList{Page: 1, PageSize: 10}})
+
+	c, rec := createTestContext(http.MethodGet, "/api/v1/admin/files?page=1")
+	require.NoError(t, h.ListFiles(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/files?page=abc")
+	require.NoError(t, h.ListFiles(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	stub := &stubAdminService{list: &service.AdminFileList{}}
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/files?sort=name&order=asc")
+	require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, repository.SortOption{Field: repository.SortFieldName, Direction: repository.SortAsc}, stub.sort)
+
+	stub = &stubAdminService{err: fmt.Errorf("파일 목록 조회 실패: %w", repository.ErrInvalidSortOption)}
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/files?sort=password")
+	require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAdminHandler_ListFilesFilter(t *testing.T) {
+	stub := &stubAdminService{list: &service.AdminFileList{}}
+	c, rec := createTestContext(http.MethodGet,
+		"/api/v1/admin/files?status=failed&mime=image/&name=report&min_size=10&max_size=2048&from=2024-03-01&to=2024-03-31")
+	require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, repository.FileFilter{
+		Status:        model.FileStatusFailed,
+		MimePrefix:    "image/",
+		NameContains:  "report",
+		MinSize:       10,
+		MaxSize:       2048,
+		CreatedAfter:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		CreatedBefore: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	}, stub.filter, "날짜만 지정한 to는 그 날까지 포함")
+
+	for _, query := range []string{"min_size=-1", "max_size=abc", "from=yesterday"} {
+		c, rec = createTestContext(http.MethodGet, "/api/v1/admin/files?"+query)
+		require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	// 모순된 필터는 저장소가 거부
+	stub = &stubAdminService{err: fmt.Errorf("파일 목록 조회 실패: %w", repository.ErrInvalidFileFilter)}
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/files?min_size=10&max_size=5")
+	require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// 사용자 메타데이터 검색
+	stub = &stubAdminService{list: &service.AdminFileList{}}
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/files?meta_key=client.name&meta_value=acme")
+	require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, repository.FileFilter{MetadataKey: "client.name", MetadataValue: "acme"}, stub.filter)
+
+	// 커서 페이지네이션과는 함께 쓸 수 없음
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/files?cursor=&status=failed")
+	require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAdminHandler_ListFilesCursor(t *testing.T) {
+	stub := &stubAdminService{page: &service.AdminFileCursorPage{
+		Files:      []*model.File{{ID: 3, Status: model.FileStatusEncrypted}},
+		NextCursor: "next",
+		PageSize:   1,
+	}}
+
+	// 빈 cursor도 커서 페이지네이션의 첫 페이지
+	c, rec := createTestContext(http.MethodGet, "/api/v1/admin/files?cursor=&page_size=1")
+	require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"next_cursor":"next"`)
+	assert.Empty(t, stub.cursor)
+
+	c, _ = createTestContext(http.MethodGet, "/api/v1/admin/files?cursor=abc")
+	require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+	assert.Equal(t, "abc", stub.cursor)
+
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/files?cursor=abc&sort=name")
+	require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	stub = &stubAdminService{err: fmt.Errorf("%w: %q", repository.ErrInvalidCursor, "!!")}
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/files?cursor=!!")
+	require.NoError(t, NewAdminHandler(stub).ListFiles(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAdminHandler_VerifyFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		id       string
+		password string
+		stub     *stubAdminService
+		wantCode int
+	}{
+		{
+			name:     "정상",
+			id:       "1",
+			password: "secret",
+			stub:     &stubAdminService{result: &service.IntegrityResult{FileID: 1, Valid: true}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "손상된 파일도 200",
+			id:       "1",
+			password: "secret",
+			stub:     &stubAdminService{result: &service.IntegrityResult{FileID: 1, Reason: "tampered"}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "잘못된 ID",
+			id:       "abc",
+			password: "secret",
+			stub:     &stubAdminService{},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "패스워드 없음",
+			id:       "1",
+			stub:     &stubAdminService{},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "파일 없음",
+			id:       "1",
+			password: "secret",
+			stub:     &stubAdminService{err: fmt.Errorf("%w: ID 1", service.ErrAdminFileNotFound)},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "패스워드 불일치",
+			id:       "1",
+			password: "wrong",
+			stub:     &stubAdminService{err: service.ErrIntegrityPasswordMismatch},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := createTestContext(http.MethodPost, "/api/v1/admin/files/"+tc.id+"/verify")
+			c.SetParamNames("id")
+			c.SetParamValues(tc.id)
+			if tc.password != "" {
+				c.Request().Header.Set(HeaderEncryptionPassword, tc.password)
+			}
+
+			require.NoError(t, NewAdminHandler(tc.stub).VerifyFile(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestAdminHandler_PurgeFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{name: "정상", wantCode: http.StatusOK},
+		{name: "파일 없음", err: service.ErrAdminFileNotFound, wantCode: http.StatusNotFound},
+		{name: "참조 중인 blob", err: service.ErrFileInUse, wantCode: http.StatusConflict},
+		{name: "볼륨 오프라인", err: fmt.Errorf("%w: 볼륨 hot", service.ErrVolumeOffline), wantCode: http.StatusServiceUnavailable},
+		{name: "내부 오류", err: fmt.Errorf("disk error"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := createTestContext(http.MethodDelete, "/api/v1/admin/files/1")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			require.NoError(t, NewAdminHandler(&stubAdminService{err: tc.err}).PurgeFile(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestAdminHandler_UpdateFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+	}{
+		{"수정", `{"description":"분기 보고서","custom_metadata":{"team":"finance"}}`, nil, http.StatusOK},
+		{"잘못된 본문", `{"description":`, nil, http.StatusBadRequest},
+		{"검증 실패", `{"custom_metadata":[1]}`, service.ErrInvalidFileDetails, http.StatusBadRequest},
+		{"파일 없음", `{"description":"x"}`, service.ErrAdminFileNotFound, http.StatusNotFound},
+		{"동시 수정", `{"description":"x"}`, model.ErrStaleRecord, http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := createJSONContext(http.MethodPatch, "/api/v1/admin/files/1", tc.body)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			require.NoError(t, NewAdminHandler(&stubAdminService{err: tc.err}).UpdateFile(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+
+	stub := &stubAdminService{}
+	c, _ := createJSONContext(http.MethodPatch, "/api/v1/admin/files/1", `{"description":"메모","custom_metadata":null}`)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	require.NoError(t, NewAdminHandler(stub).UpdateFile(c))
+	require.NotNil(t, stub.details.Description)
+	assert.Equal(t, "메모", *stub.details.Description)
+	assert.Equal(t, "null", string(stub.details.CustomMetadata), "null은 메타데이터 삭제로 전달")
+}
+
+func TestAdminHandler_DeleteAndRestoreFile(t *testing.T) {
+	testCases := []struct {
+		name        string
+		err         error
+		wantDelete  int
+		wantRestore int
+	}{
+		{name: "정상", wantDelete: http.StatusOK, wantRestore: http.StatusOK},
+		{name: "파일 없음", err: service.ErrAdminFileNotFound, wantDelete: http.StatusNotFound, wantRestore: http.StatusNotFound},
+		{name: "삭제되지 않은 파일", err: service.ErrFileNotDeleted, wantDelete: http.StatusInternalServerError, wantRestore: http.StatusConflict},
+		{name: "암호화 경로 충돌", err: fmt.Errorf("파일 복구 실패: %w", repository.ErrRestoreConflict), wantDelete: http.StatusInternalServerError, wantRestore: http.StatusConflict},
+		{name: "동시 수정", err: fmt.Errorf("파일 복구 실패: %w", model.ErrStaleRecord), wantDelete: http.StatusInternalServerError, wantRestore: http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewAdminHandler(&stubAdminService{err: tc.err})
+
+			c, rec := createTestContext(http.MethodPost, "/api/v1/admin/files/1/trash")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			require.NoError(t, h.DeleteFile(c))
+			assert.Equal(t, tc.wantDelete, rec.Code)
+
+			c, rec = createTestContext(http.MethodPost, "/api/v1/admin/files/1/restore")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			require.NoError(t, h.RestoreFile(c))
+			assert.Equal(t, tc.wantRestore, rec.Code)
+		})
+	}
+}
+
+func TestAdminHandler_CheckMetadata(t *testing.T) {
+	c, rec := createTestContext(http.MethodPost, "/api/v1/admin/files/check-metadata")
+	require.NoError(t, NewAdminHandler(&stubAdminService{}).CheckMetadata(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"rebuilt_file_ids":[5]`)
+	assert.Contains(t, rec.Body.String(), `"corrupted_file_ids":[3,8]`)
+
+	c, rec = createTestContext(http.MethodPost, "/api/v1/admin/files/check-metadata")
+	require.NoError(t, NewAdminHandler(&stubAdminService{err: fmt.Errorf("db error")}).CheckMetadata(c))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	c, rec = createTestContext(http.MethodPost, "/api/v1/admin/files/check-metadata")
+	require.NoError(t, NewAdminHandler(&stubAdminService{err: fmt.Errorf("점검 실패: %w", model.ErrStaleRecord)}).CheckMetadata(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestAdminHandler_VolumeStats(t *testing.T) {
+	c, rec := createTestContext(http.MethodGet, "/api/v1/admin/volumes")
+	require.NoError(t, NewAdminHandler(&stubAdminService{}).VolumeStats(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":"default"`)
+
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/volumes")
+	require.NoError(t, NewAdminHandler(&stubAdminService{err: fmt.Errorf("db error")}).VolumeStats(c))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestAdminHandler_RebalanceVolumes(t *testing.T) {
+	stub := &stubAdminService{rebalance: &service.RebalanceResult{DryRun: true}}
+
+	c, rec := createTestContext(http.MethodPost, "/api/v1/admin/volumes/rebalance?dry_run=true&max_files=5")
+	require.NoError(t, NewAdminHandler(stub).RebalanceVolumes(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, stub.dryRun)
+	assert.Equal(t, 5, stub.maxFiles)
+
+	c, rec = createTestContext(http.MethodPost, "/api/v1/admin/volumes/rebalance?dry_run=maybe")
+	require.NoError(t, NewAdminHandler(stub).RebalanceVolumes(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
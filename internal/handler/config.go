@@ -0,0 +1,49 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the admin endpoint that reloads live settings.
+package handler
+
+import (
+	"errors"
+
+	"DataLocker/internal/config"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ConfigReloader 설정을 다시 읽어 적용하는 기능 (config.ReloadableConfig가 구현)
+type ConfigReloader interface {
+	Reload(source string) (*config.ReloadResult, error)
+}
+
+// ConfigHandler 설정 리로드 핸들러
+type ConfigHandler struct {
+	reloader ConfigReloader
+}
+
+// NewConfigHandler 새로운 설정 리로드 핸들러를 생성합니다
+func NewConfigHandler(reloader ConfigReloader) *ConfigHandler {
+	return &ConfigHandler{
+		reloader: reloader,
+	}
+}
+
+// Reload 설정 파일을 다시 읽어 교체 가능한 항목을 적용합니다
+//
+// POST /api/v1/admin/config/reload
+// 재시작이 필요한 항목의 변경은 적용되지 않고 결과의 ignored 필드에 표시됩니다.
+func (h *ConfigHandler) Reload(c echo.Context) error {
+	result, err := h.reloader.Reload(config.ReloadSourceAPI)
+	if err != nil {
+		switch {
+		case errors.Is(err, config.ErrConfigFileNotSet):
+			return response.Conflict(c, "설정 파일 없이 시작되어 리로드할 수 없습니다", err.Error())
+		case errors.Is(err, config.ErrInvalidReloadValue):
+			return response.BadRequest(c, "설정 값이 올바르지 않습니다", err.Error())
+		default:
+			return response.InternalError(c, "설정 리로드에 실패했습니다", err.Error())
+		}
+	}
+
+	return response.Success(c, result, "설정을 리로드했습니다")
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"DataLocker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubConfigReloader 고정된 결과를 반환하는 설정 리로더
+type stubConfigReloader struct {
+	result *config.ReloadResult
+	err    error
+	source string
+}
+
+func (s *stubConfigReloader) Reload(source string) (*config.ReloadResult, error) {
+	s.source = source
+	return s.result, s.err
+}
+
+func TestConfigHandler_Reload(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stub     *stubConfigReloader
+		wantCode int
+	}{
+		{
+			name:     "정상",
+			stub:     &stubConfigReloader{result: &config.ReloadResult{Changed: []string{"app.log_level"}}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "설정 파일 없음",
+			stub:     &stubConfigReloader{err: config.ErrConfigFileNotSet},
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "잘못된 값",
+			stub:     &stubConfigReloader{err: fmt.Errorf("%w: log_level", config.ErrInvalidReloadValue)},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "읽기 실패",
+			stub:     &stubConfigReloader{err: errors.New("설정 파일 읽기 실패")},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewConfigHandler(tc.stub)
+			c, rec := createTestContext(http.MethodPost, "/api/v1/admin/config/reload")
+
+			require.NoError(t, h.Reload(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+			assert.Equal(t, config.ReloadSourceAPI, tc.stub.source)
+		})
+	}
+}
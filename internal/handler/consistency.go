@@ -0,0 +1,44 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the record/disk consistency check endpoints.
+package handler
+
+import (
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ConsistencyHandler 레코드와 디스크 정합성 점검 핸들러
+type ConsistencyHandler struct {
+	consistencyService service.ConsistencyService
+}
+
+// NewConsistencyHandler 새로운 정합성 점검 핸들러를 생성합니다
+func NewConsistencyHandler(consistencyService service.ConsistencyService) *ConsistencyHandler {
+	return &ConsistencyHandler{
+		consistencyService: consistencyService,
+	}
+}
+
+// ReconcileSizes 레코드의 암호화본 크기와 디스크 크기가 다른 파일을 보고합니다
+//
+// POST /api/v1/admin/files/reconcile-sizes?fix=true
+// fix이면 디스크 기준으로 EncryptedSize를 교정합니다. 일부 파일을 확인하지 못해도
+// 200으로 응답하며 결과의 skipped 필드에 파일 ID를 담습니다.
+func (h *ConsistencyHandler) ReconcileSizes(c echo.Context) error {
+	fix, err := parseOptionalBool(c.QueryParam("fix"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 fix 값입니다", err.Error())
+	}
+
+	result, err := h.consistencyService.ReconcileSizes(c.Request().Context(), fix)
+	if err != nil {
+		return response.InternalError(c, "암호화본 크기 점검에 실패했습니다", err.Error())
+	}
+
+	if fix {
+		return response.Success(c, result, "암호화본 크기를 점검하고 교정했습니다")
+	}
+	return response.Success(c, result, "암호화본 크기를 점검했습니다")
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"DataLocker/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubConsistencyService 요청한 교정 여부를 기록하고 고정 결과를 반환하는 정합성 점검 서비스
+type stubConsistencyService struct {
+	fix *bool
+	err error
+}
+
+func (s *stubConsistencyService) ReconcileSizes(_ context.Context, fix bool) (*service.SizeReconcileResult, error) {
+	s.fix = &fix
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &service.SizeReconcileResult{
+		Fix:        fix,
+		Checked:    2,
+		Mismatches: []service.SizeMismatch{{FileID: 7, RecordedSize: 100, DiskSize: 120, Fixed: fix}},
+		Missing:    []uint{9},
+		Skipped:    []uint{},
+	}, nil
+}
+
+func TestConsistencyHandler_ReconcileSizes(t *testing.T) {
+	svc := &stubConsistencyService{}
+	h := NewConsistencyHandler(svc)
+
+	c, rec := createTestContext(http.MethodPost, "/api/v1/admin/files/reconcile-sizes")
+	require.NoError(t, h.ReconcileSizes(c))
+	body := assertSuccessResponse(t, rec)
+	require.NotNil(t, svc.fix)
+	assert.False(t, *svc.fix, "생략하면 보고만 함")
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["checked"])
+	assert.Equal(t, []interface{}{float64(9)}, data["missing"])
+
+	c, rec = createTestContext(http.MethodPost, "/api/v1/admin/files/reconcile-sizes?fix=true")
+	require.NoError(t, h.ReconcileSizes(c))
+	body = assertSuccessResponse(t, rec)
+	assert.True(t, *svc.fix)
+	mismatch := body["data"].(map[string]interface{})["mismatches"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, float64(120), mismatch["disk_size"])
+	assert.Equal(t, true, mismatch["fixed"])
+
+	c, rec = createTestContext(http.MethodPost, "/api/v1/admin/files/reconcile-sizes?fix=maybe")
+	require.NoError(t, h.ReconcileSizes(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	svc.err = errors.New("db down")
+	c, rec = createTestContext(http.MethodPost, "/api/v1/admin/files/reconcile-sizes")
+	require.NoError(t, h.ReconcileSizes(c))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,57 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the two-step factory reset endpoint.
+package handler
+
+import (
+	"errors"
+
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FactoryResetHandler 공장 초기화 핸들러
+type FactoryResetHandler struct {
+	resetService service.FactoryResetService
+}
+
+// NewFactoryResetHandler 새로운 공장 초기화 핸들러를 생성합니다
+func NewFactoryResetHandler(resetService service.FactoryResetService) *FactoryResetHandler {
+	return &FactoryResetHandler{
+		resetService: resetService,
+	}
+}
+
+// FactoryReset 확인 토큰을 발급하거나, 토큰을 받으면 모든 데이터를 지웁니다
+//
+// POST /api/v1/admin/factory-reset
+// 1단계: 본문 없이 (또는 {}) 보내면 confirmation_token과 만료 시각을 반환합니다.
+// 2단계: {"confirmation_token": "...", "secure_delete": true}로 다시 보내면 DB 초기화,
+// 암호화 파일 삭제, 캐시 비우기를 차례로 실행하고 지운 파일 수와 바이트를 반환합니다.
+// 토큰이 틀리거나 만료되었으면 400이며, 1단계부터 다시 해야 합니다.
+func (h *FactoryResetHandler) FactoryReset(c echo.Context) error {
+	var req service.FactoryResetRequest
+	if err := c.Bind(&req); err != nil {
+		return response.BadRequest(c, "잘못된 요청 형식입니다", err.Error())
+	}
+
+	ctx := c.Request().Context()
+	if req.ConfirmationToken == "" {
+		token, err := h.resetService.IssueToken(ctx)
+		if err != nil {
+			return response.InternalError(c, "확인 토큰 발급에 실패했습니다", err.Error())
+		}
+		return response.Success(c, token, "확인 토큰을 발급했습니다. 만료 전에 confirmation_token을 담아 다시 요청하면 모든 데이터를 지웁니다")
+	}
+
+	result, err := h.resetService.Reset(ctx, req.ConfirmationToken, req.SecureDelete)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidResetToken) {
+			return response.BadRequest(c, "공장 초기화를 확인할 수 없습니다", err.Error())
+		}
+		return response.InternalError(c, "공장 초기화에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, result, "공장 초기화를 마쳤습니다")
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"DataLocker/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFactoryResetService 받은 토큰과 옵션을 기록하는 공장 초기화 서비스
+type stubFactoryResetService struct {
+	issued       int
+	token        string
+	secureDelete bool
+	err          error
+}
+
+func (s *stubFactoryResetService) IssueToken(context.Context) (*service.FactoryResetToken, error) {
+	s.issued++
+	return &service.FactoryResetToken{Token: "confirm-me", ExpiresAt: time.Date(2025, 3, 1, 9, 2, 0, 0, time.UTC)}, nil
+}
+
+func (s *stubFactoryResetService) Reset(_ context.Context, token string, secureDelete bool) (*service.FactoryResetResult, error) {
+	s.token, s.secureDelete = token, secureDelete
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &service.FactoryResetResult{
+		SecureDelete:  secureDelete,
+		DeletedFiles:  2,
+		DeletedBytes:  120,
+		ClearedCaches: map[string]int{"activity_feed": 1},
+	}, nil
+}
+
+func TestFactoryResetHandler_FactoryReset(t *testing.T) {
+	svc := &stubFactoryResetService{}
+	h := NewFactoryResetHandler(svc)
+
+	// 1단계: 토큰 발급만
+	c, rec := createTestContext(http.MethodPost, "/api/v1/admin/factory-reset")
+	require.NoError(t, h.FactoryReset(c))
+	body := assertSuccessResponse(t, rec)
+	assert.Equal(t, 1, svc.issued)
+	assert.Empty(t, svc.token, "토큰 없이는 실행하지 않음")
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, "confirm-me", data["confirmation_token"])
+	assert.Equal(t, "2025-03-01T09:02:00Z", data["expires_at"])
+
+	// 2단계: 토큰과 옵션을 넘겨 실행
+	c, rec = createJSONContext(http.MethodPost, "/api/v1/admin/factory-reset",
+		`{"confirmation_token":"confirm-me","secure_delete":true}`)
+	require.NoError(t, h.FactoryReset(c))
+	body = assertSuccessResponse(t, rec)
+	assert.Equal(t, "confirm-me", svc.token)
+	assert.True(t, svc.secureDelete)
+	data = body["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["deleted_files"])
+	assert.Equal(t, float64(120), data["deleted_bytes"])
+	assert.Equal(t, true, data["secure_delete"])
+
+	svc.err = service.ErrInvalidResetToken
+	c, rec = createJSONContext(http.MethodPost, "/api/v1/admin/factory-reset", `{"confirmation_token":"stale"}`)
+	require.NoError(t, h.FactoryReset(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	svc.err = fmt.Errorf("DB 초기화 실패: %w", errors.New("database is locked"))
+	c, rec = createJSONContext(http.MethodPost, "/api/v1/admin/factory-reset", `{"confirmation_token":"confirm-me"}`)
+	require.NoError(t, h.FactoryReset(c))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	c, rec = createJSONContext(http.MethodPost, "/api/v1/admin/factory-reset", `{"secure_delete":"yes"}`)
+	require.NoError(t, h.FactoryReset(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, 1, svc.issued)
+}
@@ -0,0 +1,73 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements lookup and reissue of short file codes.
+package handler
+
+import (
+	"errors"
+
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FileCodeHandler 짧은 파일 코드 핸들러
+type FileCodeHandler struct {
+	fileCodeService service.FileCodeService
+	links           *LinkBuilder
+}
+
+// NewFileCodeHandler 새로운 짧은 파일 코드 핸들러를 생성합니다
+func NewFileCodeHandler(fileCodeService service.FileCodeService) *FileCodeHandler {
+	return &FileCodeHandler{
+		fileCodeService: fileCodeService,
+	}
+}
+
+// SetLinkBuilder 파일 응답에 링크를 덧붙일 빌더를 설정합니다 (nil이면 링크 생략)
+func (h *FileCodeHandler) SetLinkBuilder(links *LinkBuilder) {
+	h.links = links
+}
+
+// Lookup 짧은 코드로 파일을 조회합니다
+//
+// GET /api/v1/files/code/:code
+// 대소문자와 구분용 하이픈은 무시하고 I/L은 1, O는 0으로 읽습니다. 코드 추측을
+// 막기 위해 라우트에서 클라이언트 IP별 조회 수를 제한합니다.
+func (h *FileCodeHandler) Lookup(c echo.Context) error {
+	file, err := h.fileCodeService.Lookup(c.Request().Context(), c.Param("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidFileCode):
+			return response.BadRequest(c, "잘못된 파일 코드입니다", err.Error())
+		case errors.Is(err, service.ErrFileCodeNotFound):
+			return response.NotFound(c, err.Error(), "")
+		default:
+			return response.InternalError(c, "파일 코드 조회에 실패했습니다", err.Error())
+		}
+	}
+
+	return response.Success(c, withLinks(c, h.links, file), "파일을 조회했습니다")
+}
+
+// Reissue 파일에 새 짧은 코드를 부여합니다
+//
+// POST /api/v1/admin/files/:id/code
+// 이전 코드는 바로 조회되지 않으므로 잘못 전달된 코드를 무효화할 때 사용합니다.
+func (h *FileCodeHandler) Reissue(c echo.Context) error {
+	id, err := parseFileID(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 파일 ID입니다", err.Error())
+	}
+
+	result, err := h.fileCodeService.Reissue(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return response.NotFound(c, err.Error(), "")
+		}
+		return response.InternalError(c, "파일 코드 재발급에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, result, "파일 코드를 재발급했습니다")
+}
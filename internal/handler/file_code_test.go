@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFileCodeService 고정된 결과를 반환하는 짧은 파일 코드 서비스
+type stubFileCodeService struct {
+	file     *model.File
+	reissued *service.ReissuedFileCode
+	err      error
+	code     string
+}
+
+func (s *stubFileCodeService) Lookup(_ context.Context, code string) (*model.File, error) {
+	s.code = code
+	return s.file, s.err
+}
+
+func (s *stubFileCodeService) Reissue(_ context.Context, fileID uint) (*service.ReissuedFileCode, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &service.ReissuedFileCode{FileID: fileID, ShortCode: s.reissued.ShortCode}, nil
+}
+
+func TestFileCodeHandler_Lookup(t *testing.T) {
+	stub := &stubFileCodeService{file: &model.File{ID: 7, OriginalName: "a.txt", ShortCode: "7K3M9QXZ"}}
+	b, err := NewLinkBuilder("", nil)
+	require.NoError(t, err)
+
+	h := NewFileCodeHandler(stub)
+	h.SetLinkBuilder(b)
+
+	c, rec := createTestContext(http.MethodGet, "/api/v1/files/code/7k3m-9qxz")
+	c.SetParamNames("code")
+	c.SetParamValues("7k3m-9qxz")
+	require.NoError(t, h.Lookup(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "7k3m-9qxz", stub.code, "정규화는 서비스가 담당")
+
+	var body struct {
+		Data struct {
+			ID        uint      `json:"id"`
+			ShortCode string    `json:"short_code"`
+			Links     FileLinks `json:"links"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, uint(7), body.Data.ID)
+	assert.Equal(t, "7K3M9QXZ", body.Data.ShortCode)
+	assert.Equal(t, "http://example.com/api/v1/files/code/7K3M9QXZ", body.Data.Links.Code)
+
+	testCases := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"잘못된 코드", fmt.Errorf("%w: 형식", service.ErrInvalidFileCode), http.StatusBadRequest},
+		{"없는 코드", service.ErrFileCodeNotFound, http.StatusNotFound},
+		{"조회 실패", fmt.Errorf("디스크 오류"), http.StatusInternalServerError},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := createTestContext(http.MethodGet, "/api/v1/files/code/AAAAAAAA")
+			c.SetParamNames("code")
+			c.SetParamValues("AAAAAAAA")
+			require.NoError(t, NewFileCodeHandler(&stubFileCodeService{err: tc.err}).Lookup(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestFileCodeHandler_Reissue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		id       string
+		err      error
+		wantCode int
+	}{
+		{name: "재발급", id: "7", wantCode: http.StatusOK},
+		{name: "잘못된 ID", id: "abc", wantCode: http.StatusBadRequest},
+		{name: "없는 파일", id: "7", err: fmt.Errorf("재발급 실패: %w", repository.ErrNotFound), wantCode: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stub := &stubFileCodeService{reissued: &service.ReissuedFileCode{ShortCode: "NEWC0DE1"}, err: tc.err}
+			c, rec := createTestContext(http.MethodPost, "/api/v1/admin/files/"+tc.id+"/code")
+			c.SetParamNames("id")
+			c.SetParamValues(tc.id)
+
+			require.NoError(t, NewFileCodeHandler(stub).Reissue(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+			if tc.wantCode == http.StatusOK {
+				assert.Contains(t, rec.Body.String(), `"short_code":"NEWC0DE1"`)
+				assert.Contains(t, rec.Body.String(), `"file_id":7`)
+			}
+		})
+	}
+}
@@ -7,25 +7,69 @@ import (
 	"time"
 
 	"DataLocker/internal/config"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+	"DataLocker/pkg/crypto"
 	"DataLocker/pkg/response"
 
 	"github.com/labstack/echo/v4"
 )
 
+// healthStatusDegraded 일부 의존 서비스가 healthy가 아닐 때의 전체 상태
+const healthStatusDegraded = "degraded"
+
+// WriteStatsProvider DB 쓰기 직렬화 메트릭 제공자 (repository.WriteSerializer)
+type WriteStatsProvider interface {
+	Stats() repository.WriteStats
+}
+
+// QueryStatsProvider 저장소 호출 시간 메트릭 제공자 (repository.QueryStats)
+type QueryStatsProvider interface {
+	Stats() []repository.QueryStat
+}
+
 // HealthHandler 헬스체크 핸들러
 type HealthHandler struct {
-	config    *config.Config
-	startTime time.Time
+	config     *config.Config
+	startTime  time.Time
+	writeStats WriteStatsProvider
+	queryStats QueryStatsProvider
+	checker    service.HealthService
+
+	// cryptoCheck 암호화 자체 점검 결과 (기본값: crypto.CachedSelfTest)
+	cryptoCheck func() error
 }
 
 // NewHealthHandler 새로운 헬스체크 핸들러를 생성합니다
 func NewHealthHandler(cfg *config.Config) *HealthHandler {
 	return &HealthHandler{
-		config:    cfg,
-		startTime: time.Now(),
+		config:      cfg,
+		startTime:   time.Now(),
+		cryptoCheck: crypto.CachedSelfTest,
 	}
 }
 
+// SetWriteStats 메트릭에 DB 쓰기 대기열 정보를 포함하도록 설정합니다
+//
+// 쓰기 직렬화를 사용하지 않으면 호출하지 않으며, 이때 메트릭에 db_writes 항목이 없습니다.
+func (h *HealthHandler) SetWriteStats(provider WriteStatsProvider) {
+	h.writeStats = provider
+}
+
+// SetQueryStats 메트릭에 저장소 메서드별 호출 시간을 포함하도록 설정합니다
+//
+// DB_QUERY_METRICS가 꺼져 있으면 호출하지 않으며, 이때 메트릭에 db_queries 항목이 없습니다.
+func (h *HealthHandler) SetQueryStats(provider QueryStatsProvider) {
+	h.queryStats = provider
+}
+
+// SetHealthService 헬스체크에 의존 서비스(DB, 저장소 볼륨 등) 점검 결과를 포함하도록 설정합니다
+//
+// 호출하지 않으면 services에 api 항목만 있습니다.
+func (h *HealthHandler) SetHealthService(checker service.HealthService) {
+	h.checker = checker
+}
+
 // HealthResponse 헬스체크 응답 구조체
 type HealthResponse struct {
 	Status    string                 `json:"status"`
@@ -48,16 +92,37 @@ type SystemInfo struct {
 
 // ServiceInfo 서비스 상태 정보 구조체
 type ServiceInfo struct {
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
+	Status         string     `json:"status"`
+	Message        string     `json:"message,omitempty"`
+	DurationMillis int64      `json:"duration_ms"`
+	LastSuccess    *time.Time `json:"last_success,omitempty"`
 }
 
 // Health 기본 헬스체크 엔드포인트
+//
+// 의존 서비스는 동시에 점검하고 각각 제한 시간까지만 기다리므로 느린 의존 서비스가
+// 있어도 응답이 멈추지 않습니다. 하나라도 healthy가 아니면 status를 degraded로
+// 보고하지만, 나머지 결과를 함께 전달하도록 200으로 응답합니다.
 func (h *HealthHandler) Health(c echo.Context) error {
 	uptime := time.Since(h.startTime)
 
+	status, message := service.HealthStatusHealthy, "서비스가 정상적으로 동작 중입니다"
+	services := map[string]ServiceInfo{
+		"api": {
+			Status: service.HealthStatusHealthy,
+		},
+	}
+	if h.checker != nil {
+		for name, result := range h.checker.Check(c.Request().Context()) {
+			services[name] = ServiceInfo(result)
+			if result.Status != service.HealthStatusHealthy {
+				status, message = healthStatusDegraded, "일부 의존 서비스에 문제가 있습니다"
+			}
+		}
+	}
+
 	healthData := HealthResponse{
-		Status:    "healthy",
+		Status:    status,
 		Timestamp: time.Now(),
 		Uptime:    uptime.String(),
 		Version:   h.config.App.Version,
@@ -69,20 +134,10 @@ func (h *HealthHandler) Health(c echo.Context) error {
 			OS:           runtime.GOOS,
 			Arch:         runtime.GOARCH,
 		},
-		Services: map[string]ServiceInfo{
-			"api": {
-				Status: "healthy",
-			},
-			"database": {
-				Status: "healthy", // TODO: 실제 DB 연결 체크
-			},
-			"filesystem": {
-				Status: "healthy", // TODO: 파일시스템 체크
-			},
-		},
+		Services: services,
 	}
 
-	return response.Success(c, healthData, "서비스가 정상적으로 동작 중입니다")
+	return response.Success(c, healthData, message)
 }
 
 // Ready 준비 상태 체크 엔드포인트
@@ -92,6 +147,11 @@ func (h *HealthHandler) Ready(c echo.Context) error {
 	// - 필수 서비스 확인
 	// - 설정 파일 로드 확인
 
+	// 암호화 자체 점검은 시작 시 한 번 실행한 결과를 재사용
+	if err := h.cryptoCheck(); err != nil {
+		return response.ServiceUnavailable(c, "암호화 자체 점검에 실패해 요청을 받을 수 없습니다", err.Error())
+	}
+
 	readyData := map[string]interface{}{
 		"ready":     true,
 		"timestamp": time.Now(),
@@ -99,6 +159,7 @@ func (h *HealthHandler) Ready(c echo.Context) error {
 			"database":   true, // TODO: 실제 체크
 			"filesystem": true, // TODO: 실제 체크
 			"config":     true,
+			"crypto":     true,
 		},
 	}
 
@@ -134,6 +195,12 @@ func (h *HealthHandler) Metrics(c echo.Context) error {
 		"uptime":     time.Since(h.startTime).String(),
 		"timestamp":  time.Now(),
 	}
+	if h.writeStats != nil {
+		metricsData["db_writes"] = h.writeStats.Stats()
+	}
+	if h.queryStats != nil {
+		metricsData["db_queries"] = h.queryStats.Stats()
+	}
 
 	return response.Success(c, metricsData, "메트릭 정보")
 }
@@ -1,15 +1,21 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"DataLocker/internal/config"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+	"DataLocker/pkg/crypto"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // createTestConfig creates a test configuration
@@ -58,6 +64,36 @@ func TestHealthHandler_Health(t *testing.T) {
 	assert.Equal(t, "2.0.0", data["version"])
 }
 
+// stubHealthService 고정된 점검 결과를 반환하는 의존 서비스 점검 서비스
+type stubHealthService map[string]service.HealthCheckResult
+
+func (s stubHealthService) Check(context.Context) map[string]service.HealthCheckResult {
+	return s
+}
+
+func TestHealthHandler_Health_Degraded(t *testing.T) {
+	handler := NewHealthHandler(createTestConfig())
+	handler.SetHealthService(stubHealthService{
+		"database":   {Status: service.HealthStatusTimeout, Message: "2s 안에 응답하지 않았습니다", DurationMillis: 2000},
+		"filesystem": {Status: service.HealthStatusHealthy, DurationMillis: 1},
+	})
+	c, rec := createTestContext(http.MethodGet, "/health")
+
+	assert.NoError(t, handler.Health(c))
+	response := assertSuccessResponse(t, rec)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "degraded", data["status"])
+
+	services := data["services"].(map[string]interface{})
+	database := services["database"].(map[string]interface{})
+	assert.Equal(t, service.HealthStatusTimeout, database["status"])
+	assert.Equal(t, 2000.0, database["duration_ms"])
+	assert.NotContains(t, database, "last_success")
+	assert.Equal(t, service.HealthStatusHealthy, services["filesystem"].(map[string]interface{})["status"])
+	assert.Equal(t, service.HealthStatusHealthy, services["api"].(map[string]interface{})["status"])
+}
+
 func TestHealthHandler_Ready(t *testing.T) {
 	handler := NewHealthHandler(createTestConfig())
 	c, rec := createTestContext(http.MethodGet, "/ready")
@@ -69,6 +105,16 @@ func TestHealthHandler_Ready(t *testing.T) {
 
 	data := response["data"].(map[string]interface{})
 	assert.True(t, data["ready"].(bool))
+	assert.True(t, data["checks"].(map[string]interface{})["crypto"].(bool))
+}
+
+func TestHealthHandler_Ready_CryptoSelfTestFailed(t *testing.T) {
+	handler := NewHealthHandler(createTestConfig())
+	handler.cryptoCheck = func() error { return crypto.ErrSelfTestFailed }
+	c, rec := createTestContext(http.MethodGet, "/ready")
+
+	assert.NoError(t, handler.Ready(c))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
 }
 
 func TestHealthHandler_Live(t *testing.T) {
@@ -97,4 +143,46 @@ func TestHealthHandler_Metrics(t *testing.T) {
 	assert.NotNil(t, data["memory"])
 	assert.NotNil(t, data["goroutines"])
 	assert.NotNil(t, data["uptime"])
+	assert.NotContains(t, data, "db_writes") // 쓰기 직렬화 미사용
+}
+
+// stubWriteStats 고정된 쓰기 메트릭을 반환하는 제공자
+type stubWriteStats struct{}
+
+func (stubWriteStats) Stats() repository.WriteStats {
+	return repository.WriteStats{QueueLength: 3, QueueCapacity: 256, Completed: 10}
+}
+
+func TestHealthHandler_Metrics_WriteStats(t *testing.T) {
+	handler := NewHealthHandler(createTestConfig())
+	handler.SetWriteStats(stubWriteStats{})
+	c, rec := createTestContext(http.MethodGet, "/metrics")
+
+	assert.NoError(t, handler.Metrics(c))
+
+	response := assertSuccessResponse(t, rec)
+	data := response["data"].(map[string]interface{})
+	writes := data["db_writes"].(map[string]interface{})
+	assert.Equal(t, float64(3), writes["queue_length"])
+	assert.Equal(t, float64(256), writes["queue_capacity"])
+	assert.Equal(t, float64(10), writes["completed"])
+}
+
+func TestHealthHandler_Metrics_QueryStats(t *testing.T) {
+	stats := repository.NewQueryStats()
+	stats.ObserveQuery("FileRepository.GetByID", 2*time.Millisecond, nil)
+
+	handler := NewHealthHandler(createTestConfig())
+	handler.SetQueryStats(stats)
+	c, rec := createTestContext(http.MethodGet, "/metrics")
+
+	assert.NoError(t, handler.Metrics(c))
+
+	response := assertSuccessResponse(t, rec)
+	data := response["data"].(map[string]interface{})
+	queries := data["db_queries"].([]interface{})
+	require.Len(t, queries, 1)
+	query := queries[0].(map[string]interface{})
+	assert.Equal(t, "FileRepository.GetByID", query["name"])
+	assert.Equal(t, float64(1), query["count"])
 }
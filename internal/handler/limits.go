@@ -0,0 +1,68 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the upload limits and upload policy endpoints.
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// LimitsHandler 업로드 정책 조회 핸들러
+type LimitsHandler struct {
+	validationService service.ValidationService
+}
+
+// NewLimitsHandler 새로운 업로드 정책 조회 핸들러를 생성합니다
+func NewLimitsHandler(validationService service.ValidationService) *LimitsHandler {
+	return &LimitsHandler{
+		validationService: validationService,
+	}
+}
+
+// GetLimits 업로드 크기 정책(MIME 그룹별 제한 포함)을 반환합니다
+//
+// GET /api/v1/limits
+// 클라이언트는 업로드 전에 파일의 MIME 타입에 맞는 제한을 표시할 수 있습니다.
+func (h *LimitsHandler) GetLimits(c echo.Context) error {
+	return response.Success(c, h.validationService.Limits(), "업로드 정책 조회가 완료되었습니다")
+}
+
+// mimeApplicationSchemaJSON JSON 스키마 문서의 Content-Type
+const mimeApplicationSchemaJSON = "application/schema+json"
+
+// GetUploadPolicy 현재 업로드 정책을 JSON 스키마로 반환합니다
+//
+// GET /api/v1/upload-policy
+// 클라이언트가 스키마 검증기로 바로 쓸 수 있도록 응답 래퍼 없이 스키마 문서만
+// 반환합니다. ETag는 정책 버전이며 If-None-Match가 일치하면 304를 반환합니다.
+// 설정 리로드가 바로 반영되도록 매번 재검증(no-cache)하게 합니다.
+func (h *LimitsHandler) GetUploadPolicy(c echo.Context) error {
+	document := h.validationService.UploadPolicy()
+	etag := `"` + document.Version + `"`
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set("ETag", etag)
+
+	if etagMatches(c.Request().Header.Get("If-None-Match"), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, mimeApplicationSchemaJSON, document.Schema)
+}
+
+// etagMatches If-None-Match 값에 ETag가 포함되는지 확인합니다 (약한 비교, "*"는 항상 일치)
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
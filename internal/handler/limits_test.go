@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitsHandler_GetLimits(t *testing.T) {
+	validation := service.NewValidationService(config.UploadConfig{
+		DefaultMaxSize: 1000,
+		MIMEGroups: map[string]config.MIMEGroupLimit{
+			"image": {Patterns: []string{"image/*"}, MaxSize: 500},
+		},
+	})
+	handler := NewLimitsHandler(validation)
+	c, rec := createTestContext(http.MethodGet, "/api/v1/limits")
+
+	require.NoError(t, handler.GetLimits(c))
+	response := assertSuccessResponse(t, rec)
+
+	data := response["data"].(map[string]interface{})
+	assert.InDelta(t, 1000, data["default_max_size"], 0)
+
+	groups := data["groups"].([]interface{})
+	require.Len(t, groups, 1)
+	group := groups[0].(map[string]interface{})
+	assert.Equal(t, "image", group["name"])
+	assert.InDelta(t, 500, group["max_size"], 0)
+}
+
+// getUploadPolicy If-None-Match를 붙여 업로드 정책을 조회합니다
+func getUploadPolicy(t *testing.T, handler *LimitsHandler, ifNoneMatch string) *httptest.ResponseRecorder {
+	t.Helper()
+	c, rec := createTestContext(http.MethodGet, "/api/v1/upload-policy")
+	if ifNoneMatch != "" {
+		c.Request().Header.Set("If-None-Match", ifNoneMatch)
+	}
+	require.NoError(t, handler.GetUploadPolicy(c))
+	return rec
+}
+
+func TestLimitsHandler_GetUploadPolicy(t *testing.T) {
+	validation := service.NewValidationService(config.UploadConfig{DefaultMaxSize: 1000})
+	handler := NewLimitsHandler(validation)
+
+	rec := getUploadPolicy(t, handler, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, mimeApplicationSchemaJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "no-cache", rec.Header().Get(echo.HeaderCacheControl))
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &schema))
+	version := schema["x-policy-version"].(string)
+	etag := rec.Header().Get("ETag")
+	assert.Equal(t, `"`+version+`"`, etag)
+	size := schema["properties"].(map[string]interface{})["size"].(map[string]interface{})
+	assert.InDelta(t, 1000, size["maximum"], 0)
+
+	// 같은 정책이면 본문 없이 304
+	for _, ifNoneMatch := range []string{etag, "W/" + etag, `"old", ` + etag, "*"} {
+		rec = getUploadPolicy(t, handler, ifNoneMatch)
+		assert.Equal(t, http.StatusNotModified, rec.Code, ifNoneMatch)
+		assert.Empty(t, rec.Body.String())
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+	}
+
+	// 설정 리로드로 정책이 바뀌면 이전 ETag로도 새 문서를 받음
+	validation.UpdatePolicy(config.UploadConfig{DefaultMaxSize: 2000})
+	rec = getUploadPolicy(t, handler, etag)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), `"maximum":2000`)
+
+	c, limitsRec := createTestContext(http.MethodGet, "/api/v1/limits")
+	require.NoError(t, handler.GetLimits(c))
+	assert.Contains(t, limitsRec.Body.String(), `"policy_version":`+rec.Header().Get("ETag"))
+}
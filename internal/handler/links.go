@@ -0,0 +1,201 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file builds the hypermedia links attached to file responses.
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"DataLocker/internal/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 파일 링크 관계(rel)
+const (
+	LinkSelf     = "self"
+	LinkDownload = "download"
+	LinkPreview  = "preview"
+	LinkVersions = "versions"
+	LinkCode     = "code"
+)
+
+// linkRoutes 링크 관계별 GET 경로 템플릿 (:id는 파일 ID, :code는 짧은 파일 코드로 치환)
+var linkRoutes = []struct {
+	rel  string
+	path string
+}{
+	{rel: LinkSelf, path: "/api/v1/admin/files/:id"},
+	{rel: LinkDownload, path: "/api/v1/files/:id/download"},
+	{rel: LinkPreview, path: "/api/v1/files/:id/preview"},
+	{rel: LinkVersions, path: "/api/v1/files/:id/versions"},
+	{rel: LinkCode, path: "/api/v1/files/code/:code"},
+}
+
+// 프록시가 원래 요청의 스킴과 호스트를 전달하는 헤더
+const (
+	headerForwardedProto = "X-Forwarded-Proto"
+	headerForwardedHost  = "X-Forwarded-Host"
+)
+
+// FileLinks 파일 응답에 포함하는 링크 (서버에 없는 기능의 링크는 생략)
+type FileLinks struct {
+	Self     string `json:"self,omitempty"`
+	Download string `json:"download,omitempty"`
+	Preview  string `json:"preview,omitempty"`
+	Versions string `json:"versions,omitempty"`
+	Code     string `json:"code,omitempty"` // 공유용 짧은 코드 조회 (코드가 없는 파일은 생략)
+}
+
+// fileResponse 링크를 덧붙인 파일 응답
+type fileResponse struct {
+	*model.File
+	Links *FileLinks `json:"links,omitempty"`
+}
+
+// LinkBuilder 요청 정보로 절대 URL 링크를 만드는 빌더
+//
+// 기준 주소는 설정된 공개 주소, 신뢰하는 프록시가 보낸 X-Forwarded-Proto/Host,
+// 요청의 TLS 여부와 Host 헤더 순으로 결정합니다. 신뢰하지 않는 클라이언트가 보낸
+// 전달 헤더는 무시하므로 링크가 임의의 호스트를 가리키도록 조작할 수 없습니다.
+type LinkBuilder struct {
+	publicBaseURL string
+	trusted       func(req *http.Request) bool
+	rels          map[string]string // 사용할 링크 관계 → 경로 템플릿
+}
+
+// NewLinkBuilder 새로운 링크 빌더를 생성합니다
+//
+// publicBaseURL은 비어 있거나 http(s) 절대 주소여야 합니다. trusted가 nil이면
+// 전달 헤더를 항상 무시합니다. Bind를 호출하기 전에는 모든 링크 관계를 사용합니다.
+func NewLinkBuilder(publicBaseURL string, trusted func(req *http.Request) bool) (*LinkBuilder, error) {
+	base, err := normalizeBaseURL(publicBaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if trusted == nil {
+		trusted = func(*http.Request) bool { return false }
+	}
+
+	rels := make(map[string]string, len(linkRoutes))
+	for _, route := range linkRoutes {
+		rels[route.rel] = route.path
+	}
+
+	return &LinkBuilder{
+		publicBaseURL: base,
+		trusted:       trusted,
+		rels:          rels,
+	}, nil
+}
+
+// Bind 라우터에 GET 경로가 등록된 링크 관계만 남깁니다
+//
+// 라우트를 모두 등록한 뒤 요청을 받기 전에 한 번 호출합니다. 관리 API가 꺼져 있으면
+// self 링크가, 아직 없는 기능(다운로드, 버전 등)은 해당 링크가 빠집니다.
+func (b *LinkBuilder) Bind(e *echo.Echo) {
+	registered := make(map[string]bool)
+	for _, route := range e.Routes() {
+		if route.Method == http.MethodGet {
+			registered[route.Path] = true
+		}
+	}
+
+	for rel, path := range b.rels {
+		if !registered[path] {
+			delete(b.rels, rel)
+		}
+	}
+}
+
+// BaseURL 요청에 대한 외부 기준 주소를 반환합니다 (끝의 /는 제외)
+func (b *LinkBuilder) BaseURL(c echo.Context) string {
+	if b.publicBaseURL != "" {
+		return b.publicBaseURL
+	}
+
+	req := c.Request()
+	scheme, host := "http", req.Host
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	if b.trusted(req) {
+		if proto := strings.ToLower(firstHeaderValue(req.Header.Get(headerForwardedProto))); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwardedHost := firstHeaderValue(req.Header.Get(headerForwardedHost)); forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+
+	return scheme + "://" + host
+}
+
+// FileLinks 파일의 링크를 만듭니다
+func (b *LinkBuilder) FileLinks(c echo.Context, file *model.File) *FileLinks {
+	base := b.BaseURL(c)
+	id := strconv.FormatUint(uint64(file.ID), 10)
+
+	href := func(rel string) string {
+		path, ok := b.rels[rel]
+		if !ok {
+			return ""
+		}
+		return base + strings.Replace(path, ":id", id, 1)
+	}
+
+	links := &FileLinks{
+		Self:     href(LinkSelf),
+		Download: href(LinkDownload),
+		Preview:  href(LinkPreview),
+		Versions: href(LinkVersions),
+	}
+	if path, ok := b.rels[LinkCode]; ok && file.ShortCode != "" {
+		links.Code = base + strings.Replace(path, ":code", file.ShortCode, 1)
+	}
+	return links
+}
+
+// withLinks 빌더가 있으면 파일 응답에 링크를 덧붙입니다 (nil 빌더는 링크 생략)
+func withLinks(c echo.Context, b *LinkBuilder, file *model.File) *fileResponse {
+	if file == nil {
+		return nil
+	}
+
+	resp := &fileResponse{File: file}
+	if b != nil {
+		resp.Links = b.FileLinks(c, file)
+	}
+	return resp
+}
+
+// normalizeBaseURL 설정된 공개 주소를 검증하고 끝의 /를 제거합니다
+func normalizeBaseURL(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("공개 주소를 해석할 수 없습니다: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.New("공개 주소는 http 또는 https 절대 주소여야 합니다")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", errors.New("공개 주소에는 쿼리나 프래그먼트를 포함할 수 없습니다")
+	}
+
+	return strings.TrimRight(u.Scheme+"://"+u.Host+u.Path, "/"), nil
+}
+
+// firstHeaderValue 여러 프록시를 거쳐 쉼표로 이어진 헤더 값 중 첫 번째(클라이언트 쪽)를 반환합니다
+func firstHeaderValue(value string) string {
+	first, _, _ := strings.Cut(value, ",")
+	return strings.TrimSpace(first)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trustRemote 지정한 RemoteAddr에서 온 요청만 신뢰하는 프록시 판별 함수
+func trustRemote(addr string) func(*http.Request) bool {
+	return func(req *http.Request) bool { return req.RemoteAddr == addr }
+}
+
+func TestNewLinkBuilder_ValidatesBaseURL(t *testing.T) {
+	for _, raw := range []string{"files.example.com", "ftp://files.example.com", "https://", "https://files.example.com/?a=1", "://bad"} {
+		_, err := NewLinkBuilder(raw, nil)
+		assert.Error(t, err, raw)
+	}
+
+	b, err := NewLinkBuilder("https://files.example.com/locker/", nil)
+	require.NoError(t, err)
+	c, _ := createTestContext(http.MethodGet, "/")
+	assert.Equal(t, "https://files.example.com/locker", b.BaseURL(c), "끝의 / 제거")
+}
+
+func TestLinkBuilder_BaseURL(t *testing.T) {
+	const proxyAddr = "10.0.0.1:5000"
+
+	testCases := []struct {
+		name       string
+		publicBase string
+		remoteAddr string
+		tls        bool
+		proto      string
+		host       string
+		want       string
+	}{
+		{name: "요청 Host 사용", remoteAddr: "203.0.113.9:1234", want: "http://example.com"},
+		{name: "TLS 연결", remoteAddr: "203.0.113.9:1234", tls: true, want: "https://example.com"},
+		{name: "신뢰하는 프록시의 전달 헤더", remoteAddr: proxyAddr, proto: "https", host: "files.example.com", want: "https://files.example.com"},
+		{name: "여러 프록시를 거친 헤더는 첫 값", remoteAddr: proxyAddr, proto: "HTTPS, http", host: "files.example.com, internal:8080", want: "https://files.example.com"},
+		{name: "허용하지 않는 스킴은 무시", remoteAddr: proxyAddr, proto: "javascript", want: "http://example.com"},
+		{name: "신뢰하지 않는 클라이언트의 헤더 무시", remoteAddr: "203.0.113.9:1234", proto: "https", host: "evil.example", want: "http://example.com"},
+		{name: "설정된 공개 주소 우선", publicBase: "https://public.example", remoteAddr: proxyAddr, proto: "http", host: "files.example.com", want: "https://public.example"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := NewLinkBuilder(tc.publicBase, trustRemote(proxyAddr))
+			require.NoError(t, err)
+
+			c, _ := createTestContext(http.MethodGet, "/")
+			req := c.Request()
+			req.RemoteAddr = tc.remoteAddr
+			if tc.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			if tc.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tc.proto)
+			}
+			if tc.host != "" {
+				req.Header.Set("X-Forwarded-Host", tc.host)
+			}
+
+			assert.Equal(t, tc.want, b.BaseURL(c))
+		})
+	}
+}
+
+func TestLinkBuilder_BindKeepsRegisteredRoutes(t *testing.T) {
+	b, err := NewLinkBuilder("https://files.example.com", nil)
+	require.NoError(t, err)
+
+	c, _ := createTestContext(http.MethodGet, "/")
+	links := b.FileLinks(c, &model.File{ID: 42, ShortCode: "7K3M9QXZ"})
+	assert.Equal(t, "https://files.example.com/api/v1/files/42/download", links.Download, "Bind 전에는 모든 링크")
+	assert.Equal(t, "https://files.example.com/api/v1/files/code/7K3M9QXZ", links.Code)
+	assert.Empty(t, b.FileLinks(c, &model.File{ID: 42}).Code, "코드가 없으면 코드 링크 생략")
+
+	e := echo.New()
+	noop := func(echo.Context) error { return nil }
+	e.GET("/api/v1/files/:id/preview", noop)
+	e.POST("/api/v1/files/:id/download", noop)
+	b.Bind(e)
+
+	assert.Equal(t, &FileLinks{Preview: "https://files.example.com/api/v1/files/42/preview"}, b.FileLinks(c, &model.File{ID: 42, ShortCode: "7K3M9QXZ"}),
+		"GET으로 등록된 라우트만 남김")
+}
+
+func TestAdminHandler_ListFilesLinks(t *testing.T) {
+	stub := &stubAdminService{list: &service.AdminFileList{
+		Files: []*model.File{{ID: 7, OriginalName: "a.txt"}},
+		Total: 1, Page: 1, PageSize: 10,
+	}}
+	b, err := NewLinkBuilder("", nil)
+	require.NoError(t, err)
+
+	h := NewAdminHandler(stub)
+	h.SetLinkBuilder(b)
+
+	c, rec := createTestContext(http.MethodGet, "/api/v1/admin/files")
+	require.NoError(t, h.ListFiles(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data struct {
+			Files []struct {
+				ID    uint      `json:"id"`
+				Name  string    `json:"original_name"`
+				Links FileLinks `json:"links"`
+			} `json:"files"`
+			Total int64 `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Data.Files, 1)
+	assert.Equal(t, "a.txt", body.Data.Files[0].Name, "파일 필드는 그대로 유지")
+	assert.Equal(t, "http://example.com/api/v1/admin/files/7", body.Data.Files[0].Links.Self)
+	assert.Equal(t, int64(1), body.Data.Total)
+
+	// 빌더가 없으면 links 항목 없음
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/files/7")
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	require.NoError(t, NewAdminHandler(stub).GetFile(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `"links"`)
+}
+
+func TestLinkBuilder_NotExposedWithoutTrust(t *testing.T) {
+	b, err := NewLinkBuilder("", nil)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set("X-Forwarded-Host", "evil.example")
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	assert.Equal(t, "http://example.com", b.BaseURL(c), "nil 판별 함수는 전달 헤더를 무시")
+}
@@ -0,0 +1,27 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the metadata endpoint that exposes enum values to clients.
+package handler
+
+import (
+	"DataLocker/internal/model"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MetaHandler 클라이언트용 메타 정보 조회 핸들러
+type MetaHandler struct{}
+
+// NewMetaHandler 새로운 메타 정보 조회 핸들러를 생성합니다
+func NewMetaHandler() *MetaHandler {
+	return &MetaHandler{}
+}
+
+// Enums 파일 상태, 암호화 알고리즘, 키 유도 방식의 허용값과 표시 라벨(ko/en)을 반환합니다
+//
+// GET /api/v1/meta/enums
+// 서버 검증과 같은 model 레지스트리에서 만들므로 클라이언트가 목록을 하드코딩하지
+// 않아도 됩니다. 값은 enum마다 등록 순서(파일 상태는 처리 순서)로 반환합니다.
+func (h *MetaHandler) Enums(c echo.Context) error {
+	return response.Success(c, model.Enums(), "enum 목록 조회가 완료되었습니다")
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"DataLocker/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetaHandler_Enums(t *testing.T) {
+	c, rec := createTestContext(http.MethodGet, "/api/v1/meta/enums")
+
+	require.NoError(t, NewMetaHandler().Enums(c))
+	response := assertSuccessResponse(t, rec)
+
+	data := response["data"].(map[string]interface{})
+	assert.Len(t, data, 3)
+
+	statuses := data[model.EnumFileStatus].([]interface{})
+	require.Len(t, statuses, len(model.FileStatuses()))
+	first := statuses[0].(map[string]interface{})
+	assert.Equal(t, model.FileStatusPending, first["value"])
+	assert.Equal(t, map[string]interface{}{"ko": "처리 대기", "en": "Pending"}, first["labels"])
+
+	algorithms := data[model.EnumEncryptionAlgorithm].([]interface{})
+	assert.Equal(t, model.EncryptionAlgorithmAES256GCM, algorithms[0].(map[string]interface{})["value"])
+
+	derivations := data[model.EnumKeyDerivation].([]interface{})
+	assert.Equal(t, model.KeyDerivationPBKDF2SHA256, derivations[0].(map[string]interface{})["value"])
+}
@@ -0,0 +1,87 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the two-phase upload negotiation endpoints.
+package handler
+
+import (
+	"errors"
+
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NegotiateHandler 업로드 협상 핸들러
+type NegotiateHandler struct {
+	dedupService service.DedupService
+}
+
+// NewNegotiateHandler 새로운 업로드 협상 핸들러를 생성합니다
+func NewNegotiateHandler(dedupService service.DedupService) *NegotiateHandler {
+	return &NegotiateHandler{
+		dedupService: dedupService,
+	}
+}
+
+// Negotiate 업로드 1단계: 체크섬/크기로 중복 여부를 확인합니다
+//
+// POST /api/v1/files/negotiate
+// 중복이면 참조 레코드를 만들고 201, 아니면 업로드 세션이나 소유 증명 챌린지를 200으로 반환합니다.
+func (h *NegotiateHandler) Negotiate(c echo.Context) error {
+	var req service.NegotiateRequest
+	if err := c.Bind(&req); err != nil {
+		return response.BadRequest(c, "잘못된 요청 형식입니다", err.Error())
+	}
+
+	result, err := h.dedupService.Negotiate(c.Request().Context(), &req)
+	if err != nil {
+		return negotiationError(c, err)
+	}
+
+	return negotiationResponse(c, result)
+}
+
+// VerifyProof 소유 증명 응답을 검증합니다
+//
+// POST /api/v1/files/negotiate/verify
+func (h *NegotiateHandler) VerifyProof(c echo.Context) error {
+	var req service.ProofRequest
+	if err := c.Bind(&req); err != nil {
+		return response.BadRequest(c, "잘못된 요청 형식입니다", err.Error())
+	}
+
+	result, err := h.dedupService.VerifyProof(c.Request().Context(), &req)
+	if err != nil {
+		return negotiationError(c, err)
+	}
+
+	return negotiationResponse(c, result)
+}
+
+// negotiationResponse 협상 결과에 맞는 상태 코드로 응답합니다
+func negotiationResponse(c echo.Context, result *service.NegotiateResult) error {
+	switch result.Action {
+	case service.NegotiateActionLinked:
+		return response.Created(c, result, "기존 파일을 참조하는 레코드를 생성했습니다")
+	case service.NegotiateActionChallenge:
+		return response.Success(c, result, "소유 증명이 필요합니다")
+	default:
+		return response.Success(c, result, "파일 본문을 업로드해 주세요")
+	}
+}
+
+// negotiationError 협상 에러를 HTTP 응답으로 변환합니다
+func negotiationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidNegotiation):
+		return response.BadRequest(c, "잘못된 업로드 협상 요청입니다", err.Error())
+	case errors.Is(err, service.ErrChallengeNotFound):
+		return response.NotFound(c, err.Error(), "")
+	case errors.Is(err, service.ErrProofFailed):
+		return response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrExternalIDInUse):
+		return response.Conflict(c, "외부 참조 ID가 이미 다른 파일에 사용 중입니다", err.Error())
+	default:
+		return response.InternalError(c, "업로드 협상에 실패했습니다", err.Error())
+	}
+}
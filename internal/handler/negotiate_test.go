@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDedupService 고정 결과를 반환하는 테스트용 협상 서비스
+type stubDedupService struct {
+	result  *service.NegotiateResult
+	session *service.NegotiateRequest
+	err     error
+}
+
+func (s *stubDedupService) Negotiate(ctx context.Context, req *service.NegotiateRequest) (*service.NegotiateResult, error) {
+	return s.result, s.err
+}
+
+func (s *stubDedupService) VerifyProof(ctx context.Context, req *service.ProofRequest) (*service.NegotiateResult, error) {
+	return s.result, s.err
+}
+
+func (s *stubDedupService) ClaimUploadSession(id string) (*service.NegotiateRequest, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.session, nil
+}
+
+// createJSONContext JSON 본문을 가진 테스트 컨텍스트를 생성합니다
+func createJSONContext(method, path, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	return e.NewContext(req, rec), rec
+}
+
+func TestNegotiateHandler_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stub     *stubDedupService
+		wantCode int
+	}{
+		{
+			name:     "중복 참조 생성",
+			stub:     &stubDedupService{result: &service.NegotiateResult{Action: service.NegotiateActionLinked}},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "업로드 필요",
+			stub:     &stubDedupService{result: &service.NegotiateResult{Action: service.NegotiateActionUpload}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "소유 증명 필요",
+			stub:     &stubDedupService{result: &service.NegotiateResult{Action: service.NegotiateActionChallenge}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "잘못된 요청",
+			stub:     &stubDedupService{err: service.ErrInvalidNegotiation},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewNegotiateHandler(tc.stub)
+			c, rec := createJSONContext(http.MethodPost, "/api/v1/files/negotiate", `{"original_name":"a.txt"}`)
+
+			require.NoError(t, handler.Negotiate(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestNegotiateHandler_VerifyProof(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{name: "증명 실패", err: service.ErrProofFailed, wantCode: http.StatusForbidden},
+		{name: "만료된 챌린지", err: service.ErrChallengeNotFound, wantCode: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewNegotiateHandler(&stubDedupService{err: tc.err})
+			c, rec := createJSONContext(http.MethodPost, "/api/v1/files/negotiate/verify", `{"challenge_id":"x","response":"00"}`)
+
+			require.NoError(t, handler.VerifyProof(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+
+	// 잘못된 JSON
+	handler := NewNegotiateHandler(&stubDedupService{})
+	c, rec := createJSONContext(http.MethodPost, "/api/v1/files/negotiate/verify", `{`)
+	require.NoError(t, handler.VerifyProof(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,105 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the text preview endpoint for encrypted files.
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 미리보기 응답 헤더
+const (
+	// HeaderTextEncoding 업로드 시 감지한 원본 인코딩
+	HeaderTextEncoding = "X-Text-Encoding"
+
+	// HeaderPreviewTruncated 미리보기가 앞부분에서 잘렸는지 여부
+	HeaderPreviewTruncated = "X-Preview-Truncated"
+)
+
+// PreviewHandler 텍스트 미리보기 핸들러
+type PreviewHandler struct {
+	previewService service.PreviewService
+}
+
+// NewPreviewHandler 새로운 텍스트 미리보기 핸들러를 생성합니다
+func NewPreviewHandler(previewService service.PreviewService) *PreviewHandler {
+	return &PreviewHandler{
+		previewService: previewService,
+	}
+}
+
+// Preview 텍스트 파일의 앞부분을 반환합니다
+//
+// GET /api/v1/files/:id/preview?convert=utf-8 (패스워드는 X-Encryption-Password 헤더)
+// convert=utf-8이면 감지된 인코딩에서 UTF-8로 변환하고, 생략하면 원본 바이트를
+// 감지된 charset으로 표시해 반환합니다.
+func (h *PreviewHandler) Preview(c echo.Context) error {
+	id, err := parseFileID(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 파일 ID입니다", err.Error())
+	}
+
+	toUTF8, err := parseConvertOption(c.QueryParam("convert"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 변환 옵션입니다", err.Error())
+	}
+
+	password := c.Request().Header.Get(HeaderEncryptionPassword)
+	if password == "" {
+		return response.BadRequest(c, "암호화 패스워드가 필요합니다", "")
+	}
+
+	preview, err := h.previewService.Preview(c.Request().Context(), id, password, toUTF8)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPreviewFileNotFound):
+			return response.NotFound(c, err.Error(), "")
+		case errors.Is(err, service.ErrPreviewPasswordMismatch):
+			return response.Unauthorized(c, "패스워드가 일치하지 않습니다")
+		case errors.Is(err, service.ErrPreviewNotText), errors.Is(err, service.ErrPreviewFileNotEncrypted):
+			return response.BadRequest(c, "미리보기할 수 없는 파일입니다", err.Error())
+		case errors.Is(err, service.ErrVolumeOffline):
+			return response.ServiceUnavailable(c, "파일이 저장된 볼륨을 사용할 수 없습니다", err.Error())
+		default:
+			return response.InternalError(c, "미리보기에 실패했습니다", err.Error())
+		}
+	}
+
+	c.Response().Header().Set(HeaderTextEncoding, preview.Encoding)
+	c.Response().Header().Set(HeaderPreviewTruncated, strconv.FormatBool(preview.Truncated))
+
+	return c.Blob(http.StatusOK, previewContentType(preview), preview.Content)
+}
+
+// parseConvertOption convert 쿼리 값을 해석합니다 (빈 값은 변환 안 함)
+func parseConvertOption(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return false, nil
+	case "utf-8", "utf8":
+		return true, nil
+	default:
+		return false, errors.New("convert는 utf-8만 지원합니다")
+	}
+}
+
+// previewContentType 미리보기 본문의 Content-Type을 결정합니다
+//
+// 인코딩을 알 수 없는 원본 바이트에는 charset을 붙이지 않습니다.
+func previewContentType(preview *service.TextPreview) string {
+	switch {
+	case preview.Converted:
+		return echo.MIMETextPlainCharsetUTF8
+	case preview.Encoding == "unknown":
+		return echo.MIMETextPlain
+	default:
+		return echo.MIMETextPlain + "; charset=" + preview.Encoding
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"DataLocker/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPreviewService 고정된 결과를 반환하는 미리보기 서비스
+type stubPreviewService struct {
+	preview *service.TextPreview
+	err     error
+	toUTF8  bool
+}
+
+func (s *stubPreviewService) Preview(_ context.Context, _ uint, _ string, toUTF8 bool) (*service.TextPreview, error) {
+	s.toUTF8 = toUTF8
+	return s.preview, s.err
+}
+
+// newPreviewContext 파일 ID와 패스워드가 설정된 미리보기 요청 컨텍스트
+func newPreviewContext(query, password string) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := createTestContext(http.MethodGet, "/api/v1/files/1/preview"+query)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	if password != "" {
+		c.Request().Header.Set(HeaderEncryptionPassword, password)
+	}
+	return c, rec
+}
+
+func TestPreviewHandler_Preview(t *testing.T) {
+	stub := &stubPreviewService{preview: &service.TextPreview{
+		FileID:    1,
+		Encoding:  "euc-kr",
+		Converted: true,
+		Content:   []byte("안녕하세요"),
+	}}
+	h := NewPreviewHandler(stub)
+
+	c, rec := newPreviewContext("?convert=utf-8", "secret")
+	require.NoError(t, h.Preview(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, stub.toUTF8)
+	assert.Equal(t, echo.MIMETextPlainCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "euc-kr", rec.Header().Get(HeaderTextEncoding))
+	assert.Equal(t, "false", rec.Header().Get(HeaderPreviewTruncated))
+	assert.Equal(t, "안녕하세요", rec.Body.String())
+
+	// 변환하지 않으면 감지된 charset 표시
+	stub.preview.Converted = false
+	c, rec = newPreviewContext("", "secret")
+	require.NoError(t, h.Preview(c))
+	assert.False(t, stub.toUTF8)
+	assert.Equal(t, "text/plain; charset=euc-kr", rec.Header().Get(echo.HeaderContentType))
+}
+
+func TestPreviewHandler_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		password string
+		err      error
+		wantCode int
+	}{
+		{name: "잘못된 변환 옵션", query: "?convert=latin1", password: "secret", wantCode: http.StatusBadRequest},
+		{name: "패스워드 없음", wantCode: http.StatusBadRequest},
+		{name: "파일 없음", password: "secret", err: fmt.Errorf("%w: ID 1", service.ErrPreviewFileNotFound), wantCode: http.StatusNotFound},
+		{name: "텍스트 아님", password: "secret", err: service.ErrPreviewNotText, wantCode: http.StatusBadRequest},
+		{name: "패스워드 불일치", password: "secret", err: service.ErrPreviewPasswordMismatch, wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewPreviewHandler(&stubPreviewService{err: tc.err})
+			c, rec := newPreviewContext(tc.query, tc.password)
+
+			require.NoError(t, h.Preview(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+}
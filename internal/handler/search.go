@@ -0,0 +1,157 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the unified file search endpoint.
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+	"DataLocker/pkg/httputil"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SearchHandler 통합 검색 핸들러
+type SearchHandler struct {
+	searchService service.SearchService
+}
+
+// NewSearchHandler 새로운 통합 검색 핸들러를 생성합니다
+func NewSearchHandler(searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search 이름/태그/상태/MIME/기간을 조합한 통합 검색 엔드포인트
+//
+// GET /api/v1/search?q=&tags=&status=&mime=&from=&to=&sort=&page=&page_size=
+// from/to는 RFC3339, YYYY-MM-DD(UTC), 상대 표현(-7d) 또는 now이며 최대 366일입니다.
+func (h *SearchHandler) Search(c echo.Context) error {
+	req, err := parseSearchRequest(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 검색 조건입니다", err.Error())
+	}
+
+	result, err := h.searchService.Search(c.Request().Context(), req)
+	if err != nil {
+		if errors.Is(err, service.ErrEmptySearchQuery) ||
+			errors.Is(err, service.ErrInvalidSearchSort) ||
+			errors.Is(err, service.ErrInvalidSearchFilter) {
+			return response.BadRequest(c, "잘못된 검색 조건입니다", err.Error())
+		}
+		return response.InternalError(c, "검색에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, result, "검색이 완료되었습니다")
+}
+
+// Similar 내용이 비슷한 유사 중복 파일을 해밍 거리순으로 반환합니다
+//
+// GET /api/v1/files/:id/similar?threshold=
+// threshold는 최대 해밍 거리(0~16)이며 생략하면 SIMILARITY_THRESHOLD 설정을 사용합니다.
+// 내용이 너무 짧아 시그니처가 없는 파일은 409로 응답합니다.
+func (h *SearchHandler) Similar(c echo.Context) error {
+	id, err := parseFileID(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 파일 ID입니다", err.Error())
+	}
+
+	req := &service.SimilarRequest{FileID: id}
+	if value := c.QueryParam("threshold"); value != "" {
+		threshold, err := strconv.Atoi(value)
+		if err != nil {
+			return response.BadRequest(c, "잘못된 유사도 임계값입니다", fmt.Sprintf("정수가 아닙니다: %s", value))
+		}
+		req.Threshold = &threshold
+	}
+
+	result, err := h.searchService.FindSimilar(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrRecordNotFound):
+			return response.NotFound(c, "파일을 찾을 수 없습니다", "")
+		case errors.Is(err, service.ErrInvalidSimilarityThreshold):
+			return response.BadRequest(c, "잘못된 유사도 임계값입니다", err.Error())
+		case errors.Is(err, repository.ErrNoSimilaritySignature):
+			return response.Conflict(c, "유사도 시그니처가 없는 파일입니다", err.Error())
+		default:
+			return response.InternalError(c, "유사 파일 조회에 실패했습니다", err.Error())
+		}
+	}
+
+	return response.Success(c, result, "유사 파일을 조회했습니다")
+}
+
+// parseSearchRequest 쿼리 파라미터를 검색 요청으로 변환합니다
+func parseSearchRequest(c echo.Context) (*service.SearchRequest, error) {
+	req := &service.SearchRequest{
+		Query:    strings.TrimSpace(c.QueryParam("q")),
+		Status:   c.QueryParam("status"),
+		MimeType: c.QueryParam("mime"),
+		Sort:     c.QueryParam("sort"),
+	}
+
+	if req.Status != "" && !model.IsValidFileStatus(req.Status) {
+		return nil, fmt.Errorf("유효하지 않은 파일 상태입니다: %s", req.Status)
+	}
+
+	for _, tag := range strings.Split(c.QueryParam("tags"), ",") {
+		if trimmed := strings.TrimSpace(tag); trimmed != "" {
+			req.Tags = append(req.Tags, trimmed)
+		}
+	}
+
+	// 기간은 UTC 반개구간 [from, to) (날짜만 지정한 to는 그 날까지 포함)
+	from, to, err := httputil.ParseTimeRange(c)
+	if err != nil {
+		return nil, err
+	}
+	if !from.IsZero() {
+		req.From = &from
+	}
+	if !to.IsZero() {
+		req.To = &to
+	}
+
+	if req.Page, err = parseOptionalInt(c.QueryParam("page")); err != nil {
+		return nil, fmt.Errorf("page: %w", err)
+	}
+	if req.PageSize, err = parseOptionalInt(c.QueryParam("page_size")); err != nil {
+		return nil, fmt.Errorf("page_size: %w", err)
+	}
+
+	return req, nil
+}
+
+// parseOptionalInt 비어 있으면 0을, 아니면 정수를 반환합니다
+func parseOptionalInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("정수가 아닙니다: %s", value)
+	}
+	return parsed, nil
+}
+
+// parseOptionalBool 비어 있으면 false를, 아니면 불리언 값을 반환합니다
+func parseOptionalBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("true 또는 false가 아닙니다: %s", value)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSearchService 요청을 기록하는 테스트용 검색 서비스
+type stubSearchService struct {
+	lastReq     *service.SearchRequest
+	lastSimilar *service.SimilarRequest
+	err         error
+}
+
+func (s *stubSearchService) Search(ctx context.Context, req *service.SearchRequest) (*service.SearchResult, error) {
+	s.lastReq = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &service.SearchResult{Items: []service.SearchHit{}, Page: 1, PageSize: 10}, nil
+}
+
+func (s *stubSearchService) FindSimilar(ctx context.Context, req *service.SimilarRequest) (*service.SimilarResult, error) {
+	s.lastSimilar = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &service.SimilarResult{FileID: req.FileID, Items: []service.SimilarHit{}}, nil
+}
+
+func TestSearchHandler_Search_Success(t *testing.T) {
+	stub := &stubSearchService{}
+	handler := NewSearchHandler(stub)
+	c, rec := createTestContext(http.MethodGet,
+		"/api/v1/search?q=tax&tags=tax-2024,%20personal&status=encrypted&mime=application/pdf&from=2024-01-01&to=2024-12-31&page=2&page_size=20")
+
+	err := handler.Search(c)
+	require.NoError(t, err)
+	assertSuccessResponse(t, rec)
+
+	require.NotNil(t, stub.lastReq)
+	assert.Equal(t, "tax", stub.lastReq.Query)
+	assert.Equal(t, []string{"tax-2024", "personal"}, stub.lastReq.Tags)
+	assert.Equal(t, "encrypted", stub.lastReq.Status)
+	assert.Equal(t, "application/pdf", stub.lastReq.MimeType)
+	assert.Equal(t, 2, stub.lastReq.Page)
+	assert.Equal(t, 20, stub.lastReq.PageSize)
+	require.NotNil(t, stub.lastReq.From)
+	require.NotNil(t, stub.lastReq.To)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), *stub.lastReq.From)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *stub.lastReq.To, "날짜만 지정한 상한은 다음 날 00:00(미포함)")
+}
+
+func TestSearchHandler_Search_BadRequest(t *testing.T) {
+	testCases := []struct {
+		name string
+		path string
+		err  error
+	}{
+		{name: "빈 질의", path: "/api/v1/search", err: service.ErrEmptySearchQuery},
+		{name: "잘못된 상태", path: "/api/v1/search?q=a&status=unknown"},
+		{name: "잘못된 날짜", path: "/api/v1/search?q=a&from=yesterday"},
+		{name: "역전된 기간", path: "/api/v1/search?q=a&from=2024-02-01&to=2024-01-01"},
+		{name: "최대 기간 초과", path: "/api/v1/search?q=a&from=2020-01-01&to=2024-01-01"},
+		{name: "잘못된 페이지", path: "/api/v1/search?q=a&page=abc"},
+		{name: "잘못된 태그", path: "/api/v1/search?tags=a", err: fmt.Errorf("%w: 태그", service.ErrInvalidSearchFilter)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewSearchHandler(&stubSearchService{err: tc.err})
+			c, rec := createTestContext(http.MethodGet, tc.path)
+
+			err := handler.Search(c)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.False(t, body["success"].(bool))
+		})
+	}
+}
+
+func TestSearchHandler_Similar(t *testing.T) {
+	stub := &stubSearchService{}
+	handler := NewSearchHandler(stub)
+
+	c, rec := createTestContext(http.MethodGet, "/api/v1/files/7/similar?threshold=5")
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	require.NoError(t, handler.Similar(c))
+	assertSuccessResponse(t, rec)
+	require.NotNil(t, stub.lastSimilar)
+	assert.Equal(t, uint(7), stub.lastSimilar.FileID)
+	require.NotNil(t, stub.lastSimilar.Threshold)
+	assert.Equal(t, 5, *stub.lastSimilar.Threshold)
+
+	// 생략하면 설정 기본값 사용
+	c, _ = createTestContext(http.MethodGet, "/api/v1/files/7/similar")
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	require.NoError(t, handler.Similar(c))
+	assert.Nil(t, stub.lastSimilar.Threshold)
+
+	testCases := []struct {
+		name     string
+		id       string
+		query    string
+		err      error
+		wantCode int
+	}{
+		{name: "잘못된 ID", id: "abc", wantCode: http.StatusBadRequest},
+		{name: "정수가 아닌 임계값", id: "7", query: "?threshold=high", wantCode: http.StatusBadRequest},
+		{name: "범위 밖 임계값", id: "7", err: service.ErrInvalidSimilarityThreshold, wantCode: http.StatusBadRequest},
+		{name: "없는 파일", id: "7", err: model.ErrRecordNotFound, wantCode: http.StatusNotFound},
+		{name: "시그니처 없음", id: "7", err: repository.ErrNoSimilaritySignature, wantCode: http.StatusConflict},
+		{name: "기타 오류", id: "7", err: errors.New("db"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewSearchHandler(&stubSearchService{err: tc.err})
+			c, rec := createTestContext(http.MethodGet, "/api/v1/files/"+tc.id+"/similar"+tc.query)
+			c.SetParamNames("id")
+			c.SetParamValues(tc.id)
+
+			require.NoError(t, handler.Similar(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+}
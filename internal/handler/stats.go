@@ -0,0 +1,87 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the storage statistics and daily metrics history endpoints.
+package handler
+
+import (
+	"errors"
+
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// StatsHandler 저장소 통계 핸들러
+type StatsHandler struct {
+	statsService   service.StatsService
+	metricsService service.MetricsService
+}
+
+// NewStatsHandler 새로운 저장소 통계 핸들러를 생성합니다
+func NewStatsHandler(statsService service.StatsService, metricsService service.MetricsService) *StatsHandler {
+	return &StatsHandler{
+		statsService:   statsService,
+		metricsService: metricsService,
+	}
+}
+
+// Storage 전체/상태별/MIME 대분류별 파일 수와 용량을 반환합니다
+//
+// GET /api/v1/admin/stats/storage?include_deleted=true
+// 파일이 없어도 모든 항목을 0으로 채워 200으로 응답합니다. include_deleted이면
+// 휴지통의 파일도 합계에 포함하고 그 몫을 trash로 함께 반환합니다.
+func (h *StatsHandler) Storage(c echo.Context) error {
+	includeDeleted, err := parseOptionalBool(c.QueryParam("include_deleted"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 include_deleted 값입니다", err.Error())
+	}
+
+	stats, err := h.statsService.StorageStats(c.Request().Context(), includeDeleted)
+	if err != nil {
+		return response.InternalError(c, "저장소 통계 조회에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, stats, "저장소 통계를 조회했습니다")
+}
+
+// History 어제까지 최근 days일의 일별 통계를 날짜순으로 반환합니다
+//
+// GET /api/v1/stats/history?days=30
+// 스냅샷이 없는 날은 채우지 않으므로 누락일은 Backfill로 먼저 채워야 합니다.
+func (h *StatsHandler) History(c echo.Context) error {
+	days, err := parseOptionalInt(c.QueryParam("days"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 기간입니다", err.Error())
+	}
+
+	history, err := h.metricsService.History(c.Request().Context(), days)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMetricsDays) {
+			return response.BadRequest(c, "잘못된 기간입니다", err.Error())
+		}
+		return response.InternalError(c, "일별 통계 조회에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, history, "일별 통계를 조회했습니다")
+}
+
+// Backfill 어제까지 최근 days일 중 스냅샷이 없는 날을 집계해 저장합니다
+//
+// POST /api/v1/admin/stats/backfill?days=30
+// 이미 스냅샷이 있는 날은 다시 집계하지 않습니다.
+func (h *StatsHandler) Backfill(c echo.Context) error {
+	days, err := parseOptionalInt(c.QueryParam("days"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 기간입니다", err.Error())
+	}
+
+	result, err := h.metricsService.Backfill(c.Request().Context(), days)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMetricsDays) {
+			return response.BadRequest(c, "잘못된 기간입니다", err.Error())
+		}
+		return response.InternalError(c, "일별 통계 backfill에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, result, "누락된 일별 통계를 채웠습니다")
+}
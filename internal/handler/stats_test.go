@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubStatsService 고정된 통계를 반환하는 통계 서비스
+type stubStatsService struct {
+	stats          *service.StorageStats
+	err            error
+	includeDeleted bool
+}
+
+func (s *stubStatsService) StorageStats(_ context.Context, includeDeleted bool) (*service.StorageStats, error) {
+	s.includeDeleted = includeDeleted
+	return s.stats, s.err
+}
+
+// stubMetricsService 요청한 기간을 기록하고 고정 결과를 반환하는 일별 통계 서비스
+type stubMetricsService struct {
+	service.MetricsService
+	days int
+	err  error
+}
+
+func (s *stubMetricsService) History(_ context.Context, days int) ([]*model.MetricsSnapshot, error) {
+	s.days = days
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []*model.MetricsSnapshot{{Date: "2024-03-09", TotalFiles: 2, Uploads: 1}}, nil
+}
+
+func (s *stubMetricsService) Backfill(_ context.Context, days int) (*service.MetricsBackfillResult, error) {
+	s.days = days
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &service.MetricsBackfillResult{From: "2024-03-08", To: "2024-03-09", Created: []string{"2024-03-08"}, Existing: 1}, nil
+}
+
+func TestStatsHandler_Storage(t *testing.T) {
+	stats := &stubStatsService{stats: &service.StorageStats{
+		TotalFiles: 2,
+		TotalBytes: 3072,
+		Statuses:   map[string]service.StatusUsage{"encrypted": {Files: 2, Bytes: 3072}},
+	}}
+	h := NewStatsHandler(stats, &stubMetricsService{})
+
+	c, rec := createTestContext(http.MethodGet, "/api/v1/admin/stats/storage")
+	require.NoError(t, h.Storage(c))
+	body := assertSuccessResponse(t, rec)
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, float64(3072), data["total_bytes"])
+	assert.Equal(t, float64(2), data["total_files"])
+	assert.False(t, stats.includeDeleted)
+
+	c, _ = createTestContext(http.MethodGet, "/api/v1/admin/stats/storage?include_deleted=true")
+	require.NoError(t, h.Storage(c))
+	assert.True(t, stats.includeDeleted)
+
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/stats/storage?include_deleted=maybe")
+	require.NoError(t, h.Storage(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	c, rec = createTestContext(http.MethodGet, "/api/v1/admin/stats/storage")
+	require.NoError(t, NewStatsHandler(&stubStatsService{err: errors.New("db down")}, &stubMetricsService{}).Storage(c))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestStatsHandler_History(t *testing.T) {
+	metrics := &stubMetricsService{}
+	h := NewStatsHandler(&stubStatsService{}, metrics)
+
+	c, rec := createTestContext(http.MethodGet, "/api/v1/stats/history?days=7")
+	require.NoError(t, h.History(c))
+	body := assertSuccessResponse(t, rec)
+	assert.Equal(t, 7, metrics.days)
+	data := body["data"].([]interface{})
+	require.Len(t, data, 1)
+	assert.Equal(t, "2024-03-09", data[0].(map[string]interface{})["date"])
+	assert.Equal(t, float64(1), data[0].(map[string]interface{})["uploads"])
+
+	c, rec = createTestContext(http.MethodGet, "/api/v1/stats/history?days=week")
+	require.NoError(t, h.History(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	metrics.err = fmt.Errorf("%w: 400일", service.ErrInvalidMetricsDays)
+	c, rec = createTestContext(http.MethodGet, "/api/v1/stats/history?days=400")
+	require.NoError(t, h.History(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	metrics.err = errors.New("db down")
+	c, rec = createTestContext(http.MethodGet, "/api/v1/stats/history")
+	require.NoError(t, h.History(c))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Zero(t, metrics.days, "기간을 생략하면 서비스 기본값")
+}
+
+func TestStatsHandler_Backfill(t *testing.T) {
+	metrics := &stubMetricsService{}
+	h := NewStatsHandler(&stubStatsService{}, metrics)
+
+	c, rec := createTestContext(http.MethodPost, "/api/v1/admin/stats/backfill?days=2")
+	require.NoError(t, h.Backfill(c))
+	body := assertSuccessResponse(t, rec)
+	assert.Equal(t, 2, metrics.days)
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"2024-03-08"}, data["created_dates"])
+	assert.Equal(t, float64(1), data["existing"])
+
+	metrics.err = fmt.Errorf("%w: 0일", service.ErrInvalidMetricsDays)
+	c, rec = createTestContext(http.MethodPost, "/api/v1/admin/stats/backfill?days=-1")
+	require.NoError(t, h.Backfill(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
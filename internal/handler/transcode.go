@@ -0,0 +1,104 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the endpoint that re-encrypts a stored file to another password.
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 트랜스코드 응답 트레일러
+const (
+	// TrailerTranscodeStatus 본문을 끝까지 보냈는지 알려주는 트레일러 (complete 또는 interrupted)
+	TrailerTranscodeStatus = "X-Transcode-Status"
+
+	// TranscodeStatusComplete 종료 레코드까지 모두 보냄
+	TranscodeStatusComplete = "complete"
+
+	// TranscodeStatusInterrupted 중간에 중단되어 본문이 잘림 (복호화하면 잘린 스트림 에러)
+	TranscodeStatusInterrupted = "interrupted"
+)
+
+// TranscodeHandler 재암호화 핸들러
+type TranscodeHandler struct {
+	transcodeService service.TranscodeService
+}
+
+// NewTranscodeHandler 새로운 재암호화 핸들러를 생성합니다
+func NewTranscodeHandler(transcodeService service.TranscodeService) *TranscodeHandler {
+	return &TranscodeHandler{
+		transcodeService: transcodeService,
+	}
+}
+
+// Transcode 파일을 원본 패스워드로 풀어 대상 패스워드로 다시 암호화한 결과를 내려줍니다
+//
+// POST /api/v1/files/:id/transcode
+// {"source_password": "...", "target_password": "...", "profile": {"format": "armored", "compression": "gzip", "iterations": 0}}
+// 평문은 디스크에 쓰지 않고 스트리밍합니다. 원본 패스워드 오류는 401, 대상 패스워드나
+// 프로필 오류는 400으로 본문을 보내기 전에 응답합니다. 본문을 보내기 시작한 뒤 실패하면
+// 상태 코드를 바꿀 수 없으므로 종료 레코드 없이 끊고 X-Transcode-Status 트레일러를
+// interrupted로 보냅니다 (잘린 결과는 복호화 시 잘린 스트림으로 거부됨).
+func (h *TranscodeHandler) Transcode(c echo.Context) error {
+	id, err := parseFileID(c)
+	if err != nil {
+		return response.BadRequest(c, "잘못된 파일 ID입니다", err.Error())
+	}
+
+	var req service.TranscodeRequest
+	if err := c.Bind(&req); err != nil {
+		return response.BadRequest(c, "잘못된 요청 형식입니다", err.Error())
+	}
+	req.FileID = id
+
+	if req.SourcePassword == "" {
+		return response.BadRequest(c, "원본 패스워드가 필요합니다", "source_password")
+	}
+
+	if req.TargetPassword == "" {
+		return response.BadRequest(c, "대상 패스워드가 필요합니다", "target_password")
+	}
+
+	stream, err := h.transcodeService.Open(c.Request().Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTranscodeFileNotFound):
+			return response.NotFound(c, err.Error(), "")
+		case errors.Is(err, service.ErrTranscodeSourcePassword):
+			return response.Unauthorized(c, "원본 패스워드가 일치하지 않습니다")
+		case errors.Is(err, service.ErrTranscodeTargetPassword):
+			return response.BadRequest(c, "대상 패스워드가 올바르지 않습니다", err.Error())
+		case errors.Is(err, service.ErrTranscodeInvalidProfile), errors.Is(err, service.ErrTranscodeFileNotEncrypted):
+			return response.BadRequest(c, "트랜스코드할 수 없는 요청입니다", err.Error())
+		case errors.Is(err, service.ErrTranscodeBusy):
+			c.Response().Header().Set(echo.HeaderRetryAfter, "1")
+			return response.ServiceUnavailable(c, "진행 중인 트랜스코드가 많습니다", err.Error())
+		case errors.Is(err, service.ErrVolumeOffline):
+			return response.ServiceUnavailable(c, "파일이 저장된 볼륨을 사용할 수 없습니다", err.Error())
+		default:
+			return response.InternalError(c, "트랜스코드에 실패했습니다", err.Error())
+		}
+	}
+	defer stream.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, stream.ContentType)
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": stream.FileName}))
+	header.Set("Trailer", TrailerTranscodeStatus)
+	c.Response().WriteHeader(http.StatusOK)
+
+	// 실패는 서비스가 기록하며, 응답은 이미 시작되었으므로 트레일러로만 알림
+	status := TranscodeStatusComplete
+	if _, err := stream.WriteTo(c.Response()); err != nil {
+		status = TranscodeStatusInterrupted
+	}
+	header.Set(TrailerTranscodeStatus, status)
+
+	return nil
+}
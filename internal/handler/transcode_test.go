@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+	"DataLocker/pkg/crypto"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTranscodeServer 업로드 테스트 환경에 파일 하나를 올리고 트랜스코드 라우트를 가진 서버를 시작합니다
+func setupTranscodeServer(t *testing.T, content []byte) (*httptest.Server, *model.File) {
+	t.Helper()
+	env := setupUploadTestEnv(t)
+	session := env.negotiate(t, content)
+
+	req, err := http.NewRequest(http.MethodPut, env.server.URL+session.UploadURL, bytes.NewReader(content))
+	require.NoError(t, err)
+	req.Header.Set(HeaderEncryptionPassword, uploadTestPassword)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	env.waitHandler(t)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	files, _, err := env.fileRepo.GetAll(context.Background(), 0, 1, repository.SortOption{})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	h := NewTranscodeHandler(service.NewTranscodeService(env.fileRepo, env.storage, crypto.MinIterations, 0, log))
+
+	e := echo.New()
+	e.POST("/api/v1/files/:id/transcode", h.Transcode)
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	return server, files[0]
+}
+
+// postTranscode 트랜스코드 요청을 보냅니다
+func postTranscode(t *testing.T, url, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(url, echo.MIMEApplicationJSON, strings.NewReader(body))
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestTranscodeHandler_StreamsReencryptedFile(t *testing.T) {
+	content := []byte(strings.Repeat("transcoded over http ", 10000))
+	server, file := setupTranscodeServer(t, content)
+
+	resp := postTranscode(t, server.URL+"/api/v1/files/"+fmt.Sprint(file.ID)+"/transcode",
+		`{"source_password":"`+uploadTestPassword+`","target_password":"partner-secret","profile":{"format":"armored"}}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/plain; charset=us-ascii", resp.Header.Get(echo.HeaderContentType))
+
+	_, params, err := mime.ParseMediaType(resp.Header.Get(echo.HeaderContentDisposition))
+	require.NoError(t, err)
+	assert.Equal(t, "upload.txt"+service.EncryptedFileExt, params["filename"])
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, TranscodeStatusComplete, resp.Trailer.Get(TrailerTranscodeStatus))
+
+	var plain bytes.Buffer
+	require.NoError(t, crypto.NewCryptoEngine().DecryptStreamArmored(bytes.NewReader(body), &plain, "partner-secret"))
+	assert.Equal(t, content, plain.Bytes())
+}
+
+func TestTranscodeHandler_ErrorsBeforeStreaming(t *testing.T) {
+	server, file := setupTranscodeServer(t, []byte("transcode errors"))
+	url := server.URL + "/api/v1/files/" + fmt.Sprint(file.ID) + "/transcode"
+
+	testCases := []struct {
+		name     string
+		url      string
+		body     string
+		wantCode int
+	}{
+		{"원본 패스워드 불일치", url, `{"source_password":"wrong","target_password":"t"}`, http.StatusUnauthorized},
+		{"원본 패스워드 없음", url, `{"target_password":"t"}`, http.StatusBadRequest},
+		{"대상 패스워드 없음", url, `{"source_password":"` + uploadTestPassword + `"}`, http.StatusBadRequest},
+		{"잘못된 프로필", url, `{"source_password":"` + uploadTestPassword + `","target_password":"t","profile":{"compression":"zstd"}}`, http.StatusBadRequest},
+		{"잘못된 본문", url, `{"source_password":`, http.StatusBadRequest},
+		{"없는 파일", server.URL + "/api/v1/files/999/transcode", `{"source_password":"s","target_password":"t"}`, http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := postTranscode(t, tc.url, tc.body)
+			assert.Equal(t, tc.wantCode, resp.StatusCode)
+			assert.Equal(t, echo.MIMEApplicationJSON, resp.Header.Get(echo.HeaderContentType))
+		})
+	}
+}
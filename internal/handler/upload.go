@@ -0,0 +1,209 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements the upload body endpoint of the negotiation flow.
+package handler
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"DataLocker/internal/service"
+	"DataLocker/pkg/crypto"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// HeaderEncryptionPassword 업로드 본문 암호화 패스워드를 전달하는 헤더
+const HeaderEncryptionPassword = "X-Encryption-Password"
+
+// 압축 업로드 본문 에러
+var (
+	// errDecodedTooLarge 압축을 해제한 본문이 최대 파일 크기를 넘은 경우
+	errDecodedTooLarge = errors.New("압축을 해제한 본문이 최대 파일 크기를 초과했습니다")
+
+	// errInvalidGzip gzip 헤더, 압축 데이터 또는 체크섬이 올바르지 않은 경우
+	errInvalidGzip = errors.New("gzip 스트림이 올바르지 않습니다")
+)
+
+// uploadResponse 업로드 결과 응답 (파일 정보 + 패스워드 재사용 경고)
+type uploadResponse struct {
+	*fileResponse
+	PasswordReuseWarning *service.PasswordReuseWarning `json:"password_reuse_warning,omitempty"`
+}
+
+// UploadHandler 업로드 본문 핸들러
+type UploadHandler struct {
+	dedupService  service.DedupService
+	uploadService service.UploadService
+	links         *LinkBuilder
+	logger        *logrus.Logger
+	maxDecoded    int64 // gzip 본문을 해제한 크기 상한 (0이면 협상 크기까지만 읽음)
+}
+
+// NewUploadHandler 새로운 업로드 본문 핸들러를 생성합니다
+func NewUploadHandler(dedupService service.DedupService, uploadService service.UploadService, logger *logrus.Logger) *UploadHandler {
+	if logger == nil {
+		panic("logger cannot be nil")
+	}
+
+	return &UploadHandler{
+		dedupService:  dedupService,
+		uploadService: uploadService,
+		logger:        logger,
+	}
+}
+
+// SetLinkBuilder 업로드 결과에 링크를 포함하도록 설정합니다
+//
+// 호출하지 않으면 응답에 links 항목이 없습니다.
+func (h *UploadHandler) SetLinkBuilder(links *LinkBuilder) {
+	h.links = links
+}
+
+// SetMaxDecodedSize Content-Encoding: gzip 본문을 해제한 크기 상한을 설정합니다
+//
+// 전역 본문 크기 제한은 압축된 전송 크기에만 적용되므로, 압축 해제 결과가
+// maxBytes를 넘으면 그 즉시 읽기를 중단하고 413으로 응답합니다.
+func (h *UploadHandler) SetMaxDecodedSize(maxBytes int64) {
+	h.maxDecoded = maxBytes
+}
+
+// Upload 업로드 2단계: 협상에서 발급된 세션으로 본문을 스트리밍 업로드합니다
+//
+// PUT /api/v1/files/upload/:session_id
+// 세션은 한 번만 사용할 수 있으며, 업로드가 중단되면 생성 중이던 레코드와
+// 임시 파일을 정리하므로 다시 협상부터 시작해야 합니다.
+//
+// 패스워드는 헤더에서 바이트로 한 번만 복사한 뒤 헤더를 지워, 이후 미들웨어나
+// 로그가 사본을 만들지 않게 하고 처리가 끝나면 0으로 덮어씁니다.
+func (h *UploadHandler) Upload(c echo.Context) error {
+	password := []byte(c.Request().Header.Get(HeaderEncryptionPassword))
+	c.Request().Header.Del(HeaderEncryptionPassword)
+	defer crypto.ZeroBytes(password)
+
+	if len(password) == 0 {
+		return response.BadRequest(c, "암호화 패스워드가 필요합니다", "")
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(c.Request().Header.Get(echo.HeaderContentEncoding)))
+	if encoding != "" && encoding != "identity" && encoding != "gzip" {
+		return response.BadRequest(c, "지원하지 않는 Content-Encoding입니다", encoding)
+	}
+
+	negotiated, err := h.dedupService.ClaimUploadSession(c.Param("session_id"))
+	if err != nil {
+		return response.NotFound(c, "업로드 세션을 찾을 수 없습니다", err.Error())
+	}
+
+	var body io.Reader = c.Request().Body
+	if encoding == "gzip" {
+		decoded, err := newGzipBody(c.Request().Body, h.maxDecoded)
+		if err != nil {
+			return response.BadRequest(c, "gzip 스트림이 올바르지 않습니다", err.Error())
+		}
+		defer func() {
+			h.logger.WithFields(logrus.Fields{
+				"session_id":    c.Param("session_id"),
+				"wire_bytes":    decoded.wire.n,
+				"decoded_bytes": decoded.n,
+			}).Info("gzip 업로드 본문을 해제했습니다")
+		}()
+		body = decoded
+	}
+
+	result, err := h.uploadService.Upload(c.Request().Context(), &service.UploadRequest{
+		OriginalName:   negotiated.OriginalName,
+		MimeType:       negotiated.MimeType,
+		Size:           negotiated.Size,
+		ChecksumMD5:    negotiated.ChecksumMD5,
+		ExternalID:     negotiated.ExternalID,
+		Description:    negotiated.Description,
+		CustomMetadata: negotiated.CustomMetadata,
+		Password:       password,
+	}, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, errDecodedTooLarge):
+			return response.PayloadTooLarge(c, "압축을 해제한 업로드 본문이 너무 큽니다", err.Error())
+		case errors.Is(err, errInvalidGzip):
+			return response.BadRequest(c, "gzip 스트림이 올바르지 않습니다", err.Error())
+		case errors.Is(err, service.ErrUploadInterrupted):
+			// 연결이 끊긴 경우 응답은 전달되지 않지만 로그에 남김
+			return response.BadRequest(c, "업로드가 중단되었습니다", err.Error())
+		case errors.Is(err, service.ErrUploadMismatch), errors.Is(err, service.ErrInvalidUpload):
+			return response.BadRequest(c, "업로드된 내용이 올바르지 않습니다", err.Error())
+		case errors.Is(err, service.ErrExternalIDInUse):
+			return response.Conflict(c, "외부 참조 ID가 이미 다른 파일에 사용 중입니다", err.Error())
+		case errors.Is(err, service.ErrNoVolumeCapacity):
+			return response.ServiceUnavailable(c, "저장소 용량이 부족합니다", err.Error())
+		case errors.Is(err, service.ErrPathReservation):
+			c.Response().Header().Set(echo.HeaderRetryAfter, "1")
+			return response.ServiceUnavailable(c, "저장 경로를 예약하지 못했습니다", err.Error())
+		default:
+			return response.InternalError(c, "파일 업로드에 실패했습니다", err.Error())
+		}
+	}
+
+	return response.Created(c, &uploadResponse{
+		fileResponse:         withLinks(c, h.links, result.File),
+		PasswordReuseWarning: result.PasswordReuse,
+	}, "파일이 암호화되어 저장되었습니다")
+}
+
+// countingReader 읽은 바이트 수를 세는 reader
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+// Read 읽은 만큼 바이트 수를 더합니다
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// gzipBody 압축을 해제하며 크기 상한을 검사하는 업로드 본문
+type gzipBody struct {
+	wire   *countingReader // 압축된 전송 본문
+	reader *gzip.Reader
+	limit  int64
+	n      int64 // 해제한 바이트 수
+}
+
+// newGzipBody gzip 헤더를 읽어 압축 해제 본문을 생성합니다
+//
+// limit이 0 이하면 크기를 검사하지 않으며, 업로드 서비스가 협상 크기까지만 읽습니다.
+func newGzipBody(body io.Reader, limit int64) (*gzipBody, error) {
+	wire := &countingReader{reader: body}
+	reader, err := gzip.NewReader(wire)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidGzip, err)
+	}
+	return &gzipBody{wire: wire, reader: reader, limit: limit}, nil
+}
+
+// Read 압축을 해제해 읽고, 상한을 넘거나 압축 데이터가 손상되면 에러를 반환합니다
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.limit > 0 && int64(len(p)) > b.limit-b.n+1 {
+		// 상한을 넘는지 알 수 있을 만큼만 해제
+		p = p[:b.limit-b.n+1]
+	}
+
+	n, err := b.reader.Read(p)
+	b.n += int64(n)
+	if b.limit > 0 && b.n > b.limit {
+		return n, fmt.Errorf("%w: 상한 %d 바이트", errDecodedTooLarge, b.limit)
+	}
+
+	var corrupt flate.CorruptInputError
+	if errors.Is(err, gzip.ErrHeader) || errors.Is(err, gzip.ErrChecksum) || errors.As(err, &corrupt) {
+		return n, fmt.Errorf("%w: %w", errInvalidGzip, err)
+	}
+	return n, err
+}
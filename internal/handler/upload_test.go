@@ -0,0 +1,355 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5" //nolint:gosec // 파일 모델의 체크섬 형식(MD5)과 일치
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/database"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const uploadTestPassword = "upload-password"
+
+// uploadTestEnv 실제 서비스와 HTTP 서버로 구성된 업로드 테스트 환경
+type uploadTestEnv struct {
+	server       *httptest.Server
+	db           *gorm.DB
+	handler      *UploadHandler
+	fileRepo     repository.FileRepository
+	dedupService service.DedupService
+	storage      service.StorageService
+	storageDir   string
+	logs         *bytes.Buffer // 핸들러와 서비스가 남긴 로그
+	done         chan struct{} // 업로드 핸들러 종료 알림
+}
+
+// setupUploadTestEnv 업로드 라우트를 가진 테스트 서버를 시작합니다
+func setupUploadTestEnv(t *testing.T) *uploadTestEnv {
+	t.Helper()
+	return setupUploadTestEnvWithSecurity(t, config.SecurityConfig{})
+}
+
+// setupUploadTestEnvWithSecurity 보안 설정(패스워드 재사용 감지 등)을 지정해 테스트 서버를 시작합니다
+func setupUploadTestEnvWithSecurity(t *testing.T, security config.SecurityConfig) *uploadTestEnv {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "upload.db") + "?_foreign_keys=ON&_journal_mode=WAL&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent), NowFunc: database.NowUTC})
+	require.NoError(t, err)
+	_, err = model.MigrateUp(db)
+	require.NoError(t, err)
+
+	env := &uploadTestEnv{
+		db:         db,
+		fileRepo:   repository.NewFileRepository(db),
+		storageDir: filepath.Join(t.TempDir(), "storage"),
+		logs:       &bytes.Buffer{},
+		done:       make(chan struct{}, 1),
+	}
+	log := logrus.New()
+	log.SetOutput(env.logs)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	env.dedupService = service.NewDedupService(config.SecurityConfig{}, env.fileRepo)
+	storage, err := service.NewStorageService(config.StorageConfig{Dir: env.storageDir}, env.fileRepo, repository.NewMemoryFileLocker(0), log)
+	require.NoError(t, err)
+	env.storage = storage
+	reuse, err := service.NewPasswordReuseService(security, repository.NewEncryptionRepository(db), log)
+	require.NoError(t, err)
+	handler := NewUploadHandler(env.dedupService, service.NewUploadService(storage, 0, repository.NewTxManager(db), reuse, log), log)
+	env.handler = handler
+
+	e := echo.New()
+	e.PUT("/api/v1/files/upload/:session_id", func(c echo.Context) error {
+		defer func() { env.done <- struct{}{} }()
+		return handler.Upload(c)
+	})
+	env.server = httptest.NewServer(e)
+
+	t.Cleanup(func() {
+		env.server.Close()
+		if sqlDB, dbErr := db.DB(); dbErr == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	return env
+}
+
+// negotiate 업로드 세션을 발급받습니다
+func (env *uploadTestEnv) negotiate(t *testing.T, content []byte) *service.UploadSession {
+	t.Helper()
+	sum := md5.Sum(content) //nolint:gosec // 파일 모델의 체크섬 형식(MD5)과 일치
+
+	result, err := env.dedupService.Negotiate(context.Background(), &service.NegotiateRequest{
+		OriginalName: "upload.txt",
+		Size:         int64(len(content)),
+		MimeType:     "text/plain",
+		ChecksumMD5:  hex.EncodeToString(sum[:]),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Session)
+	return result.Session
+}
+
+// waitHandler 업로드 핸들러가 끝날 때까지 기다립니다
+func (env *uploadTestEnv) waitHandler(t *testing.T) {
+	t.Helper()
+	select {
+	case <-env.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("업로드 핸들러가 종료되지 않았습니다")
+	}
+}
+
+func TestUploadHandler_Success(t *testing.T) {
+	ctx := context.Background()
+	env := setupUploadTestEnv(t)
+	content := []byte(strings.Repeat("uploaded over http ", 10000))
+	session := env.negotiate(t, content)
+
+	req, err := http.NewRequest(http.MethodPut, env.server.URL+session.UploadURL, bytes.NewReader(content))
+	require.NoError(t, err)
+	req.Header.Set(HeaderEncryptionPassword, uploadTestPassword)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	env.waitHandler(t)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	count, err := env.fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// 세션은 한 번만 사용 가능 (서비스 에러는 노출 정책에 따라 상세로만 전달)
+	status, raw := env.put(t, session, content)
+	assert.Equal(t, http.StatusNotFound, status)
+	var body struct {
+		Message string `json:"message"`
+		Error   struct {
+			Details string `json:"details"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &body))
+	assert.Equal(t, "업로드 세션을 찾을 수 없습니다", body.Message)
+	assert.Empty(t, body.Error.Details)
+}
+
+func TestUploadHandler_ClientDisconnect(t *testing.T) {
+	ctx := context.Background()
+	env := setupUploadTestEnv(t)
+	content := []byte(strings.Repeat("disconnect midway ", 100000))
+	session := env.negotiate(t, content)
+
+	// 본문 절반만 보내고 연결 종료
+	conn, err := net.Dial("tcp", env.server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	header := fmt.Sprintf("PUT %s HTTP/1.1\r\nHost: localhost\r\nContent-Length: %d\r\n%s: %s\r\n\r\n",
+		session.UploadURL, len(content), HeaderEncryptionPassword, uploadTestPassword)
+	_, err = conn.Write([]byte(header))
+	require.NoError(t, err)
+	_, err = conn.Write(content[:len(content)/2])
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	env.waitHandler(t)
+
+	// pending 레코드와 임시 파일이 모두 정리되어야 함
+	count, err := env.fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	entries, err := os.ReadDir(env.storageDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// put 세션 업로드 URL로 본문을 보내고 응답 상태 코드와 본문을 반환합니다
+func (env *uploadTestEnv) put(t *testing.T, session *service.UploadSession, body []byte) (int, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPut, env.server.URL+session.UploadURL, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set(HeaderEncryptionPassword, uploadTestPassword)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	env.waitHandler(t)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, respBody
+}
+
+func TestUploadHandler_PasswordReuseWarning(t *testing.T) {
+	env := setupUploadTestEnvWithSecurity(t, config.SecurityConfig{
+		PasswordFingerprintEnabled: true,
+		PasswordFingerprintSalt:    "00112233445566778899aabbccddeeff",
+		PasswordReuseThreshold:     1,
+	})
+
+	type uploadBody struct {
+		Data struct {
+			ID                   uint                          `json:"id"`
+			PasswordReuseWarning *service.PasswordReuseWarning `json:"password_reuse_warning"`
+		} `json:"data"`
+	}
+
+	// 처음 쓰는 패스워드는 경고 없음
+	first := []byte(strings.Repeat("first file ", 1000))
+	status, raw := env.put(t, env.negotiate(t, first), first)
+	require.Equal(t, http.StatusCreated, status)
+	var firstResp uploadBody
+	require.NoError(t, json.Unmarshal(raw, &firstResp))
+	assert.Nil(t, firstResp.Data.PasswordReuseWarning)
+	assert.NotContains(t, string(raw), "password_reuse_warning")
+
+	// 지문은 메타데이터에 저장되고 응답에는 노출되지 않음
+	var fingerprint string
+	require.NoError(t, env.db.Model(&model.EncryptionMetadata{}).
+		Where("file_id = ?", firstResp.Data.ID).Pluck("password_fingerprint", &fingerprint).Error)
+	assert.Len(t, fingerprint, model.PasswordFingerprintHexLength)
+	assert.NotContains(t, string(raw), fingerprint)
+
+	// 같은 패스워드로 다른 파일을 올리면 경고
+	second := []byte(strings.Repeat("second file ", 1000))
+	status, raw = env.put(t, env.negotiate(t, second), second)
+	require.Equal(t, http.StatusCreated, status)
+	var secondResp uploadBody
+	require.NoError(t, json.Unmarshal(raw, &secondResp))
+	require.NotNil(t, secondResp.Data.PasswordReuseWarning)
+	assert.Equal(t, int64(1), secondResp.Data.PasswordReuseWarning.ReuseCount)
+}
+
+// gzipBytes content를 gzip으로 압축합니다
+func gzipBytes(t *testing.T, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// putGzip 세션 업로드 URL로 gzip 본문을 보내고 응답 상태 코드와 본문을 반환합니다
+func (env *uploadTestEnv) putGzip(t *testing.T, session *service.UploadSession, body []byte) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPut, env.server.URL+session.UploadURL, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set(HeaderEncryptionPassword, uploadTestPassword)
+	req.Header.Set(echo.HeaderContentEncoding, "gzip")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	env.waitHandler(t)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(respBody)
+}
+
+func TestUploadHandler_GzipBody(t *testing.T) {
+	ctx := context.Background()
+	env := setupUploadTestEnv(t)
+	env.handler.SetMaxDecodedSize(1 << 20)
+	content := []byte(strings.Repeat("compressed upload ", 10000))
+	session := env.negotiate(t, content)
+
+	status, _ := env.putGzip(t, session, gzipBytes(t, content))
+	assert.Equal(t, http.StatusCreated, status)
+
+	// 협상 크기와 체크섬은 해제한 원본 기준
+	files, err := env.fileRepo.GetByIDs(ctx, []uint{1})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, int64(len(content)), files[0].Size)
+
+	// 전송 크기와 해제 크기를 필드로 남김
+	assert.Contains(t, env.logs.String(), `"decoded_bytes":`+fmt.Sprint(len(content)))
+	assert.Contains(t, env.logs.String(), `"session_id":"`+session.ID+`"`)
+}
+
+func TestUploadHandler_GzipBomb(t *testing.T) {
+	ctx := context.Background()
+	env := setupUploadTestEnv(t)
+	env.handler.SetMaxDecodedSize(64 << 10)
+
+	// 협상 크기는 맞지만 해제하면 상한(64KB)을 넘는 본문
+	content := make([]byte, 4<<20)
+	session := env.negotiate(t, content)
+	body := gzipBytes(t, content)
+	require.Less(t, len(body), 64<<10)
+
+	status, _ := env.putGzip(t, session, body)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+
+	count, err := env.fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestUploadHandler_InvalidGzip(t *testing.T) {
+	env := setupUploadTestEnv(t)
+	content := []byte(strings.Repeat("corrupted upload ", 10000))
+
+	// gzip 헤더가 아닌 본문
+	status, respBody := env.putGzip(t, env.negotiate(t, content), content)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, respBody, "gzip")
+
+	// 압축 데이터 손상 (CRC 불일치 또는 잘못된 deflate 블록)
+	body := gzipBytes(t, content)
+	for i := len(body) / 2; i < len(body)/2+16; i++ {
+		body[i] ^= 0xff
+	}
+	status, respBody = env.putGzip(t, env.negotiate(t, content), body)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, respBody, "gzip")
+}
+
+func TestUploadHandler_UnsupportedEncoding(t *testing.T) {
+	handler := NewUploadHandler(&stubDedupService{}, nil, logrus.New())
+	c, rec := createTestContext(http.MethodPut, "/api/v1/files/upload/abc")
+	c.Request().Header.Set(HeaderEncryptionPassword, uploadTestPassword)
+	c.Request().Header.Set(echo.HeaderContentEncoding, "br")
+
+	require.NoError(t, handler.Upload(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestUploadHandler_MissingPassword(t *testing.T) {
+	handler := NewUploadHandler(&stubDedupService{}, nil, logrus.New())
+	c, rec := createTestContext(http.MethodPut, "/api/v1/files/upload/abc")
+
+	require.NoError(t, handler.Upload(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
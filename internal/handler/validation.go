@@ -0,0 +1,134 @@
+// Package handler provides HTTP request handlers for DataLocker API endpoints.
+// This file implements directory validation endpoints with paged and NDJSON result retrieval.
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 검증 결과 응답 형식
+const (
+	validationFormatJSON   = "json"
+	validationFormatNDJSON = "ndjson"
+
+	// mimeApplicationNDJSON 줄 단위 JSON 스트리밍 응답의 Content-Type
+	mimeApplicationNDJSON = "application/x-ndjson"
+)
+
+// ValidationHandler 디렉터리 검증 핸들러
+type ValidationHandler struct {
+	sessionService service.ValidationSessionService
+}
+
+// NewValidationHandler 새로운 디렉터리 검증 핸들러를 생성합니다
+func NewValidationHandler(sessionService service.ValidationSessionService) *ValidationHandler {
+	return &ValidationHandler{
+		sessionService: sessionService,
+	}
+}
+
+// ValidateDirectory 디렉터리를 검증하고 요약과 결과 세션 ID를 반환합니다
+//
+// POST /api/v1/validations
+// 본문: {"directory_path": "...", "files": [...]}
+// 개별 파일 결과는 GET /api/v1/validations/:id/results로 조회합니다.
+func (h *ValidationHandler) ValidateDirectory(c echo.Context) error {
+	var req service.ValidationRequest
+	if err := c.Bind(&req); err != nil {
+		return response.BadRequest(c, "잘못된 검증 요청입니다", err.Error())
+	}
+
+	if req.Type != "" && req.Type != service.ItemTypeDirectory {
+		return response.BadRequest(c, "잘못된 검증 요청입니다", "디렉터리 검증만 지원합니다")
+	}
+
+	result, err := h.sessionService.ValidateDirectory(c.Request().Context(), req.DirectoryPath, req.Files)
+	if err != nil {
+		return response.InternalError(c, "디렉터리 검증에 실패했습니다", err.Error())
+	}
+
+	return response.Created(c, result, "디렉터리 검증이 완료되었습니다")
+}
+
+// Results 검증 세션의 개별 파일 결과를 반환합니다
+//
+// GET /api/v1/validations/:id/results?page=&page_size=
+// format=ndjson이면 페이지 없이 전체 결과를 한 줄에 하나씩 스트리밍합니다.
+func (h *ValidationHandler) Results(c echo.Context) error {
+	sessionID := c.Param("id")
+
+	format := c.QueryParam("format")
+	switch format {
+	case "", validationFormatJSON:
+	case validationFormatNDJSON:
+		return h.streamResults(c, sessionID)
+	default:
+		return response.BadRequest(c, "잘못된 응답 형식입니다", "format은 json 또는 ndjson이어야 합니다")
+	}
+
+	page, err := parseOptionalInt(c.QueryParam("page"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 페이지입니다", err.Error())
+	}
+	pageSize, err := parseOptionalInt(c.QueryParam("page_size"))
+	if err != nil {
+		return response.BadRequest(c, "잘못된 페이지 크기입니다", err.Error())
+	}
+
+	result, err := h.sessionService.Results(c.Request().Context(), sessionID, page, pageSize)
+	if err != nil {
+		if errors.Is(err, service.ErrValidationSessionNotFound) {
+			return response.NotFound(c, err.Error(), "")
+		}
+		return response.InternalError(c, "검증 결과 조회에 실패했습니다", err.Error())
+	}
+
+	return response.Success(c, result, "검증 결과 조회가 완료되었습니다")
+}
+
+// streamResults 개별 결과를 NDJSON으로 스트리밍합니다
+//
+// 첫 줄을 쓰기 전의 에러만 상태 코드로 알릴 수 있으므로, 그 뒤의 에러는 응답을
+// 중단하는 것으로 처리합니다 (클라이언트는 줄 수와 요약의 total_files를 비교).
+func (h *ValidationHandler) streamResults(c echo.Context, sessionID string) error {
+	res := c.Response()
+	encoder := json.NewEncoder(res)
+	started := false
+
+	err := h.sessionService.StreamResults(c.Request().Context(), sessionID, func(item *service.FileValidationResult) error {
+		if !started {
+			res.Header().Set(echo.HeaderContentType, mimeApplicationNDJSON)
+			res.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	})
+
+	switch {
+	case started:
+		if err != nil {
+			c.Logger().Warnf("검증 결과 스트리밍 중단: %v", err)
+		}
+		return nil
+	case errors.Is(err, service.ErrValidationSessionNotFound):
+		return response.NotFound(c, err.Error(), "")
+	case err != nil:
+		return response.InternalError(c, "검증 결과 조회에 실패했습니다", err.Error())
+	default:
+		// 결과가 없는 세션은 빈 스트림
+		res.Header().Set(echo.HeaderContentType, mimeApplicationNDJSON)
+		return c.NoContent(http.StatusOK)
+	}
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"DataLocker/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubValidationSessionService 고정된 결과를 반환하는 검증 세션 서비스
+type stubValidationSessionService struct {
+	items         []service.FileValidationResult
+	err           error
+	page          int
+	pageSize      int
+	directoryPath string
+}
+
+func (s *stubValidationSessionService) ValidateDirectory(_ context.Context, directoryPath string, files []service.FileInfo) (*service.ValidationResult, error) {
+	s.directoryPath = directoryPath
+	if s.err != nil {
+		return nil, s.err
+	}
+	expiresAt := time.Now().Add(service.ValidationSessionTTL)
+	return &service.ValidationResult{
+		Type:       service.ItemTypeDirectory,
+		TotalFiles: len(files),
+		SessionID:  "abc",
+		ExpiresAt:  &expiresAt,
+	}, nil
+}
+
+func (s *stubValidationSessionService) Results(_ context.Context, sessionID string, page, pageSize int) (*service.ValidationResultPage, error) {
+	s.page, s.pageSize = page, pageSize
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &service.ValidationResultPage{SessionID: sessionID, Items: s.items, Total: int64(len(s.items)), Page: page}, nil
+}
+
+func (s *stubValidationSessionService) StreamResults(_ context.Context, _ string, fn func(*service.FileValidationResult) error) error {
+	if s.err != nil {
+		return s.err
+	}
+	for i := range s.items {
+		if err := fn(&s.items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stubValidationSessionService) PruneExpired(_ context.Context) (int64, error) {
+	return 0, nil
+}
+
+// newResultsContext 세션 ID가 설정된 검증 결과 조회 컨텍스트
+func newResultsContext(query string) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := createTestContext(http.MethodGet, "/api/v1/validations/abc/results"+query)
+	c.SetParamNames("id")
+	c.SetParamValues("abc")
+	return c, rec
+}
+
+func TestValidationHandler_ValidateDirectory(t *testing.T) {
+	stub := &stubValidationSessionService{}
+	body := `{"directory_path":"docs","files":[{"name":"a.txt","relative_path":"docs/a.txt","size":10,"mime_type":"text/plain"}]}`
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/validations", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, NewValidationHandler(stub).ValidateDirectory(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "docs", stub.directoryPath)
+	assert.Contains(t, rec.Body.String(), `"session_id":"abc"`)
+	assert.Contains(t, rec.Body.String(), `"total_files":1`)
+	assert.NotContains(t, rec.Body.String(), `"file_results"`)
+
+	// 파일 단건 검증은 이 엔드포인트에서 받지 않음
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/validations", strings.NewReader(`{"type":"file"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	require.NoError(t, NewValidationHandler(stub).ValidateDirectory(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestValidationHandler_Results(t *testing.T) {
+	stub := &stubValidationSessionService{items: []service.FileValidationResult{
+		{FileName: "a.txt", RelativePath: "docs/a.txt", IsValid: true},
+	}}
+
+	c, rec := newResultsContext("?page=2&page_size=50")
+	require.NoError(t, NewValidationHandler(stub).Results(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, stub.page)
+	assert.Equal(t, 50, stub.pageSize)
+	assert.Contains(t, rec.Body.String(), `"relative_path":"docs/a.txt"`)
+
+	testCases := []struct {
+		name     string
+		query    string
+		err      error
+		wantCode int
+	}{
+		{name: "잘못된 페이지", query: "?page=x", wantCode: http.StatusBadRequest},
+		{name: "잘못된 형식", query: "?format=xml", wantCode: http.StatusBadRequest},
+		{name: "만료된 세션", err: fmt.Errorf("%w: abc", service.ErrValidationSessionNotFound), wantCode: http.StatusNotFound},
+		{name: "저장소 오류", err: fmt.Errorf("db error"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := newResultsContext(tc.query)
+			require.NoError(t, NewValidationHandler(&stubValidationSessionService{err: tc.err}).Results(c))
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestValidationHandler_ResultsNDJSON(t *testing.T) {
+	stub := &stubValidationSessionService{items: []service.FileValidationResult{
+		{FileName: "a.txt", RelativePath: "docs/a.txt", IsValid: true},
+		{FileName: "b.zip", RelativePath: "docs/b.zip", Errors: []string{"지원하지 않는 파일 형식입니다"}},
+	}}
+
+	c, rec := newResultsContext("?format=ndjson")
+	require.NoError(t, NewValidationHandler(stub).Results(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, mimeApplicationNDJSON, rec.Header().Get(echo.HeaderContentType))
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"relative_path":"docs/a.txt"`)
+	assert.Contains(t, lines[1], `"is_valid":false`)
+
+	// 빈 세션은 빈 스트림
+	c, rec = newResultsContext("?format=ndjson")
+	require.NoError(t, NewValidationHandler(&stubValidationSessionService{}).Results(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	// 첫 줄 전의 에러는 상태 코드로 전달
+	c, rec = newResultsContext("?format=ndjson")
+	require.NoError(t, NewValidationHandler(&stubValidationSessionService{err: service.ErrValidationSessionNotFound}).Results(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
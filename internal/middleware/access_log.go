@@ -0,0 +1,274 @@
+// Package middleware provides HTTP middleware components for DataLocker server.
+// This file implements status-class sampling of access logs with periodic summaries of skipped entries.
+package middleware
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 요청 로그 샘플링 관련 상수
+const (
+	// failedChainTTL 에러가 난 요청 ID의 후속 요청을 강제로 기록하는 기간
+	failedChainTTL = 10 * time.Minute
+
+	// failedChainMaxKeys 추적하는 요청 ID 수 상한 (넘으면 가장 오래된 ID부터 제거)
+	failedChainMaxKeys = 10000
+)
+
+// 상태 코드 그룹 (요약 로그의 키)
+const (
+	StatusGroupSuccess     = "2xx" // 1xx~3xx
+	StatusGroupClientError = "4xx"
+	StatusGroupServerError = "5xx"
+)
+
+// 상태 코드 그룹 위치 (statusGroups 순서)
+const (
+	groupSuccess = iota
+	groupClientError
+	groupServerError
+	groupCount
+)
+
+// statusGroups 그룹 위치별 이름
+var statusGroups = [groupCount]string{StatusGroupSuccess, StatusGroupClientError, StatusGroupServerError}
+
+// failedChain 에러가 난 요청 ID와 강제 기록 만료 시각
+type failedChain struct {
+	requestID string
+	expiresAt time.Time
+}
+
+// AccessLogSampler 상태 코드 그룹별로 요청 로그를 샘플링하는 기록 판단기
+//
+// 그룹마다 N건 중 첫 1건을 기록하고 나머지는 생략한 수만 세며, 주기마다 생략한
+// 수를 요약 로그로 남겨 전체 요청 수를 복원할 수 있게 합니다. 핸들러가 에러를
+// 반환했거나 5xx로 끝난 요청의 ID는 일정 시간 기억해, 같은 ID로 이어지는
+// 재시도와 후속 요청은 비율과 관계없이 기록합니다.
+type AccessLogSampler struct {
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	rates    [groupCount]int
+	interval time.Duration
+	seen     [groupCount]uint64 // 샘플링 위치 (그룹별 요청 순번)
+	skipped  [groupCount]int64  // 마지막 요약 이후 생략한 수
+	chains   map[string]*list.Element
+	lru      *list.List // 앞쪽이 최근에 에러가 난 요청 ID
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	started   bool
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewAccessLogSampler 설정의 비율로 요청 로그를 샘플링하는 판단기를 생성합니다
+//
+// 요약 로그는 Start를 호출해야 주기적으로 남습니다.
+func NewAccessLogSampler(cfg config.AccessLogConfig, logger *logrus.Logger) *AccessLogSampler {
+	if logger == nil {
+		panic("logger cannot be nil")
+	}
+
+	s := &AccessLogSampler{
+		logger: logger,
+		clock:  clock.Real{},
+		chains: make(map[string]*list.Element),
+		lru:    list.New(),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	s.SetConfig(cfg)
+	return s
+}
+
+// SetConfig 샘플링 비율과 요약 주기를 바꿉니다 (1 미만이면 기본값)
+//
+// 바뀐 비율은 다음 요청부터, 요약 주기는 진행 중인 주기가 끝난 뒤부터 적용됩니다.
+func (s *AccessLogSampler) SetConfig(cfg config.AccessLogConfig) {
+	rates := [groupCount]int{
+		orDefault(cfg.SuccessSampleRate, config.DefaultAccessLogSuccessSampleRate),
+		orDefault(cfg.ClientErrorSampleRate, config.DefaultAccessLogClientErrorSampleRate),
+		orDefault(cfg.ServerErrorSampleRate, config.DefaultAccessLogServerErrorSampleRate),
+	}
+	seconds := orDefault(cfg.SummarySeconds, config.DefaultAccessLogSummarySeconds)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rates = rates
+	s.interval = time.Duration(seconds) * time.Second
+}
+
+// Sample 요청 로그를 기록할지와 기록한 로그 한 건이 대표하는 요청 수를 반환합니다
+//
+// failed는 핸들러가 에러를 반환했는지 여부이며, 에러가 났거나 같은 요청 ID의
+// 이전 요청이 실패했으면 대표 수 1로 항상 기록합니다.
+func (s *AccessLogSampler) Sample(status int, requestID string, failed bool) (bool, int) {
+	group := statusGroupIndex(status)
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chained := s.inFailedChainLocked(requestID, now)
+	if failed || group == groupServerError {
+		s.rememberFailureLocked(requestID, now)
+	}
+	if failed || chained {
+		return true, 1
+	}
+
+	rate := s.rates[group]
+	position := s.seen[group]
+	s.seen[group]++
+	if position%uint64(rate) == 0 {
+		return true, rate
+	}
+
+	s.skipped[group]++
+	return false, rate
+}
+
+// Start 주기마다 생략한 로그 수를 요약하는 고루틴을 시작합니다 (두 번째 호출부터는 무시)
+func (s *AccessLogSampler) Start() {
+	s.startOnce.Do(func() {
+		s.mu.Lock()
+		s.started = true
+		s.mu.Unlock()
+		go s.run()
+	})
+}
+
+// Stop 요약 고루틴을 멈추고 남은 생략 수를 마지막으로 요약합니다 (여러 번 호출해도 안전)
+func (s *AccessLogSampler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+	if started {
+		<-s.done
+	} else {
+		s.Summarize()
+	}
+}
+
+// run 요약 주기마다 Summarize를 호출합니다
+func (s *AccessLogSampler) run() {
+	defer close(s.done)
+
+	for {
+		s.mu.Lock()
+		interval := s.interval
+		s.mu.Unlock()
+
+		timer := s.clock.NewTimer(interval)
+		select {
+		case <-timer.C():
+			s.Summarize()
+		case <-s.stop:
+			timer.Stop()
+			s.Summarize()
+			return
+		}
+	}
+}
+
+// Summarize 마지막 요약 이후 생략한 로그 수를 그룹별로 기록하고 초기화합니다
+//
+// 생략한 로그가 없으면 아무것도 기록하지 않습니다.
+func (s *AccessLogSampler) Summarize() {
+	s.mu.Lock()
+	skipped := s.skipped
+	rates := s.rates
+	s.skipped = [groupCount]int64{}
+	s.mu.Unlock()
+
+	var total int64
+	skippedFields := make(logrus.Fields, len(statusGroups))
+	rateFields := make(logrus.Fields, len(statusGroups))
+	for i, group := range statusGroups {
+		total += skipped[i]
+		skippedFields[group] = skipped[i]
+		rateFields[group] = rates[i]
+	}
+	if total == 0 {
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"skipped":      skippedFields,
+		"skipped_all":  total,
+		"sample_rates": rateFields,
+	}).Info("샘플링으로 생략한 요청 로그 요약")
+}
+
+// inFailedChainLocked 요청 ID가 최근 실패한 요청 체인에 속하는지 확인합니다 (만료된 항목은 제거)
+func (s *AccessLogSampler) inFailedChainLocked(requestID string, now time.Time) bool {
+	if requestID == "" {
+		return false
+	}
+
+	elem, ok := s.chains[requestID]
+	if !ok {
+		return false
+	}
+	if now.After(elem.Value.(*failedChain).expiresAt) {
+		s.lru.Remove(elem)
+		delete(s.chains, requestID)
+		return false
+	}
+	return true
+}
+
+// rememberFailureLocked 실패한 요청 ID를 기억해 후속 요청을 강제로 기록하게 합니다
+func (s *AccessLogSampler) rememberFailureLocked(requestID string, now time.Time) {
+	if requestID == "" {
+		return
+	}
+
+	expiresAt := now.Add(failedChainTTL)
+	if elem, ok := s.chains[requestID]; ok {
+		elem.Value.(*failedChain).expiresAt = expiresAt
+		s.lru.MoveToFront(elem)
+		return
+	}
+
+	s.chains[requestID] = s.lru.PushFront(&failedChain{requestID: requestID, expiresAt: expiresAt})
+	for s.lru.Len() > failedChainMaxKeys {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.chains, oldest.Value.(*failedChain).requestID)
+	}
+}
+
+// statusGroupIndex 상태 코드의 그룹 위치를 반환합니다
+func statusGroupIndex(status int) int {
+	switch {
+	case status >= HTTPInternalServerError:
+		return groupServerError
+	case status >= HTTPErrorStatusThreshold:
+		return groupClientError
+	default:
+		return groupSuccess
+	}
+}
+
+// orDefault 1 미만이면 기본값을 반환합니다
+func orDefault(value, defaultValue int) int {
+	if value < 1 {
+		return defaultValue
+	}
+	return value
+}
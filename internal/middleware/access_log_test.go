@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/pkg/clock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSampler 로그를 버퍼에 JSON으로 남기는 샘플링 판단기를 생성합니다
+func newTestSampler(t *testing.T, cfg config.AccessLogConfig) (*AccessLogSampler, *bytes.Buffer) {
+	t.Helper()
+
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	return NewAccessLogSampler(cfg, logger), &logs
+}
+
+// logLines 버퍼의 JSON 로그를 줄 단위로 해석합니다
+func logLines(t *testing.T, logs *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestAccessLogSampler_RatesByStatusGroup(t *testing.T) {
+	// 0 이하의 값은 기본값 (2xx 1/100, 4xx 1/10, 5xx 전부)
+	sampler, logs := newTestSampler(t, config.AccessLogConfig{})
+
+	count := func(status, requests int) (recorded int) {
+		for range requests {
+			record, rate := sampler.Sample(status, "", false)
+			if record {
+				recorded++
+				assert.Equal(t, map[int]int{200: 100, 404: 10, 500: 1}[status], rate)
+			}
+		}
+		return recorded
+	}
+
+	assert.Equal(t, 10, count(http.StatusOK, 1000))
+	assert.Equal(t, 10, count(http.StatusNotFound, 100))
+	assert.Equal(t, 5, count(http.StatusInternalServerError, 5))
+
+	sampler.Summarize()
+	lines := logLines(t, logs)
+	require.Len(t, lines, 1)
+	assert.Equal(t, map[string]any{"2xx": 990.0, "4xx": 90.0, "5xx": 0.0}, lines[0]["skipped"])
+	assert.Equal(t, 1080.0, lines[0]["skipped_all"])
+
+	// 요약 후 초기화되어 생략한 로그가 없으면 기록하지 않음
+	sampler.Summarize()
+	assert.Len(t, logLines(t, logs), 1)
+}
+
+func TestAccessLogSampler_FailedChain(t *testing.T) {
+	sampler, _ := newTestSampler(t, config.AccessLogConfig{SuccessSampleRate: 1000})
+	clk := clock.NewFake(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC))
+	sampler.clock = clk
+
+	// 첫 요청은 항상 기록되므로 샘플링 위치를 넘김
+	record, _ := sampler.Sample(http.StatusOK, "", false)
+	require.True(t, record)
+
+	record, rate := sampler.Sample(http.StatusOK, "req-1", true)
+	assert.True(t, record, "에러가 난 요청은 항상 기록")
+	assert.Equal(t, 1, rate)
+
+	record, rate = sampler.Sample(http.StatusOK, "req-1", false)
+	assert.True(t, record, "같은 요청 ID의 후속 요청도 기록")
+	assert.Equal(t, 1, rate)
+
+	record, _ = sampler.Sample(http.StatusOK, "req-2", false)
+	assert.False(t, record)
+
+	// 5xx 응답도 체인을 시작
+	_, _ = sampler.Sample(http.StatusServiceUnavailable, "req-2", false)
+	record, _ = sampler.Sample(http.StatusOK, "req-2", false)
+	assert.True(t, record)
+
+	clk.Advance(failedChainTTL + time.Second)
+	record, _ = sampler.Sample(http.StatusOK, "req-1", false)
+	assert.False(t, record, "기간이 지나면 다시 샘플링")
+}
+
+func TestAccessLogSampler_SetConfig(t *testing.T) {
+	sampler, _ := newTestSampler(t, config.AccessLogConfig{SuccessSampleRate: 1000})
+
+	_, rate := sampler.Sample(http.StatusOK, "", false)
+	assert.Equal(t, 1000, rate)
+
+	sampler.SetConfig(config.AccessLogConfig{SuccessSampleRate: 1})
+	for range 3 {
+		record, rate := sampler.Sample(http.StatusOK, "", false)
+		assert.True(t, record)
+		assert.Equal(t, 1, rate)
+	}
+}
+
+func TestAccessLogSampler_StopSummarizes(t *testing.T) {
+	sampler, logs := newTestSampler(t, config.AccessLogConfig{SuccessSampleRate: 10, SummarySeconds: 3600})
+	sampler.Start()
+
+	for range 25 {
+		sampler.Sample(http.StatusOK, "", false)
+	}
+
+	sampler.Stop()
+	sampler.Stop()
+
+	lines := logLines(t, logs)
+	require.Len(t, lines, 1, "종료 시 남은 생략 수를 한 번만 요약")
+	assert.Equal(t, 22.0, lines[0]["skipped_all"])
+}
+
+func TestAccessLogSampler_PeriodicSummary(t *testing.T) {
+	sampler, logs := newTestSampler(t, config.AccessLogConfig{SuccessSampleRate: 10, SummarySeconds: 3600})
+	clk := clock.NewFake(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC))
+	sampler.clock = clk
+	sampler.Start()
+
+	for range 25 {
+		sampler.Sample(http.StatusOK, "", false)
+	}
+
+	// 주기가 끝나면 요약하고 다음 주기 타이머를 다시 검
+	clk.BlockUntil(1)
+	clk.Advance(time.Hour)
+	clk.BlockUntil(1)
+
+	lines := logLines(t, logs)
+	require.Len(t, lines, 1)
+	assert.Equal(t, 22.0, lines[0]["skipped_all"])
+
+	sampler.Stop()
+	assert.Len(t, logLines(t, logs), 1, "생략한 로그가 없으면 요약하지 않음")
+}
+
+func TestRequestLoggingMiddleware_Sampling(t *testing.T) {
+	sampler, logs := newTestSampler(t, config.AccessLogConfig{SuccessSampleRate: 2})
+
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(RequestLoggingMiddleware(sampler.logger, sampler))
+	e.GET("/ok", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	e.GET("/fail", func(c echo.Context) error { return errors.New("boom") })
+
+	serve := func(path, id string) {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		if id != "" {
+			req.Header.Set(echo.HeaderXRequestID, id)
+		}
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	for range 4 {
+		serve("/ok", "")
+	}
+	lines := logLines(t, logs)
+	require.Len(t, lines, 2)
+	assert.Equal(t, 2.0, lines[0]["sample_rate"])
+
+	// 실패한 요청과 같은 요청 ID로 이어진 재시도는 모두 기록
+	serve("/fail", "trace-1")
+	serve("/ok", "trace-1")
+	serve("/ok", "trace-1")
+
+	lines = logLines(t, logs)
+	require.Len(t, lines, 5)
+	for _, line := range lines[2:] {
+		assert.Equal(t, "trace-1", line["request_id"])
+		assert.Equal(t, 1.0, line["sample_rate"])
+	}
+}
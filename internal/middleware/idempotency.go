@@ -0,0 +1,167 @@
+// Package middleware provides HTTP middleware components for DataLocker server.
+// This file implements Idempotency-Key handling that replays the stored response of a retried request.
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// 멱등 처리 헤더
+const (
+	// HeaderIdempotencyKey 클라이언트가 재시도해도 한 번만 처리할 요청에 붙이는 키
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	// HeaderIdempotentReplayed 저장된 응답을 다시 보낸 경우 "true"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// 멱등 처리 크기 제한
+const (
+	// idempotencyMaxBodyBytes 요청 해시에 포함하는 본문의 최대 크기 (협상 요청 같은 JSON 본문)
+	idempotencyMaxBodyBytes = 1 << 20
+
+	// idempotencyMaxResponseBytes 저장하는 응답의 최대 크기 (넘으면 저장하지 않고 예약 해제)
+	idempotencyMaxResponseBytes = 1 << 20
+)
+
+// IdempotencyMiddleware Idempotency-Key가 있는 요청을 한 번만 처리하고 재요청에는 저장된 응답을 돌려줍니다
+//
+// 키가 없는 요청은 그대로 처리합니다. 요청은 메서드, 경로, (hashBody이면) 본문의
+// SHA-256으로 구분하며, 같은 키로 다른 요청이 오거나 같은 요청이 아직 처리 중이면
+// 409를 반환합니다. 2xx 응답만 저장하고, 그 외의 결과는 예약을 풀어 같은 키로
+// 다시 시도할 수 있게 합니다.
+//
+// 스트리밍 업로드처럼 본문을 미리 읽을 수 없는 라우트는 hashBody를 끄고, 경로에
+// 요청 내용을 고정하는 값(업로드 세션 ID 등)이 있어야 합니다.
+func IdempotencyMiddleware(svc service.IdempotencyService, logger *logrus.Logger, hashBody bool) echo.MiddlewareFunc {
+	if svc == nil {
+		panic("멱등 처리 서비스가 필요합니다")
+	}
+
+	if logger == nil {
+		panic("logger cannot be nil")
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+
+			requestHash, err := idempotencyRequestHash(c, hashBody)
+			if err != nil {
+				return response.BadRequest(c, "요청 본문을 읽을 수 없습니다", err.Error())
+			}
+
+			ctx := c.Request().Context()
+			record, err := svc.Begin(ctx, key, requestHash)
+			if err != nil {
+				switch {
+				case errors.Is(err, service.ErrInvalidIdempotencyKey):
+					return response.BadRequest(c, err.Error(), "")
+				case errors.Is(err, service.ErrIdempotencyKeyReused), errors.Is(err, service.ErrIdempotencyKeyInFlight):
+					return response.Conflict(c, err.Error(), "")
+				default:
+					return response.InternalError(c, "Idempotency-Key를 확인하지 못했습니다", err.Error())
+				}
+			}
+
+			if record.IsCompleted() {
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				return c.Blob(record.StatusCode, record.ContentType, record.Body)
+			}
+
+			capture := &responseCapture{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = capture
+
+			err = next(c)
+
+			// 클라이언트 연결이 끊겨도 처리 결과는 남겨야 재시도에 응답할 수 있음
+			saveCtx := context.WithoutCancel(ctx)
+			entry := logger.WithFields(logrus.Fields{
+				"idempotency_key": key,
+				"actor":           model.ActorFromContext(ctx),
+			})
+
+			status := c.Response().Status
+			if err != nil || status < http.StatusOK || status >= http.StatusMultipleChoices || capture.overflow {
+				if releaseErr := svc.Release(saveCtx, record); releaseErr != nil {
+					entry.WithError(releaseErr).Warn("Idempotency-Key 예약을 풀지 못했습니다")
+				}
+				return err
+			}
+
+			contentType := c.Response().Header().Get(echo.HeaderContentType)
+			if completeErr := svc.Complete(saveCtx, record, status, contentType, capture.body.Bytes()); completeErr != nil {
+				entry.WithError(completeErr).Warn("Idempotency-Key 응답을 저장하지 못했습니다")
+			}
+			return nil
+		}
+	}
+}
+
+// idempotencyRequestHash 메서드, 경로, (hashBody이면) 본문으로 요청 해시를 계산합니다
+//
+// 읽은 본문은 핸들러가 다시 읽을 수 있도록 되돌려 둡니다.
+func idempotencyRequestHash(c echo.Context, hashBody bool) (string, error) {
+	req := c.Request()
+
+	h := sha256.New()
+	h.Write([]byte(req.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(req.URL.Path))
+	h.Write([]byte{0})
+
+	if hashBody && req.Body != nil {
+		body, err := io.ReadAll(io.LimitReader(req.Body, idempotencyMaxBodyBytes+1))
+		if err != nil {
+			return "", err
+		}
+		if len(body) > idempotencyMaxBodyBytes {
+			return "", errors.New("Idempotency-Key를 사용하는 요청의 본문이 너무 큽니다")
+		}
+		h.Write(body)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// responseCapture 클라이언트에 보내는 응답 본문을 저장용으로 함께 기록하는 Writer
+type responseCapture struct {
+	http.ResponseWriter
+	body     bytes.Buffer
+	overflow bool // 본문이 idempotencyMaxResponseBytes를 넘어 저장하지 않음
+}
+
+// Write 본문을 클라이언트에 쓰면서 크기 제한 안이면 함께 기록합니다
+func (w *responseCapture) Write(b []byte) (int, error) {
+	if !w.overflow {
+		if w.body.Len()+len(b) > idempotencyMaxResponseBytes {
+			w.overflow = true
+			w.body = bytes.Buffer{}
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap http.ResponseController가 Flush 등을 원래 Writer에서 찾도록 반환합니다
+func (w *responseCapture) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryIdempotencyService 키별 기록을 메모리에 두는 테스트용 멱등 처리 서비스
+type memoryIdempotencyService struct {
+	mu      sync.Mutex
+	nextID  uint
+	records map[string]*model.IdempotencyRecord
+}
+
+func newMemoryIdempotencyService() *memoryIdempotencyService {
+	return &memoryIdempotencyService{records: make(map[string]*model.IdempotencyRecord)}
+}
+
+func (s *memoryIdempotencyService) Begin(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[key]; ok {
+		switch {
+		case existing.RequestHash != requestHash:
+			return nil, service.ErrIdempotencyKeyReused
+		case !existing.IsCompleted():
+			return nil, service.ErrIdempotencyKeyInFlight
+		}
+		replay := *existing
+		return &replay, nil
+	}
+
+	s.nextID++
+	record := &model.IdempotencyRecord{ID: s.nextID, Key: key, Owner: model.ActorFromContext(ctx), RequestHash: requestHash}
+	s.records[key] = record
+	return record, nil
+}
+
+func (s *memoryIdempotencyService) Complete(_ context.Context, record *model.IdempotencyRecord, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := s.records[record.Key]
+	stored.StatusCode, stored.ContentType, stored.Body = statusCode, contentType, append([]byte(nil), body...)
+	return nil
+}
+
+func (s *memoryIdempotencyService) Release(_ context.Context, record *model.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, record.Key)
+	return nil
+}
+
+func (s *memoryIdempotencyService) PruneExpired(context.Context) (int64, error) {
+	return 0, nil
+}
+
+// setupIdempotencyServer 호출 수를 세는 협상 라우트를 가진 테스트 서버를 생성합니다
+func setupIdempotencyServer(t *testing.T, status int) (*echo.Echo, *memoryIdempotencyService, *int) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	svc := newMemoryIdempotencyService()
+	calls := 0
+
+	e := echo.New()
+	e.POST("/negotiate", func(c echo.Context) error {
+		calls++
+		body, err := io.ReadAll(c.Request().Body)
+		require.NoError(t, err)
+		return c.JSON(status, map[string]interface{}{"call": calls, "echo": string(body)})
+	}, IdempotencyMiddleware(svc, logger, true))
+	e.PUT("/upload/:session_id", func(c echo.Context) error {
+		calls++
+		return c.JSON(http.StatusCreated, map[string]interface{}{"call": calls})
+	}, IdempotencyMiddleware(svc, logger, false))
+
+	return e, svc, &calls
+}
+
+// sendIdempotent Idempotency-Key를 붙여 요청합니다
+func sendIdempotent(e *echo.Echo, method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyMiddleware_ReplaysResponse(t *testing.T) {
+	e, _, calls := setupIdempotencyServer(t, http.StatusOK)
+
+	first := sendIdempotent(e, http.MethodPost, "/negotiate", "key-1", `{"size":1}`)
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Empty(t, first.Header().Get(HeaderIdempotentReplayed))
+
+	// 핸들러를 다시 호출하지 않고 처음 응답을 그대로 반환 (본문도 핸들러에 그대로 전달됨)
+	second := sendIdempotent(e, http.MethodPost, "/negotiate", "key-1", `{"size":1}`)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "true", second.Header().Get(HeaderIdempotentReplayed))
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Contains(t, second.Body.String(), `"echo":"{\"size\":1}"`)
+	assert.Equal(t, first.Header().Get(echo.HeaderContentType), second.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, 1, *calls)
+
+	// 같은 키에 다른 본문
+	conflict := sendIdempotent(e, http.MethodPost, "/negotiate", "key-1", `{"size":2}`)
+	assert.Equal(t, http.StatusConflict, conflict.Code)
+
+	// 키가 없으면 매번 처리
+	sendIdempotent(e, http.MethodPost, "/negotiate", "", `{"size":1}`)
+	sendIdempotent(e, http.MethodPost, "/negotiate", "", `{"size":1}`)
+	assert.Equal(t, 3, *calls)
+}
+
+func TestIdempotencyMiddleware_ReleasesFailedRequest(t *testing.T) {
+	e, svc, calls := setupIdempotencyServer(t, http.StatusBadRequest)
+
+	rec := sendIdempotent(e, http.MethodPost, "/negotiate", "key-1", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Empty(t, svc.records, "실패한 응답은 저장하지 않음")
+
+	rec = sendIdempotent(e, http.MethodPost, "/negotiate", "key-1", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestIdempotencyMiddleware_UploadPathPinsRequest(t *testing.T) {
+	e, _, calls := setupIdempotencyServer(t, http.StatusOK)
+
+	// 본문을 해시하지 않으므로 같은 세션이면 재시도로 봄
+	rec := sendIdempotent(e, http.MethodPut, "/upload/session-1", "key-1", "first body")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = sendIdempotent(e, http.MethodPut, "/upload/session-1", "key-1", "retried body")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get(HeaderIdempotentReplayed))
+	assert.Equal(t, 1, *calls)
+
+	// 다른 세션에 같은 키
+	rec = sendIdempotent(e, http.MethodPut, "/upload/session-2", "key-1", "first body")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestIdempotencyMiddleware_InvalidKey(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	e := echo.New()
+	e.POST("/negotiate", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, IdempotencyMiddleware(&invalidKeyService{newMemoryIdempotencyService()}, logger, true))
+
+	rec := sendIdempotent(e, http.MethodPost, "/negotiate", "bad key", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	assert.Panics(t, func() { IdempotencyMiddleware(nil, logger, true) })
+}
+
+// invalidKeyService 모든 키를 잘못된 키로 거부하는 테스트용 서비스
+type invalidKeyService struct {
+	*memoryIdempotencyService
+}
+
+func (s *invalidKeyService) Begin(context.Context, string, string) (*model.IdempotencyRecord, error) {
+	return nil, service.ErrInvalidIdempotencyKey
+}
@@ -3,11 +3,19 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/crypto"
 	"DataLocker/pkg/response"
 
 	"github.com/labstack/echo/v4"
@@ -21,6 +29,8 @@ const (
 	HTTPUnauthorized        = 401
 	HTTPForbidden           = 403
 	HTTPNotFound            = 404
+	HTTPPayloadTooLarge     = 413
+	HTTPTooManyRequests     = 429
 	HTTPInternalServerError = 500
 )
 
@@ -30,17 +40,34 @@ const (
 	CORSMaxAgeSeconds = 24 * 60 * 60 // 86400초
 
 	// Rate Limiter 기본 제한 (분당 요청 수)
-	DefaultRateLimitPerMinute = 100
+	DefaultRateLimitPerMinute = config.DefaultRateLimitPerMinute
 
 	// 에러 응답 임계값 (4xx, 5xx 에러)
 	HTTPErrorStatusThreshold = 400
 )
 
 // SetupMiddleware 모든 미들웨어를 설정합니다
-func SetupMiddleware(e *echo.Echo, cfg *config.Config, logger *logrus.Logger) {
+//
+// 반환된 레이트 리밋 저장소로 실행 중에 제한값을 바꿀 수 있습니다.
+// 저장소는 운영환경에서만 요청에 적용됩니다. accessLog가 nil이면 요청 로그를
+// 샘플링하지 않고 모두 기록하고, slowProfiler가 nil이면 느린 요청 프로파일을
+// 남기지 않습니다.
+func SetupMiddleware(e *echo.Echo, cfg *config.Config, logger *logrus.Logger, accessLog *AccessLogSampler, slowProfiler *SlowRequestProfiler) *RateLimitStore {
+	// 클라이언트 IP 추출 - 신뢰하는 프록시가 보낸 X-Forwarded-For만 사용
+	e.IPExtractor = NewIPExtractor(cfg.Security.TrustedProxies, logger)
+
+	// 에러 상세 노출 정책 (개발환경에서만 노출) - 이후 모든 에러 응답에 적용되도록 가장 먼저
+	e.Use(ErrorDetailsMiddleware(cfg.App.Environment == "development"))
+
+	// Request ID 미들웨어 - 응답과 로그를 대조하기 위한 요청 ID
+	e.Use(middleware.RequestID())
+
 	// Recovery 미들웨어 - 패닉 복구
 	e.Use(RecoveryMiddleware(logger))
 
+	// 감사 행위자 - 인증 전 요청은 anonymous로 기록 (관리 API는 인증 후 admin)
+	e.Use(ActorMiddleware(model.ActorAnonymous))
+
 	// CORS 미들웨어
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     cfg.Security.AllowedOrigins,
@@ -50,22 +77,108 @@ func SetupMiddleware(e *echo.Echo, cfg *config.Config, logger *logrus.Logger) {
 		MaxAge:           CORSMaxAgeSeconds,
 	}))
 
-	// 요청 로깅 미들웨어
-	e.Use(RequestLoggingMiddleware(logger))
+	// 요청 로깅 미들웨어 (상태 코드 그룹별 샘플링)
+	e.Use(RequestLoggingMiddleware(logger, accessLog))
 
 	// 응답 시간 측정 미들웨어
-	e.Use(ResponseTimeMiddleware(logger))
+	e.Use(ResponseTimeMiddleware(logger, slowProfiler))
 
 	// Body Limit 미들웨어
 	e.Use(middleware.BodyLimit(fmt.Sprintf("%d", cfg.Security.MaxFileSize)))
 
 	// Rate Limiting (개발환경에서는 비활성화)
+	rateLimitStore := NewRateLimitStore(cfg.Security.RateLimitPerMinute)
 	if cfg.App.Environment == "production" {
-		e.Use(middleware.RateLimiter(middleware.NewRateLimiterMemoryStore(DefaultRateLimitPerMinute)))
+		e.Use(middleware.RateLimiter(rateLimitStore))
 	}
 
 	// 보안 헤더 미들웨어
 	e.Use(SecurityHeadersMiddleware())
+
+	return rateLimitStore
+}
+
+// NewIPExtractor 신뢰하는 프록시 주소 범위(CIDR 또는 IP)로 클라이언트 IP 추출기를 만듭니다
+//
+// 목록이 비어 있으면 연결 주소를 그대로 사용해 X-Forwarded-For 위조로 IP별 제한을
+// 우회할 수 없게 합니다. 해석할 수 없는 항목은 경고를 남기고 무시합니다.
+func NewIPExtractor(trustedProxies []string, logger *logrus.Logger) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, ipNet := range parseTrustedProxies(trustedProxies, logger) {
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// NewProxyTrust 요청이 신뢰하는 프록시에서 직접 왔는지 판단하는 함수를 만듭니다
+//
+// NewIPExtractor와 같은 주소 범위를 사용하며, X-Forwarded-Proto/Host처럼 프록시가
+// 덧붙이는 다른 헤더를 믿어도 되는지 확인할 때 사용합니다. 목록이 비어 있으면
+// 어떤 요청도 신뢰하지 않습니다.
+func NewProxyTrust(trustedProxies []string, logger *logrus.Logger) func(req *http.Request) bool {
+	ranges := parseTrustedProxies(trustedProxies, logger)
+
+	return func(req *http.Request) bool {
+		host, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			host = req.RemoteAddr
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return false
+		}
+		for _, ipNet := range ranges {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// parseTrustedProxies 신뢰하는 프록시 목록을 주소 범위로 해석합니다 (해석할 수 없는 항목은 경고 후 무시)
+func parseTrustedProxies(trustedProxies []string, logger *logrus.Logger) []*net.IPNet {
+	ranges := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		ipNet, err := parseTrustedProxy(proxy)
+		if err != nil {
+			logger.WithError(err).WithField("proxy", proxy).Warn("신뢰할 프록시 주소를 해석할 수 없어 무시합니다")
+			continue
+		}
+		ranges = append(ranges, ipNet)
+	}
+	return ranges
+}
+
+// parseTrustedProxy CIDR 또는 단일 IP를 주소 범위로 해석합니다
+func parseTrustedProxy(proxy string) (*net.IPNet, error) {
+	proxy = strings.TrimSpace(proxy)
+	if !strings.Contains(proxy, "/") {
+		ip := net.ParseIP(proxy)
+		if ip == nil {
+			return nil, fmt.Errorf("잘못된 IP 주소: %q", proxy)
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 8*net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("잘못된 CIDR: %w", err)
+	}
+	return ipNet, nil
 }
 
 // RecoveryMiddleware 패닉을 복구하고 로깅합니다
@@ -77,13 +190,14 @@ func RecoveryMiddleware(logger *logrus.Logger) echo.MiddlewareFunc {
 					logger.WithFields(logrus.Fields{
 						"panic":      r,
 						"stack":      string(debug.Stack()),
+						"request_id": requestID(c),
 						"method":     c.Request().Method,
 						"uri":        c.Request().RequestURI,
 						"ip":         c.RealIP(),
 						"user_agent": c.Request().UserAgent(),
 					}).Error("패닉이 발생했습니다")
 
-					// 클라이언트에게 에러 응답 전송
+					// 클라이언트에게 에러 응답 전송 (스택 트레이스는 로그에만 기록)
 					_ = response.InternalError(c, "서버에서 예상치 못한 오류가 발생했습니다", "")
 				}
 			}()
@@ -93,7 +207,10 @@ func RecoveryMiddleware(logger *logrus.Logger) echo.MiddlewareFunc {
 }
 
 // RequestLoggingMiddleware 요청을 로깅합니다
-func RequestLoggingMiddleware(logger *logrus.Logger) echo.MiddlewareFunc {
+//
+// sampler가 있으면 상태 코드 그룹별 비율로 일부만 기록하고, 기록한 로그에
+// 그 로그가 대표하는 요청 수(sample_rate)를 남깁니다. nil이면 모두 기록합니다.
+func RequestLoggingMiddleware(logger *logrus.Logger, sampler *AccessLogSampler) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			start := time.Now()
@@ -102,6 +219,15 @@ func RequestLoggingMiddleware(logger *logrus.Logger) echo.MiddlewareFunc {
 
 			duration := time.Since(start)
 
+			sampleRate := 1
+			if sampler != nil {
+				var record bool
+				record, sampleRate = sampler.Sample(c.Response().Status, requestID(c), err != nil)
+				if !record {
+					return err
+				}
+			}
+
 			entry := logger.WithFields(logrus.Fields{
 				"method":      c.Request().Method,
 				"uri":         c.Request().RequestURI,
@@ -111,8 +237,15 @@ func RequestLoggingMiddleware(logger *logrus.Logger) echo.MiddlewareFunc {
 				"duration_ms": duration.Milliseconds(),
 				"bytes_in":    c.Request().ContentLength,
 				"bytes_out":   c.Response().Size,
+				"request_id":  requestID(c),
+				"sample_rate": sampleRate,
 			})
 
+			// 응답에서 숨긴 에러 상세는 로그에만 기록
+			if details, ok := c.Get(response.ContextKeyErrorDetails).(string); ok {
+				entry = entry.WithField("error_details", details)
+			}
+
 			if err != nil {
 				entry.WithError(err).Error("요청 처리 중 오류가 발생했습니다")
 			} else {
@@ -129,11 +262,23 @@ func RequestLoggingMiddleware(logger *logrus.Logger) echo.MiddlewareFunc {
 }
 
 // ResponseTimeMiddleware 응답 시간을 헤더에 추가합니다
-func ResponseTimeMiddleware(logger *logrus.Logger) echo.MiddlewareFunc {
+//
+// profiler가 있으면 처리 시간이 임계값을 넘긴 요청의 고루틴 덤프를 요청이 끝나기
+// 전에 저장합니다.
+func ResponseTimeMiddleware(logger *logrus.Logger, profiler *SlowRequestProfiler) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			start := time.Now()
 
+			if profiler != nil {
+				done := profiler.Watch(SlowRequest{
+					RequestID: requestID(c),
+					Method:    c.Request().Method,
+					URI:       c.Request().RequestURI,
+				})
+				defer done()
+			}
+
 			err := next(c)
 
 			duration := time.Since(start)
@@ -177,6 +322,61 @@ func SecurityHeadersMiddleware() echo.MiddlewareFunc {
 	}
 }
 
+// AdminAuthMiddleware 관리 API 토큰(Authorization: Bearer)을 검사합니다
+//
+// 토큰 비교는 타이밍 공격을 피하기 위해 상수 시간으로 수행합니다.
+func AdminAuthMiddleware(token string) echo.MiddlewareFunc {
+	if token == "" {
+		panic("관리 API 토큰이 필요합니다")
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			provided, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				return response.Unauthorized(c, "유효한 관리 API 토큰이 필요합니다")
+			}
+			setActor(c, model.ActorAdmin)
+			return next(c)
+		}
+	}
+}
+
+// ErrorDetailsMiddleware 에러 응답의 상세(details) 노출 정책을 요청 컨텍스트에 지정합니다
+//
+// 상세에는 파일 경로나 SQL 같은 내부 정보가 담길 수 있으므로 development
+// 환경에서만 노출해야 합니다. 숨김 상태에서는 상세를 컨텍스트에만 보관해
+// 로그로 남기고, 클라이언트에는 request_id만 내려줍니다. 정책은 서버(Echo
+// 인스턴스)마다 따로 두므로 한 프로세스의 여러 서버가 서로 덮어쓰지 않습니다.
+func ErrorDetailsMiddleware(expose bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(response.ContextKeyExposeDetails, expose)
+			return next(c)
+		}
+	}
+}
+
+// ActorMiddleware 요청 컨텍스트에 감사 행위자를 기록합니다
+//
+// 서비스가 요청 컨텍스트로 쓴 레코드의 CreatedBy/UpdatedBy가 이 값으로 채워지고,
+// 뒤에 오는 인증 미들웨어가 인증된 행위자로 덮어씁니다.
+func ActorMiddleware(actor string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			setActor(c, actor)
+			return next(c)
+		}
+	}
+}
+
+// setActor 요청 컨텍스트의 감사 행위자를 바꿉니다
+func setActor(c echo.Context, actor string) {
+	req := c.Request()
+	c.SetRequest(req.WithContext(model.WithActor(req.Context(), actor)))
+}
+
 // ErrorHandlingMiddleware 전역 에러 핸들링 미들웨어
 func ErrorHandlingMiddleware(logger *logrus.Logger) echo.HTTPErrorHandler {
 	return func(err error, c echo.Context) {
@@ -194,20 +394,45 @@ func ErrorHandlingMiddleware(logger *logrus.Logger) echo.HTTPErrorHandler {
 			case HTTPForbidden:
 				_ = response.Forbidden(c, fmt.Sprintf("%v", he.Message))
 			case HTTPNotFound:
-				_ = response.NotFound(c, fmt.Sprintf("%v", he.Message))
+				_ = response.NotFound(c, fmt.Sprintf("%v", he.Message), "")
+			case HTTPPayloadTooLarge:
+				_ = response.PayloadTooLarge(c, fmt.Sprintf("%v", he.Message), "")
+			case HTTPTooManyRequests:
+				_ = response.TooManyRequests(c, fmt.Sprintf("%v", he.Message), "")
 			default:
 				_ = response.InternalError(c, fmt.Sprintf("%v", he.Message), "")
 			}
+		} else if errors.Is(err, crypto.ErrPayloadTooLarge) {
+			// 메모리 암호화 한도 초과는 클라이언트가 스트림 업로드로 재시도할 수 있음
+			_ = response.PayloadTooLarge(c, "암호화하기에 데이터가 너무 큽니다", err.Error())
+		} else if errors.Is(err, repository.ErrNotFound) {
+			_ = response.NotFound(c, err.Error(), "")
+		} else if errors.Is(err, repository.ErrInvalidID) || errors.Is(err, repository.ErrTooManyIDs) {
+			_ = response.BadRequest(c, err.Error(), "")
+		} else if errors.Is(err, repository.ErrLockTimeout) {
+			_ = response.Conflict(c, "다른 작업이 이 파일을 처리 중입니다. 잠시 후 다시 시도해 주세요", err.Error())
+		} else if errors.Is(err, model.ErrStaleRecord) {
+			_ = response.Conflict(c, "다른 요청이 먼저 수정했습니다. 다시 조회한 뒤 시도해 주세요", err.Error())
+		} else if errors.Is(err, repository.ErrDuplicate) || errors.Is(err, repository.ErrConstraintViolation) {
+			// 드라이버 메시지(테이블/컬럼 이름)는 노출 정책에 따라 상세로만 전달
+			_ = response.Conflict(c, "데이터 제약조건과 충돌합니다", err.Error())
 		} else {
 			// 일반 에러 처리
 			logger.WithFields(logrus.Fields{
-				"error":  err.Error(),
-				"method": c.Request().Method,
-				"uri":    c.Request().RequestURI,
-				"ip":     c.RealIP(),
+				"error":      err.Error(),
+				"method":     c.Request().Method,
+				"uri":        c.Request().RequestURI,
+				"ip":         c.RealIP(),
+				"request_id": requestID(c),
 			}).Error("처리되지 않은 에러가 발생했습니다")
 
+			// 상세는 노출 정책에 따라 development 환경에서만 응답에 포함
 			_ = response.InternalError(c, "내부 서버 오류가 발생했습니다", err.Error())
 		}
 	}
 }
+
+// requestID RequestID 미들웨어가 설정한 요청 ID를 반환합니다
+func requestID(c echo.Context) string {
+	return c.Response().Header().Get(echo.HeaderXRequestID)
+}
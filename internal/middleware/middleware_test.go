@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/crypto"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 내부 정보가 담긴 테스트용 에러 상세
+const (
+	internalErrorText = "open /var/lib/datalocker/secret.db: no such table: files"
+	internalSQLText   = "SELECT * FROM files WHERE id = 1"
+)
+
+// setupTestServer 환경별 미들웨어와 테스트 라우트가 구성된 서버를 생성합니다
+func setupTestServer(t *testing.T, environment string) (*echo.Echo, *bytes.Buffer) {
+	t.Helper()
+
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	cfg := &config.Config{
+		App: config.AppConfig{Environment: environment},
+		Security: config.SecurityConfig{
+			AllowedOrigins: []string{"*"},
+			MaxFileSize:    1024 * 1024,
+		},
+	}
+
+	e := echo.New()
+	SetupMiddleware(e, cfg, logger, nil, nil)
+	e.HTTPErrorHandler = ErrorHandlingMiddleware(logger)
+
+	e.GET("/error", func(c echo.Context) error {
+		return errors.New(internalErrorText)
+	})
+	e.GET("/details", func(c echo.Context) error {
+		return response.InternalError(c, "조회에 실패했습니다", internalSQLText)
+	})
+	e.GET("/panic", func(c echo.Context) error {
+		panic("boom")
+	})
+	e.GET("/too-large", func(c echo.Context) error {
+		return fmt.Errorf("업로드 암호화 실패: %w", crypto.ErrPayloadTooLarge)
+	})
+
+	return e, &logs
+}
+
+// doRequest 요청을 보내고 에러 정보를 파싱합니다
+func doRequest(t *testing.T, e *echo.Echo, path string) (*httptest.ResponseRecorder, *response.ErrorInfo) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+
+	var body response.Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.NotNil(t, body.Error)
+	return rec, body.Error
+}
+
+func TestErrorDetails_Production(t *testing.T) {
+	e, logs := setupTestServer(t, "production")
+
+	for _, path := range []string{"/error", "/details"} {
+		rec, errInfo := doRequest(t, e, path)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Empty(t, errInfo.Details, path)
+		assert.NotEmpty(t, errInfo.RequestID, path)
+		assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), errInfo.RequestID)
+		assert.NotContains(t, rec.Body.String(), "/var/lib")
+		assert.NotContains(t, rec.Body.String(), "SELECT")
+	}
+
+	// 전체 내용은 로그에만 남음
+	assert.Contains(t, logs.String(), "/var/lib/datalocker/secret.db")
+	assert.Contains(t, logs.String(), internalSQLText)
+}
+
+func TestErrorDetails_Development(t *testing.T) {
+	e, _ := setupTestServer(t, "development")
+
+	_, errInfo := doRequest(t, e, "/error")
+	assert.Equal(t, internalErrorText, errInfo.Details)
+	assert.NotEmpty(t, errInfo.RequestID)
+
+	_, errInfo = doRequest(t, e, "/details")
+	assert.Equal(t, internalSQLText, errInfo.Details)
+}
+
+func TestErrorDetails_PolicyPerServer(t *testing.T) {
+	// 한 프로세스에서 띄운 서버끼리 노출 정책을 덮어쓰지 않음
+	production, _ := setupTestServer(t, "production")
+	development, _ := setupTestServer(t, "development")
+
+	_, errInfo := doRequest(t, production, "/details")
+	assert.Empty(t, errInfo.Details)
+
+	_, errInfo = doRequest(t, development, "/details")
+	assert.Equal(t, internalSQLText, errInfo.Details)
+
+	// 미들웨어를 거치지 않은 컨텍스트는 숨김
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder())
+	require.NoError(t, response.InternalError(c, "조회에 실패했습니다", internalSQLText))
+	assert.NotContains(t, c.Response().Writer.(*httptest.ResponseRecorder).Body.String(), "SELECT")
+	assert.Equal(t, internalSQLText, c.Get(response.ContextKeyErrorDetails))
+}
+
+func TestErrorDetails_PanicNeverExposesStack(t *testing.T) {
+	for _, environment := range []string{"production", "development"} {
+		t.Run(environment, func(t *testing.T) {
+			e, logs := setupTestServer(t, environment)
+
+			rec, errInfo := doRequest(t, e, "/panic")
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assert.Empty(t, errInfo.Details)
+			assert.NotContains(t, rec.Body.String(), "goroutine")
+			assert.NotContains(t, rec.Body.String(), "boom")
+
+			// 스택 트레이스는 로그에만 기록
+			assert.Contains(t, logs.String(), "goroutine")
+		})
+	}
+}
+
+func TestErrorHandling_PayloadTooLarge(t *testing.T) {
+	e, _ := setupTestServer(t, "production")
+
+	rec, errInfo := doRequest(t, e, "/too-large")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, "PAYLOAD_TOO_LARGE", errInfo.Code)
+
+	// BodyLimit 초과도 413으로 응답
+	body := bytes.Repeat([]byte("a"), 2*1024*1024)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/too-large", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestErrorHandling_RepositoryErrors(t *testing.T) {
+	e, logs := setupTestServer(t, "production")
+
+	testCases := []struct {
+		path   string
+		err    error
+		status int
+		code   string
+	}{
+		{"/not-found", fmt.Errorf("파일을 찾을 수 없습니다: ID 7: %w", repository.ErrNotFound), http.StatusNotFound, "NOT_FOUND"},
+		{"/invalid-id", fmt.Errorf("조회 실패: %w", repository.ErrInvalidID), http.StatusBadRequest, "BAD_REQUEST"},
+		{"/too-many-ids", fmt.Errorf("일괄 조회 실패: %w", repository.ErrTooManyIDs), http.StatusBadRequest, "BAD_REQUEST"},
+		{"/duplicate", fmt.Errorf("파일 생성 실패: %w: %w", repository.ErrDuplicate, errors.New(internalErrorText)), http.StatusConflict, "CONFLICT"},
+		{"/constraint", fmt.Errorf("키 슬롯 생성 실패: %w", repository.ErrConstraintViolation), http.StatusConflict, "CONFLICT"},
+		{"/stale", fmt.Errorf("파일 1 (버전 2): %w", model.ErrStaleRecord), http.StatusConflict, "CONFLICT"},
+		{"/locked", fmt.Errorf("파일 락: %w", repository.ErrLockTimeout), http.StatusConflict, "CONFLICT"},
+	}
+	for _, tc := range testCases {
+		e.GET(tc.path, func(c echo.Context) error { return tc.err })
+	}
+
+	for _, tc := range testCases {
+		rec, errInfo := doRequest(t, e, tc.path)
+		assert.Equal(t, tc.status, rec.Code, tc.path)
+		assert.Equal(t, tc.code, errInfo.Code, tc.path)
+		assert.NotContains(t, rec.Body.String(), "/var/lib", "드라이버 메시지는 응답에 노출하지 않음")
+	}
+
+	// 클라이언트 오류이므로 처리되지 않은 에러로 기록하지 않음
+	assert.NotContains(t, logs.String(), "처리되지 않은 에러")
+}
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	const token = "admin-secret-token"
+
+	e := echo.New()
+	e.GET("/admin", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, AdminAuthMiddleware(token))
+
+	testCases := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{name: "올바른 토큰", header: "Bearer " + token, status: http.StatusNoContent},
+		{name: "헤더 없음", header: "", status: http.StatusUnauthorized},
+		{name: "잘못된 토큰", header: "Bearer wrong-token", status: http.StatusUnauthorized},
+		{name: "Bearer 접두사 없음", header: token, status: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tc.header != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tc.status, rec.Code)
+		})
+	}
+
+	assert.Panics(t, func() { AdminAuthMiddleware("") })
+}
+
+func TestActorMiddleware(t *testing.T) {
+	const token = "admin-secret-token"
+
+	e := echo.New()
+	e.Use(ActorMiddleware(model.ActorAnonymous))
+	actor := func(c echo.Context) error {
+		return c.String(http.StatusOK, model.ActorFromContext(c.Request().Context()))
+	}
+	e.GET("/public", actor)
+	e.GET("/admin", actor, AdminAuthMiddleware(token))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/public", nil))
+	assert.Equal(t, model.ActorAnonymous, rec.Body.String())
+
+	// 인증을 통과하면 관리자로 기록
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, model.ActorAdmin, rec.Body.String())
+}
@@ -0,0 +1,293 @@
+// Package middleware provides HTTP middleware components for DataLocker server.
+// This file implements a sliding-window limiter for password attempts per file and client IP.
+package middleware
+
+import (
+	"container/list"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/pkg/clock"
+	"DataLocker/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// passwordAttemptWindow 패스워드 시도 횟수를 세는 슬라이딩 윈도 길이
+const passwordAttemptWindow = time.Minute
+
+// passwordAttemptScopeFactor 범위(클라이언트 IP) 전체에 허용하는 분당 시도 수 (키마다 허용하는 수의 배수)
+const passwordAttemptScopeFactor = 4
+
+// passwordAttemptEntry 키 하나의 윈도 안 시도 시각 (오래된 순)
+type passwordAttemptEntry struct {
+	key      string
+	scope    bool // 범위 전체를 세는 항목이면 true (scopes에 보관)
+	attempts []time.Time
+}
+
+// admit 윈도를 벗어난 시도를 정리하고 limit번 미만인지 확인합니다
+//
+// 거부하면 다시 시도할 수 있을 때까지 남은 시간을 함께 반환합니다.
+func (e *passwordAttemptEntry) admit(now time.Time, limit int) (bool, time.Duration) {
+	e.attempts = pruneAttempts(e.attempts, now)
+	if len(e.attempts) < limit {
+		return true, 0
+	}
+
+	// 제한을 낮춘 직후에는 제한보다 많이 남아 있을 수 있으므로 제한을 넘는 만큼 기다려야 함
+	oldest := e.attempts[len(e.attempts)-limit]
+	return false, oldest.Add(passwordAttemptWindow).Sub(now)
+}
+
+// PasswordAttemptLimiter 파일+IP 조합별 패스워드 시도 횟수를 제한하는 리미터
+//
+// 최근 1분 안의 시도 시각을 키마다 보관하는 슬라이딩 윈도 방식이라 고정 윈도의
+// 경계에서 두 배까지 허용되는 문제가 없습니다. 추적하는 키 수가 상한을 넘으면
+// 가장 오래 쓰지 않은 키부터 제거해 메모리 사용량을 제한합니다.
+//
+// 키를 범위(클라이언트 IP)로 묶으면 범위 전체의 시도도 함께 셉니다. 없는 파일 ID로
+// 새 키를 계속 만들어 자기 키를 밀어내려 해도 그 시도가 모두 범위 예산을 쓰고,
+// 범위 항목은 자기 요청마다 가장 최근으로 갱신되므로 자기 트래픽으로는 제거되지 않습니다.
+type PasswordAttemptLimiter struct {
+	mu        sync.Mutex
+	perMinute int
+	maxKeys   int
+	entries   map[string]*list.Element
+	scopes    map[string]*list.Element
+	lru       *list.List // 앞쪽이 최근에 쓴 키 (범위 항목 포함)
+	clock     clock.Clock
+}
+
+// NewPasswordAttemptLimiter 키마다 분당 perMinute번, 최대 maxKeys개 키를 추적하는 리미터를 생성합니다
+//
+// 0 이하의 값은 기본값을 사용합니다.
+func NewPasswordAttemptLimiter(perMinute, maxKeys int) *PasswordAttemptLimiter {
+	if maxKeys <= 0 {
+		maxKeys = config.DefaultPasswordAttemptMaxKeys
+	}
+
+	l := &PasswordAttemptLimiter{
+		maxKeys: maxKeys,
+		entries: make(map[string]*list.Element),
+		scopes:  make(map[string]*list.Element),
+		lru:     list.New(),
+		clock:   clock.Real{},
+	}
+	l.SetLimit(perMinute)
+	return l
+}
+
+// SetLimit 키마다 분당 허용 시도 수를 바꿉니다 (0 이하이면 기본값)
+//
+// 이미 기록된 시도는 유지되므로 제한을 낮추면 바로 적용됩니다.
+func (l *PasswordAttemptLimiter) SetLimit(perMinute int) {
+	if perMinute <= 0 {
+		perMinute = config.DefaultPasswordAttemptsPerMinute
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.perMinute = perMinute
+}
+
+// Limit 현재 키마다 분당 허용 시도 수를 반환합니다
+func (l *PasswordAttemptLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.perMinute
+}
+
+// Allow 키의 시도를 기록하고 허용 여부를 반환합니다
+//
+// 거부하면 시도를 기록하지 않고, 가장 오래된 시도가 윈도를 벗어날 때까지 남은
+// 시간을 함께 반환합니다.
+func (l *PasswordAttemptLimiter) Allow(key string) (bool, time.Duration) {
+	return l.AllowScoped("", key)
+}
+
+// AllowScoped 범위 전체와 키의 시도를 함께 기록하고 허용 여부를 반환합니다
+//
+// 범위 전체는 키마다 허용하는 수의 passwordAttemptScopeFactor배까지 허용하며, 범위
+// 예산을 먼저 확인하므로 예산을 다 쓴 범위는 새 키를 만들지 못합니다. scope가
+// 비어 있으면 Allow와 같습니다.
+func (l *PasswordAttemptLimiter) AllowScoped(scope, key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	var scoped *passwordAttemptEntry
+	if scope != "" {
+		scoped = l.touch(l.scopes, scope, true)
+		if ok, retryAfter := scoped.admit(now, l.perMinute*passwordAttemptScopeFactor); !ok {
+			return false, retryAfter
+		}
+	}
+
+	entry := l.touch(l.entries, key, false)
+	if ok, retryAfter := entry.admit(now, l.perMinute); !ok {
+		return false, retryAfter
+	}
+
+	entry.attempts = append(entry.attempts, now)
+	if scoped != nil {
+		scoped.attempts = append(scoped.attempts, now)
+	}
+	return true, 0
+}
+
+// Succeed 성공한 키의 시도 기록을 절반으로 줄입니다
+//
+// 정상 사용자가 오타 몇 번 뒤에 성공하면 남은 여유가 회복되지만, 성공 한 번으로
+// 기록이 모두 사라지지는 않아 성공과 추측을 섞는 시도도 계속 제한됩니다.
+func (l *PasswordAttemptLimiter) Succeed(key string) {
+	l.SucceedScoped("", key)
+}
+
+// SucceedScoped 성공한 키의 기록을 Succeed처럼 줄이고 범위에서는 이 시도만 되돌립니다
+//
+// 범위 기록은 절반으로 줄이지 않으므로, 자기 파일에 성공하는 요청을 섞어도 없는
+// 파일 ID로 시도한 만큼은 그대로 남습니다.
+func (l *PasswordAttemptLimiter) SucceedScoped(scope, key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.scopes[scope]; ok && scope != "" {
+		scoped := elem.Value.(*passwordAttemptEntry)
+		if n := len(scoped.attempts); n > 0 {
+			scoped.attempts = scoped.attempts[:n-1]
+		}
+	}
+
+	elem, ok := l.entries[key]
+	if !ok {
+		return
+	}
+
+	entry := elem.Value.(*passwordAttemptEntry)
+	entry.attempts = pruneAttempts(entry.attempts, l.clock.Now())
+	drop := (len(entry.attempts) + 1) / 2
+	entry.attempts = entry.attempts[drop:]
+
+	if len(entry.attempts) == 0 {
+		l.lru.Remove(elem)
+		delete(l.entries, key)
+	}
+}
+
+// Len 현재 추적 중인 키 수를 반환합니다 (범위 항목 포함)
+func (l *PasswordAttemptLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lru.Len()
+}
+
+// Reset 모든 키의 시도 기록을 지우고 지운 키 수를 반환합니다 (공장 초기화용)
+func (l *PasswordAttemptLimiter) Reset() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := l.lru.Len()
+	l.entries = make(map[string]*list.Element)
+	l.scopes = make(map[string]*list.Element)
+	l.lru.Init()
+	return n
+}
+
+// touch 항목을 가장 최근에 쓴 것으로 표시하고 반환합니다 (없으면 만들고 상한을 넘는 항목을 제거)
+func (l *PasswordAttemptLimiter) touch(index map[string]*list.Element, key string, scope bool) *passwordAttemptEntry {
+	if elem, ok := index[key]; ok {
+		l.lru.MoveToFront(elem)
+		return elem.Value.(*passwordAttemptEntry)
+	}
+
+	entry := &passwordAttemptEntry{key: key, scope: scope}
+	index[key] = l.lru.PushFront(entry)
+
+	for l.lru.Len() > l.maxKeys {
+		oldest := l.lru.Remove(l.lru.Back()).(*passwordAttemptEntry)
+		if oldest.scope {
+			delete(l.scopes, oldest.key)
+		} else {
+			delete(l.entries, oldest.key)
+		}
+	}
+
+	return entry
+}
+
+// pruneAttempts 윈도를 벗어난 시도를 제거합니다
+func pruneAttempts(attempts []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-passwordAttemptWindow)
+	i := 0
+	for i < len(attempts) && !attempts[i].After(cutoff) {
+		i++
+	}
+	return attempts[i:]
+}
+
+// PasswordAttemptMiddleware 파일 ID(:id)와 클라이언트 IP별로 패스워드 시도를 제한합니다
+//
+// 제한을 넘으면 429와 Retry-After(초)를 반환하고, 2xx로 끝난 시도는 기록을 감쇠합니다.
+// 클라이언트 IP 전체의 시도도 범위로 함께 세므로 없는 파일 ID로 키를 늘려 자기
+// 기록을 밀어낼 수 없습니다. 클라이언트 IP는 echo의 IPExtractor 설정(TRUSTED_PROXIES)을 따릅니다.
+func PasswordAttemptMiddleware(limiter *PasswordAttemptLimiter) echo.MiddlewareFunc {
+	if limiter == nil {
+		panic("패스워드 시도 리미터가 필요합니다")
+	}
+
+	return limitAttempts(limiter, "패스워드 시도 횟수를 초과했습니다", func(c echo.Context) (string, string) {
+		ip := c.RealIP()
+		return ip, c.Param("id") + "|" + ip
+	})
+}
+
+// CodeLookupMiddleware 클라이언트 IP별로 짧은 파일 코드 조회를 제한합니다
+//
+// 코드는 추측할 대상이 파일마다 나뉘지 않으므로 코드가 아닌 IP만으로 셉니다.
+// 응답 방식은 PasswordAttemptMiddleware와 같습니다.
+func CodeLookupMiddleware(limiter *PasswordAttemptLimiter) echo.MiddlewareFunc {
+	if limiter == nil {
+		panic("코드 조회 리미터가 필요합니다")
+	}
+
+	return limitAttempts(limiter, "파일 코드 조회 횟수를 초과했습니다", func(c echo.Context) (string, string) {
+		return "", c.RealIP()
+	})
+}
+
+// limitAttempts key로 구분한 시도를 제한하고, 2xx로 끝난 시도는 기록을 감쇠하는 미들웨어를 만듭니다
+//
+// key는 범위와 키를 반환하며, 범위가 비어 있으면 키만 셉니다.
+func limitAttempts(limiter *PasswordAttemptLimiter, message string, key func(c echo.Context) (string, string)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scope, k := key(c)
+
+			ok, retryAfter := limiter.AllowScoped(scope, k)
+			if !ok {
+				c.Response().Header().Set(echo.HeaderRetryAfter, retryAfterSeconds(retryAfter))
+				return response.TooManyRequests(c, message, "")
+			}
+
+			err := next(c)
+			if err == nil && c.Response().Status >= http.StatusOK && c.Response().Status < http.StatusMultipleChoices {
+				limiter.SucceedScoped(scope, k)
+			}
+			return err
+		}
+	}
+}
+
+// retryAfterSeconds 대기 시간을 Retry-After 헤더 값(올림한 초, 최소 1)으로 바꿉니다
+func retryAfterSeconds(d time.Duration) string {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.Itoa(seconds)
+}
@@ -0,0 +1,319 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/pkg/clock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPasswordLimiter 시각을 조정할 수 있는 패스워드 시도 리미터를 생성합니다
+func setupPasswordLimiter(perMinute, maxKeys int) (*PasswordAttemptLimiter, *clock.Fake) {
+	limiter := NewPasswordAttemptLimiter(perMinute, maxKeys)
+	clk := clock.NewFake(time.Now())
+	limiter.clock = clk
+	return limiter, clk
+}
+
+// attemptCount 연속 시도 중 허용된 개수를 셉니다
+func attemptCount(limiter *PasswordAttemptLimiter, key string, attempts int) int {
+	allowed := 0
+	for range attempts {
+		if ok, _ := limiter.Allow(key); ok {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestPasswordAttemptLimiter_SlidingWindow(t *testing.T) {
+	limiter, clk := setupPasswordLimiter(3, 0)
+
+	assert.Equal(t, 2, attemptCount(limiter, "1|10.0.0.1", 2))
+	clk.Advance(30 * time.Second)
+	assert.Equal(t, 1, attemptCount(limiter, "1|10.0.0.1", 5))
+	assert.Equal(t, 3, attemptCount(limiter, "2|10.0.0.1", 5), "파일별로 따로 계산")
+	assert.Equal(t, 3, attemptCount(limiter, "1|10.0.0.2", 5), "IP별로 따로 계산")
+
+	// 첫 두 시도가 윈도를 벗어날 때까지 남은 시간
+	ok, retryAfter := limiter.Allow("1|10.0.0.1")
+	assert.False(t, ok)
+	assert.Equal(t, 30*time.Second, retryAfter)
+
+	// 고정 윈도와 달리 가장 오래된 시도가 빠진 만큼만 다시 허용
+	clk.Advance(30 * time.Second)
+	assert.Equal(t, 2, attemptCount(limiter, "1|10.0.0.1", 5))
+}
+
+func TestPasswordAttemptLimiter_SucceedDecays(t *testing.T) {
+	limiter, _ := setupPasswordLimiter(4, 0)
+
+	assert.Equal(t, 4, attemptCount(limiter, "1|10.0.0.1", 4))
+	limiter.Succeed("1|10.0.0.1")
+	assert.Equal(t, 2, attemptCount(limiter, "1|10.0.0.1", 4), "성공하면 기록이 절반으로 줄어듦")
+
+	// 기록이 비면 키를 더 추적하지 않음
+	assert.Equal(t, 1, attemptCount(limiter, "2|10.0.0.1", 1))
+	limiter.Succeed("2|10.0.0.1")
+	assert.Equal(t, 1, limiter.Len())
+
+	limiter.Succeed("missing")
+}
+
+func TestPasswordAttemptLimiter_SetLimit(t *testing.T) {
+	limiter, _ := setupPasswordLimiter(5, 0)
+	assert.Equal(t, 5, attemptCount(limiter, "1|10.0.0.1", 5))
+
+	// 제한을 낮추면 남은 기록으로 바로 거부
+	limiter.SetLimit(2)
+	assert.Equal(t, 2, limiter.Limit())
+	ok, retryAfter := limiter.Allow("1|10.0.0.1")
+	assert.False(t, ok)
+	assert.Equal(t, time.Minute, retryAfter)
+
+	limiter.SetLimit(10)
+	assert.Equal(t, 5, attemptCount(limiter, "1|10.0.0.1", 10))
+
+	limiter.SetLimit(0)
+	assert.Equal(t, config.DefaultPasswordAttemptsPerMinute, limiter.Limit())
+}
+
+func TestPasswordAttemptLimiter_EvictsLeastRecentlyUsed(t *testing.T) {
+	limiter, _ := setupPasswordLimiter(1, 2)
+
+	attemptCount(limiter, "a", 1)
+	attemptCount(limiter, "b", 1)
+	attemptCount(limiter, "a", 1) // a를 최근에 쓴 키로 갱신
+	attemptCount(limiter, "c", 1)
+
+	assert.Equal(t, 2, limiter.Len())
+	assert.Equal(t, 0, attemptCount(limiter, "a", 1), "최근에 쓴 키는 유지")
+	assert.Equal(t, 1, attemptCount(limiter, "b", 1), "가장 오래 쓰지 않은 키는 제거됨")
+}
+
+func TestPasswordAttemptLimiter_Reset(t *testing.T) {
+	limiter, _ := setupPasswordLimiter(1, 10)
+
+	attemptCount(limiter, "a", 1)
+	attemptCount(limiter, "b", 1)
+	assert.Equal(t, 2, limiter.Reset())
+	assert.Zero(t, limiter.Len())
+	assert.Equal(t, 1, attemptCount(limiter, "a", 1), "초기화 후에는 처음부터 다시 셈")
+}
+
+func TestPasswordAttemptLimiter_Concurrent(t *testing.T) {
+	const (
+		limit   = 10
+		workers = 50
+	)
+	limiter := NewPasswordAttemptLimiter(limit, 20)
+
+	// 동시에 시도해도 정확히 제한만큼만 허용
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, _ := limiter.Allow("1|10.0.0.1"); ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(limit), allowed.Load())
+
+	// 감쇠, 제한 변경, LRU 제거가 섞여도 상한을 지킴 (-race로 경합 검사)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("%d|10.0.0.2", i%30)
+			limiter.Allow(key)
+			limiter.Succeed(key)
+			limiter.SetLimit(limit + i%3)
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, limiter.Len(), 20)
+}
+
+// servePreview 패스워드 시도 제한이 걸린 테스트 라우트로 요청을 보냅니다
+func servePreview(e *echo.Echo, id, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/files/"+id+"/preview", http.NoBody)
+	req.RemoteAddr = remoteAddr + ":12345"
+	if forwardedFor != "" {
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPasswordAttemptMiddleware(t *testing.T) {
+	limiter, _ := setupPasswordLimiter(2, 0)
+
+	e := echo.New()
+	e.IPExtractor = NewIPExtractor(nil, logrus.New())
+	e.GET("/files/:id/preview", func(c echo.Context) error {
+		if c.QueryParam("ok") != "" {
+			return c.NoContent(http.StatusOK)
+		}
+		return c.NoContent(http.StatusUnauthorized)
+	}, PasswordAttemptMiddleware(limiter))
+
+	assert.Equal(t, http.StatusUnauthorized, servePreview(e, "1", "10.0.0.1", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, servePreview(e, "1", "10.0.0.1", "").Code)
+
+	rec := servePreview(e, "1", "10.0.0.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get(echo.HeaderRetryAfter))
+	assert.Contains(t, rec.Body.String(), "TOO_MANY_REQUESTS")
+
+	// 신뢰하는 프록시가 없으면 X-Forwarded-For로 우회할 수 없음
+	assert.Equal(t, http.StatusTooManyRequests, servePreview(e, "1", "10.0.0.1", "203.0.113.9").Code)
+	assert.Equal(t, http.StatusUnauthorized, servePreview(e, "2", "10.0.0.1", "").Code)
+
+	// 성공한 시도는 기록을 감쇠
+	req := httptest.NewRequest(http.MethodGet, "/files/3/preview?ok=1", http.NoBody)
+	req.RemoteAddr = "10.0.0.1:12345"
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 3, limiter.Len(), "성공한 키는 기록이 비어 제거됨 (파일 키 2개와 IP 범위만 남음)")
+
+	assert.Panics(t, func() { PasswordAttemptMiddleware(nil) })
+}
+
+func TestPasswordAttemptMiddleware_EvictionBypass(t *testing.T) {
+	const maxKeys = 8
+	limiter, _ := setupPasswordLimiter(2, maxKeys)
+
+	e := echo.New()
+	e.IPExtractor = NewIPExtractor(nil, logrus.New())
+	e.GET("/files/:id/preview", func(c echo.Context) error {
+		if c.Param("id") == "own" {
+			return c.NoContent(http.StatusOK)
+		}
+		if c.Param("id") != "1" {
+			return c.NoContent(http.StatusNotFound)
+		}
+		return c.NoContent(http.StatusUnauthorized)
+	}, PasswordAttemptMiddleware(limiter))
+
+	// 대상 파일의 제한을 다 쓴 뒤
+	assert.Equal(t, http.StatusUnauthorized, servePreview(e, "1", "10.0.0.1", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, servePreview(e, "1", "10.0.0.1", "").Code)
+	require.Equal(t, http.StatusTooManyRequests, servePreview(e, "1", "10.0.0.1", "").Code)
+
+	// 상한보다 많은 없는 파일 ID로 자기 키를 밀어내려 해도 IP 범위 예산에서 막힘
+	// (자기 파일에 성공하는 요청을 섞어도 범위 기록은 줄지 않음)
+	for i := range maxKeys * 4 {
+		servePreview(e, fmt.Sprintf("bogus-%d", i), "10.0.0.1", "")
+		servePreview(e, "own", "10.0.0.1", "")
+	}
+	assert.Equal(t, http.StatusTooManyRequests, servePreview(e, "1", "10.0.0.1", "").Code)
+
+	// 다른 IP는 영향받지 않음
+	assert.Equal(t, http.StatusUnauthorized, servePreview(e, "1", "10.0.0.2", "").Code)
+}
+
+func TestCodeLookupMiddleware(t *testing.T) {
+	limiter, _ := setupPasswordLimiter(2, 0)
+
+	e := echo.New()
+	e.IPExtractor = NewIPExtractor(nil, logrus.New())
+	e.GET("/files/code/:code", func(c echo.Context) error {
+		return c.NoContent(http.StatusNotFound)
+	}, CodeLookupMiddleware(limiter))
+
+	lookup := func(code, remoteIP string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/files/code/"+code, http.NoBody)
+		req.RemoteAddr = remoteIP + ":12345"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// 코드를 바꿔 가며 추측해도 같은 IP로 셈
+	assert.Equal(t, http.StatusNotFound, lookup("AAAAAAAA", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusNotFound, lookup("BBBBBBBB", "10.0.0.1").Code)
+
+	rec := lookup("CCCCCCCC", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get(echo.HeaderRetryAfter))
+	assert.Contains(t, rec.Body.String(), "파일 코드 조회 횟수를 초과했습니다")
+
+	assert.Equal(t, http.StatusNotFound, lookup("CCCCCCCC", "10.0.0.2").Code)
+
+	assert.Panics(t, func() { CodeLookupMiddleware(nil) })
+}
+
+func TestNewIPExtractor_TrustedProxies(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	e := echo.New()
+	e.IPExtractor = NewIPExtractor([]string{"10.0.0.0/8", "192.0.2.1", "not-an-ip"}, logger)
+	e.GET("/ip", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.RealIP())
+	})
+
+	testCases := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		want         string
+	}{
+		{name: "신뢰하는 대역의 프록시", remoteAddr: "10.1.2.3", forwardedFor: "203.0.113.9", want: "203.0.113.9"},
+		{name: "신뢰하는 단일 프록시", remoteAddr: "192.0.2.1", forwardedFor: "203.0.113.9", want: "203.0.113.9"},
+		{name: "신뢰하지 않는 연결", remoteAddr: "198.51.100.7", forwardedFor: "203.0.113.9", want: "198.51.100.7"},
+		{name: "위조된 앞쪽 주소는 무시", remoteAddr: "10.1.2.3", forwardedFor: "1.1.1.1, 203.0.113.9", want: "203.0.113.9"},
+		{name: "헤더 없음", remoteAddr: "10.1.2.3", want: "10.1.2.3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ip", http.NoBody)
+			req.RemoteAddr = tc.remoteAddr + ":12345"
+			if tc.forwardedFor != "" {
+				req.Header.Set(echo.HeaderXForwardedFor, tc.forwardedFor)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.want, rec.Body.String())
+		})
+	}
+}
+
+func TestNewProxyTrust(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	trusted := NewProxyTrust([]string{"10.0.0.0/8", "192.0.2.1", "not-an-ip"}, logger)
+	for remoteAddr, want := range map[string]bool{
+		"10.1.2.3:12345":     true,
+		"192.0.2.1:443":      true,
+		"198.51.100.7:12345": false,
+		"garbage":            false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.RemoteAddr = remoteAddr
+		assert.Equal(t, want, trusted(req), remoteAddr)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.RemoteAddr = "10.1.2.3:12345"
+	assert.False(t, NewProxyTrust(nil, logger)(req), "목록이 비어 있으면 신뢰하지 않음")
+}
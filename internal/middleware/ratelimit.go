@@ -0,0 +1,103 @@
+// Package middleware provides HTTP middleware components for DataLocker server.
+// This file implements a rate limiter store whose limit can change at runtime.
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"DataLocker/pkg/clock"
+
+	"golang.org/x/time/rate"
+)
+
+// 레이트 리밋 저장소 설정
+const (
+	// secondsPerMinute 분당 제한을 초당 토큰 비율로 바꿀 때 사용
+	secondsPerMinute = 60
+
+	// rateLimitVisitorTTL 이 시간 동안 요청이 없던 클라이언트는 사용량을 잊음 (echo 메모리 저장소와 같은 값)
+	rateLimitVisitorTTL = 3 * time.Minute
+)
+
+// rateLimitVisitor 클라이언트별 토큰 버킷과 마지막 요청 시각
+type rateLimitVisitor struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimitStore 제한값을 실행 중에 바꿀 수 있는 레이트 리밋 저장소
+//
+// 클라이언트마다 분당 제한만큼 버스트를 허용하는 토큰 버킷을 두며, 시각은
+// 주입한 시계에서 읽습니다. 제한값이 바뀌면 클라이언트별 사용량은 초기화됩니다.
+type RateLimitStore struct {
+	clock clock.Clock
+
+	mu          sync.Mutex
+	perMinute   int
+	visitors    map[string]*rateLimitVisitor
+	lastCleanup time.Time
+}
+
+// NewRateLimitStore 분당 perMinute개 요청을 허용하는 저장소를 생성합니다
+func NewRateLimitStore(perMinute int) *RateLimitStore {
+	s := &RateLimitStore{clock: clock.Real{}}
+	s.SetRate(perMinute)
+	return s
+}
+
+// SetRate 분당 허용 요청 수를 바꿉니다 (0 이하이면 기본값)
+func (s *RateLimitStore) SetRate(perMinute int) {
+	if perMinute <= 0 {
+		perMinute = DefaultRateLimitPerMinute
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.visitors != nil && s.perMinute == perMinute {
+		return
+	}
+
+	s.perMinute = perMinute
+	s.visitors = make(map[string]*rateLimitVisitor)
+}
+
+// Rate 현재 분당 허용 요청 수를 반환합니다
+func (s *RateLimitStore) Rate() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.perMinute
+}
+
+// Allow 식별자의 요청을 허용할지 판단합니다 (middleware.RateLimiterStore 구현)
+func (s *RateLimitStore) Allow(identifier string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if now.Sub(s.lastCleanup) > rateLimitVisitorTTL {
+		s.cleanup(now)
+	}
+
+	visitor, ok := s.visitors[identifier]
+	if !ok {
+		visitor = &rateLimitVisitor{
+			limiter: rate.NewLimiter(rate.Limit(float64(s.perMinute)/secondsPerMinute), s.perMinute),
+		}
+		s.visitors[identifier] = visitor
+	}
+	visitor.lastSeen = now
+
+	return visitor.limiter.AllowN(now, 1), nil
+}
+
+// cleanup rateLimitVisitorTTL 넘게 요청이 없던 클라이언트를 지웁니다 (s.mu를 잡은 상태)
+func (s *RateLimitStore) cleanup(now time.Time) {
+	for identifier, visitor := range s.visitors {
+		if now.Sub(visitor.lastSeen) > rateLimitVisitorTTL {
+			delete(s.visitors, identifier)
+		}
+	}
+	s.lastCleanup = now
+}
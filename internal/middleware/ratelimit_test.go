@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"DataLocker/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowCount 연속 요청 중 허용된 개수를 셉니다
+func allowCount(t *testing.T, store *RateLimitStore, identifier string, requests int) int {
+	t.Helper()
+
+	allowed := 0
+	for i := 0; i < requests; i++ {
+		ok, err := store.Allow(identifier)
+		require.NoError(t, err)
+		if ok {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestRateLimitStore_PerMinuteBurst(t *testing.T) {
+	store := NewRateLimitStore(5)
+
+	assert.Equal(t, 5, allowCount(t, store, "10.0.0.1", 10))
+	assert.Equal(t, 5, allowCount(t, store, "10.0.0.2", 10), "클라이언트별로 따로 계산")
+}
+
+func TestRateLimitStore_SetRate(t *testing.T) {
+	store := NewRateLimitStore(2)
+	assert.Equal(t, 2, allowCount(t, store, "10.0.0.1", 5))
+
+	// 제한값이 바뀌면 새 제한으로 다시 계산
+	store.SetRate(4)
+	assert.Equal(t, 4, store.Rate())
+	assert.Equal(t, 4, allowCount(t, store, "10.0.0.1", 5))
+
+	// 같은 값이면 사용량을 유지
+	store.SetRate(4)
+	assert.Equal(t, 0, allowCount(t, store, "10.0.0.1", 1))
+
+	store.SetRate(0)
+	assert.Equal(t, DefaultRateLimitPerMinute, store.Rate())
+}
+
+func TestRateLimitStore_Refill(t *testing.T) {
+	store := NewRateLimitStore(6)
+	clk := clock.NewFake(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	store.clock = clk
+
+	assert.Equal(t, 6, allowCount(t, store, "10.0.0.1", 10))
+
+	// 분당 6개이면 10초마다 하나씩 다시 허용
+	clk.Advance(9 * time.Second)
+	assert.Equal(t, 0, allowCount(t, store, "10.0.0.1", 1))
+	clk.Advance(time.Second)
+	assert.Equal(t, 1, allowCount(t, store, "10.0.0.1", 2))
+
+	// 1분이 지나면 버스트만큼 다시 허용 (그 이상 쌓이지는 않음)
+	clk.Advance(time.Hour)
+	assert.Equal(t, 6, allowCount(t, store, "10.0.0.1", 10))
+}
+
+func TestRateLimitStore_ForgetsIdleVisitors(t *testing.T) {
+	store := NewRateLimitStore(2)
+	clk := clock.NewFake(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	store.clock = clk
+
+	allowCount(t, store, "10.0.0.1", 2)
+	clk.Advance(rateLimitVisitorTTL / 2)
+	allowCount(t, store, "10.0.0.2", 2)
+	assert.Len(t, store.visitors, 2)
+
+	// 마지막 요청 후 TTL이 지난 클라이언트만 정리
+	clk.Advance(rateLimitVisitorTTL/2 + time.Second)
+	allowCount(t, store, "10.0.0.3", 1)
+	assert.Len(t, store.visitors, 2)
+	assert.NotContains(t, store.visitors, "10.0.0.1")
+}
@@ -0,0 +1,286 @@
+// Package middleware provides HTTP middleware components for DataLocker server.
+// This file implements goroutine dump capture for requests that exceed the slow-request threshold.
+package middleware
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 느린 요청 프로파일 관련 상수
+const (
+	// slowProfileMinInterval 프로파일 저장 최소 간격 (동시에 느려진 요청이 많아도 분당 1개)
+	slowProfileMinInterval = time.Minute
+
+	// slowProfileExt 프로파일 파일 확장자 (용량 정리는 이 확장자만 대상)
+	slowProfileExt = ".txt"
+
+	// slowProfileFileMode 프로파일 파일 권한 (고루틴 덤프에 요청 경로가 담기므로 소유자만)
+	slowProfileFileMode = 0o600
+
+	// slowProfileDirMode 프로파일 디렉터리 권한
+	slowProfileDirMode = 0o750
+)
+
+// requestIDPattern 파일명으로 그대로 쓸 수 있는 요청 ID
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// SlowRequest 프로파일을 남길 느린 요청 정보
+type SlowRequest struct {
+	RequestID string
+	Method    string
+	URI       string
+	Elapsed   time.Duration // 캡처 시점까지 걸린 시간 (요청은 아직 처리 중)
+}
+
+// SlowRequestProfiler 임계값을 넘긴 요청이 처리 중일 때 고루틴 덤프와 메트릭을 저장하는 캡처기
+//
+// 요청이 끝난 뒤에는 느린 원인이 된 고루틴이 사라지므로, 임계값에 도달한 순간
+// (요청이 아직 처리 중일 때) 덤프를 남깁니다. 저장은 분당 한 번으로 제한하고,
+// 디렉터리 용량이 상한을 넘으면 오래된 프로파일부터 삭제합니다.
+type SlowRequestProfiler struct {
+	logger      *logrus.Logger
+	threshold   time.Duration
+	dir         string
+	maxDirBytes int64
+	clock       clock.Clock
+
+	// dbStats DB 연결 풀 상태 (nil이면 생략)
+	dbStats func() (map[string]interface{}, error)
+
+	inFlight atomic.Int64 // 처리 중인 요청 수
+
+	mu          sync.Mutex
+	lastCapture time.Time
+}
+
+// NewSlowRequestProfiler 설정으로 느린 요청 프로파일 캡처기를 생성합니다
+//
+// 임계값과 용량 상한이 0 이하이면 기본값을 사용합니다. 디렉터리는 처음 저장할 때 만듭니다.
+func NewSlowRequestProfiler(cfg config.SlowRequestConfig, logger *logrus.Logger) *SlowRequestProfiler {
+	if logger == nil {
+		panic("logger cannot be nil")
+	}
+
+	if cfg.Dir == "" {
+		panic("프로파일 저장 디렉터리가 필요합니다")
+	}
+
+	threshold := cfg.Threshold
+	if threshold <= 0 {
+		threshold = config.DefaultSlowRequestThresholdMillis * time.Millisecond
+	}
+
+	maxDirBytes := cfg.MaxDirBytes
+	if maxDirBytes <= 0 {
+		maxDirBytes = config.DefaultSlowRequestMaxDirBytes
+	}
+
+	return &SlowRequestProfiler{
+		logger:      logger,
+		threshold:   threshold,
+		dir:         cfg.Dir,
+		maxDirBytes: maxDirBytes,
+		clock:       clock.Real{},
+	}
+}
+
+// SetDBStats 프로파일에 포함할 DB 연결 풀 상태 조회 함수를 지정합니다
+func (p *SlowRequestProfiler) SetDBStats(stats func() (map[string]interface{}, error)) {
+	p.dbStats = stats
+}
+
+// Threshold 프로파일을 남기는 처리 시간 임계값을 반환합니다
+func (p *SlowRequestProfiler) Threshold() time.Duration {
+	return p.threshold
+}
+
+// Watch 요청 처리를 시작하며, 임계값을 넘기면 slow 정보로 프로파일을 저장합니다
+//
+// 반환된 함수를 요청이 끝날 때 호출해야 하며, 그 전에 임계값에 도달하지 않으면
+// 아무것도 저장하지 않습니다. 저장은 다른 고루틴에서 하므로 요청 정보는 처리 중인
+// 요청 객체 대신 시작할 때 복사한 값을 사용합니다.
+func (p *SlowRequestProfiler) Watch(slow SlowRequest) (done func()) {
+	p.inFlight.Add(1)
+	start := p.clock.Now()
+
+	timer := p.clock.NewTimer(p.threshold)
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-timer.C():
+		case <-finished:
+			return
+		}
+
+		slow.Elapsed = p.clock.Now().Sub(start)
+		if _, err := p.Capture(slow); err != nil {
+			p.logger.WithError(err).WithField("request_id", slow.RequestID).Warn("느린 요청 프로파일 저장에 실패했습니다")
+		}
+	}()
+
+	return func() {
+		timer.Stop()
+		close(finished)
+		p.inFlight.Add(-1)
+	}
+}
+
+// Capture 고루틴 덤프와 메트릭을 저장하고 파일 경로를 반환합니다
+//
+// 마지막 저장 후 1분이 지나지 않았으면 저장하지 않고 빈 경로를 반환합니다.
+func (p *SlowRequestProfiler) Capture(slow SlowRequest) (string, error) {
+	now := p.clock.Now()
+
+	p.mu.Lock()
+	if !p.lastCapture.IsZero() && now.Sub(p.lastCapture) < slowProfileMinInterval {
+		p.mu.Unlock()
+		return "", nil
+	}
+	p.lastCapture = now
+	p.mu.Unlock()
+
+	if err := os.MkdirAll(p.dir, slowProfileDirMode); err != nil {
+		return "", fmt.Errorf("프로파일 디렉터리 생성 실패: %w", err)
+	}
+
+	path := filepath.Join(p.dir, profileFileName(slow.RequestID, now)+slowProfileExt)
+	if err := p.write(path, slow, now); err != nil {
+		return "", err
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"request_id": slow.RequestID,
+		"method":     slow.Method,
+		"uri":        slow.URI,
+		"elapsed_ms": slow.Elapsed.Milliseconds(),
+		"profile":    path,
+	}).Warn("느린 요청 프로파일을 저장했습니다")
+
+	if err := p.prune(path); err != nil {
+		p.logger.WithError(err).Warn("오래된 느린 요청 프로파일 정리에 실패했습니다")
+	}
+
+	return path, nil
+}
+
+// write 요청 정보, 메트릭, 전체 고루틴 스택을 파일 하나에 기록합니다
+func (p *SlowRequestProfiler) write(path string, slow SlowRequest, now time.Time) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, slowProfileFileMode) //nolint:gosec // 설정된 디렉터리와 정제한 요청 ID
+	if err != nil {
+		return fmt.Errorf("프로파일 파일 생성 실패: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "captured_at: %s\n", now.UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "request_id: %s\n", slow.RequestID)
+	fmt.Fprintf(&b, "request: %s %s\n", slow.Method, slow.URI)
+	fmt.Fprintf(&b, "elapsed: %s (threshold %s, still running)\n", slow.Elapsed, p.threshold)
+	fmt.Fprintf(&b, "\n== metrics ==\n")
+	fmt.Fprintf(&b, "in_flight_requests: %d\n", p.inFlight.Load())
+	fmt.Fprintf(&b, "goroutines: %d\n", runtime.NumGoroutine())
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	fmt.Fprintf(&b, "heap_alloc: %d\nsys: %d\nnum_gc: %d\n", mem.HeapAlloc, mem.Sys, mem.NumGC)
+
+	if p.dbStats != nil {
+		stats, err := p.dbStats()
+		if err != nil {
+			fmt.Fprintf(&b, "db_pool: error: %v\n", err)
+		} else {
+			keys := make([]string, 0, len(stats))
+			for key := range stats {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+			for _, key := range keys {
+				fmt.Fprintf(&b, "db_pool.%s: %v\n", key, stats[key])
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\n== goroutines ==\n")
+
+	_, err = file.WriteString(b.String())
+	if err == nil {
+		err = pprof.Lookup("goroutine").WriteTo(file, 2)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("프로파일 기록 실패: %w", err)
+	}
+
+	return nil
+}
+
+// prune 디렉터리 용량이 상한 이하가 될 때까지 오래된 프로파일부터 삭제합니다
+//
+// 방금 저장한 keep은 상한보다 커도 지우지 않습니다.
+func (p *SlowRequestProfiler) prune(keep string) error {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return fmt.Errorf("프로파일 디렉터리 읽기 실패: %w", err)
+	}
+
+	type profile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var profiles []profile
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != slowProfileExt {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		profiles = append(profiles, profile{filepath.Join(p.dir, entry.Name()), info.Size(), info.ModTime()})
+		total += info.Size()
+	}
+
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].modTime.Before(profiles[j].modTime) })
+
+	for _, old := range profiles {
+		if total <= p.maxDirBytes {
+			break
+		}
+		if old.path == keep {
+			continue
+		}
+		if err := os.Remove(old.path); err != nil {
+			return fmt.Errorf("프로파일 삭제 실패: %w", err)
+		}
+		total -= old.size
+	}
+
+	return nil
+}
+
+// profileFileName 요청 ID를 파일명으로 쓰고, 없거나 파일명에 쓸 수 없으면 시각으로 대신합니다
+func profileFileName(requestID string, now time.Time) string {
+	if requestIDPattern.MatchString(requestID) {
+		return requestID
+	}
+	return "unknown-" + now.UTC().Format("20060102T150405.000000000")
+}
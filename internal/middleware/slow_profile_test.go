@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/pkg/clock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestProfiler 임시 디렉터리에 프로파일을 남기는 캡처기를 생성합니다
+func newTestProfiler(t *testing.T, threshold time.Duration, maxDirBytes int64) *SlowRequestProfiler {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	return NewSlowRequestProfiler(config.SlowRequestConfig{
+		ProfileEnabled: true,
+		Threshold:      threshold,
+		Dir:            filepath.Join(t.TempDir(), "requests-slow"),
+		MaxDirBytes:    maxDirBytes,
+	}, logger)
+}
+
+func TestNewSlowRequestProfiler_Defaults(t *testing.T) {
+	profiler := newTestProfiler(t, 0, 0)
+	assert.Equal(t, 3*time.Second, profiler.Threshold())
+	assert.Equal(t, int64(config.DefaultSlowRequestMaxDirBytes), profiler.maxDirBytes)
+
+	assert.Panics(t, func() {
+		NewSlowRequestProfiler(config.SlowRequestConfig{}, logrus.New())
+	})
+}
+
+func TestSlowRequestProfiler_CaptureRateLimited(t *testing.T) {
+	profiler := newTestProfiler(t, time.Second, 0)
+	clk := clock.NewFake(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	profiler.clock = clk
+	profiler.SetDBStats(func() (map[string]interface{}, error) {
+		return map[string]interface{}{"open_connections": 3, "in_use": 1}, nil
+	})
+
+	path, err := profiler.Capture(SlowRequest{RequestID: "req-1", Method: http.MethodGet, URI: "/api/v1/files/1", Elapsed: 4 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(profiler.dir, "req-1.txt"), path)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "request: GET /api/v1/files/1")
+	assert.Contains(t, string(content), "db_pool.in_use: 1")
+	assert.Contains(t, string(content), "db_pool.open_connections: 3")
+	assert.Contains(t, string(content), "[running]:", "고루틴 덤프 포함")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// 1분 안의 두 번째 요청은 저장하지 않음
+	clk.Advance(59 * time.Second)
+	path, err = profiler.Capture(SlowRequest{RequestID: "req-2"})
+	require.NoError(t, err)
+	assert.Empty(t, path)
+	assert.NoFileExists(t, filepath.Join(profiler.dir, "req-2.txt"))
+
+	clk.Advance(time.Second)
+	path, err = profiler.Capture(SlowRequest{RequestID: "req-3"})
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+}
+
+func TestSlowRequestProfiler_PrunesOldestOverLimit(t *testing.T) {
+	profiler := newTestProfiler(t, time.Second, 100)
+	require.NoError(t, os.MkdirAll(profiler.dir, 0o750))
+
+	base := time.Now().Add(-time.Hour)
+	for i, name := range []string{"old.txt", "mid.txt"} {
+		path := filepath.Join(profiler.dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 60)), 0o600))
+		require.NoError(t, os.Chtimes(path, base, base.Add(time.Duration(i)*time.Minute)))
+	}
+	// 확장자가 다른 파일은 정리 대상이 아님
+	other := filepath.Join(profiler.dir, "notes.md")
+	require.NoError(t, os.WriteFile(other, []byte(strings.Repeat("x", 200)), 0o600))
+
+	// 방금 저장한 프로파일은 상한보다 커도 남김
+	path, err := profiler.Capture(SlowRequest{RequestID: "new"})
+	require.NoError(t, err)
+
+	assert.FileExists(t, path)
+	assert.FileExists(t, other)
+	assert.NoFileExists(t, filepath.Join(profiler.dir, "old.txt"))
+	assert.NoFileExists(t, filepath.Join(profiler.dir, "mid.txt"))
+}
+
+func TestProfileFileName(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	assert.Equal(t, "abc_DEF-123", profileFileName("abc_DEF-123", now))
+	assert.Equal(t, "unknown-20260102T030405.000000006", profileFileName("", now))
+	assert.Equal(t, "unknown-20260102T030405.000000006", profileFileName("../../etc/passwd", now))
+	assert.Equal(t, "unknown-20260102T030405.000000006", profileFileName(strings.Repeat("a", 65), now))
+}
+
+func TestResponseTimeMiddleware_SlowRequestProfile(t *testing.T) {
+	profiler := newTestProfiler(t, 20*time.Millisecond, 0)
+	clk := clock.NewFake(time.Now())
+	profiler.clock = clk
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(ResponseTimeMiddleware(logger, profiler))
+
+	release := make(chan struct{})
+	e.GET("/slow", func(c echo.Context) error {
+		<-release
+		return c.NoContent(http.StatusNoContent)
+	})
+	e.GET("/fast", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	// 임계값 안에 끝난 요청은 남기지 않음
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Zero(t, clk.Timers(), "끝난 요청의 타이머는 멈춤")
+	clk.Advance(time.Second)
+	assert.NoDirExists(t, profiler.dir)
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set(echo.HeaderXRequestID, "slow-req")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	// 요청이 아직 처리 중일 때 임계값에 도달하면 저장
+	clk.BlockUntil(1)
+	clk.Advance(20 * time.Millisecond)
+	path := filepath.Join(profiler.dir, "slow-req.txt")
+	var content []byte
+	require.Eventually(t, func() bool {
+		content, _ = os.ReadFile(path)
+		return strings.Contains(string(content), "== goroutines ==")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	close(release)
+	<-done
+
+	assert.Contains(t, string(content), "request: GET /slow")
+	assert.Contains(t, string(content), "in_flight_requests: 1")
+	assert.Contains(t, string(content), "elapsed: 20ms")
+	assert.Equal(t, int64(0), profiler.inFlight.Load())
+}
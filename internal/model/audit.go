@@ -0,0 +1,58 @@
+// Package model provides database models for DataLocker application.
+// This file carries the audit actor from request contexts to GORM hooks.
+package model
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// 감사 필드(CreatedBy/UpdatedBy)에 기록되는 행위자
+const (
+	// ActorSystem 서버가 스스로 수행한 작업 (감시 폴더, 점검 작업 등)
+	//
+	// 컨텍스트에 행위자가 없으면 이 값으로 기록하고, 감사 필드 도입 전 레코드도 이 값입니다.
+	ActorSystem = "system"
+
+	// ActorAdmin 관리 API 토큰으로 인증된 요청 (원격 CLI 포함)
+	ActorAdmin = "admin"
+
+	// ActorAnonymous 인증 없이 호출하는 공개 API 요청
+	ActorAnonymous = "anonymous"
+
+	// MaxActorLength 행위자 ID 최대 길이
+	MaxActorLength = 64
+)
+
+// actorContextKey 컨텍스트에 행위자를 저장하는 키
+type actorContextKey struct{}
+
+// WithActor 행위자를 담은 컨텍스트를 반환합니다
+//
+// 이 컨텍스트로 실행한 GORM 쿼리(db.WithContext, TxManager 등)의 생성/수정 훅이
+// 감사 필드를 채웁니다. 빈 행위자는 무시합니다.
+func WithActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext 컨텍스트의 행위자를 반환합니다 (없으면 ActorSystem)
+func ActorFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if actor, ok := ctx.Value(actorContextKey{}).(string); ok {
+			return actor
+		}
+	}
+	return ActorSystem
+}
+
+// actorFromTx 훅이 실행 중인 GORM 세션의 행위자를 반환합니다
+func actorFromTx(tx *gorm.DB) string {
+	if tx == nil || tx.Statement == nil {
+		return ActorSystem
+	}
+	return ActorFromContext(tx.Statement.Context)
+}
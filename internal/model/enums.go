@@ -0,0 +1,71 @@
+// Package model provides database models for DataLocker application.
+// This file keeps the registry of enum constants and their display labels served to clients.
+package model
+
+import "slices"
+
+// enum 레지스트리 이름 (GET /api/v1/meta/enums 응답의 키)
+const (
+	EnumFileStatus          = "file_status"
+	EnumEncryptionAlgorithm = "encryption_algorithm"
+	EnumKeyDerivation       = "key_derivation"
+)
+
+// EnumLabels 허용값의 언어별 표시 라벨
+type EnumLabels struct {
+	Ko string `json:"ko"`
+	En string `json:"en"`
+}
+
+// EnumValue 허용값 하나와 표시 라벨
+type EnumValue struct {
+	Value  string     `json:"value"`
+	Labels EnumLabels `json:"labels"`
+}
+
+// enumRegistry enum별 허용값 (검증과 클라이언트 응답의 단일 소스, 값 순서 유지)
+//
+// FileStatus*, EncryptionAlgorithm*, KeyDerivation* 상수를 추가하면 여기에도
+// 등록해야 하며, 빠뜨리면 TestEnumRegistry_CoversConstants가 실패합니다.
+var enumRegistry = map[string][]EnumValue{
+	EnumFileStatus: {
+		{Value: FileStatusPending, Labels: EnumLabels{Ko: "처리 대기", En: "Pending"}},
+		{Value: FileStatusEncrypted, Labels: EnumLabels{Ko: "암호화 완료", En: "Encrypted"}},
+		{Value: FileStatusFailed, Labels: EnumLabels{Ko: "처리 실패", En: "Failed"}},
+		{Value: FileStatusCorrupted, Labels: EnumLabels{Ko: "손상됨", En: "Corrupted"}},
+	},
+	EnumEncryptionAlgorithm: {
+		{Value: EncryptionAlgorithmAES256GCM, Labels: EnumLabels{Ko: "AES-256-GCM", En: "AES-256-GCM"}},
+	},
+	EnumKeyDerivation: {
+		{Value: KeyDerivationPBKDF2SHA256, Labels: EnumLabels{Ko: "PBKDF2 (SHA-256)", En: "PBKDF2 (SHA-256)"}},
+	},
+}
+
+// Enums 모든 enum의 허용값과 표시 라벨을 반환합니다 (호출자가 수정해도 레지스트리는 그대로)
+func Enums() map[string][]EnumValue {
+	enums := make(map[string][]EnumValue, len(enumRegistry))
+	for name, values := range enumRegistry {
+		enums[name] = slices.Clone(values)
+	}
+	return enums
+}
+
+// EnumValues enum의 허용값 목록을 등록 순서대로 반환합니다 (없는 enum이면 nil)
+func EnumValues(name string) []string {
+	values := enumRegistry[name]
+	if values == nil {
+		return nil
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = value.Value
+	}
+	return result
+}
+
+// isEnumValue 값이 enum의 허용값인지 확인합니다
+func isEnumValue(name, value string) bool {
+	return slices.ContainsFunc(enumRegistry[name], func(v EnumValue) bool { return v.Value == value })
+}
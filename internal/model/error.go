@@ -35,6 +35,84 @@ var (
 
 	// ErrInvalidFileStatus 잘못된 파일 상태
 	ErrInvalidFileStatus = errors.New("잘못된 파일 상태입니다")
+
+	// ErrActorTooLong 감사 필드의 행위자 ID가 너무 김
+	ErrActorTooLong = errors.New("행위자 ID가 너무 깁니다")
+
+	// ErrExternalIDTooLong 외부 시스템 참조 ID가 너무 김
+	ErrExternalIDTooLong = errors.New("외부 참조 ID가 너무 깁니다")
+
+	// ErrInvalidOwnerID 소유자를 지정했지만 사용자 ID가 0임
+	ErrInvalidOwnerID = errors.New("유효하지 않은 소유자 ID입니다")
+
+	// ErrInvalidFolderID 폴더를 지정했지만 폴더 ID가 0임
+	ErrInvalidFolderID = errors.New("유효하지 않은 폴더 ID입니다")
+
+	// ErrInvalidShortCode 짧은 파일 코드 형식이 잘못됨
+	ErrInvalidShortCode = errors.New("짧은 파일 코드는 Crockford Base32 8자여야 합니다")
+
+	// ErrDescriptionTooLong 파일 설명이 너무 김
+	ErrDescriptionTooLong = errors.New("파일 설명은 1000자를 넘을 수 없습니다")
+
+	// ErrInvalidCustomMetadata 사용자 메타데이터가 JSON 객체가 아님
+	ErrInvalidCustomMetadata = errors.New("사용자 메타데이터는 JSON 객체여야 합니다")
+
+	// ErrCustomMetadataTooLarge 사용자 메타데이터 JSON이 너무 큼
+	ErrCustomMetadataTooLarge = errors.New("사용자 메타데이터는 4KB를 넘을 수 없습니다")
+
+	// ErrTooManyMetadataKeys 사용자 메타데이터의 최상위 키가 너무 많음
+	ErrTooManyMetadataKeys = errors.New("사용자 메타데이터 키는 32개를 넘을 수 없습니다")
+
+	// ErrInvalidMetadataKey 메타데이터 검색 키 형식이 잘못됨
+	ErrInvalidMetadataKey = errors.New("메타데이터 키는 '.'으로 구분한 빈 값 없는 이름이어야 합니다")
+)
+
+// User 모델 관련 에러
+var (
+	// ErrEmptyUsername 사용자 이름이 비어있음
+	ErrEmptyUsername = errors.New("사용자 이름은 필수입니다")
+
+	// ErrUsernameTooLong 사용자 이름이 너무 김
+	ErrUsernameTooLong = errors.New("사용자 이름이 너무 깁니다")
+
+	// ErrInvalidUsername 사용자 이름에 허용하지 않는 문자가 있음
+	ErrInvalidUsername = errors.New("사용자 이름은 영문, 숫자, '.', '_', '-'만 사용할 수 있습니다")
+
+	// ErrEmptyPasswordHash 패스워드 해시가 비어있음
+	ErrEmptyPasswordHash = errors.New("패스워드 해시는 필수입니다")
+
+	// ErrPasswordHashTooLong 패스워드 해시가 너무 김
+	ErrPasswordHashTooLong = errors.New("패스워드 해시가 너무 깁니다")
+)
+
+// Tag 모델 관련 에러
+var (
+	// ErrEmptyTagName 태그 이름이 비어있음 (공백만 있는 경우 포함)
+	ErrEmptyTagName = errors.New("태그 이름은 필수입니다")
+
+	// ErrTagNameTooLong 태그 이름이 너무 김
+	ErrTagNameTooLong = errors.New("태그 이름이 너무 깁니다")
+)
+
+// Folder 모델 관련 에러
+var (
+	// ErrEmptyFolderName 폴더 이름이 비어있음 (공백만 있는 경우 포함)
+	ErrEmptyFolderName = errors.New("폴더 이름은 필수입니다")
+
+	// ErrFolderNameTooLong 폴더 이름이 너무 김
+	ErrFolderNameTooLong = errors.New("폴더 이름이 너무 깁니다")
+
+	// ErrInvalidFolderName 폴더 이름에 경로 구분자가 있거나 "." 또는 ".."임
+	ErrInvalidFolderName = errors.New("폴더 이름에 경로 구분자를 쓰거나 '.', '..'을 이름으로 쓸 수 없습니다")
+
+	// ErrFolderParentNotFound 상위 폴더가 없음
+	ErrFolderParentNotFound = errors.New("상위 폴더를 찾을 수 없습니다")
+
+	// ErrFolderTooDeep 폴더 깊이 제한 초과
+	ErrFolderTooDeep = errors.New("폴더 깊이는 16단계를 넘을 수 없습니다")
+
+	// ErrFolderCycle 폴더를 자기 자신이나 하위 폴더 아래로 옮기려 함
+	ErrFolderCycle = errors.New("폴더를 자기 자신이나 하위 폴더 아래로 옮길 수 없습니다")
 )
 
 // EncryptionMetadata 모델 관련 에러
@@ -86,6 +164,39 @@ var (
 
 	// ErrInvalidIterations 잘못된 반복 횟수
 	ErrInvalidIterations = errors.New("반복 횟수는 1,000 이상 1,000,000 이하여야 합니다")
+
+	// ErrInvalidPasswordFingerprint 잘못된 패스워드 지문 형식
+	ErrInvalidPasswordFingerprint = errors.New("잘못된 패스워드 지문 형식입니다")
+
+	// ErrInvalidCompression 지원하지 않는 압축 알고리즘
+	ErrInvalidCompression = errors.New("지원하지 않는 압축 알고리즘입니다")
+
+	// ErrInvalidMetadataPurpose 지원하지 않는 메타데이터 용도
+	ErrInvalidMetadataPurpose = errors.New("지원하지 않는 암호화 메타데이터 용도입니다")
+
+	// ErrInvalidMetadataSlot 잘못된 메타데이터 슬롯 번호
+	ErrInvalidMetadataSlot = errors.New("잘못된 암호화 메타데이터 슬롯 번호입니다")
+
+	// ErrTooManyMetadata 파일당 메타데이터 수 초과
+	ErrTooManyMetadata = errors.New("파일당 암호화 메타데이터 수를 초과했습니다")
+)
+
+// KeySlot 모델 관련 에러
+var (
+	// ErrInvalidSlotIndex 잘못된 키 슬롯 번호
+	ErrInvalidSlotIndex = errors.New("키 슬롯 번호는 0 이상 8 미만이어야 합니다")
+
+	// ErrEmptyWrappedKey 감싸진 키가 비어있음
+	ErrEmptyWrappedKey = errors.New("감싸진 키는 필수입니다")
+
+	// ErrWrappedKeyTooLong 감싸진 키가 너무 김
+	ErrWrappedKeyTooLong = errors.New("감싸진 키가 너무 깁니다")
+
+	// ErrInvalidWrappedKeyHex 잘못된 감싸진 키 hex 형식
+	ErrInvalidWrappedKeyHex = errors.New("잘못된 감싸진 키 hex 형식입니다")
+
+	// ErrInvalidWrappedKeySize 잘못된 감싸진 키 크기
+	ErrInvalidWrappedKeySize = errors.New("감싸진 키 크기가 올바르지 않습니다")
 )
 
 // 일반적인 모델 에러
@@ -93,6 +204,9 @@ var (
 	// ErrRecordNotFound 레코드를 찾을 수 없음
 	ErrRecordNotFound = errors.New("레코드를 찾을 수 없습니다")
 
+	// ErrStaleRecord 읽어 온 뒤 다른 요청이 먼저 레코드를 수정함 (버전 불일치)
+	ErrStaleRecord = errors.New("다른 요청이 먼저 수정한 레코드입니다")
+
 	// ErrDuplicateRecord 중복된 레코드
 	ErrDuplicateRecord = errors.New("중복된 레코드입니다")
 
@@ -102,3 +216,15 @@ var (
 	// ErrInvalidModelData 잘못된 모델 데이터
 	ErrInvalidModelData = errors.New("잘못된 모델 데이터입니다")
 )
+
+// 마이그레이션 관련 에러
+var (
+	// ErrUnknownMigration 이 빌드에 없는 마이그레이션이 적용된 데이터베이스 (더 새 버전이 마이그레이션함)
+	ErrUnknownMigration = errors.New("이 빌드가 모르는 마이그레이션이 적용된 데이터베이스입니다")
+
+	// ErrIrreversibleMigration 되돌리기(Down)가 없는 마이그레이션
+	ErrIrreversibleMigration = errors.New("되돌릴 수 없는 마이그레이션입니다")
+
+	// ErrInvalidMigrationSteps 되돌릴 마이그레이션 수가 1보다 작음
+	ErrInvalidMigrationSteps = errors.New("되돌릴 마이그레이션 수는 1 이상이어야 합니다")
+)
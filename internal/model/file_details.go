@@ -0,0 +1,104 @@
+// Package model provides database models for DataLocker application.
+// This file validates the free-form description and custom metadata users attach to files.
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/datatypes"
+)
+
+// 파일 설명/사용자 메타데이터 제한
+const (
+	// MaxDescriptionLength 파일 설명 최대 길이 (문자 수)
+	MaxDescriptionLength = 1000
+
+	// MaxCustomMetadataSize 사용자 메타데이터 JSON 최대 크기 (bytes)
+	MaxCustomMetadataSize = 4096
+
+	// MaxCustomMetadataKeys 사용자 메타데이터 최상위 키 최대 수
+	MaxCustomMetadataKeys = 32
+
+	// MaxCustomMetadataKeyDepth 메타데이터 검색 키 최대 깊이 ('.'으로 나눈 구간 수)
+	MaxCustomMetadataKeyDepth = 8
+)
+
+// ValidateDescription 파일 설명 길이를 검증합니다 (넘으면 ErrDescriptionTooLong)
+func ValidateDescription(description string) error {
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return ErrDescriptionTooLong
+	}
+	return nil
+}
+
+// NormalizeCustomMetadata 빈 값이나 JSON null을 nil(메타데이터 없음)로 바꿉니다
+//
+// 그 밖의 값은 검증하지 않고 그대로 반환하므로 ValidateCustomMetadata로 확인해야 합니다.
+func NormalizeCustomMetadata(raw datatypes.JSON) datatypes.JSON {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return nil
+	}
+	return raw
+}
+
+// ValidateCustomMetadata 사용자 메타데이터가 JSON 객체인지, 크기와 키 수 제한 안인지 검증합니다
+//
+// 비어 있으면(메타데이터 없음) 통과합니다. MaxCustomMetadataSize를 넘으면
+// ErrCustomMetadataTooLarge를, JSON 객체가 아니면 ErrInvalidCustomMetadata를,
+// 최상위 키가 MaxCustomMetadataKeys를 넘으면 ErrTooManyMetadataKeys를 반환합니다.
+// 중첩된 객체의 키는 크기 제한으로만 제한합니다.
+func ValidateCustomMetadata(raw datatypes.JSON) error {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	if len(raw) > MaxCustomMetadataSize {
+		return fmt.Errorf("%w: %d bytes", ErrCustomMetadataTooLarge, len(raw))
+	}
+
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) == 0 || trimmed[0] != '{' {
+		return fmt.Errorf("%w: 최상위 값은 객체여야 합니다", ErrInvalidCustomMetadata)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCustomMetadata, err)
+	}
+
+	if len(fields) > MaxCustomMetadataKeys {
+		return fmt.Errorf("%w: %d개", ErrTooManyMetadataKeys, len(fields))
+	}
+
+	return nil
+}
+
+// CustomMetadataPath '.'으로 구분한 메타데이터 키를 SQLite JSON 경로로 바꿉니다
+//
+// "client.name"은 `$."client"."name"`이 되어 중첩 객체의 값을 가리킵니다. 구간마다
+// 따옴표로 감싸므로 키에 '$'나 '['가 있어도 경로 문법으로 해석되지 않습니다. 빈
+// 구간이 있거나, 따옴표/역슬래시를 포함하거나, MaxCustomMetadataKeyDepth보다
+// 깊으면 ErrInvalidMetadataKey를 반환합니다.
+func CustomMetadataPath(key string) (string, error) {
+	segments := strings.Split(key, ".")
+	if len(segments) > MaxCustomMetadataKeyDepth {
+		return "", fmt.Errorf("%w: %q는 %d단계를 넘습니다", ErrInvalidMetadataKey, key, MaxCustomMetadataKeyDepth)
+	}
+
+	var path strings.Builder
+	path.WriteString("$")
+	for _, segment := range segments {
+		if segment == "" || strings.ContainsAny(segment, "\"\\") {
+			return "", fmt.Errorf("%w: %q", ErrInvalidMetadataKey, key)
+		}
+		path.WriteString(`."`)
+		path.WriteString(segment)
+		path.WriteString(`"`)
+	}
+
+	return path.String(), nil
+}
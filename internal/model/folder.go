@@ -0,0 +1,191 @@
+// Package model provides database models for DataLocker application.
+// This file defines the folder hierarchy that groups files uploaded from a directory.
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 폴더 계층 제한
+const (
+	// MaxFolderDepth 최상위 폴더를 1로 셀 때 허용하는 최대 깊이
+	MaxFolderDepth = 16
+
+	// MaxFolderNameLength 폴더 이름 최대 길이 (bytes, 원본 파일명과 같음)
+	MaxFolderNameLength = MaxOriginalNameLength
+)
+
+// Folder 파일을 묶는 폴더 모델 (ParentID로 자기 자신을 참조하는 트리)
+//
+// 이름은 같은 상위 폴더 안에서 유일합니다. 생성/수정 훅이 상위 폴더를 따라 올라가며
+// 깊이가 MaxFolderDepth를 넘거나 자기 자신의 하위로 옮겨 순환이 생기는 것을 막습니다.
+type Folder struct {
+	// 기본 필드
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+
+	// 폴더 이름 (경로 구분자 없음, 최상위 폴더끼리는 부분 인덱스로 유일)
+	Name string `gorm:"type:varchar(255);not null;uniqueIndex:idx_folders_parent_name,priority:2;uniqueIndex:idx_folders_root_name,where:parent_id IS NULL" json:"name"`
+
+	// 상위 폴더 (nil이면 최상위)
+	ParentID *uint `gorm:"uniqueIndex:idx_folders_parent_name,priority:1" json:"parent_id,omitempty"`
+
+	// 관계: 1:N (Folder has many Folder, 상위 폴더를 지우면 하위 폴더도 삭제)
+	Children []*Folder `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"children,omitempty"`
+
+	// 관계: 1:N (Folder has many File, 폴더를 지우면 파일은 남고 폴더 지정만 해제)
+	Files []*File `gorm:"foreignKey:FolderID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"files,omitempty"`
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (Folder) TableName() string {
+	return "folders"
+}
+
+// BeforeCreate 생성 전 이름을 NFC로 정규화하고 이름과 깊이를 검증합니다
+func (f *Folder) BeforeCreate(tx *gorm.DB) error {
+	f.Name = NormalizeFileName(f.Name)
+	if err := ValidateFolderName(f.Name); err != nil {
+		return err
+	}
+
+	if f.ParentID == nil {
+		return nil
+	}
+
+	ancestors, err := folderAncestors(tx, *f.ParentID)
+	if err != nil {
+		return err
+	}
+	if len(ancestors)+1 > MaxFolderDepth {
+		return ErrFolderTooDeep
+	}
+
+	return nil
+}
+
+// BeforeUpdate 수정 전 이름을 정규화해 검증하고, 옮길 위치에서 순환과 깊이를 검증합니다
+//
+// 하위 폴더도 함께 옮겨지므로 가장 깊은 하위 폴더까지 MaxFolderDepth 안이어야 합니다.
+func (f *Folder) BeforeUpdate(tx *gorm.DB) error {
+	f.Name = NormalizeFileName(f.Name)
+	if err := ValidateFolderName(f.Name); err != nil {
+		return err
+	}
+
+	if f.ParentID == nil {
+		return nil
+	}
+
+	if *f.ParentID == f.ID {
+		return ErrFolderCycle
+	}
+
+	ancestors, err := folderAncestors(tx, *f.ParentID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ancestors {
+		if id == f.ID {
+			return ErrFolderCycle
+		}
+	}
+
+	var height int
+	err = tx.Session(&gorm.Session{NewDB: true}).Raw(`
+		WITH RECURSIVE descendants(id, level) AS (
+			SELECT id, 0 FROM folders WHERE id = ?
+			UNION ALL
+			SELECT folders.id, descendants.level + 1
+			FROM folders JOIN descendants ON folders.parent_id = descendants.id
+			WHERE descendants.level < ?
+		)
+		SELECT COALESCE(MAX(level), 0) FROM descendants`, f.ID, MaxFolderDepth).
+		Scan(&height).Error
+	if err != nil {
+		return fmt.Errorf("하위 폴더 깊이 확인 실패: %w", err)
+	}
+	if len(ancestors)+1+height > MaxFolderDepth {
+		return ErrFolderTooDeep
+	}
+
+	return nil
+}
+
+// folderAncestors 폴더 자신부터 최상위까지의 ID를 반환합니다
+//
+// MaxFolderDepth보다 한 단계 더 올라가면 멈추므로 결과가 그보다 길지 않습니다.
+// 폴더가 없으면 ErrFolderParentNotFound를 반환합니다.
+func folderAncestors(tx *gorm.DB, id uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Session(&gorm.Session{NewDB: true}).Raw(`
+		WITH RECURSIVE ancestors(id, parent_id, depth) AS (
+			SELECT id, parent_id, 1 FROM folders WHERE id = ?
+			UNION ALL
+			SELECT folders.id, folders.parent_id, ancestors.depth + 1
+			FROM folders JOIN ancestors ON folders.id = ancestors.parent_id
+			WHERE ancestors.depth <= ?
+		)
+		SELECT id FROM ancestors ORDER BY depth`, id, MaxFolderDepth).
+		Scan(&ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("상위 폴더 확인 실패: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return nil, ErrFolderParentNotFound
+	}
+	return ids, nil
+}
+
+// ValidateFolderName 폴더 이름 하나를 검증합니다
+//
+// 비어 있거나 공백만 있으면 ErrEmptyFolderName을, MaxFolderNameLength를 넘으면
+// ErrFolderNameTooLong을, 경로 구분자나 NUL 문자를 포함하거나 "."/".."이면
+// ErrInvalidFolderName을 반환합니다.
+func ValidateFolderName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrEmptyFolderName
+	}
+
+	if len(name) > MaxFolderNameLength {
+		return ErrFolderNameTooLong
+	}
+
+	if name == "." || name == ".." || strings.ContainsAny(name, "/\\\x00") {
+		return ErrInvalidFolderName
+	}
+
+	return nil
+}
+
+// SplitFolderPath 디렉터리 기준 상대 경로를 폴더 이름 목록으로 나눕니다
+//
+// '/'와 '\'를 모두 구분자로 보고, 빈 구간과 "."은 건너뛰며, 이름은 NFC로
+// 정규화합니다. ".."이 있거나 이름이 잘못되었으면 ValidateFolderName의 에러를,
+// MaxFolderDepth보다 깊으면 ErrFolderTooDeep을 반환합니다. 빈 경로는 빈 목록입니다.
+func SplitFolderPath(path string) ([]string, error) {
+	segments := strings.FieldsFunc(NormalizeFileName(path), func(r rune) bool { return r == '/' || r == '\\' })
+
+	names := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment == "." {
+			continue
+		}
+		if err := ValidateFolderName(segment); err != nil {
+			return nil, fmt.Errorf("폴더 경로 %q: %w", path, err)
+		}
+		names = append(names, segment)
+	}
+
+	if len(names) > MaxFolderDepth {
+		return nil, fmt.Errorf("폴더 경로 %q: %w", path, ErrFolderTooDeep)
+	}
+
+	return names, nil
+}
@@ -1,33 +1,124 @@
 // Package model provides database models for DataLocker application.
-// This file handles database migration and schema setup.
+// This file defines the schema migrations and the helpers they use.
 package model
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/mattn/go-sqlite3"
+	"golang.org/x/text/unicode/norm"
 	"gorm.io/gorm"
+
+	"DataLocker/internal/model/schema0001"
+)
+
+// legacyMetadataFileIndex 메타데이터가 파일당 하나였던 시절의 file_id 유일 인덱스
+const legacyMetadataFileIndex = "idx_encryption_metadata_file_id"
+
+// 짧은 파일 코드 채우기 설정
+const (
+	// shortCodeBackfillBatch 코드가 없는 파일을 한 번에 읽는 수
+	shortCodeBackfillBatch = 500
+
+	// shortCodeBackfillAttempts 파일 하나에 코드를 다시 만드는 최대 횟수
+	shortCodeBackfillAttempts = 5
+)
+
+// 파일명 NFC 정규화 설정
+const (
+	// fileNameBackfillBatch ASCII가 아닌 파일명을 한 번에 읽는 수
+	fileNameBackfillBatch = 500
+
+	// nonASCIIGlob ASCII 밖의 문자가 하나라도 있는 값을 고르는 GLOB 패턴 (ASCII는 항상 NFC)
+	nonASCIIGlob = "*[^\x01-\x7f]*"
 )
 
-// AllModels 마이그레이션할 모든 모델들
-var AllModels = []interface{}{
-	&File{},
-	&EncryptionMetadata{},
+// encryptedSizeBackfillBatch 암호화본 크기가 없는 파일을 한 번에 읽는 수
+const encryptedSizeBackfillBatch = 500
+
+// 키 슬롯 반복 횟수 컬럼 (마이그레이션 0003)
+const (
+	// keySlotIterationsColumn 추가할 key_slots 컬럼
+	keySlotIterationsColumn = "iterations"
+
+	// keySlotIterationsCheck 반복 횟수 범위 제약 이름 (GORM 기본 이름 chk_<테이블>_<컬럼>)
+	keySlotIterationsCheck = "chk_key_slots_iterations"
+
+	// addKeySlotIterationsSQL 컬럼과 범위 제약을 함께 추가하는 DDL
+	//
+	// SQLite는 기존 테이블에 제약만 따로 추가할 수 없어 GORM은 테이블을 다시 만들며
+	// 그 과정에서 인덱스를 잃으므로, 컬럼 제약으로 붙여 ALTER TABLE 한 번으로 추가합니다.
+	addKeySlotIterationsSQL = "ALTER TABLE `key_slots` ADD COLUMN `iterations` integer NOT NULL DEFAULT 100000 " +
+		"CONSTRAINT `" + keySlotIterationsCheck + "` CHECK (iterations >= 1000 AND iterations <= 1000000)"
+)
+
+// utcTimestampBatch UTC가 아닌 시각을 한 번에 읽는 행 수
+const utcTimestampBatch = 500
+
+// utcTimestampColumns UTC로 정규화할 테이블별 시각 컬럼
+//
+// 기간 조회와 키셋 페이지네이션은 저장된 시각 문자열을 그대로 비교하므로 모든
+// 행의 오프셋이 같아야 합니다.
+var utcTimestampColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"users", []string{"created_at"}},
+	{"tags", []string{"created_at"}},
+	{"folders", []string{"created_at", "updated_at"}},
+	{"files", []string{"created_at", "updated_at", "deleted_at", "last_accessed_at"}},
+	{"encryption_metadata", []string{"created_at", "updated_at"}},
+	{"key_slots", []string{"created_at", "updated_at"}},
+	{"validation_sessions", []string{"created_at", "expires_at"}},
+	{"metrics_snapshots", []string{"created_at"}},
+	{"idempotency_records", []string{"created_at", "expires_at"}},
+	{"file_locks", []string{"created_at", "expires_at"}},
 }
 
-// Migrate 데이터베이스 마이그레이션을 수행합니다
-func Migrate(db *gorm.DB) error {
-	if db == nil {
-		return fmt.Errorf("데이터베이스 연결이 없습니다")
+// migrations 적용 순서대로 나열한 스키마 마이그레이션 (ID는 사전순으로 증가)
+//
+// 이미 적용된 마이그레이션은 고치지 말고, 컬럼 이름 변경이나 데이터 채우기 같은
+// 변경은 새 마이그레이션을 뒤에 추가합니다.
+var migrations = []Migration{
+	{ID: "0001_initial_schema", Up: migrateInitialSchema, Down: dropSchemaTables},
+	{ID: "0002_utc_timestamps", Up: normalizeTimestampsToUTC, Down: noopMigration},
+	{ID: "0003_key_slot_iterations", Up: addKeySlotIterations, Down: dropKeySlotIterations},
+}
+
+// migrateInitialSchema 버전 관리 도입 시점의 스키마를 만듭니다 (마이그레이션 0001)
+//
+// 버전 관리 전에 AutoMigrate로 만든 데이터베이스도 이 마이그레이션으로 받아들이므로,
+// 당시 모델을 고정한 schema0001 구조체 기준으로 테이블을 맞추고 그동안의 스키마
+// 전환과 데이터 채우기를 함께 실행합니다. 현재 모델이 바뀌어도 0001이 만드는
+// 스키마는 그대로이며, 모두 이미 반영된 데이터베이스에서는 아무것도 바꾸지 않습니다.
+func migrateInitialSchema(db *gorm.DB) error {
+	// 0001 시점 구조체 기준 테이블 생성
+	if err := db.AutoMigrate(schema0001.Models...); err != nil {
+		return fmt.Errorf("자동 마이그레이션 실패: %w", err)
 	}
 
-	// 외래키 제약조건 강제 활성화 (마이그레이션 전)
-	if err := db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
-		return fmt.Errorf("외래키 제약조건 활성화 실패: %w", err)
+	// 파일당 1개였던 암호화 메타데이터를 용도별 1:N 스키마로 전환
+	if err := migrateMetadataPurpose(db); err != nil {
+		return fmt.Errorf("암호화 메타데이터 스키마 전환 실패: %w", err)
 	}
 
-	// 자동 마이그레이션 실행
-	if err := db.AutoMigrate(AllModels...); err != nil {
-		return fmt.Errorf("자동 마이그레이션 실패: %w", err)
+	// 짧은 파일 코드 도입 전 파일에 코드 부여
+	if err := backfillShortCodes(db); err != nil {
+		return fmt.Errorf("짧은 파일 코드 채우기 실패: %w", err)
+	}
+
+	// NFD로 저장된 기존 파일명을 NFC로 정규화
+	if err := normalizeFileNames(db); err != nil {
+		return fmt.Errorf("파일명 정규화 실패: %w", err)
+	}
+
+	// 크기 기록 도입 전 파일의 암호화본 크기를 디스크에서 채움
+	if err := backfillEncryptedSizes(db); err != nil {
+		return fmt.Errorf("암호화본 크기 채우기 실패: %w", err)
 	}
 
 	// 추가 인덱스 생성
@@ -43,6 +134,281 @@ func Migrate(db *gorm.DB) error {
 	return nil
 }
 
+// migrateMetadataPurpose 기존 file_id 유일 인덱스를 제거하고 기존 레코드를 primary로 지정합니다
+//
+// AutoMigrate는 더 이상 모델에 없는 인덱스를 지우지 않으므로 직접 제거해야
+// 같은 파일에 version, keyslot 용도의 메타데이터를 추가할 수 있습니다.
+func migrateMetadataPurpose(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if migrator.HasIndex(&schema0001.EncryptionMetadata{}, legacyMetadataFileIndex) {
+		if err := migrator.DropIndex(&schema0001.EncryptionMetadata{}, legacyMetadataFileIndex); err != nil {
+			return fmt.Errorf("기존 인덱스 %s 삭제 실패: %w", legacyMetadataFileIndex, err)
+		}
+	}
+
+	err := db.Exec("UPDATE encryption_metadata SET purpose = ?, slot = 0 WHERE purpose IS NULL OR purpose = ''",
+		MetadataPurposePrimary).Error
+	if err != nil {
+		return fmt.Errorf("기존 메타데이터 용도 갱신 실패: %w", err)
+	}
+
+	return nil
+}
+
+// backfillShortCodes 짧은 코드가 없는 파일(소프트 삭제 포함)에 코드를 부여합니다
+//
+// 이미 코드가 있는 파일은 건드리지 않으므로 여러 번 실행해도 안전합니다.
+// 다른 파일의 코드와 충돌하면 새 코드로 다시 시도합니다.
+func backfillShortCodes(db *gorm.DB) error {
+	for {
+		var ids []uint
+		err := db.Unscoped().Model(&schema0001.File{}).
+			Where("short_code = '' OR short_code IS NULL").
+			Order("id").Limit(shortCodeBackfillBatch).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return fmt.Errorf("코드가 없는 파일 조회 실패: %w", err)
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, id := range ids {
+			if err := assignShortCode(db, id); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// assignShortCode 파일 하나에 충돌하지 않는 짧은 코드를 부여합니다
+func assignShortCode(db *gorm.DB, id uint) error {
+	for attempt := 1; ; attempt++ {
+		code, err := NewShortCode()
+		if err != nil {
+			return err
+		}
+
+		err = db.Unscoped().Model(&schema0001.File{}).
+			Where("id = ? AND (short_code = '' OR short_code IS NULL)", id).
+			UpdateColumn("short_code", code).Error
+		if err == nil {
+			return nil
+		}
+
+		if !isShortCodeCollision(err) || attempt >= shortCodeBackfillAttempts {
+			return fmt.Errorf("파일 %d 코드 부여 실패: %w", id, err)
+		}
+	}
+}
+
+// isShortCodeCollision 짧은 코드 유일 인덱스 위반인지 확인합니다
+func isShortCodeCollision(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique &&
+		strings.Contains(sqliteErr.Error(), "short_code")
+}
+
+// normalizeFileNames NFC가 아닌 원본 파일명(소프트 삭제 포함)을 NFC로 바꿉니다
+//
+// 훅이 저장할 때 정규화하기 전의 레코드를 위한 것으로, ASCII가 아닌 이름만 ID순으로
+// 읽어 바뀌는 행만 갱신하므로 여러 번 실행해도 안전합니다. 파일 내용은 그대로이므로
+// 버전과 updated_at은 바꾸지 않습니다.
+func normalizeFileNames(db *gorm.DB) error {
+	var lastID uint
+	for {
+		var rows []struct {
+			ID           uint
+			OriginalName string
+		}
+		err := db.Unscoped().Model(&schema0001.File{}).
+			Select("id", "original_name").
+			Where("id > ? AND original_name GLOB ?", lastID, nonASCIIGlob).
+			Order("id").Limit(fileNameBackfillBatch).
+			Find(&rows).Error
+		if err != nil {
+			return fmt.Errorf("파일명 조회 실패: %w", err)
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			if norm.NFC.IsNormalString(row.OriginalName) {
+				continue
+			}
+
+			err := db.Unscoped().Model(&schema0001.File{}).Where("id = ?", row.ID).
+				UpdateColumn("original_name", NormalizeFileName(row.OriginalName)).Error
+			if err != nil {
+				return fmt.Errorf("파일 %d 이름 정규화 실패: %w", row.ID, err)
+			}
+		}
+	}
+}
+
+// backfillEncryptedSizes 암호화본 크기가 0인 blob 소유 파일(소프트 삭제 포함)의 크기를 디스크에서 읽어 채웁니다
+//
+// 암호화본을 찾을 수 없는 파일(볼륨 오프라인, 유실)은 0으로 남겨 다음 실행이나
+// 관리 API의 크기 점검(reconcile-sizes)에서 다시 확인합니다. 파일 내용은 그대로이므로
+// 버전과 updated_at은 바꾸지 않습니다.
+func backfillEncryptedSizes(db *gorm.DB) error {
+	var lastID uint
+	for {
+		var rows []struct {
+			ID            uint
+			EncryptedPath string
+		}
+		err := db.Unscoped().Model(&schema0001.File{}).
+			Select("id", "encrypted_path").
+			Where("id > ? AND encrypted_size = 0 AND blob_file_id IS NULL", lastID).
+			Order("id").Limit(encryptedSizeBackfillBatch).
+			Find(&rows).Error
+		if err != nil {
+			return fmt.Errorf("암호화본 크기가 없는 파일 조회 실패: %w", err)
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			info, err := os.Stat(row.EncryptedPath)
+			if err != nil || !info.Mode().IsRegular() || info.Size() == 0 {
+				continue
+			}
+
+			err = db.Unscoped().Model(&schema0001.File{}).Where("id = ?", row.ID).
+				UpdateColumn("encrypted_size", info.Size()).Error
+			if err != nil {
+				return fmt.Errorf("파일 %d 암호화본 크기 갱신 실패: %w", row.ID, err)
+			}
+		}
+	}
+}
+
+// normalizeTimestampsToUTC 호스트 시간대 오프셋으로 저장된 시각을 UTC로 다시 씁니다 (마이그레이션 0002)
+//
+// 연결 설정에 UTC NowFunc가 없던 시절에는 호스트 시간대로 저장되어, 문자열로
+// 비교하는 조회가 행마다 다른 오프셋을 섞어 비교했습니다. 같은 시각을 표기만
+// 바꾸므로 버전과 updated_at은 그대로 둡니다.
+func normalizeTimestampsToUTC(db *gorm.DB) error {
+	for _, entry := range utcTimestampColumns {
+		for _, column := range entry.columns {
+			if err := normalizeColumnToUTC(db, entry.table, column); err != nil {
+				return fmt.Errorf("%s.%s UTC 정규화 실패: %w", entry.table, column, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizeColumnToUTC 한 컬럼에서 UTC 오프셋이 아닌 값을 rowid 순서로 읽어 UTC로 갱신합니다
+//
+// 시각으로 해석할 수 없는 값은 건너뛰므로 여러 번 실행해도 안전합니다.
+func normalizeColumnToUTC(db *gorm.DB, table, column string) error {
+	selectSQL := fmt.Sprintf("SELECT rowid, %[1]s FROM %[2]s WHERE rowid > ? AND %[1]s IS NOT NULL "+
+		"AND CAST(%[1]s AS TEXT) NOT LIKE '%%+00:00' ORDER BY rowid LIMIT ?", column, table)
+	updateSQL := fmt.Sprintf("UPDATE %s SET %s = ? WHERE rowid = ?", table, column)
+
+	var lastRowID int64
+	for {
+		rows, err := db.Raw(selectSQL, lastRowID, utcTimestampBatch).Rows()
+		if err != nil {
+			return fmt.Errorf("시각 조회 실패: %w", err)
+		}
+
+		type pending struct {
+			rowID int64
+			value time.Time
+		}
+		var updates []pending
+		var read int
+		for rows.Next() {
+			var rowID int64
+			var value any
+			if err := rows.Scan(&rowID, &value); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("시각 읽기 실패: %w", err)
+			}
+			read++
+			lastRowID = rowID
+			// 드라이버가 시각으로 해석하지 못한 값(문자열)은 그대로 둠
+			if t, ok := value.(time.Time); ok {
+				updates = append(updates, pending{rowID: rowID, value: t.UTC()})
+			}
+		}
+		if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+			return fmt.Errorf("시각 조회 실패: %w", err)
+		}
+
+		if read == 0 {
+			return nil
+		}
+
+		for _, update := range updates {
+			if err := db.Exec(updateSQL, update.value, update.rowID).Error; err != nil {
+				return fmt.Errorf("행 %d 시각 갱신 실패: %w", update.rowID, err)
+			}
+		}
+	}
+}
+
+// addKeySlotIterations 키 슬롯에 반복 횟수 컬럼과 범위 제약을 추가합니다 (마이그레이션 0003)
+//
+// 기존 슬롯은 도입 전 고정값이던 DefaultIterations로 채워집니다. 0001이 현재 모델
+// 기준으로 테이블을 만들던 시절에 이미 컬럼이 생긴 데이터베이스에서는 아무것도
+// 바꾸지 않습니다.
+func addKeySlotIterations(db *gorm.DB) error {
+	if db.Migrator().HasColumn(&schema0001.KeySlot{}, keySlotIterationsColumn) {
+		return nil
+	}
+
+	if err := db.Exec(addKeySlotIterationsSQL).Error; err != nil {
+		return fmt.Errorf("키 슬롯 반복 횟수 컬럼 추가 실패: %w", err)
+	}
+
+	return nil
+}
+
+// dropKeySlotIterations 키 슬롯의 반복 횟수 제약과 컬럼을 제거합니다 (마이그레이션 0003 되돌리기)
+//
+// 제약과 컬럼을 지우면서 테이블을 다시 만들어 잃은 인덱스는 0001 구조체 기준으로
+// 되살립니다.
+func dropKeySlotIterations(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if migrator.HasConstraint(&schema0001.KeySlot{}, keySlotIterationsCheck) {
+		if err := migrator.DropConstraint(&schema0001.KeySlot{}, keySlotIterationsCheck); err != nil {
+			return fmt.Errorf("키 슬롯 반복 횟수 제약 삭제 실패: %w", err)
+		}
+	}
+
+	if migrator.HasColumn(&schema0001.KeySlot{}, keySlotIterationsColumn) {
+		if err := migrator.DropColumn(&schema0001.KeySlot{}, keySlotIterationsColumn); err != nil {
+			return fmt.Errorf("키 슬롯 반복 횟수 컬럼 삭제 실패: %w", err)
+		}
+	}
+
+	if err := db.AutoMigrate(&schema0001.KeySlot{}); err != nil {
+		return fmt.Errorf("키 슬롯 인덱스 복원 실패: %w", err)
+	}
+
+	return nil
+}
+
+// noopMigration 되돌릴 것이 없는 마이그레이션의 Down
+//
+// 표기만 바꾼 데이터 정리처럼 되돌리지 않아도 이전 스키마와 호환되는 경우에 씁니다.
+func noopMigration(*gorm.DB) error {
+	return nil
+}
+
 // createAdditionalIndexes 추가 인덱스를 생성합니다
 func createAdditionalIndexes(db *gorm.DB) error {
 	// 복합 인덱스 생성
@@ -61,6 +427,18 @@ func createAdditionalIndexes(db *gorm.DB) error {
 			name:    "idx_files_original_name_status",
 			columns: []string{"original_name", "status"},
 		},
+		{
+			// 대소문자를 무시하는 MIME 타입 조회(정확히 일치, type/% 접두 일치)에 사용
+			table:   "files",
+			name:    "idx_files_mime_type_created_at",
+			columns: []string{"mime_type COLLATE NOCASE", "created_at"},
+		},
+		{
+			// 태그별 파일 조회에 사용 (기본키 file_id, tag_id는 파일별 조회만 지원)
+			table:   "file_tags",
+			name:    "idx_file_tags_tag_id",
+			columns: []string{"tag_id"},
+		},
 		{
 			table:   "encryption_metadata",
 			name:    "idx_encryption_algorithm_created_at",
@@ -181,19 +559,28 @@ func validateCheckConstraints(db *gorm.DB) error {
 	return nil
 }
 
-// DropAllTables 모든 테이블을 삭제합니다 (테스트용)
+// DropAllTables 마이그레이션 기록을 포함한 모든 테이블을 삭제합니다 (테스트와 공장 초기화용)
 func DropAllTables(db *gorm.DB) error {
 	if db == nil {
 		return fmt.Errorf("데이터베이스 연결이 없습니다")
 	}
 
-	// 외래키 제약조건 때문에 역순으로 삭제
-	models := []interface{}{
-		&EncryptionMetadata{},
-		&File{},
+	if err := dropSchemaTables(db); err != nil {
+		return err
+	}
+
+	// 기록도 지워야 다시 마이그레이션할 때 0001부터 적용
+	if err := db.Migrator().DropTable(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("마이그레이션 기록 테이블 삭제 실패: %w", err)
 	}
 
-	for _, model := range models {
+	return nil
+}
+
+// dropSchemaTables 0001이 만든 테이블을 모두 삭제합니다 (마이그레이션 0001 되돌리기)
+func dropSchemaTables(db *gorm.DB) error {
+	// 외래키 제약조건 때문에 역순으로 삭제
+	for _, model := range schema0001.DropOrder {
 		if err := db.Migrator().DropTable(model); err != nil {
 			return fmt.Errorf("테이블 삭제 실패: %w", err)
 		}
@@ -202,13 +589,13 @@ func DropAllTables(db *gorm.DB) error {
 	return nil
 }
 
-// ResetDatabase 데이터베이스를 초기화합니다 (테스트용)
+// ResetDatabase 모든 테이블을 지우고 다시 만들어 데이터베이스를 초기화합니다 (테스트와 공장 초기화용)
 func ResetDatabase(db *gorm.DB) error {
 	if err := DropAllTables(db); err != nil {
 		return fmt.Errorf("테이블 삭제 실패: %w", err)
 	}
 
-	if err := Migrate(db); err != nil {
+	if _, err := MigrateUp(db); err != nil {
 		return fmt.Errorf("마이그레이션 실패: %w", err)
 	}
 
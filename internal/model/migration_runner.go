@@ -0,0 +1,228 @@
+// Package model provides database models for DataLocker application.
+// This file implements the versioned migration runner backed by the schema_migrations table.
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration 순서대로 한 번씩 적용하는 스키마 변경
+//
+// ID는 사전순으로 적용 순서를 정하므로 "0002_add_xxx"처럼 번호를 앞에 붙입니다.
+// Up과 Down은 마이그레이션마다 하나의 트랜잭션 안에서 실행되며, 적용 기록도 같은
+// 트랜잭션에서 남기므로 실패하면 기록 없이 되돌려집니다. 되돌릴 수 없는 변경은
+// Down을 nil로 둡니다.
+type Migration struct {
+	ID   string
+	Up   func(*gorm.DB) error
+	Down func(*gorm.DB) error
+}
+
+// SchemaMigration 적용한 마이그레이션 기록
+type SchemaMigration struct {
+	ID        string    `gorm:"primaryKey;type:varchar(100)" json:"id"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationState 마이그레이션 하나의 적용 상태
+type MigrationState struct {
+	ID        string     `json:"id"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+
+	// 적용 기록은 있지만 이 빌드에 없는 마이그레이션 (더 새 버전이 적용함)
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// MigrateUp 아직 적용하지 않은 마이그레이션을 순서대로 적용하고 적용한 ID를 반환합니다
+//
+// 모두 적용된 데이터베이스에서는 아무것도 하지 않으므로 시작할 때마다 호출해도
+// 안전합니다. 이 빌드가 모르는 마이그레이션이 기록되어 있으면 ErrUnknownMigration을
+// 반환하고 아무것도 적용하지 않습니다.
+func MigrateUp(db *gorm.DB) ([]string, error) {
+	return migrateUp(db, migrations)
+}
+
+// MigrateDown 마지막으로 적용한 마이그레이션부터 steps개를 되돌리고 되돌린 ID를 반환합니다
+//
+// 적용한 수보다 steps가 크면 적용한 것을 모두 되돌립니다. Down이 없는
+// 마이그레이션을 만나면 그 앞에서 멈추고 ErrIrreversibleMigration을 반환합니다.
+func MigrateDown(db *gorm.DB, steps int) ([]string, error) {
+	return migrateDown(db, migrations, steps)
+}
+
+// MigrationStatus 모든 마이그레이션의 적용 상태를 ID 순서로 반환합니다
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	return migrationStatus(db, migrations)
+}
+
+// migrateUp list 중 적용하지 않은 마이그레이션을 순서대로 적용합니다
+func migrateUp(db *gorm.DB, list []Migration) ([]string, error) {
+	applied, err := prepareMigrations(db, list)
+	if err != nil {
+		return nil, err
+	}
+
+	// 외래키 설정은 트랜잭션 안에서 바꿀 수 없으므로 먼저 켬
+	if err := db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+		return nil, fmt.Errorf("외래키 제약조건 활성화 실패: %w", err)
+	}
+
+	var ran []string
+	for _, migration := range list {
+		if _, ok := applied[migration.ID]; ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{ID: migration.ID, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return ran, fmt.Errorf("마이그레이션 %s 적용 실패: %w", migration.ID, err)
+		}
+		ran = append(ran, migration.ID)
+	}
+
+	return ran, nil
+}
+
+// migrateDown list 중 마지막으로 적용한 마이그레이션부터 steps개를 되돌립니다
+func migrateDown(db *gorm.DB, list []Migration, steps int) ([]string, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidMigrationSteps, steps)
+	}
+
+	applied, err := prepareMigrations(db, list)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []string
+	for _, migration := range slices.Backward(list) {
+		if len(reverted) == steps {
+			break
+		}
+		if _, ok := applied[migration.ID]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return reverted, fmt.Errorf("%w: %s", ErrIrreversibleMigration, migration.ID)
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{ID: migration.ID}).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("마이그레이션 %s 되돌리기 실패: %w", migration.ID, err)
+		}
+		reverted = append(reverted, migration.ID)
+	}
+
+	return reverted, nil
+}
+
+// migrationStatus list와 적용 기록을 합쳐 ID 순서로 반환합니다
+func migrationStatus(db *gorm.DB, list []Migration) ([]MigrationState, error) {
+	if err := validateMigrations(list); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(list)+len(applied))
+	for _, migration := range list {
+		state := MigrationState{ID: migration.ID}
+		if record, ok := applied[migration.ID]; ok {
+			state.Applied, state.AppliedAt = true, &record.AppliedAt
+			delete(applied, migration.ID)
+		}
+		states = append(states, state)
+	}
+	for _, record := range applied {
+		states = append(states, MigrationState{ID: record.ID, Applied: true, AppliedAt: &record.AppliedAt, Unknown: true})
+	}
+
+	slices.SortFunc(states, func(a, b MigrationState) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return states, nil
+}
+
+// prepareMigrations 목록을 검증하고 기록 테이블을 만든 뒤 적용한 마이그레이션을 읽습니다
+//
+// 이 빌드가 모르는 마이그레이션이 기록되어 있으면 ErrUnknownMigration을 반환합니다.
+func prepareMigrations(db *gorm.DB, list []Migration) (map[string]SchemaMigration, error) {
+	if db == nil {
+		return nil, fmt.Errorf("데이터베이스 연결이 없습니다")
+	}
+	if err := validateMigrations(list); err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("마이그레이션 기록 테이블 생성 실패: %w", err)
+	}
+
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	for id := range applied {
+		if !slices.ContainsFunc(list, func(m Migration) bool { return m.ID == id }) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownMigration, id)
+		}
+	}
+
+	return applied, nil
+}
+
+// appliedMigrations 적용 기록을 ID별로 읽습니다 (기록 테이블이 없으면 빈 값)
+func appliedMigrations(db *gorm.DB) (map[string]SchemaMigration, error) {
+	if db == nil {
+		return nil, fmt.Errorf("데이터베이스 연결이 없습니다")
+	}
+
+	applied := make(map[string]SchemaMigration)
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return applied, nil
+	}
+
+	var records []SchemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("마이그레이션 기록 조회 실패: %w", err)
+	}
+	for _, record := range records {
+		applied[record.ID] = record
+	}
+	return applied, nil
+}
+
+// validateMigrations ID가 비어 있지 않고 사전순으로 증가하며 Up이 있는지 확인합니다
+func validateMigrations(list []Migration) error {
+	for i, migration := range list {
+		if migration.ID == "" || migration.Up == nil {
+			return fmt.Errorf("마이그레이션 %d번째 항목에 ID나 Up이 없습니다", i+1)
+		}
+		if i > 0 && list[i-1].ID >= migration.ID {
+			return fmt.Errorf("마이그레이션 ID는 사전순으로 증가해야 합니다: %s 다음 %s", list[i-1].ID, migration.ID)
+		}
+	}
+	return nil
+}
@@ -1,14 +1,28 @@
 package model
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"DataLocker/internal/database"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/datatypes"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -35,7 +49,8 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 	// 데이터베이스 연결 (외래키 활성화 포함)
 	dsn := dbPath + "?_foreign_keys=ON&_journal_mode=WAL&_sync=NORMAL"
 	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent), // 테스트 시 로그 최소화
+		Logger:  logger.Default.LogMode(logger.Silent), // 테스트 시 로그 최소화
+		NowFunc: database.NowUTC,
 	})
 	require.NoError(t, err)
 
@@ -53,7 +68,7 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 	}
 
 	// 마이그레이션 실행
-	err = Migrate(db)
+	_, err = MigrateUp(db)
 	require.NoError(t, err)
 
 	// 정리 함수 반환
@@ -69,6 +84,26 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 	return db, cleanup
 }
 
+// migrateLegacyDB 마이그레이션 기록을 지워 버전 관리 도입 전 데이터베이스로 만든 뒤 다시 마이그레이션합니다
+//
+// AutoMigrate 시절의 데이터베이스는 기록 없이 테이블만 있으므로 모든 마이그레이션이 다시 적용됩니다.
+func migrateLegacyDB(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	require.NoError(t, db.Migrator().DropTable(&SchemaMigration{}))
+	applied, err := MigrateUp(db)
+	require.NoError(t, err)
+	require.Equal(t, migrationIDs(), applied)
+}
+
+// migrationIDs 이 빌드의 마이그레이션 ID를 적용 순서대로 반환합니다
+func migrationIDs() []string {
+	ids := make([]string, 0, len(migrations))
+	for _, migration := range migrations {
+		ids = append(ids, migration.ID)
+	}
+	return ids
+}
+
 // createTestFile 테스트용 File 모델을 생성합니다
 func createTestFile() *File {
 	return &File{
@@ -144,6 +179,48 @@ func TestFile_CRUD_Operations(t *testing.T) {
 	assert.Error(t, err) // 삭제된 레코드이므로 에러가 발생해야 함
 }
 
+func TestFile_AuditFields(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 컨텍스트에 행위자가 없으면 system
+	file := createTestFile()
+	require.NoError(t, db.Create(file).Error)
+	assert.Equal(t, ActorSystem, file.CreatedBy)
+	assert.Equal(t, ActorSystem, file.UpdatedBy)
+
+	// 수정하면 수정자만 바뀜
+	adminDB := db.WithContext(WithActor(context.Background(), ActorAdmin))
+	file.Status = FileStatusEncrypted
+	require.NoError(t, adminDB.Save(file).Error)
+
+	var stored File
+	require.NoError(t, db.First(&stored, file.ID).Error)
+	assert.Equal(t, ActorSystem, stored.CreatedBy)
+	assert.Equal(t, ActorAdmin, stored.UpdatedBy)
+
+	// 트랜잭션에도 컨텍스트가 전달됨
+	err := db.WithContext(WithActor(context.Background(), ActorAnonymous)).Transaction(func(tx *gorm.DB) error {
+		other := createTestFile()
+		other.EncryptedPath = "/encrypted/audit.enc"
+		if err := tx.Create(other).Error; err != nil {
+			return err
+		}
+		assert.Equal(t, ActorAnonymous, other.CreatedBy)
+		assert.Equal(t, ActorAnonymous, other.UpdatedBy)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// 너무 긴 행위자는 거부
+	long := createTestFile()
+	long.EncryptedPath = "/encrypted/audit_long.enc"
+	err = db.WithContext(WithActor(context.Background(), strings.Repeat("a", MaxActorLength+1))).Create(long).Error
+	assert.ErrorIs(t, err, ErrActorTooLong)
+
+	assert.Equal(t, ActorSystem, ActorFromContext(WithActor(context.Background(), "")))
+}
+
 func TestEncryptionMetadata_CRUD_Operations(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -341,6 +418,22 @@ func TestEncryptionMetadata_Validation(t *testing.T) {
 			expectError: true,
 			errorType:   ErrInvalidIterations,
 		},
+		{
+			name: "잘못된 패스워드 지문",
+			modifyMetadata: func(m *EncryptionMetadata) {
+				m.PasswordFingerprint = "abcd"
+			},
+			expectError: true,
+			errorType:   ErrInvalidPasswordFingerprint,
+		},
+		{
+			name: "지원하지 않는 압축 알고리즘",
+			modifyMetadata: func(m *EncryptionMetadata) {
+				m.Compression = "zstd"
+			},
+			expectError: true,
+			errorType:   ErrInvalidCompression,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -362,6 +455,29 @@ func TestEncryptionMetadata_Validation(t *testing.T) {
 	}
 }
 
+func TestEncryptionMetadata_Compression(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for _, compression := range []string{"", CompressionGzip} {
+		file := createTestFile()
+		file.EncryptedPath += compression
+		require.NoError(t, db.Create(file).Error)
+
+		metadata := createTestEncryptionMetadata(file.ID)
+		metadata.Compression = compression
+		require.NoError(t, db.Create(metadata).Error)
+
+		var stored EncryptionMetadata
+		require.NoError(t, db.First(&stored, metadata.ID).Error)
+		if compression == "" {
+			assert.Equal(t, CompressionNone, stored.Compression)
+		} else {
+			assert.Equal(t, compression, stored.Compression)
+		}
+	}
+}
+
 func TestFile_Methods(t *testing.T) {
 	file := createTestFile()
 
@@ -388,6 +504,14 @@ func TestFile_Methods(t *testing.T) {
 	file.Size = 1048576 // 1MB
 	assert.Equal(t, 1.0, file.GetSizeInMB())
 	assert.Equal(t, 1024.0, file.GetSizeInKB())
+
+	// Similarity signature: 상위 비트가 켜진 값도 그대로 복원
+	_, ok := file.SimilaritySignature()
+	assert.False(t, ok)
+	file.SetSimilaritySignature(0xfedcba9876543210)
+	signature, ok := file.SimilaritySignature()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0xfedcba9876543210), signature)
 }
 
 func TestEncryptionMetadata_Methods(t *testing.T) {
@@ -474,6 +598,125 @@ func TestCascadeDelete(t *testing.T) {
 	}
 }
 
+func TestUser_Validation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(u *User)
+		want   error
+	}{
+		{"정상", func(u *User) {}, nil},
+		{"이름 없음", func(u *User) { u.Username = "" }, ErrEmptyUsername},
+		{"이름이 김", func(u *User) { u.Username = strings.Repeat("a", MaxUsernameLength+1) }, ErrUsernameTooLong},
+		{"허용하지 않는 문자", func(u *User) { u.Username = "alice bob" }, ErrInvalidUsername},
+		{"한글 이름", func(u *User) { u.Username = "앨리스" }, ErrInvalidUsername},
+		{"해시 없음", func(u *User) { u.PasswordHash = "" }, ErrEmptyPasswordHash},
+		{"해시가 김", func(u *User) { u.PasswordHash = strings.Repeat("h", MaxPasswordHashLength+1) }, ErrPasswordHashTooLong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &User{Username: "alice.kim-01", PasswordHash: "$argon2id$v=19$hash"}
+			tc.modify(user)
+			assert.ErrorIs(t, user.validate(), tc.want)
+		})
+	}
+
+	file := createTestFile()
+	zero := uint(0)
+	file.OwnerID = &zero
+	assert.ErrorIs(t, file.validate(), ErrInvalidOwnerID)
+}
+
+func TestUser_FileOwnership(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	user := &User{Username: "alice", PasswordHash: "hash"}
+	require.NoError(t, db.Create(user).Error)
+
+	// 이름은 유일
+	err := db.Create(&User{Username: "alice", PasswordHash: "other"}).Error
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "UNIQUE constraint failed")
+
+	// 소유자가 없는 파일(기존 파일)도 그대로 저장
+	legacy := createTestFile()
+	require.NoError(t, db.Create(legacy).Error)
+
+	owned := createTestFile()
+	owned.EncryptedPath = "/encrypted/owned.enc"
+	owned.OwnerID = &user.ID
+	require.NoError(t, db.Create(owned).Error)
+
+	// 존재하지 않는 사용자를 소유자로 지정할 수 없음
+	missing := uint(99999)
+	orphan := createTestFile()
+	orphan.EncryptedPath = "/encrypted/orphan.enc"
+	orphan.OwnerID = &missing
+	err = db.Create(orphan).Error
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "FOREIGN KEY constraint failed")
+
+	// 사용자를 지우면 소유한 파일 레코드도 CASCADE로 삭제
+	require.NoError(t, db.Delete(user).Error)
+	var remaining []uint
+	require.NoError(t, db.Unscoped().Model(&File{}).Order("id").Pluck("id", &remaining).Error)
+	assert.Equal(t, []uint{legacy.ID}, remaining)
+}
+
+func TestNormalizeTagName(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+		err   error
+	}{
+		{"소문자로 변환", "Tax-2024", "tax-2024", nil},
+		{"앞뒤 공백 제거", "  personal\t", "personal", nil},
+		{"한글", " 세금 ", "세금", nil},
+		{"최대 길이 (문자 수 기준)", strings.Repeat("가", MaxTagNameLength), strings.Repeat("가", MaxTagNameLength), nil},
+		{"빈 이름", "", "", ErrEmptyTagName},
+		{"공백만", "   ", "", ErrEmptyTagName},
+		{"너무 김", strings.Repeat("a", MaxTagNameLength+1), "", ErrTagNameTooLong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NormalizeTagName(tc.input)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestTag_FileTags(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 저장할 때 이름을 정규화하고, 정규화한 이름은 유일
+	tag := &Tag{Name: " Tax-2024 "}
+	require.NoError(t, db.Create(tag).Error)
+	assert.Equal(t, "tax-2024", tag.Name)
+	assert.Error(t, db.Create(&Tag{Name: "TAX-2024"}).Error)
+	assert.ErrorIs(t, db.Create(&Tag{Name: " "}).Error, ErrEmptyTagName)
+
+	file := createTestFile()
+	file.Tags = []*Tag{tag}
+	require.NoError(t, db.Create(file).Error)
+
+	var loaded File
+	require.NoError(t, db.Preload("Tags").First(&loaded, file.ID).Error)
+	require.Len(t, loaded.Tags, 1)
+	assert.Equal(t, "tax-2024", loaded.Tags[0].Name)
+
+	// 파일을 영구 삭제하면 연결 행도 삭제되고 태그는 남음
+	require.NoError(t, db.Unscoped().Delete(&File{}, file.ID).Error)
+	var links int64
+	require.NoError(t, db.Table("file_tags").Count(&links).Error)
+	assert.Zero(t, links)
+	assert.NoError(t, db.First(&Tag{}, tag.ID).Error)
+}
+
 func TestUniqueConstraint(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -493,6 +736,201 @@ func TestUniqueConstraint(t *testing.T) {
 	assert.Contains(t, err.Error(), "UNIQUE constraint failed")
 }
 
+func TestEncryptionMetadata_Purpose(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	file := createTestFile()
+	require.NoError(t, db.Create(file).Error)
+
+	newMetadata := func(purpose string, slot int) *EncryptionMetadata {
+		metadata := createTestEncryptionMetadata(file.ID)
+		metadata.Purpose = purpose
+		metadata.Slot = slot
+		return metadata
+	}
+
+	// 용도를 비우면 primary로 저장됨
+	primary := newMetadata("", 0)
+	require.NoError(t, db.Create(primary).Error)
+	assert.Equal(t, MetadataPurposePrimary, primary.Purpose)
+	assert.True(t, primary.IsPrimary())
+
+	// 같은 파일에 여러 용도의 메타데이터를 둘 수 있음
+	require.NoError(t, db.Create(newMetadata(MetadataPurposeVersion, 0)).Error)
+	require.NoError(t, db.Create(newMetadata(MetadataPurposeVersion, 1)).Error)
+	require.NoError(t, db.Create(newMetadata(MetadataPurposeKeySlot, 0)).Error)
+
+	var count int64
+	require.NoError(t, db.Model(&EncryptionMetadata{}).Where("file_id = ?", file.ID).Count(&count).Error)
+	assert.Equal(t, int64(4), count)
+
+	// 같은 (파일, 용도, 슬롯) 조합은 거부됨
+	err := db.Create(newMetadata(MetadataPurposeVersion, 1)).Error
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "UNIQUE constraint failed")
+
+	assert.ErrorIs(t, db.Create(newMetadata(MetadataPurposePrimary, 1)).Error, ErrInvalidMetadataSlot)
+	assert.ErrorIs(t, db.Create(newMetadata(MetadataPurposeVersion, MaxMetadataPerFile)).Error, ErrInvalidMetadataSlot)
+	assert.ErrorIs(t, db.Create(newMetadata(MetadataPurposeVersion, -1)).Error, ErrInvalidMetadataSlot)
+	assert.ErrorIs(t, db.Create(newMetadata("backup", 0)).Error, ErrInvalidMetadataPurpose)
+}
+
+func TestEncryptionMetadata_PerFileLimit(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	file := createTestFile()
+	require.NoError(t, db.Create(file).Error)
+	require.NoError(t, db.Create(createTestEncryptionMetadata(file.ID)).Error)
+
+	// primary 1개 + version 15개 = 한도
+	for slot := 0; slot < MaxMetadataPerFile-1; slot++ {
+		metadata := createTestEncryptionMetadata(file.ID)
+		metadata.Purpose = MetadataPurposeVersion
+		metadata.Slot = slot
+		require.NoError(t, db.Create(metadata).Error, "slot %d", slot)
+	}
+
+	metadata := createTestEncryptionMetadata(file.ID)
+	metadata.Purpose = MetadataPurposeKeySlot
+	assert.ErrorIs(t, db.Create(metadata).Error, ErrTooManyMetadata)
+
+	// 파일을 영구 삭제하면 모든 용도의 메타데이터가 CASCADE로 삭제됨
+	require.NoError(t, db.Unscoped().Delete(file).Error)
+
+	var count int64
+	require.NoError(t, db.Model(&EncryptionMetadata{}).Where("file_id = ?", file.ID).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestMigrate_LegacyMetadataIndex(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 파일당 메타데이터 1개 시절의 스키마 재현
+	require.NoError(t, db.Exec("CREATE UNIQUE INDEX "+legacyMetadataFileIndex+" ON encryption_metadata(file_id)").Error)
+
+	file := createTestFile()
+	require.NoError(t, db.Create(file).Error)
+	require.NoError(t, db.Create(createTestEncryptionMetadata(file.ID)).Error)
+	require.NoError(t, db.Exec("UPDATE encryption_metadata SET purpose = ''").Error)
+
+	migrateLegacyDB(t, db)
+	assert.False(t, db.Migrator().HasIndex(&EncryptionMetadata{}, legacyMetadataFileIndex))
+
+	var stored EncryptionMetadata
+	require.NoError(t, db.Where("file_id = ?", file.ID).First(&stored).Error)
+	assert.Equal(t, MetadataPurposePrimary, stored.Purpose)
+	assert.Equal(t, 0, stored.Slot)
+
+	// 인덱스가 제거되었으므로 같은 파일에 다른 용도의 메타데이터를 추가할 수 있음
+	version := createTestEncryptionMetadata(file.ID)
+	version.Purpose = MetadataPurposeVersion
+	require.NoError(t, db.Create(version).Error)
+}
+
+func TestMigrate_BackfillsShortCodes(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 짧은 코드 도입 전에 만들어진 파일 (삭제된 파일 포함)
+	live, deleted := createTestFile(), createTestFile()
+	deleted.EncryptedPath = "/encrypted/deleted.enc"
+	require.NoError(t, db.Create(live).Error)
+	require.NoError(t, db.Create(deleted).Error)
+	require.NoError(t, db.Delete(deleted).Error)
+	require.NoError(t, db.Exec("UPDATE files SET short_code = NULL").Error)
+
+	migrateLegacyDB(t, db)
+
+	var codes []string
+	require.NoError(t, db.Unscoped().Model(&File{}).Order("id").Pluck("short_code", &codes).Error)
+	require.Len(t, codes, 2)
+	assert.True(t, IsValidShortCode(codes[0]), codes[0])
+	assert.True(t, IsValidShortCode(codes[1]), codes[1])
+	assert.NotEqual(t, codes[0], codes[1])
+
+	// 이미 부여한 코드는 다시 실행해도 바뀌지 않음
+	migrateLegacyDB(t, db)
+	var again []string
+	require.NoError(t, db.Unscoped().Model(&File{}).Order("id").Pluck("short_code", &again).Error)
+	assert.Equal(t, codes, again)
+}
+
+func TestNormalizeShortCode(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+		err   bool
+	}{
+		{"7K3M9QXZ", "7K3M9QXZ", false},
+		{"7k3m-9qxz", "7K3M9QXZ", false},
+		{" 7K3M 9QXZ ", "7K3M9QXZ", false},
+		{"ILO0abcd", "1100ABCD", false}, // I/L → 1, O → 0
+		{"7K3M9QX", "", true},           // 짧음
+		{"7K3M9QXZZ", "", true},         // 김
+		{"7K3M9QXU", "", true},          // U는 쓰지 않음
+		{"7K3M9QX!", "", true},
+		{"", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := NormalizeShortCode(tc.input)
+			if tc.err {
+				assert.ErrorIs(t, err, ErrInvalidShortCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	file := createTestFile()
+	file.ShortCode = "abcdefgh"
+	assert.ErrorIs(t, file.validate(), ErrInvalidShortCode, "저장 형식은 대문자만")
+}
+
+// TestNewShortCode_Collisions 생성한 코드의 형식, 문자 분포와 충돌 수가 생일 문제 기댓값에 맞는지 확인합니다
+func TestNewShortCode_Collisions(t *testing.T) {
+	const n = 200000
+
+	seen := make(map[string]bool, n)
+	counts := make(map[rune]int, len(ShortCodeAlphabet))
+	collisions := 0
+	for range n {
+		code, err := NewShortCode()
+		require.NoError(t, err)
+		require.True(t, IsValidShortCode(code), code)
+
+		if seen[code] {
+			collisions++
+		}
+		seen[code] = true
+		for _, r := range code {
+			counts[r]++
+		}
+	}
+
+	// 기대 충돌 수 ≈ n²/(2·32⁸) ≈ 0.018, 포아송 분포에서 3번 이상일 확률은 1e-6 미만
+	space := math.Pow(float64(len(ShortCodeAlphabet)), ShortCodeLength)
+	expected := float64(n) * float64(n-1) / (2 * space)
+	assert.Less(t, expected, 0.02)
+	assert.LessOrEqual(t, collisions, 2)
+
+	// 100만 개를 만들 때 한 번이라도 겹칠 확률은 재시도가 필요한 수준 (약 36%)
+	million := 1e6
+	assert.InDelta(t, 0.36, 1-math.Exp(-million*(million-1)/(2*space)), 0.01)
+
+	// 모든 문자가 고르게 나옴 (문자당 기대값 50,000, 표준편차 약 220)
+	require.Len(t, counts, len(ShortCodeAlphabet))
+	perChar := float64(n*ShortCodeLength) / float64(len(ShortCodeAlphabet))
+	for r, count := range counts {
+		assert.InDelta(t, perChar, float64(count), perChar*0.05, "문자 %c", r)
+	}
+}
+
 func TestGetTableInfo(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -539,6 +977,13 @@ func TestResetDatabase(t *testing.T) {
 	err := db.Create(file).Error
 	require.NoError(t, err)
 
+	require.NoError(t, db.Create(&IdempotencyRecord{
+		Key:         "retry-1",
+		Owner:       ActorAnonymous,
+		RequestHash: strings.Repeat("a", 64),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}).Error)
+
 	// 데이터베이스 리셋
 	err = ResetDatabase(db)
 	require.NoError(t, err)
@@ -551,17 +996,95 @@ func TestResetDatabase(t *testing.T) {
 	err = db.Model(&File{}).Count(&count).Error
 	require.NoError(t, err)
 	assert.Equal(t, int64(0), count)
+
+	// 파일 외 기록도 함께 비움
+	require.NoError(t, db.Model(&IdempotencyRecord{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
 }
 
 // 벤치마크 테스트
+// enumConstantPrefixes enum 레지스트리에 등록해야 하는 상수 이름 접두사
+var enumConstantPrefixes = map[string]string{
+	"FileStatus":          EnumFileStatus,
+	"EncryptionAlgorithm": EnumEncryptionAlgorithm,
+	"KeyDerivation":       EnumKeyDerivation,
+}
+
+func TestEnumRegistry_CoversConstants(t *testing.T) {
+	// 패키지 소스의 문자열 상수를 읽어 접두사별로 모음 (새 상수를 레지스트리에 빠뜨리면 실패)
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	require.NoError(t, err)
+
+	declared := make(map[string][]string)
+	for _, file := range pkgs["model"].Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for i, name := range valueSpec.Names {
+					enum := enumForConstant(name.Name)
+					if enum == "" || i >= len(valueSpec.Values) {
+						continue
+					}
+					lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+					require.True(t, ok && lit.Kind == token.STRING, "%s는 문자열 리터럴이어야 합니다", name.Name)
+					value, err := strconv.Unquote(lit.Value)
+					require.NoError(t, err)
+					declared[enum] = append(declared[enum], value)
+				}
+			}
+		}
+	}
+
+	enums := Enums()
+	require.Len(t, enums, len(enumConstantPrefixes))
+	for _, enum := range enumConstantPrefixes {
+		require.NotEmpty(t, declared[enum], enum)
+		assert.ElementsMatch(t, declared[enum], EnumValues(enum), "%s 상수와 레지스트리가 다릅니다", enum)
+
+		for _, value := range enums[enum] {
+			assert.NotEmpty(t, value.Labels.Ko, "%s/%s 한국어 라벨", enum, value.Value)
+			assert.NotEmpty(t, value.Labels.En, "%s/%s 영어 라벨", enum, value.Value)
+		}
+	}
+}
+
+// enumForConstant 상수 이름이 속한 enum을 반환합니다 (해당 없으면 빈 문자열)
+func enumForConstant(name string) string {
+	for prefix, enum := range enumConstantPrefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return enum
+		}
+	}
+	return ""
+}
+
+func TestEnums(t *testing.T) {
+	assert.Equal(t, []string{FileStatusPending, FileStatusEncrypted, FileStatusFailed, FileStatusCorrupted}, FileStatuses())
+	assert.True(t, IsValidAlgorithm(EncryptionAlgorithmAES256GCM))
+	assert.False(t, IsValidKeyDerivation("argon2id"))
+	assert.Nil(t, EnumValues("unknown"))
+
+	// 반환값을 바꿔도 레지스트리는 그대로
+	enums := Enums()
+	enums[EnumFileStatus][0].Value = "changed"
+	assert.True(t, IsValidFileStatus(FileStatusPending))
+}
+
 func BenchmarkFile_Create(b *testing.B) {
 	// 메모리 데이터베이스 사용
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
 	})
 	require.NoError(b, err)
 
-	err = Migrate(db)
+	_, err = MigrateUp(db)
 	require.NoError(b, err)
 
 	b.ResetTimer()
@@ -580,11 +1103,12 @@ func BenchmarkFile_Create(b *testing.B) {
 func BenchmarkEncryptionMetadata_Create(b *testing.B) {
 	// 메모리 데이터베이스 사용
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
 	})
 	require.NoError(b, err)
 
-	err = Migrate(db)
+	_, err = MigrateUp(db)
 	require.NoError(b, err)
 
 	// 벤치마크용 파일들 미리 생성
@@ -607,3 +1131,465 @@ func BenchmarkEncryptionMetadata_Create(b *testing.B) {
 		}
 	}
 }
+
+func TestSplitFolderPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		wantErr error
+	}{
+		{"빈 경로", "", []string{}, nil},
+		{"한 단계", "docs", []string{"docs"}, nil},
+		{"구분자 혼용과 빈 구간", `docs\2024//tax/./`, []string{"docs", "2024", "tax"}, nil},
+		{"상위 경로", "docs/../etc", nil, ErrInvalidFolderName},
+		{"너무 긴 이름", strings.Repeat("a", MaxFolderNameLength+1), nil, ErrFolderNameTooLong},
+		{"최대 깊이", strings.Repeat("d/", MaxFolderDepth), slices.Repeat([]string{"d"}, MaxFolderDepth), nil},
+		{"너무 깊음", strings.Repeat("d/", MaxFolderDepth+1), nil, ErrFolderTooDeep},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SplitFolderPath(tc.path)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFolder_Hierarchy(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 같은 상위 폴더 안에서만 이름이 유일 (최상위 포함)
+	root := &Folder{Name: "docs"}
+	require.NoError(t, db.Create(root).Error)
+	assert.Error(t, db.Create(&Folder{Name: "docs"}).Error)
+	child := &Folder{Name: "docs", ParentID: &root.ID}
+	require.NoError(t, db.Create(child).Error)
+	assert.ErrorIs(t, db.Create(&Folder{Name: "a/b", ParentID: &root.ID}).Error, ErrInvalidFolderName)
+
+	missing := uint(999)
+	assert.ErrorIs(t, db.Create(&Folder{Name: "orphan", ParentID: &missing}).Error, ErrFolderParentNotFound)
+
+	// 최대 깊이까지 만들 수 있고 그보다 깊게는 만들 수 없음
+	parent := child
+	for depth := 3; depth <= MaxFolderDepth; depth++ {
+		next := &Folder{Name: fmt.Sprintf("level-%d", depth), ParentID: &parent.ID}
+		require.NoError(t, db.Create(next).Error, "깊이 %d", depth)
+		parent = next
+	}
+	assert.ErrorIs(t, db.Create(&Folder{Name: "too-deep", ParentID: &parent.ID}).Error, ErrFolderTooDeep)
+
+	// 자기 자신이나 하위 폴더 아래로 옮기면 순환
+	root.ParentID = &root.ID
+	assert.ErrorIs(t, db.Save(root).Error, ErrFolderCycle)
+	root.ParentID = &parent.ID
+	assert.ErrorIs(t, db.Save(root).Error, ErrFolderCycle)
+
+	// 옮긴 뒤 하위 폴더가 최대 깊이를 넘으면 거부
+	other := &Folder{Name: "other", ParentID: &root.ID}
+	require.NoError(t, db.Create(other).Error)
+	child.ParentID = &other.ID
+	assert.ErrorIs(t, db.Save(child).Error, ErrFolderTooDeep)
+	parent.ParentID = &other.ID
+	require.NoError(t, db.Save(parent).Error, "하위 폴더가 없으면 깊이 안에서 옮길 수 있음")
+
+	// 폴더를 지우면 하위 폴더는 삭제되고 파일은 폴더 지정만 해제
+	var level5 Folder
+	require.NoError(t, db.Where("name = ?", "level-5").First(&level5).Error)
+	file := createTestFile()
+	file.FolderID = &level5.ID
+	require.NoError(t, db.Create(file).Error)
+	require.NoError(t, db.Delete(&Folder{}, child.ID).Error)
+
+	var folders int64
+	require.NoError(t, db.Model(&Folder{}).Count(&folders).Error)
+	assert.Equal(t, int64(3), folders, "docs, other와 옮긴 폴더만 남음")
+
+	var loaded File
+	require.NoError(t, db.First(&loaded, file.ID).Error)
+	assert.Nil(t, loaded.FolderID)
+
+	zero := uint(0)
+	invalid := createTestFile()
+	invalid.EncryptedPath = "/encrypted/invalid.enc"
+	invalid.FolderID = &zero
+	assert.ErrorIs(t, db.Create(invalid).Error, ErrInvalidFolderID)
+}
+
+func TestNormalizeFileName(t *testing.T) {
+	nfc := "한글 보고서.pdf"
+	nfd := norm.NFD.String(nfc)
+	require.NotEqual(t, nfc, nfd, "NFD는 자모가 분리된 다른 바이트열")
+
+	assert.Equal(t, nfc, NormalizeFileName(nfd))
+	assert.Equal(t, nfc, NormalizeFileName(nfc))
+	assert.Equal(t, "report.pdf", NormalizeFileName("report.pdf"))
+
+	tag, err := NormalizeTagName(norm.NFD.String("세금"))
+	require.NoError(t, err)
+	assert.Equal(t, "세금", tag)
+}
+
+func TestFile_NormalizesOriginalName(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// macOS에서 올린 NFD 파일명은 NFC로 저장
+	file := createTestFile()
+	file.OriginalName = norm.NFD.String("회의록.txt")
+	require.NoError(t, db.Create(file).Error)
+	assert.Equal(t, "회의록.txt", file.OriginalName)
+
+	file.OriginalName = norm.NFD.String("회의록-수정.txt")
+	require.NoError(t, db.Save(file).Error)
+
+	var loaded File
+	require.NoError(t, db.First(&loaded, file.ID).Error)
+	assert.Equal(t, "회의록-수정.txt", loaded.OriginalName)
+
+	// 폴더 이름도 NFC로 저장하므로 NFD와 NFC는 같은 이름
+	require.NoError(t, db.Create(&Folder{Name: norm.NFD.String("사진")}).Error)
+	assert.Error(t, db.Create(&Folder{Name: "사진"}).Error)
+	names, err := SplitFolderPath(norm.NFD.String("사진/2024"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"사진", "2024"}, names)
+}
+
+func TestFile_DescriptionAndCustomMetadata(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	manyKeys := make([]string, MaxCustomMetadataKeys+1)
+	for i := range manyKeys {
+		manyKeys[i] = fmt.Sprintf(`"k%d": %d`, i, i)
+	}
+
+	testCases := []struct {
+		name     string
+		metadata string
+		errType  error
+	}{
+		{"중첩 객체", `{"case": "2024-001", "client": {"name": "홍길동", "tier": 2}}`, nil},
+		{"잘못된 JSON", `{"case": `, ErrInvalidCustomMetadata},
+		{"객체가 아닌 값", `["case", "2024-001"]`, ErrInvalidCustomMetadata},
+		{"문자열", `"2024-001"`, ErrInvalidCustomMetadata},
+		{"4KB 초과", `{"note": "` + strings.Repeat("x", MaxCustomMetadataSize) + `"}`, ErrCustomMetadataTooLarge},
+		{"키 32개 초과", "{" + strings.Join(manyKeys, ",") + "}", ErrTooManyMetadataKeys},
+		{"키 32개", "{" + strings.Join(manyKeys[:MaxCustomMetadataKeys], ",") + "}", nil},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := createTestFile()
+			file.EncryptedPath = fmt.Sprintf("/encrypted/metadata_%d.enc", i)
+			file.CustomMetadata = datatypes.JSON(tc.metadata)
+
+			err := db.Create(file).Error
+			if tc.errType != nil {
+				assert.ErrorIs(t, err, tc.errType)
+				return
+			}
+			require.NoError(t, err)
+
+			var loaded File
+			require.NoError(t, db.First(&loaded, file.ID).Error)
+			assert.JSONEq(t, tc.metadata, string(loaded.CustomMetadata))
+		})
+	}
+
+	// 설명은 바이트가 아니라 문자 수로 제한
+	file := createTestFile()
+	file.Description = strings.Repeat("메", MaxDescriptionLength)
+	file.CustomMetadata = datatypes.JSON("null")
+	require.NoError(t, db.Create(file).Error)
+	assert.Nil(t, file.CustomMetadata, "null은 메타데이터 없음")
+
+	file.Description += "모"
+	assert.ErrorIs(t, db.Save(file).Error, ErrDescriptionTooLong)
+}
+
+func TestCustomMetadataPath(t *testing.T) {
+	path, err := CustomMetadataPath("client.name")
+	require.NoError(t, err)
+	assert.Equal(t, `$."client"."name"`, path)
+
+	path, err = CustomMetadataPath("$[0]")
+	require.NoError(t, err)
+	assert.Equal(t, `$."$[0]"`, path, "경로 문법은 키 이름으로 취급")
+
+	for _, key := range []string{"", "client.", ".name", `cli"ent`, `a\b`, strings.Repeat("a.", MaxCustomMetadataKeyDepth) + "a"} {
+		_, err := CustomMetadataPath(key)
+		assert.ErrorIs(t, err, ErrInvalidMetadataKey, key)
+	}
+}
+
+func TestMigrate_BackfillsEncryptedSizes(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	onDisk := filepath.Join(dir, "on-disk.dlk")
+	require.NoError(t, os.WriteFile(onDisk, make([]byte, 148), 0o600))
+
+	// 크기 기록 도입 전 파일 (암호화본이 있는 파일, 유실된 파일, 이미 기록된 파일, 삭제된 파일)
+	legacy, missing, recorded, deleted := createTestFile(), createTestFile(), createTestFile(), createTestFile()
+	legacy.EncryptedPath = onDisk
+	missing.EncryptedPath = filepath.Join(dir, "missing.dlk")
+	recorded.EncryptedPath = filepath.Join(dir, "recorded.dlk")
+	recorded.EncryptedSize = 99
+	deletedPath := filepath.Join(dir, "deleted.dlk")
+	require.NoError(t, os.WriteFile(deletedPath, make([]byte, 64), 0o600))
+	deleted.EncryptedPath = deletedPath
+	for _, file := range []*File{legacy, missing, recorded, deleted} {
+		require.NoError(t, db.Create(file).Error)
+	}
+	require.NoError(t, db.Delete(deleted).Error)
+	require.NoError(t, db.Exec("UPDATE files SET version = 3").Error)
+
+	migrateLegacyDB(t, db)
+
+	var files []File
+	require.NoError(t, db.Unscoped().Order("id").Find(&files).Error)
+	require.Len(t, files, 4)
+	assert.Equal(t, int64(148), files[0].EncryptedSize)
+	assert.Zero(t, files[1].EncryptedSize, "암호화본을 찾지 못하면 0으로 남김")
+	assert.Equal(t, int64(99), files[2].EncryptedSize, "기록된 크기는 그대로")
+	assert.Equal(t, int64(64), files[3].EncryptedSize, "삭제된 파일도 디스크를 차지")
+	assert.Equal(t, uint(3), files[0].Version, "크기 채우기는 버전을 바꾸지 않음")
+}
+
+func TestMigrate_NormalizesFileNames(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 정규화 도입 전에 NFD로 저장된 파일 (삭제된 파일 포함)
+	nfd, nfc, deleted := createTestFile(), createTestFile(), createTestFile()
+	nfc.EncryptedPath = "/encrypted/nfc.enc"
+	deleted.EncryptedPath = "/encrypted/deleted.enc"
+	for _, file := range []*File{nfd, nfc, deleted} {
+		require.NoError(t, db.Create(file).Error)
+	}
+	require.NoError(t, db.Delete(deleted).Error)
+
+	names := map[uint]string{nfd.ID: norm.NFD.String("한글.txt"), nfc.ID: "한글.txt", deleted.ID: norm.NFD.String("삭제됨.txt")}
+	for id, name := range names {
+		require.NoError(t, db.Exec("UPDATE files SET original_name = ?, version = 3 WHERE id = ?", name, id).Error)
+	}
+
+	migrateLegacyDB(t, db)
+
+	var files []File
+	require.NoError(t, db.Unscoped().Order("id").Find(&files).Error)
+	require.Len(t, files, 3)
+	assert.Equal(t, "한글.txt", files[0].OriginalName)
+	assert.Equal(t, "한글.txt", files[1].OriginalName)
+	assert.Equal(t, "삭제됨.txt", files[2].OriginalName)
+	assert.Equal(t, uint(3), files[0].Version, "이름 정규화는 버전을 바꾸지 않음")
+
+	// 같은 이름으로 검색되고, 다시 실행해도 바뀌지 않음
+	var count int64
+	require.NoError(t, db.Model(&File{}).Where("original_name = ?", "한글.txt").Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+	migrateLegacyDB(t, db)
+}
+
+func TestMigrate_NormalizesTimestampsToUTC(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// UTC NowFunc 도입 전처럼 호스트 시간대 오프셋으로 저장된 파일과 메타데이터
+	kst := time.FixedZone("KST", 9*60*60)
+	createdAt := time.Date(2024, 3, 1, 9, 30, 15, 123456789, kst)
+	file := createTestFile()
+	require.NoError(t, db.Create(file).Error)
+	require.NoError(t, db.Create(createTestEncryptionMetadata(file.ID)).Error)
+	require.NoError(t, db.Exec("UPDATE files SET created_at = ?, updated_at = ?, last_accessed_at = ?, version = 3",
+		createdAt, createdAt, createdAt).Error)
+	require.NoError(t, db.Exec("UPDATE encryption_metadata SET created_at = ?", createdAt).Error)
+
+	migrateLegacyDB(t, db)
+
+	for _, column := range []string{"files.created_at", "files.updated_at", "files.last_accessed_at", "encryption_metadata.created_at"} {
+		table, name, _ := strings.Cut(column, ".")
+		var stored string
+		require.NoError(t, db.Raw("SELECT CAST("+name+" AS TEXT) FROM "+table).Scan(&stored).Error)
+		assert.Equal(t, "2024-03-01 00:30:15.123456789+00:00", stored, column)
+	}
+
+	var reloaded File
+	require.NoError(t, db.First(&reloaded, file.ID).Error)
+	assert.True(t, reloaded.CreatedAt.Equal(createdAt), "같은 시각을 표기만 바꿈")
+	assert.Equal(t, uint(3), reloaded.Version, "UTC 정규화는 버전을 바꾸지 않음")
+}
+
+func TestMigrate_AddsKeySlotIterations(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 0003 이전 스키마로 되돌린 뒤 당시 형식의 슬롯 저장
+	reverted, err := MigrateDown(db, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0003_key_slot_iterations"}, reverted)
+	require.False(t, db.Migrator().HasColumn(&KeySlot{}, "iterations"))
+	require.True(t, db.Migrator().HasIndex(&KeySlot{}, "idx_key_slots_file_slot"), "되돌려도 0001의 인덱스는 유지")
+
+	file := createTestFile()
+	require.NoError(t, db.Create(file).Error)
+	require.NoError(t, db.Exec("INSERT INTO key_slots (created_at, updated_at, file_id, slot_index, salt_hex, wrapped_key_hex) "+
+		"VALUES (?, ?, ?, 0, ?, ?)", time.Now().UTC(), time.Now().UTC(), file.ID,
+		strings.Repeat("01", ExpectedSaltSize), strings.Repeat("02", ExpectedWrappedKeySize)).Error)
+
+	applied, err := MigrateUp(db)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0003_key_slot_iterations"}, applied)
+
+	// 기존 슬롯은 기본 반복 횟수로 채워지고 범위 밖 값은 DB가 거부
+	var slot KeySlot
+	require.NoError(t, db.Where("file_id = ?", file.ID).First(&slot).Error)
+	assert.Equal(t, DefaultIterations, slot.Iterations)
+	assert.Error(t, db.Exec("UPDATE key_slots SET iterations = 10 WHERE id = ?", slot.ID).Error)
+	assert.True(t, db.Migrator().HasIndex(&KeySlot{}, "idx_key_slots_file_slot"), "컬럼 추가가 인덱스를 지우지 않음")
+}
+
+func TestMigrate_InitialSchemaIsFrozen(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 빈 데이터베이스에 0001만 적용하면 이후 마이그레이션의 컬럼은 없어야 함
+	require.NoError(t, DropAllTables(db))
+	applied, err := migrateUp(db, migrations[:1])
+	require.NoError(t, err)
+	require.Equal(t, []string{"0001_initial_schema"}, applied)
+	assert.False(t, db.Migrator().HasColumn(&KeySlot{}, "iterations"), "0001은 현재 모델을 따라가지 않음")
+
+	// 나머지를 적용하면 0003이 컬럼을 추가
+	applied, err = MigrateUp(db)
+	require.NoError(t, err)
+	assert.Equal(t, migrationIDs()[1:], applied)
+	assert.True(t, db.Migrator().HasColumn(&KeySlot{}, "iterations"))
+	assert.True(t, db.Migrator().HasIndex(&KeySlot{}, "idx_key_slots_file_slot"))
+}
+
+func TestMigrateUp_Idempotent(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	file := createTestFile()
+	require.NoError(t, db.Create(file).Error)
+
+	// 이미 모두 적용했으므로 다시 실행해도 아무것도 하지 않음
+	for range 2 {
+		applied, err := MigrateUp(db)
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+	}
+
+	states, err := MigrationStatus(db)
+	require.NoError(t, err)
+	require.Len(t, states, len(migrations))
+	for _, state := range states {
+		assert.True(t, state.Applied, state.ID)
+		assert.NotNil(t, state.AppliedAt)
+		assert.False(t, state.Unknown)
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&File{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count, "다시 실행해도 데이터는 그대로")
+}
+
+func TestMigrateDown_Rollback(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 기존 마이그레이션 위에 컬럼을 추가하는 마이그레이션
+	list := append(slices.Clone(migrations), Migration{
+		ID: "9999_add_files_note",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE files ADD COLUMN note TEXT").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE files DROP COLUMN note").Error
+		},
+	})
+
+	applied, err := migrateUp(db, list)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"9999_add_files_note"}, applied)
+	assert.True(t, db.Migrator().HasColumn(&File{}, "note"))
+
+	// 이 빌드에는 9999가 없으므로 기존 목록으로는 적용하지 않고 거부
+	_, err = MigrateUp(db)
+	require.ErrorIs(t, err, ErrUnknownMigration)
+	states, err := MigrationStatus(db)
+	require.NoError(t, err)
+	require.Len(t, states, len(migrations)+1)
+	assert.True(t, states[len(migrations)].Unknown)
+
+	reverted, err := migrateDown(db, list, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"9999_add_files_note"}, reverted)
+	assert.False(t, db.Migrator().HasColumn(&File{}, "note"))
+
+	states, err = migrationStatus(db, list)
+	require.NoError(t, err)
+	assert.True(t, states[len(migrations)-1].Applied)
+	assert.False(t, states[len(migrations)].Applied)
+
+	// 적용한 것보다 많이 되돌리면 모두 되돌림
+	reverted, err = MigrateDown(db, 10)
+	require.NoError(t, err)
+	expected := migrationIDs()
+	slices.Reverse(expected)
+	assert.Equal(t, expected, reverted)
+	assert.False(t, db.Migrator().HasTable(&File{}))
+	assert.True(t, db.Migrator().HasTable(&SchemaMigration{}), "기록 테이블은 남김")
+
+	_, err = MigrateDown(db, 0)
+	assert.ErrorIs(t, err, ErrInvalidMigrationSteps)
+
+	// 다시 올리면 처음부터 적용
+	applied, err = MigrateUp(db)
+	require.NoError(t, err)
+	assert.Equal(t, migrationIDs(), applied)
+	require.NoError(t, db.Create(createTestFile()).Error)
+}
+
+func TestMigrateUp_FailureRollsBack(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	list := append(slices.Clone(migrations), Migration{
+		ID: "9999_broken",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec("CREATE TABLE half_done (id INTEGER)").Error; err != nil {
+				return err
+			}
+			return tx.Exec("SELECT * FROM no_such_table").Error
+		},
+	})
+
+	_, err := migrateUp(db, list)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "9999_broken")
+	assert.False(t, db.Migrator().HasTable("half_done"), "실패한 마이그레이션의 변경은 되돌림")
+
+	states, err := migrationStatus(db, list)
+	require.NoError(t, err)
+	assert.False(t, states[len(migrations)].Applied, "실패하면 기록을 남기지 않음")
+
+	// Down이 없는 마이그레이션은 되돌리지 않음
+	list[len(migrations)].Up = func(*gorm.DB) error { return nil }
+	_, err = migrateUp(db, list)
+	require.NoError(t, err)
+	reverted, err := migrateDown(db, list, len(list))
+	require.ErrorIs(t, err, ErrIrreversibleMigration)
+	assert.Empty(t, reverted)
+	assert.True(t, db.Migrator().HasTable(&File{}))
+
+	// 순서가 어긋난 목록은 거부
+	_, err = migrateUp(db, []Migration{list[len(migrations)], list[0]})
+	assert.Error(t, err)
+}
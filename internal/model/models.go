@@ -5,8 +5,12 @@ package model
 import (
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -35,6 +39,24 @@ const (
 
 	// DefaultIterations 기본 PBKDF2 반복 횟수
 	DefaultIterations = 100000
+
+	// CompressionNone 압축하지 않음 (crypto.CompressionNone.String()과 일치)
+	CompressionNone = "none"
+
+	// CompressionGzip 청크 단위 gzip 압축 (crypto.CompressionGzip.String()과 일치)
+	CompressionGzip = "gzip"
+)
+
+// 암호화 메타데이터 용도 상수
+const (
+	// MetadataPurposePrimary 파일의 현재 암호화 설정 (파일당 하나, 슬롯 0)
+	MetadataPurposePrimary = "primary"
+
+	// MetadataPurposeVersion 이전 버전의 암호화 설정
+	MetadataPurposeVersion = "version"
+
+	// MetadataPurposeKeySlot 키 슬롯별 암호화 설정
+	MetadataPurposeKeySlot = "keyslot"
 )
 
 // 필드 길이 제한 상수
@@ -45,6 +67,24 @@ const (
 	// MaxEncryptedPathLength 암호화된 파일 경로 최대 길이
 	MaxEncryptedPathLength = 500
 
+	// MaxVolumeIDLength 저장소 볼륨 ID 최대 길이
+	MaxVolumeIDLength = 64
+
+	// MaxExternalIDLength 외부 시스템 참조 ID 최대 길이
+	MaxExternalIDLength = 128
+
+	// MaxUsernameLength 사용자 이름 최대 길이
+	MaxUsernameLength = 64
+
+	// MaxPasswordHashLength 로그인 패스워드 해시 최대 길이
+	MaxPasswordHashLength = 255
+
+	// MaxTagNameLength 태그 이름 최대 길이 (정규화 후 문자 수)
+	MaxTagNameLength = 50
+
+	// MaxIdempotencyKeyLength Idempotency-Key 최대 길이
+	MaxIdempotencyKeyLength = 255
+
 	// MaxMimeTypeLength MIME 타입 최대 길이
 	MaxMimeTypeLength = 100
 
@@ -71,6 +111,18 @@ const (
 
 	// MaxIterations 최대 반복 횟수
 	MaxIterations = 1000000
+
+	// MaxWrappedKeyHexLength 감싸진 키 hex 문자열 최대 길이 (60bytes * 2 = 120)
+	MaxWrappedKeyHexLength = 120
+
+	// MaxKeySlotsPerFile 파일당 최대 키 슬롯 수
+	MaxKeySlotsPerFile = 8
+
+	// MaxMetadataPerFile 파일당 최대 암호화 메타데이터 수 (모든 용도 합산)
+	MaxMetadataPerFile = 16
+
+	// PasswordFingerprintHexLength 패스워드 지문 hex 문자열 길이 (32bytes * 2 = 64)
+	PasswordFingerprintHexLength = 64
 )
 
 // 바이트 크기 상수 (암호화 모듈과 일치)
@@ -80,8 +132,14 @@ const (
 
 	// ExpectedNonceSize 예상 Nonce 크기 (12 바이트)
 	ExpectedNonceSize = 12
+
+	// ExpectedWrappedKeySize 예상 감싸진 키 크기 (nonce 12 + 키 32 + 태그 16 = 60 바이트)
+	ExpectedWrappedKeySize = 60
 )
 
+// MetricsDateLayout 일별 통계 스냅샷의 날짜 형식 (UTC 기준)
+const MetricsDateLayout = "2006-01-02"
+
 // 반복 횟수 상수
 const (
 	// IterationsToKDivisor 반복 횟수를 K 단위로 변환하는 제수
@@ -100,12 +158,85 @@ type File struct {
 	OriginalName  string `gorm:"type:varchar(255);not null;index:idx_files_original_name" json:"original_name"`
 	EncryptedPath string `gorm:"type:varchar(500);not null;unique" json:"encrypted_path"`
 	Size          int64  `gorm:"not null;check:size >= 0" json:"size"`
+	// 디스크에 저장된 암호화본 크기 (중복 제거 참조와 기록 도입 전 파일은 0, 크기 교정으로 채움)
+	EncryptedSize int64  `gorm:"not null;default:0;check:encrypted_size >= 0" json:"encrypted_size"`
 	MimeType      string `gorm:"type:varchar(100);not null" json:"mime_type"`
 	ChecksumMD5   string `gorm:"type:varchar(64);not null;index:idx_files_checksum" json:"checksum_md5"`
 	Status        string `gorm:"type:varchar(20);not null;default:'pending';index:idx_files_status" json:"status"`
+	FailureReason string `gorm:"type:varchar(255)" json:"failure_reason,omitempty"` // failed 상태의 사유
+	TextEncoding  string `gorm:"type:varchar(20)" json:"text_encoding,omitempty"`   // 텍스트 파일의 문자 인코딩 (업로드 시 감지)
+
+	// 사용자가 붙이는 설명과 키/값 메타데이터 (사건 번호, 고객명 등, 메타데이터는 JSON 객체)
+	Description    string         `gorm:"type:varchar(1000)" json:"description,omitempty"`
+	CustomMetadata datatypes.JSON `json:"custom_metadata,omitempty"`
+
+	// 감사 필드: 생성/수정 훅이 쿼리 컨텍스트의 행위자(WithActor)로 채움
+	CreatedBy string `gorm:"type:varchar(64);not null;default:'system'" json:"created_by"`
+	UpdatedBy string `gorm:"type:varchar(64);not null;default:'system'" json:"updated_by"`
+
+	// 암호화본이 저장된 저장소 볼륨 (비어 있으면 볼륨 도입 전 파일로 EncryptedPath를 그대로 사용)
+	VolumeID string `gorm:"type:varchar(64);index:idx_files_volume_id" json:"volume_id,omitempty"`
+
+	// 외부 시스템이 붙인 참조 ID (없으면 빈 값, 살아 있는 파일 사이에서만 유일)
+	ExternalID string `gorm:"type:varchar(128);uniqueIndex:idx_files_external_id,where:external_id <> '' AND deleted_at IS NULL" json:"external_id,omitempty"`
+
+	// 소유 사용자 (nil이면 다중 사용자 도입 전 파일로 소유자 없음)
+	OwnerID *uint `gorm:"index:idx_files_owner_id" json:"owner_id,omitempty"`
+
+	// 파일이 속한 폴더 (nil이면 폴더에 넣지 않은 파일, 폴더를 지우면 nil이 됨)
+	FolderID *uint `gorm:"index:idx_files_folder_id" json:"folder_id,omitempty"`
+
+	// 구두로 전달하기 쉬운 짧은 파일 코드 (저장소가 생성할 때 채움, 소프트 삭제된 파일을 포함해 유일)
+	ShortCode string `gorm:"type:varchar(8);uniqueIndex:idx_files_short_code,where:short_code <> ''" json:"short_code,omitempty"`
+
+	// 중복 제거 필드: BlobFileID가 있으면 해당 파일의 암호화 blob을 참조하는 레코드
+	BlobFileID  *uint  `gorm:"index:idx_files_blob_file_id" json:"blob_file_id,omitempty"`
+	BlockHashes string `gorm:"type:text" json:"-"` // 소유 증명용 블록별 SHA-256 (hex 연결)
 
-	// 관계: 1:1 (File has one EncryptionMetadata)
+	// 유사 중복 탐지용 64비트 SimHash (SQLite 정수 범위에 맞춰 비트 그대로 int64로 저장, 내용이 짧으면 nil)
+	SimHash *int64 `gorm:"column:sim_hash" json:"-"`
+
+	// 마지막으로 복호화된 시각 (nil이면 한 번도 복호화되지 않음, 접근 기록은 버전과 updated_at을 바꾸지 않음)
+	LastAccessedAt *time.Time `gorm:"index:idx_files_last_accessed_at" json:"last_accessed_at,omitempty"`
+
+	// 낙관적 잠금 버전: 갱신할 때마다 1씩 증가 (Update는 읽어 온 버전이 그대로일 때만 반영)
+	Version uint `gorm:"not null;default:1" json:"version"`
+
+	// 관계: 1:1 (File has one primary EncryptionMetadata, 조회 시 용도로 필터링)
 	EncryptionMetadata *EncryptionMetadata `gorm:"foreignKey:FileID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"encryption_metadata,omitempty"`
+
+	// 관계: 1:N (File has many KeySlot)
+	KeySlots []*KeySlot `gorm:"foreignKey:FileID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"key_slots,omitempty"`
+
+	// 관계: N:M (File has many Tag, file_tags 연결 테이블, 파일이나 태그를 지우면 연결도 삭제)
+	Tags []*Tag `gorm:"many2many:file_tags;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"tags,omitempty"`
+}
+
+// User 파일을 소유하는 사용자 모델
+type User struct {
+	// 기본 필드
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+
+	// 계정 필드 (패스워드는 해시만 저장하고 응답에 포함하지 않음)
+	Username     string `gorm:"type:varchar(64);not null;uniqueIndex:idx_users_username" json:"username"`
+	PasswordHash string `gorm:"type:varchar(255);not null" json:"-"`
+
+	// 관계: 1:N (User has many File, 사용자를 지우면 소유한 파일 레코드도 삭제)
+	Files []*File `gorm:"foreignKey:OwnerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"files,omitempty"`
+}
+
+// Tag 파일을 분류하는 라벨 모델 (예: "tax-2024", "personal")
+//
+// 이름은 NormalizeTagName으로 정규화해 저장하므로 대소문자와 앞뒤 공백만 다른
+// 이름은 같은 태그입니다.
+type Tag struct {
+	// 기본 필드
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+
+	// 태그 이름 (정규화 후 유일)
+	Name string `gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_name" json:"name"`
 }
 
 // EncryptionMetadata 암호화에 사용된 설정과 키 정보를 저장하는 모델
@@ -115,32 +246,229 @@ type EncryptionMetadata struct {
 	CreatedAt time.Time `gorm:"not null;index:idx_encryption_metadata_created_at" json:"created_at"`
 	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
 
-	// 외래키 필드
-	FileID uint `gorm:"not null;uniqueIndex:idx_encryption_metadata_file_id" json:"file_id"`
+	// 외래키 필드 (파일 내 용도, 슬롯과 함께 유일)
+	FileID  uint   `gorm:"not null;uniqueIndex:idx_encryption_metadata_file_purpose_slot" json:"file_id"`
+	Purpose string `gorm:"type:varchar(20);not null;default:'primary';uniqueIndex:idx_encryption_metadata_file_purpose_slot" json:"purpose"`
+	Slot    int    `gorm:"not null;default:0;uniqueIndex:idx_encryption_metadata_file_purpose_slot;check:slot >= 0" json:"slot"`
 
 	// 암호화 설정 필드
 	Algorithm     string `gorm:"type:varchar(50);not null;default:'AES-256-GCM';index:idx_encryption_metadata_algorithm" json:"algorithm"`
 	KeyDerivation string `gorm:"type:varchar(50);not null;default:'PBKDF2-SHA256'" json:"key_derivation"`
-	SaltHex       string `gorm:"type:varchar(64);not null" json:"salt_hex"`
-	NonceHex      string `gorm:"type:varchar(24);not null" json:"nonce_hex"`
-	Iterations    int    `gorm:"not null;default:100000;check:iterations >= 1000 AND iterations <= 1000000" json:"iterations"`
+	SaltHex       string `gorm:"type:varchar(64);not null" json:"-"` // 키 유도 재료는 응답에 포함하지 않음
+	NonceHex      string `gorm:"type:varchar(24);not null" json:"-"`
+	Iterations    int    `gorm:"not null;default:100000;index:idx_encryption_metadata_iterations;check:iterations >= 1000 AND iterations <= 1000000" json:"iterations"`
+
+	// 암호문 포맷 버전 (crypto.EncryptedData.Version, 기존 레코드는 0 = 유도 키 직접 사용)
+	FormatVersion int `gorm:"not null;default:0" json:"format_version"`
+
+	// 암호화 전 청크 압축 알고리즘 (none, gzip)
+	Compression string `gorm:"type:varchar(20);not null;default:'none'" json:"compression"`
+
+	// 패스워드 재사용 감지용 지문 (옵트인, 비활성 시 빈 값)
+	PasswordFingerprint string `gorm:"type:varchar(64);index:idx_encryption_metadata_fingerprint" json:"-"`
 
 	// 관계: N:1 (EncryptionMetadata belongs to File)
 	File *File `gorm:"foreignKey:FileID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
 }
 
+// KeySlot 패스워드별로 감싼 데이터 키(DEK)를 저장하는 모델
+//
+// 하나의 파일은 여러 패스워드(예: 소유자, 복구용)로 열 수 있으며,
+// 패스워드마다 고유한 salt로 데이터 키를 감싼 슬롯을 하나씩 가집니다.
+type KeySlot struct {
+	// 기본 필드
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+
+	// 외래키 필드 (파일 내 슬롯 번호와 함께 유일)
+	FileID    uint `gorm:"not null;uniqueIndex:idx_key_slots_file_slot" json:"file_id"`
+	SlotIndex int  `gorm:"not null;uniqueIndex:idx_key_slots_file_slot;check:slot_index >= 0" json:"slot_index"`
+
+	// 키 슬롯 필드 (감싼 키와 salt는 응답에 포함하지 않음)
+	SaltHex       string `gorm:"type:varchar(64);not null" json:"-"`
+	WrappedKeyHex string `gorm:"type:varchar(120);not null" json:"-"`
+
+	// 이 슬롯의 키 유도 반복 횟수 (슬롯마다 다를 수 있으므로 슬롯별로 기록)
+	Iterations int `gorm:"not null;default:100000;check:iterations >= 1000 AND iterations <= 1000000" json:"iterations"`
+
+	// 관계: N:1 (KeySlot belongs to File)
+	File *File `gorm:"foreignKey:FileID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// ValidationSession 디렉터리 검증 결과 세션
+//
+// 파일이 많은 디렉터리의 개별 결과를 응답 하나에 담지 않고 세션으로 보관했다가
+// 페이지 단위로 조회하게 합니다. ExpiresAt이 지난 세션은 결과와 함께 정리됩니다.
+type ValidationSession struct {
+	// 기본 필드
+	ID        string    `gorm:"primaryKey;type:varchar(32)" json:"id"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	ExpiresAt time.Time `gorm:"not null;index:idx_validation_sessions_expires_at" json:"expires_at"`
+
+	// 검증 요약
+	DirectoryPath string   `gorm:"type:text;not null" json:"directory_path"`
+	IsValid       bool     `gorm:"not null" json:"is_valid"`
+	TotalFiles    int      `gorm:"not null" json:"total_files"`
+	TotalSize     int64    `gorm:"not null" json:"total_size"`
+	ValidFiles    int      `gorm:"not null" json:"valid_files"`
+	InvalidFiles  int      `gorm:"not null" json:"invalid_files"`
+	Errors        []string `gorm:"type:text;serializer:json" json:"errors,omitempty"`
+
+	// 관계: 1:N (ValidationSession has many ValidationFileResult)
+	Results []*ValidationFileResult `gorm:"foreignKey:SessionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// ValidationFileResult 검증 세션에 보관하는 개별 파일 결과
+type ValidationFileResult struct {
+	// 기본 필드
+	ID uint `gorm:"primaryKey;autoIncrement" json:"-"`
+
+	// 외래키 필드 (세션 내 요청 순서와 함께 유일)
+	SessionID string `gorm:"type:varchar(32);not null;uniqueIndex:idx_validation_file_results_session_seq" json:"-"`
+	Seq       int    `gorm:"not null;uniqueIndex:idx_validation_file_results_session_seq" json:"-"`
+
+	// 검증 결과 필드
+	FileName     string   `gorm:"type:text;not null" json:"file_name"`
+	RelativePath string   `gorm:"type:text" json:"relative_path"`
+	IsValid      bool     `gorm:"not null" json:"is_valid"`
+	MIMEGroup    string   `gorm:"type:varchar(100)" json:"mime_group,omitempty"`
+	MaxSize      int64    `gorm:"not null" json:"max_size"`
+	Errors       []string `gorm:"type:text;serializer:json" json:"errors,omitempty"`
+}
+
+// MetricsSnapshot 관리자 대시보드 추이 그래프용 일별 통계 (UTC 하루 단위, 날짜당 하나)
+//
+// 파일 수와 총 용량은 그날이 끝난 시점 기준이고, 업로드/실패 수는 그날 생성된
+// 파일 기준입니다. 소프트 삭제된 파일은 삭제된 날부터 제외합니다.
+type MetricsSnapshot struct {
+	// 기본 필드
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"-"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+
+	// 집계 날짜 (MetricsDateLayout, 중복 실행해도 하나만 저장)
+	Date string `gorm:"type:char(10);not null;uniqueIndex:idx_metrics_snapshots_date" json:"date"`
+
+	// 집계 값
+	TotalFiles int64 `gorm:"not null" json:"total_files"`
+	TotalBytes int64 `gorm:"not null" json:"total_bytes"` // 원본 크기 합계 (중복 제거 참조 제외)
+	Uploads    int64 `gorm:"not null" json:"uploads"`
+	Failures   int64 `gorm:"not null" json:"failures"` // 그날 업로드되어 failed 상태인 파일 수
+}
+
+// IdempotencyRecord Idempotency-Key로 처리한 요청과 그 응답
+//
+// 키는 행위자(Owner)별로 유일합니다. StatusCode가 0이면 아직 처리 중인 요청이고,
+// 처리가 끝나면 응답을 저장해 같은 키의 재요청에 그대로 돌려줍니다. ExpiresAt이
+// 지난 기록은 정리됩니다.
+type IdempotencyRecord struct {
+	// 기본 필드
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"-"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	ExpiresAt time.Time `gorm:"not null;index:idx_idempotency_records_expires_at" json:"expires_at"`
+
+	// 키 필드 (행위자와 함께 유일)
+	Key   string `gorm:"column:idempotency_key;type:varchar(255);not null;uniqueIndex:idx_idempotency_records_key_owner" json:"key"`
+	Owner string `gorm:"type:varchar(64);not null;uniqueIndex:idx_idempotency_records_key_owner" json:"owner"`
+
+	// RequestHash 메서드, 경로, 본문의 SHA-256 (같은 키로 다른 요청이 오면 거부)
+	RequestHash string `gorm:"type:char(64);not null" json:"-"`
+
+	// 저장된 응답 (처리 중이면 StatusCode가 0)
+	StatusCode  int    `gorm:"not null;default:0" json:"status_code"`
+	ContentType string `gorm:"type:varchar(100)" json:"-"`
+	Body        []byte `gorm:"type:blob" json:"-"`
+}
+
+// MaxLockOwnerLength 파일 락 홀더 이름의 최대 길이
+const MaxLockOwnerLength = 64
+
+// FileLock 인스턴스 간에 공유하는 파일 단위 락
+//
+// 파일당 최대 한 행이며, 홀더가 ExpiresAt 전에 계속 연장합니다. 홀더가 비정상
+// 종료해 ExpiresAt이 지나면 다른 인스턴스가 행을 회수합니다. 영구 삭제된 파일의
+// 락도 남아 있어야 하므로 files에 대한 외래키가 없습니다.
+type FileLock struct {
+	FileID    uint      `gorm:"primaryKey;autoIncrement:false" json:"file_id"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+
+	Owner string `gorm:"type:varchar(64);not null" json:"owner"` // 락을 가진 인스턴스 (호스트:PID)
+	Token string `gorm:"type:char(32);not null" json:"-"`        // 획득 한 번을 구분하는 토큰
+}
+
 // TableName GORM 테이블명을 명시적으로 지정
 func (File) TableName() string {
 	return "files"
 }
 
+// TableName GORM 테이블명을 명시적으로 지정
+func (User) TableName() string {
+	return "users"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (FileLock) TableName() string {
+	return "file_locks"
+}
+
 // TableName GORM 테이블명을 명시적으로 지정
 func (EncryptionMetadata) TableName() string {
 	return "encryption_metadata"
 }
 
+// TableName GORM 테이블명을 명시적으로 지정
+func (KeySlot) TableName() string {
+	return "key_slots"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (ValidationSession) TableName() string {
+	return "validation_sessions"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (ValidationFileResult) TableName() string {
+	return "validation_file_results"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_records"
+}
+
+// IsCompleted 처리가 끝나 응답이 저장된 기록인지 확인합니다
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (MetricsSnapshot) TableName() string {
+	return "metrics_snapshots"
+}
+
 // BeforeCreate 생성 전 검증 로직
+//
+// 생성자를 직접 지정하지 않았으면 쿼리 컨텍스트의 행위자로 채우고, 원본 파일명은
+// NFC로 정규화해 저장합니다.
 func (f *File) BeforeCreate(tx *gorm.DB) error {
+	f.OriginalName = NormalizeFileName(f.OriginalName)
+	f.CustomMetadata = NormalizeCustomMetadata(f.CustomMetadata)
+
+	if f.CreatedBy == "" {
+		f.CreatedBy = actorFromTx(tx)
+	}
+	f.UpdatedBy = f.CreatedBy
+
+	if f.Version == 0 {
+		f.Version = 1
+	}
+
 	if err := f.validate(); err != nil {
 		return err
 	}
@@ -153,8 +481,11 @@ func (f *File) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-// BeforeUpdate 수정 전 검증 로직
+// BeforeUpdate 수정 전 검증 로직 (수정자는 쿼리 컨텍스트의 행위자, 원본 파일명은 NFC로 정규화)
 func (f *File) BeforeUpdate(tx *gorm.DB) error {
+	f.OriginalName = NormalizeFileName(f.OriginalName)
+	f.CustomMetadata = NormalizeCustomMetadata(f.CustomMetadata)
+	f.UpdatedBy = actorFromTx(tx)
 	return f.validate()
 }
 
@@ -200,6 +531,34 @@ func (f *File) validate() error {
 		return ErrInvalidFileStatus
 	}
 
+	if len(f.CreatedBy) > MaxActorLength || len(f.UpdatedBy) > MaxActorLength {
+		return ErrActorTooLong
+	}
+
+	if len(f.ExternalID) > MaxExternalIDLength {
+		return ErrExternalIDTooLong
+	}
+
+	if f.ShortCode != "" && !IsValidShortCode(f.ShortCode) {
+		return ErrInvalidShortCode
+	}
+
+	if err := ValidateDescription(f.Description); err != nil {
+		return err
+	}
+
+	if err := ValidateCustomMetadata(f.CustomMetadata); err != nil {
+		return err
+	}
+
+	if f.OwnerID != nil && *f.OwnerID == 0 {
+		return ErrInvalidOwnerID
+	}
+
+	if f.FolderID != nil && *f.FolderID == 0 {
+		return ErrInvalidFolderID
+	}
+
 	return nil
 }
 
@@ -222,6 +581,26 @@ func (em *EncryptionMetadata) BeforeCreate(tx *gorm.DB) error {
 		em.Iterations = DefaultIterations
 	}
 
+	if em.Compression == "" {
+		em.Compression = CompressionNone
+	}
+
+	if em.Purpose == "" {
+		em.Purpose = MetadataPurposePrimary
+	}
+
+	// 파일당 메타데이터 수 제한 (같은 용도와 슬롯의 행은 upsert로 교체되므로 제외)
+	var count int64
+	err := tx.Session(&gorm.Session{NewDB: true}).Model(&EncryptionMetadata{}).
+		Where("file_id = ? AND NOT (purpose = ? AND slot = ?)", em.FileID, em.Purpose, em.Slot).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("메타데이터 수 확인 실패: %w", err)
+	}
+	if count >= MaxMetadataPerFile {
+		return ErrTooManyMetadata
+	}
+
 	return nil
 }
 
@@ -263,7 +642,22 @@ func (em *EncryptionMetadata) validate() error {
 	}
 
 	// Salt와 Nonce 바이트 크기 검증
-	return em.validateCryptoSizes()
+	if err := em.validateCryptoSizes(); err != nil {
+		return err
+	}
+
+	// 압축 알고리즘 검증
+	if err := em.validateCompression(); err != nil {
+		return err
+	}
+
+	// 용도와 슬롯 검증
+	if err := em.validatePurpose(); err != nil {
+		return err
+	}
+
+	// 패스워드 지문 검증
+	return em.validatePasswordFingerprint()
 }
 
 // validateBasicFields 기본 필드 검증
@@ -376,34 +770,240 @@ func (em *EncryptionMetadata) validateCryptoSizes() error {
 	return nil
 }
 
-// IsValidFileStatus 유효한 파일 상태인지 확인
-func IsValidFileStatus(status string) bool {
-	validStatuses := map[string]bool{
-		FileStatusPending:   true,
-		FileStatusEncrypted: true,
-		FileStatusFailed:    true,
-		FileStatusCorrupted: true,
+// validatePasswordFingerprint 패스워드 지문 검증 (비어 있으면 기능 비활성)
+func (em *EncryptionMetadata) validatePasswordFingerprint() error {
+	if em.PasswordFingerprint == "" {
+		return nil
+	}
+
+	if len(em.PasswordFingerprint) != PasswordFingerprintHexLength || !IsValidHex(em.PasswordFingerprint) {
+		return ErrInvalidPasswordFingerprint
+	}
+
+	return nil
+}
+
+// validateCompression 압축 알고리즘 검증 (빈 값은 생성 시 none으로 채움)
+func (em *EncryptionMetadata) validateCompression() error {
+	if em.Compression == "" || IsValidCompression(em.Compression) {
+		return nil
+	}
+	return ErrInvalidCompression
+}
+
+// validatePurpose 용도와 슬롯 검증 (빈 용도는 생성 시 primary로 채움)
+func (em *EncryptionMetadata) validatePurpose() error {
+	if em.Purpose != "" && !IsValidMetadataPurpose(em.Purpose) {
+		return ErrInvalidMetadataPurpose
+	}
+
+	if em.Slot < 0 || em.Slot >= MaxMetadataPerFile {
+		return ErrInvalidMetadataSlot
+	}
+
+	// primary는 파일당 하나뿐이므로 슬롯 0만 허용
+	if (em.Purpose == "" || em.Purpose == MetadataPurposePrimary) && em.Slot != 0 {
+		return ErrInvalidMetadataSlot
+	}
+
+	return nil
+}
+
+// IsPrimary 파일의 현재 암호화 설정인지 확인
+func (em *EncryptionMetadata) IsPrimary() bool {
+	return em.Purpose == "" || em.Purpose == MetadataPurposePrimary
+}
+
+// BeforeCreate 생성 전 검증 로직
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+	return u.validate()
+}
+
+// BeforeUpdate 수정 전 검증 로직
+func (u *User) BeforeUpdate(tx *gorm.DB) error {
+	return u.validate()
+}
+
+// validate 사용자 검증 (이름은 영문, 숫자, '.', '_', '-'만 허용)
+func (u *User) validate() error {
+	if u.Username == "" {
+		return ErrEmptyUsername
+	}
+
+	if len(u.Username) > MaxUsernameLength {
+		return ErrUsernameTooLong
+	}
+
+	for _, r := range u.Username {
+		if !isUsernameRune(r) {
+			return ErrInvalidUsername
+		}
+	}
+
+	if u.PasswordHash == "" {
+		return ErrEmptyPasswordHash
+	}
+
+	if len(u.PasswordHash) > MaxPasswordHashLength {
+		return ErrPasswordHashTooLong
+	}
+
+	return nil
+}
+
+// isUsernameRune 사용자 이름에 쓸 수 있는 문자인지 확인합니다
+func isUsernameRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		r == '.' || r == '_' || r == '-'
+}
+
+// BeforeCreate 생성 전 이름을 정규화하고 검증합니다
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	return t.normalize()
+}
+
+// BeforeUpdate 수정 전 이름을 정규화하고 검증합니다
+func (t *Tag) BeforeUpdate(tx *gorm.DB) error {
+	return t.normalize()
+}
+
+// normalize 태그 이름을 저장 형식으로 바꿉니다
+func (t *Tag) normalize() error {
+	name, err := NormalizeTagName(t.Name)
+	if err != nil {
+		return err
+	}
+	t.Name = name
+	return nil
+}
+
+// NormalizeFileName 파일명이나 상대 경로를 유니코드 NFC로 바꿉니다
+//
+// macOS는 한글 파일명을 자모가 분리된 NFD로, Windows와 대부분의 입력기는 NFC로
+// 보내므로 같은 이름이 서로 다른 바이트열이 됩니다. 저장, 검증, 검색이 모두 이
+// 함수를 거쳐 NFC로 맞춥니다. 이미 NFC면 그대로 반환합니다.
+func NormalizeFileName(name string) string {
+	return norm.NFC.String(name)
+}
+
+// NormalizeTagName 태그 이름을 저장 형식(앞뒤 공백 제거, 소문자, NFC)으로 바꿉니다
+//
+// 정규화한 이름이 비어 있으면 ErrEmptyTagName을, MaxTagNameLength자를 넘으면
+// ErrTagNameTooLong을 반환합니다.
+func NormalizeTagName(name string) (string, error) {
+	normalized := NormalizeFileName(strings.ToLower(strings.TrimSpace(name)))
+	if normalized == "" {
+		return "", ErrEmptyTagName
+	}
+
+	if utf8.RuneCountInString(normalized) > MaxTagNameLength {
+		return "", ErrTagNameTooLong
+	}
+
+	return normalized, nil
+}
+
+// BeforeCreate 생성 전 검증 로직
+func (ks *KeySlot) BeforeCreate(tx *gorm.DB) error {
+	if ks.Iterations == 0 {
+		ks.Iterations = DefaultIterations
+	}
+
+	return ks.validate()
+}
+
+// BeforeUpdate 수정 전 검증 로직
+func (ks *KeySlot) BeforeUpdate(tx *gorm.DB) error {
+	return ks.validate()
+}
+
+// validate 키 슬롯 검증
+func (ks *KeySlot) validate() error {
+	if ks.FileID == 0 {
+		return ErrInvalidFileID
+	}
+
+	if ks.SlotIndex < 0 || ks.SlotIndex >= MaxKeySlotsPerFile {
+		return ErrInvalidSlotIndex
+	}
+
+	// Salt 검증
+	if ks.SaltHex == "" {
+		return ErrEmptySalt
 	}
 
-	return validStatuses[status]
+	if len(ks.SaltHex) > MaxSaltHexLength {
+		return ErrSaltTooLong
+	}
+
+	saltBytes, err := hex.DecodeString(ks.SaltHex)
+	if err != nil {
+		return ErrInvalidSaltHex
+	}
+
+	if len(saltBytes) != ExpectedSaltSize {
+		return ErrInvalidSaltSize
+	}
+
+	// 감싸진 키 검증
+	if ks.WrappedKeyHex == "" {
+		return ErrEmptyWrappedKey
+	}
+
+	if len(ks.WrappedKeyHex) > MaxWrappedKeyHexLength {
+		return ErrWrappedKeyTooLong
+	}
+
+	wrappedBytes, err := hex.DecodeString(ks.WrappedKeyHex)
+	if err != nil {
+		return ErrInvalidWrappedKeyHex
+	}
+
+	if len(wrappedBytes) != ExpectedWrappedKeySize {
+		return ErrInvalidWrappedKeySize
+	}
+
+	// 반복 횟수 검증 (암호화 메타데이터와 같은 범위)
+	if ks.Iterations < MinIterations || ks.Iterations > MaxIterations {
+		return ErrInvalidIterations
+	}
+
+	return nil
+}
+
+// FileStatuses 유효한 파일 상태 목록 (처리 순서)
+func FileStatuses() []string {
+	return EnumValues(EnumFileStatus)
+}
+
+// IsValidFileStatus 유효한 파일 상태인지 확인
+func IsValidFileStatus(status string) bool {
+	return isEnumValue(EnumFileStatus, status)
 }
 
 // IsValidAlgorithm 유효한 암호화 알고리즘인지 확인
 func IsValidAlgorithm(algorithm string) bool {
-	validAlgorithms := map[string]bool{
-		EncryptionAlgorithmAES256GCM: true,
+	return isEnumValue(EnumEncryptionAlgorithm, algorithm)
+}
+
+// IsValidMetadataPurpose 유효한 메타데이터 용도인지 확인
+func IsValidMetadataPurpose(purpose string) bool {
+	switch purpose {
+	case MetadataPurposePrimary, MetadataPurposeVersion, MetadataPurposeKeySlot:
+		return true
+	default:
+		return false
 	}
+}
 
-	return validAlgorithms[algorithm]
+// IsValidCompression 유효한 압축 알고리즘인지 확인
+func IsValidCompression(compression string) bool {
+	return compression == CompressionNone || compression == CompressionGzip
 }
 
 // IsValidKeyDerivation 유효한 키 유도 방식인지 확인
 func IsValidKeyDerivation(keyDerivation string) bool {
-	validDerivations := map[string]bool{
-		KeyDerivationPBKDF2SHA256: true,
-	}
-
-	return validDerivations[keyDerivation]
+	return isEnumValue(EnumKeyDerivation, keyDerivation)
 }
 
 // IsValidHex 유효한 16진수 문자열인지 확인
@@ -448,6 +1048,25 @@ func (f *File) MarkAsCorrupted() {
 	f.Status = FileStatusCorrupted
 }
 
+// IsBlobReference 다른 파일의 blob을 참조하는 레코드인지 확인
+func (f *File) IsBlobReference() bool {
+	return f.BlobFileID != nil
+}
+
+// SetSimilaritySignature 유사 중복 탐지용 SimHash 시그니처를 설정
+func (f *File) SetSimilaritySignature(signature uint64) {
+	stored := int64(signature)
+	f.SimHash = &stored
+}
+
+// SimilaritySignature 저장된 SimHash 시그니처를 반환 (없으면 false)
+func (f *File) SimilaritySignature() (uint64, bool) {
+	if f.SimHash == nil {
+		return 0, false
+	}
+	return uint64(*f.SimHash), true
+}
+
 // GetSizeInMB 파일 크기를 MB 단위로 반환
 func (f *File) GetSizeInMB() float64 {
 	const bytesPerMB = 1024 * 1024
@@ -506,3 +1125,34 @@ func (em *EncryptionMetadata) GetIterationsString() string {
 	iterations := em.Iterations / IterationsToKDivisor
 	return fmt.Sprintf("%dK", iterations)
 }
+
+// KeySlot 메소드들
+// GetSaltBytes Salt를 바이트 배열로 반환
+func (ks *KeySlot) GetSaltBytes() ([]byte, error) {
+	return hex.DecodeString(ks.SaltHex)
+}
+
+// GetWrappedKeyBytes 감싸진 키를 바이트 배열로 반환
+func (ks *KeySlot) GetWrappedKeyBytes() ([]byte, error) {
+	return hex.DecodeString(ks.WrappedKeyHex)
+}
+
+// SetSaltBytes 바이트 배열을 Salt hex 문자열로 설정
+func (ks *KeySlot) SetSaltBytes(saltBytes []byte) error {
+	if len(saltBytes) != ExpectedSaltSize {
+		return ErrInvalidSaltSize
+	}
+
+	ks.SaltHex = hex.EncodeToString(saltBytes)
+	return nil
+}
+
+// SetWrappedKeyBytes 바이트 배열을 감싸진 키 hex 문자열로 설정
+func (ks *KeySlot) SetWrappedKeyBytes(wrappedBytes []byte) error {
+	if len(wrappedBytes) != ExpectedWrappedKeySize {
+		return ErrInvalidWrappedKeySize
+	}
+
+	ks.WrappedKeyHex = hex.EncodeToString(wrappedBytes)
+	return nil
+}
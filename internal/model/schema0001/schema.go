@@ -0,0 +1,282 @@
+// Package schema0001 freezes the DataLocker model structs as of migration 0001.
+//
+// 마이그레이션 0001(초기 스키마)은 이 구조체를 기준으로 테이블을 맞춥니다. 모델
+// 패키지의 구조체는 계속 바뀌므로 여기 구조체는 고치지 말고, 이후의 스키마 변경은
+// model 패키지에 새 마이그레이션으로 추가합니다. 제약조건과 연결 테이블 이름이
+// 모델과 같아야 하므로 타입 이름과 필드 이름도 당시 모델과 같게 둡니다.
+package schema0001
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Models 0001에서 만드는 테이블의 구조체 (외래키가 참조하는 테이블이 먼저)
+var Models = []interface{}{
+	&User{},   // files.owner_id 외래키가 참조하므로 File보다 먼저
+	&Tag{},    // file_tags 연결 테이블이 참조하므로 File보다 먼저
+	&Folder{}, // files.folder_id 외래키가 참조하므로 File보다 먼저
+	&File{},   // Tags 관계로 file_tags 연결 테이블도 함께 생성
+	&EncryptionMetadata{},
+	&KeySlot{},
+	&ValidationSession{},
+	&ValidationFileResult{},
+	&MetricsSnapshot{},
+	&IdempotencyRecord{},
+	&FileLock{},
+}
+
+// DropOrder 0001을 되돌릴 때 삭제하는 테이블 (외래키 제약조건 때문에 역순)
+var DropOrder = []interface{}{
+	&FileLock{},
+	&IdempotencyRecord{},
+	&MetricsSnapshot{},
+	&ValidationFileResult{},
+	&ValidationSession{},
+	"file_tags",
+	&KeySlot{},
+	&EncryptionMetadata{},
+	&File{},
+	&Folder{},
+	&Tag{},
+	&User{},
+}
+
+// File 0001 시점의 files 테이블
+type File struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement"`
+	CreatedAt time.Time      `gorm:"not null;index:idx_files_created_at"`
+	UpdatedAt time.Time      `gorm:"not null"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	OriginalName  string `gorm:"type:varchar(255);not null;index:idx_files_original_name"`
+	EncryptedPath string `gorm:"type:varchar(500);not null;unique"`
+	Size          int64  `gorm:"not null;check:size >= 0"`
+	EncryptedSize int64  `gorm:"not null;default:0;check:encrypted_size >= 0"`
+	MimeType      string `gorm:"type:varchar(100);not null"`
+	ChecksumMD5   string `gorm:"type:varchar(64);not null;index:idx_files_checksum"`
+	Status        string `gorm:"type:varchar(20);not null;default:'pending';index:idx_files_status"`
+	FailureReason string `gorm:"type:varchar(255)"`
+	TextEncoding  string `gorm:"type:varchar(20)"`
+
+	Description    string `gorm:"type:varchar(1000)"`
+	CustomMetadata datatypes.JSON
+
+	CreatedBy string `gorm:"type:varchar(64);not null;default:'system'"`
+	UpdatedBy string `gorm:"type:varchar(64);not null;default:'system'"`
+
+	VolumeID   string `gorm:"type:varchar(64);index:idx_files_volume_id"`
+	ExternalID string `gorm:"type:varchar(128);uniqueIndex:idx_files_external_id,where:external_id <> '' AND deleted_at IS NULL"`
+	OwnerID    *uint  `gorm:"index:idx_files_owner_id"`
+	FolderID   *uint  `gorm:"index:idx_files_folder_id"`
+	ShortCode  string `gorm:"type:varchar(8);uniqueIndex:idx_files_short_code,where:short_code <> ''"`
+
+	BlobFileID  *uint  `gorm:"index:idx_files_blob_file_id"`
+	BlockHashes string `gorm:"type:text"`
+
+	SimHash        *int64     `gorm:"column:sim_hash"`
+	LastAccessedAt *time.Time `gorm:"index:idx_files_last_accessed_at"`
+	Version        uint       `gorm:"not null;default:1"`
+
+	EncryptionMetadata *EncryptionMetadata `gorm:"foreignKey:FileID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	KeySlots           []*KeySlot          `gorm:"foreignKey:FileID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Tags               []*Tag              `gorm:"many2many:file_tags;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// User 0001 시점의 users 테이블
+type User struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	CreatedAt time.Time `gorm:"not null"`
+
+	Username     string `gorm:"type:varchar(64);not null;uniqueIndex:idx_users_username"`
+	PasswordHash string `gorm:"type:varchar(255);not null"`
+
+	Files []*File `gorm:"foreignKey:OwnerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// Tag 0001 시점의 tags 테이블
+type Tag struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	CreatedAt time.Time `gorm:"not null"`
+
+	Name string `gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_name"`
+}
+
+// Folder 0001 시점의 folders 테이블
+type Folder struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+
+	Name     string `gorm:"type:varchar(255);not null;uniqueIndex:idx_folders_parent_name,priority:2;uniqueIndex:idx_folders_root_name,where:parent_id IS NULL"`
+	ParentID *uint  `gorm:"uniqueIndex:idx_folders_parent_name,priority:1"`
+
+	Children []*Folder `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Files    []*File   `gorm:"foreignKey:FolderID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+}
+
+// EncryptionMetadata 0001 시점의 encryption_metadata 테이블
+type EncryptionMetadata struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	CreatedAt time.Time `gorm:"not null;index:idx_encryption_metadata_created_at"`
+	UpdatedAt time.Time `gorm:"not null"`
+
+	FileID  uint   `gorm:"not null;uniqueIndex:idx_encryption_metadata_file_purpose_slot"`
+	Purpose string `gorm:"type:varchar(20);not null;default:'primary';uniqueIndex:idx_encryption_metadata_file_purpose_slot"`
+	Slot    int    `gorm:"not null;default:0;uniqueIndex:idx_encryption_metadata_file_purpose_slot;check:slot >= 0"`
+
+	Algorithm     string `gorm:"type:varchar(50);not null;default:'AES-256-GCM';index:idx_encryption_metadata_algorithm"`
+	KeyDerivation string `gorm:"type:varchar(50);not null;default:'PBKDF2-SHA256'"`
+	SaltHex       string `gorm:"type:varchar(64);not null"`
+	NonceHex      string `gorm:"type:varchar(24);not null"`
+	Iterations    int    `gorm:"not null;default:100000;index:idx_encryption_metadata_iterations;check:iterations >= 1000 AND iterations <= 1000000"`
+
+	FormatVersion       int    `gorm:"not null;default:0"`
+	Compression         string `gorm:"type:varchar(20);not null;default:'none'"`
+	PasswordFingerprint string `gorm:"type:varchar(64);index:idx_encryption_metadata_fingerprint"`
+
+	File *File `gorm:"foreignKey:FileID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// KeySlot 0001 시점의 key_slots 테이블 (반복 횟수 컬럼은 0003에서 추가)
+type KeySlot struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+
+	FileID    uint `gorm:"not null;uniqueIndex:idx_key_slots_file_slot"`
+	SlotIndex int  `gorm:"not null;uniqueIndex:idx_key_slots_file_slot;check:slot_index >= 0"`
+
+	SaltHex       string `gorm:"type:varchar(64);not null"`
+	WrappedKeyHex string `gorm:"type:varchar(120);not null"`
+
+	File *File `gorm:"foreignKey:FileID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// ValidationSession 0001 시점의 validation_sessions 테이블
+type ValidationSession struct {
+	ID        string    `gorm:"primaryKey;type:varchar(32)"`
+	CreatedAt time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null;index:idx_validation_sessions_expires_at"`
+
+	DirectoryPath string   `gorm:"type:text;not null"`
+	IsValid       bool     `gorm:"not null"`
+	TotalFiles    int      `gorm:"not null"`
+	TotalSize     int64    `gorm:"not null"`
+	ValidFiles    int      `gorm:"not null"`
+	InvalidFiles  int      `gorm:"not null"`
+	Errors        []string `gorm:"type:text;serializer:json"`
+
+	Results []*ValidationFileResult `gorm:"foreignKey:SessionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// ValidationFileResult 0001 시점의 validation_file_results 테이블
+type ValidationFileResult struct {
+	ID uint `gorm:"primaryKey;autoIncrement"`
+
+	SessionID string `gorm:"type:varchar(32);not null;uniqueIndex:idx_validation_file_results_session_seq"`
+	Seq       int    `gorm:"not null;uniqueIndex:idx_validation_file_results_session_seq"`
+
+	FileName     string   `gorm:"type:text;not null"`
+	RelativePath string   `gorm:"type:text"`
+	IsValid      bool     `gorm:"not null"`
+	MIMEGroup    string   `gorm:"type:varchar(100)"`
+	MaxSize      int64    `gorm:"not null"`
+	Errors       []string `gorm:"type:text;serializer:json"`
+}
+
+// MetricsSnapshot 0001 시점의 metrics_snapshots 테이블
+type MetricsSnapshot struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	CreatedAt time.Time `gorm:"not null"`
+
+	Date string `gorm:"type:char(10);not null;uniqueIndex:idx_metrics_snapshots_date"`
+
+	TotalFiles int64 `gorm:"not null"`
+	TotalBytes int64 `gorm:"not null"`
+	Uploads    int64 `gorm:"not null"`
+	Failures   int64 `gorm:"not null"`
+}
+
+// IdempotencyRecord 0001 시점의 idempotency_records 테이블
+type IdempotencyRecord struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	CreatedAt time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null;index:idx_idempotency_records_expires_at"`
+
+	Key   string `gorm:"column:idempotency_key;type:varchar(255);not null;uniqueIndex:idx_idempotency_records_key_owner"`
+	Owner string `gorm:"type:varchar(64);not null;uniqueIndex:idx_idempotency_records_key_owner"`
+
+	RequestHash string `gorm:"type:char(64);not null"`
+
+	StatusCode  int    `gorm:"not null;default:0"`
+	ContentType string `gorm:"type:varchar(100)"`
+	Body        []byte `gorm:"type:blob"`
+}
+
+// FileLock 0001 시점의 file_locks 테이블 (영구 삭제된 파일의 락도 남도록 외래키 없음)
+type FileLock struct {
+	FileID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+
+	Owner string `gorm:"type:varchar(64);not null"`
+	Token string `gorm:"type:char(32);not null"`
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (File) TableName() string {
+	return "files"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (User) TableName() string {
+	return "users"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (Folder) TableName() string {
+	return "folders"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (EncryptionMetadata) TableName() string {
+	return "encryption_metadata"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (KeySlot) TableName() string {
+	return "key_slots"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (ValidationSession) TableName() string {
+	return "validation_sessions"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (ValidationFileResult) TableName() string {
+	return "validation_file_results"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (MetricsSnapshot) TableName() string {
+	return "metrics_snapshots"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_records"
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (FileLock) TableName() string {
+	return "file_locks"
+}
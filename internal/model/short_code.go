@@ -0,0 +1,66 @@
+// Package model provides database models for DataLocker application.
+// This file generates and normalizes the short file codes shared by people.
+package model
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+)
+
+// 짧은 파일 코드 형식
+const (
+	// ShortCodeLength 짧은 파일 코드 길이 (40비트, 약 1.1조 가지)
+	ShortCodeLength = 8
+
+	// ShortCodeAlphabet Crockford Base32 문자 (혼동하기 쉬운 I, L, O, U 제외)
+	ShortCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// shortCodeAliases 읽거나 받아 적을 때 혼동하는 문자를 같은 코드로 취급하기 위한 치환
+var shortCodeAliases = strings.NewReplacer(
+	"-", "", " ", "",
+	"I", "1", "L", "1", "O", "0",
+)
+
+// NewShortCode 암호학적 난수로 새 짧은 파일 코드를 만듭니다
+//
+// 문자 수(32)가 256의 약수라 바이트의 하위 5비트만 써도 치우치지 않습니다.
+// 유일성은 보장하지 않으므로 저장할 때 충돌하면 다시 만들어야 합니다.
+func NewShortCode() (string, error) {
+	buf := make([]byte, ShortCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("짧은 파일 코드 생성 실패: %w", err)
+	}
+
+	for i, b := range buf {
+		buf[i] = ShortCodeAlphabet[b&0x1F]
+	}
+	return string(buf), nil
+}
+
+// NormalizeShortCode 사용자가 입력한 코드를 저장 형식으로 바꿉니다
+//
+// 대소문자를 구분하지 않고, 구분용 하이픈과 공백을 무시하며, I/L은 1로 O는 0으로
+// 읽습니다. 정규화한 결과가 형식에 맞지 않으면 ErrInvalidShortCode를 반환합니다.
+func NormalizeShortCode(code string) (string, error) {
+	normalized := shortCodeAliases.Replace(strings.ToUpper(strings.TrimSpace(code)))
+	if !IsValidShortCode(normalized) {
+		return "", ErrInvalidShortCode
+	}
+	return normalized, nil
+}
+
+// IsValidShortCode 코드가 저장 형식(대문자 Crockford Base32 8자)인지 확인합니다
+func IsValidShortCode(code string) bool {
+	if len(code) != ShortCodeLength {
+		return false
+	}
+
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(ShortCodeAlphabet, code[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
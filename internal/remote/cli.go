@@ -0,0 +1,515 @@
+// Package remote provides a client and command-line front end for the
+// DataLocker admin API, used to manage a running server remotely.
+// This file implements the `datalocker remote` subcommand.
+package remote
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"DataLocker/internal/repository"
+)
+
+// 종료 코드 규약 (스크립트에서 실패 원인을 구분하기 위함)
+const (
+	ExitOK        = 0 // 성공
+	ExitFailure   = 1 // 서버가 요청을 거부했거나 사용자가 취소함
+	ExitUsage     = 2 // 잘못된 명령/인자
+	ExitNetwork   = 3 // 연결 실패, 타임아웃 등 네트워크 오류
+	ExitAuth      = 4 // 토큰 누락 또는 인증 실패
+	ExitIntegrity = 5 // verify 결과 파일이 손상됨
+)
+
+// CLI 환경변수
+const (
+	EnvAddr     = "DATALOCKER_ADDR"
+	EnvToken    = "DATALOCKER_TOKEN"
+	EnvPassword = "DATALOCKER_PASSWORD" //nolint:gosec // 환경변수 이름
+)
+
+// 기본값
+const (
+	defaultAddr    = "http://localhost:8080"
+	defaultTimeout = 30 * time.Second
+)
+
+// 표 출력의 생성 시각 형식
+const tableTimeLayout = "2006-01-02 15:04"
+
+// CLI 에러
+var (
+	errAborted      = errors.New("사용자가 취소했습니다")
+	errMissingToken = errors.New("관리 API 토큰이 필요합니다 (--token 또는 " + EnvToken + ")")
+	errCorrupted    = errors.New("무결성 검사에 실패했습니다")
+)
+
+// usageError 잘못된 명령/인자
+type usageError struct {
+	msg string
+}
+
+// Error 에러 메시지를 반환합니다
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// Streams 명령 입출력
+type Streams struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// options 전역 플래그
+type options struct {
+	addr    string
+	token   string
+	timeout time.Duration
+	json    bool
+	yes     bool
+}
+
+// Run `datalocker remote` 이후의 인자를 실행하고 종료 코드를 반환합니다
+//
+//	datalocker remote [--addr URL] [--token TOKEN] [--json] [-y] files list [--page N] [--page-size N] [--sort 기준] [--order asc|desc]
+//	datalocker remote ... files verify <id> [--password PASSWORD]
+//	datalocker remote ... files purge <id> [-y]
+//	datalocker remote ... volumes list
+//	datalocker remote ... volumes rebalance [--dry-run] [--max-files N] [-y]
+//	datalocker remote ... stats backfill [--days N]
+func Run(ctx context.Context, args []string, streams Streams) int {
+	err := run(ctx, args, streams)
+	if err == nil {
+		return ExitOK
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		fmt.Fprintf(streams.Err, "오류: %s\n\n", usageErr.msg)
+		printUsage(streams.Err)
+		return ExitUsage
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+
+	fmt.Fprintf(streams.Err, "오류: %s\n", err)
+	return exitCode(err)
+}
+
+// exitCode 에러 종류에 맞는 종료 코드를 반환합니다
+func exitCode(err error) int {
+	var apiErr *APIError
+	switch {
+	case errors.Is(err, ErrNetwork):
+		return ExitNetwork
+	case errors.As(err, &apiErr) && apiErr.IsAuthError():
+		return ExitAuth
+	case errors.Is(err, errMissingToken):
+		return ExitAuth
+	case errors.Is(err, errCorrupted):
+		return ExitIntegrity
+	default:
+		return ExitFailure
+	}
+}
+
+// run 플래그를 해석하고 하위 명령을 실행합니다
+func run(ctx context.Context, args []string, streams Streams) error {
+	opts := &options{}
+
+	fs := newFlagSet("remote", streams.Err)
+	fs.StringVar(&opts.addr, "addr", envOrDefault(EnvAddr, defaultAddr), "서버 주소 (환경변수 "+EnvAddr+")")
+	fs.StringVar(&opts.token, "token", os.Getenv(EnvToken), "관리 API 토큰 (환경변수 "+EnvToken+" 권장)")
+	fs.DurationVar(&opts.timeout, "timeout", defaultTimeout, "요청 타임아웃")
+	bindOutputFlags(fs, opts)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rest := fs.Args()
+	if len(rest) < 2 || (rest[0] != "files" && rest[0] != "volumes" && rest[0] != "stats") {
+		return &usageError{msg: "files <list|verify|purge>, volumes <list|rebalance> 또는 stats backfill 명령이 필요합니다"}
+	}
+
+	if opts.token == "" {
+		return errMissingToken
+	}
+
+	client, err := NewClient(opts.addr, opts.token, &http.Client{Timeout: opts.timeout})
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+
+	cmd := &command{client: client, opts: opts, streams: streams}
+	switch rest[0] + " " + rest[1] {
+	case "files list":
+		return cmd.list(ctx, rest[2:])
+	case "files verify":
+		return cmd.verify(ctx, rest[2:])
+	case "files purge":
+		return cmd.purge(ctx, rest[2:])
+	case "volumes list":
+		return cmd.volumes(ctx, rest[2:])
+	case "volumes rebalance":
+		return cmd.rebalance(ctx, rest[2:])
+	case "stats backfill":
+		return cmd.backfill(ctx, rest[2:])
+	default:
+		return &usageError{msg: "알 수 없는 명령입니다: " + rest[0] + " " + rest[1]}
+	}
+}
+
+// command 하위 명령 실행 컨텍스트
+type command struct {
+	client  *Client
+	opts    *options
+	streams Streams
+}
+
+// list 파일 목록을 출력합니다
+func (c *command) list(ctx context.Context, args []string) error {
+	var page, pageSize int
+	var sort repository.SortOption
+	fs := newFlagSet("files list", c.streams.Err)
+	fs.IntVar(&page, "page", 1, "페이지 번호")
+	fs.IntVar(&pageSize, "page-size", 0, "페이지 크기 (기본값: 서버 설정)")
+	fs.StringVar(&sort.Field, "sort", "", "정렬 기준 (created_at, name, size, status)")
+	fs.StringVar(&sort.Direction, "order", "", "정렬 방향 (asc, desc)")
+	bindOutputFlags(fs, c.opts)
+	if _, err := parsePositional(fs, args, 0); err != nil {
+		return err
+	}
+
+	list, err := c.client.ListFiles(ctx, page, pageSize, sort)
+	if err != nil {
+		return err
+	}
+
+	if c.opts.json {
+		return writeJSON(c.streams.Out, list)
+	}
+
+	tw := tabwriter.NewWriter(c.streams.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tSTATUS\tSIZE\tCREATED")
+	for _, file := range list.Files {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\n",
+			file.ID, file.OriginalName, file.Status, file.Size, file.CreatedAt.Local().Format(tableTimeLayout))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("출력 실패: %w", err)
+	}
+
+	fmt.Fprintf(c.streams.Out, "\n총 %d개 (페이지 %d, 페이지당 %d개)\n", list.Total, list.Page, list.PageSize)
+	return nil
+}
+
+// verify 파일의 무결성을 검사하고 손상 시 ExitIntegrity로 종료합니다
+func (c *command) verify(ctx context.Context, args []string) error {
+	var password string
+	fs := newFlagSet("files verify", c.streams.Err)
+	fs.StringVar(&password, "password", os.Getenv(EnvPassword), "암호화 패스워드 (환경변수 "+EnvPassword+" 권장)")
+	bindOutputFlags(fs, c.opts)
+	positional, err := parsePositional(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+
+	if password == "" {
+		return &usageError{msg: "암호화 패스워드가 필요합니다 (--password 또는 " + EnvPassword + ")"}
+	}
+
+	result, err := c.client.VerifyFile(ctx, id, password)
+	if err != nil {
+		return err
+	}
+
+	if c.opts.json {
+		if err := writeJSON(c.streams.Out, result); err != nil {
+			return err
+		}
+	} else if result.Valid {
+		fmt.Fprintf(c.streams.Out, "파일 %d: 정상\n", result.FileID)
+	} else {
+		fmt.Fprintf(c.streams.Out, "파일 %d: 손상됨 (%s)\n", result.FileID, result.Reason)
+	}
+
+	if !result.Valid {
+		return fmt.Errorf("파일 %d: %w", result.FileID, errCorrupted)
+	}
+	return nil
+}
+
+// purge 확인 후 파일을 영구 삭제합니다
+func (c *command) purge(ctx context.Context, args []string) error {
+	fs := newFlagSet("files purge", c.streams.Err)
+	bindOutputFlags(fs, c.opts)
+	positional, err := parsePositional(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+
+	if !c.opts.yes {
+		prompt := fmt.Sprintf("파일 %d과(와) 암호화본을 영구 삭제합니다. 되돌릴 수 없습니다. 계속할까요? [y/N]: ", id)
+		if !confirm(c.streams.In, c.streams.Err, prompt) {
+			return errAborted
+		}
+	}
+
+	if err := c.client.PurgeFile(ctx, id); err != nil {
+		return err
+	}
+
+	if c.opts.json {
+		return writeJSON(c.streams.Out, map[string]interface{}{"file_id": id, "purged": true})
+	}
+
+	fmt.Fprintf(c.streams.Out, "파일 %d을(를) 영구 삭제했습니다\n", id)
+	return nil
+}
+
+// volumes 저장소 볼륨별 사용량을 출력합니다
+func (c *command) volumes(ctx context.Context, args []string) error {
+	fs := newFlagSet("volumes list", c.streams.Err)
+	bindOutputFlags(fs, c.opts)
+	if _, err := parsePositional(fs, args, 0); err != nil {
+		return err
+	}
+
+	stats, err := c.client.VolumeStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.opts.json {
+		return writeJSON(c.streams.Out, stats)
+	}
+
+	tw := tabwriter.NewWriter(c.streams.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATE\tWEIGHT\tFILES\tUSED\tMAX\tPATH")
+	for _, v := range stats {
+		state, limit := "online", "-"
+		if !v.Online {
+			state = "offline"
+		}
+		if v.MaxBytes > 0 {
+			limit = fmt.Sprintf("%d (%.0f%%)", v.MaxBytes, v.UsedRatio*100)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%s\t%s\n", v.ID, state, v.Weight, v.Files, v.UsedBytes, limit, v.Path)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("출력 실패: %w", err)
+	}
+	return nil
+}
+
+// rebalance 확인 후 볼륨 간 파일을 재배치합니다 (--dry-run이면 계획만 출력)
+func (c *command) rebalance(ctx context.Context, args []string) error {
+	var (
+		dryRun   bool
+		maxFiles int
+	)
+	fs := newFlagSet("volumes rebalance", c.streams.Err)
+	fs.BoolVar(&dryRun, "dry-run", false, "옮길 계획만 출력")
+	fs.IntVar(&maxFiles, "max-files", 0, "한 번에 옮길 최대 파일 수 (기본값: 서버 설정)")
+	bindOutputFlags(fs, c.opts)
+	if _, err := parsePositional(fs, args, 0); err != nil {
+		return err
+	}
+
+	if maxFiles < 0 {
+		return &usageError{msg: fmt.Sprintf("--max-files는 0 이상이어야 합니다: %d", maxFiles)}
+	}
+
+	if !dryRun && !c.opts.yes {
+		prompt := "볼륨 간에 암호화 파일을 옮깁니다. 옮기는 동안 해당 파일 접근이 느려질 수 있습니다. 계속할까요? [y/N]: "
+		if !confirm(c.streams.In, c.streams.Err, prompt) {
+			return errAborted
+		}
+	}
+
+	result, err := c.client.RebalanceVolumes(ctx, dryRun, maxFiles)
+	if err != nil {
+		return err
+	}
+
+	if c.opts.json {
+		if err := writeJSON(c.streams.Out, result); err != nil {
+			return err
+		}
+	} else {
+		verb := "옮겼습니다"
+		if result.DryRun {
+			verb = "옮길 예정입니다"
+		}
+		for _, move := range result.Moves {
+			fmt.Fprintf(c.streams.Out, "파일 %d (%d 바이트): %s -> %s\n", move.FileID, move.Size, move.From, move.To)
+		}
+		for _, failure := range result.Failed {
+			fmt.Fprintf(c.streams.Out, "실패: %s\n", failure)
+		}
+		fmt.Fprintf(c.streams.Out, "\n파일 %d개를 %s\n", len(result.Moves), verb)
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("파일 %d개를 옮기지 못했습니다", len(result.Failed))
+	}
+	return nil
+}
+
+// backfill 누락된 일별 통계 스냅샷을 채웁니다 (이미 있는 날은 그대로 두므로 확인 없이 실행)
+func (c *command) backfill(ctx context.Context, args []string) error {
+	var days int
+	fs := newFlagSet("stats backfill", c.streams.Err)
+	fs.IntVar(&days, "days", 0, "어제까지 채울 기간 (일, 기본값: 서버 설정)")
+	bindOutputFlags(fs, c.opts)
+	if _, err := parsePositional(fs, args, 0); err != nil {
+		return err
+	}
+
+	if days < 0 {
+		return &usageError{msg: fmt.Sprintf("--days는 0 이상이어야 합니다: %d", days)}
+	}
+
+	result, err := c.client.BackfillMetrics(ctx, days)
+	if err != nil {
+		return err
+	}
+
+	if c.opts.json {
+		return writeJSON(c.streams.Out, result)
+	}
+
+	for _, date := range result.Created {
+		fmt.Fprintf(c.streams.Out, "%s: 저장\n", date)
+	}
+	fmt.Fprintf(c.streams.Out, "\n%s ~ %s: %d일 저장, %d일은 이미 있음\n", result.From, result.To, len(result.Created), result.Existing)
+	return nil
+}
+
+// confirm 프롬프트를 출력하고 y/yes 입력 여부를 반환합니다 (입력이 없으면 거부)
+func confirm(in io.Reader, out io.Writer, prompt string) bool {
+	fmt.Fprint(out, prompt)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(out)
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// newFlagSet 에러를 반환하는 FlagSet을 생성합니다
+func newFlagSet(name string, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	return fs
+}
+
+// bindOutputFlags 어느 위치에서든 쓸 수 있는 공통 플래그를 등록합니다
+func bindOutputFlags(fs *flag.FlagSet, opts *options) {
+	fs.BoolVar(&opts.json, "json", opts.json, "JSON으로 출력")
+	fs.BoolVar(&opts.yes, "y", opts.yes, "확인 프롬프트 생략")
+}
+
+// parsePositional 플래그와 위치 인자가 섞인 인자를 해석하고 위치 인자 개수를 검사합니다
+func parsePositional(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &usageError{msg: err.Error()}
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != want {
+		return nil, &usageError{msg: fmt.Sprintf("%s: 인자 %d개가 필요합니다 (입력: %d개)", fs.Name(), want, len(positional))}
+	}
+	return positional, nil
+}
+
+// parseID 파일 ID를 파싱합니다
+func parseID(value string) (uint, error) {
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return 0, &usageError{msg: "파일 ID는 양의 정수여야 합니다: " + value}
+	}
+	return uint(id), nil
+}
+
+// writeJSON 값을 들여쓴 JSON으로 출력합니다
+func writeJSON(out io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("JSON 출력 실패: %w", err)
+	}
+	return nil
+}
+
+// envOrDefault 환경변수가 비어 있으면 기본값을 반환합니다
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// printUsage 사용법을 출력합니다
+func printUsage(out io.Writer) {
+	fmt.Fprint(out, `사용법:
+  datalocker remote [전역 옵션] files list [--page N] [--page-size N] [--sort 기준] [--order asc|desc]
+  datalocker remote [전역 옵션] files verify <id> [--password PASSWORD]
+  datalocker remote [전역 옵션] files purge <id> [-y]
+  datalocker remote [전역 옵션] volumes list
+  datalocker remote [전역 옵션] volumes rebalance [--dry-run] [--max-files N] [-y]
+  datalocker remote [전역 옵션] stats backfill [--days N]
+
+전역 옵션:
+  --addr URL        서버 주소 (기본값: `+defaultAddr+`, 환경변수 `+EnvAddr+`)
+  --token TOKEN     관리 API 토큰 (환경변수 `+EnvToken+` 권장)
+  --timeout DUR     요청 타임아웃 (기본값: 30s)
+  --json            JSON으로 출력
+  -y                파괴적 명령의 확인 프롬프트 생략
+
+종료 코드:
+  0 성공, 1 요청 실패/취소, 2 사용법 오류, 3 네트워크 오류, 4 인증 실패, 5 무결성 검사 실패
+`)
+}
@@ -0,0 +1,301 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"DataLocker/internal/handler"
+	"DataLocker/internal/middleware"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "remote-test-token"
+
+// fakeAdminService 호출을 기록하는 관리 서비스
+type fakeAdminService struct {
+	files   []*model.File
+	valid   bool
+	purged  []uint
+	listErr error
+
+	rebalanced []bool                // 재배치 요청의 dry run 여부
+	sort       repository.SortOption // 목록 요청의 정렬 조건
+}
+
+func (s *fakeAdminService) ListFiles(_ context.Context, _ repository.FileFilter, page, pageSize int, sort repository.SortOption) (*service.AdminFileList, error) {
+	s.sort = sort
+	if s.listErr != nil {
+		return nil, s.listErr
+	}
+	return &service.AdminFileList{Files: s.files, Total: int64(len(s.files)), Page: page, PageSize: pageSize}, nil
+}
+
+func (s *fakeAdminService) ListFilesAfter(_ context.Context, _ string, pageSize int) (*service.AdminFileCursorPage, error) {
+	if s.listErr != nil {
+		return nil, s.listErr
+	}
+	return &service.AdminFileCursorPage{Files: s.files, PageSize: pageSize}, nil
+}
+
+func (s *fakeAdminService) GetFile(_ context.Context, fileID uint) (*model.File, error) {
+	return &model.File{ID: fileID}, nil
+}
+
+func (s *fakeAdminService) VerifyFile(_ context.Context, fileID uint, _ string) (*service.IntegrityResult, error) {
+	if s.valid {
+		return &service.IntegrityResult{FileID: fileID, Valid: true}, nil
+	}
+	return &service.IntegrityResult{FileID: fileID, Reason: "chunk authentication failed"}, nil
+}
+
+func (s *fakeAdminService) DeleteFile(_ context.Context, _ uint) error {
+	return nil
+}
+
+func (s *fakeAdminService) RestoreFile(_ context.Context, fileID uint) (*model.File, error) {
+	return &model.File{ID: fileID}, nil
+}
+
+func (s *fakeAdminService) UpdateFileDetails(_ context.Context, fileID uint, _ service.FileDetailsUpdate) (*model.File, error) {
+	return &model.File{ID: fileID}, nil
+}
+
+func (s *fakeAdminService) CheckMetadata(_ context.Context) (*service.MetadataCheckResult, error) {
+	return &service.MetadataCheckResult{}, nil
+}
+
+func (s *fakeAdminService) PurgeFile(_ context.Context, fileID uint) error {
+	s.purged = append(s.purged, fileID)
+	return nil
+}
+
+func (s *fakeAdminService) VolumeStats(_ context.Context) ([]service.VolumeStats, error) {
+	return []service.VolumeStats{
+		{ID: "hot", Path: "/mnt/hot", Weight: 1, Online: true, Files: 3, UsedBytes: 500, MaxBytes: 1000, UsedRatio: 0.5},
+		{ID: "cold", Path: "/mnt/cold", Weight: 2},
+	}, nil
+}
+
+func (s *fakeAdminService) RebalanceVolumes(ctx context.Context, dryRun bool, _ int) (*service.RebalanceResult, error) {
+	s.rebalanced = append(s.rebalanced, dryRun)
+	volumes, _ := s.VolumeStats(ctx)
+	return &service.RebalanceResult{
+		DryRun:  dryRun,
+		Moves:   []service.RebalanceMove{{FileID: 9, Size: 400, From: "hot", To: "cold"}},
+		Volumes: volumes,
+	}, nil
+}
+
+// startAdminServer 실제 관리 라우트와 인증 미들웨어를 가진 테스트 서버를 시작합니다
+func startAdminServer(t *testing.T, svc service.AdminService) *httptest.Server {
+	t.Helper()
+
+	h := handler.NewAdminHandler(svc)
+	e := echo.New()
+	admin := e.Group("/api/v1/admin", middleware.AdminAuthMiddleware(testToken))
+	admin.GET("/files", h.ListFiles)
+	admin.POST("/files/:id/verify", h.VerifyFile)
+	admin.DELETE("/files/:id", h.PurgeFile)
+	admin.GET("/volumes", h.VolumeStats)
+	admin.POST("/volumes/rebalance", h.RebalanceVolumes)
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// runCLI 명령을 실행하고 종료 코드와 출력을 반환합니다
+func runCLI(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), args, Streams{In: strings.NewReader(stdin), Out: &stdout, Err: &stderr})
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_ListFiles(t *testing.T) {
+	svc := &fakeAdminService{files: []*model.File{
+		{ID: 7, OriginalName: "report.pdf", Status: model.FileStatusEncrypted, Size: 2048, CreatedAt: time.Now()},
+	}}
+	server := startAdminServer(t, svc)
+
+	code, stdout, _ := runCLI(t, "", "--addr", server.URL, "--token", testToken, "files", "list")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "ID")
+	assert.Contains(t, stdout, "report.pdf")
+	assert.Contains(t, stdout, "총 1개")
+
+	// 플래그는 하위 명령 뒤에도 올 수 있음
+	code, stdout, _ = runCLI(t, "", "--addr", server.URL, "--token", testToken, "files", "list", "--json")
+	assert.Equal(t, ExitOK, code)
+
+	var list service.AdminFileList
+	require.NoError(t, json.Unmarshal([]byte(stdout), &list))
+	require.Len(t, list.Files, 1)
+	assert.Equal(t, uint(7), list.Files[0].ID)
+
+	code, _, _ = runCLI(t, "", "--addr", server.URL, "--token", testToken, "files", "list", "--sort", "size", "--order", "asc")
+	assert.Equal(t, ExitOK, code)
+	assert.Equal(t, repository.SortOption{Field: repository.SortFieldSize, Direction: repository.SortAsc}, svc.sort)
+}
+
+func TestRun_VerifyFile(t *testing.T) {
+	t.Setenv(EnvPassword, "secret")
+
+	server := startAdminServer(t, &fakeAdminService{valid: true})
+	code, stdout, _ := runCLI(t, "", "--addr", server.URL, "--token", testToken, "files", "verify", "3")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "정상")
+
+	server = startAdminServer(t, &fakeAdminService{valid: false})
+	code, stdout, _ = runCLI(t, "", "--addr", server.URL, "--token", testToken, "--json", "files", "verify", "3")
+	assert.Equal(t, ExitIntegrity, code)
+
+	var result service.IntegrityResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.False(t, result.Valid)
+}
+
+func TestRun_PurgeConfirmation(t *testing.T) {
+	testCases := []struct {
+		name       string
+		stdin      string
+		args       []string
+		wantCode   int
+		wantPurged []uint
+	}{
+		{name: "거부", stdin: "n\n", args: []string{"files", "purge", "5"}, wantCode: ExitFailure},
+		{name: "입력 없음", stdin: "", args: []string{"files", "purge", "5"}, wantCode: ExitFailure},
+		{name: "승인", stdin: "yes\n", args: []string{"files", "purge", "5"}, wantCode: ExitOK, wantPurged: []uint{5}},
+		{name: "-y로 생략", args: []string{"files", "purge", "5", "-y"}, wantCode: ExitOK, wantPurged: []uint{5}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &fakeAdminService{}
+			server := startAdminServer(t, svc)
+
+			args := append([]string{"--addr", server.URL, "--token", testToken}, tc.args...)
+			code, _, _ := runCLI(t, tc.stdin, args...)
+			assert.Equal(t, tc.wantCode, code)
+			assert.Equal(t, tc.wantPurged, svc.purged)
+		})
+	}
+}
+
+func TestRun_Volumes(t *testing.T) {
+	server := startAdminServer(t, &fakeAdminService{})
+
+	code, stdout, _ := runCLI(t, "", "--addr", server.URL, "--token", testToken, "volumes", "list")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "hot")
+	assert.Contains(t, stdout, "offline")
+	assert.Contains(t, stdout, "1000 (50%)")
+}
+
+func TestRun_RebalanceVolumes(t *testing.T) {
+	testCases := []struct {
+		name           string
+		stdin          string
+		args           []string
+		wantCode       int
+		wantRebalanced []bool
+	}{
+		{name: "dry run은 확인 없이 실행", args: []string{"volumes", "rebalance", "--dry-run"}, wantCode: ExitOK, wantRebalanced: []bool{true}},
+		{name: "거부", stdin: "n\n", args: []string{"volumes", "rebalance"}, wantCode: ExitFailure},
+		{name: "-y로 생략", args: []string{"volumes", "rebalance", "-y", "--max-files", "10"}, wantCode: ExitOK, wantRebalanced: []bool{false}},
+		{name: "음수 최대 파일 수", args: []string{"volumes", "rebalance", "--max-files", "-1"}, wantCode: ExitUsage},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &fakeAdminService{}
+			server := startAdminServer(t, svc)
+
+			args := append([]string{"--addr", server.URL, "--token", testToken}, tc.args...)
+			code, stdout, _ := runCLI(t, tc.stdin, args...)
+			assert.Equal(t, tc.wantCode, code)
+			assert.Equal(t, tc.wantRebalanced, svc.rebalanced)
+			if tc.wantCode == ExitOK {
+				assert.Contains(t, stdout, "hot -> cold")
+			}
+		})
+	}
+}
+
+// fakeMetricsService backfill 요청 기간을 기록하는 일별 통계 서비스
+type fakeMetricsService struct {
+	service.MetricsService
+	days []int
+}
+
+func (s *fakeMetricsService) Backfill(_ context.Context, days int) (*service.MetricsBackfillResult, error) {
+	s.days = append(s.days, days)
+	return &service.MetricsBackfillResult{From: "2024-03-07", To: "2024-03-09", Created: []string{"2024-03-07", "2024-03-09"}, Existing: 1}, nil
+}
+
+func TestRun_StatsBackfill(t *testing.T) {
+	metrics := &fakeMetricsService{}
+	h := handler.NewStatsHandler(nil, metrics)
+	e := echo.New()
+	e.POST("/api/v1/admin/stats/backfill", h.Backfill, middleware.AdminAuthMiddleware(testToken))
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	code, stdout, _ := runCLI(t, "", "--addr", server.URL, "--token", testToken, "stats", "backfill", "--days", "3")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "2024-03-09: 저장")
+	assert.Contains(t, stdout, "2024-03-07 ~ 2024-03-09: 2일 저장, 1일은 이미 있음")
+
+	code, stdout, _ = runCLI(t, "", "--addr", server.URL, "--token", testToken, "--json", "stats", "backfill")
+	assert.Equal(t, ExitOK, code)
+	var result service.MetricsBackfillResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.Equal(t, 1, result.Existing)
+	assert.Equal(t, []int{3, 0}, metrics.days, "생략하면 서버 기본 기간")
+
+	code, _, _ = runCLI(t, "", "--addr", server.URL, "--token", testToken, "stats", "backfill", "--days", "-1")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	server := startAdminServer(t, &fakeAdminService{})
+
+	// 연결할 수 없는 서버
+	closed := httptest.NewServer(nil)
+	closedURL := closed.URL
+	closed.Close()
+
+	testCases := []struct {
+		name     string
+		args     []string
+		wantCode int
+	}{
+		{name: "잘못된 토큰", args: []string{"--addr", server.URL, "--token", "wrong", "files", "list"}, wantCode: ExitAuth},
+		{name: "토큰 없음", args: []string{"--addr", server.URL, "files", "list"}, wantCode: ExitAuth},
+		{name: "네트워크 오류", args: []string{"--addr", closedURL, "--token", testToken, "files", "list"}, wantCode: ExitNetwork},
+		{name: "알 수 없는 명령", args: []string{"--addr", server.URL, "--token", testToken, "files", "rename"}, wantCode: ExitUsage},
+		{name: "ID 누락", args: []string{"--addr", server.URL, "--token", testToken, "files", "purge"}, wantCode: ExitUsage},
+		{name: "잘못된 ID", args: []string{"--addr", server.URL, "--token", testToken, "files", "purge", "x", "-y"}, wantCode: ExitUsage},
+		{name: "명령 없음", args: []string{"--addr", server.URL}, wantCode: ExitUsage},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(EnvToken, "")
+			code, _, stderr := runCLI(t, "", tc.args...)
+			assert.Equal(t, tc.wantCode, code, stderr)
+		})
+	}
+}
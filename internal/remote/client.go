@@ -0,0 +1,234 @@
+// Package remote provides a client and command-line front end for the
+// DataLocker admin API, used to manage a running server remotely.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"DataLocker/internal/repository"
+	"DataLocker/internal/service"
+	"DataLocker/pkg/response"
+)
+
+// 관리 API 경로 및 헤더
+const (
+	adminFilesPath           = "/api/v1/admin/files"
+	adminVolumesPath         = "/api/v1/admin/volumes"
+	adminStatsPath           = "/api/v1/admin/stats"
+	headerEncryptionPassword = "X-Encryption-Password" //nolint:gosec // 헤더 이름
+)
+
+// 응답 본문 최대 크기 (목록 응답 기준으로 충분한 크기)
+const maxResponseBytes = 16 << 20
+
+// ErrNetwork 서버에 연결하지 못했거나 응답을 받지 못한 경우
+var ErrNetwork = errors.New("서버와 통신하지 못했습니다")
+
+// APIError 서버가 반환한 에러 응답
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    string
+	RequestID  string
+}
+
+// Error 에러 메시지를 반환합니다
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("서버 오류 (HTTP %d): %s", e.StatusCode, e.Message)
+	if e.Details != "" {
+		msg += ": " + e.Details
+	}
+	if e.RequestID != "" {
+		msg += " [request_id=" + e.RequestID + "]"
+	}
+	return msg
+}
+
+// IsAuthError 인증/권한 오류인지 확인합니다
+func (e *APIError) IsAuthError() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// Client 관리 API 클라이언트
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient 새로운 관리 API 클라이언트를 생성합니다
+//
+// addr는 http(s)://host:port 형식이며, 스킴이 없으면 http로 간주합니다.
+func NewClient(addr, token string, httpClient *http.Client) (*Client, error) {
+	if addr == "" {
+		return nil, errors.New("서버 주소가 필요합니다")
+	}
+
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+
+	parsed, err := url.Parse(addr)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("잘못된 서버 주소입니다: %s", addr)
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(parsed.String(), "/"),
+		token:      token,
+		httpClient: httpClient,
+	}, nil
+}
+
+// ListFiles 파일 목록을 sort 순서로 조회합니다 (빈 값은 서버 기본값)
+func (c *Client) ListFiles(ctx context.Context, page, pageSize int, sort repository.SortOption) (*service.AdminFileList, error) {
+	query := url.Values{}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if pageSize > 0 {
+		query.Set("page_size", strconv.Itoa(pageSize))
+	}
+	if sort.Field != "" {
+		query.Set("sort", sort.Field)
+	}
+	if sort.Direction != "" {
+		query.Set("order", sort.Direction)
+	}
+
+	path := adminFilesPath
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var list service.AdminFileList
+	if err := c.do(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// VerifyFile 파일의 무결성 검사를 요청합니다
+func (c *Client) VerifyFile(ctx context.Context, id uint, password string) (*service.IntegrityResult, error) {
+	header := http.Header{}
+	header.Set(headerEncryptionPassword, password)
+
+	var result service.IntegrityResult
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("%s/%d/verify", adminFilesPath, id), header, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PurgeFile 파일 영구 삭제를 요청합니다
+func (c *Client) PurgeFile(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("%s/%d", adminFilesPath, id), nil, nil)
+}
+
+// VolumeStats 저장소 볼륨별 사용량을 조회합니다
+func (c *Client) VolumeStats(ctx context.Context) ([]service.VolumeStats, error) {
+	var stats []service.VolumeStats
+	if err := c.do(ctx, http.MethodGet, adminVolumesPath, nil, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// RebalanceVolumes 볼륨 재배치를 요청합니다 (maxFiles가 0이면 서버 기본값)
+func (c *Client) RebalanceVolumes(ctx context.Context, dryRun bool, maxFiles int) (*service.RebalanceResult, error) {
+	query := url.Values{}
+	query.Set("dry_run", strconv.FormatBool(dryRun))
+	if maxFiles > 0 {
+		query.Set("max_files", strconv.Itoa(maxFiles))
+	}
+
+	var result service.RebalanceResult
+	if err := c.do(ctx, http.MethodPost, adminVolumesPath+"/rebalance?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// BackfillMetrics 어제까지 최근 days일 중 누락된 일별 통계 스냅샷 저장을 요청합니다 (0이면 서버 기본값)
+func (c *Client) BackfillMetrics(ctx context.Context, days int) (*service.MetricsBackfillResult, error) {
+	path := adminStatsPath + "/backfill"
+	if days > 0 {
+		path += "?days=" + strconv.Itoa(days)
+	}
+
+	var result service.MetricsBackfillResult
+	if err := c.do(ctx, http.MethodPost, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// do 요청을 보내고 표준 응답의 data를 out으로 디코딩합니다
+func (c *Client) do(ctx context.Context, method, path string, header http.Header, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("요청 생성 실패: %w", err)
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNetwork, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("%w: 응답 읽기 실패: %w", ErrNetwork, err)
+	}
+
+	var envelope struct {
+		response.Response
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		}
+		return fmt.Errorf("응답 형식이 올바르지 않습니다: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest || !envelope.Success {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: envelope.Message}
+		if envelope.Error != nil {
+			apiErr.Code = envelope.Error.Code
+			apiErr.Details = envelope.Error.Details
+			apiErr.RequestID = envelope.Error.RequestID
+		}
+		return apiErr
+	}
+
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("응답 데이터 형식이 올바르지 않습니다: %w", err)
+	}
+	return nil
+}
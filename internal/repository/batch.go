@@ -0,0 +1,108 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements helpers shared by the batch create and delete operations.
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"DataLocker/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// createBatchSize 일괄 생성 시 INSERT 한 번에 넣는 행 수
+//
+// 파일 행은 컬럼이 많으므로 SQLite 바인드 변수 상한에 닿지 않도록 작게 유지합니다.
+const createBatchSize = 100
+
+// idBatchSize ID 목록으로 여러 행을 지울 때 IN 절 하나에 넣는 ID 수
+const idBatchSize = 500
+
+// MaxBatchIDs ID 목록으로 한 번에 조회할 수 있는 최대 ID 수 (IN 절 하나로 처리)
+const MaxBatchIDs = idBatchSize
+
+// ErrTooManyIDs 한 번에 조회하려는 ID가 MaxBatchIDs개를 넘는 경우
+var ErrTooManyIDs = errors.New("한 번에 조회할 수 있는 ID 수를 넘었습니다")
+
+// BatchItemError 일괄 생성 중 특정 행이 실패한 경우
+//
+// 일괄 생성은 전부 성공하거나 전부 롤백되므로, Index는 호출자가 넘긴 슬라이스에서
+// 처음 실패한 행의 위치입니다. errors.Is/As로 원인(model.ErrEmptyOriginalName 등)을
+// 확인할 수 있습니다.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+// Error 에러 메시지를 반환합니다
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("%d번째 항목: %v", e.Index, e.Err)
+}
+
+// Unwrap 원인 에러를 반환합니다
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// MissingIDsError 일괄 작업 대상 중 찾을 수 없는 ID가 있는 경우
+//
+// 나머지 ID에 대한 작업은 완료된 상태이며, errors.Is(err, model.ErrRecordNotFound)로
+// 확인할 수 있습니다.
+type MissingIDsError struct {
+	IDs []uint
+}
+
+// Error 에러 메시지를 반환합니다
+func (e *MissingIDsError) Error() string {
+	return fmt.Sprintf("대상을 찾을 수 없는 ID %d개: %v", len(e.IDs), e.IDs)
+}
+
+// Unwrap model.ErrRecordNotFound를 반환합니다
+func (e *MissingIDsError) Unwrap() error {
+	return model.ErrRecordNotFound
+}
+
+// uniqueIDs 중복을 제거한 ID 목록을 반환합니다 (0이 있으면 에러, 순서 유지)
+func uniqueIDs(ids []uint) ([]uint, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("대상 ID가 없습니다")
+	}
+
+	seen := make(map[uint]struct{}, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for i, id := range ids {
+		if id == 0 {
+			return nil, &BatchItemError{Index: i, Err: invalidID("파일")}
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique, nil
+}
+
+// createHook 생성 전 검증 훅(model의 BeforeCreate)이 있는 모델 포인터
+type createHook[T any] interface {
+	*T
+	BeforeCreate(tx *gorm.DB) error
+}
+
+// createInBatches 행마다 생성 훅을 먼저 실행한 뒤 훅 없이 묶어서 INSERT합니다
+//
+// GORM이 슬라이스 생성 중 훅을 실행하면 어느 행이 실패했는지 알 수 없으므로 훅은
+// 직접 실행해 실패한 행을 BatchItemError로 알려줍니다. tx는 트랜잭션이어야 합니다.
+func createInBatches[T any, P createHook[T]](tx *gorm.DB, rows []P) error {
+	for i, row := range rows {
+		if row == nil {
+			return &BatchItemError{Index: i, Err: fmt.Errorf("데이터가 없습니다")}
+		}
+		if err := row.BeforeCreate(tx); err != nil {
+			return &BatchItemError{Index: i, Err: err}
+		}
+	}
+
+	return tx.Session(&gorm.Session{SkipHooks: true}).CreateInBatches(rows, createBatchSize).Error
+}
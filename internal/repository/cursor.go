@@ -0,0 +1,87 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements the opaque cursor used by keyset pagination of file lists.
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// cursorSize 인코딩 전 커서 크기 (생성 시각 UnixNano 8바이트 + ID 8바이트)
+const cursorSize = 16
+
+// ErrInvalidCursor 커서 문자열을 해석할 수 없음 (이 서버가 발급한 커서가 아님)
+var ErrInvalidCursor = errors.New("유효하지 않은 커서입니다")
+
+// Cursor 키셋 페이지네이션 위치 (이전 페이지 마지막 파일의 생성 시각과 ID)
+//
+// 빈 Cursor는 첫 페이지를 뜻합니다. 클라이언트에는 String으로 만든 불투명한
+// 문자열로 전달하고, 돌려받은 문자열은 ParseCursor로 되돌립니다.
+type Cursor struct {
+	createdAt time.Time
+	id        uint
+}
+
+// cursorAfter 파일 다음 위치를 가리키는 커서를 생성합니다
+func cursorAfter(createdAt time.Time, id uint) Cursor {
+	return Cursor{createdAt: createdAt.UTC(), id: id}
+}
+
+// ParseCursor String으로 만든 커서 문자열을 해석합니다 (빈 문자열은 첫 페이지)
+//
+// 형식이 맞지 않으면 ErrInvalidCursor를 감싼 에러를 반환합니다.
+func ParseCursor(value string) (Cursor, error) {
+	if value == "" {
+		return Cursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) != cursorSize {
+		return Cursor{}, fmt.Errorf("%w: %q", ErrInvalidCursor, value)
+	}
+
+	nanos := int64(binary.BigEndian.Uint64(raw[:8]))
+	id := binary.BigEndian.Uint64(raw[8:])
+	if id == 0 || uint64(uint(id)) != id {
+		return Cursor{}, fmt.Errorf("%w: %q", ErrInvalidCursor, value)
+	}
+
+	return cursorAfter(time.Unix(0, nanos), uint(id)), nil
+}
+
+// IsZero 첫 페이지를 가리키는 커서인지 확인합니다
+func (c Cursor) IsZero() bool {
+	return c.id == 0
+}
+
+// String 커서를 URL에 그대로 넣을 수 있는 문자열로 인코딩합니다 (빈 커서는 빈 문자열)
+func (c Cursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+
+	raw := make([]byte, cursorSize)
+	binary.BigEndian.PutUint64(raw[:8], uint64(c.createdAt.UnixNano()))
+	binary.BigEndian.PutUint64(raw[8:], uint64(c.id))
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Follows 최신순 목록에서 c가 prev보다 뒤(더 오래된) 위치인지 확인합니다
+//
+// 빈 커서(첫 페이지)는 어떤 커서보다도 앞입니다. 커서로 목록을 끝까지 읽는
+// 호출자가 페이지마다 커서가 진행하는지 확인해 무한 반복을 막는 데 씁니다.
+func (c Cursor) Follows(prev Cursor) bool {
+	switch {
+	case c.IsZero():
+		return false
+	case prev.IsZero():
+		return true
+	case !c.createdAt.Equal(prev.createdAt):
+		return c.createdAt.Before(prev.createdAt)
+	default:
+		return c.id < prev.id
+	}
+}
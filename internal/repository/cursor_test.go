@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCursor(t *testing.T) {
+	cursor, err := ParseCursor("")
+	require.NoError(t, err)
+	assert.True(t, cursor.IsZero())
+	assert.Empty(t, cursor.String())
+
+	original := cursorAfter(time.Date(2024, 3, 10, 12, 0, 0, 123456789, time.UTC), 42)
+	parsed, err := ParseCursor(original.String())
+	require.NoError(t, err)
+	assert.Equal(t, original, parsed)
+
+	zeroID := base64.RawURLEncoding.EncodeToString(make([]byte, cursorSize))
+	for _, value := range []string{"!!", "YWJj", zeroID, original.String() + "AA"} {
+		_, err := ParseCursor(value)
+		assert.ErrorIs(t, err, ErrInvalidCursor, value)
+	}
+}
+
+func TestCursor_Follows(t *testing.T) {
+	at := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	cursor := cursorAfter(at, 10)
+
+	assert.True(t, cursor.Follows(Cursor{}), "첫 페이지 다음")
+	assert.True(t, cursorAfter(at.Add(-time.Nanosecond), 99).Follows(cursor), "더 오래된 시각")
+	assert.True(t, cursorAfter(at, 9).Follows(cursor), "같은 시각의 더 작은 ID")
+
+	assert.False(t, cursor.Follows(cursor), "같은 위치")
+	assert.False(t, cursorAfter(at, 11).Follows(cursor))
+	assert.False(t, cursorAfter(at.Add(time.Second), 1).Follows(cursor))
+	assert.False(t, Cursor{}.Follows(cursor))
+}
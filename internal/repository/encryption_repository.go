@@ -3,26 +3,35 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 
 	"DataLocker/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// EncryptionRepository 암호화 메타데이터 저장소 인터페이스
+// EncryptionRepository 암호화 메타데이터 저장소 인터페이스 (ctx 취소 시 쿼리 중단)
 type EncryptionRepository interface {
-	Create(metadata *model.EncryptionMetadata) error
-	GetByID(id uint) (*model.EncryptionMetadata, error)
-	GetByFileID(fileID uint) (*model.EncryptionMetadata, error)
-	Update(metadata *model.EncryptionMetadata) error
-	DeleteByID(id uint) error
-	DeleteByFileID(fileID uint) error
-	GetByAlgorithm(algorithm string, offset, limit int) ([]*model.EncryptionMetadata, int64, error)
-	Exists(id uint) (bool, error)
-	ExistsByFileID(fileID uint) (bool, error)
-	Count() (int64, error)
-	CountByAlgorithm(algorithm string) (int64, error)
+	Create(ctx context.Context, metadata *model.EncryptionMetadata) error
+	CreateBatch(ctx context.Context, metadata []*model.EncryptionMetadata) error
+	GetByID(ctx context.Context, id uint) (*model.EncryptionMetadata, error)
+	GetByFileID(ctx context.Context, fileID uint) (*model.EncryptionMetadata, error)
+	ListByFileID(ctx context.Context, fileID uint) ([]*model.EncryptionMetadata, error)
+	Update(ctx context.Context, metadata *model.EncryptionMetadata) error
+	UpsertByFileID(ctx context.Context, metadata *model.EncryptionMetadata) error
+	DeleteByID(ctx context.Context, id uint) error
+	DeleteByFileID(ctx context.Context, fileID uint) error
+	GetByAlgorithm(ctx context.Context, algorithm string, offset, limit int) ([]*model.EncryptionMetadata, int64, error)
+	Exists(ctx context.Context, id uint) (bool, error)
+	ExistsByFileID(ctx context.Context, fileID uint) (bool, error)
+	Count(ctx context.Context) (int64, error)
+	CountByAlgorithm(ctx context.Context, algorithm string) (int64, error)
+	CountByPasswordFingerprint(ctx context.Context, fingerprint string) (int64, error)
+	GetByIterationsBelow(ctx context.Context, threshold, offset, limit int) ([]*model.EncryptionMetadata, int64, error)
+	CountByIterationsBelow(ctx context.Context, threshold int) (int64, error)
+	GetOrphaned(ctx context.Context, offset, limit int) ([]*model.EncryptionMetadata, int64, error)
 }
 
 // encryptionRepository GORM 기반 암호화 메타데이터 저장소 구현체
@@ -31,40 +40,117 @@ type encryptionRepository struct {
 }
 
 // NewEncryptionRepository 새로운 암호화 메타데이터 저장소를 생성합니다
-func NewEncryptionRepository(db *gorm.DB) EncryptionRepository {
+//
+// WithInstrumenter로 훅을 지정하면 모든 메서드의 실행 시간을 보고하는 저장소를 반환합니다.
+func NewEncryptionRepository(db *gorm.DB, opts ...Option) EncryptionRepository {
 	if db == nil {
 		panic("데이터베이스 연결이 필요합니다")
 	}
 
-	return &encryptionRepository{
+	var repo EncryptionRepository = &encryptionRepository{
 		db: db,
 	}
+	if o := applyOptions(opts); o.instrumenter != nil {
+		repo = &instrumentedEncryptionRepository{next: repo, inst: o.instrumenter}
+	}
+	return repo
 }
 
 // Create 새로운 암호화 메타데이터 레코드를 생성합니다
-func (r *encryptionRepository) Create(metadata *model.EncryptionMetadata) error {
+func (r *encryptionRepository) Create(ctx context.Context, metadata *model.EncryptionMetadata) error {
 	if metadata == nil {
 		return fmt.Errorf("암호화 메타데이터가 없습니다")
 	}
 
-	if err := r.db.Create(metadata).Error; err != nil {
-		return fmt.Errorf("암호화 메타데이터 생성 실패: %w", err)
+	if err := r.db.WithContext(ctx).Create(metadata).Error; err != nil {
+		return fmt.Errorf("암호화 메타데이터 생성 실패: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// CreateBatch 여러 암호화 메타데이터 레코드를 한 트랜잭션에서 묶어서 생성합니다
+//
+// 전부 생성되거나 하나도 생성되지 않습니다. 검증에 실패하거나 파일당 메타데이터 수
+// 제한을 넘긴 행이 있으면 *BatchItemError로 위치를 알려줍니다.
+func (r *encryptionRepository) CreateBatch(ctx context.Context, metadata []*model.EncryptionMetadata) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createInBatches(tx, metadata); err != nil {
+			return err
+		}
+		return checkMetadataLimit(tx, metadata)
+	})
+	if err != nil {
+		return fmt.Errorf("암호화 메타데이터 일괄 생성 실패: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// checkMetadataLimit 방금 생성한 행까지 포함해 파일당 메타데이터 수 제한을 확인합니다
+//
+// 행마다 실행한 생성 훅은 기존 행만 세므로 같은 배치 안의 행은 여기서 함께 셉니다.
+// 제한을 넘기게 만든 첫 행을 *BatchItemError로 반환합니다.
+func checkMetadataLimit(tx *gorm.DB, metadata []*model.EncryptionMetadata) error {
+	inBatch := make(map[uint]int64)
+	fileIDs := make([]uint, 0, len(metadata))
+	for _, em := range metadata {
+		if inBatch[em.FileID] == 0 {
+			fileIDs = append(fileIDs, em.FileID)
+		}
+		inBatch[em.FileID]++
+	}
+
+	var counts []struct {
+		FileID uint
+		Count  int64
+	}
+	err := tx.Model(&model.EncryptionMetadata{}).
+		Select("file_id, COUNT(*) AS count").
+		Where("file_id IN ?", fileIDs).
+		Group("file_id").
+		Scan(&counts).Error
+	if err != nil {
+		return fmt.Errorf("메타데이터 수 확인 실패: %w", err)
+	}
+
+	// 파일별로 배치 이전에 있던 행 수부터 배치 순서대로 세어 제한을 넘는 행을 찾음
+	seen := make(map[uint]int64, len(counts))
+	for _, c := range counts {
+		if c.Count > model.MaxMetadataPerFile {
+			seen[c.FileID] = c.Count - inBatch[c.FileID]
+		}
+	}
+	for i, em := range metadata {
+		existing, over := seen[em.FileID]
+		if !over {
+			continue
+		}
+		existing++
+		seen[em.FileID] = existing
+		if existing > model.MaxMetadataPerFile {
+			return &BatchItemError{Index: i, Err: model.ErrTooManyMetadata}
+		}
 	}
 
 	return nil
 }
 
 // GetByID ID로 암호화 메타데이터를 조회합니다
-func (r *encryptionRepository) GetByID(id uint) (*model.EncryptionMetadata, error) {
+func (r *encryptionRepository) GetByID(ctx context.Context, id uint) (*model.EncryptionMetadata, error) {
 	if id == 0 {
-		return nil, fmt.Errorf("유효하지 않은 암호화 메타데이터 ID입니다")
+		return nil, invalidID("암호화 메타데이터")
 	}
 
 	var metadata model.EncryptionMetadata
-	err := r.db.Preload("File").First(&metadata, id).Error
+	err := r.db.WithContext(ctx).Preload("File").First(&metadata, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("암호화 메타데이터를 찾을 수 없습니다: ID %d", id)
+			return nil, fmt.Errorf("암호화 메타데이터를 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
 		}
 		return nil, fmt.Errorf("암호화 메타데이터 조회 실패: %w", err)
 	}
@@ -72,17 +158,22 @@ func (r *encryptionRepository) GetByID(id uint) (*model.EncryptionMetadata, erro
 	return &metadata, nil
 }
 
-// GetByFileID 파일 ID로 암호화 메타데이터를 조회합니다
-func (r *encryptionRepository) GetByFileID(fileID uint) (*model.EncryptionMetadata, error) {
+// GetByFileID 파일 ID로 primary 암호화 메타데이터를 조회합니다
+//
+// 파일에는 version, keyslot 용도의 메타데이터가 함께 있을 수 있으며, 이
+// 메서드는 파일의 현재 암호화 설정(primary)만 반환합니다.
+func (r *encryptionRepository) GetByFileID(ctx context.Context, fileID uint) (*model.EncryptionMetadata, error) {
 	if fileID == 0 {
-		return nil, fmt.Errorf("유효하지 않은 파일 ID입니다")
+		return nil, invalidID("파일")
 	}
 
 	var metadata model.EncryptionMetadata
-	err := r.db.Preload("File").Where("file_id = ?", fileID).First(&metadata).Error
+	err := r.db.WithContext(ctx).Preload("File").
+		Where("file_id = ? AND purpose = ?", fileID, model.MetadataPurposePrimary).
+		First(&metadata).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("파일 ID %d에 대한 암호화 메타데이터를 찾을 수 없습니다", fileID)
+			return nil, fmt.Errorf("파일 ID %d에 대한 암호화 메타데이터를 찾을 수 없습니다: %w", fileID, model.ErrRecordNotFound)
 		}
 		return nil, fmt.Errorf("암호화 메타데이터 조회 실패: %w", err)
 	}
@@ -90,76 +181,121 @@ func (r *encryptionRepository) GetByFileID(fileID uint) (*model.EncryptionMetada
 	return &metadata, nil
 }
 
+// ListByFileID 파일의 모든 용도의 암호화 메타데이터를 용도, 슬롯 순으로 조회합니다
+func (r *encryptionRepository) ListByFileID(ctx context.Context, fileID uint) ([]*model.EncryptionMetadata, error) {
+	if fileID == 0 {
+		return nil, invalidID("파일")
+	}
+
+	var metadataList []*model.EncryptionMetadata
+	err := r.db.WithContext(ctx).Where("file_id = ?", fileID).
+		Order("purpose ASC").
+		Order("slot ASC").
+		Find(&metadataList).Error
+	if err != nil {
+		return nil, fmt.Errorf("파일별 암호화 메타데이터 목록 조회 실패: %w", err)
+	}
+
+	return metadataList, nil
+}
+
 // Update 암호화 메타데이터를 업데이트합니다
-func (r *encryptionRepository) Update(metadata *model.EncryptionMetadata) error {
+func (r *encryptionRepository) Update(ctx context.Context, metadata *model.EncryptionMetadata) error {
 	if metadata == nil {
 		return fmt.Errorf("암호화 메타데이터가 없습니다")
 	}
 
 	if metadata.ID == 0 {
-		return fmt.Errorf("유효하지 않은 암호화 메타데이터 ID입니다")
+		return invalidID("암호화 메타데이터")
 	}
 
 	// 메타데이터 존재 여부 확인
-	exists, err := r.Exists(metadata.ID)
+	exists, err := r.Exists(ctx, metadata.ID)
 	if err != nil {
 		return fmt.Errorf("암호화 메타데이터 존재 확인 실패: %w", err)
 	}
 
 	if !exists {
-		return fmt.Errorf("업데이트할 암호화 메타데이터를 찾을 수 없습니다: ID %d", metadata.ID)
+		return fmt.Errorf("업데이트할 암호화 메타데이터를 찾을 수 없습니다: ID %d: %w", metadata.ID, model.ErrRecordNotFound)
 	}
 
 	// 업데이트 실행
-	if err := r.db.Save(metadata).Error; err != nil {
-		return fmt.Errorf("암호화 메타데이터 업데이트 실패: %w", err)
+	if err := r.db.WithContext(ctx).Save(metadata).Error; err != nil {
+		return fmt.Errorf("암호화 메타데이터 업데이트 실패: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// UpsertByFileID 파일의 같은 용도, 슬롯 메타데이터가 있으면 암호화 설정을 교체하고 없으면 생성합니다
+//
+// 암호화를 다시 시도할 때 기존 행을 조회해 수정하지 않고 한 문장으로 교체합니다.
+// 생성과 같은 검증 훅을 거치며, 성공하면 metadata.ID는 저장된 행의 ID입니다.
+func (r *encryptionRepository) UpsertByFileID(ctx context.Context, metadata *model.EncryptionMetadata) error {
+	if metadata == nil {
+		return fmt.Errorf("암호화 메타데이터가 없습니다")
+	}
+
+	if metadata.FileID == 0 {
+		return invalidID("파일")
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "file_id"}, {Name: "purpose"}, {Name: "slot"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at", "algorithm", "key_derivation", "salt_hex", "nonce_hex", "iterations",
+			"format_version", "compression", "password_fingerprint",
+		}),
+	}).Create(metadata).Error
+	if err != nil {
+		return fmt.Errorf("암호화 메타데이터 upsert 실패: 파일 ID %d: %w", metadata.FileID, translateError(err))
 	}
 
 	return nil
 }
 
 // DeleteByID ID로 암호화 메타데이터를 삭제합니다
-func (r *encryptionRepository) DeleteByID(id uint) error {
+func (r *encryptionRepository) DeleteByID(ctx context.Context, id uint) error {
 	if id == 0 {
-		return fmt.Errorf("유효하지 않은 암호화 메타데이터 ID입니다")
+		return invalidID("암호화 메타데이터")
 	}
 
 	// 메타데이터 존재 여부 확인
-	exists, err := r.Exists(id)
+	exists, err := r.Exists(ctx, id)
 	if err != nil {
 		return fmt.Errorf("암호화 메타데이터 존재 확인 실패: %w", err)
 	}
 
 	if !exists {
-		return fmt.Errorf("삭제할 암호화 메타데이터를 찾을 수 없습니다: ID %d", id)
+		return fmt.Errorf("삭제할 암호화 메타데이터를 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
 	}
 
 	// 삭제 실행 (하드 삭제 - 암호화 메타데이터는 보안상 완전 삭제)
-	if err := r.db.Unscoped().Delete(&model.EncryptionMetadata{}, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Unscoped().Delete(&model.EncryptionMetadata{}, id).Error; err != nil {
 		return fmt.Errorf("암호화 메타데이터 삭제 실패: %w", err)
 	}
 
 	return nil
 }
 
-// DeleteByFileID 파일 ID로 암호화 메타데이터를 삭제합니다
-func (r *encryptionRepository) DeleteByFileID(fileID uint) error {
+// DeleteByFileID 파일 ID로 모든 용도의 암호화 메타데이터를 삭제합니다
+func (r *encryptionRepository) DeleteByFileID(ctx context.Context, fileID uint) error {
 	if fileID == 0 {
-		return fmt.Errorf("유효하지 않은 파일 ID입니다")
+		return invalidID("파일")
 	}
 
 	// 메타데이터 존재 여부 확인
-	exists, err := r.ExistsByFileID(fileID)
+	exists, err := r.ExistsByFileID(ctx, fileID)
 	if err != nil {
 		return fmt.Errorf("암호화 메타데이터 존재 확인 실패: %w", err)
 	}
 
 	if !exists {
-		return fmt.Errorf("파일 ID %d에 대한 암호화 메타데이터를 찾을 수 없습니다", fileID)
+		return fmt.Errorf("파일 ID %d에 대한 암호화 메타데이터를 찾을 수 없습니다: %w", fileID, model.ErrRecordNotFound)
 	}
 
 	// 삭제 실행 (하드 삭제)
-	if err := r.db.Unscoped().Where("file_id = ?", fileID).Delete(&model.EncryptionMetadata{}).Error; err != nil {
+	if err := r.db.WithContext(ctx).Unscoped().Where("file_id = ?", fileID).Delete(&model.EncryptionMetadata{}).Error; err != nil {
 		return fmt.Errorf("암호화 메타데이터 삭제 실패: %w", err)
 	}
 
@@ -167,7 +303,7 @@ func (r *encryptionRepository) DeleteByFileID(fileID uint) error {
 }
 
 // GetByAlgorithm 암호화 알고리즘별로 메타데이터를 조회합니다
-func (r *encryptionRepository) GetByAlgorithm(algorithm string, offset, limit int) ([]*model.EncryptionMetadata, int64, error) {
+func (r *encryptionRepository) GetByAlgorithm(ctx context.Context, algorithm string, offset, limit int) ([]*model.EncryptionMetadata, int64, error) {
 	if algorithm == "" {
 		return nil, 0, fmt.Errorf("암호화 알고리즘이 필요합니다")
 	}
@@ -182,12 +318,12 @@ func (r *encryptionRepository) GetByAlgorithm(algorithm string, offset, limit in
 	var total int64
 
 	// 알고리즘별 카운트 조회
-	if err := r.db.Model(&model.EncryptionMetadata{}).Where("algorithm = ?", algorithm).Count(&total).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&model.EncryptionMetadata{}).Where("algorithm = ?", algorithm).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("알고리즘별 암호화 메타데이터 카운트 조회 실패: %w", err)
 	}
 
 	// 알고리즘별 메타데이터 목록 조회
-	err := r.db.Preload("File").
+	err := r.db.WithContext(ctx).Preload("File").
 		Where("algorithm = ?", algorithm).
 		Offset(offset).
 		Limit(limit).
@@ -201,13 +337,13 @@ func (r *encryptionRepository) GetByAlgorithm(algorithm string, offset, limit in
 }
 
 // Exists 암호화 메타데이터 존재 여부를 확인합니다
-func (r *encryptionRepository) Exists(id uint) (bool, error) {
+func (r *encryptionRepository) Exists(ctx context.Context, id uint) (bool, error) {
 	if id == 0 {
-		return false, fmt.Errorf("유효하지 않은 암호화 메타데이터 ID입니다")
+		return false, invalidID("암호화 메타데이터")
 	}
 
 	var count int64
-	err := r.db.Model(&model.EncryptionMetadata{}).Where("id = ?", id).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.EncryptionMetadata{}).Where("id = ?", id).Count(&count).Error
 	if err != nil {
 		return false, fmt.Errorf("암호화 메타데이터 존재 확인 실패: %w", err)
 	}
@@ -216,13 +352,13 @@ func (r *encryptionRepository) Exists(id uint) (bool, error) {
 }
 
 // ExistsByFileID 파일 ID로 암호화 메타데이터 존재 여부를 확인합니다
-func (r *encryptionRepository) ExistsByFileID(fileID uint) (bool, error) {
+func (r *encryptionRepository) ExistsByFileID(ctx context.Context, fileID uint) (bool, error) {
 	if fileID == 0 {
-		return false, fmt.Errorf("유효하지 않은 파일 ID입니다")
+		return false, invalidID("파일")
 	}
 
 	var count int64
-	err := r.db.Model(&model.EncryptionMetadata{}).Where("file_id = ?", fileID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.EncryptionMetadata{}).Where("file_id = ?", fileID).Count(&count).Error
 	if err != nil {
 		return false, fmt.Errorf("암호화 메타데이터 존재 확인 실패: %w", err)
 	}
@@ -231,9 +367,9 @@ func (r *encryptionRepository) ExistsByFileID(fileID uint) (bool, error) {
 }
 
 // Count 전체 암호화 메타데이터 수를 반환합니다
-func (r *encryptionRepository) Count() (int64, error) {
+func (r *encryptionRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.Model(&model.EncryptionMetadata{}).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.EncryptionMetadata{}).Count(&count).Error
 	if err != nil {
 		return 0, fmt.Errorf("암호화 메타데이터 카운트 조회 실패: %w", err)
 	}
@@ -242,7 +378,7 @@ func (r *encryptionRepository) Count() (int64, error) {
 }
 
 // CountByAlgorithm 알고리즘별 암호화 메타데이터 수를 반환합니다
-func (r *encryptionRepository) CountByAlgorithm(algorithm string) (int64, error) {
+func (r *encryptionRepository) CountByAlgorithm(ctx context.Context, algorithm string) (int64, error) {
 	if algorithm == "" {
 		return 0, fmt.Errorf("암호화 알고리즘이 필요합니다")
 	}
@@ -252,7 +388,7 @@ func (r *encryptionRepository) CountByAlgorithm(algorithm string) (int64, error)
 	}
 
 	var count int64
-	err := r.db.Model(&model.EncryptionMetadata{}).Where("algorithm = ?", algorithm).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.EncryptionMetadata{}).Where("algorithm = ?", algorithm).Count(&count).Error
 	if err != nil {
 		return 0, fmt.Errorf("알고리즘별 암호화 메타데이터 카운트 조회 실패: %w", err)
 	}
@@ -260,6 +396,109 @@ func (r *encryptionRepository) CountByAlgorithm(algorithm string) (int64, error)
 	return count, nil
 }
 
+// CountByPasswordFingerprint 같은 패스워드 지문을 가진 암호화 메타데이터 수를 반환합니다
+func (r *encryptionRepository) CountByPasswordFingerprint(ctx context.Context, fingerprint string) (int64, error) {
+	if fingerprint == "" {
+		return 0, fmt.Errorf("패스워드 지문이 필요합니다")
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.EncryptionMetadata{}).Where("password_fingerprint = ?", fingerprint).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("패스워드 지문별 암호화 메타데이터 카운트 조회 실패: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetByIterationsBelow 반복 횟수가 threshold 미만인 메타데이터를 ID순으로 페이지네이션 조회합니다
+//
+// 기본 반복 횟수를 올린 뒤 다시 암호화할 대상을 찾는 용도입니다. 다시 암호화한
+// 메타데이터는 결과에서 빠지므로, 처리하면서 갱신하는 호출자는 offset 0부터 반복해
+// 조회해야 건너뛰는 레코드가 없습니다. threshold는 model.MinIterations 이상
+// model.MaxIterations 이하여야 합니다.
+func (r *encryptionRepository) GetByIterationsBelow(ctx context.Context, threshold, offset, limit int) ([]*model.EncryptionMetadata, int64, error) {
+	if err := validateIterationsThreshold(threshold); err != nil {
+		return nil, 0, err
+	}
+
+	offset, limit = r.normalizePagination(offset, limit)
+
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.EncryptionMetadata{}).Where("iterations < ?", threshold).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("반복 횟수별 암호화 메타데이터 카운트 조회 실패: %w", err)
+	}
+
+	var metadataList []*model.EncryptionMetadata
+	err := r.db.WithContext(ctx).Preload("File").
+		Where("iterations < ?", threshold).
+		Order("id").
+		Offset(offset).
+		Limit(limit).
+		Find(&metadataList).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("반복 횟수별 암호화 메타데이터 목록 조회 실패: %w", err)
+	}
+
+	return metadataList, total, nil
+}
+
+// CountByIterationsBelow 반복 횟수가 threshold 미만인 메타데이터 수를 반환합니다
+func (r *encryptionRepository) CountByIterationsBelow(ctx context.Context, threshold int) (int64, error) {
+	if err := validateIterationsThreshold(threshold); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.EncryptionMetadata{}).Where("iterations < ?", threshold).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("반복 횟수별 암호화 메타데이터 카운트 조회 실패: %w", err)
+	}
+
+	return count, nil
+}
+
+// validateIterationsThreshold 반복 횟수 기준값이 저장 가능한 범위인지 확인합니다
+func validateIterationsThreshold(threshold int) error {
+	if threshold < model.MinIterations || threshold > model.MaxIterations {
+		return fmt.Errorf("%w: 기준값 %d", model.ErrInvalidIterations, threshold)
+	}
+	return nil
+}
+
+// GetOrphaned 살아 있는 파일이 없는 암호화 메타데이터를 ID순으로 페이지네이션 조회합니다
+//
+// 파일은 소프트 삭제하고 메타데이터는 하드 삭제하므로, 파일만 삭제되면 메타데이터가
+// 남습니다. 소프트 삭제된 원본이라도 살아 있는 blob 참조 레코드가 있으면 그 참조가
+// 메타데이터를 사용하므로 고아로 보지 않습니다.
+func (r *encryptionRepository) GetOrphaned(ctx context.Context, offset, limit int) ([]*model.EncryptionMetadata, int64, error) {
+	offset, limit = r.normalizePagination(offset, limit)
+
+	var total int64
+	if err := r.orphaned(r.db.WithContext(ctx).Model(&model.EncryptionMetadata{})).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("고아 암호화 메타데이터 카운트 조회 실패: %w", err)
+	}
+
+	var metadataList []*model.EncryptionMetadata
+	err := r.orphaned(r.db.WithContext(ctx)).Select("encryption_metadata.*").
+		Order("encryption_metadata.id").
+		Offset(offset).
+		Limit(limit).
+		Find(&metadataList).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("고아 암호화 메타데이터 목록 조회 실패: %w", err)
+	}
+
+	return metadataList, total, nil
+}
+
+// orphaned 살아 있는 파일과 LEFT JOIN해 짝이 없는 메타데이터만 남깁니다
+func (r *encryptionRepository) orphaned(query *gorm.DB) *gorm.DB {
+	return query.Joins("LEFT JOIN files ON files.id = encryption_metadata.file_id AND files.deleted_at IS NULL").
+		Where("files.id IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM files refs WHERE refs.blob_file_id = encryption_metadata.file_id AND refs.deleted_at IS NULL)")
+}
+
 // normalizePagination 페이지네이션 파라미터를 정규화합니다
 func (r *encryptionRepository) normalizePagination(offset, limit int) (int, int) {
 	if offset < MinOffset {
@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"DataLocker/internal/database"
 	"DataLocker/internal/model"
 
 	"github.com/stretchr/testify/assert"
@@ -29,11 +32,12 @@ func setupEncTestDB(t *testing.T) (*gorm.DB, func()) {
 
 	dbPath := filepath.Join("./testdata", "test_enc_"+t.Name()+".db")
 	db, err := gorm.Open(sqlite.Open(dbPath+"?_foreign_keys=ON"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
 	})
 	require.NoError(t, err)
 
-	err = model.Migrate(db)
+	_, err = model.MigrateUp(db)
 	require.NoError(t, err)
 
 	cleanup := func() {
@@ -88,6 +92,7 @@ func TestNewEncryptionRepository(t *testing.T) {
 }
 
 func TestEncryptionRepository_CRUD_Success(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupEncTestDB(t)
 	defer cleanup()
 
@@ -96,67 +101,71 @@ func TestEncryptionRepository_CRUD_Success(t *testing.T) {
 	metadata := createTestEncryptionMetadata(file.ID)
 
 	// Create
-	err := repo.Create(metadata)
+	err := repo.Create(ctx, metadata)
 	require.NoError(t, err)
 	assert.NotZero(t, metadata.ID)
 
 	// Read by ID
-	retrieved, err := repo.GetByID(metadata.ID)
+	retrieved, err := repo.GetByID(ctx, metadata.ID)
 	require.NoError(t, err)
 	assert.Equal(t, metadata.FileID, retrieved.FileID)
 	assert.Equal(t, metadata.Algorithm, retrieved.Algorithm)
 
 	// Read by FileID
-	retrievedByFile, err := repo.GetByFileID(file.ID)
+	retrievedByFile, err := repo.GetByFileID(ctx, file.ID)
 	require.NoError(t, err)
 	assert.Equal(t, metadata.ID, retrievedByFile.ID)
 
 	// Update
 	metadata.Iterations = 150000
-	err = repo.Update(metadata)
+	err = repo.Update(ctx, metadata)
 	require.NoError(t, err)
 
-	updated, err := repo.GetByID(metadata.ID)
+	updated, err := repo.GetByID(ctx, metadata.ID)
 	require.NoError(t, err)
 	assert.Equal(t, 150000, updated.Iterations)
 
 	// Delete
-	err = repo.DeleteByID(metadata.ID)
+	err = repo.DeleteByID(ctx, metadata.ID)
 	require.NoError(t, err)
 
-	_, err = repo.GetByID(metadata.ID)
+	_, err = repo.GetByID(ctx, metadata.ID)
 	assert.Error(t, err)
 }
 
 func TestEncryptionRepository_ErrorCases(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupEncTestDB(t)
 	defer cleanup()
 
 	repo := NewEncryptionRepository(db)
 
 	// Create with nil
-	err := repo.Create(nil)
+	err := repo.Create(ctx, nil)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "암호화 메타데이터가 없습니다")
 
 	// Get with invalid ID
-	_, err = repo.GetByID(0)
+	_, err = repo.GetByID(ctx, 0)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "유효하지 않은")
 
 	// Get with non-existent ID
-	_, err = repo.GetByID(999999)
+	_, err = repo.GetByID(ctx, 999999)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "찾을 수 없습니다")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
 
 	// Foreign key constraint
 	metadata := createTestEncryptionMetadata(999999) // non-existent file ID
-	err = repo.Create(metadata)
+	err = repo.Create(ctx, metadata)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "FOREIGN KEY constraint failed")
+	assert.ErrorIs(t, err, ErrConstraintViolation)
 }
 
 func TestEncryptionRepository_GetByAlgorithm(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupEncTestDB(t)
 	defer cleanup()
 
@@ -166,25 +175,89 @@ func TestEncryptionRepository_GetByAlgorithm(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		file := createTestFileForEncryption(t, db, fmt.Sprintf("_algo_%d", i))
 		metadata := createTestEncryptionMetadata(file.ID)
-		err := repo.Create(metadata)
+		err := repo.Create(ctx, metadata)
 		require.NoError(t, err)
 	}
 
 	// Get by algorithm
-	results, total, err := repo.GetByAlgorithm(model.EncryptionAlgorithmAES256GCM, 0, 10)
+	results, total, err := repo.GetByAlgorithm(ctx, model.EncryptionAlgorithmAES256GCM, 0, 10)
 	require.NoError(t, err)
 	assert.Len(t, results, 3)
 	assert.Equal(t, int64(3), total)
 
 	// Error cases
-	_, _, err = repo.GetByAlgorithm("", 0, 10)
+	_, _, err = repo.GetByAlgorithm(ctx, "", 0, 10)
 	assert.Error(t, err)
 
-	_, _, err = repo.GetByAlgorithm("INVALID", 0, 10)
+	_, _, err = repo.GetByAlgorithm(ctx, "INVALID", 0, 10)
 	assert.Error(t, err)
 }
 
+func TestEncryptionRepository_IterationsBelow(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewEncryptionRepository(db)
+
+	// 100k 3개, 200k 2개
+	var weak []uint
+	for i, iterations := range []int{100000, 200000, 100000, 200000, 100000} {
+		file := createTestFileForEncryption(t, db, fmt.Sprintf("_iter_%d", i))
+		metadata := createTestEncryptionMetadata(file.ID)
+		metadata.Iterations = iterations
+		require.NoError(t, repo.Create(ctx, metadata))
+		if iterations == 100000 {
+			weak = append(weak, metadata.ID)
+		}
+	}
+
+	count, err := repo.CountByIterationsBelow(ctx, 150000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// 페이지를 이어 붙이면 기준 미만만 ID순으로 빠짐없이 나옴
+	var ids []uint
+	for offset := 0; ; offset += 2 {
+		page, total, err := repo.GetByIterationsBelow(ctx, 150000, offset, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		if len(page) == 0 {
+			break
+		}
+		for _, metadata := range page {
+			assert.Equal(t, 100000, metadata.Iterations)
+			require.NotNil(t, metadata.File)
+			ids = append(ids, metadata.ID)
+		}
+	}
+	assert.Equal(t, weak, ids)
+
+	// 기준값은 미만 비교 (같은 값은 제외)
+	count, err = repo.CountByIterationsBelow(ctx, 200000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	count, err = repo.CountByIterationsBelow(ctx, model.MaxIterations)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+
+	count, err = repo.CountByIterationsBelow(ctx, model.MinIterations)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	// 저장할 수 없는 범위의 기준값은 거부
+	for _, threshold := range []int{0, model.MinIterations - 1, model.MaxIterations + 1} {
+		_, err = repo.CountByIterationsBelow(ctx, threshold)
+		assert.ErrorIs(t, err, model.ErrInvalidIterations)
+
+		_, _, err = repo.GetByIterationsBelow(ctx, threshold, 0, 10)
+		assert.ErrorIs(t, err, model.ErrInvalidIterations)
+	}
+}
+
 func TestEncryptionRepository_Exists(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupEncTestDB(t)
 	defer cleanup()
 
@@ -193,31 +266,32 @@ func TestEncryptionRepository_Exists(t *testing.T) {
 	metadata := createTestEncryptionMetadata(file.ID)
 
 	// Before create
-	exists, err := repo.Exists(1)
+	exists, err := repo.Exists(ctx, 1)
 	require.NoError(t, err)
 	assert.False(t, exists)
 
 	// After create
-	err = repo.Create(metadata)
+	err = repo.Create(ctx, metadata)
 	require.NoError(t, err)
 
-	exists, err = repo.Exists(metadata.ID)
+	exists, err = repo.Exists(ctx, metadata.ID)
 	require.NoError(t, err)
 	assert.True(t, exists)
 
-	exists, err = repo.ExistsByFileID(file.ID)
+	exists, err = repo.ExistsByFileID(ctx, file.ID)
 	require.NoError(t, err)
 	assert.True(t, exists)
 }
 
 func TestEncryptionRepository_Count(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupEncTestDB(t)
 	defer cleanup()
 
 	repo := NewEncryptionRepository(db)
 
 	// Initial count
-	count, err := repo.Count()
+	count, err := repo.Count(ctx)
 	require.NoError(t, err)
 	assert.Zero(t, count)
 
@@ -225,21 +299,48 @@ func TestEncryptionRepository_Count(t *testing.T) {
 	for i := 0; i < 5; i++ {
 		file := createTestFileForEncryption(t, db, fmt.Sprintf("_count_%d", i))
 		metadata := createTestEncryptionMetadata(file.ID)
-		createErr := repo.Create(metadata)
+		createErr := repo.Create(ctx, metadata)
 		require.NoError(t, createErr)
 	}
 
-	count, err = repo.Count()
+	count, err = repo.Count(ctx)
 	require.NoError(t, err)
 	assert.Equal(t, int64(5), count)
 
 	// Count by algorithm
-	algoCount, algoErr := repo.CountByAlgorithm(model.EncryptionAlgorithmAES256GCM)
+	algoCount, algoErr := repo.CountByAlgorithm(ctx, model.EncryptionAlgorithmAES256GCM)
 	require.NoError(t, algoErr)
 	assert.Equal(t, int64(5), algoCount)
 }
 
+func TestEncryptionRepository_CountByPasswordFingerprint(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewEncryptionRepository(db)
+	fingerprint := strings.Repeat("ab", model.PasswordFingerprintHexLength/2)
+
+	// 지문이 있는 메타데이터 2개, 없는 메타데이터 1개
+	for i := 0; i < 3; i++ {
+		file := createTestFileForEncryption(t, db, fmt.Sprintf("_fingerprint_%d", i))
+		metadata := createTestEncryptionMetadata(file.ID)
+		if i < 2 {
+			metadata.PasswordFingerprint = fingerprint
+		}
+		require.NoError(t, repo.Create(ctx, metadata))
+	}
+
+	count, err := repo.CountByPasswordFingerprint(ctx, fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = repo.CountByPasswordFingerprint(ctx, "")
+	assert.Error(t, err)
+}
+
 func TestEncryptionRepository_ForeignKeyAndUnique(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupEncTestDB(t)
 	defer cleanup()
 
@@ -248,14 +349,15 @@ func TestEncryptionRepository_ForeignKeyAndUnique(t *testing.T) {
 	metadata := createTestEncryptionMetadata(file.ID)
 
 	// First creation should succeed
-	err := repo.Create(metadata)
+	err := repo.Create(ctx, metadata)
 	require.NoError(t, err)
 
 	// Duplicate file_id should fail (unique constraint)
 	metadata2 := createTestEncryptionMetadata(file.ID)
-	err = repo.Create(metadata2)
+	err = repo.Create(ctx, metadata2)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "UNIQUE constraint failed")
+	assert.ErrorIs(t, err, ErrDuplicate)
 
 	// Cascade delete test - 외래키 제약조건 재확인
 	err = db.Exec("PRAGMA foreign_keys = ON").Error
@@ -284,12 +386,256 @@ func TestEncryptionRepository_ForeignKeyAndUnique(t *testing.T) {
 	assert.Zero(t, count, "EncryptionMetadata should be deleted (either by CASCADE or manually)")
 }
 
+func TestEncryptionRepository_MultiplePurposes(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewEncryptionRepository(db)
+	file := createTestFileForEncryption(t, db, "_purposes")
+
+	// version이 먼저 저장되어도 GetByFileID는 primary를 반환해야 함
+	version1 := createTestEncryptionMetadata(file.ID)
+	version1.Purpose = model.MetadataPurposeVersion
+	version1.Slot = 1
+	require.NoError(t, repo.Create(ctx, version1))
+
+	version0 := createTestEncryptionMetadata(file.ID)
+	version0.Purpose = model.MetadataPurposeVersion
+	require.NoError(t, repo.Create(ctx, version0))
+
+	_, err := repo.GetByFileID(ctx, file.ID)
+	assert.Error(t, err, "primary가 없으면 조회되지 않아야 함")
+
+	primary := createTestEncryptionMetadata(file.ID)
+	require.NoError(t, repo.Create(ctx, primary))
+
+	found, err := repo.GetByFileID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, primary.ID, found.ID)
+	assert.True(t, found.IsPrimary())
+
+	list, err := repo.ListByFileID(ctx, file.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, primary.ID, list[0].ID)
+	assert.Equal(t, version0.ID, list[1].ID)
+	assert.Equal(t, version1.ID, list[2].ID)
+
+	_, err = repo.ListByFileID(ctx, 0)
+	assert.Error(t, err)
+
+	// DeleteByFileID는 모든 용도를 삭제함
+	require.NoError(t, repo.DeleteByFileID(ctx, file.ID))
+	list, err = repo.ListByFileID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestEncryptionRepository_UpsertByFileID(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewEncryptionRepository(db)
+	file := createTestFileForEncryption(t, db, "_upsert")
+
+	first := createTestEncryptionMetadata(file.ID)
+	require.NoError(t, repo.UpsertByFileID(ctx, first))
+	require.NotZero(t, first.ID)
+
+	// 재시도는 에러 없이 기존 행의 암호화 설정을 교체
+	retry := createTestEncryptionMetadata(file.ID)
+	retry.SaltHex = strings.Repeat("ab", 32)
+	retry.NonceHex = strings.Repeat("cd", 12)
+	retry.Iterations = 200000
+	require.NoError(t, repo.UpsertByFileID(ctx, retry))
+	assert.Equal(t, first.ID, retry.ID)
+
+	found, err := repo.GetByFileID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+	assert.Equal(t, retry.SaltHex, found.SaltHex)
+	assert.Equal(t, retry.NonceHex, found.NonceHex)
+	assert.Equal(t, 200000, found.Iterations)
+
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// 다른 용도는 별도 행
+	version := createTestEncryptionMetadata(file.ID)
+	version.Purpose = model.MetadataPurposeVersion
+	require.NoError(t, repo.UpsertByFileID(ctx, version))
+	assert.NotEqual(t, first.ID, version.ID)
+
+	// 검증 훅은 그대로 적용되어 기존 행을 바꾸지 않음
+	invalid := createTestEncryptionMetadata(file.ID)
+	invalid.SaltHex = "not-hex"
+	assert.Error(t, repo.UpsertByFileID(ctx, invalid))
+	found, err = repo.GetByFileID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, retry.SaltHex, found.SaltHex)
+
+	assert.ErrorIs(t, repo.UpsertByFileID(ctx, &model.EncryptionMetadata{}), ErrInvalidID)
+	assert.Error(t, repo.UpsertByFileID(ctx, nil))
+}
+
+func TestEncryptionRepository_UpsertByFileID_AtLimit(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewEncryptionRepository(db)
+	file := createTestFileForEncryption(t, db, "_upsert_limit")
+	require.NoError(t, repo.Create(ctx, createTestEncryptionMetadata(file.ID)))
+	for slot := 0; slot < model.MaxMetadataPerFile-1; slot++ {
+		version := createTestEncryptionMetadata(file.ID)
+		version.Purpose = model.MetadataPurposeVersion
+		version.Slot = slot
+		require.NoError(t, repo.Create(ctx, version))
+	}
+
+	// 한도에 찬 파일도 기존 행 교체는 허용하고 새 행은 거부
+	retry := createTestEncryptionMetadata(file.ID)
+	retry.Iterations = 200000
+	require.NoError(t, repo.UpsertByFileID(ctx, retry))
+
+	extra := createTestEncryptionMetadata(file.ID)
+	extra.Purpose = model.MetadataPurposeKeySlot
+	assert.ErrorIs(t, repo.UpsertByFileID(ctx, extra), model.ErrTooManyMetadata)
+}
+
+func TestEncryptionRepository_GetOrphaned(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewEncryptionRepository(db)
+	files := NewFileRepository(db)
+	withMetadata := func(suffix string) (*model.File, *model.EncryptionMetadata) {
+		file := createTestFileForEncryption(t, db, suffix)
+		metadata := createTestEncryptionMetadata(file.ID)
+		require.NoError(t, repo.Create(ctx, metadata))
+		return file, metadata
+	}
+
+	// 살아 있는 파일의 메타데이터는 제외
+	withMetadata("_live")
+
+	// 파일만 소프트 삭제되어 남은 메타데이터 (모든 용도 포함)
+	deleted, orphan := withMetadata("_deleted")
+	version := createTestEncryptionMetadata(deleted.ID)
+	version.Purpose = model.MetadataPurposeVersion
+	require.NoError(t, repo.Create(ctx, version))
+	require.NoError(t, files.Delete(ctx, deleted.ID))
+
+	// 삭제된 원본이라도 살아 있는 참조가 쓰는 메타데이터는 제외
+	source, _ := withMetadata("_source")
+	ref := createTestFileForEncryption(t, db, "_ref")
+	ref.BlobFileID = &source.ID
+	require.NoError(t, db.Save(ref).Error)
+	require.NoError(t, files.Delete(ctx, source.ID))
+
+	list, total, err := repo.GetOrphaned(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, list, 2)
+	assert.Equal(t, orphan.ID, list[0].ID)
+	assert.Equal(t, version.ID, list[1].ID)
+
+	// 참조까지 삭제되면 원본의 메타데이터도 고아
+	require.NoError(t, files.Delete(ctx, ref.ID))
+	list, total, err = repo.GetOrphaned(ctx, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, list, 1)
+	assert.Equal(t, source.ID, list[0].FileID)
+
+	// 복구하면 더 이상 고아가 아님
+	require.NoError(t, files.Restore(ctx, deleted.ID))
+	_, total, err = repo.GetOrphaned(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestEncryptionRepository_CreateBatch(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewEncryptionRepository(db)
+
+	t.Run("전체 생성", func(t *testing.T) {
+		metadata := make([]*model.EncryptionMetadata, 0, 10)
+		for i := range 10 {
+			file := createTestFileForEncryption(t, db, fmt.Sprintf("_batch_%d", i))
+			metadata = append(metadata, createTestEncryptionMetadata(file.ID))
+		}
+
+		require.NoError(t, repo.CreateBatch(ctx, metadata))
+
+		for _, em := range metadata {
+			assert.NotZero(t, em.ID)
+			assert.Equal(t, model.MetadataPurposePrimary, em.Purpose, "생성 훅의 기본값 적용")
+		}
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), count)
+	})
+
+	t.Run("검증 실패 행 위치 보고", func(t *testing.T) {
+		before, err := repo.Count(ctx)
+		require.NoError(t, err)
+
+		file := createTestFileForEncryption(t, db, "_batch_invalid")
+		bad := createTestEncryptionMetadata(file.ID)
+		bad.SaltHex = "zz"
+		metadata := []*model.EncryptionMetadata{createTestEncryptionMetadata(file.ID), bad}
+
+		err = repo.CreateBatch(ctx, metadata)
+		var itemErr *BatchItemError
+		require.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, 1, itemErr.Index)
+
+		after, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("배치 안에서 파일당 제한 초과", func(t *testing.T) {
+		file := createTestFileForEncryption(t, db, "_batch_limit")
+		require.NoError(t, repo.Create(ctx, createTestEncryptionMetadata(file.ID)))
+
+		// 기존 1개 + 배치 16개 중 마지막 행이 제한을 넘김
+		metadata := make([]*model.EncryptionMetadata, 0, model.MaxMetadataPerFile)
+		for slot := 1; slot <= model.MaxMetadataPerFile; slot++ {
+			em := createTestEncryptionMetadata(file.ID)
+			em.Purpose = model.MetadataPurposeVersion
+			em.Slot = slot % model.MaxMetadataPerFile
+			metadata = append(metadata, em)
+		}
+
+		err := repo.CreateBatch(ctx, metadata)
+		var itemErr *BatchItemError
+		require.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, model.MaxMetadataPerFile-1, itemErr.Index)
+		assert.ErrorIs(t, err, model.ErrTooManyMetadata)
+
+		list, err := repo.ListByFileID(ctx, file.ID)
+		require.NoError(t, err)
+		assert.Len(t, list, 1, "배치 전체가 롤백됨")
+	})
+}
+
 // 벤치마크 테스트
 func BenchmarkEncryptionRepository_Create(b *testing.B) {
+	ctx := context.Background()
 	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
 	})
-	_ = model.Migrate(db)
+	_, _ = model.MigrateUp(db)
 
 	repo := NewEncryptionRepository(db)
 
@@ -311,6 +657,6 @@ func BenchmarkEncryptionRepository_Create(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		metadata := createTestEncryptionMetadata(files[i].ID)
-		_ = repo.Create(metadata)
+		_ = repo.Create(ctx, metadata)
 	}
 }
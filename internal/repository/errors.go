@@ -0,0 +1,81 @@
+// Package repository provides data access layer for DataLocker application.
+// This file defines the typed errors every repository wraps so callers can branch with errors.Is.
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"DataLocker/internal/model"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// 저장소 공통 에러
+//
+// 메시지는 사람이 읽을 수 있는 한국어 그대로 두고 %w로 감싸므로, 호출자는 문자열
+// 대신 errors.Is로 구분합니다. HTTP 계층에서는 ErrorHandlingMiddleware가
+// 404/400/409로 바꿉니다.
+var (
+	// ErrNotFound 대상 레코드가 없음 (model.ErrRecordNotFound와 같은 값이므로 기존 비교도 동작)
+	ErrNotFound = model.ErrRecordNotFound
+
+	// ErrInvalidID 0 또는 빈 값처럼 조회할 수 없는 ID
+	ErrInvalidID = errors.New("유효하지 않은 ID입니다")
+
+	// ErrDuplicate UNIQUE/PRIMARY KEY 제약조건 위반 (model.ErrDuplicateRecord와 같은 값)
+	ErrDuplicate = model.ErrDuplicateRecord
+
+	// ErrConstraintViolation FOREIGN KEY, NOT NULL, CHECK 등 나머지 제약조건 위반
+	ErrConstraintViolation = errors.New("제약조건 위반입니다")
+)
+
+// invalidIDError 대상별 메시지를 유지하면서 ErrInvalidID로 판별되는 에러
+type invalidIDError struct {
+	subject string
+}
+
+// invalidID subject(예: "파일")의 ID가 유효하지 않다는 에러를 생성합니다
+func invalidID(subject string) error {
+	return &invalidIDError{subject: subject}
+}
+
+// Error 대상 이름이 들어간 메시지를 반환합니다
+func (e *invalidIDError) Error() string {
+	return fmt.Sprintf("유효하지 않은 %s ID입니다", e.subject)
+}
+
+// Unwrap ErrInvalidID를 반환합니다
+func (e *invalidIDError) Unwrap() error {
+	return ErrInvalidID
+}
+
+// translateError SQLite 제약조건 에러를 저장소 공통 에러로 감쌉니다
+//
+// 드라이버의 원래 메시지(어느 테이블/컬럼인지)는 그대로 남기고, 제약조건 에러가
+// 아니면 err를 그대로 반환합니다.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return fmt.Errorf("%w: %w", ErrDuplicate, err)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return fmt.Errorf("%w: %w", ErrConstraintViolation, err)
+	}
+
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.Code != sqlite3.ErrConstraint {
+		return err
+	}
+
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return fmt.Errorf("%w: %w", ErrDuplicate, err)
+	default:
+		return fmt.Errorf("%w: %w", ErrConstraintViolation, err)
+	}
+}
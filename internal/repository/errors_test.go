@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"DataLocker/internal/model"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTranslateError(t *testing.T) {
+	plain := errors.New("disk I/O error")
+
+	testCases := []struct {
+		name   string
+		err    error
+		wantIs error
+	}{
+		{name: "UNIQUE", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}, wantIs: ErrDuplicate},
+		{name: "PRIMARY KEY", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintPrimaryKey}, wantIs: ErrDuplicate},
+		{name: "FOREIGN KEY", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintForeignKey}, wantIs: ErrConstraintViolation},
+		{name: "NOT NULL", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintNotNull}, wantIs: ErrConstraintViolation},
+		{name: "CHECK", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintCheck}, wantIs: ErrConstraintViolation},
+		{name: "GORM 중복 키", err: gorm.ErrDuplicatedKey, wantIs: ErrDuplicate},
+		{name: "GORM 외래키", err: gorm.ErrForeignKeyViolated, wantIs: ErrConstraintViolation},
+		{name: "제약조건 외 SQLite 에러", err: sqlite3.Error{Code: sqlite3.ErrBusy}, wantIs: nil},
+		{name: "일반 에러", err: plain, wantIs: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := translateError(tc.err)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tc.err, "원래 에러도 그대로 판별")
+			if tc.wantIs == nil {
+				assert.Equal(t, tc.err, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantIs)
+		})
+	}
+
+	assert.NoError(t, translateError(nil))
+}
+
+func TestInvalidID(t *testing.T) {
+	err := invalidID("키 슬롯")
+	assert.EqualError(t, err, "유효하지 않은 키 슬롯 ID입니다")
+	assert.ErrorIs(t, err, ErrInvalidID)
+	assert.ErrorIs(t, ErrNotFound, model.ErrRecordNotFound)
+}
+
+func TestFileRepository_TypedConstraintErrors(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	first := createTestFile("_typed_1")
+	require.NoError(t, repo.Create(ctx, first))
+	second := createTestFile("_typed_2")
+	require.NoError(t, repo.Create(ctx, second))
+
+	// 같은 암호화 경로로 생성
+	err := repo.Create(ctx, createTestFile("_typed_1"))
+	assert.ErrorIs(t, err, ErrDuplicate)
+	assert.Contains(t, err.Error(), "파일 생성 실패")
+
+	// 같은 암호화 경로로 수정 (버전은 그대로 유지)
+	second.EncryptedPath = first.EncryptedPath
+	err = repo.Update(ctx, second)
+	assert.ErrorIs(t, err, ErrDuplicate)
+	assert.Equal(t, uint(1), second.Version)
+
+	assert.ErrorIs(t, repo.Delete(ctx, 0), ErrInvalidID)
+	assert.ErrorIs(t, repo.Delete(ctx, TestNonExistentID), ErrNotFound)
+}
@@ -0,0 +1,49 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"DataLocker/internal/database"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openExampleDB 예제용 메모리 DB를 엽니다
+func openExampleDB() *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?_foreign_keys=ON"), &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
+	})
+	if err != nil {
+		panic(err)
+	}
+	if _, err := model.MigrateUp(db); err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// 레코드가 없으면 (nil, nil)이 아니라 model.ErrRecordNotFound를 감싼 에러가 반환됩니다.
+func ExampleFileRepository_GetByChecksumMD5() {
+	ctx := context.Background()
+	repo := repository.NewFileRepository(openExampleDB())
+
+	file, err := repo.GetByChecksumMD5(ctx, "d41d8cd98f00b204e9800998ecf8427e")
+	switch {
+	case errors.Is(err, model.ErrRecordNotFound):
+		fmt.Println("중복 없음")
+	case err != nil:
+		fmt.Println("조회 실패:", err)
+	default:
+		fmt.Println("중복 파일:", file.ID)
+	}
+
+	// Output:
+	// 중복 없음
+}
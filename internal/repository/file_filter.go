@@ -0,0 +1,166 @@
+// Package repository provides data access layer for DataLocker application.
+// This file defines the composable file filter used by FileRepository.Find.
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"DataLocker/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidFileFilter 필터 값이 범위를 벗어나거나 서로 모순됨 (예: MinSize > MaxSize)
+var ErrInvalidFileFilter = errors.New("유효하지 않은 파일 필터입니다")
+
+// Pagination 오프셋 페이지네이션 조건 (범위를 벗어나면 기본값으로 정규화)
+type Pagination struct {
+	Offset int
+	Limit  int
+}
+
+// folderSubtreeSQL 폴더 자신과 모든 하위 폴더의 ID를 고르는 쿼리 (UNION이라 순환이 있어도 끝남)
+const folderSubtreeSQL = `WITH RECURSIVE subtree(id) AS (
+	SELECT ?
+	UNION
+	SELECT folders.id FROM folders JOIN subtree ON folders.parent_id = subtree.id
+)
+SELECT id FROM subtree`
+
+// taggedFileIDsSQL 이름이 ?인 태그가 붙은 파일의 ID를 고르는 쿼리
+const taggedFileIDsSQL = "SELECT file_tags.file_id FROM file_tags JOIN tags ON tags.id = file_tags.tag_id WHERE tags.name = ?"
+
+// FileFilter 파일 목록 조회 조건 (zero 값인 필드는 조건에서 제외)
+//
+// 모든 조건은 AND로 묶입니다. 크기는 0이 "조건 없음"이므로 빈 파일만 고르는
+// 조건은 표현할 수 없습니다.
+type FileFilter struct {
+	Status        string    // 파일 상태 (model.FileStatus*)
+	MimePrefix    string    // MIME 타입 접두사 ("image/", "application/vnd." 등, 대소문자 무시)
+	NameContains  string    // 원본 파일명 부분 일치 (대소문자 무시, NFC로 정규화, %와 _는 문자 그대로)
+	ExternalID    string    // 외부 시스템 참조 ID (정확히 일치)
+	OwnerID       uint      // 소유 사용자 ID (소유자가 없는 파일은 어떤 사용자 조건에도 맞지 않음)
+	Tag           string    // 붙은 태그 이름 (model.NormalizeTagName으로 정규화해 비교)
+	FolderID      uint      // 파일이 속한 폴더 ID
+	Recursive     bool      // FolderID의 하위 폴더에 있는 파일도 포함 (FolderID가 없으면 무시)
+	MinSize       int64     // 원본 크기 하한 (바이트, 포함)
+	MaxSize       int64     // 원본 크기 상한 (바이트, 포함)
+	CreatedAfter  time.Time // 생성 시각 하한 (포함)
+	CreatedBefore time.Time // 생성 시각 상한 (미포함)
+	MetadataKey   string    // 사용자 메타데이터 키 ('.'으로 중첩 키 지정, 키가 있는 파일만)
+	MetadataValue string    // MetadataKey의 값 (문자열로 바꿔 정확히 일치, MetadataKey 필요)
+}
+
+// validate 필터 값의 범위와 상호 모순을 검사합니다
+func (f FileFilter) validate() error {
+	if f.Status != "" && !model.IsValidFileStatus(f.Status) {
+		return fmt.Errorf("%w: 상태 %q", ErrInvalidFileFilter, f.Status)
+	}
+
+	if len(f.MimePrefix) > model.MaxMimeTypeLength {
+		return fmt.Errorf("%w: MIME 접두사 길이 %d", ErrInvalidFileFilter, len(f.MimePrefix))
+	}
+
+	if len(f.NameContains) > model.MaxOriginalNameLength {
+		return fmt.Errorf("%w: 이름 검색어 길이 %d", ErrInvalidFileFilter, len(f.NameContains))
+	}
+
+	if len(f.ExternalID) > model.MaxExternalIDLength {
+		return fmt.Errorf("%w: 외부 참조 ID 길이 %d", ErrInvalidFileFilter, len(f.ExternalID))
+	}
+
+	if f.Tag != "" {
+		if _, err := model.NormalizeTagName(f.Tag); err != nil {
+			return fmt.Errorf("%w: 태그 %q: %w", ErrInvalidFileFilter, f.Tag, err)
+		}
+	}
+
+	if f.MetadataKey != "" {
+		if _, err := model.CustomMetadataPath(f.MetadataKey); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidFileFilter, err)
+		}
+	} else if f.MetadataValue != "" {
+		return fmt.Errorf("%w: 메타데이터 값은 키와 함께 지정해야 합니다", ErrInvalidFileFilter)
+	}
+
+	if f.MinSize < 0 || f.MaxSize < 0 {
+		return fmt.Errorf("%w: 크기는 0 이상이어야 합니다 (%d ~ %d)", ErrInvalidFileFilter, f.MinSize, f.MaxSize)
+	}
+
+	if f.MaxSize > 0 && f.MinSize > f.MaxSize {
+		return fmt.Errorf("%w: 최소 크기 %d가 최대 크기 %d보다 큽니다", ErrInvalidFileFilter, f.MinSize, f.MaxSize)
+	}
+
+	if !f.CreatedAfter.IsZero() && !f.CreatedBefore.IsZero() && !f.CreatedAfter.Before(f.CreatedBefore) {
+		return fmt.Errorf("%w: 기간 %s ~ %s", ErrInvalidFileFilter,
+			f.CreatedAfter.Format(time.RFC3339), f.CreatedBefore.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// apply zero가 아닌 조건을 WHERE 절로 추가합니다
+//
+// 저장된 시각이 UTC이므로 기간 조건도 UTC로 바꿔 비교합니다.
+func (f FileFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+
+	if prefix := strings.ToLower(strings.TrimSpace(f.MimePrefix)); prefix != "" {
+		query = query.Where("mime_type LIKE ? ESCAPE '\\'", escapeLike(prefix)+"%")
+	}
+
+	if name := model.NormalizeFileName(strings.TrimSpace(f.NameContains)); name != "" {
+		query = query.Where("original_name LIKE ? ESCAPE '\\'", "%"+escapeLike(name)+"%")
+	}
+
+	if f.ExternalID != "" {
+		query = query.Where("external_id = ?", f.ExternalID)
+	}
+
+	if f.OwnerID != 0 {
+		query = query.Where("owner_id = ?", f.OwnerID)
+	}
+
+	if f.Tag != "" {
+		name, _ := model.NormalizeTagName(f.Tag) // validate에서 확인
+		query = query.Where("id IN (?)", gorm.Expr(taggedFileIDsSQL, name))
+	}
+
+	if f.FolderID != 0 && f.Recursive {
+		query = query.Where("folder_id IN (?)", gorm.Expr(folderSubtreeSQL, f.FolderID))
+	} else if f.FolderID != 0 {
+		query = query.Where("folder_id = ?", f.FolderID)
+	}
+
+	if f.MinSize > 0 {
+		query = query.Where("size >= ?", f.MinSize)
+	}
+
+	if f.MaxSize > 0 {
+		query = query.Where("size <= ?", f.MaxSize)
+	}
+
+	if !f.CreatedAfter.IsZero() {
+		query = query.Where("created_at >= ?", f.CreatedAfter.UTC())
+	}
+
+	if !f.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", f.CreatedBefore.UTC())
+	}
+
+	if f.MetadataKey != "" {
+		path, _ := model.CustomMetadataPath(f.MetadataKey) // validate에서 확인
+		if f.MetadataValue != "" {
+			query = query.Where("CAST(json_extract(custom_metadata, ?) AS TEXT) = ?", path, f.MetadataValue)
+		} else {
+			query = query.Where("json_type(custom_metadata, ?) IS NOT NULL", path)
+		}
+	}
+
+	return query
+}
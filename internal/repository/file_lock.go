@@ -0,0 +1,291 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements per-file locks that serialize destructive file operations.
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/pkg/clock"
+
+	"gorm.io/gorm"
+)
+
+// 파일 락 기본값
+const (
+	// DefaultFileLockTTL 데이터베이스 락을 갱신하지 않으면 만료되는 기본 시간
+	DefaultFileLockTTL = 30 * time.Second
+
+	// DefaultFileLockWait 락을 얻기까지 기다리는 기본 시간
+	DefaultFileLockWait = 10 * time.Second
+
+	// fileLockPollInterval 데이터베이스 락이 풀렸는지 다시 확인하는 최대 간격
+	fileLockPollInterval = 100 * time.Millisecond
+)
+
+// ErrLockTimeout 대기 시간 안에 파일 락을 얻지 못한 경우
+var ErrLockTimeout = errors.New("파일 락 대기 시간을 초과했습니다")
+
+// UnlockFunc 얻은 파일 락을 해제하는 함수 (여러 번 호출해도 안전)
+type UnlockFunc func()
+
+// FileLocker 같은 파일에 대한 작업(영구 삭제, 볼륨 이동 등)을 직렬화하는 락
+//
+// 구현에 관계없이 계약은 같습니다:
+//   - 같은 파일 ID의 락은 한 번에 하나의 호출자만 가집니다 (재진입 불가)
+//   - 다른 파일의 락은 서로 막지 않습니다
+//   - 대기 시간 안에 얻지 못하면 ErrLockTimeout, ctx가 먼저 끝나면 ctx의 에러를 반환합니다
+//   - 해제 함수는 여러 번 호출해도 안전합니다
+type FileLocker interface {
+	Lock(ctx context.Context, fileID uint) (UnlockFunc, error)
+}
+
+// memoryFileLocker 한 프로세스 안에서만 유효한 파일 락
+type memoryFileLocker struct {
+	wait  time.Duration
+	table *memoryLockTable
+	clock clock.Clock
+}
+
+// memoryLockTable 잠긴 파일별 해제 알림 채널
+type memoryLockTable struct {
+	mu    sync.Mutex
+	locks map[uint]chan struct{}
+}
+
+// NewMemoryFileLocker 인스턴스가 하나일 때 사용하는 메모리 파일 락을 생성합니다
+//
+// wait가 0 이하이면 DefaultFileLockWait를 사용합니다.
+func NewMemoryFileLocker(wait time.Duration) FileLocker {
+	if wait <= 0 {
+		wait = DefaultFileLockWait
+	}
+
+	return &memoryFileLocker{
+		wait:  wait,
+		table: &memoryLockTable{locks: make(map[uint]chan struct{})},
+		clock: clock.Real{},
+	}
+}
+
+// Lock 파일 락을 얻을 때까지 기다립니다
+func (l *memoryFileLocker) Lock(ctx context.Context, fileID uint) (UnlockFunc, error) {
+	if fileID == 0 {
+		return nil, invalidID("파일")
+	}
+
+	timer := l.clock.NewTimer(l.wait)
+	defer timer.Stop()
+
+	table := l.table
+	for {
+		table.mu.Lock()
+		released, locked := table.locks[fileID]
+		if !locked {
+			done := make(chan struct{})
+			table.locks[fileID] = done
+			table.mu.Unlock()
+
+			var once sync.Once
+			return func() {
+				once.Do(func() {
+					table.mu.Lock()
+					delete(table.locks, fileID)
+					table.mu.Unlock()
+					close(done)
+				})
+			}, nil
+		}
+		table.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-timer.C():
+			return nil, fmt.Errorf("%w: 파일 ID %d", ErrLockTimeout, fileID)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// dbFileLocker file_locks 테이블로 인스턴스 간에 공유하는 파일 락
+type dbFileLocker struct {
+	db    *gorm.DB
+	owner string
+	ttl   time.Duration
+	wait  time.Duration
+	clock clock.Clock
+}
+
+// NewDBFileLocker 같은 데이터베이스를 쓰는 모든 인스턴스에서 유효한 파일 락을 생성합니다
+//
+// 락은 file_locks 테이블의 행이며, 가진 동안 ttl/3마다 만료 시각을 늘립니다.
+// 홀더가 비정상 종료해 갱신이 멈추면 ttl 뒤 다른 인스턴스가 회수합니다.
+// 만료 시각은 각 인스턴스의 시계로 기록하므로 인스턴스 간 시계 차이는 ttl보다
+// 충분히 작아야 합니다. ttl, wait가 0 이하이면 기본값을 사용합니다.
+func NewDBFileLocker(db *gorm.DB, ttl, wait time.Duration) FileLocker {
+	if db == nil {
+		panic("데이터베이스 연결이 필요합니다")
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultFileLockTTL
+	}
+
+	if wait <= 0 {
+		wait = DefaultFileLockWait
+	}
+
+	return &dbFileLocker{
+		db:    db,
+		owner: lockOwner(),
+		ttl:   ttl,
+		wait:  wait,
+		clock: clock.Real{},
+	}
+}
+
+// Lock 파일 락 행을 만들거나 만료된 행을 회수할 때까지 기다립니다
+func (l *dbFileLocker) Lock(ctx context.Context, fileID uint) (UnlockFunc, error) {
+	if fileID == 0 {
+		return nil, invalidID("파일")
+	}
+
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := l.clock.Now().Add(l.wait)
+	interval := min(fileLockPollInterval, l.ttl/3)
+	for {
+		acquired, err := l.tryLock(ctx, fileID, token)
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			break
+		}
+
+		remaining := deadline.Sub(l.clock.Now())
+		if remaining <= 0 {
+			return nil, fmt.Errorf("%w: 파일 ID %d", ErrLockTimeout, fileID)
+		}
+
+		timer := l.clock.NewTimer(min(interval, remaining))
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	// 가진 동안 만료 시각 연장 (해제하면 중단)
+	stop := make(chan struct{})
+	renewed := make(chan struct{})
+	go l.renew(fileID, token, stop, renewed)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-renewed
+
+			// 삭제에 실패해도 갱신이 멈췄으므로 ttl 뒤 다른 홀더가 회수함
+			_ = l.db.Where("file_id = ? AND token = ?", fileID, token).Delete(&model.FileLock{}).Error
+		})
+	}, nil
+}
+
+// tryLock 락 행을 만들거나 만료된 행을 가져옵니다
+func (l *dbFileLocker) tryLock(ctx context.Context, fileID uint, token string) (bool, error) {
+	now := l.clock.Now().UTC()
+	lock := &model.FileLock{
+		FileID:    fileID,
+		Owner:     l.owner,
+		Token:     token,
+		ExpiresAt: now.Add(l.ttl),
+	}
+
+	err := l.db.WithContext(ctx).Create(lock).Error
+	if err == nil {
+		return true, nil
+	}
+
+	if err = translateError(err); !errors.Is(err, ErrDuplicate) {
+		return false, fmt.Errorf("파일 락 생성 실패: %w", err)
+	}
+
+	// 다른 홀더의 락이 만료되었으면 회수 (조건부 UPDATE라 한 인스턴스만 성공)
+	result := l.db.WithContext(ctx).Model(&model.FileLock{}).
+		Where("file_id = ? AND expires_at < ?", fileID, now).
+		UpdateColumns(map[string]any{
+			"owner":      l.owner,
+			"token":      token,
+			"expires_at": now.Add(l.ttl),
+			"created_at": now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("만료된 파일 락 회수 실패: %w", translateError(result.Error))
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// renew stop이 닫힐 때까지 ttl/3마다 락 만료 시각을 연장합니다
+//
+// 연장하지 못한 동안에도 락은 ttl까지 유효하므로 다음 주기에 다시 시도합니다.
+func (l *dbFileLocker) renew(fileID uint, token string, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	interval := l.ttl / 3
+	timer := l.clock.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C():
+			l.db.Model(&model.FileLock{}).
+				Where("file_id = ? AND token = ?", fileID, token).
+				UpdateColumn("expires_at", l.clock.Now().UTC().Add(l.ttl))
+			timer.Reset(interval)
+		}
+	}
+}
+
+// lockOwner 락을 가진 인스턴스를 "호스트:PID" 형식으로 만듭니다 (운영자가 file_locks를 볼 때 참고용)
+func lockOwner() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+
+	owner := host + ":" + strconv.Itoa(os.Getpid())
+	if len(owner) > model.MaxLockOwnerLength {
+		owner = owner[len(owner)-model.MaxLockOwnerLength:]
+	}
+	return owner
+}
+
+// newLockToken 락 획득 한 번을 구분하는 임의의 토큰을 만듭니다
+//
+// 해제와 연장은 토큰이 같은 행에만 적용되므로, 만료되어 다른 홀더가 가져간
+// 락을 이전 홀더가 지우거나 연장하지 않습니다.
+func newLockToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("파일 락 토큰 생성 실패: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
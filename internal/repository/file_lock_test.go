@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fileLockerFactory wait 대기 시간을 가진 락을 만듭니다
+//
+// 같은 테스트 안에서 여러 번 호출하면 같은 백엔드를 공유하는 별도의 홀더
+// (데이터베이스 락은 다른 인스턴스)를 반환해야 합니다.
+type fileLockerFactory func(wait time.Duration) FileLocker
+
+// testFileLockerContract 모든 FileLocker 구현이 지켜야 하는 계약을 검사합니다
+func testFileLockerContract(t *testing.T, newLocker func(t *testing.T) fileLockerFactory) {
+	ctx := context.Background()
+
+	t.Run("같은 파일은 한 번에 하나만", func(t *testing.T) {
+		factory := newLocker(t)
+		lockers := []FileLocker{factory(5 * time.Second), factory(5 * time.Second)}
+
+		var holders, maxHolders atomic.Int32
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func(locker FileLocker) {
+				defer wg.Done()
+				unlock, err := locker.Lock(ctx, 1)
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer unlock()
+
+				n := holders.Add(1)
+				for {
+					current := maxHolders.Load()
+					if n <= current || maxHolders.CompareAndSwap(current, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				holders.Add(-1)
+			}(lockers[i%len(lockers)])
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), maxHolders.Load())
+	})
+
+	t.Run("다른 파일은 서로 막지 않음", func(t *testing.T) {
+		factory := newLocker(t)
+		a, b := factory(time.Second), factory(50*time.Millisecond)
+
+		unlock, err := a.Lock(ctx, 1)
+		require.NoError(t, err)
+		defer unlock()
+
+		unlockOther, err := b.Lock(ctx, 2)
+		require.NoError(t, err)
+		unlockOther()
+	})
+
+	t.Run("대기 시간 초과", func(t *testing.T) {
+		factory := newLocker(t)
+		a, b := factory(time.Second), factory(50*time.Millisecond)
+
+		unlock, err := a.Lock(ctx, 1)
+		require.NoError(t, err)
+		defer unlock()
+
+		start := time.Now()
+		_, err = b.Lock(ctx, 1)
+		assert.ErrorIs(t, err, ErrLockTimeout)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("context 취소", func(t *testing.T) {
+		factory := newLocker(t)
+		a, b := factory(time.Second), factory(5*time.Second)
+
+		unlock, err := a.Lock(ctx, 1)
+		require.NoError(t, err)
+		defer unlock()
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err = b.Lock(cancelCtx, 1)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("해제하면 기다리던 홀더가 얻음", func(t *testing.T) {
+		factory := newLocker(t)
+		a, b := factory(time.Second), factory(5*time.Second)
+
+		unlock, err := a.Lock(ctx, 1)
+		require.NoError(t, err)
+
+		acquired := make(chan error, 1)
+		go func() {
+			unlockB, err := b.Lock(ctx, 1)
+			if err == nil {
+				unlockB()
+			}
+			acquired <- err
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		unlock()
+		unlock() // 두 번 해제해도 안전
+
+		select {
+		case err := <-acquired:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("해제한 락을 얻지 못했습니다")
+		}
+
+		// 두 번째 해제가 다른 홀더의 락을 풀지 않음
+		unlockAgain, err := a.Lock(ctx, 1)
+		require.NoError(t, err)
+		_, err = factory(20*time.Millisecond).Lock(ctx, 1)
+		assert.ErrorIs(t, err, ErrLockTimeout)
+		unlockAgain()
+	})
+
+	t.Run("잘못된 파일 ID", func(t *testing.T) {
+		_, err := newLocker(t)(time.Second).Lock(ctx, 0)
+		assert.ErrorIs(t, err, ErrInvalidID)
+	})
+}
+
+func TestMemoryFileLocker(t *testing.T) {
+	testFileLockerContract(t, func(_ *testing.T) fileLockerFactory {
+		// 메모리 락은 한 인스턴스 안에서만 유효하므로 상태를 공유
+		shared := NewMemoryFileLocker(time.Second).(*memoryFileLocker)
+		return func(wait time.Duration) FileLocker {
+			return &memoryFileLocker{wait: wait, table: shared.table, clock: clock.Real{}}
+		}
+	})
+}
+
+func TestDBFileLocker(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 하위 테스트는 모두 락을 해제하고 끝나므로 데이터베이스를 공유
+	testFileLockerContract(t, func(_ *testing.T) fileLockerFactory {
+		return func(wait time.Duration) FileLocker {
+			return NewDBFileLocker(db, time.Second, wait)
+		}
+	})
+}
+
+func TestDBFileLocker_ReclaimsExpiredLock(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 갱신하지 못하고 종료된 홀더가 남긴 락
+	require.NoError(t, db.Create(&model.FileLock{
+		FileID:    1,
+		Owner:     "crashed:1",
+		Token:     "00000000000000000000000000000000",
+		ExpiresAt: time.Now().UTC().Add(-time.Second),
+	}).Error)
+
+	unlock, err := NewDBFileLocker(db, time.Second, 100*time.Millisecond).Lock(ctx, 1)
+	require.NoError(t, err)
+
+	var lock model.FileLock
+	require.NoError(t, db.First(&lock, 1).Error)
+	assert.Equal(t, lockOwner(), lock.Owner)
+	assert.True(t, lock.ExpiresAt.After(time.Now()))
+
+	unlock()
+	var count int64
+	require.NoError(t, db.Model(&model.FileLock{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestDBFileLocker_RenewsWhileHeld(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ttl := 150 * time.Millisecond
+	unlock, err := NewDBFileLocker(db, ttl, time.Second).Lock(ctx, 1)
+	require.NoError(t, err)
+	defer unlock()
+
+	// ttl을 몇 번 넘겨도 갱신 중인 락은 회수되지 않음
+	_, err = NewDBFileLocker(db, ttl, 3*ttl).Lock(ctx, 1)
+	assert.ErrorIs(t, err, ErrLockTimeout)
+}
+
+func TestMemoryFileLocker_TimesOutOnClock(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(time.Now())
+	locker := NewMemoryFileLocker(time.Minute).(*memoryFileLocker)
+	locker.clock = clk
+
+	unlock, err := locker.Lock(ctx, 1)
+	require.NoError(t, err)
+	defer unlock()
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := locker.Lock(ctx, 1)
+		errs <- err
+	}()
+
+	// 대기 타이머가 걸린 뒤 대기 시간만큼 시계를 진행하면 바로 포기
+	clk.BlockUntil(1)
+	clk.Advance(time.Minute)
+	assert.ErrorIs(t, <-errs, ErrLockTimeout)
+}
+
+func TestDBFileLocker_RenewsOnClock(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+	locker := NewDBFileLocker(db, 30*time.Second, time.Second).(*dbFileLocker)
+	locker.clock = clk
+
+	unlock, err := locker.Lock(ctx, 1)
+	require.NoError(t, err)
+	defer unlock()
+
+	var lock model.FileLock
+	require.NoError(t, db.First(&lock, 1).Error)
+	assert.True(t, lock.ExpiresAt.Equal(start.Add(30*time.Second)))
+
+	// ttl/3이 지나면 연장하고 다음 갱신 타이머를 다시 검
+	clk.BlockUntil(1)
+	clk.Advance(10 * time.Second)
+	clk.BlockUntil(1)
+
+	require.NoError(t, db.First(&lock, 1).Error)
+	assert.True(t, lock.ExpiresAt.Equal(start.Add(40*time.Second)))
+}
@@ -1,13 +1,29 @@
 // Package repository provides data access layer for DataLocker application.
 // It implements repository pattern for database operations with GORM.
+//
+// 단건 조회(GetByID, GetByFileID, GetByChecksumMD5 등)와 대상 레코드가 있어야 하는
+// Update, Delete는 레코드가 없으면 model.ErrRecordNotFound를 감싼 에러를 반환합니다.
+// (nil, nil)은 반환하지 않으므로 호출자는 에러가 nil이면 결과를 바로 사용하고,
+// "없음"은 errors.Is(err, model.ErrRecordNotFound)로 구분합니다. 목록 조회는
+// 결과가 없으면 빈 슬라이스를, 존재 여부만 필요하면 Exists 계열을 사용합니다.
 package repository
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"DataLocker/internal/model"
+	"DataLocker/pkg/clock"
+	"DataLocker/pkg/simhash"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // 페이지네이션 관련 상수
@@ -22,59 +38,403 @@ const (
 	MinOffset = 0
 )
 
+// 검색 정렬 관련 상수
+const (
+	// SearchSortRelevance 관련도순 (정확히 일치 > 접두 일치 > 부분 일치, 같으면 최신순)
+	SearchSortRelevance = "relevance"
+
+	// SearchSortLatest 최신순
+	SearchSortLatest = "latest"
+)
+
+// maxShortCodeAttempts 생성한 짧은 코드가 다른 파일과 겹칠 때 다시 만드는 최대 횟수
+//
+// 파일이 100만 개일 때 한 번 겹칠 확률이 약 100만분의 1이므로 다섯 번 모두
+// 겹치는 일은 사실상 저장소 상태가 잘못된 경우뿐입니다.
+const maxShortCodeAttempts = 5
+
+// 목록 정렬 기준 (SortOption.Field)
+const (
+	SortFieldCreatedAt = "created_at" // 생성 시각 (기본값)
+	SortFieldName      = "name"       // 원본 파일명 (대소문자 무시)
+	SortFieldSize      = "size"       // 원본 크기
+	SortFieldStatus    = "status"     // 파일 상태
+)
+
+// 목록 정렬 방향 (SortOption.Direction)
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc" // 기본값
+)
+
+// sortColumns 정렬 기준별 ORDER BY 식 (사용자 입력은 이 목록의 키로만 사용)
+var sortColumns = map[string]string{
+	SortFieldCreatedAt: "created_at",
+	SortFieldName:      "original_name COLLATE NOCASE",
+	SortFieldSize:      "size",
+	SortFieldStatus:    "status",
+}
+
+// 조회 조건 에러
+var (
+	// ErrInvalidSortOption 허용하지 않는 정렬 기준 또는 방향
+	ErrInvalidSortOption = errors.New("정렬 기준은 created_at, name, size, status 중 하나, 방향은 asc 또는 desc여야 합니다")
+
+	// ErrInvalidNameQuery 이름 검색어가 비어 있거나 파일명 최대 길이보다 김
+	ErrInvalidNameQuery = errors.New("이름 검색어는 1자 이상 파일명 최대 길이 이하여야 합니다")
+
+	// ErrInvalidMimeFilter MIME 필터가 type/subtype, type/, type/* 형식이 아님
+	ErrInvalidMimeFilter = errors.New("MIME 필터는 type/subtype, type/ 또는 type/* 형식이어야 합니다")
+
+	// ErrInvalidDateRange 기간의 시작이 없거나 종료보다 늦지 않음
+	ErrInvalidDateRange = errors.New("기간은 시작 시각이 있어야 하고 종료 시각보다 빨라야 합니다")
+)
+
+// 유사 중복 조회 에러
+var (
+	// ErrNoSimilaritySignature 기준 파일에 SimHash가 없음 (내용이 너무 짧거나 도입 전에 업로드됨)
+	ErrNoSimilaritySignature = errors.New("파일에 유사도 시그니처가 없습니다")
+
+	// ErrInvalidSimilarityDistance 최대 해밍 거리가 시그니처 길이 범위를 벗어남
+	ErrInvalidSimilarityDistance = errors.New("유사도 거리는 0 이상 시그니처 비트 수 이하여야 합니다")
+)
+
+// ErrInvalidStatusTransition 일괄 상태 변경으로 허용하지 않는 전환 (잘못된 상태, 같은 상태, encrypted로의 전환)
+var ErrInvalidStatusTransition = errors.New("허용하지 않는 파일 상태 전환입니다")
+
+// ErrRestoreConflict 복구하려는 파일의 암호화 경로를 다른 활성 파일이 사용 중
+var ErrRestoreConflict = errors.New("같은 암호화 경로를 사용하는 파일이 있어 복구할 수 없습니다")
+
+// mimeFilterPattern MIME 필터 형식 (subtype이 비어 있거나 *이면 type 전체)
+var mimeFilterPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9!#$&^_.+-]*/([a-z0-9][a-z0-9!#$&^_.+-]*|\*)?$`)
+
+// FileSearchParams 파일 통합 검색 조건 (비어 있는 조건은 무시)
+type FileSearchParams struct {
+	Query            string     // 원본 파일명 부분 일치 (대소문자 무시)
+	MatchDescription bool       // Query를 파일 설명에서도 찾음 (관련도순에서는 이름 일치보다 뒤)
+	Tags             []string   // 모두 붙은 파일만 (model.NormalizeTagName으로 정규화해 비교)
+	Status           string     // 파일 상태
+	MimeType         string     // MIME 타입
+	From             *time.Time // 생성 시각 하한 (포함)
+	To               *time.Time // 생성 시각 상한 (미포함)
+	SortBy           string     // relevance 또는 latest
+	Offset           int
+	Limit            int
+}
+
+// SortOption 목록 정렬 조건 (비어 있는 값은 생성 시각 내림차순)
+type SortOption struct {
+	Field     string // SortField* 중 하나
+	Direction string // SortAsc 또는 SortDesc
+}
+
+// orderBy 정렬 조건을 ORDER BY 절로 바꿉니다
+//
+// 기준 값이 같은 레코드는 ID로 같은 방향으로 정렬해 페이지 사이에 순서가 흔들리지
+// 않게 합니다. 허용 목록에 없으면 ErrInvalidSortOption을 반환합니다.
+func (o SortOption) orderBy() (clause.OrderBy, error) {
+	field := o.Field
+	if field == "" {
+		field = SortFieldCreatedAt
+	}
+
+	column, ok := sortColumns[field]
+	if !ok {
+		return clause.OrderBy{}, fmt.Errorf("%w: field=%q", ErrInvalidSortOption, o.Field)
+	}
+
+	var desc bool
+	switch o.Direction {
+	case "", SortDesc:
+		desc = true
+	case SortAsc:
+		desc = false
+	default:
+		return clause.OrderBy{}, fmt.Errorf("%w: direction=%q", ErrInvalidSortOption, o.Direction)
+	}
+
+	return clause.OrderBy{Columns: []clause.OrderByColumn{
+		{Column: clause.Column{Name: column, Raw: true}, Desc: desc},
+		{Column: clause.Column{Name: "id"}, Desc: desc},
+	}}, nil
+}
+
+// VolumeUsage 저장소 볼륨별 사용량
+type VolumeUsage struct {
+	VolumeID       string
+	Files          int64 // blob을 소유한 레코드 수
+	Bytes          int64 // 원본 크기 합계 (볼륨 용량 제한 기준)
+	EncryptedBytes int64 // 암호화본 크기 합계 (디스크 사용량)
+}
+
+// MimeFamilies 집계에서 구분하는 MIME 대분류 (나머지는 MimeFamilyOther)
+var MimeFamilies = []string{"text", "image", "audio", "video", "application"}
+
+// MimeFamilyOther MimeFamilies에 속하지 않는 MIME 타입의 대분류 이름
+const MimeFamilyOther = "other"
+
+// UsageTotals 파일 수와 원본/암호화본 크기 합계
+//
+// 파일 수에는 blob 참조 레코드가 포함되지만, 크기에는 저장 공간을 차지하는
+// blob 소유 레코드만 포함됩니다. Bytes는 보호하는 데이터 양(원본 크기)이고,
+// EncryptedBytes는 청크마다 붙는 암호화 오버헤드를 포함한 디스크 사용량입니다.
+type UsageTotals struct {
+	Files          int64
+	Bytes          int64
+	EncryptedBytes int64
+}
+
+// AggregateOptions 집계 범위
+type AggregateOptions struct {
+	IncludeDeleted bool // 소프트 삭제된 파일(휴지통)도 집계하고 그 몫을 Trash로 따로 반환
+}
+
+// FileAggregates 상태별, MIME 대분류별 파일 수와 크기 집계
+type FileAggregates struct {
+	Total        UsageTotals
+	Trash        UsageTotals            // Total 중 소프트 삭제된 파일 (IncludeDeleted가 아니면 0)
+	ByStatus     map[string]UsageTotals // 모든 상태를 포함 (파일이 없으면 0)
+	ByMimeFamily map[string]UsageTotals // MimeFamilies와 MimeFamilyOther를 모두 포함
+}
+
+// SimilarFile 유사 중복 조회 결과 한 건
+type SimilarFile struct {
+	File     *model.File
+	Distance int // 기준 파일과의 SimHash 해밍 거리 (0이면 거의 같은 내용)
+}
+
+// DuplicateChecksum 같은 MD5 체크섬을 가진 살아 있는 파일 묶음
+type DuplicateChecksum struct {
+	Checksum string
+	Files    int64 // blob 참조 레코드를 포함한 파일 수
+	Blobs    int64 // 자체 blob을 가진 파일 수 (중복 제거되지 않은 사본)
+	Size     int64 // 원본 크기 (같은 내용이므로 묶음 안에서 같음)
+}
+
+// ReclaimableBytes 사본을 blob 하나로 합치면 아낄 수 있는 용량을 반환합니다
+func (d DuplicateChecksum) ReclaimableBytes() int64 {
+	if d.Blobs <= 1 {
+		return 0
+	}
+	return (d.Blobs - 1) * d.Size
+}
+
+// 파일 활동 이벤트 종류
+const (
+	// ActivityUploaded 파일 레코드 생성 (생성 시각, 생성자)
+	ActivityUploaded = "uploaded"
+
+	// ActivityFailed 처리 실패 (failed 상태가 된 뒤 마지막 수정 시각, 수정자)
+	ActivityFailed = "failed"
+
+	// ActivityDeleted 소프트 삭제 (삭제 시각, 삭제한 행위자)
+	ActivityDeleted = "deleted"
+)
+
+// FileActivity 파일 레코드의 시각과 감사 필드에서 만든 활동 이벤트 한 건
+//
+// 별도 이벤트 로그가 아니므로 영구 삭제된 파일의 이벤트는 남지 않고, 파일당
+// 종류별로 가장 최근 이벤트 하나만 표현합니다.
+type FileActivity struct {
+	Type       string    // Activity* 상수
+	FileID     uint      // 이벤트가 일어난 파일
+	FileName   string    // 원본 파일명
+	OccurredAt time.Time // 이벤트 시각 (UTC)
+	Actor      string    // 감사 필드의 행위자
+}
+
 // FileRepository 파일 메타데이터 저장소 인터페이스
+//
+// 모든 메서드는 ctx를 쿼리에 전달하므로 요청이 취소되거나 제한 시간이 지나면
+// 진행 중인 쿼리도 중단되고, ctx의 에러를 감싼 에러를 반환합니다.
 type FileRepository interface {
-	Create(file *model.File) error
-	GetByID(id uint) (*model.File, error)
-	GetAll(offset, limit int) ([]*model.File, int64, error)
-	Update(file *model.File) error
-	Delete(id uint) error
-	GetByStatus(status string, offset, limit int) ([]*model.File, int64, error)
-	GetByChecksumMD5(checksum string) (*model.File, error)
-	Exists(id uint) (bool, error)
-	Count() (int64, error)
+	Create(ctx context.Context, file *model.File) error
+	CreateBatch(ctx context.Context, files []*model.File) error
+	GetByID(ctx context.Context, id uint) (*model.File, error)
+	GetByIDWithDeleted(ctx context.Context, id uint) (*model.File, error)
+	GetByIDForOwner(ctx context.Context, id, ownerID uint) (*model.File, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]*model.File, error)
+	GetAll(ctx context.Context, offset, limit int, sort SortOption) ([]*model.File, int64, error)
+	GetAllByOwner(ctx context.Context, ownerID uint, offset, limit int) ([]*model.File, int64, error)
+	Find(ctx context.Context, filter FileFilter, page Pagination, sort SortOption) ([]*model.File, int64, error)
+	GetAfter(ctx context.Context, cursor Cursor, limit int) ([]*model.File, Cursor, error)
+	Update(ctx context.Context, file *model.File) error
+	UpdateStatus(ctx context.Context, id uint, status string) error
+	Touch(ctx context.Context, id uint) error
+	BulkUpdateStatus(ctx context.Context, fromStatus, toStatus string, olderThan time.Time) (int64, error)
+	Delete(ctx context.Context, id uint) error
+	DeleteBatch(ctx context.Context, ids []uint) (int64, error)
+	Restore(ctx context.Context, id uint) error
+	GetDeleted(ctx context.Context, offset, limit int) ([]*model.File, int64, error)
+	GetByStatus(ctx context.Context, status string, offset, limit int, sort SortOption) ([]*model.File, int64, error)
+	GetStalePending(ctx context.Context, olderThan time.Duration, offset, limit int) ([]*model.File, int64, error)
+	GetNotAccessedSince(ctx context.Context, cutoff time.Time, offset, limit int) ([]*model.File, int64, error)
+	GetByMimeType(ctx context.Context, mimeType string, offset, limit int) ([]*model.File, int64, error)
+	GetByDateRange(ctx context.Context, from, to time.Time, status string, offset, limit int) ([]*model.File, int64, error)
+	GetByChecksumMD5(ctx context.Context, checksum string) (*model.File, error)
+	GetAllByChecksum(ctx context.Context, checksum string) ([]*model.File, error)
+	FindDuplicateChecksums(ctx context.Context, minCount, offset, limit int) ([]DuplicateChecksum, int64, error)
+	GetByEncryptedPath(ctx context.Context, path string) (*model.File, error)
+	GetByExternalID(ctx context.Context, externalID string) (*model.File, error)
+	GetByShortCode(ctx context.Context, code string) (*model.File, error)
+	ReissueShortCode(ctx context.Context, id uint) (string, error)
+	RecentActivity(ctx context.Context, ownerID uint, page Pagination) ([]FileActivity, int64, error)
+	AddTags(ctx context.Context, fileID uint, tags []string) error
+	RemoveTag(ctx context.Context, fileID uint, tag string) error
+	GetByTag(ctx context.Context, tag string, offset, limit int) ([]*model.File, int64, error)
+	SearchMetadata(ctx context.Context, key, value string, offset, limit int) ([]*model.File, int64, error)
+	CreateFolder(ctx context.Context, folder *model.Folder) error
+	EnsureFolderPath(ctx context.Context, parentID *uint, path string) (*model.Folder, error)
+	MoveFolder(ctx context.Context, id uint, parentID *uint) error
+	GetFolderTree(ctx context.Context, rootID uint) ([]*model.Folder, error)
+	GetFilesInFolder(ctx context.Context, folderID uint, recursive bool, page Pagination) ([]*model.File, int64, error)
+	FindSimilar(ctx context.Context, fileID uint, maxDistance int) ([]SimilarFile, error)
+	Exists(ctx context.Context, id uint) (bool, error)
+	Count(ctx context.Context) (int64, error)
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+	SumSizeByStatus(ctx context.Context) (map[string]int64, error)
+	TotalSize(ctx context.Context) (int64, error)
+	TotalSizeByMimePrefix(ctx context.Context, prefix string) (int64, error)
+	Aggregate(ctx context.Context, opts AggregateOptions) (*FileAggregates, error)
+	Search(ctx context.Context, params FileSearchParams) ([]*model.File, int64, error)
+	SearchByName(ctx context.Context, query string, offset, limit int) ([]*model.File, int64, error)
+	Purge(ctx context.Context, id uint) error
+	PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+	CountBlobReferences(ctx context.Context, blobFileID uint) (int64, error)
+	UsageByVolume(ctx context.Context) ([]VolumeUsage, error)
+	GetByVolume(ctx context.Context, volumeID string, offset, limit int) ([]*model.File, int64, error)
+	ListMissingMetadata(ctx context.Context) ([]*model.File, error)
+	GetWithoutMetadata(ctx context.Context, offset, limit int) ([]*model.File, int64, error)
 }
 
 // fileRepository GORM 기반 파일 저장소 구현체
 type fileRepository struct {
-	db *gorm.DB
+	db           *gorm.DB
+	newShortCode func() (string, error) // 짧은 코드 생성기 (테스트에서 충돌을 재현할 때 교체)
+	clock        clock.Clock
 }
 
 // NewFileRepository 새로운 파일 저장소를 생성합니다
-func NewFileRepository(db *gorm.DB) FileRepository {
+//
+// WithInstrumenter로 훅을 지정하면 모든 메서드의 실행 시간을 보고하는 저장소를 반환합니다.
+func NewFileRepository(db *gorm.DB, opts ...Option) FileRepository {
 	if db == nil {
 		panic("데이터베이스 연결이 필요합니다")
 	}
 
-	return &fileRepository{
-		db: db,
+	var repo FileRepository = &fileRepository{
+		db:           db,
+		newShortCode: model.NewShortCode,
+		clock:        clock.Real{},
 	}
+	if o := applyOptions(opts); o.instrumenter != nil {
+		repo = &instrumentedFileRepository{next: repo, inst: o.instrumenter}
+	}
+	return repo
 }
 
 // Create 새로운 파일 레코드를 생성합니다
-func (r *fileRepository) Create(file *model.File) error {
+func (r *fileRepository) Create(ctx context.Context, file *model.File) error {
 	if file == nil {
 		return fmt.Errorf("파일 데이터가 없습니다")
 	}
 
-	if err := r.db.Create(file).Error; err != nil {
-		return fmt.Errorf("파일 생성 실패: %w", err)
+	// 짧은 코드를 지정하지 않았으면 생성하고, 다른 파일과 겹치면 새 코드로 다시 시도
+	generated := file.ShortCode == ""
+	for attempt := 1; ; attempt++ {
+		if generated {
+			code, err := r.newShortCode()
+			if err != nil {
+				return err
+			}
+			file.ShortCode = code
+		}
+
+		err := r.db.WithContext(ctx).Create(file).Error
+		if err == nil {
+			return nil
+		}
+
+		if !generated || !isShortCodeCollision(err) || attempt >= maxShortCodeAttempts {
+			return fmt.Errorf("파일 생성 실패: %w", translateError(err))
+		}
+	}
+}
+
+// CreateBatch 여러 파일 레코드를 한 트랜잭션에서 묶어서 생성합니다
+//
+// 전부 생성되거나 하나도 생성되지 않습니다. 검증에 실패한 행이 있으면
+// *BatchItemError로 위치를 알려주고, 성공하면 각 파일의 ID가 채워집니다.
+func (r *fileRepository) CreateBatch(ctx context.Context, files []*model.File) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	generated := make([]bool, len(files))
+	for i, file := range files {
+		generated[i] = file != nil && file.ShortCode == ""
+	}
+
+	// 짧은 코드가 기존 파일과 겹치면 전체가 롤백되므로 생성한 코드를 모두 바꿔 다시 시도
+	for attempt := 1; ; attempt++ {
+		if err := r.assignShortCodes(files, generated); err != nil {
+			return err
+		}
+
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return createInBatches(tx, files)
+		})
+		if err == nil {
+			return nil
+		}
+
+		if !isShortCodeCollision(err) || attempt >= maxShortCodeAttempts {
+			return fmt.Errorf("파일 일괄 생성 실패: %w", translateError(err))
+		}
+	}
+}
+
+// assignShortCodes generated로 표시된 파일에 배치 안에서도 겹치지 않는 짧은 코드를 부여합니다
+func (r *fileRepository) assignShortCodes(files []*model.File, generated []bool) error {
+	used := make(map[string]bool, len(files))
+	for i, file := range files {
+		if file != nil && !generated[i] {
+			used[file.ShortCode] = true
+		}
+	}
+
+	for i, file := range files {
+		if !generated[i] {
+			continue
+		}
+
+		for {
+			code, err := r.newShortCode()
+			if err != nil {
+				return err
+			}
+			if !used[code] {
+				used[code] = true
+				file.ShortCode = code
+				break
+			}
+		}
 	}
 
 	return nil
 }
 
 // GetByID ID로 파일을 조회합니다
-func (r *fileRepository) GetByID(id uint) (*model.File, error) {
+func (r *fileRepository) GetByID(ctx context.Context, id uint) (*model.File, error) {
 	if id == 0 {
-		return nil, fmt.Errorf("유효하지 않은 파일 ID입니다")
+		return nil, invalidID("파일")
 	}
 
 	var file model.File
-	err := r.db.Preload("EncryptionMetadata").First(&file, id).Error
+	err := r.preloadMetadata(r.db.WithContext(ctx)).First(&file, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("파일을 찾을 수 없습니다: ID %d", id)
+			return nil, fmt.Errorf("파일을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
 		}
 		return nil, fmt.Errorf("파일 조회 실패: %w", err)
 	}
@@ -82,23 +442,189 @@ func (r *fileRepository) GetByID(id uint) (*model.File, error) {
 	return &file, nil
 }
 
-// GetAll 모든 파일을 페이지네이션으로 조회합니다
-func (r *fileRepository) GetAll(offset, limit int) ([]*model.File, int64, error) {
-	offset, limit = r.normalizePagination(offset, limit)
+// GetByIDs 여러 파일을 한 번의 쿼리로 primary 암호화 메타데이터와 함께 조회합니다
+//
+// 결과는 ids 순서를 따르며 중복 ID는 한 번만 담습니다. 찾지 못한(소프트 삭제 포함)
+// ID가 있으면 찾은 파일과 함께 *MissingIDsError를 반환합니다. ID가 MaxBatchIDs개를
+// 넘으면 ErrTooManyIDs를, 0이 있으면 ErrInvalidID로 판별되는 *BatchItemError를 반환합니다.
+func (r *fileRepository) GetByIDs(ctx context.Context, ids []uint) ([]*model.File, error) {
+	unique, err := uniqueIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("파일 일괄 조회 실패: %w", err)
+	}
+	if len(unique) > MaxBatchIDs {
+		return nil, fmt.Errorf("파일 일괄 조회 실패: %w: %d개", ErrTooManyIDs, len(unique))
+	}
+
+	var found []*model.File
+	if err := r.preloadMetadata(r.db.WithContext(ctx)).Where("id IN ?", unique).Find(&found).Error; err != nil {
+		return nil, fmt.Errorf("파일 일괄 조회 실패: %w", err)
+	}
+
+	byID := make(map[uint]*model.File, len(found))
+	for _, file := range found {
+		byID[file.ID] = file
+	}
+
+	files := make([]*model.File, 0, len(found))
+	var missing []uint
+	for _, id := range unique {
+		if file, ok := byID[id]; ok {
+			files = append(files, file)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		return files, &MissingIDsError{IDs: missing}
+	}
+	return files, nil
+}
 
+// ListMissingMetadata primary 암호화 메타데이터가 없는 encrypted 파일을 ID순으로 조회합니다
+//
+// 메타데이터 레코드를 잃은 파일을 점검하는 데 사용합니다. blob 참조 레코드는
+// 원본의 메타데이터를 사용하므로 제외하고, 소프트 삭제된 레코드는 복구될 수
+// 있으므로 포함합니다.
+func (r *fileRepository) ListMissingMetadata(ctx context.Context) ([]*model.File, error) {
 	var files []*model.File
+	err := r.withoutMetadata(r.db.WithContext(ctx).Unscoped()).Select("files.*").
+		Order("files.id").
+		Find(&files).Error
+	if err != nil {
+		return nil, fmt.Errorf("메타데이터 누락 파일 조회 실패: %w", err)
+	}
+
+	return files, nil
+}
+
+// GetWithoutMetadata primary 암호화 메타데이터가 없는 살아 있는 encrypted 파일을 ID순으로 페이지네이션 조회합니다
+//
+// 메타데이터 생성이 실패해 복호화할 수 없게 된 파일을 정합성 점검에서 찾는 데
+// 사용합니다. ListMissingMetadata와 달리 소프트 삭제된 레코드는 제외합니다.
+func (r *fileRepository) GetWithoutMetadata(ctx context.Context, offset, limit int) ([]*model.File, int64, error) {
+	offset, limit = r.normalizePagination(offset, limit)
+
 	var total int64
+	if err := r.withoutMetadata(r.db.WithContext(ctx).Model(&model.File{})).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("메타데이터 누락 파일 카운트 조회 실패: %w", err)
+	}
+
+	var files []*model.File
+	err := r.withoutMetadata(r.db.WithContext(ctx)).Select("files.*").
+		Order("files.id").
+		Offset(offset).
+		Limit(limit).
+		Find(&files).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("메타데이터 누락 파일 목록 조회 실패: %w", err)
+	}
+
+	return files, total, nil
+}
+
+// withoutMetadata primary 메타데이터와 LEFT JOIN해 짝이 없는 encrypted 파일만 남깁니다
+//
+// blob 참조 레코드는 원본의 메타데이터를 사용하므로 제외합니다.
+func (r *fileRepository) withoutMetadata(query *gorm.DB) *gorm.DB {
+	return query.Joins("LEFT JOIN encryption_metadata ON encryption_metadata.file_id = files.id AND encryption_metadata.purpose = ?", model.MetadataPurposePrimary).
+		Where("encryption_metadata.id IS NULL").
+		Where("files.status = ? AND files.blob_file_id IS NULL", model.FileStatusEncrypted)
+}
+
+// GetByIDWithDeleted 소프트 삭제된 레코드를 포함해 ID로 파일을 조회합니다
+func (r *fileRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*model.File, error) {
+	if id == 0 {
+		return nil, invalidID("파일")
+	}
+
+	var file model.File
+	err := r.preloadMetadata(r.db.WithContext(ctx).Unscoped()).First(&file, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("파일을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("파일 조회 실패: %w", err)
+	}
+
+	return &file, nil
+}
+
+// GetByIDForOwner 사용자가 소유한 파일만 ID로 조회합니다
+//
+// 다른 사용자의 파일이나 소유자가 없는 파일은 없는 파일과 같은 에러
+// (model.ErrRecordNotFound)를 반환하므로 응답으로 존재 여부를 알 수 없습니다.
+func (r *fileRepository) GetByIDForOwner(ctx context.Context, id, ownerID uint) (*model.File, error) {
+	if id == 0 {
+		return nil, invalidID("파일")
+	}
+
+	if ownerID == 0 {
+		return nil, invalidID("사용자")
+	}
+
+	var file model.File
+	err := r.preloadMetadata(r.db.WithContext(ctx)).
+		Where("owner_id = ?", ownerID).
+		First(&file, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("파일을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("파일 조회 실패: %w", err)
+	}
+
+	return &file, nil
+}
+
+// preloadMetadata 파일의 primary 암호화 메타데이터를 함께 조회하도록 설정합니다
+func (r *fileRepository) preloadMetadata(query *gorm.DB) *gorm.DB {
+	return query.Preload("EncryptionMetadata", "purpose = ?", model.MetadataPurposePrimary)
+}
+
+// GetAll 모든 파일을 sort 순서로 페이지네이션 조회합니다 (빈 SortOption은 최신순)
+func (r *fileRepository) GetAll(ctx context.Context, offset, limit int, sort SortOption) ([]*model.File, int64, error) {
+	return r.Find(ctx, FileFilter{}, Pagination{Offset: offset, Limit: limit}, sort)
+}
+
+// GetAllByOwner 사용자가 소유한 파일을 최신순으로 페이지네이션 조회합니다
+//
+// 소유자가 없는 파일(다중 사용자 도입 전 파일)은 포함하지 않습니다.
+func (r *fileRepository) GetAllByOwner(ctx context.Context, ownerID uint, offset, limit int) ([]*model.File, int64, error) {
+	if ownerID == 0 {
+		return nil, 0, invalidID("사용자")
+	}
+
+	return r.Find(ctx, FileFilter{OwnerID: ownerID}, Pagination{Offset: offset, Limit: limit}, SortOption{})
+}
+
+// Find filter에 맞는 파일을 sort 순서로 페이지네이션 조회합니다 (빈 SortOption은 최신순)
+//
+// 필터 값이 모순되면 ErrInvalidFileFilter를, 정렬 조건이 허용 목록에 없으면
+// ErrInvalidSortOption을 감싼 에러를 반환합니다. total은 페이지와 무관한 전체 건수입니다.
+func (r *fileRepository) Find(ctx context.Context, filter FileFilter, page Pagination, sort SortOption) ([]*model.File, int64, error) {
+	if err := filter.validate(); err != nil {
+		return nil, 0, err
+	}
 
-	// 전체 카운트 조회
-	if err := r.db.Model(&model.File{}).Count(&total).Error; err != nil {
+	orderBy, err := sort.orderBy()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset, limit := r.normalizePagination(page.Offset, page.Limit)
+
+	var total int64
+	if err := filter.apply(r.db.WithContext(ctx).Model(&model.File{})).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("파일 카운트 조회 실패: %w", err)
 	}
 
-	// 페이지네이션된 데이터 조회
-	err := r.db.Preload("EncryptionMetadata").
+	var files []*model.File
+	err = filter.apply(r.preloadMetadata(r.db.WithContext(ctx))).
 		Offset(offset).
 		Limit(limit).
-		Order("created_at DESC").
+		Clauses(orderBy).
 		Find(&files).Error
 	if err != nil {
 		return nil, 0, fmt.Errorf("파일 목록 조회 실패: %w", err)
@@ -107,136 +633,1665 @@ func (r *fileRepository) GetAll(offset, limit int) ([]*model.File, int64, error)
 	return files, total, nil
 }
 
+// GetAfter 커서 다음 파일들을 최신순(created_at, id 내림차순)으로 조회합니다
+//
+// 빈 커서는 가장 최신 페이지를 반환합니다. 오프셋 대신 이전 페이지 마지막 파일의
+// (created_at, id) 뒤부터 읽으므로 앞쪽에 새 파일이 추가되어도 페이지 사이에 행이
+// 밀리거나 중복되지 않고, 깊은 페이지도 인덱스(idx_files_created_at, SQLite는 id를
+// 포함)로 바로 찾아갑니다. 다음 페이지가 없으면 빈 커서를 반환합니다.
+func (r *fileRepository) GetAfter(ctx context.Context, cursor Cursor, limit int) ([]*model.File, Cursor, error) {
+	_, limit = r.normalizePagination(0, limit)
+
+	query := r.preloadMetadata(r.db.WithContext(ctx))
+	if !cursor.IsZero() {
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)",
+			cursor.createdAt, cursor.createdAt, cursor.id)
+	}
+
+	// 다음 페이지 존재 여부를 알기 위해 한 건 더 조회
+	var files []*model.File
+	err := query.Order("created_at DESC").Order("id DESC").
+		Limit(limit + 1).
+		Find(&files).Error
+	if err != nil {
+		return nil, Cursor{}, fmt.Errorf("파일 목록 조회 실패: %w", err)
+	}
+
+	if len(files) <= limit {
+		return files, Cursor{}, nil
+	}
+
+	files = files[:limit]
+	last := files[limit-1]
+	return files, cursorAfter(last.CreatedAt, last.ID), nil
+}
+
 // Update 파일 정보를 업데이트합니다
-func (r *fileRepository) Update(file *model.File) error {
+//
+// 읽어 온 Version이 DB와 같을 때만 반영하고 Version을 1 올립니다. 그 사이 다른
+// 요청이 먼저 수정했으면 model.ErrStaleRecord를 감싼 에러를 반환하므로, 호출자는
+// 다시 읽어 재시도하거나 충돌로 응답합니다. 연관 레코드(암호화 메타데이터, 키 슬롯)는
+// 각 저장소로만 변경하므로 여기서는 파일 컬럼만 갱신합니다.
+func (r *fileRepository) Update(ctx context.Context, file *model.File) error {
 	if file == nil {
 		return fmt.Errorf("파일 데이터가 없습니다")
 	}
 
 	if file.ID == 0 {
-		return fmt.Errorf("유효하지 않은 파일 ID입니다")
+		return invalidID("파일")
 	}
 
 	// 파일 존재 여부 확인
-	exists, err := r.Exists(file.ID)
+	exists, err := r.Exists(ctx, file.ID)
 	if err != nil {
 		return fmt.Errorf("파일 존재 확인 실패: %w", err)
 	}
 
 	if !exists {
-		return fmt.Errorf("업데이트할 파일을 찾을 수 없습니다: ID %d", file.ID)
+		return fmt.Errorf("업데이트할 파일을 찾을 수 없습니다: ID %d: %w", file.ID, model.ErrRecordNotFound)
+	}
+
+	// 버전 조건과 함께 업데이트 실행 (생성 정보와 ReissueShortCode로만 바꾸는 짧은 코드는 유지)
+	expected := file.Version
+	file.Version = expected + 1
+	result := r.db.WithContext(ctx).Model(file).
+		Where("version = ?", expected).
+		Select("*").
+		Omit("created_at", "created_by", "short_code", clause.Associations).
+		Updates(file)
+	if result.Error != nil {
+		file.Version = expected
+		return fmt.Errorf("파일 업데이트 실패: %w", translateError(result.Error))
 	}
 
-	// 업데이트 실행
-	if err := r.db.Save(file).Error; err != nil {
-		return fmt.Errorf("파일 업데이트 실패: %w", err)
+	if result.RowsAffected == 0 {
+		file.Version = expected
+		return fmt.Errorf("파일 %d (버전 %d): %w", file.ID, expected, model.ErrStaleRecord)
 	}
 
 	return nil
 }
 
-// Delete 파일을 삭제합니다 (소프트 삭제)
-func (r *fileRepository) Delete(id uint) error {
+// UpdateStatus 파일의 상태 컬럼만 갱신합니다
+//
+// Update와 달리 버전 확인 없이 status/updated_at/updated_by만 한 문장으로 바꾸므로
+// 다른 컬럼의 동시 변경을 덮어쓰지 않고, 전체 모델 검증 훅도 실행하지 않습니다.
+// 버전은 올리므로 이 파일을 먼저 읽어 둔 Update는 ErrStaleRecord로 실패합니다.
+// 삭제되었거나 없는 파일이면 model.ErrRecordNotFound를 감싼 에러를 반환합니다.
+func (r *fileRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
 	if id == 0 {
-		return fmt.Errorf("유효하지 않은 파일 ID입니다")
+		return invalidID("파일")
 	}
 
-	// 파일 존재 여부 확인
-	exists, err := r.Exists(id)
-	if err != nil {
-		return fmt.Errorf("파일 존재 확인 실패: %w", err)
+	if !model.IsValidFileStatus(status) {
+		return fmt.Errorf("유효하지 않은 파일 상태입니다: %s", status)
 	}
 
-	if !exists {
-		return fmt.Errorf("삭제할 파일을 찾을 수 없습니다: ID %d", id)
+	result := r.db.WithContext(ctx).Model(&model.File{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"status":     status,
+			"updated_at": r.clock.Now().UTC(),
+			"updated_by": model.ActorFromContext(ctx),
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("파일 상태 변경 실패: %w", translateError(result.Error))
 	}
 
-	// 소프트 삭제 실행
-	if err := r.db.Delete(&model.File{}, id).Error; err != nil {
-		return fmt.Errorf("파일 삭제 실패: %w", err)
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("상태를 변경할 파일을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
 	}
 
 	return nil
 }
 
-// GetByStatus 상태별로 파일을 조회합니다
-func (r *fileRepository) GetByStatus(status string, offset, limit int) ([]*model.File, int64, error) {
-	if status == "" {
-		return nil, 0, fmt.Errorf("상태 값이 필요합니다")
+// Touch 파일의 마지막 접근 시각을 현재 시각으로 기록합니다
+//
+// 복호화가 성공할 때마다 호출되므로 last_accessed_at 한 컬럼만 바꾸며, 내용이
+// 바뀐 것이 아니므로 updated_at과 버전은 그대로 둡니다 (읽어 둔 Update가
+// 접근 기록 때문에 ErrStaleRecord로 실패하지 않음).
+// 삭제되었거나 없는 파일이면 model.ErrRecordNotFound를 감싼 에러를 반환합니다.
+func (r *fileRepository) Touch(ctx context.Context, id uint) error {
+	if id == 0 {
+		return invalidID("파일")
 	}
 
-	if !model.IsValidFileStatus(status) {
-		return nil, 0, fmt.Errorf("유효하지 않은 파일 상태입니다: %s", status)
+	result := r.db.WithContext(ctx).Model(&model.File{}).
+		Where("id = ?", id).
+		UpdateColumn("last_accessed_at", r.clock.Now().UTC())
+	if result.Error != nil {
+		return fmt.Errorf("파일 접근 시각 기록 실패: %w", translateError(result.Error))
 	}
 
-	offset, limit = r.normalizePagination(offset, limit)
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("접근 시각을 기록할 파일을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+	}
 
-	var files []*model.File
-	var total int64
+	return nil
+}
 
-	// 상태별 카운트 조회
-	if err := r.db.Model(&model.File{}).Where("status = ?", status).Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("상태별 파일 카운트 조회 실패: %w", err)
+// BulkUpdateStatus fromStatus이고 olderThan 이전에 마지막으로 갱신된 파일을 한 번의 UPDATE로 toStatus로 바꿉니다
+//
+// 방치된 pending을 failed로 표시하거나 failed를 다시 pending으로 돌리는 운영 복구용이며,
+// 행을 읽지 않고 바꾼 행 수만 반환합니다. encrypted는 암호화가 성공했을 때만 붙는
+// 상태이므로 이 경로로 바꿀 수 없습니다. pending으로 돌리면 실패 사유를 지웁니다.
+func (r *fileRepository) BulkUpdateStatus(ctx context.Context, fromStatus, toStatus string, olderThan time.Time) (int64, error) {
+	if !model.IsValidFileStatus(fromStatus) || !model.IsValidFileStatus(toStatus) {
+		return 0, fmt.Errorf("%w: %s → %s: %w", ErrInvalidStatusTransition, fromStatus, toStatus, model.ErrInvalidFileStatus)
 	}
 
-	// 상태별 파일 목록 조회
-	err := r.db.Preload("EncryptionMetadata").
-		Where("status = ?", status).
-		Offset(offset).
-		Limit(limit).
-		Order("created_at DESC").
-		Find(&files).Error
-	if err != nil {
-		return nil, 0, fmt.Errorf("상태별 파일 목록 조회 실패: %w", err)
+	if fromStatus == toStatus || toStatus == model.FileStatusEncrypted {
+		return 0, fmt.Errorf("%w: %s → %s", ErrInvalidStatusTransition, fromStatus, toStatus)
 	}
 
-	return files, total, nil
-}
+	if olderThan.IsZero() {
+		return 0, fmt.Errorf("%w: 기준 시각이 필요합니다", ErrInvalidStatusTransition)
+	}
 
-// GetByChecksumMD5 MD5 체크섬으로 파일을 조회합니다 (중복 검사용)
-func (r *fileRepository) GetByChecksumMD5(checksum string) (*model.File, error) {
-	if checksum == "" {
-		return nil, fmt.Errorf("체크섬 값이 필요합니다")
+	updates := map[string]any{
+		"status":     toStatus,
+		"updated_at": r.clock.Now().UTC(),
+		"updated_by": model.ActorFromContext(ctx),
+		"version":    gorm.Expr("version + 1"),
+	}
+	if toStatus == model.FileStatusPending {
+		updates["failure_reason"] = ""
 	}
 
-	var file model.File
-	err := r.db.Preload("EncryptionMetadata").
-		Where("checksum_md5 = ?", checksum).
-		First(&file).Error
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, nil // 중복이 없음을 나타내기 위해 nil 반환
-		}
-		return nil, fmt.Errorf("체크섬 조회 실패: %w", err)
+	result := r.db.WithContext(ctx).Model(&model.File{}).
+		Where("status = ? AND updated_at < ?", fromStatus, olderThan.UTC()).
+		UpdateColumns(updates)
+	if result.Error != nil {
+		return 0, fmt.Errorf("파일 상태 일괄 변경 실패: %w", translateError(result.Error))
 	}
 
-	return &file, nil
+	return result.RowsAffected, nil
 }
 
-// Exists 파일 존재 여부를 확인합니다
-func (r *fileRepository) Exists(id uint) (bool, error) {
+// Delete 파일을 삭제합니다 (소프트 삭제)
+//
+// 암호화 메타데이터와 키 슬롯, 태그 연결은 지우지 않으므로 Restore로 복구하면
+// 그대로 복호화할 수 있습니다. 메타데이터까지 지우는 것은 Purge뿐입니다.
+func (r *fileRepository) Delete(ctx context.Context, id uint) error {
 	if id == 0 {
-		return false, fmt.Errorf("유효하지 않은 파일 ID입니다")
+		return invalidID("파일")
 	}
 
-	var count int64
-	err := r.db.Model(&model.File{}).Where("id = ?", id).Count(&count).Error
+	// 파일 존재 여부 확인
+	exists, err := r.Exists(ctx, id)
 	if err != nil {
-		return false, fmt.Errorf("파일 존재 확인 실패: %w", err)
+		return fmt.Errorf("파일 존재 확인 실패: %w", err)
 	}
 
-	return count > 0, nil
+	if !exists {
+		return fmt.Errorf("삭제할 파일을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+	}
+
+	// 소프트 삭제 실행 (활동 피드가 삭제한 행위자를 알 수 있도록 수정자도 기록)
+	err = r.db.WithContext(ctx).Model(&model.File{}).
+		Where("id = ?", id).
+		UpdateColumns(r.softDeleteColumns(ctx)).Error
+	if err != nil {
+		return fmt.Errorf("파일 삭제 실패: %w", err)
+	}
+
+	return nil
 }
 
-// Count 전체 파일 수를 반환합니다
-func (r *fileRepository) Count() (int64, error) {
-	var count int64
-	err := r.db.Model(&model.File{}).Count(&count).Error
+// softDeleteColumns 소프트 삭제할 때 바꾸는 컬럼 (삭제 시각과 컨텍스트의 행위자)
+//
+// gorm의 Delete는 deleted_at만 바꾸므로 직접 갱신합니다. 기준 시각과 비교하는
+// 영구 삭제가 어긋나지 않도록 삭제 시각은 UTC로 기록하고, 내용이 바뀐 것이
+// 아니므로 updated_at과 버전은 그대로 둡니다.
+func (r *fileRepository) softDeleteColumns(ctx context.Context) map[string]any {
+	return map[string]any{
+		"deleted_at": r.clock.Now().UTC(),
+		"updated_by": model.ActorFromContext(ctx),
+	}
+}
+
+// DeleteBatch 여러 파일을 한 문장으로 소프트 삭제하고 삭제한 수를 반환합니다
+//
+// 삭제되지 않은 파일만 대상이며, 없거나 이미 삭제된 ID가 있으면 나머지를 삭제한 뒤
+// 삭제 수와 함께 *MissingIDsError를 반환합니다. 빈 목록이나 0인 ID가 있으면 아무것도
+// 삭제하지 않습니다. 암호화 메타데이터와 키 슬롯은 Delete와 마찬가지로 보존합니다.
+func (r *fileRepository) DeleteBatch(ctx context.Context, ids []uint) (int64, error) {
+	unique, err := uniqueIDs(ids)
 	if err != nil {
-		return 0, fmt.Errorf("파일 카운트 조회 실패: %w", err)
+		return 0, fmt.Errorf("파일 일괄 삭제 실패: %w", err)
 	}
 
-	return count, nil
+	var deleted int64
+	var missing []uint
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var found []uint
+		if err := tx.Model(&model.File{}).Where("id IN ?", unique).Pluck("id", &found).Error; err != nil {
+			return fmt.Errorf("파일 존재 확인 실패: %w", err)
+		}
+
+		existing := make(map[uint]struct{}, len(found))
+		for _, id := range found {
+			existing[id] = struct{}{}
+		}
+		for _, id := range unique {
+			if _, ok := existing[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+
+		if len(found) == 0 {
+			return nil
+		}
+
+		result := tx.Model(&model.File{}).Where("id IN ?", found).UpdateColumns(r.softDeleteColumns(ctx))
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("파일 일괄 삭제 실패: %w", err)
+	}
+
+	if len(missing) > 0 {
+		return deleted, &MissingIDsError{IDs: missing}
+	}
+
+	return deleted, nil
+}
+
+// Restore 소프트 삭제된 파일 레코드를 복구합니다
+//
+// 휴지통에 없는(삭제되지 않았거나 영구 삭제된) 파일은 model.ErrRecordNotFound를,
+// 같은 암호화 경로를 다른 활성 파일이 쓰고 있으면 ErrRestoreConflict를 감싼 에러를 반환합니다.
+// 경로 유일성은 DB 제약으로도 보장하지만, 제약 없이 만들어진 DB나 외부에서 옮긴
+// 레코드에서도 두 레코드가 같은 암호화본을 가리키지 않도록 복구 전에 확인합니다.
+func (r *fileRepository) Restore(ctx context.Context, id uint) error {
+	if id == 0 {
+		return invalidID("파일")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var file model.File
+		err := tx.Unscoped().Select("id", "encrypted_path").
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			First(&file).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("삭제된 파일을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+			}
+			return fmt.Errorf("삭제된 파일 조회 실패: %w", err)
+		}
+
+		var occupant model.File
+		err = tx.Select("id").
+			Where("encrypted_path = ? AND id <> ?", file.EncryptedPath, id).
+			Take(&occupant).Error
+		switch {
+		case err == nil:
+			return fmt.Errorf("%w: ID %d (사용 중인 파일 ID %d)", ErrRestoreConflict, id, occupant.ID)
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("암호화 경로 중복 확인 실패: %w", err)
+		}
+
+		// 훅(검증)을 거치지 않도록 deleted_at 컬럼만 직접 갱신
+		result := tx.Unscoped().Model(&model.File{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			UpdateColumns(map[string]any{"deleted_at": nil, "updated_at": r.clock.Now().UTC(), "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return fmt.Errorf("파일 복구 실패: %w", translateError(result.Error))
+		}
+
+		return nil
+	})
+}
+
+// GetDeleted 휴지통(소프트 삭제된 파일)을 최근 삭제순으로 페이지네이션 조회합니다
+func (r *fileRepository) GetDeleted(ctx context.Context, offset, limit int) ([]*model.File, int64, error) {
+	offset, limit = r.normalizePagination(offset, limit)
+
+	var files []*model.File
+	var total int64
+
+	trash := func() *gorm.DB {
+		return r.db.WithContext(ctx).Unscoped().Model(&model.File{}).Where("deleted_at IS NOT NULL")
+	}
+
+	if err := trash().Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("삭제된 파일 카운트 조회 실패: %w", err)
+	}
+
+	err := r.preloadMetadata(trash()).
+		Offset(offset).
+		Limit(limit).
+		Order("deleted_at DESC, id DESC").
+		Find(&files).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("삭제된 파일 목록 조회 실패: %w", err)
+	}
+
+	return files, total, nil
+}
+
+// Purge 파일과 암호화 메타데이터, 키 슬롯, 태그 연결을 영구 삭제합니다 (소프트 삭제된 레코드 포함)
+func (r *fileRepository) Purge(ctx context.Context, id uint) error {
+	if id == 0 {
+		return invalidID("파일")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("file_id = ?", id).Delete(&fileTag{}).Error; err != nil {
+			return fmt.Errorf("태그 연결 삭제 실패: %w", err)
+		}
+
+		if err := tx.Where("file_id = ?", id).Delete(&model.KeySlot{}).Error; err != nil {
+			return fmt.Errorf("키 슬롯 삭제 실패: %w", err)
+		}
+
+		if err := tx.Where("file_id = ?", id).Delete(&model.EncryptionMetadata{}).Error; err != nil {
+			return fmt.Errorf("암호화 메타데이터 삭제 실패: %w", err)
+		}
+
+		result := tx.Unscoped().Delete(&model.File{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("파일 영구 삭제 실패: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("삭제할 파일을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+		}
+
+		return nil
+	})
+}
+
+// PurgeDeletedOlderThan cutoff 이전에 소프트 삭제된 파일을 암호화 메타데이터, 키 슬롯, 태그 연결과 함께 영구 삭제합니다
+//
+// 키 정보가 남지 않도록 한 트랜잭션에서 함께 지우고 삭제한 파일 수를 반환합니다.
+// 다른 레코드가 blob으로 참조 중인 파일은 참조가 사라질 때까지 남겨 둡니다.
+// 디스크의 암호화본은 지우지 않으므로 호출자가 먼저 경로를 확보해 정리해야 합니다.
+func (r *fileRepository) PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	if cutoff.IsZero() {
+		return 0, fmt.Errorf("영구 삭제 기준 시각이 필요합니다")
+	}
+
+	var purged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 삭제 시각은 UTC로 저장되므로 기준 시각도 UTC로 비교
+		expired := tx.Unscoped().Model(&model.File{}).Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff.UTC()).
+			Where("id NOT IN (?)", tx.Unscoped().Model(&model.File{}).Select("blob_file_id").Where("blob_file_id IS NOT NULL"))
+
+		var ids []uint
+		if err := expired.Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("영구 삭제 대상 조회 실패: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for start := 0; start < len(ids); start += idBatchSize {
+			chunk := ids[start:min(start+idBatchSize, len(ids))]
+
+			if err := tx.Where("file_id IN ?", chunk).Delete(&fileTag{}).Error; err != nil {
+				return fmt.Errorf("태그 연결 삭제 실패: %w", err)
+			}
+
+			if err := tx.Where("file_id IN ?", chunk).Delete(&model.KeySlot{}).Error; err != nil {
+				return fmt.Errorf("키 슬롯 삭제 실패: %w", err)
+			}
+
+			if err := tx.Where("file_id IN ?", chunk).Delete(&model.EncryptionMetadata{}).Error; err != nil {
+				return fmt.Errorf("암호화 메타데이터 삭제 실패: %w", err)
+			}
+
+			result := tx.Unscoped().Where("id IN ?", chunk).Delete(&model.File{})
+			if result.Error != nil {
+				return fmt.Errorf("파일 영구 삭제 실패: %w", result.Error)
+			}
+			purged += result.RowsAffected
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// CountBlobReferences 해당 파일의 암호화 blob을 참조하는 레코드 수를 반환합니다
+func (r *fileRepository) CountBlobReferences(ctx context.Context, blobFileID uint) (int64, error) {
+	if blobFileID == 0 {
+		return 0, invalidID("파일")
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.File{}).Where("blob_file_id = ?", blobFileID).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("blob 참조 카운트 조회 실패: %w", err)
+	}
+
+	return count, nil
+}
+
+// UsageByVolume 볼륨별 파일 수와 원본/암호화본 크기 합계를 조회합니다
+//
+// blob을 소유한 레코드만 세며, 소프트 삭제된 레코드도 암호화본이 디스크에 남아
+// 있으므로 포함합니다. 볼륨이 없는 레코드(볼륨 도입 전 파일)는 제외합니다.
+func (r *fileRepository) UsageByVolume(ctx context.Context) ([]VolumeUsage, error) {
+	var usage []VolumeUsage
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.File{}).
+		Select("volume_id, COUNT(*) AS files, COALESCE(SUM(size), 0) AS bytes, COALESCE(SUM(encrypted_size), 0) AS encrypted_bytes").
+		Where("volume_id <> '' AND blob_file_id IS NULL").
+		Group("volume_id").
+		Order("volume_id").
+		Scan(&usage).Error
+	if err != nil {
+		return nil, fmt.Errorf("볼륨별 사용량 조회 실패: %w", err)
+	}
+
+	return usage, nil
+}
+
+// GetByVolume 볼륨에 암호화본이 있는 파일을 크기가 큰 순으로 조회합니다
+//
+// blob 참조 레코드는 암호화본을 소유하지 않으므로 제외합니다.
+func (r *fileRepository) GetByVolume(ctx context.Context, volumeID string, offset, limit int) ([]*model.File, int64, error) {
+	if volumeID == "" {
+		return nil, 0, fmt.Errorf("볼륨 ID가 필요합니다")
+	}
+
+	offset, limit = r.normalizePagination(offset, limit)
+
+	var files []*model.File
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.File{}).Where("volume_id = ? AND blob_file_id IS NULL", volumeID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("볼륨별 파일 카운트 조회 실패: %w", err)
+	}
+
+	err := r.preloadMetadata(r.db.WithContext(ctx)).
+		Where("volume_id = ? AND blob_file_id IS NULL", volumeID).
+		Offset(offset).
+		Limit(limit).
+		Order("size DESC, id").
+		Find(&files).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("볼륨별 파일 목록 조회 실패: %w", err)
+	}
+
+	return files, total, nil
+}
+
+// GetByStatus 상태별로 파일을 sort 순서로 조회합니다 (빈 SortOption은 최신순)
+func (r *fileRepository) GetByStatus(ctx context.Context, status string, offset, limit int, sort SortOption) ([]*model.File, int64, error) {
+	if status == "" {
+		return nil, 0, fmt.Errorf("상태 값이 필요합니다")
+	}
+
+	if !model.IsValidFileStatus(status) {
+		return nil, 0, fmt.Errorf("유효하지 않은 파일 상태입니다: %s", status)
+	}
+
+	return r.Find(ctx, FileFilter{Status: status}, Pagination{Offset: offset, Limit: limit}, sort)
+}
+
+// GetStalePending olderThan 넘게 갱신되지 않은 pending 파일을 오래된 순으로 조회합니다
+//
+// 암호화 도중 서버가 죽으면 파일이 pending으로 남으므로, 정리 작업이 이 목록을
+// 다시 처리하거나 실패로 표시합니다. olderThan이 0 이하이면 에러를 반환합니다.
+func (r *fileRepository) GetStalePending(ctx context.Context, olderThan time.Duration, offset, limit int) ([]*model.File, int64, error) {
+	if olderThan <= 0 {
+		return nil, 0, fmt.Errorf("방치 기준 시간은 0보다 커야 합니다: %s", olderThan)
+	}
+
+	cutoff := r.clock.Now().Add(-olderThan).UTC()
+
+	// 갱신 시각은 생성 시각보다 빠를 수 없으므로 created_at 조건을 함께 걸어
+	// idx_files_status_created_at 범위 안에서만 찾음
+	where := func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("status = ? AND created_at < ?", model.FileStatusPending, cutoff).
+			Where("updated_at < ?", cutoff)
+	}
+
+	offset, limit = r.normalizePagination(offset, limit)
+
+	var total int64
+	if err := where(r.db.WithContext(ctx).Model(&model.File{})).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("방치된 대기 파일 카운트 조회 실패: %w", err)
+	}
+
+	var files []*model.File
+	err := where(r.preloadMetadata(r.db.WithContext(ctx))).
+		Order("created_at ASC").
+		Order("id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&files).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("방치된 대기 파일 목록 조회 실패: %w", err)
+	}
+
+	return files, total, nil
+}
+
+// GetNotAccessedSince cutoff 이후 한 번도 복호화되지 않은 파일을 접근이 오래된 순으로 조회합니다
+//
+// 보존 정책("90일 동안 복호화되지 않은 파일 보관")용이며, 접근 기록이 없는
+// 파일(NULL)은 어떤 cutoff보다도 오래된 것으로 보고 가장 먼저 반환합니다.
+// cutoff가 0이면 에러를 반환합니다.
+func (r *fileRepository) GetNotAccessedSince(ctx context.Context, cutoff time.Time, offset, limit int) ([]*model.File, int64, error) {
+	if cutoff.IsZero() {
+		return nil, 0, errors.New("접근 기준 시각이 필요합니다")
+	}
+
+	where := func(query *gorm.DB) *gorm.DB {
+		return query.Where("last_accessed_at IS NULL OR last_accessed_at < ?", cutoff.UTC())
+	}
+
+	offset, limit = r.normalizePagination(offset, limit)
+
+	var total int64
+	if err := where(r.db.WithContext(ctx).Model(&model.File{})).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("미접근 파일 카운트 조회 실패: %w", err)
+	}
+
+	// NULL 정렬 위치는 DB마다 다르므로 접근 기록이 없는 파일을 명시적으로 앞에 둠
+	var files []*model.File
+	err := where(r.preloadMetadata(r.db.WithContext(ctx))).
+		Order("last_accessed_at IS NOT NULL").
+		Order("last_accessed_at ASC").
+		Order("id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&files).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("미접근 파일 목록 조회 실패: %w", err)
+	}
+
+	return files, total, nil
+}
+
+// GetByMimeType MIME 타입으로 파일을 최신순 조회합니다
+//
+// "application/pdf"처럼 subtype까지 지정하면 정확히 일치하는 파일을, "image/"나
+// "image/*"처럼 type만 지정하면 그 계열 전체를 반환합니다. 대소문자는 무시하며
+// 형식이 맞지 않으면 ErrInvalidMimeFilter를 반환합니다.
+func (r *fileRepository) GetByMimeType(ctx context.Context, mimeType string, offset, limit int) ([]*model.File, int64, error) {
+	filter := strings.ToLower(strings.TrimSpace(mimeType))
+	if len(filter) > model.MaxMimeTypeLength || !mimeFilterPattern.MatchString(filter) {
+		return nil, 0, fmt.Errorf("%w: %q", ErrInvalidMimeFilter, mimeType)
+	}
+
+	where := func(query *gorm.DB) *gorm.DB {
+		if family, ok := strings.CutSuffix(strings.TrimSuffix(filter, "*"), "/"); ok {
+			return query.Where("mime_type LIKE ? ESCAPE '\\'", escapeLike(family)+"/%")
+		}
+		return query.Where("mime_type = ? COLLATE NOCASE", filter)
+	}
+
+	offset, limit = r.normalizePagination(offset, limit)
+
+	var total int64
+	if err := where(r.db.WithContext(ctx).Model(&model.File{})).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("MIME 타입별 파일 카운트 조회 실패: %w", err)
+	}
+
+	var files []*model.File
+	err := where(r.preloadMetadata(r.db.WithContext(ctx))).
+		Order("created_at DESC").
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&files).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("MIME 타입별 파일 목록 조회 실패: %w", err)
+	}
+
+	return files, total, nil
+}
+
+// GetByDateRange 생성 시각이 [from, to) 안인 파일을 최신순 조회합니다
+//
+// to가 zero이면 현재 시각까지 조회하며, 두 시각은 UTC로 바꿔 비교합니다. from이
+// zero이거나 to보다 빠르지 않으면 ErrInvalidDateRange를 반환합니다. status가
+// 비어 있지 않으면 해당 상태의 파일만 반환합니다 ("이번 주 실패한 파일").
+func (r *fileRepository) GetByDateRange(ctx context.Context, from, to time.Time, status string, offset, limit int) ([]*model.File, int64, error) {
+	if to.IsZero() {
+		to = r.clock.Now()
+	}
+
+	if from.IsZero() || !from.Before(to) {
+		return nil, 0, fmt.Errorf("%w: %s ~ %s", ErrInvalidDateRange, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	if status != "" && !model.IsValidFileStatus(status) {
+		return nil, 0, fmt.Errorf("유효하지 않은 파일 상태입니다: %s", status)
+	}
+
+	// 상태가 있으면 idx_files_status_created_at, 없으면 idx_files_created_at 사용
+	filter := FileFilter{Status: status, CreatedAfter: from, CreatedBefore: to}
+	return r.Find(ctx, filter, Pagination{Offset: offset, Limit: limit}, SortOption{})
+}
+
+// GetByChecksumMD5 MD5 체크섬으로 파일을 조회합니다 (중복 검사용)
+//
+// 같은 체크섬의 파일이 여러 개면 가장 먼저 생성된 파일을 반환하며, 없으면
+// model.ErrRecordNotFound를 반환합니다.
+func (r *fileRepository) GetByChecksumMD5(ctx context.Context, checksum string) (*model.File, error) {
+	if checksum == "" {
+		return nil, fmt.Errorf("체크섬 값이 필요합니다")
+	}
+
+	var file model.File
+	err := r.preloadMetadata(r.db.WithContext(ctx)).
+		Where("checksum_md5 = ?", checksum).
+		First(&file).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("체크섬 %s인 파일을 찾을 수 없습니다: %w", checksum, model.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("체크섬 조회 실패: %w", err)
+	}
+
+	return &file, nil
+}
+
+// GetAllByChecksum MD5 체크섬이 같은 살아 있는 파일을 모두 생성순(ID 오름차순)으로 조회합니다
+//
+// 첫 번째 파일은 GetByChecksumMD5가 반환하는 파일과 같으며, 없으면 빈 슬라이스를 반환합니다.
+func (r *fileRepository) GetAllByChecksum(ctx context.Context, checksum string) ([]*model.File, error) {
+	if checksum == "" {
+		return nil, fmt.Errorf("체크섬 값이 필요합니다")
+	}
+
+	var files []*model.File
+	err := r.preloadMetadata(r.db.WithContext(ctx)).
+		Where("checksum_md5 = ?", checksum).
+		Order("id").
+		Find(&files).Error
+	if err != nil {
+		return nil, fmt.Errorf("체크섬 %s인 파일 목록 조회 실패: %w", checksum, err)
+	}
+
+	return files, nil
+}
+
+// FindDuplicateChecksums 살아 있는 파일이 minCount개 이상인 체크섬 묶음과 전체 묶음 수를 조회합니다
+//
+// minCount가 2보다 작으면 2를 사용합니다(중복만 조회). 파일 수가 많은 묶음부터,
+// 같으면 크기가 큰 묶음부터 정렬하며 체크섬으로 순서를 고정합니다. 소프트 삭제된
+// 파일은 제외하고 GROUP BY/HAVING으로 DB에서 집계하므로 행을 메모리로 읽지 않습니다.
+func (r *fileRepository) FindDuplicateChecksums(ctx context.Context, minCount, offset, limit int) ([]DuplicateChecksum, int64, error) {
+	if minCount < 2 {
+		minCount = 2
+	}
+	offset, limit = r.normalizePagination(offset, limit)
+
+	groups := r.db.WithContext(ctx).Model(&model.File{}).
+		Group("checksum_md5").
+		Having("COUNT(*) >= ?", minCount)
+
+	var total int64
+	err := r.db.WithContext(ctx).Table("(?) AS duplicate_groups", groups.Select("checksum_md5")).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("중복 체크섬 수 조회 실패: %w", err)
+	}
+
+	var duplicates []DuplicateChecksum
+	err = r.db.WithContext(ctx).Model(&model.File{}).
+		Select("checksum_md5 AS checksum, COUNT(*) AS files, "+
+			"SUM(CASE WHEN blob_file_id IS NULL THEN 1 ELSE 0 END) AS blobs, MAX(size) AS size").
+		Group("checksum_md5").
+		Having("COUNT(*) >= ?", minCount).
+		Order("files DESC, size DESC, checksum_md5").
+		Offset(offset).
+		Limit(limit).
+		Scan(&duplicates).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("중복 체크섬 조회 실패: %w", err)
+	}
+
+	return duplicates, total, nil
+}
+
+// GetByEncryptedPath 암호화 경로로 파일을 조회합니다 (저장소 디렉터리 대조용)
+//
+// 소프트 삭제된 레코드도 복구될 수 있어 암호화본을 계속 소유하므로 함께 조회하며,
+// 호출자는 DeletedAt으로 구분합니다. 없으면 model.ErrRecordNotFound를 반환합니다.
+func (r *fileRepository) GetByEncryptedPath(ctx context.Context, path string) (*model.File, error) {
+	if path == "" || len(path) > model.MaxEncryptedPathLength {
+		return nil, fmt.Errorf("암호화 경로는 1자 이상 %d자 이하여야 합니다", model.MaxEncryptedPathLength)
+	}
+
+	var file model.File
+	err := r.preloadMetadata(r.db.WithContext(ctx).Unscoped()).
+		Where("encrypted_path = ?", path).
+		First(&file).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("암호화 경로 %s인 파일을 찾을 수 없습니다: %w", path, model.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("암호화 경로 조회 실패: %w", err)
+	}
+
+	return &file, nil
+}
+
+// GetByExternalID 외부 시스템 참조 ID로 살아 있는 파일을 조회합니다
+//
+// 참조 ID는 살아 있는 파일 사이에서만 유일하므로 소프트 삭제된 레코드는 조회하지
+// 않습니다. 없으면 model.ErrRecordNotFound를 반환합니다.
+func (r *fileRepository) GetByExternalID(ctx context.Context, externalID string) (*model.File, error) {
+	if externalID == "" || len(externalID) > model.MaxExternalIDLength {
+		return nil, invalidID("외부 참조")
+	}
+
+	var file model.File
+	err := r.preloadMetadata(r.db.WithContext(ctx)).
+		Where("external_id = ?", externalID).
+		First(&file).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("외부 참조 ID %s인 파일을 찾을 수 없습니다: %w", externalID, model.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("외부 참조 ID 조회 실패: %w", err)
+	}
+
+	return &file, nil
+}
+
+// GetByShortCode 짧은 파일 코드로 살아 있는 파일을 조회합니다
+//
+// code는 model.NormalizeShortCode로 정규화하므로 소문자, 하이픈, 혼동 문자(I/L/O)를
+// 그대로 받습니다. 형식이 잘못되면 ErrInvalidID를, 없거나 소프트 삭제된 파일이면
+// model.ErrRecordNotFound를 감싼 에러를 반환합니다.
+func (r *fileRepository) GetByShortCode(ctx context.Context, code string) (*model.File, error) {
+	normalized, err := model.NormalizeShortCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidID, err)
+	}
+
+	var file model.File
+	err = r.preloadMetadata(r.db.WithContext(ctx)).
+		Where("short_code = ?", normalized).
+		First(&file).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("코드 %s인 파일을 찾을 수 없습니다: %w", normalized, model.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("짧은 코드 조회 실패: %w", err)
+	}
+
+	return &file, nil
+}
+
+// ReissueShortCode 살아 있는 파일에 새 짧은 코드를 부여하고 반환합니다
+//
+// 이전 코드는 바로 조회되지 않습니다. 코드는 공유용 별칭이므로 접근 기록처럼
+// 버전과 updated_at을 바꾸지 않습니다. 파일이 없으면 model.ErrRecordNotFound를
+// 감싼 에러를 반환합니다.
+func (r *fileRepository) ReissueShortCode(ctx context.Context, id uint) (string, error) {
+	if id == 0 {
+		return "", invalidID("파일")
+	}
+
+	for attempt := 1; ; attempt++ {
+		code, err := r.newShortCode()
+		if err != nil {
+			return "", err
+		}
+
+		result := r.db.WithContext(ctx).Model(&model.File{}).
+			Where("id = ?", id).
+			UpdateColumn("short_code", code)
+		if result.Error != nil {
+			if isShortCodeCollision(result.Error) && attempt < maxShortCodeAttempts {
+				continue
+			}
+			return "", fmt.Errorf("짧은 코드 재발급 실패: %w", translateError(result.Error))
+		}
+
+		if result.RowsAffected == 0 {
+			return "", fmt.Errorf("코드를 재발급할 파일을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+		}
+
+		return code, nil
+	}
+}
+
+// isShortCodeCollision 짧은 코드 유일 인덱스 위반인지 확인합니다 (다른 제약조건 위반은 다시 시도하지 않음)
+func isShortCodeCollision(err error) bool {
+	return errors.Is(translateError(err), ErrDuplicate) && strings.Contains(err.Error(), "short_code")
+}
+
+// activitySQL 종류별 SELECT를 UNION ALL로 묶은 활동 이벤트 쿼리 (각 SELECT 끝에 소유자 조건을 붙임)
+//
+// 삭제된 파일도 업로드/실패 이벤트는 남아야 하므로 deleted_at으로 거르지 않습니다.
+const activitySQL = `
+SELECT 'uploaded' AS type, id AS file_id, original_name AS file_name, created_at AS occurred_at, created_by AS actor
+FROM files WHERE 1 = 1%[1]s
+UNION ALL
+SELECT 'failed', id, original_name, updated_at, updated_by
+FROM files WHERE status = 'failed'%[1]s
+UNION ALL
+SELECT 'deleted', id, original_name, deleted_at, updated_by
+FROM files WHERE deleted_at IS NOT NULL%[1]s`
+
+// RecentActivity 업로드, 실패, 삭제 이벤트를 최신순으로 페이지네이션 조회합니다
+//
+// ownerID가 0이 아니면 그 사용자가 소유한 파일의 이벤트만 조회합니다. 컬럼마다
+// 기록한 시간대가 다를 수 있어 문자열이 아닌 julianday로 비교하고, 시각이 같으면
+// 파일 ID 내림차순으로 정렬합니다. total은 페이지와 무관한 전체 이벤트 수입니다.
+func (r *fileRepository) RecentActivity(ctx context.Context, ownerID uint, page Pagination) ([]FileActivity, int64, error) {
+	offset, limit := r.normalizePagination(page.Offset, page.Limit)
+
+	ownerCond := ""
+	var args []any
+	if ownerID != 0 {
+		ownerCond = " AND owner_id = ?"
+		args = []any{ownerID, ownerID, ownerID}
+	}
+	events := fmt.Sprintf(activitySQL, ownerCond)
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw("SELECT COUNT(*) FROM ("+events+")", args...).Scan(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("활동 카운트 조회 실패: %w", err)
+	}
+
+	activities := []FileActivity{}
+	if total == 0 {
+		return activities, 0, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Raw("SELECT * FROM ("+events+")\nORDER BY julianday(occurred_at) DESC, file_id DESC LIMIT ? OFFSET ?", append(args, limit, offset)...).
+		Scan(&activities).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("활동 목록 조회 실패: %w", err)
+	}
+
+	for i := range activities {
+		activities[i].OccurredAt = activities[i].OccurredAt.UTC()
+	}
+
+	return activities, total, nil
+}
+
+// fileTag file_tags 연결 테이블의 행 (model.File.Tags 관계가 만드는 테이블)
+type fileTag struct {
+	FileID uint
+	TagID  uint
+}
+
+// TableName GORM 테이블명을 명시적으로 지정
+func (fileTag) TableName() string {
+	return "file_tags"
+}
+
+// AddTags 파일에 태그를 붙입니다 (없는 태그는 만들고 이미 붙은 태그는 건너뜀)
+//
+// 이름은 model.NormalizeTagName으로 정규화하므로 "Tax-2024"와 " tax-2024 "는 같은
+// 태그입니다. 이름 하나라도 잘못되면 아무것도 붙이지 않고 model의 검증 에러를 감싼
+// 에러를, 없거나 삭제된 파일이면 model.ErrRecordNotFound를 감싼 에러를 반환합니다.
+// 태그는 파일 내용이 아니므로 버전과 updated_at을 바꾸지 않습니다.
+func (r *fileRepository) AddTags(ctx context.Context, fileID uint, tags []string) error {
+	if fileID == 0 {
+		return invalidID("파일")
+	}
+
+	names := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		name, err := model.NormalizeTagName(tag)
+		if err != nil {
+			return fmt.Errorf("태그 %q 추가 실패: %w", tag, err)
+		}
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var file model.File
+		if err := tx.Select("id").First(&file, fileID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("태그를 붙일 파일을 찾을 수 없습니다: ID %d: %w", fileID, model.ErrRecordNotFound)
+			}
+			return fmt.Errorf("파일 조회 실패: %w", err)
+		}
+
+		// 이미 있는 이름은 건너뛰므로 다른 요청이 같은 태그를 먼저 만들어도 충돌하지 않음
+		newTags := make([]*model.Tag, len(names))
+		for i, name := range names {
+			newTags[i] = &model.Tag{Name: name}
+		}
+		err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+			Create(&newTags).Error
+		if err != nil {
+			return fmt.Errorf("태그 생성 실패: %w", translateError(err))
+		}
+
+		var tagIDs []uint
+		if err := tx.Model(&model.Tag{}).Where("name IN ?", names).Pluck("id", &tagIDs).Error; err != nil {
+			return fmt.Errorf("태그 조회 실패: %w", err)
+		}
+
+		links := make([]fileTag, len(tagIDs))
+		for i, tagID := range tagIDs {
+			links[i] = fileTag{FileID: fileID, TagID: tagID}
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error; err != nil {
+			return fmt.Errorf("태그 연결 실패: %w", translateError(err))
+		}
+
+		return nil
+	})
+}
+
+// RemoveTag 파일에서 태그를 뗍니다 (태그 자체는 다른 파일을 위해 남겨 둠)
+//
+// 파일에 붙어 있지 않은 태그면 model.ErrRecordNotFound를 감싼 에러를 반환합니다.
+func (r *fileRepository) RemoveTag(ctx context.Context, fileID uint, tag string) error {
+	if fileID == 0 {
+		return invalidID("파일")
+	}
+
+	name, err := model.NormalizeTagName(tag)
+	if err != nil {
+		return fmt.Errorf("태그 %q 제거 실패: %w", tag, err)
+	}
+
+	tagIDs := r.db.WithContext(ctx).Model(&model.Tag{}).Select("id").Where("name = ?", name)
+	result := r.db.WithContext(ctx).
+		Where("file_id = ? AND tag_id IN (?)", fileID, tagIDs).
+		Delete(&fileTag{})
+	if result.Error != nil {
+		return fmt.Errorf("태그 제거 실패: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("파일에 붙은 태그를 찾을 수 없습니다: ID %d, 태그 %q: %w", fileID, name, model.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// GetByTag 태그가 붙은 파일을 최신순으로 페이지네이션 조회합니다 (삭제된 파일 제외)
+//
+// 태그 이름은 AddTags와 같은 방식으로 정규화해 비교합니다.
+func (r *fileRepository) GetByTag(ctx context.Context, tag string, offset, limit int) ([]*model.File, int64, error) {
+	name, err := model.NormalizeTagName(tag)
+	if err != nil {
+		return nil, 0, fmt.Errorf("태그별 파일 조회 실패: %w", err)
+	}
+
+	return r.Find(ctx, FileFilter{Tag: name}, Pagination{Offset: offset, Limit: limit}, SortOption{})
+}
+
+// SearchMetadata 사용자 메타데이터의 key 값이 value인 파일을 최신순으로 페이지네이션 조회합니다 (삭제된 파일 제외)
+//
+// key는 '.'으로 중첩 키를 가리키고(예: "client.name"), 값은 json_extract 결과를
+// 문자열로 바꿔 비교합니다(숫자 42는 "42", true는 "1"). value가 비어 있으면 키가
+// 있는 파일을 모두 찾습니다. key가 비어 있거나 형식이 잘못되었으면
+// ErrInvalidFileFilter를 감싼 에러를 반환합니다.
+func (r *fileRepository) SearchMetadata(ctx context.Context, key, value string, offset, limit int) ([]*model.File, int64, error) {
+	if key == "" {
+		return nil, 0, fmt.Errorf("%w: 메타데이터 키가 필요합니다", ErrInvalidFileFilter)
+	}
+
+	filter := FileFilter{MetadataKey: key, MetadataValue: value}
+	return r.Find(ctx, filter, Pagination{Offset: offset, Limit: limit}, SortOption{})
+}
+
+// CreateFolder 폴더를 생성합니다
+//
+// 이름과 깊이는 model.Folder 훅이 검증하므로 잘못되면 model의 검증 에러를, 상위
+// 폴더가 없으면 model.ErrFolderParentNotFound를, 같은 위치에 같은 이름의 폴더가
+// 있으면 ErrDuplicate를 감싼 에러를 반환합니다.
+func (r *fileRepository) CreateFolder(ctx context.Context, folder *model.Folder) error {
+	if folder == nil {
+		return fmt.Errorf("폴더 정보가 nil입니다")
+	}
+
+	if folder.ParentID != nil && *folder.ParentID == 0 {
+		return invalidID("상위 폴더")
+	}
+
+	if err := r.db.WithContext(ctx).Create(folder).Error; err != nil {
+		return fmt.Errorf("폴더 생성 실패: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// EnsureFolderPath 상대 경로의 폴더들을 parentID 아래에 차례로 만들고 마지막 폴더를 반환합니다
+//
+// 경로는 model.SplitFolderPath로 나누며 이미 있는 폴더는 그대로 사용하므로, 디렉터리의
+// 파일마다 호출해도 같은 폴더가 두 번 생기지 않습니다. parentID가 nil이면 최상위부터
+// 만들고, 경로가 비어 있으면 상위 폴더(nil이면 nil)를 반환합니다. 중간에 실패하면
+// 아무 폴더도 만들지 않습니다.
+func (r *fileRepository) EnsureFolderPath(ctx context.Context, parentID *uint, path string) (*model.Folder, error) {
+	if parentID != nil && *parentID == 0 {
+		return nil, invalidID("상위 폴더")
+	}
+
+	names, err := model.SplitFolderPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("폴더 경로 생성 실패: %w", err)
+	}
+
+	var folder *model.Folder
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if parentID != nil {
+			var parent model.Folder
+			if err := tx.First(&parent, *parentID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("상위 폴더를 찾을 수 없습니다: ID %d: %w", *parentID, model.ErrRecordNotFound)
+				}
+				return fmt.Errorf("상위 폴더 조회 실패: %w", err)
+			}
+			folder = &parent
+		}
+
+		current := parentID
+		for _, name := range names {
+			next, err := findChildFolder(tx, current, name)
+			if err != nil {
+				return err
+			}
+
+			if next == nil {
+				next = &model.Folder{Name: name, ParentID: current}
+				if err := tx.Create(next).Error; err != nil {
+					return fmt.Errorf("폴더 %q 생성 실패: %w", name, translateError(err))
+				}
+			}
+
+			folder = next
+			current = &next.ID
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return folder, nil
+}
+
+// findChildFolder 상위 폴더(nil이면 최상위) 바로 아래에서 이름이 같은 폴더를 찾습니다 (없으면 nil)
+func findChildFolder(tx *gorm.DB, parentID *uint, name string) (*model.Folder, error) {
+	query := tx.Where("name = ?", name)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+
+	var folder model.Folder
+	if err := query.Take(&folder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("폴더 %q 조회 실패: %w", name, err)
+	}
+
+	return &folder, nil
+}
+
+// MoveFolder 폴더를 하위 폴더와 함께 parentID 아래로 옮깁니다 (nil이면 최상위로)
+//
+// 자기 자신이나 하위 폴더 아래로 옮기면 model.ErrFolderCycle을, 옮긴 뒤 가장 깊은
+// 하위 폴더가 model.MaxFolderDepth를 넘으면 model.ErrFolderTooDeep을, 옮길 위치에
+// 같은 이름의 폴더가 있으면 ErrDuplicate를 감싼 에러를 반환합니다.
+func (r *fileRepository) MoveFolder(ctx context.Context, id uint, parentID *uint) error {
+	if id == 0 {
+		return invalidID("폴더")
+	}
+
+	if parentID != nil && *parentID == 0 {
+		return invalidID("상위 폴더")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var folder model.Folder
+		if err := tx.First(&folder, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("옮길 폴더를 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+			}
+			return fmt.Errorf("폴더 조회 실패: %w", err)
+		}
+
+		folder.ParentID = parentID
+		if err := tx.Model(&folder).Select("parent_id", "updated_at").Updates(&folder).Error; err != nil {
+			return fmt.Errorf("폴더 이동 실패: %w", translateError(err))
+		}
+
+		return nil
+	})
+}
+
+// GetFolderTree 폴더 트리를 조회합니다 (Children을 채운 최상위 폴더 목록)
+//
+// rootID가 0이면 모든 최상위 폴더를, 아니면 그 폴더 하나를 하위 폴더와 함께
+// 반환합니다. 형제 폴더는 이름순이며 파일은 채우지 않습니다. rootID의 폴더가 없으면
+// model.ErrRecordNotFound를 감싼 에러를 반환합니다.
+func (r *fileRepository) GetFolderTree(ctx context.Context, rootID uint) ([]*model.Folder, error) {
+	query := r.db.WithContext(ctx).Order("name, id")
+	if rootID != 0 {
+		query = query.Where("id IN (?)", gorm.Expr(folderSubtreeSQL, rootID))
+	}
+
+	var folders []*model.Folder
+	if err := query.Find(&folders).Error; err != nil {
+		return nil, fmt.Errorf("폴더 트리 조회 실패: %w", err)
+	}
+
+	if rootID != 0 && len(folders) == 0 {
+		return nil, fmt.Errorf("폴더를 찾을 수 없습니다: ID %d: %w", rootID, model.ErrRecordNotFound)
+	}
+
+	byID := make(map[uint]*model.Folder, len(folders))
+	for _, folder := range folders {
+		byID[folder.ID] = folder
+	}
+
+	roots := make([]*model.Folder, 0)
+	for _, folder := range folders {
+		var parent *model.Folder
+		if folder.ID != rootID && folder.ParentID != nil {
+			parent = byID[*folder.ParentID]
+		}
+
+		if parent != nil {
+			parent.Children = append(parent.Children, folder)
+		} else {
+			roots = append(roots, folder)
+		}
+	}
+
+	return roots, nil
+}
+
+// GetFilesInFolder 폴더에 있는 파일을 최신순으로 페이지네이션 조회합니다 (삭제된 파일 제외)
+//
+// recursive면 모든 하위 폴더의 파일도 포함합니다. 폴더가 없으면
+// model.ErrRecordNotFound를 감싼 에러를 반환합니다.
+func (r *fileRepository) GetFilesInFolder(ctx context.Context, folderID uint, recursive bool, page Pagination) ([]*model.File, int64, error) {
+	if folderID == 0 {
+		return nil, 0, invalidID("폴더")
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.Folder{}).Where("id = ?", folderID).Count(&count).Error; err != nil {
+		return nil, 0, fmt.Errorf("폴더 조회 실패: %w", err)
+	}
+
+	if count == 0 {
+		return nil, 0, fmt.Errorf("폴더를 찾을 수 없습니다: ID %d: %w", folderID, model.ErrRecordNotFound)
+	}
+
+	return r.Find(ctx, FileFilter{FolderID: folderID, Recursive: recursive}, page, SortOption{})
+}
+
+// FindSimilar 기준 파일과 SimHash 해밍 거리가 maxDistance 이하인 파일을 조회합니다
+//
+// 해밍 거리는 SQL로 색인할 수 없으므로 시그니처 열(id, sim_hash)만 순서대로 읽으며
+// 비교하고, 일치한 파일만 메타데이터와 함께 다시 조회합니다. 결과는 거리, ID순이며
+// 최대 MaxPageSize건입니다. 기준 파일이 없으면 model.ErrRecordNotFound를,
+// 시그니처가 없으면 ErrNoSimilaritySignature를 감싼 에러를 반환합니다.
+func (r *fileRepository) FindSimilar(ctx context.Context, fileID uint, maxDistance int) ([]SimilarFile, error) {
+	if fileID == 0 {
+		return nil, invalidID("파일")
+	}
+	if maxDistance < 0 || maxDistance > simhash.Bits {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidSimilarityDistance, maxDistance)
+	}
+
+	var target model.File
+	if err := r.db.WithContext(ctx).Select("id", "sim_hash").First(&target, fileID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("파일을 찾을 수 없습니다: ID %d: %w", fileID, model.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("파일 조회 실패: %w", err)
+	}
+
+	signature, ok := target.SimilaritySignature()
+	if !ok {
+		return nil, fmt.Errorf("ID %d: %w", fileID, ErrNoSimilaritySignature)
+	}
+
+	matches, err := r.scanSimilar(ctx, fileID, signature, maxDistance)
+	if err != nil {
+		return nil, fmt.Errorf("유사 파일 조회 실패: %w", err)
+	}
+	if len(matches) == 0 {
+		return []SimilarFile{}, nil
+	}
+
+	ids := make([]uint, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+
+	var files []*model.File
+	if err := r.preloadMetadata(r.db.WithContext(ctx)).Where("id IN ?", ids).Find(&files).Error; err != nil {
+		return nil, fmt.Errorf("유사 파일 조회 실패: %w", err)
+	}
+
+	byID := make(map[uint]*model.File, len(files))
+	for _, file := range files {
+		byID[file.ID] = file
+	}
+
+	// 비교와 재조회 사이에 삭제된 파일은 건너뜀
+	result := make([]SimilarFile, 0, len(matches))
+	for _, match := range matches {
+		if file, ok := byID[match.ID]; ok {
+			result = append(result, SimilarFile{File: file, Distance: match.Distance})
+		}
+	}
+
+	return result, nil
+}
+
+// similarMatch 시그니처 비교로 고른 후보
+type similarMatch struct {
+	ID       uint
+	Distance int
+}
+
+// scanSimilar 시그니처가 있는 다른 파일을 읽으며 거리가 maxDistance 이하인 후보를 고릅니다
+func (r *fileRepository) scanSimilar(ctx context.Context, fileID uint, signature uint64, maxDistance int) ([]similarMatch, error) {
+	rows, err := r.db.WithContext(ctx).Model(&model.File{}).
+		Select("id, sim_hash").
+		Where("sim_hash IS NOT NULL AND id <> ?", fileID).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []similarMatch
+	for rows.Next() {
+		var id uint
+		var hash int64
+		if err := rows.Scan(&id, &hash); err != nil {
+			return nil, err
+		}
+
+		if distance := simhash.Distance(signature, uint64(hash)); distance <= maxDistance {
+			matches = append(matches, similarMatch{ID: id, Distance: distance})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(matches, func(a, b similarMatch) int {
+		if a.Distance != b.Distance {
+			return cmp.Compare(a.Distance, b.Distance)
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	if len(matches) > MaxPageSize {
+		matches = matches[:MaxPageSize]
+	}
+
+	return matches, nil
+}
+
+// Exists 파일 존재 여부를 확인합니다
+func (r *fileRepository) Exists(ctx context.Context, id uint) (bool, error) {
+	if id == 0 {
+		return false, invalidID("파일")
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.File{}).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("파일 존재 확인 실패: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// Count 전체 파일 수를 반환합니다
+func (r *fileRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.File{}).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("파일 카운트 조회 실패: %w", err)
+	}
+
+	return count, nil
+}
+
+// statusAggregate 상태별 집계 결과 한 행
+type statusAggregate struct {
+	Status string
+	Value  int64
+}
+
+// CountByStatus 상태별 파일 수를 한 번의 GROUP BY 쿼리로 조회합니다
+//
+// 소프트 삭제된 파일은 제외하며, 파일이 없는 상태는 결과에 포함하지 않습니다.
+func (r *fileRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	counts, err := r.aggregateByStatus(r.db.WithContext(ctx).Model(&model.File{}), "COUNT(*)")
+	if err != nil {
+		return nil, fmt.Errorf("상태별 파일 카운트 조회 실패: %w", err)
+	}
+
+	return counts, nil
+}
+
+// SumSizeByStatus 상태별 원본 크기 합계를 한 번의 GROUP BY 쿼리로 조회합니다
+//
+// 저장 공간을 차지하지 않는 blob 참조 레코드와 소프트 삭제된 파일은 제외하며,
+// 파일이 없는 상태는 결과에 포함하지 않습니다.
+func (r *fileRepository) SumSizeByStatus(ctx context.Context) (map[string]int64, error) {
+	sums, err := r.aggregateByStatus(
+		r.db.WithContext(ctx).Model(&model.File{}).Where("blob_file_id IS NULL"), "COALESCE(SUM(size), 0)")
+	if err != nil {
+		return nil, fmt.Errorf("상태별 크기 합계 조회 실패: %w", err)
+	}
+
+	return sums, nil
+}
+
+// TotalSize 소프트 삭제되지 않은 파일의 원본 크기 합계를 조회합니다
+//
+// SumSizeByStatus와 같이 저장 공간을 차지하지 않는 blob 참조 레코드는 제외하며,
+// 파일이 없으면 0을 반환합니다.
+func (r *fileRepository) TotalSize(ctx context.Context) (int64, error) {
+	total, err := r.sumSize(r.db.WithContext(ctx).Model(&model.File{}))
+	if err != nil {
+		return 0, fmt.Errorf("전체 크기 합계 조회 실패: %w", err)
+	}
+
+	return total, nil
+}
+
+// TotalSizeByMimePrefix MIME 타입이 prefix로 시작하는 파일의 원본 크기 합계를 조회합니다
+//
+// prefix는 "image/"처럼 대분류 또는 "application/vnd."처럼 타입의 앞부분이며
+// 대소문자를 구분하지 않습니다. 집계 범위는 TotalSize와 같습니다.
+func (r *fileRepository) TotalSizeByMimePrefix(ctx context.Context, prefix string) (int64, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || len(prefix) > model.MaxMimeTypeLength {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMimeFilter, prefix)
+	}
+
+	total, err := r.sumSize(r.db.WithContext(ctx).Model(&model.File{}).
+		Where("mime_type LIKE ? ESCAPE '\\'", escapeLike(prefix)+"%"))
+	if err != nil {
+		return 0, fmt.Errorf("MIME 타입별 크기 합계 조회 실패: %w", err)
+	}
+
+	return total, nil
+}
+
+// Aggregate 상태별, MIME 대분류별 파일 수와 크기를 두 번의 GROUP BY 쿼리로 집계합니다
+//
+// 행을 메모리로 읽지 않으며, MIME 대분류는 대소문자를 구분하지 않습니다.
+// 기본적으로 소프트 삭제된 파일은 제외하고, opts.IncludeDeleted이면 포함한 뒤
+// 그중 삭제된 파일의 몫을 Trash로 함께 반환합니다.
+func (r *fileRepository) Aggregate(ctx context.Context, opts AggregateOptions) (*FileAggregates, error) {
+	scope := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.File{})
+		if opts.IncludeDeleted {
+			query = query.Unscoped()
+		}
+		return query
+	}
+
+	result := &FileAggregates{
+		ByStatus:     make(map[string]UsageTotals, len(model.FileStatuses())),
+		ByMimeFamily: make(map[string]UsageTotals, len(MimeFamilies)+1),
+	}
+	for _, status := range model.FileStatuses() {
+		result.ByStatus[status] = UsageTotals{}
+	}
+	for _, family := range MimeFamilies {
+		result.ByMimeFamily[family] = UsageTotals{}
+	}
+	result.ByMimeFamily[MimeFamilyOther] = UsageTotals{}
+
+	var statusRows []struct {
+		Status                string
+		Files                 int64
+		Bytes                 int64
+		EncryptedBytes        int64
+		DeletedFiles          int64
+		DeletedBytes          int64
+		DeletedEncryptedBytes int64
+	}
+	err := scope().
+		Select("status, COUNT(*) AS files, " + ownedSumExpr("size") + " AS bytes, " +
+			ownedSumExpr("encrypted_size") + " AS encrypted_bytes, " +
+			"COALESCE(SUM(CASE WHEN deleted_at IS NOT NULL THEN 1 ELSE 0 END), 0) AS deleted_files, " +
+			"COALESCE(SUM(CASE WHEN deleted_at IS NOT NULL AND blob_file_id IS NULL THEN size ELSE 0 END), 0) AS deleted_bytes, " +
+			"COALESCE(SUM(CASE WHEN deleted_at IS NOT NULL AND blob_file_id IS NULL THEN encrypted_size ELSE 0 END), 0) AS deleted_encrypted_bytes").
+		Group("status").
+		Scan(&statusRows).Error
+	if err != nil {
+		return nil, fmt.Errorf("상태별 집계 조회 실패: %w", err)
+	}
+
+	for _, row := range statusRows {
+		result.ByStatus[row.Status] = UsageTotals{Files: row.Files, Bytes: row.Bytes, EncryptedBytes: row.EncryptedBytes}
+		result.Total.Files += row.Files
+		result.Total.Bytes += row.Bytes
+		result.Total.EncryptedBytes += row.EncryptedBytes
+		result.Trash.Files += row.DeletedFiles
+		result.Trash.Bytes += row.DeletedBytes
+		result.Trash.EncryptedBytes += row.DeletedEncryptedBytes
+	}
+
+	var familyRows []struct {
+		Family         string
+		Files          int64
+		Bytes          int64
+		EncryptedBytes int64
+	}
+	err = scope().
+		Select(mimeFamilyExpr() + " AS family, COUNT(*) AS files, " + ownedSumExpr("size") + " AS bytes, " +
+			ownedSumExpr("encrypted_size") + " AS encrypted_bytes").
+		Group("family").
+		Scan(&familyRows).Error
+	if err != nil {
+		return nil, fmt.Errorf("MIME 대분류별 집계 조회 실패: %w", err)
+	}
+
+	for _, row := range familyRows {
+		result.ByMimeFamily[row.Family] = UsageTotals{Files: row.Files, Bytes: row.Bytes, EncryptedBytes: row.EncryptedBytes}
+	}
+
+	return result, nil
+}
+
+// ownedSumExpr blob 소유 레코드의 크기 컬럼 합계 SQL 식 (참조 레코드는 0으로 셈)
+//
+// column은 size 또는 encrypted_size 같은 패키지 내부 상수이므로 식에 그대로 넣습니다.
+func ownedSumExpr(column string) string {
+	return "COALESCE(SUM(CASE WHEN blob_file_id IS NULL THEN " + column + " ELSE 0 END), 0)"
+}
+
+// mimeFamilyExpr MIME 타입을 MimeFamilies 중 하나 또는 MimeFamilyOther로 바꾸는 SQL 식
+//
+// 대분류 이름은 패키지 상수이므로 식에 그대로 넣습니다.
+func mimeFamilyExpr() string {
+	var expr strings.Builder
+	expr.WriteString("CASE")
+	for _, family := range MimeFamilies {
+		expr.WriteString(" WHEN LOWER(mime_type) LIKE '" + family + "/%' THEN '" + family + "'")
+	}
+	expr.WriteString(" ELSE '" + MimeFamilyOther + "' END")
+	return expr.String()
+}
+
+// sumSize query에 해당하는 blob 소유 레코드의 크기 합계를 DB에서 계산합니다
+func (r *fileRepository) sumSize(query *gorm.DB) (int64, error) {
+	var total int64
+	err := query.
+		Where("blob_file_id IS NULL").
+		Select("COALESCE(SUM(size), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// aggregateByStatus query를 상태별로 묶어 집계식 결과를 맵으로 반환합니다
+func (r *fileRepository) aggregateByStatus(query *gorm.DB, aggregate string) (map[string]int64, error) {
+	var rows []statusAggregate
+	err := query.
+		Select("status, " + aggregate + " AS value").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		result[row.Status] = row.Value
+	}
+	return result, nil
+}
+
+// Search 이름(또는 설명)/태그/상태/MIME/기간 조건을 조합해 파일을 검색합니다
+//
+// 태그 이름이 잘못되면 ErrInvalidFileFilter를 감싼 에러를 반환합니다.
+func (r *fileRepository) Search(ctx context.Context, params FileSearchParams) ([]*model.File, int64, error) {
+	if params.Status != "" && !model.IsValidFileStatus(params.Status) {
+		return nil, 0, fmt.Errorf("유효하지 않은 파일 상태입니다: %s", params.Status)
+	}
+
+	if params.From != nil && params.To != nil && params.From.After(*params.To) {
+		return nil, 0, fmt.Errorf("검색 시작 시각이 종료 시각보다 늦습니다")
+	}
+
+	tags := make([]string, 0, len(params.Tags))
+	for _, tag := range params.Tags {
+		name, err := model.NormalizeTagName(tag)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: 태그 %q: %w", ErrInvalidFileFilter, tag, err)
+		}
+		tags = append(tags, name)
+	}
+	params.Tags = tags
+
+	// 파일명은 NFC로 저장하므로 검색어도 NFC로 맞춤 (NFD로 입력해도 같은 파일을 찾음)
+	params.Query = model.NormalizeFileName(params.Query)
+
+	offset, limit := r.normalizePagination(params.Offset, params.Limit)
+	query := r.applySearchFilters(r.db.WithContext(ctx).Model(&model.File{}), params)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("검색 결과 카운트 조회 실패: %w", err)
+	}
+
+	var files []*model.File
+	err := r.applySearchOrder(r.applySearchFilters(r.preloadMetadata(r.db.WithContext(ctx)), params), params).
+		Offset(offset).
+		Limit(limit).
+		Find(&files).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("파일 검색 실패: %w", err)
+	}
+
+	return files, total, nil
+}
+
+// SearchByName 원본 파일명에 검색어가 포함된 파일을 관련도순으로 조회합니다
+//
+// 입력 중 검색(type-ahead)용으로, 대소문자를 무시하고 %, _는 와일드카드가 아닌
+// 문자 그대로 비교합니다. 검색어는 Search에서 NFC로 정규화합니다. 앞뒤 공백을 제거한 검색어가 비어 있거나 너무 길면
+// ErrInvalidNameQuery를 반환합니다.
+func (r *fileRepository) SearchByName(ctx context.Context, query string, offset, limit int) ([]*model.File, int64, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || len(query) > model.MaxOriginalNameLength {
+		return nil, 0, fmt.Errorf("%w: 길이 %d", ErrInvalidNameQuery, len(query))
+	}
+
+	return r.Search(ctx, FileSearchParams{
+		Query:  query,
+		SortBy: SearchSortRelevance,
+		Offset: offset,
+		Limit:  limit,
+	})
+}
+
+// applySearchFilters 검색 조건을 쿼리에 적용합니다
+func (r *fileRepository) applySearchFilters(query *gorm.DB, params FileSearchParams) *gorm.DB {
+	if params.Query != "" {
+		pattern := "%" + escapeLike(params.Query) + "%"
+		if params.MatchDescription {
+			query = query.Where("(original_name LIKE ? ESCAPE '\\' OR description LIKE ? ESCAPE '\\')", pattern, pattern)
+		} else {
+			query = query.Where("original_name LIKE ? ESCAPE '\\'", pattern)
+		}
+	}
+
+	for _, tag := range params.Tags {
+		query = query.Where("id IN (?)", gorm.Expr(taggedFileIDsSQL, tag))
+	}
+
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	if params.MimeType != "" {
+		query = query.Where("mime_type = ?", params.MimeType)
+	}
+
+	// 저장된 시각이 UTC이므로 오프셋이 있는 조건도 UTC로 바꿔 비교 (GetByDateRange와 같은 반개구간)
+	if params.From != nil {
+		query = query.Where("created_at >= ?", params.From.UTC())
+	}
+
+	if params.To != nil {
+		query = query.Where("created_at < ?", params.To.UTC())
+	}
+
+	return query
+}
+
+// applySearchOrder 검색 결과 정렬을 적용합니다
+func (r *fileRepository) applySearchOrder(query *gorm.DB, params FileSearchParams) *gorm.DB {
+	if params.SortBy == SearchSortLatest || params.Query == "" {
+		return query.Order("created_at DESC").Order("id DESC")
+	}
+
+	// 설명에서만 찾은 파일은 이름에서 찾은 파일 뒤
+	escaped := escapeLike(params.Query)
+	return query.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL: "CASE WHEN original_name = ? COLLATE NOCASE THEN 0 " +
+			"WHEN original_name LIKE ? ESCAPE '\\' THEN 1 " +
+			"WHEN original_name LIKE ? ESCAPE '\\' THEN 2 ELSE 3 END, created_at DESC, id DESC",
+		Vars:               []interface{}{params.Query, escaped + "%", "%" + escaped + "%"},
+		WithoutParentheses: true,
+	}})
+}
+
+// escapeLike LIKE 패턴의 특수문자(%, _, \)를 이스케이프합니다
+func escapeLike(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(value)
 }
 
 // normalizePagination 페이지네이션 파라미터를 정규화합니다
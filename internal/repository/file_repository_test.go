@@ -1,16 +1,24 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"DataLocker/internal/database"
 	"DataLocker/internal/model"
+	"DataLocker/pkg/clock"
+	"DataLocker/pkg/httputil"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/datatypes"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -44,12 +52,13 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 	// 데이터베이스 연결
 	dsn := dbPath + "?_foreign_keys=ON&_journal_mode=WAL&_sync=NORMAL"
 	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent), // 테스트 시 로그 최소화
+		Logger:  logger.Default.LogMode(logger.Silent), // 테스트 시 로그 최소화
+		NowFunc: database.NowUTC,
 	})
 	require.NoError(t, err)
 
 	// 마이그레이션 실행
-	err = model.Migrate(db)
+	_, err = model.MigrateUp(db)
 	require.NoError(t, err)
 
 	// 정리 함수 반환
@@ -65,6 +74,14 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 	return db, cleanup
 }
 
+// assertStoredUTC files 행에 저장된 시각 문자열이 UTC 오프셋인지 확인합니다
+func assertStoredUTC(t *testing.T, db *gorm.DB, column string, id uint) {
+	t.Helper()
+	var stored string
+	require.NoError(t, db.Raw("SELECT CAST("+column+" AS TEXT) FROM files WHERE id = ?", id).Scan(&stored).Error)
+	assert.True(t, strings.HasSuffix(stored, "+00:00"), "%s = %s", column, stored)
+}
+
 // createTestFile 테스트용 파일 모델을 생성합니다
 func createTestFile(suffix string) *model.File {
 	return &model.File{
@@ -92,13 +109,14 @@ func TestNewFileRepository(t *testing.T) {
 }
 
 func TestFileRepository_Create_Success(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	repo := NewFileRepository(db)
 	file := createTestFile("_create")
 
-	err := repo.Create(file)
+	err := repo.Create(ctx, file)
 	require.NoError(t, err)
 	assert.NotZero(t, file.ID)
 	assert.NotZero(t, file.CreatedAt)
@@ -106,6 +124,7 @@ func TestFileRepository_Create_Success(t *testing.T) {
 }
 
 func TestFileRepository_Create_ErrorCases(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -137,7 +156,7 @@ func TestFileRepository_Create_ErrorCases(t *testing.T) {
 			file: func() *model.File {
 				// 먼저 파일 하나 생성
 				firstFile := createTestFile("_first")
-				_ = repo.Create(firstFile)
+				_ = repo.Create(ctx, firstFile)
 
 				// 같은 암호화 경로로 두 번째 파일 생성 시도
 				secondFile := createTestFile("_second")
@@ -150,7 +169,7 @@ func TestFileRepository_Create_ErrorCases(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := repo.Create(tc.file)
+			err := repo.Create(ctx, tc.file)
 			require.Error(t, err)
 			assert.Contains(t, err.Error(), tc.wantErr)
 		})
@@ -158,6 +177,7 @@ func TestFileRepository_Create_ErrorCases(t *testing.T) {
 }
 
 func TestFileRepository_GetByID_Success(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -165,11 +185,11 @@ func TestFileRepository_GetByID_Success(t *testing.T) {
 	originalFile := createTestFile("_getbyid")
 
 	// 파일 생성
-	err := repo.Create(originalFile)
+	err := repo.Create(ctx, originalFile)
 	require.NoError(t, err)
 
 	// 조회 테스트
-	retrievedFile, err := repo.GetByID(originalFile.ID)
+	retrievedFile, err := repo.GetByID(ctx, originalFile.ID)
 	require.NoError(t, err)
 	require.NotNil(t, retrievedFile)
 
@@ -183,6 +203,7 @@ func TestFileRepository_GetByID_Success(t *testing.T) {
 }
 
 func TestFileRepository_GetByID_ErrorCases(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -192,30 +213,35 @@ func TestFileRepository_GetByID_ErrorCases(t *testing.T) {
 		name    string
 		id      uint
 		wantErr string
+		wantIs  error
 	}{
 		{
 			name:    "잘못된 ID (0)",
 			id:      TestInvalidFileID,
 			wantErr: "유효하지 않은 파일 ID입니다",
+			wantIs:  ErrInvalidID,
 		},
 		{
 			name:    "존재하지 않는 ID",
 			id:      TestNonExistentID,
 			wantErr: "파일을 찾을 수 없습니다",
+			wantIs:  ErrNotFound,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			file, err := repo.GetByID(tc.id)
+			file, err := repo.GetByID(ctx, tc.id)
 			require.Error(t, err)
 			assert.Nil(t, file)
 			assert.Contains(t, err.Error(), tc.wantErr)
+			assert.ErrorIs(t, err, tc.wantIs)
 		})
 	}
 }
 
 func TestFileRepository_GetAll_Success(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -225,7 +251,7 @@ func TestFileRepository_GetAll_Success(t *testing.T) {
 	testFiles := make([]*model.File, TestPageSize)
 	for i := 0; i < TestPageSize; i++ {
 		file := createTestFile(fmt.Sprintf("_getall_%d", i))
-		err := repo.Create(file)
+		err := repo.Create(ctx, file)
 		require.NoError(t, err)
 		testFiles[i] = file
 
@@ -234,7 +260,7 @@ func TestFileRepository_GetAll_Success(t *testing.T) {
 	}
 
 	// 전체 조회 테스트
-	files, total, err := repo.GetAll(0, TestPageSize)
+	files, total, err := repo.GetAll(ctx, 0, TestPageSize, SortOption{})
 	require.NoError(t, err)
 	assert.Len(t, files, TestPageSize)
 	assert.Equal(t, int64(TestPageSize), total)
@@ -246,7 +272,156 @@ func TestFileRepository_GetAll_Success(t *testing.T) {
 	}
 }
 
+func TestFileRepository_GetAfter(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 생성 시각이 같은 파일을 섞어 (created_at, id) 순서가 페이지 경계에서도 유지되는지 확인
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	offsets := []time.Duration{0, time.Minute, time.Minute, time.Minute, 2 * time.Minute}
+	var want []uint
+	for i, offset := range offsets {
+		file := createTestFile(fmt.Sprintf("_after_%d", i))
+		file.CreatedAt = base.Add(offset)
+		require.NoError(t, repo.Create(ctx, file))
+		want = append([]uint{file.ID}, want...) // 같은 시각끼리는 ID 내림차순
+	}
+
+	var got []uint
+	cursor := Cursor{}
+	for page := 0; ; page++ {
+		files, next, err := repo.GetAfter(ctx, cursor, 2)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(files), 2)
+		for _, file := range files {
+			got = append(got, file.ID)
+		}
+
+		// 첫 페이지를 읽은 뒤 추가된 최신 파일은 다음 페이지에 끼어들지 않음
+		if page == 0 {
+			fresh := createTestFile("_after_fresh")
+			require.NoError(t, repo.Create(ctx, fresh))
+		}
+
+		if next.IsZero() {
+			break
+		}
+		parsed, err := ParseCursor(next.String())
+		require.NoError(t, err)
+		assert.Equal(t, next, parsed)
+		cursor = parsed
+	}
+	assert.Equal(t, want, got)
+
+	// 정확히 한 페이지면 다음 커서가 없음
+	files, next, err := repo.GetAfter(ctx, Cursor{}, 6)
+	require.NoError(t, err)
+	assert.Len(t, files, 6)
+	assert.True(t, next.IsZero())
+}
+
+func TestFileRepository_GetAll_Sort(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 기준마다 순서가 다르도록 이름(대소문자 섞음), 크기, 상태, 생성 시각을 배치
+	base := time.Now().UTC().Add(-time.Hour)
+	seeds := []struct {
+		name    string
+		size    int64
+		status  string
+		created time.Duration
+	}{
+		{name: "b.txt", size: 300, status: model.FileStatusFailed, created: 2 * time.Minute},
+		{name: "A.txt", size: 100, status: model.FileStatusPending, created: 4 * time.Minute},
+		{name: "d.txt", size: 400, status: model.FileStatusCorrupted, created: 1 * time.Minute},
+		{name: "C.txt", size: 200, status: model.FileStatusEncrypted, created: 3 * time.Minute},
+	}
+	ids := make(map[string]uint, len(seeds))
+	for _, seed := range seeds {
+		file := createTestFile("_sort_" + seed.name)
+		file.OriginalName = seed.name
+		file.Size = seed.size
+		file.Status = seed.status
+		file.CreatedAt = base.Add(seed.created)
+		require.NoError(t, repo.Create(ctx, file))
+		ids[seed.name] = file.ID
+	}
+
+	testCases := []struct {
+		sort SortOption
+		want []string // 원본 파일명 순서
+	}{
+		{sort: SortOption{}, want: []string{"A.txt", "C.txt", "b.txt", "d.txt"}},
+		{sort: SortOption{Field: SortFieldCreatedAt, Direction: SortAsc}, want: []string{"d.txt", "b.txt", "C.txt", "A.txt"}},
+		{sort: SortOption{Field: SortFieldCreatedAt, Direction: SortDesc}, want: []string{"A.txt", "C.txt", "b.txt", "d.txt"}},
+		{sort: SortOption{Field: SortFieldName, Direction: SortAsc}, want: []string{"A.txt", "b.txt", "C.txt", "d.txt"}},
+		{sort: SortOption{Field: SortFieldName, Direction: SortDesc}, want: []string{"d.txt", "C.txt", "b.txt", "A.txt"}},
+		{sort: SortOption{Field: SortFieldSize, Direction: SortAsc}, want: []string{"A.txt", "C.txt", "b.txt", "d.txt"}},
+		{sort: SortOption{Field: SortFieldSize, Direction: SortDesc}, want: []string{"d.txt", "b.txt", "C.txt", "A.txt"}},
+		{sort: SortOption{Field: SortFieldStatus, Direction: SortAsc}, want: []string{"d.txt", "C.txt", "b.txt", "A.txt"}},
+		{sort: SortOption{Field: SortFieldStatus, Direction: SortDesc}, want: []string{"A.txt", "b.txt", "C.txt", "d.txt"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sort.Field+"_"+tc.sort.Direction, func(t *testing.T) {
+			files, total, err := repo.GetAll(ctx, 0, DefaultPageSize, tc.sort)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(seeds)), total)
+
+			names := make([]string, 0, len(files))
+			for _, file := range files {
+				names = append(names, file.OriginalName)
+			}
+			assert.Equal(t, tc.want, names)
+		})
+	}
+
+	t.Run("같은 값은 ID로 정렬", func(t *testing.T) {
+		for i := range 3 {
+			file := createTestFile(fmt.Sprintf("_same_%d", i))
+			file.Size = 50
+			require.NoError(t, repo.Create(ctx, file))
+		}
+
+		files, total, err := repo.GetByStatus(ctx, model.FileStatusPending, 0, DefaultPageSize, SortOption{Field: SortFieldSize, Direction: SortAsc})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+		require.Len(t, files, 4)
+		assert.Less(t, files[0].ID, files[1].ID)
+		assert.Less(t, files[1].ID, files[2].ID)
+		assert.Equal(t, ids["A.txt"], files[3].ID, "크기 100은 마지막")
+
+		files, _, err = repo.GetByStatus(ctx, model.FileStatusPending, 0, DefaultPageSize, SortOption{Field: SortFieldSize, Direction: SortDesc})
+		require.NoError(t, err)
+		assert.Equal(t, ids["A.txt"], files[0].ID)
+		assert.Greater(t, files[1].ID, files[2].ID)
+	})
+
+	t.Run("허용하지 않는 정렬 조건", func(t *testing.T) {
+		for _, sort := range []SortOption{
+			{Field: "encrypted_path"},
+			{Field: "created_at; DROP TABLE files"},
+			{Field: "NAME"},
+			{Field: SortFieldName, Direction: "up"},
+		} {
+			_, _, err := repo.GetAll(ctx, 0, 0, sort)
+			assert.ErrorIs(t, err, ErrInvalidSortOption, sort.Field)
+
+			_, _, err = repo.GetByStatus(ctx, model.FileStatusPending, 0, 0, sort)
+			assert.ErrorIs(t, err, ErrInvalidSortOption, sort.Field)
+		}
+	})
+}
+
 func TestFileRepository_GetAll_Pagination(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -255,7 +430,7 @@ func TestFileRepository_GetAll_Pagination(t *testing.T) {
 	// 테스트 파일들 생성 (10개)
 	for i := 0; i < 10; i++ {
 		file := createTestFile(fmt.Sprintf("_pagination_%d", i))
-		err := repo.Create(file)
+		err := repo.Create(ctx, file)
 		require.NoError(t, err)
 	}
 
@@ -317,7 +492,7 @@ func TestFileRepository_GetAll_Pagination(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			files, total, err := repo.GetAll(tc.offset, tc.limit)
+			files, total, err := repo.GetAll(ctx, tc.offset, tc.limit, SortOption{})
 			require.NoError(t, err)
 			assert.Len(t, files, tc.expectedCount)
 			assert.Equal(t, tc.expectedTotal, total)
@@ -326,6 +501,7 @@ func TestFileRepository_GetAll_Pagination(t *testing.T) {
 }
 
 func TestFileRepository_Update_Success(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -333,24 +509,25 @@ func TestFileRepository_Update_Success(t *testing.T) {
 	file := createTestFile("_update")
 
 	// 파일 생성
-	err := repo.Create(file)
+	err := repo.Create(ctx, file)
 	require.NoError(t, err)
 
 	// 파일 수정
 	file.Status = model.FileStatusEncrypted
 	file.Size = TestLargeFileSize
 
-	err = repo.Update(file)
+	err = repo.Update(ctx, file)
 	require.NoError(t, err)
 
 	// 수정 확인
-	updatedFile, err := repo.GetByID(file.ID)
+	updatedFile, err := repo.GetByID(ctx, file.ID)
 	require.NoError(t, err)
 	assert.Equal(t, model.FileStatusEncrypted, updatedFile.Status)
 	assert.Equal(t, int64(TestLargeFileSize), updatedFile.Size)
 }
 
 func TestFileRepository_Update_ErrorCases(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -384,14 +561,111 @@ func TestFileRepository_Update_ErrorCases(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := repo.Update(tc.file)
+			err := repo.Update(ctx, tc.file)
 			require.Error(t, err)
 			assert.Contains(t, err.Error(), tc.wantErr)
 		})
 	}
 }
 
+func TestFileRepository_Update_StaleRecord(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	file := createTestFile("_stale")
+	require.NoError(t, repo.Create(ctx, file))
+	assert.Equal(t, uint(1), file.Version)
+
+	// 두 요청이 같은 버전을 읽어 온 상황
+	first, err := repo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	stale, err := repo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+
+	first.Status = model.FileStatusEncrypted
+	require.NoError(t, repo.Update(ctx, first))
+	assert.Equal(t, uint(2), first.Version)
+
+	// 먼저 반영된 변경을 모르는 복사본은 덮어쓰지 못함
+	stale.Size = TestLargeFileSize
+	err = repo.Update(ctx, stale)
+	require.ErrorIs(t, err, model.ErrStaleRecord)
+	assert.NotErrorIs(t, err, model.ErrRecordNotFound)
+	assert.Equal(t, uint(1), stale.Version, "실패하면 메모리의 버전도 그대로")
+
+	current, err := repo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStatusEncrypted, current.Status)
+	assert.Equal(t, file.Size, current.Size)
+	assert.Equal(t, uint(2), current.Version)
+
+	// 다시 읽어 재시도하면 반영됨
+	current.Size = TestLargeFileSize
+	require.NoError(t, repo.Update(ctx, current))
+
+	// 상태만 바꾸는 갱신도 버전을 올림
+	require.NoError(t, repo.UpdateStatus(ctx, file.ID, model.FileStatusFailed))
+	assert.ErrorIs(t, repo.Update(ctx, current), model.ErrStaleRecord)
+
+	reloaded, err := repo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint(4), reloaded.Version)
+	assert.Equal(t, int64(TestLargeFileSize), reloaded.Size)
+	assert.Equal(t, model.FileStatusFailed, reloaded.Status)
+}
+
+func TestFileRepository_UpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	file := createTestFile("_update_status")
+	require.NoError(t, repo.Create(ctx, file))
+
+	// 다른 컬럼을 먼저 바꿔 둔 뒤 상태만 변경해도 덮어쓰지 않음
+	require.NoError(t, db.Model(&model.File{}).Where("id = ?", file.ID).
+		UpdateColumn("size", TestLargeFileSize).Error)
+
+	err := repo.UpdateStatus(ctx, file.ID, model.FileStatusEncrypted)
+	require.NoError(t, err)
+
+	updated, err := repo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStatusEncrypted, updated.Status)
+	assert.Equal(t, int64(TestLargeFileSize), updated.Size)
+	assert.Equal(t, file.OriginalName, updated.OriginalName)
+	assert.False(t, updated.UpdatedAt.Before(file.UpdatedAt))
+
+	// 호스트 시간대와 무관하게 UTC로 기록 (문자열 비교 조회와 어긋나지 않음)
+	assertStoredUTC(t, db, "updated_at", file.ID)
+
+	// 잘못된 상태는 쿼리 전에 거부
+	err = repo.UpdateStatus(ctx, file.ID, "unknown")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "유효하지 않은 파일 상태입니다")
+
+	err = repo.UpdateStatus(ctx, TestInvalidFileID, model.FileStatusEncrypted)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "유효하지 않은 파일 ID입니다")
+
+	err = repo.UpdateStatus(ctx, TestNonExistentID, model.FileStatusEncrypted)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	// 소프트 삭제된 파일은 변경하지 않음
+	require.NoError(t, repo.Delete(ctx, file.ID))
+	err = repo.UpdateStatus(ctx, file.ID, model.FileStatusFailed)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	deleted, err := repo.GetByIDWithDeleted(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStatusEncrypted, deleted.Status)
+}
+
 func TestFileRepository_Delete_Success(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -399,20 +673,22 @@ func TestFileRepository_Delete_Success(t *testing.T) {
 	file := createTestFile("_delete")
 
 	// 파일 생성
-	err := repo.Create(file)
+	err := repo.Create(ctx, file)
 	require.NoError(t, err)
 
 	// 파일 삭제
-	err = repo.Delete(file.ID)
+	err = repo.Delete(ctx, file.ID)
 	require.NoError(t, err)
 
 	// 삭제 확인 (소프트 삭제이므로 GetByID는 에러 반환)
-	_, err = repo.GetByID(file.ID)
+	_, err = repo.GetByID(ctx, file.ID)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "파일을 찾을 수 없습니다")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
 }
 
 func TestFileRepository_Delete_ErrorCases(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -437,208 +713,1988 @@ func TestFileRepository_Delete_ErrorCases(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := repo.Delete(tc.id)
+			err := repo.Delete(ctx, tc.id)
 			require.Error(t, err)
 			assert.Contains(t, err.Error(), tc.wantErr)
 		})
 	}
 }
 
-func TestFileRepository_GetByStatus_Success(t *testing.T) {
+func TestFileRepository_GetByIDs(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	repo := NewFileRepository(db)
-
-	// 다양한 상태의 파일들 생성
-	statuses := []string{
-		model.FileStatusPending,
-		model.FileStatusEncrypted,
-		model.FileStatusFailed,
+	files := make([]*model.File, 4)
+	for i := range files {
+		files[i] = createTestFile(fmt.Sprintf("_get_by_ids_%d", i))
+		require.NoError(t, repo.Create(ctx, files[i]))
+		require.NoError(t, db.Create(createTestEncryptionMetadata(files[i].ID)).Error)
 	}
+	require.NoError(t, repo.Delete(ctx, files[3].ID))
 
-	filesPerStatus := 3
-	for _, status := range statuses {
-		for i := 0; i < filesPerStatus; i++ {
-			file := createTestFile(fmt.Sprintf("_%s_%d", status, i))
-			file.Status = status
-			err := repo.Create(file)
-			require.NoError(t, err)
+	t.Run("입력 순서를 유지하고 메타데이터를 함께 로드", func(t *testing.T) {
+		found, err := repo.GetByIDs(ctx, []uint{files[2].ID, files[0].ID, files[2].ID, files[1].ID})
+		require.NoError(t, err)
+		require.Len(t, found, 3, "중복 ID는 한 번만 포함")
+
+		assert.Equal(t, []uint{files[2].ID, files[0].ID, files[1].ID}, []uint{found[0].ID, found[1].ID, found[2].ID})
+		for _, file := range found {
+			require.NotNil(t, file.EncryptionMetadata)
+			assert.Equal(t, file.ID, file.EncryptionMetadata.FileID)
 		}
-	}
+	})
 
-	// 각 상태별 조회 테스트
-	for _, status := range statuses {
-		t.Run(fmt.Sprintf("status_%s", status), func(t *testing.T) {
-			files, total, err := repo.GetByStatus(status, 0, DefaultPageSize)
-			require.NoError(t, err)
-			assert.Len(t, files, filesPerStatus)
-			assert.Equal(t, int64(filesPerStatus), total)
+	t.Run("없거나 삭제된 ID는 찾은 파일과 함께 보고", func(t *testing.T) {
+		found, err := repo.GetByIDs(ctx, []uint{files[3].ID, files[1].ID, TestNonExistentID})
 
-			// 모든 파일이 요청한 상태인지 확인
-			for _, file := range files {
-				assert.Equal(t, status, file.Status)
-			}
-		})
-	}
-}
+		var missingErr *MissingIDsError
+		require.ErrorAs(t, err, &missingErr)
+		assert.Equal(t, []uint{files[3].ID, TestNonExistentID}, missingErr.IDs)
+		assert.ErrorIs(t, err, ErrNotFound)
 
-func TestFileRepository_GetByStatus_ErrorCases(t *testing.T) {
-	db, cleanup := setupTestDB(t)
-	defer cleanup()
+		require.Len(t, found, 1)
+		assert.Equal(t, files[1].ID, found[0].ID)
+	})
 
-	repo := NewFileRepository(db)
+	t.Run("잘못된 입력", func(t *testing.T) {
+		_, err := repo.GetByIDs(ctx, nil)
+		assert.Error(t, err)
 
-	testCases := []struct {
-		name    string
-		status  string
-		wantErr string
-	}{
-		{
-			name:    "빈 상태",
-			status:  "",
-			wantErr: "상태 값이 필요합니다",
-		},
-		{
-			name:    "잘못된 상태",
-			status:  "invalid_status",
-			wantErr: "유효하지 않은 파일 상태입니다",
-		},
-	}
+		_, err = repo.GetByIDs(ctx, []uint{files[0].ID, 0})
+		assert.ErrorIs(t, err, ErrInvalidID)
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			files, total, err := repo.GetByStatus(tc.status, 0, DefaultPageSize)
-			require.Error(t, err)
-			assert.Nil(t, files)
-			assert.Zero(t, total)
-			assert.Contains(t, err.Error(), tc.wantErr)
-		})
-	}
+		tooMany := make([]uint, MaxBatchIDs+1)
+		for i := range tooMany {
+			tooMany[i] = uint(i + 1)
+		}
+		_, err = repo.GetByIDs(ctx, tooMany)
+		assert.ErrorIs(t, err, ErrTooManyIDs)
+
+		// 중복을 제거한 수가 상한 이내면 허용
+		_, err = repo.GetByIDs(ctx, append(tooMany[:MaxBatchIDs], 1))
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
 }
 
-func TestFileRepository_GetByChecksumMD5_Success(t *testing.T) {
+func TestFileRepository_DeleteBatch(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	repo := NewFileRepository(db)
-	file := createTestFile("_checksum")
-	uniqueChecksum := "unique123456789abcdef0123456789ab"
-	file.ChecksumMD5 = uniqueChecksum
+	files := make([]*model.File, 3)
+	for i := range files {
+		files[i] = createTestFile(fmt.Sprintf("_delete_batch_%d", i))
+		require.NoError(t, repo.Create(ctx, files[i]))
+		require.NoError(t, db.Create(createTestEncryptionMetadata(files[i].ID)).Error)
+	}
 
-	// 파일 생성
-	err := repo.Create(file)
-	require.NoError(t, err)
+	t.Run("잘못된 입력은 아무것도 삭제하지 않음", func(t *testing.T) {
+		_, err := repo.DeleteBatch(ctx, nil)
+		assert.ErrorContains(t, err, "대상 ID가 없습니다")
 
-	// 체크섬으로 조회
-	foundFile, err := repo.GetByChecksumMD5(uniqueChecksum)
-	require.NoError(t, err)
-	require.NotNil(t, foundFile)
-	assert.Equal(t, file.ID, foundFile.ID)
-	assert.Equal(t, uniqueChecksum, foundFile.ChecksumMD5)
+		deleted, err := repo.DeleteBatch(ctx, []uint{files[0].ID, 0})
+		var itemErr *BatchItemError
+		require.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, 1, itemErr.Index)
+		assert.Zero(t, deleted)
 
-	// 존재하지 않는 체크섬 조회
-	notFoundFile, err := repo.GetByChecksumMD5("nonexistent_checksum")
-	require.NoError(t, err)
-	assert.Nil(t, notFoundFile)
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("있는 파일만 삭제하고 없는 ID 보고", func(t *testing.T) {
+		deleted, err := repo.DeleteBatch(ctx, []uint{files[0].ID, files[1].ID, files[1].ID, TestNonExistentID})
+		assert.Equal(t, int64(2), deleted, "중복 ID는 한 번만 삭제")
+
+		var missingErr *MissingIDsError
+		require.ErrorAs(t, err, &missingErr)
+		assert.Equal(t, []uint{TestNonExistentID}, missingErr.IDs)
+		assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+		_, err = repo.GetByID(ctx, files[0].ID)
+		assert.ErrorIs(t, err, model.ErrRecordNotFound)
+		_, err = repo.GetByID(ctx, files[2].ID)
+		assert.NoError(t, err)
+
+		// 소프트 삭제이므로 메타데이터는 남고 복구하면 그대로 사용 가능
+		var metadataCount int64
+		require.NoError(t, db.Model(&model.EncryptionMetadata{}).
+			Where("file_id IN ?", []uint{files[0].ID, files[1].ID}).Count(&metadataCount).Error)
+		assert.Equal(t, int64(2), metadataCount)
+
+		require.NoError(t, repo.Restore(ctx, files[0].ID))
+		restored, err := repo.GetByID(ctx, files[0].ID)
+		require.NoError(t, err)
+		assert.NotNil(t, restored.EncryptionMetadata)
+	})
+
+	t.Run("이미 삭제된 파일은 없는 ID로 보고", func(t *testing.T) {
+		deleted, err := repo.DeleteBatch(ctx, []uint{files[1].ID, files[2].ID})
+		assert.Equal(t, int64(1), deleted)
+
+		var missingErr *MissingIDsError
+		require.ErrorAs(t, err, &missingErr)
+		assert.Equal(t, []uint{files[1].ID}, missingErr.IDs)
+	})
 }
 
-func TestFileRepository_GetByChecksumMD5_ErrorCases(t *testing.T) {
+func TestFileRepository_Purge(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	repo := NewFileRepository(db)
 
-	// 빈 체크섬
-	file, err := repo.GetByChecksumMD5("")
-	require.Error(t, err)
-	assert.Nil(t, file)
-	assert.Contains(t, err.Error(), "체크섬 값이 필요합니다")
+	t.Run("메타데이터와 키 슬롯까지 영구 삭제", func(t *testing.T) {
+		file := createTestFile("_purge")
+		require.NoError(t, repo.Create(ctx, file))
+		require.NoError(t, db.Create(createTestEncryptionMetadata(file.ID)).Error)
+		require.NoError(t, db.Create(createTestKeySlot(file.ID, 0)).Error)
+
+		require.NoError(t, repo.Purge(ctx, file.ID))
+
+		var count int64
+		assert.ErrorIs(t, db.Unscoped().First(&model.File{}, file.ID).Error, gorm.ErrRecordNotFound)
+		require.NoError(t, db.Model(&model.EncryptionMetadata{}).Where("file_id = ?", file.ID).Count(&count).Error)
+		assert.Zero(t, count)
+		require.NoError(t, db.Model(&model.KeySlot{}).Where("file_id = ?", file.ID).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("소프트 삭제된 레코드도 영구 삭제", func(t *testing.T) {
+		file := createTestFile("_purge_soft")
+		require.NoError(t, repo.Create(ctx, file))
+		require.NoError(t, repo.Delete(ctx, file.ID))
+
+		require.NoError(t, repo.Purge(ctx, file.ID))
+
+		var count int64
+		require.NoError(t, db.Unscoped().Model(&model.File{}).Where("id = ?", file.ID).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("에러 케이스", func(t *testing.T) {
+		err := repo.Purge(ctx, TestInvalidFileID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "유효하지 않은 파일 ID입니다")
+
+		err = repo.Purge(ctx, TestNonExistentID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "삭제할 파일을 찾을 수 없습니다")
+	})
 }
 
-func TestFileRepository_Exists_Success(t *testing.T) {
+func TestFileRepository_PurgeDeletedOlderThan(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	repo := NewFileRepository(db)
-	file := createTestFile("_exists")
+	now := time.Now()
+
+	// deletedAgo만큼 전에 삭제된 파일 (0이면 삭제하지 않음)
+	seed := func(suffix string, deletedAgo time.Duration) *model.File {
+		file := createTestFile("_retention" + suffix)
+		require.NoError(t, repo.Create(ctx, file))
+		require.NoError(t, db.Create(createTestEncryptionMetadata(file.ID)).Error)
+		require.NoError(t, db.Create(createTestKeySlot(file.ID, 0)).Error)
+		if deletedAgo > 0 {
+			require.NoError(t, db.Model(file).UpdateColumn("deleted_at", now.Add(-deletedAgo).UTC()).Error)
+		}
+		return file
+	}
 
-	// 파일 생성 전 - 존재하지 않음
-	exists, err := repo.Exists(TestValidFileID)
-	require.NoError(t, err)
-	assert.False(t, exists)
+	expired := seed("_expired", 48*time.Hour)
+	recent := seed("_recent", time.Hour)
+	live := seed("_live", 0)
 
-	// 파일 생성
-	err = repo.Create(file)
-	require.NoError(t, err)
+	// 참조 레코드가 남아 있는 blob 소유자는 만료되어도 유지
+	owner := seed("_owner", 48*time.Hour)
+	ref := createTestFile("_retention_ref")
+	ref.BlobFileID = &owner.ID
+	require.NoError(t, repo.Create(ctx, ref))
 
-	// 파일 생성 후 - 존재함
-	exists, err = repo.Exists(file.ID)
-	require.NoError(t, err)
-	assert.True(t, exists)
+	_, err := repo.PurgeDeletedOlderThan(ctx, time.Time{})
+	require.Error(t, err)
 
-	// 파일 삭제
-	err = repo.Delete(file.ID)
+	purged, err := repo.PurgeDeletedOlderThan(ctx, now.Add(-24*time.Hour))
 	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
 
-	// 파일 삭제 후 - 존재하지 않음 (소프트 삭제)
-	exists, err = repo.Exists(file.ID)
+	// 레코드가 정말 사라지고 키 정보도 함께 삭제됨
+	assert.ErrorIs(t, db.Unscoped().First(&model.File{}, expired.ID).Error, gorm.ErrRecordNotFound)
+	var count int64
+	require.NoError(t, db.Model(&model.EncryptionMetadata{}).Where("file_id = ?", expired.ID).Count(&count).Error)
+	assert.Zero(t, count)
+	require.NoError(t, db.Model(&model.KeySlot{}).Where("file_id = ?", expired.ID).Count(&count).Error)
+	assert.Zero(t, count)
+
+	for _, kept := range []*model.File{recent, live, owner} {
+		assert.NoError(t, db.Unscoped().First(&model.File{}, kept.ID).Error)
+		require.NoError(t, db.Model(&model.EncryptionMetadata{}).Where("file_id = ?", kept.ID).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	}
+
+	// 다시 실행해도 대상이 없으면 0
+	purged, err = repo.PurgeDeletedOlderThan(ctx, now.Add(-24*time.Hour))
 	require.NoError(t, err)
-	assert.False(t, exists)
+	assert.Zero(t, purged)
 }
 
-func TestFileRepository_Exists_ErrorCases(t *testing.T) {
+func TestFileRepository_Restore(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	repo := NewFileRepository(db)
+	file := createTestFile("_restore")
+	require.NoError(t, repo.Create(ctx, file))
+	require.NoError(t, db.Create(createTestEncryptionMetadata(file.ID)).Error)
+	require.NoError(t, repo.Delete(ctx, file.ID))
 
-	// 잘못된 ID
-	exists, err := repo.Exists(TestInvalidFileID)
-	require.Error(t, err)
-	assert.False(t, exists)
-	assert.Contains(t, err.Error(), "유효하지 않은 파일 ID입니다")
+	// 소프트 삭제된 레코드도 메타데이터와 함께 조회 가능
+	deleted, err := repo.GetByIDWithDeleted(ctx, file.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted.DeletedAt.Valid)
+	require.NotNil(t, deleted.EncryptionMetadata)
+
+	require.NoError(t, repo.Restore(ctx, file.ID))
+
+	restored, err := repo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.False(t, restored.DeletedAt.Valid)
+	require.NotNil(t, restored.EncryptionMetadata)
+	assert.Equal(t, TestValidSaltHex, restored.EncryptionMetadata.SaltHex)
+	assertStoredUTC(t, db, "updated_at", file.ID)
+
+	// 삭제되지 않은 레코드와 없는 레코드는 복구 대상이 아님
+	assert.ErrorIs(t, repo.Restore(ctx, file.ID), model.ErrRecordNotFound)
+	assert.ErrorIs(t, repo.Restore(ctx, TestNonExistentID), model.ErrRecordNotFound)
+	assert.Error(t, repo.Restore(ctx, TestInvalidFileID))
+
+	_, err = repo.GetByIDWithDeleted(ctx, TestNonExistentID)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
 }
 
-func TestFileRepository_Count_Success(t *testing.T) {
+func TestFileRepository_RestorePathConflict(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
+	// 경로 유일성 제약 없이 만들어진 DB를 재현 (제약이 있으면 충돌 레코드를 만들 수 없음)
+	require.NoError(t, db.Migrator().DropConstraint(&model.File{}, "uni_files_encrypted_path"))
+
 	repo := NewFileRepository(db)
+	trashed := createTestFile("_conflict")
+	require.NoError(t, repo.Create(ctx, trashed))
+	require.NoError(t, repo.Delete(ctx, trashed.ID))
 
-	// 초기 카운트 확인
-	count, err := repo.Count()
+	occupant := createTestFile("_conflict")
+	occupant.OriginalName = "occupant.txt"
+	require.NoError(t, repo.Create(ctx, occupant))
+
+	err := repo.Restore(ctx, trashed.ID)
+	require.ErrorIs(t, err, ErrRestoreConflict)
+	assert.NotErrorIs(t, err, model.ErrRecordNotFound)
+
+	// 충돌 시 휴지통에 그대로 남음
+	deleted, err := repo.GetByIDWithDeleted(ctx, trashed.ID)
 	require.NoError(t, err)
-	assert.Zero(t, count)
+	assert.True(t, deleted.DeletedAt.Valid)
 
-	// 파일들 생성
-	fileCount := 5
-	for i := 0; i < fileCount; i++ {
-		file := createTestFile(fmt.Sprintf("_count_%d", i))
-		createErr := repo.Create(file)
-		require.NoError(t, createErr)
+	// 사용 중인 파일이 삭제되면 복구 가능 (휴지통의 다른 레코드는 충돌로 보지 않음)
+	require.NoError(t, repo.Delete(ctx, occupant.ID))
+	require.NoError(t, repo.Restore(ctx, trashed.ID))
+
+	err = repo.Restore(ctx, occupant.ID)
+	assert.ErrorIs(t, err, ErrRestoreConflict, "이번에는 복구한 파일이 경로를 사용 중")
+}
+
+func TestFileRepository_GetDeleted(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	var ids []uint
+	for i := 0; i < 3; i++ {
+		file := createTestFile(fmt.Sprintf("_trash_%d", i))
+		require.NoError(t, repo.Create(ctx, file))
+		ids = append(ids, file.ID)
+	}
+	require.NoError(t, db.Create(createTestEncryptionMetadata(ids[0])).Error)
+	require.NoError(t, repo.Create(ctx, createTestFile("_live")))
+
+	// 삭제 순서를 고정하기 위해 deleted_at을 직접 지정
+	base := time.Now().UTC().Add(-time.Hour)
+	for i, id := range ids {
+		require.NoError(t, db.Model(&model.File{}).Where("id = ?", id).
+			UpdateColumn("deleted_at", base.Add(time.Duration(i)*time.Minute)).Error)
 	}
 
-	// 생성 후 카운트 확인
-	count, err = repo.Count()
+	files, total, err := repo.GetDeleted(ctx, 0, 2)
 	require.NoError(t, err)
-	assert.Equal(t, int64(fileCount), count)
+	assert.Equal(t, int64(3), total, "활성 파일은 제외")
+	require.Len(t, files, 2)
+	assert.Equal(t, ids[2], files[0].ID, "최근 삭제순")
+	assert.Equal(t, ids[1], files[1].ID)
 
-	// 파일 하나 삭제
-	deleteErr := repo.Delete(TestValidFileID)
-	require.NoError(t, deleteErr)
+	files, _, err = repo.GetDeleted(ctx, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, ids[0], files[0].ID)
+	assert.True(t, files[0].DeletedAt.Valid)
+	assert.NotNil(t, files[0].EncryptionMetadata, "메타데이터를 함께 조회")
 
-	// 삭제 후 카운트 확인 (소프트 삭제이므로 카운트 감소)
-	count, err = repo.Count()
+	require.NoError(t, repo.Restore(ctx, ids[0]))
+	_, total, err = repo.GetDeleted(ctx, 0, 10)
 	require.NoError(t, err)
-	assert.Equal(t, int64(fileCount-1), count)
+	assert.Equal(t, int64(2), total)
 }
 
-func TestFileRepository_NormalizePagination(t *testing.T) {
+func TestFileRepository_ListMissingMetadata(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewFileRepository(db).(*fileRepository)
+	repo := NewFileRepository(db)
+	create := func(suffix, status string, withMetadata bool) *model.File {
+		file := createTestFile(suffix)
+		file.Status = status
+		require.NoError(t, repo.Create(ctx, file))
+		if withMetadata {
+			require.NoError(t, db.Create(createTestEncryptionMetadata(file.ID)).Error)
+		}
+		return file
+	}
+
+	create("_with_metadata", model.FileStatusEncrypted, true)
+	create("_pending", model.FileStatusPending, false)
+	missing := create("_missing", model.FileStatusEncrypted, false)
+	deleted := create("_missing_deleted", model.FileStatusEncrypted, false)
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	// 참조 레코드는 원본의 메타데이터를 쓰므로 제외
+	ref := createTestFile("_ref")
+	ref.Status = model.FileStatusEncrypted
+	ref.BlobFileID = &missing.ID
+	require.NoError(t, repo.Create(ctx, ref))
+
+	files, err := repo.ListMissingMetadata(ctx)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, missing.ID, files[0].ID)
+	assert.Equal(t, deleted.ID, files[1].ID)
+	assert.True(t, files[1].DeletedAt.Valid)
+}
+
+func TestFileRepository_GetWithoutMetadata(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	create := func(suffix, status string, purpose string) *model.File {
+		file := createTestFile(suffix)
+		file.Status = status
+		require.NoError(t, repo.Create(ctx, file))
+		if purpose != "" {
+			metadata := createTestEncryptionMetadata(file.ID)
+			metadata.Purpose = purpose
+			require.NoError(t, db.Create(metadata).Error)
+		}
+		return file
+	}
+
+	create("_with_metadata", model.FileStatusEncrypted, model.MetadataPurposePrimary)
+	create("_pending", model.FileStatusPending, "")
+	missing := create("_missing", model.FileStatusEncrypted, "")
+	// primary가 아닌 메타데이터만 있으면 복호화할 수 없으므로 포함
+	versionOnly := create("_version_only", model.FileStatusEncrypted, model.MetadataPurposeVersion)
+	deleted := create("_missing_deleted", model.FileStatusEncrypted, "")
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	ref := createTestFile("_ref")
+	ref.Status = model.FileStatusEncrypted
+	ref.BlobFileID = &missing.ID
+	require.NoError(t, repo.Create(ctx, ref))
+
+	files, total, err := repo.GetWithoutMetadata(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, files, 2)
+	assert.Equal(t, missing.ID, files[0].ID)
+	assert.Equal(t, versionOnly.ID, files[1].ID)
+	assert.Equal(t, missing.OriginalName, files[0].OriginalName, "파일 컬럼을 그대로 조회")
+
+	files, total, err = repo.GetWithoutMetadata(ctx, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, files, 1)
+	assert.Equal(t, versionOnly.ID, files[0].ID)
+}
+
+func TestFileRepository_BulkUpdateStatus(t *testing.T) {
+	ctx := model.WithActor(context.Background(), model.ActorAdmin)
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	now := time.Now()
+	create := func(suffix, status string, updatedAt time.Time) *model.File {
+		file := createTestFile(suffix)
+		file.Status = status
+		if status == model.FileStatusFailed {
+			file.FailureReason = "암호화 실패"
+		}
+		require.NoError(t, repo.Create(ctx, file))
+		require.NoError(t, db.Model(&model.File{}).Where("id = ?", file.ID).UpdateColumn("updated_at", updatedAt.UTC()).Error)
+		return file
+	}
+
+	stale := create("_stale", model.FileStatusPending, now.Add(-2*time.Hour))
+	fresh := create("_fresh", model.FileStatusPending, now)
+	failed := create("_failed", model.FileStatusFailed, now.Add(-2*time.Hour))
+
+	// 방치된 pending만 failed로
+	affected, err := repo.BulkUpdateStatus(ctx, model.FileStatusPending, model.FileStatusFailed, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	got, err := repo.GetByID(ctx, stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStatusFailed, got.Status)
+	assert.Equal(t, stale.Version+1, got.Version)
+	assert.Equal(t, model.ActorAdmin, got.UpdatedBy)
+	assertStoredUTC(t, db, "updated_at", stale.ID)
+
+	got, err = repo.GetByID(ctx, fresh.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStatusPending, got.Status)
+
+	// failed를 다시 pending으로 돌리면 실패 사유를 지움 (방금 바뀐 파일은 기준 시각 이후라 제외)
+	affected, err = repo.BulkUpdateStatus(ctx, model.FileStatusFailed, model.FileStatusPending, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	got, err = repo.GetByID(ctx, failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStatusPending, got.Status)
+	assert.Empty(t, got.FailureReason)
+
+	// 바뀐 행이 없어도 에러가 아님
+	affected, err = repo.BulkUpdateStatus(ctx, model.FileStatusCorrupted, model.FileStatusFailed, now)
+	require.NoError(t, err)
+	assert.Zero(t, affected)
+
+	for name, tc := range map[string]struct{ from, to string }{
+		"encrypted로 전환": {model.FileStatusPending, model.FileStatusEncrypted},
+		"같은 상태":         {model.FileStatusFailed, model.FileStatusFailed},
+		"잘못된 상태":        {"unknown", model.FileStatusFailed},
+	} {
+		_, err := repo.BulkUpdateStatus(ctx, tc.from, tc.to, now)
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition, name)
+	}
+	_, err = repo.BulkUpdateStatus(ctx, model.FileStatusPending, model.FileStatusFailed, time.Time{})
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+}
+
+func TestFileRepository_GetByExternalID(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	file := createTestFile("_external")
+	file.ExternalID = "erp-42"
+	require.NoError(t, repo.Create(ctx, file))
+	// 외부 참조가 없는 파일은 여러 개여도 됨
+	require.NoError(t, repo.Create(ctx, createTestFile("_plain1")))
+	require.NoError(t, repo.Create(ctx, createTestFile("_plain2")))
+
+	found, err := repo.GetByExternalID(ctx, "erp-42")
+	require.NoError(t, err)
+	assert.Equal(t, file.ID, found.ID)
+
+	// 삭제되지 않은 파일끼리는 같은 ID를 쓸 수 없음
+	dup := createTestFile("_external_dup")
+	dup.ExternalID = "erp-42"
+	assert.ErrorIs(t, repo.Create(ctx, dup), ErrDuplicate)
+
+	// 삭제된 파일의 ID는 다시 사용할 수 있고 조회되지 않음
+	require.NoError(t, repo.Delete(ctx, file.ID))
+	_, err = repo.GetByExternalID(ctx, "erp-42")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	require.NoError(t, repo.Create(ctx, dup))
+
+	found, err = repo.GetByExternalID(ctx, "erp-42")
+	require.NoError(t, err)
+	assert.Equal(t, dup.ID, found.ID)
+
+	_, err = repo.GetByExternalID(ctx, "")
+	assert.ErrorIs(t, err, ErrInvalidID)
+	_, err = repo.GetByExternalID(ctx, strings.Repeat("a", model.MaxExternalIDLength+1))
+	assert.ErrorIs(t, err, ErrInvalidID)
+
+	tooLong := createTestFile("_external_long")
+	tooLong.ExternalID = strings.Repeat("a", model.MaxExternalIDLength+1)
+	assert.ErrorIs(t, repo.Create(ctx, tooLong), model.ErrExternalIDTooLong)
+}
+
+func TestFileRepository_ShortCode(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	file := createTestFile("_code")
+	require.NoError(t, repo.Create(ctx, file))
+	require.True(t, model.IsValidShortCode(file.ShortCode), file.ShortCode)
+
+	// 소문자, 하이픈, 혼동 문자로 입력해도 같은 파일
+	typed := strings.ToLower(file.ShortCode[:4] + "-" + file.ShortCode[4:])
+	typed = strings.NewReplacer("1", "l", "0", "o").Replace(typed)
+	found, err := repo.GetByShortCode(ctx, typed)
+	require.NoError(t, err)
+	assert.Equal(t, file.ID, found.ID)
+
+	// Update는 코드를 지우거나 되돌리지 않음
+	original := file.ShortCode
+	file.ShortCode = ""
+	require.NoError(t, repo.Update(ctx, file))
+	found, err = repo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, original, found.ShortCode)
+
+	// 재발급하면 이전 코드는 조회되지 않고 버전은 그대로
+	reissued, err := repo.ReissueShortCode(ctx, file.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, original, reissued)
+	_, err = repo.GetByShortCode(ctx, original)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	found, err = repo.GetByShortCode(ctx, reissued)
+	require.NoError(t, err)
+	assert.Equal(t, found.Version, file.Version)
+
+	// 삭제된 파일은 조회되지도 재발급되지도 않음
+	require.NoError(t, repo.Delete(ctx, file.ID))
+	_, err = repo.GetByShortCode(ctx, reissued)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, err = repo.ReissueShortCode(ctx, file.ID)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	_, err = repo.GetByShortCode(ctx, "ABC")
+	assert.ErrorIs(t, err, ErrInvalidID)
+	_, err = repo.GetByShortCode(ctx, "ABCDEFGU")
+	assert.ErrorIs(t, err, ErrInvalidID)
+	_, err = repo.ReissueShortCode(ctx, 0)
+	assert.ErrorIs(t, err, ErrInvalidID)
+}
+
+func TestFileRepository_ShortCodeCollisionRetry(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 앞의 몇 개는 이미 쓰인 코드를 내놓는 생성기
+	queue := []string{"AAAAAAAA", "AAAAAAAA", "BBBBBBBB"}
+	repo := &fileRepository{db: db, clock: clock.Real{}, newShortCode: func() (string, error) {
+		code := queue[0]
+		queue = queue[1:]
+		return code, nil
+	}}
+
+	first := createTestFile("_first")
+	require.NoError(t, repo.Create(ctx, first))
+	assert.Equal(t, "AAAAAAAA", first.ShortCode)
+
+	second := createTestFile("_second")
+	require.NoError(t, repo.Create(ctx, second))
+	assert.Equal(t, "BBBBBBBB", second.ShortCode, "겹치면 새 코드로 다시 시도")
+
+	// 배치는 기존 코드와 배치 안의 코드를 모두 피함
+	batch := []*model.File{createTestFile("_b1"), createTestFile("_b2")}
+	queue = []string{"AAAAAAAA", "CCCCCCCC", "CCCCCCCC", "CCCCCCCC", "DDDDDDDD"}
+	require.NoError(t, repo.CreateBatch(ctx, batch))
+	assert.Equal(t, "CCCCCCCC", batch[0].ShortCode)
+	assert.Equal(t, "DDDDDDDD", batch[1].ShortCode)
+	assert.Empty(t, queue)
+
+	// 계속 겹치면 시도 횟수 안에서 포기
+	queue = slices.Repeat([]string{"AAAAAAAA"}, maxShortCodeAttempts)
+	assert.ErrorIs(t, repo.Create(ctx, createTestFile("_stuck")), ErrDuplicate)
+	assert.Empty(t, queue)
+
+	// 호출자가 지정한 코드가 겹치면 다시 만들지 않음
+	explicit := createTestFile("_explicit")
+	explicit.ShortCode = "BBBBBBBB"
+	assert.ErrorIs(t, repo.Create(ctx, explicit), ErrDuplicate)
+
+	// 다른 제약조건 위반은 다시 시도하지 않음
+	queue = []string{"FFFFFFFF"}
+	dupPath := createTestFile("_first")
+	assert.ErrorIs(t, repo.Create(ctx, dupPath), ErrDuplicate)
+	assert.Empty(t, queue)
+}
+
+func TestFileRepository_OwnerScoping(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	alice := &model.User{Username: "alice", PasswordHash: "hash-a"}
+	bob := &model.User{Username: "bob", PasswordHash: "hash-b"}
+	require.NoError(t, db.Create(alice).Error)
+	require.NoError(t, db.Create(bob).Error)
+
+	repo := NewFileRepository(db)
+	newOwned := func(suffix string, owner *model.User) *model.File {
+		file := createTestFile(suffix)
+		if owner != nil {
+			file.OwnerID = &owner.ID
+		}
+		require.NoError(t, repo.Create(ctx, file))
+		return file
+	}
+	aliceFiles := []*model.File{newOwned("_alice1", alice), newOwned("_alice2", alice)}
+	bobFile := newOwned("_bob", bob)
+	legacy := newOwned("_legacy", nil)
+
+	// 자기 파일은 조회됨
+	found, err := repo.GetByIDForOwner(ctx, aliceFiles[0].ID, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, aliceFiles[0].ID, found.ID)
+	require.NotNil(t, found.OwnerID)
+	assert.Equal(t, alice.ID, *found.OwnerID)
+
+	// 다른 사용자의 파일과 소유자 없는 파일은 없는 파일과 같은 에러
+	_, err = repo.GetByIDForOwner(ctx, bobFile.ID, alice.ID)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, err = repo.GetByIDForOwner(ctx, legacy.ID, alice.ID)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, missingErr := repo.GetByIDForOwner(ctx, legacy.ID+100, alice.ID)
+	assert.Equal(t, missingErr.Error(), strings.Replace(err.Error(), fmt.Sprint(legacy.ID), fmt.Sprint(legacy.ID+100), 1))
+
+	// 목록은 자기 파일만 최신순
+	files, total, err := repo.GetAllByOwner(ctx, alice.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, files, 2)
+	for _, file := range files {
+		assert.Equal(t, alice.ID, *file.OwnerID)
+	}
+
+	files, total, err = repo.GetAllByOwner(ctx, bob.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, bobFile.ID, files[0].ID)
+
+	// 삭제된 파일은 제외
+	require.NoError(t, repo.Delete(ctx, bobFile.ID))
+	_, total, err = repo.GetAllByOwner(ctx, bob.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	_, err = repo.GetByIDForOwner(ctx, bobFile.ID, bob.ID)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	_, _, err = repo.GetAllByOwner(ctx, 0, 0, 10)
+	assert.ErrorIs(t, err, ErrInvalidID)
+	_, err = repo.GetByIDForOwner(ctx, aliceFiles[0].ID, 0)
+	assert.ErrorIs(t, err, ErrInvalidID)
+	_, err = repo.GetByIDForOwner(ctx, 0, alice.ID)
+	assert.ErrorIs(t, err, ErrInvalidID)
+}
+
+func TestFileRepository_Tags(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	report := createTestFile("_report")
+	receipt := createTestFile("_receipt")
+	photo := createTestFile("_photo")
+	require.NoError(t, repo.CreateBatch(ctx, []*model.File{report, receipt, photo}))
+
+	// 이름은 정규화하고, 같은 태그를 여러 번 붙여도 하나만 연결
+	require.NoError(t, repo.AddTags(ctx, report.ID, []string{"Tax-2024", " tax-2024 ", "personal"}))
+	require.NoError(t, repo.AddTags(ctx, receipt.ID, []string{"TAX-2024"}))
+	require.NoError(t, repo.AddTags(ctx, report.ID, []string{"personal"}))
+	require.NoError(t, repo.AddTags(ctx, photo.ID, nil))
+
+	var tagCount int64
+	require.NoError(t, db.Model(&model.Tag{}).Count(&tagCount).Error)
+	assert.Equal(t, int64(2), tagCount)
+
+	files, total, err := repo.GetByTag(ctx, "tax-2024", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.ElementsMatch(t, []uint{report.ID, receipt.ID}, []uint{files[0].ID, files[1].ID})
+
+	files, total, err = repo.GetByTag(ctx, " Personal", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, report.ID, files[0].ID)
+
+	_, total, err = repo.GetByTag(ctx, "unknown", 0, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	// 잘못된 이름이 하나라도 있으면 아무것도 붙이지 않음
+	err = repo.AddTags(ctx, photo.ID, []string{"holiday", strings.Repeat("x", model.MaxTagNameLength+1)})
+	assert.ErrorIs(t, err, model.ErrTagNameTooLong)
+	_, total, err = repo.GetByTag(ctx, "holiday", 0, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	_, _, err = repo.GetByTag(ctx, "  ", 0, 10)
+	assert.ErrorIs(t, err, model.ErrEmptyTagName)
+	err = repo.AddTags(ctx, photo.ID+100, []string{"holiday"})
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	assert.ErrorIs(t, repo.AddTags(ctx, 0, []string{"holiday"}), ErrInvalidID)
+
+	// 태그를 떼면 해당 파일만 빠지고 태그는 남음
+	require.NoError(t, repo.RemoveTag(ctx, receipt.ID, "Tax-2024"))
+	_, total, err = repo.GetByTag(ctx, "tax-2024", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.ErrorIs(t, repo.RemoveTag(ctx, receipt.ID, "tax-2024"), model.ErrRecordNotFound)
+	assert.ErrorIs(t, repo.RemoveTag(ctx, receipt.ID, ""), model.ErrEmptyTagName)
+
+	// 소프트 삭제한 파일은 목록에서 빠지지만 복구하면 태그도 돌아옴
+	require.NoError(t, repo.Delete(ctx, report.ID))
+	_, total, err = repo.GetByTag(ctx, "personal", 0, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	require.NoError(t, repo.Restore(ctx, report.ID))
+	_, total, err = repo.GetByTag(ctx, "personal", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// 영구 삭제하면 연결 행도 삭제
+	require.NoError(t, repo.Purge(ctx, report.ID))
+	var links int64
+	require.NoError(t, db.Table("file_tags").Where("file_id = ?", report.ID).Count(&links).Error)
+	assert.Zero(t, links)
+
+	// 필터로도 다른 조건과 함께 사용
+	require.NoError(t, repo.AddTags(ctx, photo.ID, []string{"tax-2024"}))
+	files, total, err = repo.Find(ctx, FileFilter{Tag: "TAX-2024", NameContains: "photo"}, Pagination{Limit: 10}, SortOption{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, photo.ID, files[0].ID)
+	_, _, err = repo.Find(ctx, FileFilter{Tag: strings.Repeat("x", model.MaxTagNameLength+1)}, Pagination{}, SortOption{})
+	assert.ErrorIs(t, err, ErrInvalidFileFilter)
+}
+
+func TestFileRepository_RecentActivity(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 이벤트가 없으면 빈 목록
+	activities, total, err := repo.RecentActivity(ctx, 0, Pagination{Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.NotNil(t, activities)
+	assert.Empty(t, activities)
+
+	owner := &model.User{Username: "alice", PasswordHash: "hash"}
+	require.NoError(t, db.Create(owner).Error)
+
+	base := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	newFile := func(suffix string, minutes int, ownerID *uint) *model.File {
+		file := createTestFile(suffix)
+		file.CreatedAt = base.Add(time.Duration(minutes) * time.Minute)
+		file.OwnerID = ownerID
+		require.NoError(t, repo.Create(model.WithActor(ctx, "uploader"), file))
+		return file
+	}
+	first := newFile("_first", 0, &owner.ID)
+	second := newFile("_second", 1, nil)
+	broken := newFile("_broken", 2, &owner.ID)
+
+	// 실패와 삭제는 그 뒤에 일어나고, 삭제한 행위자가 기록됨
+	require.NoError(t, repo.UpdateStatus(model.WithActor(ctx, model.ActorSystem), broken.ID, model.FileStatusFailed))
+	time.Sleep(5 * time.Millisecond) // 시각 비교는 밀리초 단위
+	require.NoError(t, repo.Delete(model.WithActor(ctx, model.ActorAdmin), first.ID))
+
+	activities, total, err = repo.RecentActivity(ctx, 0, Pagination{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	require.Len(t, activities, 5)
+
+	type event struct {
+		Type   string
+		FileID uint
+		Actor  string
+	}
+	got := make([]event, len(activities))
+	for i, activity := range activities {
+		got[i] = event{activity.Type, activity.FileID, activity.Actor}
+		assert.Equal(t, time.UTC, activity.OccurredAt.Location())
+	}
+	assert.Equal(t, []event{
+		{ActivityDeleted, first.ID, model.ActorAdmin},
+		{ActivityFailed, broken.ID, model.ActorSystem},
+		{ActivityUploaded, broken.ID, "uploader"},
+		{ActivityUploaded, second.ID, "uploader"},
+		{ActivityUploaded, first.ID, "uploader"},
+	}, got, "최신순, 삭제된 파일의 업로드 이벤트도 유지")
+	assert.Equal(t, "test_first.txt", activities[0].FileName)
+	assert.True(t, activities[4].OccurredAt.Equal(base))
+
+	// 페이지네이션
+	activities, total, err = repo.RecentActivity(ctx, 0, Pagination{Offset: 3, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	require.Len(t, activities, 2)
+	assert.Equal(t, second.ID, activities[0].FileID)
+
+	// 소유자 범위
+	activities, total, err = repo.RecentActivity(ctx, owner.ID, Pagination{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	for _, activity := range activities {
+		assert.NotEqual(t, second.ID, activity.FileID)
+	}
+
+	_, total, err = repo.RecentActivity(ctx, owner.ID+1, Pagination{Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestFileRepository_CountBlobReferences(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	blob := createTestFile("_blob")
+	require.NoError(t, repo.Create(ctx, blob))
+
+	count, err := repo.CountBlobReferences(ctx, blob.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	for i := range 2 {
+		ref := createTestFile(fmt.Sprintf("_ref%d", i))
+		ref.BlobFileID = &blob.ID
+		require.NoError(t, repo.Create(ctx, ref))
+	}
+
+	count, err = repo.CountBlobReferences(ctx, blob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = repo.CountBlobReferences(ctx, TestInvalidFileID)
+	assert.Error(t, err)
+}
+
+func TestFileRepository_GetByStatus_Success(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 다양한 상태의 파일들 생성
+	statuses := []string{
+		model.FileStatusPending,
+		model.FileStatusEncrypted,
+		model.FileStatusFailed,
+	}
+
+	filesPerStatus := 3
+	for _, status := range statuses {
+		for i := 0; i < filesPerStatus; i++ {
+			file := createTestFile(fmt.Sprintf("_%s_%d", status, i))
+			file.Status = status
+			err := repo.Create(ctx, file)
+			require.NoError(t, err)
+		}
+	}
+
+	// 각 상태별 조회 테스트
+	for _, status := range statuses {
+		t.Run(fmt.Sprintf("status_%s", status), func(t *testing.T) {
+			files, total, err := repo.GetByStatus(ctx, status, 0, DefaultPageSize, SortOption{})
+			require.NoError(t, err)
+			assert.Len(t, files, filesPerStatus)
+			assert.Equal(t, int64(filesPerStatus), total)
+
+			// 모든 파일이 요청한 상태인지 확인
+			for _, file := range files {
+				assert.Equal(t, status, file.Status)
+			}
+		})
+	}
+}
+
+func TestFileRepository_GetByStatus_ErrorCases(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	testCases := []struct {
+		name    string
+		status  string
+		wantErr string
+	}{
+		{
+			name:    "빈 상태",
+			status:  "",
+			wantErr: "상태 값이 필요합니다",
+		},
+		{
+			name:    "잘못된 상태",
+			status:  "invalid_status",
+			wantErr: "유효하지 않은 파일 상태입니다",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files, total, err := repo.GetByStatus(ctx, tc.status, 0, DefaultPageSize, SortOption{})
+			require.Error(t, err)
+			assert.Nil(t, files)
+			assert.Zero(t, total)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestFileRepository_GetStalePending(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	now := time.Now()
+
+	// updatedAgo만큼 전에 마지막으로 갱신된 파일 (생성은 그보다 한 시간 전)
+	seed := func(suffix, status string, updatedAgo time.Duration) *model.File {
+		file := createTestFile("_stale" + suffix)
+		file.Status = status
+		require.NoError(t, repo.Create(ctx, file))
+		require.NoError(t, db.Model(file).UpdateColumns(map[string]any{
+			"created_at": now.Add(-updatedAgo - time.Hour).UTC(),
+			"updated_at": now.Add(-updatedAgo).UTC(),
+		}).Error)
+		return file
+	}
+
+	oldest := seed("_oldest", model.FileStatusPending, 3*time.Hour)
+	stale := seed("_pending", model.FileStatusPending, 2*time.Hour)
+	seed("_fresh", model.FileStatusPending, 10*time.Minute)
+	seed("_encrypted", model.FileStatusEncrypted, 2*time.Hour)
+	seed("_failed", model.FileStatusFailed, 2*time.Hour)
+
+	// 오래전에 생성됐어도 최근에 갱신된 파일은 제외
+	touched := seed("_touched", model.FileStatusPending, 5*time.Minute)
+	require.NoError(t, db.Model(touched).UpdateColumn("created_at", now.Add(-5*time.Hour).UTC()).Error)
+
+	files, total, err := repo.GetStalePending(ctx, time.Hour, 0, MaxPageSize)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, files, 2)
+	assert.Equal(t, []uint{oldest.ID, stale.ID}, []uint{files[0].ID, files[1].ID}, "오래된 순")
+
+	files, total, err = repo.GetStalePending(ctx, time.Hour, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, files, 1)
+	assert.Equal(t, stale.ID, files[0].ID)
+
+	// 기준을 늘리면 더 오래된 파일만
+	files, _, err = repo.GetStalePending(ctx, 150*time.Minute, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, oldest.ID, files[0].ID)
+
+	// 삭제된 파일은 제외
+	require.NoError(t, repo.Delete(ctx, oldest.ID))
+	_, total, err = repo.GetStalePending(ctx, time.Hour, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	_, _, err = repo.GetStalePending(ctx, 0, 0, 0)
+	assert.Error(t, err)
+}
+
+func TestFileRepository_Touch(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	file := createTestFile("_touch")
+	require.NoError(t, repo.Create(ctx, file))
+	assert.Nil(t, file.LastAccessedAt)
+
+	before := time.Now().Add(-time.Second)
+	require.NoError(t, repo.Touch(ctx, file.ID))
+
+	// 접근 시각만 기록되고 버전과 updated_at은 그대로
+	found, err := repo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.LastAccessedAt)
+	assert.True(t, found.LastAccessedAt.After(before))
+	assert.Equal(t, file.Version, found.Version)
+	assert.True(t, file.UpdatedAt.Equal(found.UpdatedAt))
+
+	assert.ErrorIs(t, repo.Touch(ctx, 0), ErrInvalidID)
+	assert.ErrorIs(t, repo.Touch(ctx, file.ID+100), ErrNotFound)
+
+	require.NoError(t, repo.Delete(ctx, file.ID))
+	assert.ErrorIs(t, repo.Touch(ctx, file.ID), ErrNotFound)
+}
+
+func TestFileRepository_GetNotAccessedSince(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	now := time.Now()
+
+	// accessedAgo만큼 전에 마지막으로 접근한 파일 (0이면 접근 기록 없음)
+	seed := func(suffix string, accessedAgo time.Duration) *model.File {
+		file := createTestFile("_access" + suffix)
+		require.NoError(t, repo.Create(ctx, file))
+		if accessedAgo > 0 {
+			require.NoError(t, db.Model(file).UpdateColumn("last_accessed_at", now.Add(-accessedAgo).UTC()).Error)
+		}
+		return file
+	}
+
+	old := seed("_old", 100*24*time.Hour)
+	never := seed("_never", 0)
+	older := seed("_older", 200*24*time.Hour)
+	seed("_recent", 24*time.Hour)
+
+	cutoff := now.Add(-90 * 24 * time.Hour)
+	files, total, err := repo.GetNotAccessedSince(ctx, cutoff, 0, MaxPageSize)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, files, 3)
+	assert.Equal(t, []uint{never.ID, older.ID, old.ID}, []uint{files[0].ID, files[1].ID, files[2].ID}, "접근 기록 없음, 오래된 순")
+
+	// 접근 기록이 없는 파일은 어떤 기준보다도 오래된 것으로 취급
+	files, total, err = repo.GetNotAccessedSince(ctx, now.Add(-10*365*24*time.Hour), 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, files, 1)
+	assert.Equal(t, never.ID, files[0].ID)
+
+	// 접근하면 목록에서 빠짐
+	require.NoError(t, repo.Touch(ctx, old.ID))
+	files, total, err = repo.GetNotAccessedSince(ctx, cutoff, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, files, 1)
+	assert.Equal(t, older.ID, files[0].ID)
+
+	_, _, err = repo.GetNotAccessedSince(ctx, time.Time{}, 0, 0)
+	assert.Error(t, err)
+}
+
+func TestFileRepository_GetByMimeType(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 섞인 MIME 타입: PDF 5개, 이미지 7개(png 4, JPEG 3), 텍스트 2개
+	mimeTypes := []string{
+		"application/pdf", "image/png", "image/JPEG", "text/plain", "application/pdf", "image/png",
+		"image/jpeg", "application/pdf", "image/png", "text/plain", "application/pdf", "image/jpeg",
+		"application/pdf", "image/png",
+	}
+	for i, mimeType := range mimeTypes {
+		file := createTestFile(fmt.Sprintf("_mime_%d", i))
+		file.MimeType = mimeType
+		require.NoError(t, repo.Create(ctx, file))
+	}
+	// 이름만 비슷한 다른 계열은 접두 일치에 포함되지 않음
+	other := createTestFile("_mime_other")
+	other.MimeType = "imagex/custom"
+	require.NoError(t, repo.Create(ctx, other))
+
+	testCases := []struct {
+		name      string
+		filter    string
+		wantTotal int64
+	}{
+		{name: "정확히 일치", filter: "application/pdf", wantTotal: 5},
+		{name: "대소문자 무시", filter: "IMAGE/jpeg", wantTotal: 3},
+		{name: "계열 접두 일치", filter: "image/", wantTotal: 7},
+		{name: "계열 와일드카드", filter: "image/*", wantTotal: 7},
+		{name: "결과 없음", filter: "video/mp4", wantTotal: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files, total, err := repo.GetByMimeType(ctx, tc.filter, 0, MaxPageSize)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTotal, total)
+			assert.Len(t, files, int(tc.wantTotal))
+		})
+	}
+
+	t.Run("페이지네이션", func(t *testing.T) {
+		seen := make(map[uint]bool)
+		for offset := 0; offset < 7; offset += 3 {
+			files, total, err := repo.GetByMimeType(ctx, "image/", offset, 3)
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), total)
+			assert.Len(t, files, min(3, 7-offset))
+			for _, file := range files {
+				assert.True(t, strings.HasPrefix(strings.ToLower(file.MimeType), "image/"))
+				assert.False(t, seen[file.ID], "페이지 간 중복")
+				seen[file.ID] = true
+			}
+		}
+		assert.Len(t, seen, 7)
+	})
+
+	t.Run("잘못된 필터", func(t *testing.T) {
+		for _, filter := range []string{"", "pdf", "/pdf", "image/%", "image/p ng", "a/b/c", strings.Repeat("a", model.MaxMimeTypeLength) + "/x"} {
+			_, _, err := repo.GetByMimeType(ctx, filter, 0, 0)
+			assert.ErrorIs(t, err, ErrInvalidMimeFilter, filter)
+		}
+	})
+}
+
+func TestFileRepository_GetByDateRange(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 2주 전부터 한 주 동안 하루 하나씩, 짝수 날은 실패 + 주 경계 바로 앞뒤
+	weekStart := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -14)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	createdAt := []time.Time{weekStart.Add(-time.Second), weekEnd}
+	for day := range 7 {
+		createdAt = append(createdAt, weekStart.AddDate(0, 0, day).Add(12*time.Hour))
+	}
+	for i, at := range createdAt {
+		file := createTestFile(fmt.Sprintf("_range_%d", i))
+		file.CreatedAt = at
+		if i >= 2 && (i-2)%2 == 0 {
+			file.Status = model.FileStatusFailed
+		}
+		require.NoError(t, repo.Create(ctx, file))
+	}
+	recent := createTestFile("_range_recent")
+	recent.CreatedAt = time.Now().UTC().Add(-time.Minute)
+	require.NoError(t, repo.Create(ctx, recent))
+
+	seoul := time.FixedZone("KST", 9*60*60)
+
+	testCases := []struct {
+		name      string
+		from      time.Time
+		to        time.Time
+		status    string
+		wantTotal int64
+	}{
+		{name: "주 단위 (끝은 제외)", from: weekStart, to: weekEnd, wantTotal: 7},
+		{name: "상태 필터", from: weekStart, to: weekEnd, status: model.FileStatusFailed, wantTotal: 4},
+		{name: "다른 시간대도 UTC로 비교", from: weekStart.In(seoul), to: weekStart.AddDate(0, 0, 2).In(seoul), wantTotal: 2},
+		{name: "종료 생략은 현재까지", from: weekEnd, wantTotal: 2},
+		{name: "결과 없음", from: weekStart.AddDate(-1, 0, 0), to: weekStart.AddDate(0, -1, 0), wantTotal: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files, total, err := repo.GetByDateRange(ctx, tc.from, tc.to, tc.status, 0, MaxPageSize)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTotal, total)
+			assert.Len(t, files, int(tc.wantTotal))
+			for _, file := range files {
+				if tc.status != "" {
+					assert.Equal(t, tc.status, file.Status)
+				}
+			}
+		})
+	}
+
+	t.Run("종료 생략은 저장소 시계 기준", func(t *testing.T) {
+		fixed := &fileRepository{db: db, newShortCode: model.NewShortCode, clock: clock.NewFake(weekEnd)}
+		_, total, err := fixed.GetByDateRange(ctx, weekStart, time.Time{}, "", 0, MaxPageSize)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), total)
+	})
+
+	t.Run("최신순 페이지네이션", func(t *testing.T) {
+		files, total, err := repo.GetByDateRange(ctx, weekStart, weekEnd, "", 2, 3)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), total)
+		require.Len(t, files, 3)
+		assert.True(t, files[0].CreatedAt.Equal(weekStart.AddDate(0, 0, 4).Add(12*time.Hour)))
+		assert.True(t, files[0].CreatedAt.After(files[1].CreatedAt))
+	})
+
+	t.Run("잘못된 기간", func(t *testing.T) {
+		for name, r := range map[string][2]time.Time{
+			"시작 없음":  {{}, weekEnd},
+			"뒤집힌 기간": {weekEnd, weekStart},
+			"길이 0":   {weekStart, weekStart},
+		} {
+			_, _, err := repo.GetByDateRange(ctx, r[0], r[1], "", 0, 0)
+			assert.ErrorIs(t, err, ErrInvalidDateRange, name)
+		}
+
+		_, _, err := repo.GetByDateRange(ctx, weekStart, weekEnd, "unknown", 0, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestFileRepository_Find(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := []struct {
+		name   string
+		mime   string
+		size   int64
+		status string
+	}{
+		{"Report_2024.pdf", "application/pdf", 500, model.FileStatusEncrypted},
+		{"photo.png", "image/png", 2048, model.FileStatusEncrypted},
+		{"photo_small.JPG", "IMAGE/JPEG", 100, model.FileStatusFailed},
+		{"100%_done.txt", "text/plain", 10, model.FileStatusPending},
+	}
+	for i, row := range seed {
+		file := createTestFile(fmt.Sprintf("_find_%d", i))
+		file.OriginalName = row.name
+		file.MimeType = row.mime
+		file.Size = row.size
+		file.Status = row.status
+		file.CreatedAt = base.AddDate(0, 0, i)
+		if i == 1 {
+			file.ExternalID = "erp-1"
+		}
+		require.NoError(t, repo.Create(ctx, file))
+	}
+
+	testCases := []struct {
+		name      string
+		filter    FileFilter
+		wantNames []string
+	}{
+		{name: "조건 없음", filter: FileFilter{}, wantNames: []string{"100%_done.txt", "photo_small.JPG", "photo.png", "Report_2024.pdf"}},
+		{name: "상태", filter: FileFilter{Status: model.FileStatusEncrypted}, wantNames: []string{"photo.png", "Report_2024.pdf"}},
+		{name: "MIME 접두사 (대소문자 무시)", filter: FileFilter{MimePrefix: "Image/"}, wantNames: []string{"photo_small.JPG", "photo.png"}},
+		{name: "이름 부분 일치", filter: FileFilter{NameContains: "REPORT"}, wantNames: []string{"Report_2024.pdf"}},
+		{name: "이름의 %는 문자 그대로", filter: FileFilter{NameContains: "0%"}, wantNames: []string{"100%_done.txt"}},
+		{name: "외부 참조 ID", filter: FileFilter{ExternalID: "erp-1"}, wantNames: []string{"photo.png"}},
+		{name: "크기 범위 (경계 포함)", filter: FileFilter{MinSize: 100, MaxSize: 500}, wantNames: []string{"photo_small.JPG", "Report_2024.pdf"}},
+		{name: "최소 크기만", filter: FileFilter{MinSize: 501}, wantNames: []string{"photo.png"}},
+		{name: "기간 (끝은 제외)", filter: FileFilter{CreatedAfter: base.AddDate(0, 0, 1), CreatedBefore: base.AddDate(0, 0, 3)}, wantNames: []string{"photo_small.JPG", "photo.png"}},
+		{name: "조건 조합", filter: FileFilter{MimePrefix: "image/", Status: model.FileStatusFailed, MaxSize: 1000}, wantNames: []string{"photo_small.JPG"}},
+		{name: "결과 없음", filter: FileFilter{MimePrefix: "video/"}, wantNames: []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files, total, err := repo.Find(ctx, tc.filter, Pagination{Limit: MaxPageSize}, SortOption{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tc.wantNames)), total)
+			names := make([]string, 0, len(files))
+			for _, file := range files {
+				names = append(names, file.OriginalName)
+			}
+			assert.Equal(t, tc.wantNames, names)
+		})
+	}
+
+	t.Run("정렬과 페이지네이션", func(t *testing.T) {
+		files, total, err := repo.Find(ctx, FileFilter{MinSize: 50}, Pagination{Offset: 1, Limit: 1},
+			SortOption{Field: SortFieldSize, Direction: SortAsc})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total, "전체 건수는 페이지와 무관")
+		require.Len(t, files, 1)
+		assert.Equal(t, "Report_2024.pdf", files[0].OriginalName)
+	})
+
+	t.Run("잘못된 조건", func(t *testing.T) {
+		for name, filter := range map[string]FileFilter{
+			"최소가 최대보다 큼": {MinSize: 10, MaxSize: 5},
+			"음수 크기":      {MinSize: -1},
+			"뒤집힌 기간":     {CreatedAfter: base.AddDate(0, 0, 1), CreatedBefore: base},
+			"알 수 없는 상태":  {Status: "unknown"},
+		} {
+			_, _, err := repo.Find(ctx, filter, Pagination{}, SortOption{})
+			assert.ErrorIs(t, err, ErrInvalidFileFilter, name)
+		}
+
+		_, _, err := repo.Find(ctx, FileFilter{}, Pagination{}, SortOption{Field: "checksum_md5"})
+		assert.ErrorIs(t, err, ErrInvalidSortOption)
+	})
+}
+
+func TestFileRepository_GetByChecksumMD5_Success(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	file := createTestFile("_checksum")
+	uniqueChecksum := "unique123456789abcdef0123456789ab"
+	file.ChecksumMD5 = uniqueChecksum
+
+	// 파일 생성
+	err := repo.Create(ctx, file)
+	require.NoError(t, err)
+
+	// 체크섬으로 조회
+	foundFile, err := repo.GetByChecksumMD5(ctx, uniqueChecksum)
+	require.NoError(t, err)
+	require.NotNil(t, foundFile)
+	assert.Equal(t, file.ID, foundFile.ID)
+	assert.Equal(t, uniqueChecksum, foundFile.ChecksumMD5)
+
+	// 존재하지 않는 체크섬 조회
+	notFoundFile, err := repo.GetByChecksumMD5(ctx, "nonexistent_checksum")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	assert.Nil(t, notFoundFile)
+}
+
+func TestFileRepository_GetByChecksumMD5_ErrorCases(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 빈 체크섬
+	file, err := repo.GetByChecksumMD5(ctx, "")
+	require.Error(t, err)
+	assert.Nil(t, file)
+	assert.Contains(t, err.Error(), "체크섬 값이 필요합니다")
+}
+
+func TestFileRepository_DuplicateChecksums(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	const (
+		shared = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		single = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		large  = "cccccccccccccccccccccccccccccccc"
+	)
+	create := func(suffix, checksum string, size int64) *model.File {
+		file := createTestFile(suffix)
+		file.ChecksumMD5 = checksum
+		file.Size = size
+		require.NoError(t, repo.Create(ctx, file))
+		return file
+	}
+
+	// 세 파일 중 두 파일이 체크섬을 공유
+	first := create("_dup_first", shared, 100)
+	second := create("_dup_second", shared, 100)
+	create("_dup_single", single, 100)
+
+	files, err := repo.GetAllByChecksum(ctx, shared)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, first.ID, files[0].ID, "생성순이며 첫 파일은 GetByChecksumMD5와 같음")
+	assert.Equal(t, second.ID, files[1].ID)
+
+	duplicates, total, err := repo.FindDuplicateChecksums(ctx, 0, 0, DefaultPageSize)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, DuplicateChecksum{Checksum: shared, Files: 2, Blobs: 2, Size: 100}, duplicates[0])
+	assert.Equal(t, int64(100), duplicates[0].ReclaimableBytes())
+
+	// 크기가 큰 묶음이 파일 수가 같으면 먼저, blob 참조 레코드는 용량을 아낄 수 없음
+	owner := create("_dup_large_owner", large, 500)
+	ref := createTestFile("_dup_large_ref")
+	ref.ChecksumMD5 = large
+	ref.Size = 500
+	ref.BlobFileID = &owner.ID
+	require.NoError(t, repo.Create(ctx, ref))
+
+	// 소프트 삭제된 파일은 제외되어 single은 중복이 아님
+	deleted := create("_dup_single_deleted", single, 100)
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	duplicates, total, err = repo.FindDuplicateChecksums(ctx, 2, 0, DefaultPageSize)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, duplicates, 2)
+	assert.Equal(t, DuplicateChecksum{Checksum: large, Files: 2, Blobs: 1, Size: 500}, duplicates[0])
+	assert.Zero(t, duplicates[0].ReclaimableBytes())
+	assert.Equal(t, shared, duplicates[1].Checksum)
+
+	// 페이지네이션과 최소 개수
+	duplicates, total, err = repo.FindDuplicateChecksums(ctx, 2, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, shared, duplicates[0].Checksum)
+
+	duplicates, total, err = repo.FindDuplicateChecksums(ctx, 3, 0, DefaultPageSize)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, duplicates)
+
+	// 없는 체크섬은 빈 목록, 빈 체크섬은 에러
+	files, err = repo.GetAllByChecksum(ctx, "nonexistent_checksum")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	_, err = repo.GetAllByChecksum(ctx, "")
+	assert.Error(t, err)
+}
+
+func TestFileRepository_GetByEncryptedPath(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	active := createTestFile("_path_active")
+	require.NoError(t, repo.Create(ctx, active))
+
+	deleted := createTestFile("_path_deleted")
+	require.NoError(t, repo.Create(ctx, deleted))
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	found, err := repo.GetByEncryptedPath(ctx, active.EncryptedPath)
+	require.NoError(t, err)
+	assert.Equal(t, active.ID, found.ID)
+	assert.False(t, found.DeletedAt.Valid)
+
+	// 소프트 삭제된 레코드도 암호화본을 소유하므로 조회됨
+	found, err = repo.GetByEncryptedPath(ctx, deleted.EncryptedPath)
+	require.NoError(t, err)
+	assert.Equal(t, deleted.ID, found.ID)
+	assert.True(t, found.DeletedAt.Valid)
+
+	found, err = repo.GetByEncryptedPath(ctx, "/encrypted/orphan.enc")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	assert.Nil(t, found)
+
+	_, err = repo.GetByEncryptedPath(ctx, "")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, model.ErrRecordNotFound)
+
+	_, err = repo.GetByEncryptedPath(ctx, strings.Repeat("a", model.MaxEncryptedPathLength+1))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, model.ErrRecordNotFound)
+}
+
+func TestFileRepository_SearchMetadata(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	seed := []string{
+		`{"case": "2024-001", "client": {"name": "홍길동", "tier": 2}}`,
+		`{"case": "2024-002", "client": {"name": "김철수"}}`,
+		`{"case": 42, "client": {"name": "홍길동", "tier": 1}}`,
+		"",
+	}
+	files := make([]*model.File, len(seed))
+	for i, metadata := range seed {
+		files[i] = createTestFile(fmt.Sprintf("_metadata_%d", i))
+		files[i].CustomMetadata = datatypes.JSON(metadata)
+		require.NoError(t, repo.Create(ctx, files[i]))
+	}
+	require.NoError(t, repo.Delete(ctx, files[1].ID))
+
+	ids := func(found []*model.File) []uint {
+		result := make([]uint, 0, len(found))
+		for _, file := range found {
+			result = append(result, file.ID)
+		}
+		slices.Sort(result)
+		return result
+	}
+
+	// 중첩 키로 검색하고 삭제된 파일은 제외
+	found, total, err := repo.SearchMetadata(ctx, "client.name", "홍길동", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []uint{files[0].ID, files[2].ID}, ids(found))
+
+	found, _, err = repo.SearchMetadata(ctx, "client.name", "김철수", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, found, "삭제된 파일")
+
+	// 숫자는 십진 문자열로 비교
+	found, _, err = repo.SearchMetadata(ctx, "client.tier", "2", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{files[0].ID}, ids(found))
+	found, _, err = repo.SearchMetadata(ctx, "case", "42", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{files[2].ID}, ids(found))
+
+	// 값을 비우면 키가 있는 파일
+	found, _, err = repo.SearchMetadata(ctx, "client.tier", "", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{files[0].ID, files[2].ID}, ids(found))
+
+	// 목록 필터와 함께 쓸 수 있음
+	found, _, err = repo.Find(ctx, FileFilter{MetadataKey: "client.name", MetadataValue: "홍길동", MinSize: 1}, Pagination{}, SortOption{})
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	for _, key := range []string{"", "client..name", `client."name"`} {
+		_, _, err = repo.SearchMetadata(ctx, key, "홍길동", 0, 10)
+		assert.ErrorIs(t, err, ErrInvalidFileFilter, key)
+	}
+	_, _, err = repo.Find(ctx, FileFilter{MetadataValue: "홍길동"}, Pagination{}, SortOption{})
+	assert.ErrorIs(t, err, ErrInvalidFileFilter, "키 없이 값만 지정")
+}
+
+func TestFileRepository_FindSimilar(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 상위 비트가 켜진 시그니처도 그대로 비교되는지 함께 확인
+	const base = uint64(0x8000000000000001)
+	target := createTestFile("_similar_target")
+	target.SetSimilaritySignature(base)
+	require.NoError(t, repo.Create(ctx, target))
+
+	far := createTestFile("_similar_far")
+	far.SetSimilaritySignature(^base)
+	require.NoError(t, repo.Create(ctx, far))
+
+	nearer := createTestFile("_similar_nearer")
+	nearer.SetSimilaritySignature(base ^ 1<<10)
+	require.NoError(t, repo.Create(ctx, nearer))
+
+	near := createTestFile("_similar_near")
+	near.SetSimilaritySignature(base ^ 0b111<<20)
+	require.NoError(t, repo.Create(ctx, near))
+
+	deleted := createTestFile("_similar_deleted")
+	deleted.SetSimilaritySignature(base)
+	require.NoError(t, repo.Create(ctx, deleted))
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	unsigned := createTestFile("_similar_unsigned")
+	require.NoError(t, repo.Create(ctx, unsigned))
+
+	// 거리순 정렬, 임계값 초과/삭제/시그니처 없는 파일 제외
+	similar, err := repo.FindSimilar(ctx, target.ID, 3)
+	require.NoError(t, err)
+	require.Len(t, similar, 2)
+	assert.Equal(t, nearer.ID, similar[0].File.ID)
+	assert.Equal(t, 1, similar[0].Distance)
+	assert.Equal(t, near.ID, similar[1].File.ID)
+	assert.Equal(t, 3, similar[1].Distance)
+
+	similar, err = repo.FindSimilar(ctx, target.ID, 0)
+	require.NoError(t, err)
+	assert.Empty(t, similar)
+
+	_, err = repo.FindSimilar(ctx, unsigned.ID, 3)
+	assert.ErrorIs(t, err, ErrNoSimilaritySignature)
+
+	_, err = repo.FindSimilar(ctx, TestNonExistentID, 3)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	_, err = repo.FindSimilar(ctx, target.ID, 65)
+	assert.ErrorIs(t, err, ErrInvalidSimilarityDistance)
+}
+
+func TestFileRepository_Exists_Success(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	file := createTestFile("_exists")
+
+	// 파일 생성 전 - 존재하지 않음
+	exists, err := repo.Exists(ctx, TestValidFileID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// 파일 생성
+	err = repo.Create(ctx, file)
+	require.NoError(t, err)
+
+	// 파일 생성 후 - 존재함
+	exists, err = repo.Exists(ctx, file.ID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// 파일 삭제
+	err = repo.Delete(ctx, file.ID)
+	require.NoError(t, err)
+
+	// 파일 삭제 후 - 존재하지 않음 (소프트 삭제)
+	exists, err = repo.Exists(ctx, file.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestFileRepository_Exists_ErrorCases(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 잘못된 ID
+	exists, err := repo.Exists(ctx, TestInvalidFileID)
+	require.Error(t, err)
+	assert.False(t, exists)
+	assert.Contains(t, err.Error(), "유효하지 않은 파일 ID입니다")
+}
+
+func TestFileRepository_Count_Success(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 초기 카운트 확인
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	// 파일들 생성
+	fileCount := 5
+	for i := 0; i < fileCount; i++ {
+		file := createTestFile(fmt.Sprintf("_count_%d", i))
+		createErr := repo.Create(ctx, file)
+		require.NoError(t, createErr)
+	}
+
+	// 생성 후 카운트 확인
+	count, err = repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(fileCount), count)
+
+	// 파일 하나 삭제
+	deleteErr := repo.Delete(ctx, TestValidFileID)
+	require.NoError(t, deleteErr)
+
+	// 삭제 후 카운트 확인 (소프트 삭제이므로 카운트 감소)
+	count, err = repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(fileCount-1), count)
+}
+
+func TestFileRepository_CountAndSumByStatus(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 파일이 없으면 빈 맵
+	counts, err := repo.CountByStatus(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+
+	seed := []struct {
+		status string
+		size   int64
+	}{
+		{model.FileStatusPending, 100},
+		{model.FileStatusEncrypted, 1000},
+		{model.FileStatusEncrypted, 2000},
+		{model.FileStatusEncrypted, 4000},
+		{model.FileStatusFailed, 10},
+		{model.FileStatusFailed, 20},
+	}
+	var files []*model.File
+	for i, row := range seed {
+		file := createTestFile(fmt.Sprintf("_status_%d", i))
+		file.Status = row.status
+		file.Size = row.size
+		require.NoError(t, repo.Create(ctx, file))
+		files = append(files, file)
+	}
+
+	// blob 참조는 개수에만 포함, 소프트 삭제된 파일은 모두 제외
+	ref := createTestFile("_status_ref")
+	ref.Status = model.FileStatusEncrypted
+	ref.Size = 4000
+	ref.BlobFileID = &files[3].ID
+	require.NoError(t, repo.Create(ctx, ref))
+	deleted := createTestFile("_status_deleted")
+	deleted.Status = model.FileStatusFailed
+	deleted.Size = 500
+	require.NoError(t, repo.Create(ctx, deleted))
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	counts, err = repo.CountByStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		model.FileStatusPending:   1,
+		model.FileStatusEncrypted: 4,
+		model.FileStatusFailed:    2,
+	}, counts, "corrupted처럼 파일이 없는 상태는 키가 없음")
+
+	sums, err := repo.SumSizeByStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		model.FileStatusPending:   100,
+		model.FileStatusEncrypted: 7000,
+		model.FileStatusFailed:    30,
+	}, sums)
+
+	// 전체 카운트와 일치
+	total, err := repo.Count(ctx)
+	require.NoError(t, err)
+	var sum int64
+	for _, count := range counts {
+		sum += count
+	}
+	assert.Equal(t, total, sum)
+}
+
+func TestFileRepository_TotalSize(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 빈 테이블은 에러 없이 0
+	total, err := repo.TotalSize(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	total, err = repo.TotalSizeByMimePrefix(ctx, "image/")
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	seed := map[string]int64{
+		"image/png":                 1000,
+		"image/jpeg":                2000,
+		"application/pdf":           300,
+		"application/vnd.ms-excel":  40,
+		"application/vnd_x.custom":  5,
+		"text/plain; charset=utf-8": 6,
+	}
+	var owner *model.File
+	i := 0
+	for mime, size := range seed {
+		file := createTestFile(fmt.Sprintf("_total_%d", i))
+		file.MimeType = mime
+		file.Size = size
+		require.NoError(t, repo.Create(ctx, file))
+		owner = file
+		i++
+	}
+
+	// blob 참조와 소프트 삭제된 파일은 합계에서 제외
+	ref := createTestFile("_total_ref")
+	ref.MimeType = "image/png"
+	ref.Size = 1000
+	ref.BlobFileID = &owner.ID
+	require.NoError(t, repo.Create(ctx, ref))
+	deleted := createTestFile("_total_deleted")
+	deleted.MimeType = "image/png"
+	deleted.Size = 9000
+	require.NoError(t, repo.Create(ctx, deleted))
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	total, err = repo.TotalSize(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3351), total)
+
+	testCases := []struct {
+		prefix string
+		want   int64
+	}{
+		{prefix: "image/", want: 3000},
+		{prefix: "Image/", want: 3000},
+		{prefix: "application/", want: 345},
+		{prefix: "application/vnd.", want: 40},
+		{prefix: "application/vnd_", want: 5},
+		{prefix: "video/", want: 0},
+		{prefix: "text/plain", want: 6},
+	}
+	for _, tc := range testCases {
+		got, err := repo.TotalSizeByMimePrefix(ctx, tc.prefix)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got, tc.prefix)
+	}
+
+	_, err = repo.TotalSizeByMimePrefix(ctx, " ")
+	assert.ErrorIs(t, err, ErrInvalidMimeFilter)
+}
+
+func TestFileRepository_Aggregate(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 빈 테이블도 모든 상태와 대분류를 0으로 채움
+	empty, err := repo.Aggregate(ctx, AggregateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, UsageTotals{}, empty.Total)
+	assert.Len(t, empty.ByStatus, len(model.FileStatuses()))
+	assert.Len(t, empty.ByMimeFamily, len(MimeFamilies)+1)
+
+	seed := []struct {
+		mime   string
+		status string
+		size   int64
+	}{
+		{"text/plain", model.FileStatusEncrypted, 100},
+		{"image/png", model.FileStatusEncrypted, 2000},
+		{"IMAGE/JPEG", model.FileStatusFailed, 3000},
+		{"application/pdf", model.FileStatusPending, 400},
+		{"font/woff2", model.FileStatusEncrypted, 50},
+	}
+	var files []*model.File
+	for i, row := range seed {
+		file := createTestFile(fmt.Sprintf("_aggregate_%d", i))
+		file.MimeType = row.mime
+		file.Status = row.status
+		file.Size = row.size
+		require.NoError(t, repo.Create(ctx, file))
+		files = append(files, file)
+	}
+
+	// blob 참조는 개수에만 포함
+	ref := createTestFile("_aggregate_ref")
+	ref.MimeType = "image/png"
+	ref.Status = model.FileStatusEncrypted
+	ref.Size = 2000
+	ref.BlobFileID = &files[1].ID
+	require.NoError(t, repo.Create(ctx, ref))
+
+	// 휴지통의 파일 두 개 (하나는 blob 참조)
+	trashed := createTestFile("_aggregate_trashed")
+	trashed.MimeType = "video/mp4"
+	trashed.Status = model.FileStatusEncrypted
+	trashed.Size = 70000
+	require.NoError(t, repo.Create(ctx, trashed))
+	require.NoError(t, repo.Delete(ctx, trashed.ID))
+	trashedRef := createTestFile("_aggregate_trashed_ref")
+	trashedRef.MimeType = "text/plain"
+	trashedRef.Status = model.FileStatusEncrypted
+	trashedRef.Size = 100
+	trashedRef.BlobFileID = &files[0].ID
+	require.NoError(t, repo.Create(ctx, trashedRef))
+	require.NoError(t, repo.Delete(ctx, trashedRef.ID))
+
+	live, err := repo.Aggregate(ctx, AggregateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, UsageTotals{Files: 6, Bytes: 5550}, live.Total)
+	assert.Equal(t, UsageTotals{}, live.Trash)
+	assert.Equal(t, map[string]UsageTotals{
+		model.FileStatusPending:   {Files: 1, Bytes: 400},
+		model.FileStatusEncrypted: {Files: 4, Bytes: 2150},
+		model.FileStatusFailed:    {Files: 1, Bytes: 3000},
+		model.FileStatusCorrupted: {},
+	}, live.ByStatus)
+	assert.Equal(t, map[string]UsageTotals{
+		"text":          {Files: 1, Bytes: 100},
+		"image":         {Files: 3, Bytes: 5000},
+		"audio":         {},
+		"video":         {},
+		"application":   {Files: 1, Bytes: 400},
+		MimeFamilyOther: {Files: 1, Bytes: 50},
+	}, live.ByMimeFamily, "대소문자 무시, 나머지는 other")
+
+	all, err := repo.Aggregate(ctx, AggregateOptions{IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, UsageTotals{Files: 8, Bytes: 75550}, all.Total)
+	assert.Equal(t, UsageTotals{Files: 2, Bytes: 70000}, all.Trash)
+	assert.Equal(t, UsageTotals{Files: 6, Bytes: 72150}, all.ByStatus[model.FileStatusEncrypted])
+	assert.Equal(t, UsageTotals{Files: 1, Bytes: 70000}, all.ByMimeFamily["video"])
+	assert.Equal(t, UsageTotals{Files: 2, Bytes: 100}, all.ByMimeFamily["text"])
+
+	// 상태별 합계와 대분류별 합계는 전체와 일치
+	var byFamily UsageTotals
+	for _, usage := range all.ByMimeFamily {
+		byFamily.Files += usage.Files
+		byFamily.Bytes += usage.Bytes
+	}
+	assert.Equal(t, all.Total, byFamily)
+}
+
+func TestFileRepository_AggregateEncryptedBytes(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	owned := createTestFile("_encrypted_owned")
+	owned.MimeType = "text/plain"
+	owned.VolumeID = "hot"
+	owned.Size, owned.EncryptedSize = 100, 140
+	require.NoError(t, repo.Create(ctx, owned))
+
+	// blob 참조는 디스크를 쓰지 않음
+	ref := createTestFile("_encrypted_ref")
+	ref.MimeType = "text/plain"
+	ref.Size = 100
+	ref.BlobFileID = &owned.ID
+	require.NoError(t, repo.Create(ctx, ref))
+
+	trashed := createTestFile("_encrypted_trashed")
+	trashed.MimeType = "image/png"
+	trashed.VolumeID = "hot"
+	trashed.Size, trashed.EncryptedSize = 200, 260
+	require.NoError(t, repo.Create(ctx, trashed))
+	require.NoError(t, repo.Delete(ctx, trashed.ID))
+
+	live, err := repo.Aggregate(ctx, AggregateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, UsageTotals{Files: 2, Bytes: 100, EncryptedBytes: 140}, live.Total)
+	assert.Equal(t, UsageTotals{Files: 2, Bytes: 100, EncryptedBytes: 140}, live.ByMimeFamily["text"])
+
+	all, err := repo.Aggregate(ctx, AggregateOptions{IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, UsageTotals{Files: 3, Bytes: 300, EncryptedBytes: 400}, all.Total)
+	assert.Equal(t, UsageTotals{Files: 1, Bytes: 200, EncryptedBytes: 260}, all.Trash)
+	assert.Equal(t, UsageTotals{Files: 1, Bytes: 200, EncryptedBytes: 260}, all.ByMimeFamily["image"])
+
+	// 볼륨 사용량도 원본과 암호화본 크기를 함께 집계 (휴지통 포함)
+	usage, err := repo.UsageByVolume(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []VolumeUsage{{VolumeID: "hot", Files: 2, Bytes: 300, EncryptedBytes: 400}}, usage)
+}
+
+func TestFileRepository_NormalizePagination(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db).(*fileRepository)
 
 	testCases := []struct {
 		name           string
@@ -700,7 +2756,37 @@ func TestFileRepository_NormalizePagination(t *testing.T) {
 	}
 }
 
+func TestFileRepository_CanceledContext(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+	for i := range 50 {
+		require.NoError(t, repo.Create(context.Background(), createTestFile(fmt.Sprintf("_canceled_%d", i))))
+	}
+
+	// 이미 취소된 컨텍스트로는 쿼리를 시작하지 않고 바로 컨텍스트 에러를 반환
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	started := time.Now()
+	_, _, err := repo.Search(ctx, FileSearchParams{Query: "test", Limit: MaxPageSize})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(started), time.Second)
+
+	_, err = repo.Count(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	file := createTestFile("_canceled_write")
+	assert.ErrorIs(t, repo.Create(ctx, file), context.Canceled)
+
+	count, err := repo.Count(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), count, "취소된 쓰기는 반영되지 않음")
+}
+
 func TestFileRepository_ConcurrentOperations(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -714,7 +2800,7 @@ func TestFileRepository_ConcurrentOperations(t *testing.T) {
 		go func(index int) {
 			file := createTestFile(fmt.Sprintf("_concurrent_%d", index))
 			file.EncryptedPath = fmt.Sprintf("/encrypted/concurrent_%d.enc", index) // 고유한 경로
-			err := repo.Create(file)
+			err := repo.Create(ctx, file)
 			results <- err
 		}(i)
 	}
@@ -726,12 +2812,13 @@ func TestFileRepository_ConcurrentOperations(t *testing.T) {
 	}
 
 	// 최종 카운트 확인
-	count, err := repo.Count()
+	count, err := repo.Count(ctx)
 	require.NoError(t, err)
 	assert.Equal(t, int64(goroutineCount), count)
 }
 
 func TestFileRepository_BulkOperations(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -741,12 +2828,12 @@ func TestFileRepository_BulkOperations(t *testing.T) {
 	for i := 0; i < TestBulkCreateCount; i++ {
 		file := createTestFile(fmt.Sprintf("_bulk_%d", i))
 		file.EncryptedPath = fmt.Sprintf("/encrypted/bulk_%d.enc", i)
-		err := repo.Create(file)
+		err := repo.Create(ctx, file)
 		require.NoError(t, err)
 	}
 
 	// 전체 조회 성능 테스트
-	files, total, err := repo.GetAll(0, TestBulkCreateCount)
+	files, total, err := repo.GetAll(ctx, 0, TestBulkCreateCount, SortOption{})
 	require.NoError(t, err)
 	assert.Len(t, files, TestBulkCreateCount)
 	assert.Equal(t, int64(TestBulkCreateCount), total)
@@ -758,7 +2845,7 @@ func TestFileRepository_BulkOperations(t *testing.T) {
 	totalRetrieved := 0
 	for page := 0; page < expectedPages; page++ {
 		offset := page * pageSize
-		pageFiles, _, err := repo.GetAll(offset, pageSize)
+		pageFiles, _, err := repo.GetAll(ctx, offset, pageSize, SortOption{})
 		require.NoError(t, err)
 		totalRetrieved += len(pageFiles)
 	}
@@ -766,21 +2853,85 @@ func TestFileRepository_BulkOperations(t *testing.T) {
 	assert.Equal(t, TestBulkCreateCount, totalRetrieved)
 }
 
+func TestFileRepository_CreateBatch(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	t.Run("전체 생성", func(t *testing.T) {
+		files := make([]*model.File, TestBulkCreateCount)
+		for i := range files {
+			files[i] = createTestFile(fmt.Sprintf("_batch_%d", i))
+		}
+
+		require.NoError(t, repo.CreateBatch(ctx, files))
+
+		for _, file := range files {
+			assert.NotZero(t, file.ID)
+		}
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(TestBulkCreateCount), count)
+
+		assert.NoError(t, repo.CreateBatch(ctx, nil))
+	})
+
+	t.Run("검증 실패 행 위치 보고", func(t *testing.T) {
+		files := []*model.File{
+			createTestFile("_batch_ok_0"),
+			createTestFile("_batch_ok_1"),
+			createTestFile("_batch_bad"),
+		}
+		files[2].OriginalName = ""
+
+		err := repo.CreateBatch(ctx, files)
+		var itemErr *BatchItemError
+		require.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, 2, itemErr.Index)
+		assert.ErrorIs(t, err, model.ErrEmptyOriginalName)
+
+		err = repo.CreateBatch(ctx, []*model.File{createTestFile("_batch_ok_2"), nil})
+		require.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, 1, itemErr.Index)
+	})
+
+	t.Run("DB 제약 위반 시 전부 롤백", func(t *testing.T) {
+		before, err := repo.Count(ctx)
+		require.NoError(t, err)
+
+		// 앞쪽 행은 배치 하나로 들어간 뒤 뒤쪽 배치에서 경로 중복
+		files := make([]*model.File, createBatchSize+1)
+		for i := range files {
+			files[i] = createTestFile(fmt.Sprintf("_batch_dup_%d", i))
+		}
+		files[createBatchSize].EncryptedPath = files[0].EncryptedPath
+
+		require.Error(t, repo.CreateBatch(ctx, files))
+
+		after, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+}
+
 func TestFileRepository_EdgeCases(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	repo := NewFileRepository(db)
 
 	t.Run("빈 데이터베이스에서 GetAll", func(t *testing.T) {
-		files, total, err := repo.GetAll(0, DefaultPageSize)
+		files, total, err := repo.GetAll(ctx, 0, DefaultPageSize, SortOption{})
 		require.NoError(t, err)
 		assert.Empty(t, files)
 		assert.Zero(t, total)
 	})
 
 	t.Run("빈 데이터베이스에서 GetByStatus", func(t *testing.T) {
-		files, total, err := repo.GetByStatus(model.FileStatusPending, 0, DefaultPageSize)
+		files, total, err := repo.GetByStatus(ctx, model.FileStatusPending, 0, DefaultPageSize, SortOption{})
 		require.NoError(t, err)
 		assert.Empty(t, files)
 		assert.Zero(t, total)
@@ -790,12 +2941,12 @@ func TestFileRepository_EdgeCases(t *testing.T) {
 		// 파일 몇 개 생성
 		for i := 0; i < 5; i++ {
 			file := createTestFile(fmt.Sprintf("_edge_%d", i))
-			err := repo.Create(file)
+			err := repo.Create(ctx, file)
 			require.NoError(t, err)
 		}
 
 		// 전체 파일 수보다 큰 offset으로 조회
-		files, total, err := repo.GetAll(100, DefaultPageSize)
+		files, total, err := repo.GetAll(ctx, 100, DefaultPageSize, SortOption{})
 		require.NoError(t, err)
 		assert.Empty(t, files)
 		assert.Equal(t, int64(5), total) // 전체 수는 여전히 5
@@ -803,7 +2954,7 @@ func TestFileRepository_EdgeCases(t *testing.T) {
 
 	t.Run("파일 상태 변경 추적", func(t *testing.T) {
 		file := createTestFile("_status_change")
-		err := repo.Create(file)
+		err := repo.Create(ctx, file)
 		require.NoError(t, err)
 
 		// 상태를 여러 번 변경
@@ -815,11 +2966,11 @@ func TestFileRepository_EdgeCases(t *testing.T) {
 
 		for _, status := range statuses {
 			file.Status = status
-			err := repo.Update(file)
+			err := repo.Update(ctx, file)
 			require.NoError(t, err)
 
 			// 업데이트된 상태 확인
-			updatedFile, err := repo.GetByID(file.ID)
+			updatedFile, err := repo.GetByID(ctx, file.ID)
 			require.NoError(t, err)
 			assert.Equal(t, status, updatedFile.Status)
 		}
@@ -827,6 +2978,7 @@ func TestFileRepository_EdgeCases(t *testing.T) {
 }
 
 func TestFileRepository_DataIntegrity(t *testing.T) {
+	ctx := context.Background()
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -839,18 +2991,18 @@ func TestFileRepository_DataIntegrity(t *testing.T) {
 		file1 := createTestFile("_integrity1")
 		file1.ChecksumMD5 = checksum
 		file1.EncryptedPath = "/encrypted/integrity1.enc"
-		err1 := repo.Create(file1)
+		err1 := repo.Create(ctx, file1)
 		require.NoError(t, err1)
 
 		// 같은 체크섬으로 두 번째 파일 생성
 		file2 := createTestFile("_integrity2")
 		file2.ChecksumMD5 = checksum
 		file2.EncryptedPath = "/encrypted/integrity2.enc"
-		err2 := repo.Create(file2)
+		err2 := repo.Create(ctx, file2)
 		require.NoError(t, err2) // 체크섬 중복은 허용
 
 		// 체크섬으로 조회 시 첫 번째 파일이 반환되는지 확인
-		foundFile, err3 := repo.GetByChecksumMD5(checksum)
+		foundFile, err3 := repo.GetByChecksumMD5(ctx, checksum)
 		require.NoError(t, err3)
 		require.NotNil(t, foundFile)
 		assert.Equal(t, file1.ID, foundFile.ID) // 첫 번째 생성된 파일
@@ -874,10 +3026,10 @@ func TestFileRepository_DataIntegrity(t *testing.T) {
 				file.Size = tc.size
 				file.EncryptedPath = fmt.Sprintf("/encrypted/size_%d.enc", tc.size)
 
-				createErr := repo.Create(file)
+				createErr := repo.Create(ctx, file)
 				require.NoError(t, createErr)
 
-				retrievedFile, getErr := repo.GetByID(file.ID)
+				retrievedFile, getErr := repo.GetByID(ctx, file.ID)
 				require.NoError(t, getErr)
 				assert.Equal(t, tc.size, retrievedFile.Size)
 			})
@@ -887,13 +3039,15 @@ func TestFileRepository_DataIntegrity(t *testing.T) {
 
 // 벤치마크 테스트
 func BenchmarkFileRepository_Create(b *testing.B) {
+	ctx := context.Background()
 	// 메모리 데이터베이스 사용
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
 	})
 	require.NoError(b, err)
 
-	migrationErr := model.Migrate(db)
+	_, migrationErr := model.MigrateUp(db)
 	require.NoError(b, migrationErr)
 
 	repo := NewFileRepository(db)
@@ -902,21 +3056,72 @@ func BenchmarkFileRepository_Create(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		file := createTestFile(fmt.Sprintf("_bench_%d", i))
 		file.EncryptedPath = fmt.Sprintf("/encrypted/bench_%d.enc", i)
-		createErr := repo.Create(file)
+		createErr := repo.Create(ctx, file)
 		if createErr != nil {
 			b.Fatal(createErr)
 		}
 	}
 }
 
+// BenchmarkFileRepository_CreateBatch 500건을 한 건씩 생성할 때와 한 번에 생성할 때를 비교합니다
+func BenchmarkFileRepository_CreateBatch(b *testing.B) {
+	ctx := context.Background()
+	const filesPerOp = 500
+
+	run := func(b *testing.B, create func(repo FileRepository, files []*model.File) error) {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(b.TempDir(), "bench.db")), &gorm.Config{
+			Logger:  logger.Default.LogMode(logger.Silent),
+			NowFunc: database.NowUTC,
+		})
+		require.NoError(b, err)
+		_, err = model.MigrateUp(db)
+		require.NoError(b, err)
+
+		repo := NewFileRepository(db)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			files := make([]*model.File, filesPerOp)
+			for j := range files {
+				files[j] = createTestFile(fmt.Sprintf("_bench_batch_%d_%d", i, j))
+			}
+			b.StartTimer()
+
+			if err := create(repo, files); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("single", func(b *testing.B) {
+		run(b, func(repo FileRepository, files []*model.File) error {
+			for _, file := range files {
+				if err := repo.Create(ctx, file); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		run(b, func(repo FileRepository, files []*model.File) error {
+			return repo.CreateBatch(ctx, files)
+		})
+	})
+}
+
 func BenchmarkFileRepository_GetByID(b *testing.B) {
+	ctx := context.Background()
 	// 메모리 데이터베이스 사용
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
 	})
 	require.NoError(b, err)
 
-	migrationErr := model.Migrate(db)
+	_, migrationErr := model.MigrateUp(db)
 	require.NoError(b, migrationErr)
 
 	repo := NewFileRepository(db)
@@ -926,28 +3131,77 @@ func BenchmarkFileRepository_GetByID(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		file := createTestFile(fmt.Sprintf("_bench_get_%d", i))
 		file.EncryptedPath = fmt.Sprintf("/encrypted/bench_get_%d.enc", i)
-		createErr := repo.Create(file)
+		createErr := repo.Create(ctx, file)
 		require.NoError(b, createErr)
 		fileIDs[i] = file.ID
 	}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, getErr := repo.GetByID(fileIDs[i])
+		_, getErr := repo.GetByID(ctx, fileIDs[i])
 		if getErr != nil {
 			b.Fatal(getErr)
 		}
 	}
 }
 
+// setupBenchmarkFile 상태 변경 벤치마크용 메모리 DB와 파일 한 건을 준비합니다
+func setupBenchmarkFile(b *testing.B) (FileRepository, *model.File) {
+	b.Helper()
+	ctx := context.Background()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
+	})
+	require.NoError(b, err)
+	_, err = model.MigrateUp(db)
+	require.NoError(b, err)
+
+	repo := NewFileRepository(db)
+	file := createTestFile("_bench_status")
+	require.NoError(b, repo.Create(ctx, file))
+	return repo, file
+}
+
+// benchmarkStatuses 벤치마크에서 번갈아 기록하는 상태
+var benchmarkStatuses = []string{model.FileStatusPending, model.FileStatusEncrypted}
+
+func BenchmarkFileRepository_Update(b *testing.B) {
+	ctx := context.Background()
+	repo, file := setupBenchmarkFile(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		file.Status = benchmarkStatuses[i%len(benchmarkStatuses)]
+		if err := repo.Update(ctx, file); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFileRepository_UpdateStatus(b *testing.B) {
+	ctx := context.Background()
+	repo, file := setupBenchmarkFile(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := repo.UpdateStatus(ctx, file.ID, benchmarkStatuses[i%len(benchmarkStatuses)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func BenchmarkFileRepository_GetAll(b *testing.B) {
+	ctx := context.Background()
 	// 메모리 데이터베이스 사용
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
 	})
 	require.NoError(b, err)
 
-	migrationErr := model.Migrate(db)
+	_, migrationErr := model.MigrateUp(db)
 	require.NoError(b, migrationErr)
 
 	repo := NewFileRepository(db)
@@ -956,15 +3210,400 @@ func BenchmarkFileRepository_GetAll(b *testing.B) {
 	for i := 0; i < TestBulkCreateCount; i++ {
 		file := createTestFile(fmt.Sprintf("_bench_getall_%d", i))
 		file.EncryptedPath = fmt.Sprintf("/encrypted/bench_getall_%d.enc", i)
-		createErr := repo.Create(file)
+		createErr := repo.Create(ctx, file)
 		require.NoError(b, createErr)
 	}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _, getAllErr := repo.GetAll(0, DefaultPageSize)
+		_, _, getAllErr := repo.GetAll(ctx, 0, DefaultPageSize, SortOption{})
 		if getAllErr != nil {
 			b.Fatal(getAllErr)
 		}
 	}
 }
+
+func TestFileRepository_Search(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	fixtures := []struct {
+		name   string
+		mime   string
+		status string
+	}{
+		{"report.pdf", "application/pdf", model.FileStatusEncrypted},
+		{"annual_report_2024.pdf", "application/pdf", model.FileStatusPending},
+		{"Report", "text/plain", model.FileStatusEncrypted},
+		{"photo.png", "image/png", model.FileStatusEncrypted},
+		{"100%_done.txt", "text/plain", model.FileStatusEncrypted},
+	}
+	ids := make([]uint, len(fixtures))
+	for i, fixture := range fixtures {
+		file := createTestFile(fmt.Sprintf("_search_%d", i))
+		file.OriginalName = fixture.name
+		file.MimeType = fixture.mime
+		file.Status = fixture.status
+		require.NoError(t, repo.Create(ctx, file))
+		ids[i] = file.ID
+	}
+	require.NoError(t, repo.AddTags(ctx, ids[0], []string{"tax-2024", "work"}))
+	require.NoError(t, repo.AddTags(ctx, ids[1], []string{"tax-2024"}))
+	require.NoError(t, db.Model(&model.File{}).Where("id = ?", ids[3]).
+		UpdateColumn("description", "Scanned page of the REPORT").Error)
+
+	testCases := []struct {
+		name      string
+		params    FileSearchParams
+		wantNames []string
+	}{
+		{
+			name:      "관련도순 (정확히 일치 > 접두 일치 > 부분 일치)",
+			params:    FileSearchParams{Query: "report", SortBy: SearchSortRelevance},
+			wantNames: []string{"Report", "report.pdf", "annual_report_2024.pdf"},
+		},
+		{
+			name:      "이름 + 상태",
+			params:    FileSearchParams{Query: "report", Status: model.FileStatusPending},
+			wantNames: []string{"annual_report_2024.pdf"},
+		},
+		{
+			name:      "MIME 필터만",
+			params:    FileSearchParams{MimeType: "image/png"},
+			wantNames: []string{"photo.png"},
+		},
+		{
+			name:      "LIKE 특수문자 이스케이프",
+			params:    FileSearchParams{Query: "%_"},
+			wantNames: []string{"100%_done.txt"},
+		},
+		{
+			name:      "설명 포함 (이름 일치 뒤)",
+			params:    FileSearchParams{Query: "report", MatchDescription: true, SortBy: SearchSortRelevance},
+			wantNames: []string{"Report", "report.pdf", "annual_report_2024.pdf", "photo.png"},
+		},
+		{
+			name:      "태그 (정규화해 비교)",
+			params:    FileSearchParams{Tags: []string{" TAX-2024 "}, SortBy: SearchSortLatest},
+			wantNames: []string{"annual_report_2024.pdf", "report.pdf"},
+		},
+		{
+			name:      "여러 태그는 모두 붙은 파일만",
+			params:    FileSearchParams{Query: "report", Tags: []string{"tax-2024", "work"}},
+			wantNames: []string{"report.pdf"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files, total, err := repo.Search(ctx, tc.params)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tc.wantNames)), total)
+
+			names := make([]string, 0, len(files))
+			for _, file := range files {
+				names = append(names, file.OriginalName)
+			}
+			assert.Equal(t, tc.wantNames, names)
+		})
+	}
+
+	// 기간 필터
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	_, total, err := repo.Search(ctx, FileSearchParams{From: &past, To: &future})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(fixtures)), total)
+
+	_, total, err = repo.Search(ctx, FileSearchParams{From: &future})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	// 잘못된 조건
+	_, _, err = repo.Search(ctx, FileSearchParams{Status: "unknown"})
+	assert.Error(t, err)
+	_, _, err = repo.Search(ctx, FileSearchParams{From: &future, To: &past})
+	assert.Error(t, err)
+	_, _, err = repo.Search(ctx, FileSearchParams{Tags: []string{strings.Repeat("t", model.MaxTagNameLength+1)}})
+	assert.ErrorIs(t, err, ErrInvalidFileFilter)
+	assert.ErrorIs(t, err, model.ErrTagNameTooLong)
+}
+
+func TestFileRepository_SearchDateBoundaries(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 2024-03-10 자정(UTC) 앞뒤와 KST 자정(UTC 15:00) 앞뒤
+	midnight := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	kstMidnight := time.Date(2024, 3, 11, 0, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+	fixtures := map[string]time.Time{
+		"before_midnight":     midnight.Add(-time.Microsecond),
+		"at_midnight":         midnight,
+		"before_kst_midnight": kstMidnight.Add(-time.Second).UTC(),
+		"at_kst_midnight":     kstMidnight.UTC(),
+		"next_utc_day":        midnight.AddDate(0, 0, 1),
+	}
+	for name, at := range fixtures {
+		file := createTestFile("_" + name)
+		file.OriginalName = name
+		file.CreatedAt = at
+		require.NoError(t, repo.Create(ctx, file))
+	}
+
+	parser := httputil.TimeRangeParser{MaxRange: httputil.DefaultMaxTimeRange}
+	testCases := []struct {
+		name      string
+		from      string
+		to        string
+		wantNames []string
+	}{
+		{name: "날짜만 지정하면 UTC 00:00~24:00", from: "2024-03-10", to: "2024-03-10",
+			wantNames: []string{"at_midnight", "at_kst_midnight", "before_kst_midnight"}},
+		{name: "상한 자정은 미포함", from: "2024-03-09T00:00:00Z", to: "2024-03-10T00:00:00Z",
+			wantNames: []string{"before_midnight"}},
+		{name: "오프셋이 있는 하한은 같은 순간으로 비교", from: "2024-03-11T00:00:00+09:00", to: "2024-03-11",
+			wantNames: []string{"at_kst_midnight", "next_utc_day"}},
+		{name: "오프셋이 있는 상한", from: "2024-03-10", to: "2024-03-11T00:00:00+09:00",
+			wantNames: []string{"at_midnight", "before_kst_midnight"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := parser.ParseValues(tc.from, tc.to)
+			require.NoError(t, err)
+
+			files, total, err := repo.Search(ctx, FileSearchParams{From: &from, To: &to, SortBy: "latest"})
+			require.NoError(t, err)
+			names := make([]string, 0, len(files))
+			for _, file := range files {
+				names = append(names, file.OriginalName)
+			}
+			assert.ElementsMatch(t, tc.wantNames, names)
+			assert.Equal(t, int64(len(tc.wantNames)), total)
+
+			// 같은 기간을 GetByDateRange로 조회해도 결과가 같음
+			_, total, err = repo.GetByDateRange(ctx, from, to, "", 0, DefaultPageSize)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tc.wantNames)), total)
+		})
+	}
+
+	// 파서를 거치지 않고 오프셋이 있는 시각을 넘겨도 UTC로 비교
+	_, total, err := repo.Search(ctx, FileSearchParams{From: &kstMidnight})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total, "at_kst_midnight, next_utc_day")
+}
+
+func TestFileRepository_SearchByName(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	for i, name := range []string{"Quarterly_Report.pdf", "report.txt", "a%b.txt", "axb.txt"} {
+		file := createTestFile(fmt.Sprintf("_name_%d", i))
+		file.OriginalName = name
+		require.NoError(t, repo.Create(ctx, file))
+		if i == 0 {
+			require.NoError(t, db.Create(createTestEncryptionMetadata(file.ID)).Error)
+		}
+	}
+
+	// 대소문자 무시 + 접두 일치 우선
+	files, total, err := repo.SearchByName(ctx, "  REPORT ", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, files, 2)
+	assert.Equal(t, "report.txt", files[0].OriginalName)
+	assert.Equal(t, "Quarterly_Report.pdf", files[1].OriginalName)
+	assert.NotNil(t, files[1].EncryptionMetadata)
+
+	// 페이지네이션
+	files, total, err = repo.SearchByName(ctx, "report", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, files, 1)
+	assert.Equal(t, "Quarterly_Report.pdf", files[0].OriginalName)
+
+	// %, _는 문자 그대로 비교
+	files, _, err = repo.SearchByName(ctx, "a%b", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "a%b.txt", files[0].OriginalName)
+
+	files, _, err = repo.SearchByName(ctx, "y_R", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "Quarterly_Report.pdf", files[0].OriginalName)
+
+	for _, query := range []string{"", "   ", strings.Repeat("a", model.MaxOriginalNameLength+1)} {
+		_, _, err = repo.SearchByName(ctx, query, 0, 0)
+		assert.ErrorIs(t, err, ErrInvalidNameQuery)
+	}
+
+	// NFD(macOS)로 올린 이름과 NFC(Windows)로 올린 이름을 어느 쪽 검색어로도 찾음
+	mac := createTestFile("_name_mac")
+	mac.OriginalName = norm.NFD.String("급여명세서_3월.pdf")
+	windows := createTestFile("_name_windows")
+	windows.OriginalName = "급여명세서_4월.pdf"
+	require.NoError(t, repo.CreateBatch(ctx, []*model.File{mac, windows}))
+
+	for _, query := range []string{"급여명세서", norm.NFD.String("급여명세서")} {
+		files, total, err = repo.SearchByName(ctx, query, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, files, 2)
+		assert.Equal(t, "급여명세서_3월.pdf", files[1].OriginalName)
+
+		_, total, err = repo.Find(ctx, FileFilter{NameContains: query}, Pagination{}, SortOption{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	}
+}
+
+func TestFileRepository_Search_Performance(t *testing.T) {
+	ctx := context.Background()
+	if testing.Short() {
+		t.Skip("성능 테스트는 -short 모드에서 건너뜁니다")
+	}
+
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 1만 건 생성
+	const recordCount = 10000
+	files := make([]*model.File, 0, recordCount)
+	for i := 0; i < recordCount; i++ {
+		file := createTestFile(fmt.Sprintf("_perf_%d", i))
+		file.OriginalName = fmt.Sprintf("document_%05d.txt", i)
+		files = append(files, file)
+	}
+	require.NoError(t, db.CreateInBatches(files, 500).Error)
+
+	start := time.Now()
+	result, total, err := repo.Search(ctx, FileSearchParams{
+		Query:  "document_09",
+		Status: model.FileStatusPending,
+		SortBy: SearchSortRelevance,
+		Limit:  TestPageSize,
+	})
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), total)
+	assert.Len(t, result, TestPageSize)
+	assert.Less(t, elapsed, time.Second, "1만 건 검색이 1초 이내여야 합니다")
+}
+
+func TestFileRepository_Folders(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFileRepository(db)
+
+	// 경로의 폴더를 차례로 만들고, 이미 있는 폴더는 다시 만들지 않음
+	tax, err := repo.EnsureFolderPath(ctx, nil, "docs/2024/tax")
+	require.NoError(t, err)
+	assert.Equal(t, "tax", tax.Name)
+	again, err := repo.EnsureFolderPath(ctx, nil, `docs\2024\tax`)
+	require.NoError(t, err)
+	assert.Equal(t, tax.ID, again.ID)
+	korean, err := repo.EnsureFolderPath(ctx, &tax.ID, norm.NFD.String("영수증"))
+	require.NoError(t, err)
+	again, err = repo.EnsureFolderPath(ctx, &tax.ID, "영수증")
+	require.NoError(t, err)
+	assert.Equal(t, korean.ID, again.ID, "NFD와 NFC 경로는 같은 폴더")
+
+	docs, err := repo.EnsureFolderPath(ctx, nil, "docs")
+	require.NoError(t, err)
+	photos := &model.Folder{Name: "photos", ParentID: &docs.ID}
+	require.NoError(t, repo.CreateFolder(ctx, photos))
+	assert.ErrorIs(t, repo.CreateFolder(ctx, &model.Folder{Name: "photos", ParentID: &docs.ID}), ErrDuplicate)
+
+	// 경로가 비어 있으면 상위 폴더를, 잘못된 경로면 아무것도 만들지 않음
+	same, err := repo.EnsureFolderPath(ctx, &docs.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, docs.ID, same.ID)
+	_, err = repo.EnsureFolderPath(ctx, &docs.ID, "new/../etc")
+	assert.ErrorIs(t, err, model.ErrInvalidFolderName)
+	_, err = repo.EnsureFolderPath(ctx, &tax.ID, strings.Repeat("d/", model.MaxFolderDepth-2))
+	assert.ErrorIs(t, err, model.ErrFolderTooDeep)
+	missing := uint(999)
+	_, err = repo.EnsureFolderPath(ctx, &missing, "a")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	var folderCount int64
+	require.NoError(t, db.Model(&model.Folder{}).Count(&folderCount).Error)
+	assert.Equal(t, int64(5), folderCount, "docs, 2024, tax, 영수증, photos")
+
+	// 트리는 이름순으로 하위 폴더를 채움
+	tree, err := repo.GetFolderTree(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, tree, 1)
+	assert.Equal(t, "docs", tree[0].Name)
+	require.Len(t, tree[0].Children, 2)
+	assert.Equal(t, "2024", tree[0].Children[0].Name)
+	assert.Equal(t, "photos", tree[0].Children[1].Name)
+	require.Len(t, tree[0].Children[0].Children, 1)
+	assert.Equal(t, tax.ID, tree[0].Children[0].Children[0].ID)
+
+	tree, err = repo.GetFolderTree(ctx, tree[0].Children[0].ID)
+	require.NoError(t, err)
+	require.Len(t, tree, 1)
+	assert.Equal(t, "2024", tree[0].Name)
+	assert.Len(t, tree[0].Children, 1)
+	_, err = repo.GetFolderTree(ctx, 999)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	// 폴더의 파일은 바로 아래만, recursive면 하위 폴더까지
+	report := createTestFile("_report")
+	report.FolderID = &tax.ID
+	cover := createTestFile("_cover")
+	cover.FolderID = &docs.ID
+	loose := createTestFile("_loose")
+	require.NoError(t, repo.CreateBatch(ctx, []*model.File{report, cover, loose}))
+
+	files, total, err := repo.GetFilesInFolder(ctx, docs.ID, false, Pagination{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, cover.ID, files[0].ID)
+
+	files, total, err = repo.GetFilesInFolder(ctx, docs.ID, true, Pagination{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, files, 1)
+
+	_, total, err = repo.GetFilesInFolder(ctx, photos.ID, true, Pagination{})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	_, _, err = repo.GetFilesInFolder(ctx, 999, false, Pagination{})
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, _, err = repo.GetFilesInFolder(ctx, 0, false, Pagination{})
+	assert.ErrorIs(t, err, ErrInvalidID)
+
+	// 하위 폴더 아래로는 옮길 수 없고, 다른 곳으로 옮기면 파일도 따라감
+	assert.ErrorIs(t, repo.MoveFolder(ctx, docs.ID, &tax.ID), model.ErrFolderCycle)
+	require.NoError(t, repo.MoveFolder(ctx, tax.ID, &photos.ID))
+	files, total, err = repo.GetFilesInFolder(ctx, photos.ID, true, Pagination{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, report.ID, files[0].ID)
+	require.NoError(t, repo.MoveFolder(ctx, tax.ID, nil))
+	assert.ErrorIs(t, repo.MoveFolder(ctx, 999, nil), model.ErrRecordNotFound)
+
+	tree, err = repo.GetFolderTree(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "docs", tree[0].Name)
+	assert.Equal(t, "tax", tree[1].Name)
+}
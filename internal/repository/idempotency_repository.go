@@ -0,0 +1,129 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements repository pattern for idempotency key records.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"DataLocker/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IdempotencyRepository Idempotency-Key 기록 저장소 인터페이스
+type IdempotencyRepository interface {
+	// Create 처리 중인 기록을 생성합니다 (같은 키와 행위자의 기록이 있으면 ErrDuplicate)
+	Create(ctx context.Context, record *model.IdempotencyRecord) error
+
+	// Get 키와 행위자로 기록을 조회합니다 (만료 여부는 호출자가 판단)
+	Get(ctx context.Context, key, owner string) (*model.IdempotencyRecord, error)
+
+	// Complete 처리 중인 기록에 응답을 저장합니다
+	Complete(ctx context.Context, id uint, statusCode int, contentType string, body []byte) error
+
+	// Delete 기록을 삭제합니다 (없으면 무시)
+	Delete(ctx context.Context, id uint) error
+
+	// DeleteExpired now 이전에 만료된 기록을 삭제하고 삭제한 수를 반환합니다
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// idempotencyRepository GORM 기반 Idempotency-Key 기록 저장소 구현체
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository 새로운 Idempotency-Key 기록 저장소를 생성합니다
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	if db == nil {
+		panic("데이터베이스 연결이 필요합니다")
+	}
+
+	return &idempotencyRepository{
+		db: db,
+	}
+}
+
+// Create 처리 중인 기록을 생성합니다
+func (r *idempotencyRepository) Create(ctx context.Context, record *model.IdempotencyRecord) error {
+	if record == nil || record.Key == "" || record.Owner == "" {
+		return fmt.Errorf("Idempotency-Key 기록 데이터가 없습니다")
+	}
+
+	if err := r.db.WithContext(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("Idempotency-Key 기록 생성 실패: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// Get 키와 행위자로 기록을 조회합니다
+func (r *idempotencyRepository) Get(ctx context.Context, key, owner string) (*model.IdempotencyRecord, error) {
+	if key == "" || owner == "" {
+		return nil, invalidID("Idempotency-Key")
+	}
+
+	var record model.IdempotencyRecord
+	err := r.db.WithContext(ctx).
+		Where("idempotency_key = ? AND owner = ?", key, owner).
+		First(&record).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("Idempotency-Key 기록을 찾을 수 없습니다: %s: %w", key, model.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("Idempotency-Key 기록 조회 실패: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Complete 처리 중인 기록에 응답을 저장합니다
+//
+// 이미 응답이 저장되었거나 정리된 기록이면 model.ErrRecordNotFound를 반환합니다.
+func (r *idempotencyRepository) Complete(ctx context.Context, id uint, statusCode int, contentType string, body []byte) error {
+	if id == 0 {
+		return invalidID("Idempotency-Key 기록")
+	}
+
+	result := r.db.WithContext(ctx).Model(&model.IdempotencyRecord{}).
+		Where("id = ? AND status_code = 0", id).
+		Updates(map[string]interface{}{
+			"status_code":  statusCode,
+			"content_type": contentType,
+			"body":         body,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("Idempotency-Key 응답 저장 실패: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("처리 중인 Idempotency-Key 기록을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// Delete 기록을 삭제합니다
+func (r *idempotencyRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return invalidID("Idempotency-Key 기록")
+	}
+
+	if err := r.db.WithContext(ctx).Delete(&model.IdempotencyRecord{}, id).Error; err != nil {
+		return fmt.Errorf("Idempotency-Key 기록 삭제 실패: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired now 이전에 만료된 기록을 삭제합니다
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&model.IdempotencyRecord{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("만료된 Idempotency-Key 기록 삭제 실패: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestIdempotencyRecord 처리 중인 Idempotency-Key 기록을 생성합니다
+func createTestIdempotencyRecord(t *testing.T, repo IdempotencyRepository, key, owner string, expiresAt time.Time) *model.IdempotencyRecord {
+	t.Helper()
+	record := &model.IdempotencyRecord{
+		Key:         key,
+		Owner:       owner,
+		RequestHash: "hash-" + key,
+		ExpiresAt:   expiresAt,
+	}
+	require.NoError(t, repo.Create(context.Background(), record))
+	return record
+}
+
+func TestIdempotencyRepository_CreateAndComplete(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewIdempotencyRepository(db)
+	record := createTestIdempotencyRecord(t, repo, "key-1", "anonymous", time.Now().Add(time.Hour))
+
+	// 같은 키와 행위자는 중복, 행위자가 다르면 별도 기록
+	err := repo.Create(ctx, &model.IdempotencyRecord{Key: "key-1", Owner: "anonymous", ExpiresAt: time.Now()})
+	assert.ErrorIs(t, err, ErrDuplicate)
+	createTestIdempotencyRecord(t, repo, "key-1", "admin", time.Now().Add(time.Hour))
+
+	got, err := repo.Get(ctx, "key-1", "anonymous")
+	require.NoError(t, err)
+	assert.False(t, got.IsCompleted())
+
+	require.NoError(t, repo.Complete(ctx, record.ID, 201, "application/json", []byte(`{"ok":true}`)))
+	got, err = repo.Get(ctx, "key-1", "anonymous")
+	require.NoError(t, err)
+	assert.True(t, got.IsCompleted())
+	assert.Equal(t, 201, got.StatusCode)
+	assert.Equal(t, "application/json", got.ContentType)
+	assert.Equal(t, []byte(`{"ok":true}`), got.Body)
+
+	// 이미 응답을 저장한 기록은 덮어쓰지 않음
+	err = repo.Complete(ctx, record.ID, 500, "text/plain", nil)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	require.NoError(t, repo.Delete(ctx, record.ID))
+	_, err = repo.Get(ctx, "key-1", "anonymous")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	_, err = repo.Get(ctx, "", "anonymous")
+	assert.ErrorIs(t, err, ErrInvalidID)
+	assert.ErrorIs(t, repo.Complete(ctx, 0, 200, "", nil), ErrInvalidID)
+	assert.Error(t, repo.Create(ctx, nil))
+
+	assert.Panics(t, func() { NewIdempotencyRepository(nil) })
+}
+
+func TestIdempotencyRepository_DeleteExpired(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewIdempotencyRepository(db)
+	now := time.Now()
+	createTestIdempotencyRecord(t, repo, "expired", "anonymous", now.Add(-time.Minute))
+	createTestIdempotencyRecord(t, repo, "alive", "anonymous", now.Add(time.Hour))
+
+	deleted, err := repo.DeleteExpired(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = repo.Get(ctx, "expired", "anonymous")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, err = repo.Get(ctx, "alive", "anonymous")
+	assert.NoError(t, err)
+}
+
+func TestSerializedIdempotencyRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := NewWriteSerializer(0)
+	defer writer.Close()
+
+	repo := NewSerializedIdempotencyRepository(NewIdempotencyRepository(db), writer)
+	record := createTestIdempotencyRecord(t, repo, "serialized", "anonymous", time.Now().Add(-time.Second))
+	require.NoError(t, repo.Complete(ctx, record.ID, 200, "application/json", nil))
+
+	deleted, err := repo.DeleteExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.Equal(t, int64(3), writer.Stats().Completed)
+
+	assert.Panics(t, func() { NewSerializedIdempotencyRepository(nil, writer) })
+	assert.Panics(t, func() { NewSerializedIdempotencyRepository(NewIdempotencyRepository(db), nil) })
+}
@@ -0,0 +1,222 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements optional query timing hooks for repository methods.
+package repository
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"DataLocker/internal/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Instrumenter 저장소 메서드 호출 시간을 관찰하는 훅
+//
+// name은 "FileRepository.GetByID"처럼 저장소와 메서드 이름이며, err는 메서드가
+// 반환한 에러 그대로입니다. 여러 고루틴에서 동시에 호출되므로 구현은 동시성에
+// 안전해야 하고, 쿼리 경로에서 실행되므로 오래 막히면 안 됩니다.
+type Instrumenter interface {
+	ObserveQuery(name string, duration time.Duration, err error)
+}
+
+// Option 저장소 생성 옵션
+type Option func(*options)
+
+// options 저장소 생성 옵션 값
+type options struct {
+	instrumenter Instrumenter
+}
+
+// WithInstrumenter 모든 공개 메서드의 실행 시간을 inst로 보고하도록 설정합니다
+//
+// nil이면 계측하지 않으며, 저장소를 감싸지 않으므로 추가 비용이 없습니다.
+func WithInstrumenter(inst Instrumenter) Option {
+	return func(o *options) {
+		o.instrumenter = inst
+	}
+}
+
+// applyOptions 옵션을 적용한 값을 반환합니다
+func applyOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// JoinInstrumenters nil이 아닌 훅을 모두 호출하는 훅을 반환합니다 (하나도 없으면 nil)
+func JoinInstrumenters(insts ...Instrumenter) Instrumenter {
+	var joined multiInstrumenter
+	for _, inst := range insts {
+		if inst != nil {
+			joined = append(joined, inst)
+		}
+	}
+
+	switch len(joined) {
+	case 0:
+		return nil
+	case 1:
+		return joined[0]
+	default:
+		return joined
+	}
+}
+
+// multiInstrumenter 여러 훅에 차례로 보고하는 훅
+type multiInstrumenter []Instrumenter
+
+// ObserveQuery 모든 훅에 호출 시간을 보고합니다
+func (m multiInstrumenter) ObserveQuery(name string, duration time.Duration, err error) {
+	for _, inst := range m {
+		inst.ObserveQuery(name, duration, err)
+	}
+}
+
+// logInstrumenter 임계값을 넘긴 호출을 경고 로그로 남기는 훅
+type logInstrumenter struct {
+	logger    *logrus.Logger
+	threshold time.Duration
+}
+
+// NewLogInstrumenter threshold 이상 걸린 저장소 호출을 경고 로그로 남기는 훅을 생성합니다
+func NewLogInstrumenter(logger *logrus.Logger, threshold time.Duration) Instrumenter {
+	if logger == nil {
+		panic("logger cannot be nil")
+	}
+
+	if threshold <= 0 {
+		panic("느린 쿼리 임계값은 0보다 커야 합니다")
+	}
+
+	return &logInstrumenter{logger: logger, threshold: threshold}
+}
+
+// ObserveQuery 임계값 이상이면 경고 로그를 남깁니다
+func (l *logInstrumenter) ObserveQuery(name string, duration time.Duration, err error) {
+	if duration < l.threshold {
+		return
+	}
+
+	entry := l.logger.WithFields(logrus.Fields{
+		"query":        name,
+		"duration_ms":  millis(duration),
+		"threshold_ms": millis(l.threshold),
+	})
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	entry.Warn("느린 저장소 호출이 감지되었습니다")
+}
+
+// queryBuckets 호출 시간 히스토그램의 상한 (누적 버킷, Prometheus histogram과 같은 의미)
+var queryBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// QueryStat 저장소 메서드 하나의 누적 호출 메트릭
+type QueryStat struct {
+	Name      string  `json:"name"`
+	Count     int64   `json:"count"`
+	Failed    int64   `json:"failed"` // 레코드 없음을 제외한 에러
+	AvgMillis float64 `json:"avg_ms"`
+	MaxMillis float64 `json:"max_ms"`
+
+	// Buckets "le" 상한(밀리초)별 누적 호출 수 (마지막 항목 +Inf는 Count와 같음)
+	Buckets []QueryBucket `json:"buckets"`
+}
+
+// QueryBucket 히스토그램 버킷 하나
+type QueryBucket struct {
+	LE    string `json:"le"`
+	Count int64  `json:"count"`
+}
+
+// queryCounter 메서드 하나의 집계 값
+type queryCounter struct {
+	count   int64
+	failed  int64
+	total   time.Duration
+	max     time.Duration
+	buckets []int64 // queryBuckets 순서, 마지막은 +Inf
+}
+
+// QueryStats 저장소 호출 시간을 메서드별 히스토그램으로 집계하는 훅
+//
+// /metrics의 db_queries 항목으로 노출되며, 누적 버킷은 Prometheus histogram과
+// 같은 방식으로 해석할 수 있습니다.
+type QueryStats struct {
+	mu       sync.Mutex
+	counters map[string]*queryCounter
+}
+
+// NewQueryStats 새로운 저장소 호출 집계기를 생성합니다
+func NewQueryStats() *QueryStats {
+	return &QueryStats{counters: make(map[string]*queryCounter)}
+}
+
+// ObserveQuery 호출 한 건을 집계합니다
+func (s *QueryStats) ObserveQuery(name string, duration time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, exists := s.counters[name]
+	if !exists {
+		counter = &queryCounter{buckets: make([]int64, len(queryBuckets)+1)}
+		s.counters[name] = counter
+	}
+
+	counter.count++
+	if err != nil && !errors.Is(err, model.ErrRecordNotFound) {
+		counter.failed++
+	}
+	counter.total += duration
+	counter.max = max(counter.max, duration)
+
+	index := sort.Search(len(queryBuckets), func(i int) bool { return duration <= queryBuckets[i] })
+	counter.buckets[index]++
+}
+
+// Stats 메서드 이름순으로 누적 메트릭을 반환합니다
+func (s *QueryStats) Stats() []QueryStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]QueryStat, 0, len(s.counters))
+	for name, counter := range s.counters {
+		stat := QueryStat{
+			Name:      name,
+			Count:     counter.count,
+			Failed:    counter.failed,
+			AvgMillis: millis(counter.total) / float64(counter.count),
+			MaxMillis: millis(counter.max),
+			Buckets:   make([]QueryBucket, 0, len(counter.buckets)),
+		}
+
+		var cumulative int64
+		for i, n := range counter.buckets {
+			cumulative += n
+			le := "+Inf"
+			if i < len(queryBuckets) {
+				le = strconv.FormatInt(queryBuckets[i].Milliseconds(), 10)
+			}
+			stat.Buckets = append(stat.Buckets, QueryBucket{LE: le, Count: cumulative})
+		}
+		stats = append(stats, stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
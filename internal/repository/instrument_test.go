@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryCall 기록된 저장소 호출 하나
+type queryCall struct {
+	name string
+	err  error
+}
+
+// recordingInstrumenter 보고된 호출을 순서대로 기록하는 훅
+type recordingInstrumenter struct {
+	mu    sync.Mutex
+	calls []queryCall
+}
+
+func (r *recordingInstrumenter) ObserveQuery(name string, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, queryCall{name: name, err: err})
+}
+
+func (r *recordingInstrumenter) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.calls))
+	for _, call := range r.calls {
+		names = append(names, call.name)
+	}
+	return names
+}
+
+func TestInstrumentedRepositories(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rec := &recordingInstrumenter{}
+
+	files := NewFileRepository(db, WithInstrumenter(rec))
+	file := createTestFile("instrumented")
+	require.NoError(t, files.Create(ctx, file))
+
+	// 결과와 에러를 그대로 전달하고, 보고하는 에러도 같은 값
+	found, err := files.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, file.ID, found.ID)
+
+	_, err = files.GetByID(ctx, file.ID+100)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	encryption := NewEncryptionRepository(db, WithInstrumenter(rec))
+	_, err = encryption.GetByFileID(ctx, file.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Equal(t, []string{
+		"FileRepository.Create",
+		"FileRepository.GetByID",
+		"FileRepository.GetByID",
+		"EncryptionRepository.GetByFileID",
+	}, rec.names())
+	assert.NoError(t, rec.calls[1].err)
+	assert.ErrorIs(t, rec.calls[2].err, ErrNotFound)
+	assert.ErrorIs(t, rec.calls[3].err, err)
+
+	// 트랜잭션 안의 저장소에도 같은 훅 전달
+	rec.calls = nil
+	tx := NewTxManager(db, WithInstrumenter(rec))
+	require.NoError(t, tx.WithinTransaction(ctx, func(txRepos Repositories) error {
+		_, err := txRepos.Files.Count(ctx)
+		return err
+	}))
+	assert.Equal(t, []string{"FileRepository.Count"}, rec.names())
+}
+
+func TestInstrumentedRepositories_NilInstrumenter(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 훅이 없으면 감싸지 않은 구현체를 그대로 반환
+	assert.IsType(t, &fileRepository{}, NewFileRepository(db))
+	assert.IsType(t, &fileRepository{}, NewFileRepository(db, WithInstrumenter(nil)))
+	assert.IsType(t, &encryptionRepository{}, NewEncryptionRepository(db, WithInstrumenter(JoinInstrumenters(nil, nil))))
+}
+
+// TestInstrumentedRepositories_AllMethods 인터페이스의 모든 메서드가 자기 이름으로 보고되는지 확인합니다
+func TestInstrumentedRepositories_AllMethods(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	fileRec, encRec := &recordingInstrumenter{}, &recordingInstrumenter{}
+	cases := []struct {
+		prefix string
+		repo   interface{}
+		iface  reflect.Type
+		rec    *recordingInstrumenter
+	}{
+		{"FileRepository", NewFileRepository(db, WithInstrumenter(fileRec)), reflect.TypeOf((*FileRepository)(nil)).Elem(), fileRec},
+		{"EncryptionRepository", NewEncryptionRepository(db, WithInstrumenter(encRec)), reflect.TypeOf((*EncryptionRepository)(nil)).Elem(), encRec},
+	}
+
+	for _, tc := range cases {
+		prefix, iface, rec := tc.prefix, tc.iface, tc.rec
+		value := reflect.ValueOf(tc.repo)
+
+		for i := range iface.NumMethod() {
+			method := iface.Method(i)
+
+			// ctx 외의 인자는 빈 값 (포인터는 빈 구조체)으로 호출
+			args := []reflect.Value{reflect.ValueOf(context.Background())}
+			for j := 1; j < method.Type.NumIn(); j++ {
+				argType := method.Type.In(j)
+				if argType.Kind() == reflect.Pointer {
+					args = append(args, reflect.New(argType.Elem()))
+				} else {
+					args = append(args, reflect.Zero(argType))
+				}
+			}
+
+			rec.calls = nil
+			value.MethodByName(method.Name).Call(args)
+			assert.Equal(t, []string{prefix + "." + method.Name}, rec.names())
+		}
+	}
+}
+
+func TestLogInstrumenter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	inst := NewLogInstrumenter(logger, 100*time.Millisecond)
+
+	inst.ObserveQuery("FileRepository.Find", 10*time.Millisecond, nil)
+	assert.Empty(t, buf.String())
+
+	inst.ObserveQuery("FileRepository.Find", 150*time.Millisecond, ErrNotFound)
+	assert.Contains(t, buf.String(), `"query":"FileRepository.Find"`)
+	assert.Contains(t, buf.String(), `"duration_ms":150`)
+	assert.Contains(t, buf.String(), `"level":"warning"`)
+	assert.Contains(t, buf.String(), model.ErrRecordNotFound.Error())
+
+	assert.Panics(t, func() { NewLogInstrumenter(logger, 0) })
+}
+
+func TestQueryStats(t *testing.T) {
+	stats := NewQueryStats()
+	stats.ObserveQuery("FileRepository.GetByID", 3*time.Millisecond, nil)
+	stats.ObserveQuery("FileRepository.GetByID", 7*time.Millisecond, ErrNotFound)
+	stats.ObserveQuery("FileRepository.GetByID", 10*time.Second, ErrInvalidID)
+	stats.ObserveQuery("EncryptionRepository.Count", time.Millisecond, nil)
+
+	result := stats.Stats()
+	require.Len(t, result, 2)
+	assert.Equal(t, "EncryptionRepository.Count", result[0].Name)
+
+	get := result[1]
+	assert.Equal(t, int64(3), get.Count)
+	assert.Equal(t, int64(1), get.Failed) // 레코드 없음은 실패로 세지 않음
+	assert.InDelta(t, 10000.0, get.MaxMillis, 0.001)
+
+	// 누적 버킷: 1ms 이하 0, 5ms 이하 1, 10ms 이하 2, ..., +Inf 3
+	counts := map[string]int64{}
+	for _, bucket := range get.Buckets {
+		counts[bucket.LE] = bucket.Count
+	}
+	assert.Equal(t, int64(0), counts["1"])
+	assert.Equal(t, int64(1), counts["5"])
+	assert.Equal(t, int64(2), counts["10"])
+	assert.Equal(t, int64(2), counts["5000"])
+	assert.Equal(t, int64(3), counts["+Inf"])
+	assert.Equal(t, "+Inf", get.Buckets[len(get.Buckets)-1].LE)
+}
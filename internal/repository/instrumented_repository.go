@@ -0,0 +1,635 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements the repository wrappers that report method timings to an Instrumenter.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"DataLocker/internal/model"
+)
+
+// instrumentedFileRepository 모든 메서드의 실행 시간을 보고하는 파일 저장소
+//
+// 인터페이스에 메서드가 추가되면 계측을 빠뜨리지 않도록 저장소를 임베드하지 않고
+// 모든 메서드를 직접 구현합니다.
+type instrumentedFileRepository struct {
+	next FileRepository
+	inst Instrumenter
+}
+
+// observe 메서드 호출 시간을 보고합니다
+func (r *instrumentedFileRepository) observe(method string, start time.Time, err error) {
+	r.inst.ObserveQuery("FileRepository."+method, time.Since(start), err)
+}
+
+// Create 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Create(ctx context.Context, file *model.File) error {
+	start := time.Now()
+	err := r.next.Create(ctx, file)
+	r.observe("Create", start, err)
+	return err
+}
+
+// CreateBatch 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) CreateBatch(ctx context.Context, files []*model.File) error {
+	start := time.Now()
+	err := r.next.CreateBatch(ctx, files)
+	r.observe("CreateBatch", start, err)
+	return err
+}
+
+// GetByID 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByID(ctx context.Context, id uint) (*model.File, error) {
+	start := time.Now()
+	file, err := r.next.GetByID(ctx, id)
+	r.observe("GetByID", start, err)
+	return file, err
+}
+
+// GetByIDWithDeleted 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*model.File, error) {
+	start := time.Now()
+	file, err := r.next.GetByIDWithDeleted(ctx, id)
+	r.observe("GetByIDWithDeleted", start, err)
+	return file, err
+}
+
+// GetByIDForOwner 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByIDForOwner(ctx context.Context, id, ownerID uint) (*model.File, error) {
+	start := time.Now()
+	file, err := r.next.GetByIDForOwner(ctx, id, ownerID)
+	r.observe("GetByIDForOwner", start, err)
+	return file, err
+}
+
+// GetByIDs 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByIDs(ctx context.Context, ids []uint) ([]*model.File, error) {
+	start := time.Now()
+	files, err := r.next.GetByIDs(ctx, ids)
+	r.observe("GetByIDs", start, err)
+	return files, err
+}
+
+// GetAll 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetAll(ctx context.Context, offset, limit int, sort SortOption) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetAll(ctx, offset, limit, sort)
+	r.observe("GetAll", start, err)
+	return files, total, err
+}
+
+// GetAllByOwner 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetAllByOwner(ctx context.Context, ownerID uint, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetAllByOwner(ctx, ownerID, offset, limit)
+	r.observe("GetAllByOwner", start, err)
+	return files, total, err
+}
+
+// Find 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Find(ctx context.Context, filter FileFilter, page Pagination, sort SortOption) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.Find(ctx, filter, page, sort)
+	r.observe("Find", start, err)
+	return files, total, err
+}
+
+// GetAfter 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetAfter(ctx context.Context, cursor Cursor, limit int) ([]*model.File, Cursor, error) {
+	start := time.Now()
+	files, next, err := r.next.GetAfter(ctx, cursor, limit)
+	r.observe("GetAfter", start, err)
+	return files, next, err
+}
+
+// Update 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Update(ctx context.Context, file *model.File) error {
+	start := time.Now()
+	err := r.next.Update(ctx, file)
+	r.observe("Update", start, err)
+	return err
+}
+
+// UpdateStatus 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
+	start := time.Now()
+	err := r.next.UpdateStatus(ctx, id, status)
+	r.observe("UpdateStatus", start, err)
+	return err
+}
+
+// Touch 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Touch(ctx context.Context, id uint) error {
+	start := time.Now()
+	err := r.next.Touch(ctx, id)
+	r.observe("Touch", start, err)
+	return err
+}
+
+// BulkUpdateStatus 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) BulkUpdateStatus(ctx context.Context, fromStatus, toStatus string, olderThan time.Time) (int64, error) {
+	start := time.Now()
+	affected, err := r.next.BulkUpdateStatus(ctx, fromStatus, toStatus, olderThan)
+	r.observe("BulkUpdateStatus", start, err)
+	return affected, err
+}
+
+// Delete 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Delete(ctx context.Context, id uint) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	r.observe("Delete", start, err)
+	return err
+}
+
+// DeleteBatch 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) DeleteBatch(ctx context.Context, ids []uint) (int64, error) {
+	start := time.Now()
+	affected, err := r.next.DeleteBatch(ctx, ids)
+	r.observe("DeleteBatch", start, err)
+	return affected, err
+}
+
+// Restore 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Restore(ctx context.Context, id uint) error {
+	start := time.Now()
+	err := r.next.Restore(ctx, id)
+	r.observe("Restore", start, err)
+	return err
+}
+
+// GetDeleted 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetDeleted(ctx context.Context, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetDeleted(ctx, offset, limit)
+	r.observe("GetDeleted", start, err)
+	return files, total, err
+}
+
+// GetByStatus 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByStatus(ctx context.Context, status string, offset, limit int, sort SortOption) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetByStatus(ctx, status, offset, limit, sort)
+	r.observe("GetByStatus", start, err)
+	return files, total, err
+}
+
+// GetStalePending 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetStalePending(ctx context.Context, olderThan time.Duration, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetStalePending(ctx, olderThan, offset, limit)
+	r.observe("GetStalePending", start, err)
+	return files, total, err
+}
+
+// GetNotAccessedSince 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetNotAccessedSince(ctx context.Context, cutoff time.Time, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetNotAccessedSince(ctx, cutoff, offset, limit)
+	r.observe("GetNotAccessedSince", start, err)
+	return files, total, err
+}
+
+// GetByMimeType 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByMimeType(ctx context.Context, mimeType string, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetByMimeType(ctx, mimeType, offset, limit)
+	r.observe("GetByMimeType", start, err)
+	return files, total, err
+}
+
+// GetByDateRange 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByDateRange(ctx context.Context, from, to time.Time, status string, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetByDateRange(ctx, from, to, status, offset, limit)
+	r.observe("GetByDateRange", start, err)
+	return files, total, err
+}
+
+// GetByChecksumMD5 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByChecksumMD5(ctx context.Context, checksum string) (*model.File, error) {
+	start := time.Now()
+	file, err := r.next.GetByChecksumMD5(ctx, checksum)
+	r.observe("GetByChecksumMD5", start, err)
+	return file, err
+}
+
+// GetAllByChecksum 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetAllByChecksum(ctx context.Context, checksum string) ([]*model.File, error) {
+	start := time.Now()
+	files, err := r.next.GetAllByChecksum(ctx, checksum)
+	r.observe("GetAllByChecksum", start, err)
+	return files, err
+}
+
+// FindDuplicateChecksums 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) FindDuplicateChecksums(ctx context.Context, minCount, offset, limit int) ([]DuplicateChecksum, int64, error) {
+	start := time.Now()
+	duplicates, total, err := r.next.FindDuplicateChecksums(ctx, minCount, offset, limit)
+	r.observe("FindDuplicateChecksums", start, err)
+	return duplicates, total, err
+}
+
+// GetByEncryptedPath 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByEncryptedPath(ctx context.Context, path string) (*model.File, error) {
+	start := time.Now()
+	file, err := r.next.GetByEncryptedPath(ctx, path)
+	r.observe("GetByEncryptedPath", start, err)
+	return file, err
+}
+
+// GetByExternalID 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByExternalID(ctx context.Context, externalID string) (*model.File, error) {
+	start := time.Now()
+	file, err := r.next.GetByExternalID(ctx, externalID)
+	r.observe("GetByExternalID", start, err)
+	return file, err
+}
+
+// GetByShortCode 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByShortCode(ctx context.Context, code string) (*model.File, error) {
+	start := time.Now()
+	file, err := r.next.GetByShortCode(ctx, code)
+	r.observe("GetByShortCode", start, err)
+	return file, err
+}
+
+// ReissueShortCode 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) ReissueShortCode(ctx context.Context, id uint) (string, error) {
+	start := time.Now()
+	code, err := r.next.ReissueShortCode(ctx, id)
+	r.observe("ReissueShortCode", start, err)
+	return code, err
+}
+
+// RecentActivity 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) RecentActivity(ctx context.Context, ownerID uint, page Pagination) ([]FileActivity, int64, error) {
+	start := time.Now()
+	activities, total, err := r.next.RecentActivity(ctx, ownerID, page)
+	r.observe("RecentActivity", start, err)
+	return activities, total, err
+}
+
+// AddTags 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) AddTags(ctx context.Context, fileID uint, tags []string) error {
+	start := time.Now()
+	err := r.next.AddTags(ctx, fileID, tags)
+	r.observe("AddTags", start, err)
+	return err
+}
+
+// RemoveTag 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) RemoveTag(ctx context.Context, fileID uint, tag string) error {
+	start := time.Now()
+	err := r.next.RemoveTag(ctx, fileID, tag)
+	r.observe("RemoveTag", start, err)
+	return err
+}
+
+// GetByTag 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByTag(ctx context.Context, tag string, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetByTag(ctx, tag, offset, limit)
+	r.observe("GetByTag", start, err)
+	return files, total, err
+}
+
+// SearchMetadata 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) SearchMetadata(ctx context.Context, key, value string, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.SearchMetadata(ctx, key, value, offset, limit)
+	r.observe("SearchMetadata", start, err)
+	return files, total, err
+}
+
+// CreateFolder 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) CreateFolder(ctx context.Context, folder *model.Folder) error {
+	start := time.Now()
+	err := r.next.CreateFolder(ctx, folder)
+	r.observe("CreateFolder", start, err)
+	return err
+}
+
+// EnsureFolderPath 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) EnsureFolderPath(ctx context.Context, parentID *uint, path string) (*model.Folder, error) {
+	start := time.Now()
+	folder, err := r.next.EnsureFolderPath(ctx, parentID, path)
+	r.observe("EnsureFolderPath", start, err)
+	return folder, err
+}
+
+// MoveFolder 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) MoveFolder(ctx context.Context, id uint, parentID *uint) error {
+	start := time.Now()
+	err := r.next.MoveFolder(ctx, id, parentID)
+	r.observe("MoveFolder", start, err)
+	return err
+}
+
+// GetFolderTree 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetFolderTree(ctx context.Context, rootID uint) ([]*model.Folder, error) {
+	start := time.Now()
+	folders, err := r.next.GetFolderTree(ctx, rootID)
+	r.observe("GetFolderTree", start, err)
+	return folders, err
+}
+
+// GetFilesInFolder 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetFilesInFolder(ctx context.Context, folderID uint, recursive bool, page Pagination) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetFilesInFolder(ctx, folderID, recursive, page)
+	r.observe("GetFilesInFolder", start, err)
+	return files, total, err
+}
+
+// FindSimilar 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) FindSimilar(ctx context.Context, fileID uint, maxDistance int) ([]SimilarFile, error) {
+	start := time.Now()
+	similar, err := r.next.FindSimilar(ctx, fileID, maxDistance)
+	r.observe("FindSimilar", start, err)
+	return similar, err
+}
+
+// Exists 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Exists(ctx context.Context, id uint) (bool, error) {
+	start := time.Now()
+	exists, err := r.next.Exists(ctx, id)
+	r.observe("Exists", start, err)
+	return exists, err
+}
+
+// Count 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Count(ctx context.Context) (int64, error) {
+	start := time.Now()
+	count, err := r.next.Count(ctx)
+	r.observe("Count", start, err)
+	return count, err
+}
+
+// CountByStatus 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	start := time.Now()
+	counts, err := r.next.CountByStatus(ctx)
+	r.observe("CountByStatus", start, err)
+	return counts, err
+}
+
+// SumSizeByStatus 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) SumSizeByStatus(ctx context.Context) (map[string]int64, error) {
+	start := time.Now()
+	sums, err := r.next.SumSizeByStatus(ctx)
+	r.observe("SumSizeByStatus", start, err)
+	return sums, err
+}
+
+// TotalSize 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) TotalSize(ctx context.Context) (int64, error) {
+	start := time.Now()
+	size, err := r.next.TotalSize(ctx)
+	r.observe("TotalSize", start, err)
+	return size, err
+}
+
+// TotalSizeByMimePrefix 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) TotalSizeByMimePrefix(ctx context.Context, prefix string) (int64, error) {
+	start := time.Now()
+	size, err := r.next.TotalSizeByMimePrefix(ctx, prefix)
+	r.observe("TotalSizeByMimePrefix", start, err)
+	return size, err
+}
+
+// Aggregate 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Aggregate(ctx context.Context, opts AggregateOptions) (*FileAggregates, error) {
+	start := time.Now()
+	aggregates, err := r.next.Aggregate(ctx, opts)
+	r.observe("Aggregate", start, err)
+	return aggregates, err
+}
+
+// Search 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Search(ctx context.Context, params FileSearchParams) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.Search(ctx, params)
+	r.observe("Search", start, err)
+	return files, total, err
+}
+
+// SearchByName 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) SearchByName(ctx context.Context, query string, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.SearchByName(ctx, query, offset, limit)
+	r.observe("SearchByName", start, err)
+	return files, total, err
+}
+
+// Purge 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) Purge(ctx context.Context, id uint) error {
+	start := time.Now()
+	err := r.next.Purge(ctx, id)
+	r.observe("Purge", start, err)
+	return err
+}
+
+// PurgeDeletedOlderThan 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	start := time.Now()
+	affected, err := r.next.PurgeDeletedOlderThan(ctx, cutoff)
+	r.observe("PurgeDeletedOlderThan", start, err)
+	return affected, err
+}
+
+// CountBlobReferences 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) CountBlobReferences(ctx context.Context, blobFileID uint) (int64, error) {
+	start := time.Now()
+	count, err := r.next.CountBlobReferences(ctx, blobFileID)
+	r.observe("CountBlobReferences", start, err)
+	return count, err
+}
+
+// UsageByVolume 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) UsageByVolume(ctx context.Context) ([]VolumeUsage, error) {
+	start := time.Now()
+	usage, err := r.next.UsageByVolume(ctx)
+	r.observe("UsageByVolume", start, err)
+	return usage, err
+}
+
+// GetByVolume 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetByVolume(ctx context.Context, volumeID string, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetByVolume(ctx, volumeID, offset, limit)
+	r.observe("GetByVolume", start, err)
+	return files, total, err
+}
+
+// ListMissingMetadata 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) ListMissingMetadata(ctx context.Context) ([]*model.File, error) {
+	start := time.Now()
+	files, err := r.next.ListMissingMetadata(ctx)
+	r.observe("ListMissingMetadata", start, err)
+	return files, err
+}
+
+// GetWithoutMetadata 실행 시간을 계측합니다
+func (r *instrumentedFileRepository) GetWithoutMetadata(ctx context.Context, offset, limit int) ([]*model.File, int64, error) {
+	start := time.Now()
+	files, total, err := r.next.GetWithoutMetadata(ctx, offset, limit)
+	r.observe("GetWithoutMetadata", start, err)
+	return files, total, err
+}
+
+// instrumentedEncryptionRepository 모든 메서드의 실행 시간을 보고하는 암호화 메타데이터 저장소
+type instrumentedEncryptionRepository struct {
+	next EncryptionRepository
+	inst Instrumenter
+}
+
+// observe 메서드 호출 시간을 보고합니다
+func (r *instrumentedEncryptionRepository) observe(method string, start time.Time, err error) {
+	r.inst.ObserveQuery("EncryptionRepository."+method, time.Since(start), err)
+}
+
+// Create 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) Create(ctx context.Context, metadata *model.EncryptionMetadata) error {
+	start := time.Now()
+	err := r.next.Create(ctx, metadata)
+	r.observe("Create", start, err)
+	return err
+}
+
+// CreateBatch 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) CreateBatch(ctx context.Context, metadata []*model.EncryptionMetadata) error {
+	start := time.Now()
+	err := r.next.CreateBatch(ctx, metadata)
+	r.observe("CreateBatch", start, err)
+	return err
+}
+
+// GetByID 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) GetByID(ctx context.Context, id uint) (*model.EncryptionMetadata, error) {
+	start := time.Now()
+	metadata, err := r.next.GetByID(ctx, id)
+	r.observe("GetByID", start, err)
+	return metadata, err
+}
+
+// GetByFileID 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) GetByFileID(ctx context.Context, fileID uint) (*model.EncryptionMetadata, error) {
+	start := time.Now()
+	metadata, err := r.next.GetByFileID(ctx, fileID)
+	r.observe("GetByFileID", start, err)
+	return metadata, err
+}
+
+// ListByFileID 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) ListByFileID(ctx context.Context, fileID uint) ([]*model.EncryptionMetadata, error) {
+	start := time.Now()
+	list, err := r.next.ListByFileID(ctx, fileID)
+	r.observe("ListByFileID", start, err)
+	return list, err
+}
+
+// Update 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) Update(ctx context.Context, metadata *model.EncryptionMetadata) error {
+	start := time.Now()
+	err := r.next.Update(ctx, metadata)
+	r.observe("Update", start, err)
+	return err
+}
+
+// UpsertByFileID 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) UpsertByFileID(ctx context.Context, metadata *model.EncryptionMetadata) error {
+	start := time.Now()
+	err := r.next.UpsertByFileID(ctx, metadata)
+	r.observe("UpsertByFileID", start, err)
+	return err
+}
+
+// DeleteByID 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) DeleteByID(ctx context.Context, id uint) error {
+	start := time.Now()
+	err := r.next.DeleteByID(ctx, id)
+	r.observe("DeleteByID", start, err)
+	return err
+}
+
+// DeleteByFileID 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) DeleteByFileID(ctx context.Context, fileID uint) error {
+	start := time.Now()
+	err := r.next.DeleteByFileID(ctx, fileID)
+	r.observe("DeleteByFileID", start, err)
+	return err
+}
+
+// GetByAlgorithm 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) GetByAlgorithm(ctx context.Context, algorithm string, offset, limit int) ([]*model.EncryptionMetadata, int64, error) {
+	start := time.Now()
+	list, total, err := r.next.GetByAlgorithm(ctx, algorithm, offset, limit)
+	r.observe("GetByAlgorithm", start, err)
+	return list, total, err
+}
+
+// Exists 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) Exists(ctx context.Context, id uint) (bool, error) {
+	start := time.Now()
+	exists, err := r.next.Exists(ctx, id)
+	r.observe("Exists", start, err)
+	return exists, err
+}
+
+// ExistsByFileID 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) ExistsByFileID(ctx context.Context, fileID uint) (bool, error) {
+	start := time.Now()
+	exists, err := r.next.ExistsByFileID(ctx, fileID)
+	r.observe("ExistsByFileID", start, err)
+	return exists, err
+}
+
+// Count 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) Count(ctx context.Context) (int64, error) {
+	start := time.Now()
+	count, err := r.next.Count(ctx)
+	r.observe("Count", start, err)
+	return count, err
+}
+
+// CountByAlgorithm 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) CountByAlgorithm(ctx context.Context, algorithm string) (int64, error) {
+	start := time.Now()
+	count, err := r.next.CountByAlgorithm(ctx, algorithm)
+	r.observe("CountByAlgorithm", start, err)
+	return count, err
+}
+
+// CountByPasswordFingerprint 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) CountByPasswordFingerprint(ctx context.Context, fingerprint string) (int64, error) {
+	start := time.Now()
+	count, err := r.next.CountByPasswordFingerprint(ctx, fingerprint)
+	r.observe("CountByPasswordFingerprint", start, err)
+	return count, err
+}
+
+// GetByIterationsBelow 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) GetByIterationsBelow(ctx context.Context, threshold, offset, limit int) ([]*model.EncryptionMetadata, int64, error) {
+	start := time.Now()
+	metadata, total, err := r.next.GetByIterationsBelow(ctx, threshold, offset, limit)
+	r.observe("GetByIterationsBelow", start, err)
+	return metadata, total, err
+}
+
+// CountByIterationsBelow 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) CountByIterationsBelow(ctx context.Context, threshold int) (int64, error) {
+	start := time.Now()
+	count, err := r.next.CountByIterationsBelow(ctx, threshold)
+	r.observe("CountByIterationsBelow", start, err)
+	return count, err
+}
+
+// GetOrphaned 실행 시간을 계측합니다
+func (r *instrumentedEncryptionRepository) GetOrphaned(ctx context.Context, offset, limit int) ([]*model.EncryptionMetadata, int64, error) {
+	start := time.Now()
+	list, total, err := r.next.GetOrphaned(ctx, offset, limit)
+	r.observe("GetOrphaned", start, err)
+	return list, total, err
+}
@@ -0,0 +1,160 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements repository pattern for key slot operations.
+package repository
+
+import (
+	"fmt"
+
+	"DataLocker/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// KeySlotRepository 키 슬롯 저장소 인터페이스
+type KeySlotRepository interface {
+	Create(slot *model.KeySlot) error
+	GetByID(id uint) (*model.KeySlot, error)
+	GetByFileID(fileID uint) ([]*model.KeySlot, error)
+	Update(slot *model.KeySlot) error
+	DeleteByID(id uint) error
+	DeleteByFileID(fileID uint) error
+	CountByFileID(fileID uint) (int64, error)
+}
+
+// keySlotRepository GORM 기반 키 슬롯 저장소 구현체
+type keySlotRepository struct {
+	db *gorm.DB
+}
+
+// NewKeySlotRepository 새로운 키 슬롯 저장소를 생성합니다
+func NewKeySlotRepository(db *gorm.DB) KeySlotRepository {
+	if db == nil {
+		panic("데이터베이스 연결이 필요합니다")
+	}
+
+	return &keySlotRepository{
+		db: db,
+	}
+}
+
+// Create 새로운 키 슬롯 레코드를 생성합니다
+func (r *keySlotRepository) Create(slot *model.KeySlot) error {
+	if slot == nil {
+		return fmt.Errorf("키 슬롯 데이터가 없습니다")
+	}
+
+	if err := r.db.Create(slot).Error; err != nil {
+		return fmt.Errorf("키 슬롯 생성 실패: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// GetByID ID로 키 슬롯을 조회합니다
+func (r *keySlotRepository) GetByID(id uint) (*model.KeySlot, error) {
+	if id == 0 {
+		return nil, invalidID("키 슬롯")
+	}
+
+	var slot model.KeySlot
+	err := r.db.First(&slot, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("키 슬롯을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("키 슬롯 조회 실패: %w", err)
+	}
+
+	return &slot, nil
+}
+
+// GetByFileID 파일의 모든 키 슬롯을 슬롯 번호 순으로 조회합니다
+func (r *keySlotRepository) GetByFileID(fileID uint) ([]*model.KeySlot, error) {
+	if fileID == 0 {
+		return nil, invalidID("파일")
+	}
+
+	var slots []*model.KeySlot
+	err := r.db.Where("file_id = ?", fileID).
+		Order("slot_index ASC").
+		Find(&slots).Error
+	if err != nil {
+		return nil, fmt.Errorf("키 슬롯 목록 조회 실패: %w", err)
+	}
+
+	return slots, nil
+}
+
+// Update 키 슬롯을 업데이트합니다
+func (r *keySlotRepository) Update(slot *model.KeySlot) error {
+	if slot == nil {
+		return fmt.Errorf("키 슬롯 데이터가 없습니다")
+	}
+
+	if slot.ID == 0 {
+		return invalidID("키 슬롯")
+	}
+
+	// 키 슬롯 존재 여부 확인
+	var count int64
+	if err := r.db.Model(&model.KeySlot{}).Where("id = ?", slot.ID).Count(&count).Error; err != nil {
+		return fmt.Errorf("키 슬롯 존재 확인 실패: %w", err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("업데이트할 키 슬롯을 찾을 수 없습니다: ID %d: %w", slot.ID, model.ErrRecordNotFound)
+	}
+
+	// 업데이트 실행
+	if err := r.db.Save(slot).Error; err != nil {
+		return fmt.Errorf("키 슬롯 업데이트 실패: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// DeleteByID ID로 키 슬롯을 삭제합니다 (하드 삭제)
+func (r *keySlotRepository) DeleteByID(id uint) error {
+	if id == 0 {
+		return invalidID("키 슬롯")
+	}
+
+	result := r.db.Unscoped().Delete(&model.KeySlot{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("키 슬롯 삭제 실패: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("삭제할 키 슬롯을 찾을 수 없습니다: ID %d: %w", id, model.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// DeleteByFileID 파일의 모든 키 슬롯을 삭제합니다 (하드 삭제)
+func (r *keySlotRepository) DeleteByFileID(fileID uint) error {
+	if fileID == 0 {
+		return invalidID("파일")
+	}
+
+	if err := r.db.Unscoped().Where("file_id = ?", fileID).Delete(&model.KeySlot{}).Error; err != nil {
+		return fmt.Errorf("키 슬롯 삭제 실패: %w", err)
+	}
+
+	return nil
+}
+
+// CountByFileID 파일의 키 슬롯 수를 반환합니다
+func (r *keySlotRepository) CountByFileID(fileID uint) (int64, error) {
+	if fileID == 0 {
+		return 0, invalidID("파일")
+	}
+
+	var count int64
+	err := r.db.Model(&model.KeySlot{}).Where("file_id = ?", fileID).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("키 슬롯 카운트 조회 실패: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/pkg/crypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 테스트용 상수
+const (
+	TestValidWrappedKeyHex = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef" +
+		"0123456789abcdef0123456789abcdef0123456789abcdef01234567"
+)
+
+// createTestKeySlot 테스트용 키 슬롯 생성
+func createTestKeySlot(fileID uint, index int) *model.KeySlot {
+	return &model.KeySlot{
+		FileID:        fileID,
+		SlotIndex:     index,
+		SaltHex:       TestValidSaltHex,
+		WrappedKeyHex: TestValidWrappedKeyHex,
+	}
+}
+
+func TestNewKeySlotRepository(t *testing.T) {
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewKeySlotRepository(db)
+	assert.NotNil(t, repo)
+
+	assert.Panics(t, func() {
+		NewKeySlotRepository(nil)
+	})
+}
+
+func TestKeySlotRepository_CRUD_Success(t *testing.T) {
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewKeySlotRepository(db)
+	file := createTestFileForEncryption(t, db, "_keyslot_crud")
+
+	// Create
+	for i := 0; i < 2; i++ {
+		err := repo.Create(createTestKeySlot(file.ID, i))
+		require.NoError(t, err)
+	}
+
+	// Read by FileID (슬롯 번호 순)
+	slots, err := repo.GetByFileID(file.ID)
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+	assert.Equal(t, 0, slots[0].SlotIndex)
+	assert.Equal(t, 1, slots[1].SlotIndex)
+	assert.Equal(t, model.DefaultIterations, slots[0].Iterations, "반복 횟수를 비우면 기본값")
+
+	// Read by ID
+	retrieved, err := repo.GetByID(slots[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, file.ID, retrieved.FileID)
+
+	// Update
+	retrieved.SaltHex = "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+	err = repo.Update(retrieved)
+	require.NoError(t, err)
+
+	updated, err := repo.GetByID(retrieved.ID)
+	require.NoError(t, err)
+	assert.Equal(t, retrieved.SaltHex, updated.SaltHex)
+
+	// Count
+	count, err := repo.CountByFileID(file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Delete by ID
+	err = repo.DeleteByID(slots[0].ID)
+	require.NoError(t, err)
+
+	_, err = repo.GetByID(slots[0].ID)
+	assert.Error(t, err)
+
+	// Delete by FileID
+	err = repo.DeleteByFileID(file.ID)
+	require.NoError(t, err)
+
+	count, err = repo.CountByFileID(file.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestKeySlotRepository_ErrorCases(t *testing.T) {
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewKeySlotRepository(db)
+	file := createTestFileForEncryption(t, db, "_keyslot_err")
+
+	testCases := []struct {
+		name    string
+		slot    *model.KeySlot
+		wantErr string
+	}{
+		{
+			name:    "nil 슬롯",
+			slot:    nil,
+			wantErr: "키 슬롯 데이터가 없습니다",
+		},
+		{
+			name:    "잘못된 슬롯 번호",
+			slot:    createTestKeySlot(file.ID, model.MaxKeySlotsPerFile),
+			wantErr: model.ErrInvalidSlotIndex.Error(),
+		},
+		{
+			name: "잘못된 감싸진 키 크기",
+			slot: func() *model.KeySlot {
+				slot := createTestKeySlot(file.ID, 0)
+				slot.WrappedKeyHex = "abcd"
+				return slot
+			}(),
+			wantErr: model.ErrInvalidWrappedKeySize.Error(),
+		},
+		{
+			name: "잘못된 반복 횟수",
+			slot: func() *model.KeySlot {
+				slot := createTestKeySlot(file.ID, 0)
+				slot.Iterations = model.MinIterations - 1
+				return slot
+			}(),
+			wantErr: model.ErrInvalidIterations.Error(),
+		},
+		{
+			name:    "존재하지 않는 파일",
+			slot:    createTestKeySlot(TestNonExistentID, 0),
+			wantErr: "FOREIGN KEY constraint failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := repo.Create(tc.slot)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+
+	// 같은 슬롯 번호 중복
+	require.NoError(t, repo.Create(createTestKeySlot(file.ID, 0)))
+	err := repo.Create(createTestKeySlot(file.ID, 0))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "UNIQUE constraint failed")
+	assert.ErrorIs(t, err, ErrDuplicate)
+
+	// 잘못된 ID
+	_, err = repo.GetByID(0)
+	assert.ErrorIs(t, err, ErrInvalidID)
+	_, err = repo.GetByFileID(0)
+	assert.ErrorIs(t, err, ErrInvalidID)
+	err = repo.DeleteByID(TestNonExistentID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "찾을 수 없습니다")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+}
+
+func TestKeySlotRepository_CascadeDelete(t *testing.T) {
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewKeySlotRepository(db)
+	file := createTestFileForEncryption(t, db, "_keyslot_cascade")
+	require.NoError(t, repo.Create(createTestKeySlot(file.ID, 0)))
+
+	// 파일 하드 삭제 시 키 슬롯도 함께 삭제되어야 함
+	err := db.Unscoped().Delete(file).Error
+	require.NoError(t, err)
+
+	count, err := repo.CountByFileID(file.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestKeySlotRepository_RoundTripWithCrypto(t *testing.T) {
+	db, cleanup := setupEncTestDB(t)
+	defer cleanup()
+
+	repo := NewKeySlotRepository(db)
+	file := createTestFileForEncryption(t, db, "_keyslot_crypto")
+	engine := crypto.NewCryptoEngine()
+
+	// 소유자/복구 패스워드로 암호화
+	encData, err := engine.Encrypt([]byte("team secret"), "owner-password", "recovery-password")
+	require.NoError(t, err)
+
+	// 키 슬롯 저장
+	for i, cryptoSlot := range encData.KeySlots {
+		slot := &model.KeySlot{FileID: file.ID, SlotIndex: i, Iterations: cryptoSlot.Iterations}
+		require.NoError(t, slot.SetSaltBytes(cryptoSlot.Salt))
+		require.NoError(t, slot.SetWrappedKeyBytes(cryptoSlot.WrappedKey))
+		require.NoError(t, repo.Create(slot))
+	}
+
+	// 저장된 슬롯으로 복원 후 복호화
+	stored, err := repo.GetByFileID(file.ID)
+	require.NoError(t, err)
+
+	restored := &crypto.EncryptedData{Version: encData.Version, Nonce: encData.Nonce, Ciphertext: encData.Ciphertext}
+	for _, slot := range stored {
+		salt, saltErr := slot.GetSaltBytes()
+		require.NoError(t, saltErr)
+		wrapped, wrapErr := slot.GetWrappedKeyBytes()
+		require.NoError(t, wrapErr)
+		restored.KeySlots = append(restored.KeySlots, &crypto.KeySlot{
+			Iterations: slot.Iterations,
+			Salt:       salt,
+			WrappedKey: wrapped,
+		})
+	}
+
+	for _, password := range []string{"owner-password", "recovery-password"} {
+		plaintext, decErr := engine.Decrypt(restored, password)
+		require.NoError(t, decErr, fmt.Sprintf("password %s", password))
+		assert.Equal(t, "team secret", string(plaintext))
+	}
+
+	_, err = engine.Decrypt(restored, "third-password")
+	assert.ErrorIs(t, err, crypto.ErrNoMatchingKeySlot)
+}
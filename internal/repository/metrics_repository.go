@@ -0,0 +1,161 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements repository pattern for daily metrics snapshot operations.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"DataLocker/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MetricsRepository 일별 통계 스냅샷 저장소 인터페이스
+//
+// 날짜는 model.MetricsDateLayout 형식의 UTC 날짜 문자열이며, 이 형식은 문자열
+// 순서와 날짜 순서가 같으므로 범위 조건에 그대로 사용합니다.
+type MetricsRepository interface {
+	// Collect 파일 테이블에서 day(UTC)의 통계를 집계합니다 (저장하지 않음)
+	Collect(ctx context.Context, day time.Time) (*model.MetricsSnapshot, error)
+
+	// CreateIfAbsent 같은 날짜의 스냅샷이 없을 때만 저장하고, 저장했는지 반환합니다
+	CreateIfAbsent(ctx context.Context, snapshot *model.MetricsSnapshot) (bool, error)
+
+	// ListSince from 이후(포함) 스냅샷을 날짜순으로 조회합니다
+	ListSince(ctx context.Context, from string) ([]*model.MetricsSnapshot, error)
+
+	// ListDates from~to(포함) 사이에 저장된 스냅샷 날짜를 날짜순으로 조회합니다
+	ListDates(ctx context.Context, from, to string) ([]string, error)
+
+	// DeleteBefore before 이전 날짜의 스냅샷을 삭제하고 삭제한 수를 반환합니다
+	DeleteBefore(ctx context.Context, before string) (int64, error)
+}
+
+// metricsRepository GORM 기반 일별 통계 스냅샷 저장소 구현체
+type metricsRepository struct {
+	db *gorm.DB
+}
+
+// NewMetricsRepository 새로운 일별 통계 스냅샷 저장소를 생성합니다
+func NewMetricsRepository(db *gorm.DB) MetricsRepository {
+	if db == nil {
+		panic("데이터베이스 연결이 필요합니다")
+	}
+
+	return &metricsRepository{
+		db: db,
+	}
+}
+
+// metricsAggregate Collect 집계 쿼리 결과
+type metricsAggregate struct {
+	TotalFiles int64
+	TotalBytes int64
+	Uploads    int64
+	Failures   int64
+}
+
+// metricsCollectQuery 하루 동안의 통계를 한 번에 집계하는 쿼리
+//
+// 그날이 끝난 시점에 남아 있던 파일(이후 삭제된 파일 포함)과 그날 생성된 파일을
+// 함께 세므로 소프트 삭제 범위를 적용하지 않습니다. 영구 삭제된 파일은 남아 있지
+// 않으므로 지난 날짜를 다시 집계하면 당시보다 작게 나올 수 있습니다.
+const metricsCollectQuery = `SELECT
+	COALESCE(SUM(CASE WHEN deleted_at IS NULL OR deleted_at >= @end THEN 1 ELSE 0 END), 0) AS total_files,
+	COALESCE(SUM(CASE WHEN (deleted_at IS NULL OR deleted_at >= @end) AND blob_file_id IS NULL THEN size ELSE 0 END), 0) AS total_bytes,
+	COALESCE(SUM(CASE WHEN created_at >= @start THEN 1 ELSE 0 END), 0) AS uploads,
+	COALESCE(SUM(CASE WHEN created_at >= @start AND status = @failed THEN 1 ELSE 0 END), 0) AS failures
+FROM files
+WHERE created_at < @end`
+
+// Collect day가 속한 UTC 하루의 통계를 집계합니다
+func (r *metricsRepository) Collect(ctx context.Context, day time.Time) (*model.MetricsSnapshot, error) {
+	day = day.UTC()
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	var aggregate metricsAggregate
+	err := r.db.WithContext(ctx).Raw(metricsCollectQuery, map[string]any{
+		"start":  start,
+		"end":    end,
+		"failed": model.FileStatusFailed,
+	}).Scan(&aggregate).Error
+	if err != nil {
+		return nil, fmt.Errorf("일별 통계 집계 실패: %w", err)
+	}
+
+	return &model.MetricsSnapshot{
+		Date:       start.Format(model.MetricsDateLayout),
+		TotalFiles: aggregate.TotalFiles,
+		TotalBytes: aggregate.TotalBytes,
+		Uploads:    aggregate.Uploads,
+		Failures:   aggregate.Failures,
+	}, nil
+}
+
+// CreateIfAbsent 날짜 유일 인덱스와 충돌하면 저장하지 않습니다
+//
+// 스케줄러와 backfill이 같은 날짜를 동시에 저장해도 먼저 저장한 스냅샷만 남습니다.
+func (r *metricsRepository) CreateIfAbsent(ctx context.Context, snapshot *model.MetricsSnapshot) (bool, error) {
+	if snapshot == nil {
+		return false, fmt.Errorf("스냅샷 데이터가 없습니다")
+	}
+
+	if _, err := time.Parse(model.MetricsDateLayout, snapshot.Date); err != nil {
+		return false, fmt.Errorf("유효하지 않은 스냅샷 날짜입니다: %q", snapshot.Date)
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "date"}}, DoNothing: true}).
+		Create(snapshot)
+	if result.Error != nil {
+		return false, fmt.Errorf("스냅샷 저장 실패: %w", translateError(result.Error))
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// ListSince from 이후 스냅샷을 날짜 오름차순으로 조회합니다 (없으면 빈 슬라이스)
+func (r *metricsRepository) ListSince(ctx context.Context, from string) ([]*model.MetricsSnapshot, error) {
+	snapshots := make([]*model.MetricsSnapshot, 0)
+	err := r.db.WithContext(ctx).
+		Where("date >= ?", from).
+		Order("date ASC").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("스냅샷 조회 실패: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// ListDates from~to 사이에 저장된 날짜만 조회합니다 (backfill의 누락일 계산용)
+func (r *metricsRepository) ListDates(ctx context.Context, from, to string) ([]string, error) {
+	var dates []string
+	err := r.db.WithContext(ctx).Model(&model.MetricsSnapshot{}).
+		Where("date >= ? AND date <= ?", from, to).
+		Order("date ASC").
+		Pluck("date", &dates).Error
+	if err != nil {
+		return nil, fmt.Errorf("스냅샷 날짜 조회 실패: %w", err)
+	}
+
+	return dates, nil
+}
+
+// DeleteBefore 보존 기간이 지난 스냅샷을 삭제합니다
+func (r *metricsRepository) DeleteBefore(ctx context.Context, before string) (int64, error) {
+	if _, err := time.Parse(model.MetricsDateLayout, before); err != nil {
+		return 0, fmt.Errorf("유효하지 않은 스냅샷 날짜입니다: %q", before)
+	}
+
+	result := r.db.WithContext(ctx).Where("date < ?", before).Delete(&model.MetricsSnapshot{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("오래된 스냅샷 삭제 실패: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetricsRepository(t *testing.T) {
+	assert.Panics(t, func() { NewMetricsRepository(nil) })
+}
+
+func TestMetricsRepository_Collect(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	files := NewFileRepository(db)
+	repo := NewMetricsRepository(db)
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	seed := []struct {
+		createdAt time.Time
+		status    string
+		size      int64
+	}{
+		{day.Add(-time.Hour), model.FileStatusEncrypted, 100},      // 전날 업로드
+		{day.Add(time.Hour), model.FileStatusEncrypted, 200},       // 당일 업로드
+		{day.Add(23 * time.Hour), model.FileStatusFailed, 300},     // 당일 실패
+		{day.Add(24 * time.Hour), model.FileStatusEncrypted, 1000}, // 다음 날 (제외)
+	}
+	created := make([]*model.File, len(seed))
+	for i, row := range seed {
+		file := createTestFile(fmt.Sprintf("_metrics_%d", i))
+		file.CreatedAt = row.createdAt
+		file.Status = row.status
+		file.Size = row.size
+		require.NoError(t, files.Create(ctx, file))
+		created[i] = file
+	}
+
+	// 당일 업로드된 중복 제거 참조는 개수에만 포함
+	ref := createTestFile("_metrics_ref")
+	ref.CreatedAt = day.Add(2 * time.Hour)
+	ref.BlobFileID = &created[0].ID
+	require.NoError(t, files.Create(ctx, ref))
+
+	// 다음 날 삭제된 파일은 포함, 당일 삭제된 파일은 제외
+	require.NoError(t, db.Unscoped().Model(&model.File{}).Where("id = ?", created[1].ID).
+		UpdateColumn("deleted_at", day.Add(30*time.Hour)).Error)
+	trashed := createTestFile("_metrics_trashed")
+	trashed.CreatedAt = day.Add(-48 * time.Hour)
+	require.NoError(t, files.Create(ctx, trashed))
+	require.NoError(t, db.Unscoped().Model(&model.File{}).Where("id = ?", trashed.ID).
+		UpdateColumn("deleted_at", day.Add(12*time.Hour)).Error)
+
+	// 같은 날의 어느 시각을 넘겨도 하루 전체를 집계
+	snapshot, err := repo.Collect(ctx, day.Add(15*time.Hour).In(time.FixedZone("KST", 9*60*60)))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-10", snapshot.Date)
+	assert.Equal(t, int64(4), snapshot.TotalFiles)
+	assert.Equal(t, int64(600), snapshot.TotalBytes)
+	assert.Equal(t, int64(3), snapshot.Uploads)
+	assert.Equal(t, int64(1), snapshot.Failures)
+
+	// 파일이 없던 날은 모두 0
+	empty, err := repo.Collect(ctx, day.AddDate(0, 0, -10))
+	require.NoError(t, err)
+	assert.Equal(t, &model.MetricsSnapshot{Date: "2024-02-29"}, empty)
+}
+
+func TestMetricsRepository_CreateListDelete(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewMetricsRepository(db)
+
+	for _, date := range []string{"2024-03-01", "2024-03-02", "2024-03-04"} {
+		created, err := repo.CreateIfAbsent(ctx, &model.MetricsSnapshot{Date: date, TotalFiles: 1})
+		require.NoError(t, err)
+		assert.True(t, created)
+	}
+
+	// 같은 날짜는 다시 저장하지 않고 처음 값을 유지
+	created, err := repo.CreateIfAbsent(ctx, &model.MetricsSnapshot{Date: "2024-03-02", TotalFiles: 99})
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	_, err = repo.CreateIfAbsent(ctx, &model.MetricsSnapshot{Date: "2024/03/05"})
+	assert.ErrorContains(t, err, "유효하지 않은 스냅샷 날짜입니다")
+	_, err = repo.CreateIfAbsent(ctx, nil)
+	assert.Error(t, err)
+
+	snapshots, err := repo.ListSince(ctx, "2024-03-02")
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "2024-03-02", snapshots[0].Date)
+	assert.Equal(t, int64(1), snapshots[0].TotalFiles)
+	assert.Equal(t, "2024-03-04", snapshots[1].Date)
+
+	dates, err := repo.ListDates(ctx, "2024-03-01", "2024-03-03")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-03-01", "2024-03-02"}, dates)
+
+	deleted, err := repo.DeleteBefore(ctx, "2024-03-02")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = repo.DeleteBefore(ctx, "")
+	assert.Error(t, err)
+
+	snapshots, err = repo.ListSince(ctx, "2000-01-01")
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+}
@@ -0,0 +1,63 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements transactions spanning several repositories.
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Repositories 같은 트랜잭션에 묶인 저장소 묶음
+type Repositories struct {
+	Files      FileRepository
+	Encryption EncryptionRepository
+	KeySlots   KeySlotRepository
+}
+
+// TxManager 여러 저장소의 쓰기를 하나의 트랜잭션으로 묶는 관리자
+type TxManager interface {
+	// WithinTransaction 트랜잭션에 묶인 저장소로 fn을 실행합니다
+	//
+	// fn이 에러를 반환하거나 패닉이 발생하면 롤백하고, 정상 종료하면 커밋합니다.
+	// 패닉은 롤백 후 다시 발생시킵니다. fn 안에서는 txRepos만 사용해야 하며,
+	// 바깥의 저장소로 쓰면 트랜잭션에 포함되지 않습니다.
+	WithinTransaction(ctx context.Context, fn func(txRepos Repositories) error) error
+}
+
+// txManager GORM 트랜잭션 기반 구현체
+type txManager struct {
+	db   *gorm.DB
+	opts []Option
+}
+
+// NewTxManager 새로운 트랜잭션 관리자를 생성합니다
+//
+// opts는 트랜잭션마다 만드는 파일/암호화 메타데이터 저장소에 그대로 전달합니다.
+func NewTxManager(db *gorm.DB, opts ...Option) TxManager {
+	if db == nil {
+		panic("데이터베이스 연결이 필요합니다")
+	}
+
+	return &txManager{
+		db:   db,
+		opts: opts,
+	}
+}
+
+// WithinTransaction 트랜잭션을 시작해 저장소를 묶고 fn을 실행합니다
+func (m *txManager) WithinTransaction(ctx context.Context, fn func(txRepos Repositories) error) error {
+	if fn == nil {
+		return fmt.Errorf("트랜잭션 함수가 없습니다")
+	}
+
+	// gorm.DB.Transaction은 에러와 패닉 모두 롤백 (패닉은 롤백 후 다시 발생)
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Files:      NewFileRepository(tx, m.opts...),
+			Encryption: NewEncryptionRepository(tx, m.opts...),
+			KeySlots:   NewKeySlotRepository(tx),
+		})
+	})
+}
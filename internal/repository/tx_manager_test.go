@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"DataLocker/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// countAllFiles 소프트 삭제된 레코드까지 포함한 파일 레코드 수를 반환합니다
+func countAllFiles(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, db.Unscoped().Model(&model.File{}).Count(&count).Error)
+	return count
+}
+
+// createFileWithMetadata 파일과 암호화 메타데이터를 한 트랜잭션에서 생성합니다
+func createFileWithMetadata(ctx context.Context, tx TxManager, file *model.File, saltHex string) error {
+	return tx.WithinTransaction(ctx, func(repos Repositories) error {
+		if err := repos.Files.Create(ctx, file); err != nil {
+			return err
+		}
+
+		metadata := createTestEncryptionMetadata(file.ID)
+		metadata.SaltHex = saltHex
+		return repos.Encryption.Create(ctx, metadata)
+	})
+}
+
+func TestTxManager_Commit(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	file := createTestFile("_tx_commit")
+	require.NoError(t, createFileWithMetadata(context.Background(), NewTxManager(db), file, TestValidSaltHex))
+
+	assert.Equal(t, int64(1), countAllFiles(t, db))
+	metadata, err := NewEncryptionRepository(db).GetByFileID(context.Background(), file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, file.ID, metadata.FileID)
+
+	assert.Panics(t, func() { NewTxManager(nil) })
+}
+
+func TestTxManager_RollbackOnMetadataFailure(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// 메타데이터 검증 실패 시 먼저 생성한 파일 레코드도 남지 않아야 함
+	err := createFileWithMetadata(context.Background(), NewTxManager(db), createTestFile("_tx_rollback"), "invalid")
+	require.Error(t, err)
+
+	assert.Zero(t, countAllFiles(t, db))
+}
+
+func TestTxManager_RollbackOnPanic(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	tx := NewTxManager(db)
+	assert.PanicsWithValue(t, "메타데이터 생성 중 패닉", func() {
+		_ = tx.WithinTransaction(context.Background(), func(repos Repositories) error {
+			require.NoError(t, repos.Files.Create(ctx, createTestFile("_tx_panic")))
+			panic("메타데이터 생성 중 패닉")
+		})
+	})
+
+	assert.Zero(t, countAllFiles(t, db))
+}
+
+func TestTxManager_CanceledContext(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := createFileWithMetadata(ctx, NewTxManager(db), createTestFile("_tx_canceled"), TestValidSaltHex)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, countAllFiles(t, db))
+}
+
+func TestSerializedTxManager(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	writer := NewWriteSerializer(0)
+	defer writer.Close()
+
+	tx := NewSerializedTxManager(NewTxManager(db), writer)
+
+	// 트랜잭션 하나가 쓰기 하나로 집계됨
+	require.NoError(t, createFileWithMetadata(context.Background(), tx, createTestFile("_tx_serialized"), TestValidSaltHex))
+	assert.Equal(t, int64(1), writer.Stats().Completed)
+
+	// 실패는 롤백되고 에러가 그대로 전달됨
+	rollbackErr := errors.New("롤백")
+	err := tx.WithinTransaction(context.Background(), func(repos Repositories) error {
+		require.NoError(t, repos.Files.Create(ctx, createTestFile("_tx_serialized_fail")))
+		return rollbackErr
+	})
+	assert.ErrorIs(t, err, rollbackErr)
+	assert.Equal(t, int64(1), countAllFiles(t, db))
+
+	// 패닉은 호출자 고루틴에서 다시 발생하고 직렬화기는 계속 동작
+	assert.Panics(t, func() {
+		_ = tx.WithinTransaction(context.Background(), func(Repositories) error { panic("패닉") })
+	})
+	require.NoError(t, writer.Do(func() error { return nil }))
+
+	assert.Panics(t, func() { NewSerializedTxManager(nil, writer) })
+	assert.Panics(t, func() { NewSerializedTxManager(NewTxManager(db), nil) })
+}
@@ -0,0 +1,143 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements repository pattern for directory validation session operations.
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"DataLocker/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// validationResultBatchSize 개별 결과를 한 번에 INSERT하는 건수
+const validationResultBatchSize = 200
+
+// ValidationRepository 디렉터리 검증 세션 저장소 인터페이스
+type ValidationRepository interface {
+	CreateSession(session *model.ValidationSession, results []*model.ValidationFileResult) error
+	GetSession(id string) (*model.ValidationSession, error)
+	ListResults(sessionID string, offset, limit int) ([]*model.ValidationFileResult, int64, error)
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// validationRepository GORM 기반 검증 세션 저장소 구현체
+type validationRepository struct {
+	db *gorm.DB
+}
+
+// NewValidationRepository 새로운 검증 세션 저장소를 생성합니다
+func NewValidationRepository(db *gorm.DB) ValidationRepository {
+	if db == nil {
+		panic("데이터베이스 연결이 필요합니다")
+	}
+
+	return &validationRepository{
+		db: db,
+	}
+}
+
+// CreateSession 세션 요약과 개별 결과를 한 트랜잭션에서 생성합니다
+//
+// 결과의 SessionID는 세션 ID로 채웁니다.
+func (r *validationRepository) CreateSession(session *model.ValidationSession, results []*model.ValidationFileResult) error {
+	if session == nil || session.ID == "" {
+		return fmt.Errorf("검증 세션 데이터가 없습니다")
+	}
+
+	for _, result := range results {
+		result.SessionID = session.ID
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Results").Create(session).Error; err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(results, validationResultBatchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("검증 세션 생성 실패: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// GetSession ID로 검증 세션 요약을 조회합니다 (만료 여부는 호출자가 판단)
+func (r *validationRepository) GetSession(id string) (*model.ValidationSession, error) {
+	if id == "" {
+		return nil, invalidID("검증 세션")
+	}
+
+	var session model.ValidationSession
+	err := r.db.Where("id = ?", id).First(&session).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("검증 세션을 찾을 수 없습니다: ID %s: %w", id, model.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("검증 세션 조회 실패: %w", err)
+	}
+
+	return &session, nil
+}
+
+// ListResults 세션의 개별 결과를 요청 순서대로 페이지 조회합니다
+//
+// 스트리밍처럼 전체를 나눠 읽는 호출자를 위해 limit은 MaxPageSize를 넘지 않게
+// 정규화하고, 0 이하면 DefaultPageSize를 사용합니다.
+func (r *validationRepository) ListResults(sessionID string, offset, limit int) ([]*model.ValidationFileResult, int64, error) {
+	if sessionID == "" {
+		return nil, 0, invalidID("검증 세션")
+	}
+
+	if offset < MinOffset {
+		offset = MinOffset
+	}
+	if limit <= 0 || limit > MaxPageSize {
+		limit = DefaultPageSize
+	}
+
+	query := r.db.Model(&model.ValidationFileResult{}).Where("session_id = ?", sessionID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("검증 결과 카운트 조회 실패: %w", err)
+	}
+
+	var results []*model.ValidationFileResult
+	err := r.db.Where("session_id = ?", sessionID).
+		Order("seq ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("검증 결과 조회 실패: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// DeleteExpired now 이전에 만료된 세션과 개별 결과를 삭제하고 삭제한 세션 수를 반환합니다
+//
+// 외래키 CASCADE가 꺼진 연결에서도 결과가 남지 않도록 결과를 먼저 지웁니다.
+func (r *validationRepository) DeleteExpired(now time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&model.ValidationSession{}).Select("id").Where("expires_at <= ?", now)
+		if err := tx.Where("session_id IN (?)", expired).Delete(&model.ValidationFileResult{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("expires_at <= ?", now).Delete(&model.ValidationSession{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("만료된 검증 세션 삭제 실패: %w", err)
+	}
+
+	return deleted, nil
+}
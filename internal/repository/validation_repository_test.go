@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestValidationSession 개별 결과 count개를 가진 검증 세션을 생성합니다
+func createTestValidationSession(t *testing.T, repo ValidationRepository, id string, expiresAt time.Time, count int) {
+	t.Helper()
+	results := make([]*model.ValidationFileResult, 0, count)
+	for i := range count {
+		results = append(results, &model.ValidationFileResult{
+			Seq:          i,
+			FileName:     fmt.Sprintf("file_%04d.txt", i),
+			RelativePath: fmt.Sprintf("dir/file_%04d.txt", i),
+			IsValid:      i%2 == 0,
+			MaxSize:      1024,
+			Errors:       []string{"파일이 너무 작습니다"},
+		})
+	}
+
+	session := &model.ValidationSession{
+		ID:            id,
+		ExpiresAt:     expiresAt,
+		DirectoryPath: "dir",
+		TotalFiles:    count,
+		Errors:        []string{"디렉터리 오류"},
+	}
+	require.NoError(t, repo.CreateSession(session, results))
+}
+
+func TestValidationRepository_CreateAndList(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewValidationRepository(db)
+	createTestValidationSession(t, repo, "session1", time.Now().Add(time.Hour), 450)
+
+	session, err := repo.GetSession("session1")
+	require.NoError(t, err)
+	assert.Equal(t, 450, session.TotalFiles)
+	assert.Equal(t, []string{"디렉터리 오류"}, session.Errors)
+
+	// 요청 순서대로 페이지 조회
+	results, total, err := repo.ListResults("session1", 440, MaxPageSize)
+	require.NoError(t, err)
+	assert.Equal(t, int64(450), total)
+	require.Len(t, results, 10)
+	assert.Equal(t, 440, results[0].Seq)
+	assert.Equal(t, "dir/file_0449.txt", results[9].RelativePath)
+	assert.Equal(t, []string{"파일이 너무 작습니다"}, results[0].Errors)
+
+	// limit 정규화
+	results, _, err = repo.ListResults("session1", 0, MaxPageSize+1)
+	require.NoError(t, err)
+	assert.Len(t, results, DefaultPageSize)
+
+	// 결과 없는 세션
+	require.NoError(t, repo.CreateSession(&model.ValidationSession{ID: "empty", ExpiresAt: time.Now(), DirectoryPath: "dir"}, nil))
+	results, total, err = repo.ListResults("empty", 0, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, results)
+
+	_, err = repo.GetSession("missing")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	assert.Error(t, repo.CreateSession(nil, nil))
+	_, _, err = repo.ListResults("", 0, 0)
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { NewValidationRepository(nil) })
+}
+
+func TestValidationRepository_DeleteExpired(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewValidationRepository(db)
+	now := time.Now()
+	createTestValidationSession(t, repo, "expired", now.Add(-time.Minute), 3)
+	createTestValidationSession(t, repo, "alive", now.Add(time.Hour), 2)
+
+	deleted, err := repo.DeleteExpired(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = repo.GetSession("expired")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	var orphans int64
+	require.NoError(t, db.Model(&model.ValidationFileResult{}).Where("session_id = ?", "expired").Count(&orphans).Error)
+	assert.Zero(t, orphans)
+
+	_, total, err := repo.ListResults("alive", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestSerializedValidationRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	writer := NewWriteSerializer(0)
+	defer writer.Close()
+
+	repo := NewSerializedValidationRepository(NewValidationRepository(db), writer)
+	createTestValidationSession(t, repo, "serialized", time.Now().Add(-time.Second), 1)
+
+	deleted, err := repo.DeleteExpired(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.Equal(t, int64(2), writer.Stats().Completed)
+
+	assert.Panics(t, func() { NewSerializedValidationRepository(nil, writer) })
+	assert.Panics(t, func() { NewSerializedValidationRepository(NewValidationRepository(db), nil) })
+}
@@ -0,0 +1,583 @@
+// Package repository provides data access layer for DataLocker application.
+// This file implements optional serialization of database writes through a single goroutine.
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"DataLocker/internal/model"
+)
+
+// 쓰기 직렬화 관련 상수
+const (
+	// DefaultWriteQueueSize 쓰기 대기열 기본 크기
+	DefaultWriteQueueSize = 256
+
+	// writeWaitSamples p99 대기 시간 계산에 쓰는 최근 표본 수
+	writeWaitSamples = 1024
+
+	// writeWaitPercentile 보고하는 대기 시간 백분위
+	writeWaitPercentile = 0.99
+)
+
+// ErrWriteSerializerClosed 닫힌 직렬화기에 쓰기를 요청한 경우
+var ErrWriteSerializerClosed = errors.New("DB 쓰기 직렬화기가 종료되었습니다")
+
+// WriteStats 쓰기 직렬화 메트릭
+type WriteStats struct {
+	QueueLength    int     `json:"queue_length"` // 실행을 기다리는 쓰기 수
+	QueueCapacity  int     `json:"queue_capacity"`
+	Completed      int64   `json:"completed"`
+	Failed         int64   `json:"failed"`      // 레코드 없음을 제외한 DB 오류
+	WaitAvgMillis  float64 `json:"wait_avg_ms"` // 대기열에 들어간 뒤 실행되기까지의 시간
+	WaitP99Millis  float64 `json:"wait_p99_ms"` // 최근 writeWaitSamples개 기준
+	WaitMaxMillis  float64 `json:"wait_max_ms"`
+	ExecAvgMillis  float64 `json:"exec_avg_ms"`
+	LastWriteError string  `json:"last_write_error,omitempty"`
+}
+
+// writeJob 대기열의 쓰기 작업
+type writeJob struct {
+	fn       func() error
+	enqueued time.Time
+	result   chan error
+}
+
+// WriteSerializer 모든 DB 쓰기를 단일 고루틴에서 순서대로 실행하는 직렬화기
+//
+// SQLite는 쓰기를 한 번에 하나만 허용하므로, 여러 고루틴이 동시에 쓰면 잠금을
+// 기다리며 busy 재시도만 늘어납니다. 직렬화기는 쓰기를 채널 대기열에 넣어 한 번에
+// 하나씩 실행하고, 호출자는 자기 쓰기가 끝날 때까지 기다립니다. 읽기는 거치지
+// 않습니다. 쓰기 함수 안에서 같은 직렬화기로 다시 쓰기를 요청하면 교착되므로,
+// 직렬화된 저장소의 쓰기 메서드는 서로를 호출하지 않아야 합니다.
+type WriteSerializer struct {
+	jobs chan writeJob
+	wg   sync.WaitGroup
+
+	closeOnce sync.Once
+	closeMu   sync.RWMutex // Do의 전송과 Close의 채널 닫기 경합 방지
+	closed    bool
+
+	mu        sync.Mutex
+	completed int64
+	failed    int64
+	waitTotal time.Duration
+	waitMax   time.Duration
+	execTotal time.Duration
+	waits     []time.Duration // 최근 대기 시간 (원형 버퍼)
+	next      int
+	lastErr   error
+}
+
+// NewWriteSerializer queueSize 크기의 대기열을 가진 직렬화기를 생성하고 시작합니다
+//
+// queueSize가 0 이하이면 DefaultWriteQueueSize를 사용합니다. 대기열이 가득 차면
+// Do는 자리가 날 때까지 기다립니다. 사용이 끝나면 Close를 호출해야 합니다.
+func NewWriteSerializer(queueSize int) *WriteSerializer {
+	if queueSize <= 0 {
+		queueSize = DefaultWriteQueueSize
+	}
+
+	s := &WriteSerializer{
+		jobs:  make(chan writeJob, queueSize),
+		waits: make([]time.Duration, 0, writeWaitSamples),
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+// Do 쓰기 함수를 대기열에 넣고 실행이 끝날 때까지 기다립니다
+func (s *WriteSerializer) Do(fn func() error) error {
+	job := writeJob{fn: fn, enqueued: time.Now(), result: make(chan error, 1)}
+
+	s.closeMu.RLock()
+	if s.closed {
+		s.closeMu.RUnlock()
+		return ErrWriteSerializerClosed
+	}
+	s.jobs <- job
+	s.closeMu.RUnlock()
+
+	return <-job.result
+}
+
+// Close 대기열에 남은 쓰기를 모두 실행한 뒤 직렬화기를 종료합니다
+func (s *WriteSerializer) Close() {
+	s.closeOnce.Do(func() {
+		s.closeMu.Lock()
+		s.closed = true
+		close(s.jobs)
+		s.closeMu.Unlock()
+
+		s.wg.Wait()
+	})
+}
+
+// Stats 현재까지의 쓰기 메트릭을 반환합니다
+func (s *WriteSerializer) Stats() WriteStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := WriteStats{
+		QueueLength:   len(s.jobs),
+		QueueCapacity: cap(s.jobs),
+		Completed:     s.completed,
+		Failed:        s.failed,
+		WaitMaxMillis: millis(s.waitMax),
+		WaitP99Millis: millis(percentile(s.waits, writeWaitPercentile)),
+	}
+	if total := s.completed + s.failed; total > 0 {
+		stats.WaitAvgMillis = millis(s.waitTotal) / float64(total)
+		stats.ExecAvgMillis = millis(s.execTotal) / float64(total)
+	}
+	if s.lastErr != nil {
+		stats.LastWriteError = s.lastErr.Error()
+	}
+	return stats
+}
+
+// run 대기열의 쓰기를 하나씩 실행합니다
+func (s *WriteSerializer) run() {
+	defer s.wg.Done()
+
+	for job := range s.jobs {
+		started := time.Now()
+		err := job.fn()
+		s.record(started.Sub(job.enqueued), time.Since(started), err)
+		job.result <- err
+	}
+}
+
+// record 쓰기 한 건의 대기/실행 시간을 기록합니다
+func (s *WriteSerializer) record(wait, exec time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil && !errors.Is(err, model.ErrRecordNotFound) {
+		s.failed++
+		s.lastErr = err
+	} else {
+		s.completed++
+	}
+
+	s.waitTotal += wait
+	s.execTotal += exec
+	s.waitMax = max(s.waitMax, wait)
+
+	if len(s.waits) < writeWaitSamples {
+		s.waits = append(s.waits, wait)
+	} else {
+		s.waits[s.next] = wait
+	}
+	s.next = (s.next + 1) % writeWaitSamples
+}
+
+// percentile 표본의 p 백분위 값을 반환합니다 (표본이 없으면 0)
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	index := int(float64(len(sorted)-1) * p)
+	return sorted[index]
+}
+
+// millis 시간을 밀리초 실수로 변환합니다
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// serializedFileRepository 쓰기만 직렬화기를 거치는 파일 저장소
+type serializedFileRepository struct {
+	FileRepository
+	writer *WriteSerializer
+}
+
+// NewSerializedFileRepository 쓰기(Create, CreateBatch, Update, UpdateStatus, Touch, ReissueShortCode, AddTags, RemoveTag, CreateFolder, EnsureFolderPath, MoveFolder, Delete, DeleteBatch, Restore, Purge, PurgeDeletedOlderThan)를 직렬화하는 파일 저장소를 생성합니다
+//
+// 조회 메서드는 repo를 그대로 호출합니다.
+func NewSerializedFileRepository(repo FileRepository, writer *WriteSerializer) FileRepository {
+	if repo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+	if writer == nil {
+		panic("쓰기 직렬화기가 필요합니다")
+	}
+
+	return &serializedFileRepository{FileRepository: repo, writer: writer}
+}
+
+// Create 파일 레코드 생성을 직렬화해 실행합니다
+func (r *serializedFileRepository) Create(ctx context.Context, file *model.File) error {
+	return r.writer.Do(func() error { return r.FileRepository.Create(ctx, file) })
+}
+
+// CreateBatch 파일 레코드 일괄 생성을 직렬화해 실행합니다
+func (r *serializedFileRepository) CreateBatch(ctx context.Context, files []*model.File) error {
+	return r.writer.Do(func() error { return r.FileRepository.CreateBatch(ctx, files) })
+}
+
+// Update 파일 레코드 업데이트를 직렬화해 실행합니다
+func (r *serializedFileRepository) Update(ctx context.Context, file *model.File) error {
+	return r.writer.Do(func() error { return r.FileRepository.Update(ctx, file) })
+}
+
+// UpdateStatus 파일 상태 변경을 직렬화해 실행합니다
+func (r *serializedFileRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
+	return r.writer.Do(func() error { return r.FileRepository.UpdateStatus(ctx, id, status) })
+}
+
+// Touch 파일 접근 시각 기록을 직렬화해 실행합니다
+func (r *serializedFileRepository) Touch(ctx context.Context, id uint) error {
+	return r.writer.Do(func() error { return r.FileRepository.Touch(ctx, id) })
+}
+
+// ReissueShortCode 짧은 코드 재발급을 직렬화해 실행합니다
+func (r *serializedFileRepository) ReissueShortCode(ctx context.Context, id uint) (string, error) {
+	var code string
+	err := r.writer.Do(func() error {
+		var err error
+		code, err = r.FileRepository.ReissueShortCode(ctx, id)
+		return err
+	})
+	return code, err
+}
+
+// AddTags 태그 추가를 직렬화해 실행합니다
+func (r *serializedFileRepository) AddTags(ctx context.Context, fileID uint, tags []string) error {
+	return r.writer.Do(func() error { return r.FileRepository.AddTags(ctx, fileID, tags) })
+}
+
+// RemoveTag 태그 제거를 직렬화해 실행합니다
+func (r *serializedFileRepository) RemoveTag(ctx context.Context, fileID uint, tag string) error {
+	return r.writer.Do(func() error { return r.FileRepository.RemoveTag(ctx, fileID, tag) })
+}
+
+// CreateFolder 폴더 생성을 직렬화해 실행합니다
+func (r *serializedFileRepository) CreateFolder(ctx context.Context, folder *model.Folder) error {
+	return r.writer.Do(func() error { return r.FileRepository.CreateFolder(ctx, folder) })
+}
+
+// EnsureFolderPath 경로의 폴더 생성을 직렬화해 실행합니다
+func (r *serializedFileRepository) EnsureFolderPath(ctx context.Context, parentID *uint, path string) (*model.Folder, error) {
+	var folder *model.Folder
+	err := r.writer.Do(func() error {
+		var err error
+		folder, err = r.FileRepository.EnsureFolderPath(ctx, parentID, path)
+		return err
+	})
+	return folder, err
+}
+
+// MoveFolder 폴더 이동을 직렬화해 실행합니다
+func (r *serializedFileRepository) MoveFolder(ctx context.Context, id uint, parentID *uint) error {
+	return r.writer.Do(func() error { return r.FileRepository.MoveFolder(ctx, id, parentID) })
+}
+
+// BulkUpdateStatus 파일 상태 일괄 변경을 직렬화해 실행합니다
+func (r *serializedFileRepository) BulkUpdateStatus(ctx context.Context, fromStatus, toStatus string, olderThan time.Time) (int64, error) {
+	var affected int64
+	err := r.writer.Do(func() error {
+		var err error
+		affected, err = r.FileRepository.BulkUpdateStatus(ctx, fromStatus, toStatus, olderThan)
+		return err
+	})
+	return affected, err
+}
+
+// Delete 파일 소프트 삭제를 직렬화해 실행합니다
+func (r *serializedFileRepository) Delete(ctx context.Context, id uint) error {
+	return r.writer.Do(func() error { return r.FileRepository.Delete(ctx, id) })
+}
+
+// DeleteBatch 파일 일괄 소프트 삭제를 직렬화해 실행합니다
+func (r *serializedFileRepository) DeleteBatch(ctx context.Context, ids []uint) (int64, error) {
+	var deleted int64
+	err := r.writer.Do(func() error {
+		var err error
+		deleted, err = r.FileRepository.DeleteBatch(ctx, ids)
+		return err
+	})
+	return deleted, err
+}
+
+// Restore 파일 복구를 직렬화해 실행합니다
+func (r *serializedFileRepository) Restore(ctx context.Context, id uint) error {
+	return r.writer.Do(func() error { return r.FileRepository.Restore(ctx, id) })
+}
+
+// Purge 파일 영구 삭제를 직렬화해 실행합니다
+func (r *serializedFileRepository) Purge(ctx context.Context, id uint) error {
+	return r.writer.Do(func() error { return r.FileRepository.Purge(ctx, id) })
+}
+
+// PurgeDeletedOlderThan 오래된 소프트 삭제 파일의 영구 삭제를 직렬화해 실행합니다
+func (r *serializedFileRepository) PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	var purged int64
+	err := r.writer.Do(func() error {
+		var err error
+		purged, err = r.FileRepository.PurgeDeletedOlderThan(ctx, cutoff)
+		return err
+	})
+	return purged, err
+}
+
+// serializedEncryptionRepository 쓰기만 직렬화기를 거치는 암호화 메타데이터 저장소
+type serializedEncryptionRepository struct {
+	EncryptionRepository
+	writer *WriteSerializer
+}
+
+// NewSerializedEncryptionRepository 쓰기를 직렬화하는 암호화 메타데이터 저장소를 생성합니다
+func NewSerializedEncryptionRepository(repo EncryptionRepository, writer *WriteSerializer) EncryptionRepository {
+	if repo == nil {
+		panic("암호화 메타데이터 저장소가 필요합니다")
+	}
+	if writer == nil {
+		panic("쓰기 직렬화기가 필요합니다")
+	}
+
+	return &serializedEncryptionRepository{EncryptionRepository: repo, writer: writer}
+}
+
+// Create 암호화 메타데이터 생성을 직렬화해 실행합니다
+func (r *serializedEncryptionRepository) Create(ctx context.Context, metadata *model.EncryptionMetadata) error {
+	return r.writer.Do(func() error { return r.EncryptionRepository.Create(ctx, metadata) })
+}
+
+// CreateBatch 암호화 메타데이터 일괄 생성을 직렬화해 실행합니다
+func (r *serializedEncryptionRepository) CreateBatch(ctx context.Context, metadata []*model.EncryptionMetadata) error {
+	return r.writer.Do(func() error { return r.EncryptionRepository.CreateBatch(ctx, metadata) })
+}
+
+// Update 암호화 메타데이터 업데이트를 직렬화해 실행합니다
+func (r *serializedEncryptionRepository) Update(ctx context.Context, metadata *model.EncryptionMetadata) error {
+	return r.writer.Do(func() error { return r.EncryptionRepository.Update(ctx, metadata) })
+}
+
+// UpsertByFileID 암호화 메타데이터 upsert를 직렬화해 실행합니다
+func (r *serializedEncryptionRepository) UpsertByFileID(ctx context.Context, metadata *model.EncryptionMetadata) error {
+	return r.writer.Do(func() error { return r.EncryptionRepository.UpsertByFileID(ctx, metadata) })
+}
+
+// DeleteByID 암호화 메타데이터 삭제를 직렬화해 실행합니다
+func (r *serializedEncryptionRepository) DeleteByID(ctx context.Context, id uint) error {
+	return r.writer.Do(func() error { return r.EncryptionRepository.DeleteByID(ctx, id) })
+}
+
+// DeleteByFileID 파일의 암호화 메타데이터 삭제를 직렬화해 실행합니다
+func (r *serializedEncryptionRepository) DeleteByFileID(ctx context.Context, fileID uint) error {
+	return r.writer.Do(func() error { return r.EncryptionRepository.DeleteByFileID(ctx, fileID) })
+}
+
+// serializedKeySlotRepository 쓰기만 직렬화기를 거치는 키 슬롯 저장소
+type serializedKeySlotRepository struct {
+	KeySlotRepository
+	writer *WriteSerializer
+}
+
+// NewSerializedKeySlotRepository 쓰기를 직렬화하는 키 슬롯 저장소를 생성합니다
+func NewSerializedKeySlotRepository(repo KeySlotRepository, writer *WriteSerializer) KeySlotRepository {
+	if repo == nil {
+		panic("키 슬롯 저장소가 필요합니다")
+	}
+	if writer == nil {
+		panic("쓰기 직렬화기가 필요합니다")
+	}
+
+	return &serializedKeySlotRepository{KeySlotRepository: repo, writer: writer}
+}
+
+// Create 키 슬롯 생성을 직렬화해 실행합니다
+func (r *serializedKeySlotRepository) Create(slot *model.KeySlot) error {
+	return r.writer.Do(func() error { return r.KeySlotRepository.Create(slot) })
+}
+
+// Update 키 슬롯 업데이트를 직렬화해 실행합니다
+func (r *serializedKeySlotRepository) Update(slot *model.KeySlot) error {
+	return r.writer.Do(func() error { return r.KeySlotRepository.Update(slot) })
+}
+
+// DeleteByID 키 슬롯 삭제를 직렬화해 실행합니다
+func (r *serializedKeySlotRepository) DeleteByID(id uint) error {
+	return r.writer.Do(func() error { return r.KeySlotRepository.DeleteByID(id) })
+}
+
+// DeleteByFileID 파일의 키 슬롯 삭제를 직렬화해 실행합니다
+func (r *serializedKeySlotRepository) DeleteByFileID(fileID uint) error {
+	return r.writer.Do(func() error { return r.KeySlotRepository.DeleteByFileID(fileID) })
+}
+
+// serializedValidationRepository 쓰기만 직렬화기를 거치는 검증 세션 저장소
+type serializedValidationRepository struct {
+	ValidationRepository
+	writer *WriteSerializer
+}
+
+// NewSerializedValidationRepository 쓰기를 직렬화하는 검증 세션 저장소를 생성합니다
+func NewSerializedValidationRepository(repo ValidationRepository, writer *WriteSerializer) ValidationRepository {
+	if repo == nil {
+		panic("검증 세션 저장소가 필요합니다")
+	}
+	if writer == nil {
+		panic("쓰기 직렬화기가 필요합니다")
+	}
+
+	return &serializedValidationRepository{ValidationRepository: repo, writer: writer}
+}
+
+// CreateSession 검증 세션 생성을 직렬화해 실행합니다
+func (r *serializedValidationRepository) CreateSession(session *model.ValidationSession, results []*model.ValidationFileResult) error {
+	return r.writer.Do(func() error { return r.ValidationRepository.CreateSession(session, results) })
+}
+
+// DeleteExpired 만료된 검증 세션 삭제를 직렬화해 실행합니다
+func (r *serializedValidationRepository) DeleteExpired(now time.Time) (int64, error) {
+	var deleted int64
+	err := r.writer.Do(func() error {
+		var err error
+		deleted, err = r.ValidationRepository.DeleteExpired(now)
+		return err
+	})
+	return deleted, err
+}
+
+// serializedMetricsRepository 쓰기만 직렬화기를 거치는 일별 통계 스냅샷 저장소
+type serializedMetricsRepository struct {
+	MetricsRepository
+	writer *WriteSerializer
+}
+
+// NewSerializedMetricsRepository 쓰기(CreateIfAbsent, DeleteBefore)를 직렬화하는 일별 통계 스냅샷 저장소를 생성합니다
+func NewSerializedMetricsRepository(repo MetricsRepository, writer *WriteSerializer) MetricsRepository {
+	if repo == nil {
+		panic("일별 통계 스냅샷 저장소가 필요합니다")
+	}
+	if writer == nil {
+		panic("쓰기 직렬화기가 필요합니다")
+	}
+
+	return &serializedMetricsRepository{MetricsRepository: repo, writer: writer}
+}
+
+// CreateIfAbsent 스냅샷 저장을 직렬화해 실행합니다
+func (r *serializedMetricsRepository) CreateIfAbsent(ctx context.Context, snapshot *model.MetricsSnapshot) (bool, error) {
+	var created bool
+	err := r.writer.Do(func() error {
+		var err error
+		created, err = r.MetricsRepository.CreateIfAbsent(ctx, snapshot)
+		return err
+	})
+	return created, err
+}
+
+// DeleteBefore 오래된 스냅샷 삭제를 직렬화해 실행합니다
+func (r *serializedMetricsRepository) DeleteBefore(ctx context.Context, before string) (int64, error) {
+	var deleted int64
+	err := r.writer.Do(func() error {
+		var err error
+		deleted, err = r.MetricsRepository.DeleteBefore(ctx, before)
+		return err
+	})
+	return deleted, err
+}
+
+// serializedIdempotencyRepository 쓰기만 직렬화기를 거치는 Idempotency-Key 기록 저장소
+type serializedIdempotencyRepository struct {
+	IdempotencyRepository
+	writer *WriteSerializer
+}
+
+// NewSerializedIdempotencyRepository 쓰기를 직렬화하는 Idempotency-Key 기록 저장소를 생성합니다
+func NewSerializedIdempotencyRepository(repo IdempotencyRepository, writer *WriteSerializer) IdempotencyRepository {
+	if repo == nil {
+		panic("Idempotency-Key 기록 저장소가 필요합니다")
+	}
+	if writer == nil {
+		panic("쓰기 직렬화기가 필요합니다")
+	}
+
+	return &serializedIdempotencyRepository{IdempotencyRepository: repo, writer: writer}
+}
+
+// Create 기록 생성을 직렬화해 실행합니다
+func (r *serializedIdempotencyRepository) Create(ctx context.Context, record *model.IdempotencyRecord) error {
+	return r.writer.Do(func() error { return r.IdempotencyRepository.Create(ctx, record) })
+}
+
+// Complete 응답 저장을 직렬화해 실행합니다
+func (r *serializedIdempotencyRepository) Complete(ctx context.Context, id uint, statusCode int, contentType string, body []byte) error {
+	return r.writer.Do(func() error {
+		return r.IdempotencyRepository.Complete(ctx, id, statusCode, contentType, body)
+	})
+}
+
+// Delete 기록 삭제를 직렬화해 실행합니다
+func (r *serializedIdempotencyRepository) Delete(ctx context.Context, id uint) error {
+	return r.writer.Do(func() error { return r.IdempotencyRepository.Delete(ctx, id) })
+}
+
+// DeleteExpired 만료된 기록 삭제를 직렬화해 실행합니다
+func (r *serializedIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	err := r.writer.Do(func() error {
+		var err error
+		deleted, err = r.IdempotencyRepository.DeleteExpired(ctx, now)
+		return err
+	})
+	return deleted, err
+}
+
+// serializedTxManager 트랜잭션 전체를 직렬화기에서 실행하는 트랜잭션 관리자
+type serializedTxManager struct {
+	TxManager
+	writer *WriteSerializer
+}
+
+// NewSerializedTxManager 트랜잭션을 쓰기 하나로 직렬화하는 트랜잭션 관리자를 생성합니다
+//
+// 트랜잭션은 커밋할 때까지 SQLite 쓰기 잠금을 잡고 있으므로 다른 직렬화된
+// 쓰기와 함께 대기열을 거칩니다. 트랜잭션 안의 저장소는 직렬화되지 않은 원본이라
+// fn에서 쓰기를 해도 교착되지 않지만, fn에서 바깥의 직렬화된 저장소로 쓰면
+// 교착됩니다.
+func NewSerializedTxManager(tx TxManager, writer *WriteSerializer) TxManager {
+	if tx == nil {
+		panic("트랜잭션 관리자가 필요합니다")
+	}
+	if writer == nil {
+		panic("쓰기 직렬화기가 필요합니다")
+	}
+
+	return &serializedTxManager{TxManager: tx, writer: writer}
+}
+
+// WithinTransaction 트랜잭션 실행을 직렬화해 실행합니다
+//
+// fn의 패닉은 직렬화기 고루틴을 멈추지 않도록 그곳에서 회수한 뒤, 롤백이 끝나면
+// 호출자 고루틴에서 다시 발생시킵니다.
+func (m *serializedTxManager) WithinTransaction(ctx context.Context, fn func(txRepos Repositories) error) error {
+	var panicked any
+	err := m.writer.Do(func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = r
+				err = fmt.Errorf("트랜잭션 중 패닉이 발생했습니다: %v", r)
+			}
+		}()
+		return m.TxManager.WithinTransaction(ctx, fn)
+	})
+
+	if panicked != nil {
+		panic(panicked)
+	}
+	return err
+}
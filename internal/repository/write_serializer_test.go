@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"DataLocker/internal/database"
+	"DataLocker/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 쓰기 직렬화 테스트 상수
+const (
+	// benchWriters 벤치마크에서 동시에 쓰는 고루틴 수
+	benchWriters = 100
+
+	// benchBusyTimeoutMs 잠금 경합이 드러나도록 짧게 둔 busy 대기 시간
+	benchBusyTimeoutMs = 20
+)
+
+func TestWriteSerializer_Do(t *testing.T) {
+	writer := NewWriteSerializer(4)
+	defer writer.Close()
+
+	// 제출한 순서대로 실행
+	var order []int
+	for i := range 3 {
+		require.NoError(t, writer.Do(func() error {
+			order = append(order, i)
+			return nil
+		}))
+	}
+	assert.Equal(t, []int{0, 1, 2}, order)
+
+	// 쓰기 함수의 에러를 그대로 전달
+	writeErr := errors.New("쓰기 실패")
+	assert.ErrorIs(t, writer.Do(func() error { return writeErr }), writeErr)
+
+	stats := writer.Stats()
+	assert.Equal(t, int64(3), stats.Completed)
+	assert.Equal(t, int64(1), stats.Failed)
+	assert.Equal(t, "쓰기 실패", stats.LastWriteError)
+	assert.Equal(t, 4, stats.QueueCapacity)
+}
+
+func TestWriteSerializer_NotFoundIsNotFailure(t *testing.T) {
+	writer := NewWriteSerializer(0)
+	defer writer.Close()
+
+	err := writer.Do(func() error { return fmt.Errorf("없음: %w", model.ErrRecordNotFound) })
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	stats := writer.Stats()
+	assert.Equal(t, int64(1), stats.Completed)
+	assert.Zero(t, stats.Failed)
+	assert.Equal(t, DefaultWriteQueueSize, stats.QueueCapacity)
+}
+
+func TestWriteSerializer_QueueLength(t *testing.T) {
+	writer := NewWriteSerializer(8)
+	defer writer.Close()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var wg sync.WaitGroup
+
+	// 첫 쓰기가 막혀 있는 동안 나머지는 대기열에서 기다림
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = writer.Do(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = writer.Do(func() error { return nil })
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return writer.Stats().QueueLength == 3 }, time.Second, time.Millisecond)
+
+	close(release)
+	wg.Wait()
+
+	stats := writer.Stats()
+	assert.Zero(t, stats.QueueLength)
+	assert.Equal(t, int64(4), stats.Completed)
+	assert.Positive(t, stats.WaitMaxMillis)
+	assert.GreaterOrEqual(t, stats.WaitMaxMillis, stats.WaitP99Millis)
+}
+
+func TestWriteSerializer_Close(t *testing.T) {
+	writer := NewWriteSerializer(1)
+
+	var done atomic.Bool
+	require.NoError(t, writer.Do(func() error {
+		done.Store(true)
+		return nil
+	}))
+
+	writer.Close()
+	writer.Close() // 두 번 호출해도 안전
+
+	assert.True(t, done.Load())
+	assert.ErrorIs(t, writer.Do(func() error { return nil }), ErrWriteSerializerClosed)
+}
+
+func TestSerializedFileRepository(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	writer := NewWriteSerializer(0)
+	defer writer.Close()
+
+	repo := NewSerializedFileRepository(NewFileRepository(db), writer)
+
+	file := createTestFile("_serialized")
+	require.NoError(t, repo.Create(ctx, file))
+	require.NotZero(t, file.ID)
+
+	file.Status = model.FileStatusEncrypted
+	require.NoError(t, repo.Update(ctx, file))
+
+	// 조회는 직렬화기를 거치지 않음
+	found, err := repo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStatusEncrypted, found.Status)
+
+	require.NoError(t, repo.UpdateStatus(ctx, file.ID, model.FileStatusCorrupted))
+
+	require.NoError(t, repo.Delete(ctx, file.ID))
+	assert.ErrorIs(t, repo.Delete(ctx, TestNonExistentID), model.ErrRecordNotFound)
+
+	stats := writer.Stats()
+	assert.Equal(t, int64(5), stats.Completed)
+	assert.Zero(t, stats.Failed)
+
+	assert.Panics(t, func() { NewSerializedFileRepository(nil, writer) })
+	assert.Panics(t, func() { NewSerializedFileRepository(NewFileRepository(db), nil) })
+}
+
+func TestSerializedFileRepository_ReadsNotBlocked(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	writer := NewWriteSerializer(0)
+	defer writer.Close()
+
+	repo := NewSerializedFileRepository(NewFileRepository(db), writer)
+	file := createTestFile("_read")
+	require.NoError(t, repo.Create(ctx, file))
+
+	// 직렬화기를 점유한 동안에도 조회는 바로 끝나야 함
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = writer.Do(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	defer close(release)
+
+	found, err := repo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, file.ID, found.ID)
+}
+
+func TestSerializedKeySlotAndEncryptionRepository(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	writer := NewWriteSerializer(0)
+	defer writer.Close()
+
+	file := createTestFileForEncryption(t, db, "_serialized")
+
+	encRepo := NewSerializedEncryptionRepository(NewEncryptionRepository(db), writer)
+	require.NoError(t, encRepo.Create(ctx, createTestEncryptionMetadata(file.ID)))
+	_, err := encRepo.GetByFileID(ctx, file.ID)
+	require.NoError(t, err)
+	require.NoError(t, encRepo.DeleteByFileID(ctx, file.ID))
+
+	slotRepo := NewSerializedKeySlotRepository(NewKeySlotRepository(db), writer)
+	assert.ErrorIs(t, slotRepo.DeleteByID(TestNonExistentID), model.ErrRecordNotFound)
+
+	assert.Equal(t, int64(3), writer.Stats().Completed)
+	assert.Panics(t, func() { NewSerializedEncryptionRepository(nil, writer) })
+	assert.Panics(t, func() { NewSerializedKeySlotRepository(nil, writer) })
+}
+
+// openBenchDB 벤치마크용 파일 DB를 엽니다 (busy 대기 시간을 짧게 설정)
+func openBenchDB(b *testing.B) *gorm.DB {
+	b.Helper()
+
+	dbPath := filepath.Join(b.TempDir(), "bench.db")
+	dsn := fmt.Sprintf("%s?_foreign_keys=ON&_journal_mode=WAL&_sync=NORMAL&_busy_timeout=%d", dbPath, benchBusyTimeoutMs)
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
+	})
+	require.NoError(b, err)
+	_, err = model.MigrateUp(db)
+	require.NoError(b, err)
+
+	b.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+		_ = os.Remove(dbPath)
+	})
+	return db
+}
+
+// BenchmarkConcurrentCreate 고루틴 100개가 동시에 파일을 생성할 때의 에러율과 p99 지연
+//
+//	go test ./internal/repository -run '^$' -bench ConcurrentCreate -benchtime 5x
+//
+// direct는 SQLite 잠금 경합으로 "database is locked" 에러가 나고 지연이 튀지만,
+// serialized는 대기열에서 순서대로 실행되어 에러 없이 끝납니다.
+func BenchmarkConcurrentCreate(b *testing.B) {
+	ctx := context.Background()
+	variants := []struct {
+		name string
+		wrap func(FileRepository) (FileRepository, func())
+	}{
+		{name: "direct", wrap: func(repo FileRepository) (FileRepository, func()) {
+			return repo, func() {}
+		}},
+		{name: "serialized", wrap: func(repo FileRepository) (FileRepository, func()) {
+			writer := NewWriteSerializer(0)
+			return NewSerializedFileRepository(repo, writer), writer.Close
+		}},
+	}
+
+	for _, v := range variants {
+		b.Run(v.name, func(b *testing.B) {
+			repo, closeRepo := v.wrap(NewFileRepository(openBenchDB(b)))
+			defer closeRepo()
+
+			var (
+				failures  atomic.Int64
+				mu        sync.Mutex
+				latencies []time.Duration
+			)
+
+			b.ResetTimer()
+			for i := range b.N {
+				var wg sync.WaitGroup
+				for w := range benchWriters {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+
+						started := time.Now()
+						err := repo.Create(ctx, createTestFile(fmt.Sprintf("_%d_%d", i, w)))
+						elapsed := time.Since(started)
+
+						if err != nil {
+							failures.Add(1)
+						}
+						mu.Lock()
+						latencies = append(latencies, elapsed)
+						mu.Unlock()
+					}()
+				}
+				wg.Wait()
+			}
+			b.StopTimer()
+
+			total := float64(b.N * benchWriters)
+			b.ReportMetric(float64(failures.Load())/total*100, "err%")
+			b.ReportMetric(millis(percentile(latencies, writeWaitPercentile)), "p99-ms")
+		})
+	}
+}
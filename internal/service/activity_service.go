@@ -0,0 +1,161 @@
+// Package service provides business logic for DataLocker.
+// This file implements the recent file activity feed shown on the dashboard summary card.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+)
+
+// 활동 피드 관련 상수
+const (
+	// ActivityFeedTTL 같은 조건의 피드를 다시 조회하지 않고 돌려주는 시간
+	ActivityFeedTTL = 10 * time.Second
+
+	// DefaultActivityLimit limit을 지정하지 않았을 때의 이벤트 수 (요약 카드 한 장 분량)
+	DefaultActivityLimit = 10
+
+	// MaxActivityLimit 한 번에 조회할 수 있는 최대 이벤트 수
+	MaxActivityLimit = repository.MaxPageSize
+
+	// maxCachedActivityFeeds 캐시에 보관하는 조건 조합 수 (넘치면 만료된 항목부터 비움)
+	maxCachedActivityFeeds = 256
+)
+
+// ErrInvalidActivityPage offset이 음수이거나 limit이 허용 범위를 벗어남
+var ErrInvalidActivityPage = errors.New("잘못된 활동 피드 범위입니다")
+
+// ActivityQuery 활동 피드 조회 조건
+type ActivityQuery struct {
+	OwnerID uint // 0이 아니면 이 사용자가 소유한 파일의 이벤트만
+	Offset  int
+	Limit   int // 0이면 DefaultActivityLimit
+}
+
+// ActivityEvent 피드에 표시하는 이벤트 한 건 (경로, 키 정보 등 민감 정보는 포함하지 않음)
+type ActivityEvent struct {
+	Type       string    `json:"type"` // uploaded, failed, deleted
+	FileID     uint      `json:"file_id"`
+	FileName   string    `json:"file_name"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor"`
+}
+
+// ActivityFeed 최신순 활동 피드 한 페이지
+type ActivityFeed struct {
+	Events []ActivityEvent `json:"events"` // 이벤트가 없으면 빈 배열
+	Total  int64           `json:"total"`
+	Offset int             `json:"offset"`
+	Limit  int             `json:"limit"`
+}
+
+// ActivityService 최근 파일 활동 피드 서비스
+type ActivityService interface {
+	// Recent 업로드, 실패, 삭제 이벤트를 최신순으로 조회합니다 (ActivityFeedTTL 동안 캐시)
+	Recent(ctx context.Context, query ActivityQuery) (*ActivityFeed, error)
+}
+
+// activityCacheEntry 캐시된 피드와 만료 시각
+type activityCacheEntry struct {
+	feed      *ActivityFeed
+	expiresAt time.Time
+}
+
+// activityService 최근 파일 활동 피드 서비스 구현체
+type activityService struct {
+	fileRepo repository.FileRepository
+	clock    clock.Clock
+
+	mu    sync.Mutex
+	cache map[ActivityQuery]activityCacheEntry
+}
+
+// NewActivityService 새로운 최근 파일 활동 피드 서비스를 생성합니다
+func NewActivityService(fileRepo repository.FileRepository) ActivityService {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	return &activityService{
+		fileRepo: fileRepo,
+		clock:    clock.Real{},
+		cache:    make(map[ActivityQuery]activityCacheEntry),
+	}
+}
+
+// Recent 최근 파일 활동을 조회합니다
+//
+// 대시보드가 주기적으로 새로 고쳐도 DB를 매번 훑지 않도록 같은 조건의 결과를
+// ActivityFeedTTL 동안 재사용하므로, 직후의 변경은 최대 그만큼 늦게 보일 수 있습니다.
+func (s *activityService) Recent(ctx context.Context, query ActivityQuery) (*ActivityFeed, error) {
+	if query.Limit == 0 {
+		query.Limit = DefaultActivityLimit
+	}
+	if query.Offset < 0 || query.Limit < 0 || query.Limit > MaxActivityLimit {
+		return nil, fmt.Errorf("%w: offset %d, limit %d (1~%d)", ErrInvalidActivityPage, query.Offset, query.Limit, MaxActivityLimit)
+	}
+
+	now := s.clock.Now()
+	s.mu.Lock()
+	entry, ok := s.cache[query]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.feed, nil
+	}
+
+	activities, total, err := s.fileRepo.RecentActivity(ctx, query.OwnerID,
+		repository.Pagination{Offset: query.Offset, Limit: query.Limit})
+	if err != nil {
+		return nil, fmt.Errorf("활동 피드 조회 실패: %w", err)
+	}
+
+	events := make([]ActivityEvent, len(activities))
+	for i, activity := range activities {
+		events[i] = ActivityEvent{
+			Type:       activity.Type,
+			FileID:     activity.FileID,
+			FileName:   activity.FileName,
+			OccurredAt: activity.OccurredAt,
+			Actor:      activity.Actor,
+		}
+	}
+	feed := &ActivityFeed{Events: events, Total: total, Offset: query.Offset, Limit: query.Limit}
+
+	s.store(query, feed, now)
+	return feed, nil
+}
+
+// store 피드를 캐시에 넣습니다 (가득 차면 만료된 항목을, 그래도 가득 차면 전부 비움)
+func (s *activityService) store(query ActivityQuery, feed *ActivityFeed, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.cache) >= maxCachedActivityFeeds {
+		for key, entry := range s.cache {
+			if !now.Before(entry.expiresAt) {
+				delete(s.cache, key)
+			}
+		}
+		if len(s.cache) >= maxCachedActivityFeeds {
+			clear(s.cache)
+		}
+	}
+
+	s.cache[query] = activityCacheEntry{feed: feed, expiresAt: now.Add(ActivityFeedTTL)}
+}
+
+// Reset 캐시된 피드를 모두 비우고 비운 수를 반환합니다 (공장 초기화용)
+func (s *activityService) Reset() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.cache)
+	clear(s.cache)
+	return n
+}
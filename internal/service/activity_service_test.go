@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingActivityRepo RecentActivity 호출 수를 세는 파일 저장소
+type countingActivityRepo struct {
+	repository.FileRepository
+	calls int
+}
+
+func (r *countingActivityRepo) RecentActivity(ctx context.Context, ownerID uint, page repository.Pagination) ([]repository.FileActivity, int64, error) {
+	r.calls++
+	return r.FileRepository.RecentActivity(ctx, ownerID, page)
+}
+
+func TestActivityService_Recent(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := &countingActivityRepo{FileRepository: repository.NewFileRepository(setupServiceTestDB(t))}
+	svc := NewActivityService(fileRepo)
+
+	clk := clock.NewFake(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	svc.(*activityService).clock = clk
+
+	// 이벤트가 없어도 빈 배열과 기본 limit
+	feed, err := svc.Recent(ctx, ActivityQuery{})
+	require.NoError(t, err)
+	assert.NotNil(t, feed.Events)
+	assert.Empty(t, feed.Events)
+	assert.Zero(t, feed.Total)
+	assert.Equal(t, DefaultActivityLimit, feed.Limit)
+
+	for i := range 3 {
+		require.NoError(t, fileRepo.Create(model.WithActor(ctx, "uploader"), &model.File{
+			OriginalName:  fmt.Sprintf("report-%d.pdf", i),
+			EncryptedPath: fmt.Sprintf("/encrypted/report-%d.enc", i),
+			Size:          10,
+			MimeType:      "application/pdf",
+			ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+			Status:        model.FileStatusEncrypted,
+		}))
+	}
+
+	// TTL 동안은 같은 조건의 결과를 재사용
+	feed, err = svc.Recent(ctx, ActivityQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, feed.Events)
+	assert.Equal(t, 1, fileRepo.calls)
+
+	clk.Advance(ActivityFeedTTL)
+	feed, err = svc.Recent(ctx, ActivityQuery{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), feed.Total)
+	require.Len(t, feed.Events, 2)
+	assert.Equal(t, repository.ActivityUploaded, feed.Events[0].Type)
+	assert.Equal(t, "report-2.pdf", feed.Events[0].FileName)
+	assert.Equal(t, "uploader", feed.Events[0].Actor)
+
+	// 조건이 다르면 따로 조회
+	feed, err = svc.Recent(ctx, ActivityQuery{Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Len(t, feed.Events, 1)
+	feed, err = svc.Recent(ctx, ActivityQuery{OwnerID: 7})
+	require.NoError(t, err)
+	assert.Empty(t, feed.Events)
+	assert.Equal(t, 4, fileRepo.calls)
+
+	// TTL이 지나면 다시 조회
+	_, err = svc.Recent(ctx, ActivityQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 5, fileRepo.calls)
+
+	for _, query := range []ActivityQuery{{Limit: -1}, {Offset: -1}, {Limit: MaxActivityLimit + 1}} {
+		_, err = svc.Recent(ctx, query)
+		assert.ErrorIs(t, err, ErrInvalidActivityPage, "%+v", query)
+	}
+
+	assert.Panics(t, func() { NewActivityService(nil) })
+}
+
+func TestActivityService_CacheIsBounded(t *testing.T) {
+	svc := NewActivityService(repository.NewFileRepository(setupServiceTestDB(t))).(*activityService)
+	now := time.Now()
+
+	for i := range maxCachedActivityFeeds + 10 {
+		svc.store(ActivityQuery{Offset: i, Limit: 1}, &ActivityFeed{}, now)
+	}
+	assert.LessOrEqual(t, len(svc.cache), maxCachedActivityFeeds)
+
+	// 만료된 항목이 있으면 그것만 비움
+	later := now.Add(ActivityFeedTTL)
+	for i := len(svc.cache); i < maxCachedActivityFeeds; i++ {
+		svc.store(ActivityQuery{Offset: -i - 1, Limit: 1}, &ActivityFeed{}, later)
+	}
+	svc.store(ActivityQuery{Limit: 2}, &ActivityFeed{}, later)
+	assert.Less(t, len(svc.cache), maxCachedActivityFeeds)
+	_, kept := svc.cache[ActivityQuery{Offset: -maxCachedActivityFeeds, Limit: 1}]
+	assert.True(t, kept, "만료되지 않은 항목은 유지")
+}
@@ -0,0 +1,541 @@
+// Package service provides business logic for DataLocker.
+// This file implements administrative operations used by the remote admin API.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/datatypes"
+)
+
+// 관리 서비스 에러
+var (
+	ErrAdminFileNotFound  = errors.New("파일을 찾을 수 없습니다")
+	ErrFileInUse          = errors.New("다른 레코드가 참조 중인 blob은 영구 삭제할 수 없습니다")
+	ErrFileBusy           = errors.New("다른 작업이 이 파일을 처리 중입니다")
+	ErrFileNotDeleted     = errors.New("삭제되지 않은 파일은 복구할 수 없습니다")
+	ErrInvalidFileDetails = errors.New("파일 설명이나 사용자 메타데이터가 올바르지 않습니다")
+)
+
+// missingMetadataReason 메타데이터를 복원하지 못한 파일에 기록하는 손상 사유
+const missingMetadataReason = "암호화 메타데이터가 없고 스트림 헤더로 복원할 수 없습니다"
+
+// MetadataCheckResult 암호화 메타데이터 점검 결과
+type MetadataCheckResult struct {
+	Rebuilt   []uint `json:"rebuilt_file_ids"`   // 스트림 헤더로 메타데이터를 다시 만든 파일
+	Corrupted []uint `json:"corrupted_file_ids"` // 복원하지 못해 corrupted로 표시한 파일
+	Skipped   []uint `json:"skipped_file_ids"`   // 볼륨 오프라인 또는 소프트 삭제로 건너뛴 파일
+}
+
+// AdminFileList 관리용 파일 목록
+type AdminFileList struct {
+	Files    []*model.File `json:"files"`
+	Total    int64         `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+}
+
+// AdminFileCursorPage 커서로 조회한 관리용 파일 목록 (NextCursor가 비어 있으면 마지막 페이지)
+type AdminFileCursorPage struct {
+	Files      []*model.File `json:"files"`
+	NextCursor string        `json:"next_cursor"`
+	PageSize   int           `json:"page_size"`
+}
+
+// FileDetailsUpdate 파일 설명/사용자 메타데이터 수정 요청 (보내지 않은 필드는 그대로 둠)
+type FileDetailsUpdate struct {
+	Description    *string        `json:"description"`     // 빈 문자열이면 설명을 지움
+	CustomMetadata datatypes.JSON `json:"custom_metadata"` // JSON 객체로 통째로 바꾸고, null이면 지움
+}
+
+// AdminService 원격 관리 작업(목록/검증/삭제/복구/영구 삭제/볼륨 관리) 서비스
+//
+// 파일 삭제는 이 서비스로 일원화합니다. DeleteFile은 레코드만 소프트 삭제하고
+// 암호화 메타데이터, 키 슬롯, 암호화본을 모두 보존하므로 RestoreFile로 복구하면
+// 그대로 복호화할 수 있습니다. 메타데이터와 암호화본을 지우는 것은 PurgeFile뿐입니다.
+type AdminService interface {
+	// ListFiles filter에 맞는 파일 목록을 sort 순서(빈 값은 최신순)로 조회합니다
+	//
+	// 허용하지 않는 정렬 조건이면 repository.ErrInvalidSortOption을, 모순된 필터면
+	// repository.ErrInvalidFileFilter를 감싼 에러를 반환합니다.
+	ListFiles(ctx context.Context, filter repository.FileFilter, page, pageSize int, sort repository.SortOption) (*AdminFileList, error)
+
+	// ListFilesAfter cursor 다음 파일들을 최신순으로 조회합니다 (빈 cursor는 첫 페이지)
+	//
+	// 해석할 수 없는 커서면 repository.ErrInvalidCursor를 감싼 에러를 반환합니다.
+	ListFilesAfter(ctx context.Context, cursor string, pageSize int) (*AdminFileCursorPage, error)
+
+	// GetFile 파일 하나를 조회합니다 (소프트 삭제된 파일 포함, 없으면 ErrAdminFileNotFound)
+	GetFile(ctx context.Context, fileID uint) (*model.File, error)
+
+	// VerifyFile 파일의 암호화 blob 무결성을 검사합니다
+	VerifyFile(ctx context.Context, fileID uint, password string) (*IntegrityResult, error)
+
+	// UpdateFileDetails 파일 설명과 사용자 메타데이터를 수정하고 수정된 레코드를 반환합니다
+	//
+	// 값이 제한을 넘거나 메타데이터가 JSON 객체가 아니면 ErrInvalidFileDetails를, 소프트
+	// 삭제된 파일이면 ErrAdminFileNotFound를 반환합니다. 다른 요청이 먼저 수정했으면
+	// model.ErrStaleRecord를 감싼 에러를 반환합니다.
+	UpdateFileDetails(ctx context.Context, fileID uint, update FileDetailsUpdate) (*model.File, error)
+
+	// DeleteFile 파일을 소프트 삭제합니다 (메타데이터와 암호화본은 보존)
+	DeleteFile(ctx context.Context, fileID uint) error
+
+	// RestoreFile 소프트 삭제된 파일을 복구하고 복구된 레코드를 반환합니다
+	//
+	// 삭제되지 않은 파일은 ErrFileNotDeleted로 거부합니다. 암호화 메타데이터가
+	// 없으면 스트림 헤더로 다시 만들고, 그마저 불가능하면 corrupted로 표시해 반환합니다.
+	RestoreFile(ctx context.Context, fileID uint) (*model.File, error)
+
+	// PurgeFile 파일 레코드와 디스크의 암호화 파일을 영구 삭제합니다 (소프트 삭제된 파일 포함)
+	//
+	// 다른 레코드가 참조 중인 blob은 ErrFileInUse로, 암호화본의 볼륨이 오프라인이면
+	// ErrVolumeOffline으로 거부합니다. 같은 파일의 다른 작업(볼륨 이동 등)이 락을
+	// 놓지 않으면 ErrFileBusy를 반환합니다.
+	PurgeFile(ctx context.Context, fileID uint) error
+
+	// CheckMetadata 암호화 메타데이터가 없는 encrypted 파일을 점검합니다
+	//
+	// 스트림 헤더로 메타데이터를 복원할 수 있으면 다시 만들고, 불가능한 파일은
+	// corrupted로 표시합니다. 소프트 삭제된 파일은 복구할 때 같은 점검을 거치므로
+	// 복원만 시도합니다.
+	CheckMetadata(ctx context.Context) (*MetadataCheckResult, error)
+
+	// VolumeStats 저장소 볼륨별 사용량을 조회합니다
+	VolumeStats(ctx context.Context) ([]VolumeStats, error)
+
+	// RebalanceVolumes 볼륨 간 사용량이 가중치 비율에 맞도록 파일을 옮깁니다
+	RebalanceVolumes(ctx context.Context, dryRun bool, maxFiles int) (*RebalanceResult, error)
+}
+
+// adminService 관리 서비스 구현체
+type adminService struct {
+	fileRepo  repository.FileRepository
+	txManager repository.TxManager
+	storage   StorageService
+	integrity IntegrityService
+	locker    repository.FileLocker
+	logger    *logrus.Logger
+}
+
+// NewAdminService 새로운 관리 서비스를 생성합니다
+func NewAdminService(
+	fileRepo repository.FileRepository,
+	txManager repository.TxManager,
+	storage StorageService,
+	integrity IntegrityService,
+	locker repository.FileLocker,
+	logger *logrus.Logger,
+) AdminService {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	if txManager == nil {
+		panic("트랜잭션 관리자가 필요합니다")
+	}
+
+	if storage == nil {
+		panic("저장소 볼륨 서비스가 필요합니다")
+	}
+
+	if integrity == nil {
+		panic("무결성 검사 서비스가 필요합니다")
+	}
+
+	if locker == nil {
+		panic("파일 락이 필요합니다")
+	}
+
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	return &adminService{
+		fileRepo:  fileRepo,
+		txManager: txManager,
+		storage:   storage,
+		integrity: integrity,
+		locker:    locker,
+		logger:    logger,
+	}
+}
+
+// ListFiles 파일 목록을 페이지 단위로 조회합니다
+func (s *adminService) ListFiles(ctx context.Context, filter repository.FileFilter, page, pageSize int, sort repository.SortOption) (*AdminFileList, error) {
+	page, pageSize = normalizeSearchPage(page, pageSize)
+
+	files, total, err := s.fileRepo.Find(ctx, filter, repository.Pagination{Offset: (page - 1) * pageSize, Limit: pageSize}, sort)
+	if err != nil {
+		return nil, fmt.Errorf("파일 목록 조회 실패: %w", err)
+	}
+
+	return &AdminFileList{
+		Files:    files,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// ListFilesAfter 커서를 해석해 다음 페이지를 조회하고 다음 커서를 문자열로 돌려줍니다
+func (s *adminService) ListFilesAfter(ctx context.Context, cursor string, pageSize int) (*AdminFileCursorPage, error) {
+	_, pageSize = normalizeSearchPage(1, pageSize)
+
+	after, err := repository.ParseCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	files, next, err := s.fileRepo.GetAfter(ctx, after, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("파일 목록 조회 실패: %w", err)
+	}
+
+	return &AdminFileCursorPage{
+		Files:      files,
+		NextCursor: next.String(),
+		PageSize:   pageSize,
+	}, nil
+}
+
+// GetFile 휴지통에 있는 파일도 확인할 수 있도록 소프트 삭제된 레코드를 포함해 조회합니다
+func (s *adminService) GetFile(ctx context.Context, fileID uint) (*model.File, error) {
+	return s.getWithDeleted(ctx, fileID)
+}
+
+// VerifyFile 파일 존재를 확인한 뒤 무결성 검사를 위임합니다
+func (s *adminService) VerifyFile(ctx context.Context, fileID uint, password string) (*IntegrityResult, error) {
+	if err := s.ensureExists(ctx, fileID); err != nil {
+		return nil, err
+	}
+
+	return s.integrity.VerifyFile(ctx, fileID, password)
+}
+
+// UpdateFileDetails 요청한 필드를 검증한 뒤 파일 레코드에 반영합니다
+func (s *adminService) UpdateFileDetails(ctx context.Context, fileID uint, update FileDetailsUpdate) (*model.File, error) {
+	if update.Description != nil {
+		if err := model.ValidateDescription(*update.Description); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFileDetails, err)
+		}
+	}
+
+	// 보내지 않은 필드(nil)와 null(지움)을 구분하기 위해 정규화 전에 확인
+	replaceMetadata := update.CustomMetadata != nil
+	metadata := model.NormalizeCustomMetadata(update.CustomMetadata)
+	if err := model.ValidateCustomMetadata(metadata); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFileDetails, err)
+	}
+
+	file, err := s.fileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: ID %d", ErrAdminFileNotFound, fileID)
+		}
+		return nil, fmt.Errorf("파일 조회 실패: %w", err)
+	}
+
+	if update.Description != nil {
+		file.Description = *update.Description
+	}
+	if replaceMetadata {
+		file.CustomMetadata = metadata
+	}
+
+	if err := s.fileRepo.Update(ctx, file); err != nil {
+		return nil, fmt.Errorf("파일 정보 수정 실패: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"file_id": fileID,
+		"version": file.Version,
+	}).Info("파일 설명/메타데이터를 수정했습니다")
+	return file, nil
+}
+
+// DeleteFile 레코드만 소프트 삭제합니다
+func (s *adminService) DeleteFile(ctx context.Context, fileID uint) error {
+	if err := s.ensureExists(ctx, fileID); err != nil {
+		return err
+	}
+
+	if err := s.fileRepo.Delete(ctx, fileID); err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return fmt.Errorf("%w: ID %d", ErrAdminFileNotFound, fileID)
+		}
+		return fmt.Errorf("파일 삭제 실패: %w", err)
+	}
+
+	s.logger.WithField("file_id", fileID).Info("파일을 삭제했습니다 (복구 가능)")
+	return nil
+}
+
+// RestoreFile 소프트 삭제를 되돌리고 누락된 메타데이터를 함께 복원합니다
+func (s *adminService) RestoreFile(ctx context.Context, fileID uint) (*model.File, error) {
+	file, err := s.getWithDeleted(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !file.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w: ID %d", ErrFileNotDeleted, fileID)
+	}
+
+	// 헤더 읽기는 트랜잭션 밖에서 미리 수행
+	var metadata *model.EncryptionMetadata
+	missing := needsMetadata(file)
+	if missing {
+		if metadata, err = s.recoverMetadata(file); err != nil {
+			return nil, err
+		}
+	}
+
+	// 복구와 메타데이터 복원/손상 표시를 함께 커밋 (요청이 끊겨도 중간에 멈추지 않도록
+	// 트랜잭션 안의 저장소 호출에도 취소되지 않는 context를 전달)
+	ctx = context.WithoutCancel(ctx)
+	var restored *model.File
+	err = s.txManager.WithinTransaction(ctx, func(repos repository.Repositories) error {
+		if err := repos.Files.Restore(ctx, fileID); err != nil {
+			return err
+		}
+
+		if metadata != nil {
+			if err := repos.Encryption.Create(ctx, metadata); err != nil {
+				return err
+			}
+		}
+
+		if restored, err = repos.Files.GetByID(ctx, fileID); err != nil {
+			return err
+		}
+
+		if missing && metadata == nil {
+			restored.MarkAsCorrupted()
+			restored.FailureReason = missingMetadataReason
+			return repos.Files.Update(ctx, restored)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("파일 복구 실패: %w", err)
+	}
+
+	entry := s.logger.WithField("file_id", fileID)
+	switch {
+	case metadata != nil:
+		entry.Warn("삭제된 파일을 복구하고 누락된 암호화 메타데이터를 스트림 헤더로 복원했습니다")
+	case missing:
+		entry.Warn("복구한 파일의 암호화 메타데이터를 복원할 수 없어 손상으로 표시했습니다")
+	default:
+		entry.Info("삭제된 파일을 복구했습니다")
+	}
+	return restored, nil
+}
+
+// PurgeFile 레코드를 영구 삭제하고 자신이 소유한 암호화 파일을 지웁니다
+func (s *adminService) PurgeFile(ctx context.Context, fileID uint) error {
+	if fileID == 0 {
+		return fmt.Errorf("%w: ID %d", ErrAdminFileNotFound, fileID)
+	}
+
+	// 볼륨 이동 등 같은 파일의 다른 작업이 끝난 뒤의 레코드로 판단
+	unlock, err := lockFile(ctx, s.locker, fileID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	file, err := s.getWithDeleted(ctx, fileID)
+	if err != nil {
+		return err
+	}
+
+	// 참조 레코드는 blob을 소유하지 않으므로 레코드만 삭제
+	var path string
+	if !file.IsBlobReference() {
+		refs, err := s.fileRepo.CountBlobReferences(ctx, file.ID)
+		if err != nil {
+			return fmt.Errorf("blob 참조 확인 실패: %w", err)
+		}
+		if refs > 0 {
+			return fmt.Errorf("%w: 참조 %d개", ErrFileInUse, refs)
+		}
+
+		// 볼륨이 오프라인이면 암호화본이 고아로 남으므로 레코드도 지우지 않음
+		if path, err = s.storage.Locate(file); err != nil {
+			return err
+		}
+	}
+
+	if err := s.fileRepo.Purge(ctx, file.ID); err != nil {
+		return fmt.Errorf("파일 영구 삭제 실패: %w", err)
+	}
+
+	entry := s.logger.WithFields(logrus.Fields{
+		"file_id":       file.ID,
+		"original_name": file.OriginalName,
+	})
+
+	// 레코드가 사라진 뒤에는 파일이 남아도 참조되지 않으므로 경고만 남김
+	if !file.IsBlobReference() {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			entry.WithError(err).WithField("path", path).
+				Warn("영구 삭제한 파일의 암호화본을 지우지 못했습니다 (수동 정리 필요)")
+			return nil
+		}
+	}
+
+	entry.Info("파일을 영구 삭제했습니다")
+	return nil
+}
+
+// CheckMetadata 메타데이터가 사라진 파일을 복원하거나 손상으로 표시합니다
+func (s *adminService) CheckMetadata(ctx context.Context) (*MetadataCheckResult, error) {
+	files, err := s.fileRepo.ListMissingMetadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("메타데이터 점검 실패: %w", err)
+	}
+
+	result := &MetadataCheckResult{
+		Rebuilt:   []uint{},
+		Corrupted: []uint{},
+		Skipped:   []uint{},
+	}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		entry := s.logger.WithField("file_id", file.ID)
+
+		metadata, err := s.recoverMetadata(file)
+		if err != nil {
+			entry.WithError(err).Warn("암호화 메타데이터 점검을 건너뜁니다")
+			result.Skipped = append(result.Skipped, file.ID)
+			continue
+		}
+
+		switch {
+		case metadata != nil:
+			err = s.txManager.WithinTransaction(ctx, func(repos repository.Repositories) error {
+				return repos.Encryption.Create(ctx, metadata)
+			})
+			if err == nil {
+				result.Rebuilt = append(result.Rebuilt, file.ID)
+			}
+		case file.DeletedAt.Valid:
+			// 삭제된 파일은 복구할 때 손상으로 표시
+			result.Skipped = append(result.Skipped, file.ID)
+		default:
+			file.MarkAsCorrupted()
+			file.FailureReason = missingMetadataReason
+			err = s.txManager.WithinTransaction(ctx, func(repos repository.Repositories) error {
+				return repos.Files.Update(ctx, file)
+			})
+			if err == nil {
+				result.Corrupted = append(result.Corrupted, file.ID)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("파일 %d 메타데이터 점검 실패: %w", file.ID, err)
+		}
+	}
+
+	if len(result.Rebuilt) > 0 || len(result.Corrupted) > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"rebuilt":   result.Rebuilt,
+			"corrupted": result.Corrupted,
+		}).Warn("암호화 메타데이터가 없는 파일을 점검했습니다")
+	}
+	return result, nil
+}
+
+// VolumeStats 저장소 볼륨별 사용량을 조회합니다
+func (s *adminService) VolumeStats(ctx context.Context) ([]VolumeStats, error) {
+	return s.storage.Stats(ctx)
+}
+
+// RebalanceVolumes 볼륨 재배치를 저장소 볼륨 서비스에 위임합니다
+func (s *adminService) RebalanceVolumes(ctx context.Context, dryRun bool, maxFiles int) (*RebalanceResult, error) {
+	return s.storage.Rebalance(ctx, dryRun, maxFiles)
+}
+
+// recoverMetadata 암호화본의 스트림 헤더로 primary 메타데이터를 다시 만듭니다
+//
+// 헤더를 읽을 수 없으면(레거시 형식, 손상, 파일 없음) nil을 반환하고, 볼륨이
+// 오프라인이면 판단할 수 없으므로 에러를 반환합니다.
+func (s *adminService) recoverMetadata(file *model.File) (*model.EncryptionMetadata, error) {
+	path, err := s.storage.Locate(file)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := readStreamMetadata(path)
+	if err != nil {
+		s.logger.WithError(err).WithField("file_id", file.ID).Debug("스트림 헤더로 메타데이터를 복원할 수 없습니다")
+		return nil, nil //nolint:nilnil // 복원 불가는 에러가 아님
+	}
+
+	metadata.FileID = file.ID
+	return metadata, nil
+}
+
+// needsMetadata 자신의 primary 메타데이터가 있어야 하는데 없는 파일인지 확인합니다
+func needsMetadata(file *model.File) bool {
+	return file.IsEncrypted() && !file.IsBlobReference() && file.EncryptionMetadata == nil
+}
+
+// getWithDeleted 소프트 삭제된 레코드를 포함해 파일을 조회합니다 (없으면 ErrAdminFileNotFound)
+func (s *adminService) getWithDeleted(ctx context.Context, fileID uint) (*model.File, error) {
+	if fileID == 0 {
+		return nil, fmt.Errorf("%w: ID %d", ErrAdminFileNotFound, fileID)
+	}
+
+	file, err := s.fileRepo.GetByIDWithDeleted(ctx, fileID)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: ID %d", ErrAdminFileNotFound, fileID)
+		}
+		return nil, fmt.Errorf("파일 조회 실패: %w", err)
+	}
+
+	return file, nil
+}
+
+// ensureExists 파일이 없으면 ErrAdminFileNotFound를 반환합니다
+func (s *adminService) ensureExists(ctx context.Context, fileID uint) error {
+	if fileID == 0 {
+		return fmt.Errorf("%w: ID %d", ErrAdminFileNotFound, fileID)
+	}
+
+	exists, err := s.fileRepo.Exists(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("파일 존재 확인 실패: %w", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%w: ID %d", ErrAdminFileNotFound, fileID)
+	}
+
+	return nil
+}
+
+// lockFile 파일 락을 얻고, 대기 시간을 넘기면 ErrFileBusy를 감싸 반환합니다
+func lockFile(ctx context.Context, locker repository.FileLocker, fileID uint) (repository.UnlockFunc, error) {
+	unlock, err := locker.Lock(ctx, fileID)
+	switch {
+	case errors.Is(err, repository.ErrLockTimeout):
+		return nil, fmt.Errorf("%w: %w", ErrFileBusy, err)
+	case err != nil:
+		return nil, fmt.Errorf("파일 락 획득 실패: %w", err)
+	}
+	return unlock, nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// setupAdminTest 관리 서비스와 미리보기 서비스, 메타데이터가 없는 암호화 파일을 준비합니다
+func setupAdminTest(t *testing.T) (AdminService, PreviewService, repository.FileRepository, *model.File) {
+	t.Helper()
+	db := setupServiceTestDB(t)
+	fileRepo := repository.NewFileRepository(db)
+	file := createIntegrityTarget(t, fileRepo)
+
+	storage := newDirStorage(t, filepath.Dir(file.EncryptedPath), fileRepo)
+	integrity := NewIntegrityService(fileRepo, storage, newSilentLogger())
+	admin := NewAdminService(fileRepo, repository.NewTxManager(db), storage, integrity, repository.NewMemoryFileLocker(0), newSilentLogger())
+	return admin, NewPreviewService(fileRepo, storage), fileRepo, file
+}
+
+func TestAdminService_ListFiles(t *testing.T) {
+	svc, _, _, file := setupAdminTest(t)
+
+	list, err := svc.ListFiles(context.Background(), repository.FileFilter{}, 0, 0, repository.SortOption{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), list.Total)
+	assert.Equal(t, 1, list.Page)
+	require.Len(t, list.Files, 1)
+	assert.Equal(t, file.ID, list.Files[0].ID)
+
+	list, err = svc.ListFiles(context.Background(), repository.FileFilter{MinSize: file.Size + 1}, 0, 0, repository.SortOption{})
+	require.NoError(t, err)
+	assert.Zero(t, list.Total)
+	assert.Empty(t, list.Files)
+
+	_, err = svc.ListFiles(context.Background(), repository.FileFilter{}, 0, 0, repository.SortOption{Field: "encrypted_path"})
+	assert.ErrorIs(t, err, repository.ErrInvalidSortOption)
+
+	_, err = svc.ListFiles(context.Background(), repository.FileFilter{MinSize: 10, MaxSize: 5}, 0, 0, repository.SortOption{})
+	assert.ErrorIs(t, err, repository.ErrInvalidFileFilter)
+}
+
+func TestAdminService_ListFilesAfter(t *testing.T) {
+	ctx := context.Background()
+	svc, _, fileRepo, file := setupAdminTest(t)
+
+	second := &model.File{
+		OriginalName:  "second.txt",
+		EncryptedPath: file.EncryptedPath + ".second",
+		Size:          1,
+		MimeType:      "text/plain",
+		ChecksumMD5:   file.ChecksumMD5,
+		Status:        model.FileStatusPending,
+		CreatedAt:     file.CreatedAt.Add(time.Second),
+	}
+	require.NoError(t, fileRepo.Create(ctx, second))
+
+	page, err := svc.ListFilesAfter(context.Background(), "", 1)
+	require.NoError(t, err)
+	require.Len(t, page.Files, 1)
+	assert.Equal(t, second.ID, page.Files[0].ID)
+	assert.Equal(t, 1, page.PageSize)
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = svc.ListFilesAfter(context.Background(), page.NextCursor, 1)
+	require.NoError(t, err)
+	require.Len(t, page.Files, 1)
+	assert.Equal(t, file.ID, page.Files[0].ID)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = svc.ListFilesAfter(context.Background(), "not-a-cursor", 1)
+	assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+}
+
+func TestAdminService_VerifyFile(t *testing.T) {
+	svc, _, _, file := setupAdminTest(t)
+
+	result, err := svc.VerifyFile(context.Background(), file.ID, integrityTestPassword)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	_, err = svc.VerifyFile(context.Background(), file.ID+100, integrityTestPassword)
+	assert.ErrorIs(t, err, ErrAdminFileNotFound)
+}
+
+func TestAdminService_PurgeFile(t *testing.T) {
+	ctx := context.Background()
+	svc, _, fileRepo, file := setupAdminTest(t)
+
+	// 참조 레코드가 있으면 blob 삭제 거부
+	ref := &model.File{
+		OriginalName:  "ref.txt",
+		EncryptedPath: blobReferencePathPrefix + "1/ref",
+		Size:          file.Size,
+		MimeType:      file.MimeType,
+		ChecksumMD5:   file.ChecksumMD5,
+		Status:        model.FileStatusEncrypted,
+		BlobFileID:    &file.ID,
+	}
+	require.NoError(t, fileRepo.Create(ctx, ref))
+
+	err := svc.PurgeFile(context.Background(), file.ID)
+	assert.ErrorIs(t, err, ErrFileInUse)
+	assert.FileExists(t, file.EncryptedPath)
+
+	// 참조 레코드는 blob을 건드리지 않고 삭제
+	require.NoError(t, svc.PurgeFile(context.Background(), ref.ID))
+	assert.FileExists(t, file.EncryptedPath)
+
+	// 참조가 없어지면 레코드와 암호화 파일 모두 삭제
+	require.NoError(t, svc.PurgeFile(context.Background(), file.ID))
+	_, err = os.Stat(file.EncryptedPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	exists, err := fileRepo.Exists(ctx, file.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	err = svc.PurgeFile(context.Background(), file.ID)
+	assert.ErrorIs(t, err, ErrAdminFileNotFound)
+}
+
+func TestAdminService_PurgeFileLocked(t *testing.T) {
+	ctx := context.Background()
+	db := setupServiceTestDB(t)
+	fileRepo := repository.NewFileRepository(db)
+	file := createIntegrityTarget(t, fileRepo)
+
+	storage := newDirStorage(t, filepath.Dir(file.EncryptedPath), fileRepo)
+	locker := repository.NewMemoryFileLocker(50 * time.Millisecond)
+	svc := NewAdminService(fileRepo, repository.NewTxManager(db), storage,
+		NewIntegrityService(fileRepo, storage, newSilentLogger()), locker, newSilentLogger())
+
+	// 다른 작업이 락을 가진 동안에는 영구 삭제하지 않음
+	unlock, err := locker.Lock(ctx, file.ID)
+	require.NoError(t, err)
+
+	err = svc.PurgeFile(ctx, file.ID)
+	assert.ErrorIs(t, err, ErrFileBusy)
+	assert.ErrorIs(t, err, repository.ErrLockTimeout)
+	assert.FileExists(t, file.EncryptedPath)
+
+	unlock()
+	require.NoError(t, svc.PurgeFile(ctx, file.ID))
+	assert.NoFileExists(t, file.EncryptedPath)
+}
+
+func TestAdminService_PurgeDeletedFile(t *testing.T) {
+	ctx := context.Background()
+	svc, _, fileRepo, file := setupAdminTest(t)
+
+	require.NoError(t, svc.DeleteFile(context.Background(), file.ID))
+	require.NoError(t, svc.PurgeFile(context.Background(), file.ID))
+
+	_, err := fileRepo.GetByIDWithDeleted(ctx, file.ID)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, err = os.Stat(file.EncryptedPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestAdminService_DeleteRestoreRoundTrip(t *testing.T) {
+	svc, preview, fileRepo, file := setupAdminTest(t)
+	ctx := context.Background()
+
+	// 점검으로 메타데이터를 스트림 헤더에서 다시 만듦
+	result, err := svc.CheckMetadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{file.ID}, result.Rebuilt)
+
+	before, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	require.NotNil(t, before.EncryptionMetadata)
+
+	_, err = svc.RestoreFile(ctx, file.ID)
+	assert.ErrorIs(t, err, ErrFileNotDeleted)
+
+	require.NoError(t, svc.DeleteFile(ctx, file.ID))
+	_, err = preview.Preview(ctx, file.ID, integrityTestPassword, false)
+	require.Error(t, err)
+	assert.ErrorIs(t, svc.DeleteFile(ctx, file.ID), ErrAdminFileNotFound)
+
+	// 삭제 후에도 메타데이터와 암호화본은 보존
+	deleted, err := fileRepo.GetByIDWithDeleted(ctx, file.ID)
+	require.NoError(t, err)
+	require.NotNil(t, deleted.EncryptionMetadata)
+	assert.Equal(t, before.EncryptionMetadata.SaltHex, deleted.EncryptionMetadata.SaltHex)
+	assert.FileExists(t, file.EncryptedPath)
+
+	restored, err := svc.RestoreFile(ctx, file.ID)
+	require.NoError(t, err)
+	assert.True(t, restored.IsEncrypted())
+
+	text, err := preview.Preview(ctx, file.ID, integrityTestPassword, false)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("integrity scan target"), text.Content)
+
+	_, err = svc.RestoreFile(ctx, file.ID+100)
+	assert.ErrorIs(t, err, ErrAdminFileNotFound)
+}
+
+func TestAdminService_UpdateFileDetails(t *testing.T) {
+	svc, _, fileRepo, file := setupAdminTest(t)
+	ctx := context.Background()
+
+	description := "분기 보고서"
+	updated, err := svc.UpdateFileDetails(ctx, file.ID, FileDetailsUpdate{
+		Description:    &description,
+		CustomMetadata: datatypes.JSON(`{"team":"finance","tags":["q3"]}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, description, updated.Description)
+
+	// 보내지 않은 필드는 그대로
+	updated, err = svc.UpdateFileDetails(ctx, file.ID, FileDetailsUpdate{CustomMetadata: datatypes.JSON(`{"team":"legal"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, description, updated.Description)
+
+	stored, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"team":"legal"}`, string(stored.CustomMetadata), "메타데이터는 통째로 바뀜")
+
+	// null은 메타데이터 삭제
+	_, err = svc.UpdateFileDetails(ctx, file.ID, FileDetailsUpdate{CustomMetadata: datatypes.JSON("null")})
+	require.NoError(t, err)
+	stored, err = fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.CustomMetadata)
+	assert.Equal(t, description, stored.Description)
+
+	for _, invalid := range []string{`{"team":`, `"finance"`, `{"big":"` + strings.Repeat("x", model.MaxCustomMetadataSize) + `"}`} {
+		_, err = svc.UpdateFileDetails(ctx, file.ID, FileDetailsUpdate{CustomMetadata: datatypes.JSON(invalid)})
+		assert.ErrorIs(t, err, ErrInvalidFileDetails)
+	}
+
+	long := strings.Repeat("가", model.MaxDescriptionLength+1)
+	_, err = svc.UpdateFileDetails(ctx, file.ID, FileDetailsUpdate{Description: &long})
+	assert.ErrorIs(t, err, ErrInvalidFileDetails)
+
+	require.NoError(t, svc.DeleteFile(ctx, file.ID))
+	_, err = svc.UpdateFileDetails(ctx, file.ID, FileDetailsUpdate{Description: &description})
+	assert.ErrorIs(t, err, ErrAdminFileNotFound)
+}
+
+func TestAdminService_RestoreRebuildsMissingMetadata(t *testing.T) {
+	svc, preview, fileRepo, file := setupAdminTest(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.DeleteFile(ctx, file.ID))
+
+	restored, err := svc.RestoreFile(ctx, file.ID)
+	require.NoError(t, err)
+	assert.True(t, restored.IsEncrypted())
+
+	reloaded, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	require.NotNil(t, reloaded.EncryptionMetadata)
+	assert.Equal(t, model.MetadataPurposePrimary, reloaded.EncryptionMetadata.Purpose)
+
+	_, err = preview.Preview(ctx, file.ID, integrityTestPassword, false)
+	require.NoError(t, err)
+}
+
+func TestAdminService_UnrecoverableMetadataMarksCorrupted(t *testing.T) {
+	svc, _, fileRepo, file := setupAdminTest(t)
+	ctx := context.Background()
+
+	// 헤더가 없는 암호화본은 메타데이터를 복원할 수 없음
+	require.NoError(t, os.WriteFile(file.EncryptedPath, []byte("not a stream"), 0o600))
+
+	other := createIntegrityTarget(t, fileRepo)
+	require.NoError(t, os.WriteFile(other.EncryptedPath, []byte("not a stream"), 0o600))
+	require.NoError(t, svc.DeleteFile(ctx, other.ID))
+
+	// 살아 있는 파일만 손상으로 표시하고 삭제된 파일은 복구 시점으로 미룸
+	result, err := svc.CheckMetadata(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.Rebuilt)
+	assert.Equal(t, []uint{file.ID}, result.Corrupted)
+	assert.Equal(t, []uint{other.ID}, result.Skipped)
+
+	corrupted, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStatusCorrupted, corrupted.Status)
+	assert.Equal(t, missingMetadataReason, corrupted.FailureReason)
+
+	restored, err := svc.RestoreFile(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStatusCorrupted, restored.Status)
+
+	// 손상으로 표시된 파일은 다시 점검 대상이 아님
+	result, err = svc.CheckMetadata(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.Corrupted)
+	assert.Empty(t, result.Skipped)
+}
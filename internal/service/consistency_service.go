@@ -0,0 +1,198 @@
+// Package service provides business logic for DataLocker.
+// This file implements consistency checks between file records and the encrypted files on disk.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrCursorStalled 커서로 목록을 읽는 중 다음 페이지로 진행하지 못함
+//
+// 저장된 정렬 키가 어긋나(예: 시각 오프셋이 섞임) 같은 페이지가 반복될 때 끝없이
+// 돌지 않고 중단합니다.
+var ErrCursorStalled = errors.New("파일 목록 커서가 진행하지 않습니다")
+
+// SizeMismatch 레코드의 암호화본 크기와 디스크 크기가 다른 파일
+type SizeMismatch struct {
+	FileID       uint  `json:"file_id"`
+	RecordedSize int64 `json:"recorded_size"` // 레코드의 EncryptedSize (기록 도입 전 파일은 0)
+	DiskSize     int64 `json:"disk_size"`
+	Fixed        bool  `json:"fixed"`
+}
+
+// SizeReconcileResult 암호화본 크기 점검 결과
+type SizeReconcileResult struct {
+	Fix        bool           `json:"fix"`
+	Checked    int            `json:"checked"`    // 디스크 크기를 확인한 파일 수
+	Mismatches []SizeMismatch `json:"mismatches"` // 크기가 다른 파일 (fix이면 교정 여부 포함)
+	Missing    []uint         `json:"missing"`    // 암호화본이 없는 파일 (교정하지 않음)
+	Skipped    []uint         `json:"skipped"`    // 볼륨 오프라인, 동시 수정 등으로 판단하거나 교정하지 못한 파일
+}
+
+// ConsistencyService 파일 레코드와 디스크의 암호화본이 일치하는지 점검하는 서비스
+type ConsistencyService interface {
+	// ReconcileSizes 암호화된 파일의 EncryptedSize를 디스크의 실제 크기와 비교합니다
+	//
+	// fix이면 디스크 기준으로 EncryptedSize만 교정하고 원본 크기(Size)는 바꾸지
+	// 않습니다. 파일을 배치 단위로 읽으며 배치마다 진행률을 로그로 남기고, 교정한
+	// 파일은 감사 로그에 기록합니다. 중복 제거 참조는 암호화본을 갖지 않으므로 제외합니다.
+	ReconcileSizes(ctx context.Context, fix bool) (*SizeReconcileResult, error)
+}
+
+// consistencyService 정합성 점검 서비스 구현체
+type consistencyService struct {
+	fileRepo  repository.FileRepository
+	storage   StorageService
+	logger    *logrus.Logger
+	batchSize int
+}
+
+// NewConsistencyService 새로운 정합성 점검 서비스를 생성합니다
+func NewConsistencyService(fileRepo repository.FileRepository, storage StorageService, logger *logrus.Logger) ConsistencyService {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	if storage == nil {
+		panic("저장소 볼륨 서비스가 필요합니다")
+	}
+
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	return &consistencyService{
+		fileRepo:  fileRepo,
+		storage:   storage,
+		logger:    logger,
+		batchSize: repository.MaxPageSize,
+	}
+}
+
+// ReconcileSizes 암호화본 크기를 점검하고 fix이면 교정합니다
+func (s *consistencyService) ReconcileSizes(ctx context.Context, fix bool) (*SizeReconcileResult, error) {
+	total, err := s.fileRepo.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("파일 수 조회 실패: %w", err)
+	}
+
+	result := &SizeReconcileResult{
+		Fix:        fix,
+		Mismatches: []SizeMismatch{},
+		Missing:    []uint{},
+		Skipped:    []uint{},
+	}
+
+	// 교정해도 정렬 키(created_at, id)가 바뀌지 않으므로 커서로 끝까지 한 번씩 읽음
+	var cursor repository.Cursor
+	var previous map[uint]struct{}
+	scanned := 0
+	for {
+		files, next, err := s.fileRepo.GetAfter(ctx, cursor, s.batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("파일 목록 조회 실패: %w", err)
+		}
+
+		// 직전 페이지의 파일이 다시 나오면 커서가 제자리이므로 중단
+		page := make(map[uint]struct{}, len(files))
+		for _, file := range files {
+			if _, ok := previous[file.ID]; ok {
+				return nil, fmt.Errorf("%w: 파일 %d가 다시 조회됨", ErrCursorStalled, file.ID)
+			}
+			page[file.ID] = struct{}{}
+		}
+		previous = page
+
+		for _, file := range files {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			s.reconcileFile(ctx, file, fix, result)
+		}
+
+		scanned += len(files)
+		s.logger.WithFields(logrus.Fields{
+			"scanned":    scanned,
+			"total":      total,
+			"mismatches": len(result.Mismatches),
+		}).Info("암호화본 크기를 점검하는 중입니다")
+
+		if next.IsZero() {
+			break
+		}
+		if !next.Follows(cursor) {
+			return nil, fmt.Errorf("%w: 다음 커서가 현재 위치보다 앞입니다", ErrCursorStalled)
+		}
+		cursor = next
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"audit":      "consistency",
+		"fix":        fix,
+		"checked":    result.Checked,
+		"mismatches": len(result.Mismatches),
+		"missing":    result.Missing,
+		"skipped":    result.Skipped,
+	}).Info("암호화본 크기 점검을 마쳤습니다")
+
+	return result, nil
+}
+
+// reconcileFile 파일 하나의 암호화본 크기를 비교하고 fix이면 교정합니다
+func (s *consistencyService) reconcileFile(ctx context.Context, file *model.File, fix bool, result *SizeReconcileResult) {
+	if !file.IsEncrypted() || file.BlobFileID != nil {
+		return
+	}
+
+	entry := s.logger.WithField("file_id", file.ID)
+
+	path, err := s.storage.Locate(file)
+	if err != nil {
+		entry.WithError(err).Warn("암호화본 크기 점검을 건너뜁니다")
+		result.Skipped = append(result.Skipped, file.ID)
+		return
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		result.Missing = append(result.Missing, file.ID)
+		return
+	}
+	if err != nil {
+		entry.WithError(err).Warn("암호화본 크기 점검을 건너뜁니다")
+		result.Skipped = append(result.Skipped, file.ID)
+		return
+	}
+
+	result.Checked++
+	if info.Size() == file.EncryptedSize {
+		return
+	}
+
+	mismatch := SizeMismatch{FileID: file.ID, RecordedSize: file.EncryptedSize, DiskSize: info.Size()}
+	if fix {
+		file.EncryptedSize = info.Size()
+		if err := s.fileRepo.Update(ctx, file); err != nil {
+			// 동시 수정 등으로 교정하지 못한 파일은 다음 점검에서 다시 확인
+			entry.WithError(err).Warn("암호화본 크기를 교정하지 못했습니다")
+			result.Skipped = append(result.Skipped, file.ID)
+		} else {
+			mismatch.Fixed = true
+			entry.WithFields(logrus.Fields{
+				"audit": "consistency",
+				"actor": model.ActorFromContext(ctx),
+				"from":  mismatch.RecordedSize,
+				"to":    mismatch.DiskSize,
+			}).Info("암호화본 크기를 디스크 기준으로 교정했습니다")
+		}
+	}
+	result.Mismatches = append(result.Mismatches, mismatch)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupConsistencyTest 임시 저장소 디렉터리를 쓰는 정합성 점검 서비스를 구성합니다
+func setupConsistencyTest(t *testing.T) (*consistencyService, repository.FileRepository, string) {
+	t.Helper()
+	dir := t.TempDir()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+	svc := NewConsistencyService(fileRepo, newDirStorage(t, dir, fileRepo), newSilentLogger()).(*consistencyService)
+	return svc, fileRepo, dir
+}
+
+// createSizedFile 디스크에 diskSize 바이트의 암호화본을 두고 recorded 크기로 레코드를 생성합니다 (diskSize < 0이면 암호화본 없음)
+func createSizedFile(t *testing.T, fileRepo repository.FileRepository, dir, name string, recorded, diskSize int64) *model.File {
+	t.Helper()
+	path := filepath.Join(dir, name+EncryptedFileExt)
+	if diskSize >= 0 {
+		require.NoError(t, os.WriteFile(path, make([]byte, diskSize), 0o600))
+	}
+
+	file := &model.File{
+		OriginalName:  name + ".txt",
+		EncryptedPath: path,
+		Size:          10,
+		EncryptedSize: recorded,
+		MimeType:      "text/plain",
+		ChecksumMD5:   md5Hex([]byte(name)),
+		Status:        model.FileStatusEncrypted,
+	}
+	require.NoError(t, fileRepo.Create(context.Background(), file))
+	return file
+}
+
+func TestConsistencyService_ReconcileSizes(t *testing.T) {
+	ctx := model.WithActor(context.Background(), model.ActorAdmin)
+	svc, fileRepo, dir := setupConsistencyTest(t)
+
+	match := createSizedFile(t, fileRepo, dir, "match", 100, 100)
+	drifted := createSizedFile(t, fileRepo, dir, "drifted", 100, 120)
+	legacy := createSizedFile(t, fileRepo, dir, "legacy", 0, 80)
+	missing := createSizedFile(t, fileRepo, dir, "missing", 50, -1)
+
+	// 중복 제거 참조와 암호화되지 않은 파일은 제외
+	ref := &model.File{
+		OriginalName: "ref.txt", EncryptedPath: "blob-ref/1/x", Size: 10, MimeType: "text/plain",
+		ChecksumMD5: md5Hex([]byte("match")), Status: model.FileStatusEncrypted, BlobFileID: &match.ID,
+	}
+	require.NoError(t, fileRepo.Create(ctx, ref))
+	pending := createSizedFile(t, fileRepo, dir, "pending", 1, 2)
+	require.NoError(t, fileRepo.UpdateStatus(ctx, pending.ID, model.FileStatusPending))
+
+	// 보고만 하면 레코드는 그대로
+	result, err := svc.ReconcileSizes(ctx, false)
+	require.NoError(t, err)
+	assert.False(t, result.Fix)
+	assert.Equal(t, 3, result.Checked)
+	assert.ElementsMatch(t, []SizeMismatch{
+		{FileID: drifted.ID, RecordedSize: 100, DiskSize: 120},
+		{FileID: legacy.ID, RecordedSize: 0, DiskSize: 80},
+	}, result.Mismatches)
+	assert.Equal(t, []uint{missing.ID}, result.Missing)
+	assert.Empty(t, result.Skipped)
+
+	stored, err := fileRepo.GetByID(ctx, drifted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), stored.EncryptedSize)
+
+	// 교정은 EncryptedSize만 디스크 기준으로 바꿈
+	result, err = svc.ReconcileSizes(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, result.Mismatches, 2)
+	for _, mismatch := range result.Mismatches {
+		assert.True(t, mismatch.Fixed)
+	}
+
+	stored, err = fileRepo.GetByID(ctx, drifted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), stored.EncryptedSize)
+	assert.Equal(t, int64(10), stored.Size, "원본 크기는 유지")
+	assert.Equal(t, model.ActorAdmin, stored.UpdatedBy)
+
+	stored, err = fileRepo.GetByID(ctx, legacy.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(80), stored.EncryptedSize)
+
+	// 교정 후에는 불일치 없음
+	result, err = svc.ReconcileSizes(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Mismatches)
+	assert.Equal(t, []uint{missing.ID}, result.Missing)
+}
+
+func TestConsistencyService_ReconcileSizesInBatches(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, dir := setupConsistencyTest(t)
+	svc.batchSize = 2
+
+	for i := range 5 {
+		createSizedFile(t, fileRepo, dir, fmt.Sprintf("batch_%d", i), 0, int64(i+1))
+	}
+
+	result, err := svc.ReconcileSizes(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Checked, "배치 경계와 관계없이 모든 파일을 한 번씩 점검")
+	assert.Len(t, result.Mismatches, 5)
+
+	result, err = svc.ReconcileSizes(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Mismatches)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = svc.ReconcileSizes(canceled, false)
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { NewConsistencyService(nil, svc.storage, newSilentLogger()) })
+}
+
+// stalledCursorRepo 커서를 무시하고 항상 첫 페이지 위치부터 조회하는 저장소 (정렬 키가 어긋난 DB 흉내)
+type stalledCursorRepo struct {
+	repository.FileRepository
+	emptyPages bool // 첫 페이지 이후에는 파일 없이 같은 커서만 반환
+	calls      int
+}
+
+func (r *stalledCursorRepo) GetAfter(ctx context.Context, _ repository.Cursor, limit int) ([]*model.File, repository.Cursor, error) {
+	r.calls++
+	files, next, err := r.FileRepository.GetAfter(ctx, repository.Cursor{}, limit)
+	if r.emptyPages && r.calls > 1 {
+		files = nil
+	}
+	return files, next, err
+}
+
+func TestConsistencyService_ReconcileSizesStalledCursor(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, dir := setupConsistencyTest(t)
+	svc.batchSize = 2
+	for i := range 5 {
+		createSizedFile(t, fileRepo, dir, fmt.Sprintf("stalled_%d", i), int64(i+1), int64(i+1))
+	}
+
+	for name, repo := range map[string]*stalledCursorRepo{
+		"같은 페이지 반복": {FileRepository: fileRepo},
+		"커서 제자리":    {FileRepository: fileRepo, emptyPages: true},
+	} {
+		svc.fileRepo = repo
+		_, err := svc.ReconcileSizes(ctx, false)
+		assert.ErrorIs(t, err, ErrCursorStalled, name)
+		assert.Equal(t, 2, repo.calls, name)
+	}
+}
@@ -0,0 +1,521 @@
+// Package service provides business logic for DataLocker.
+// This file implements upload negotiation with checksum deduplication and proof of ownership.
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"gorm.io/datatypes"
+)
+
+// 중복 제거 관련 상수
+const (
+	// ContentBlockSize 소유 증명 블록 크기 (1MB)
+	ContentBlockSize = 1024 * 1024
+
+	// 챌린지/세션 유효 시간
+	DedupChallengeTTL = 5 * time.Minute
+	UploadSessionTTL  = 30 * time.Minute
+
+	// 챌린지 nonce 크기 (바이트)
+	challengeNonceSize = 32
+
+	// 세션/챌린지 ID 크기 (바이트)
+	negotiationIDSize = 16
+
+	// 블록 해시 hex 길이
+	blockHashHexLength = sha256.Size * 2
+
+	// 업로드 URL 접두사
+	uploadURLPrefix = "/api/v1/files/upload/"
+
+	// 참조 레코드의 논리 경로 접두사 (디스크 경로가 아님)
+	blobReferencePathPrefix = "ref/"
+)
+
+// 협상 결과 동작
+const (
+	NegotiateActionUpload    = "upload"    // 본문 업로드 필요
+	NegotiateActionLinked    = "linked"    // 기존 blob 참조 레코드 생성 완료
+	NegotiateActionChallenge = "challenge" // 소유 증명 필요
+)
+
+// 중복 제거 서비스 에러
+var (
+	ErrInvalidNegotiation = errors.New("잘못된 업로드 협상 요청입니다")
+	ErrChallengeNotFound  = errors.New("소유 증명 챌린지를 찾을 수 없거나 만료되었습니다")
+	ErrProofFailed        = errors.New("소유 증명에 실패했습니다")
+	ErrSessionNotFound    = errors.New("업로드 세션을 찾을 수 없거나 만료되었습니다")
+	ErrExternalIDInUse    = errors.New("외부 참조 ID가 이미 다른 파일에 사용 중입니다")
+)
+
+// NegotiateRequest 업로드 1단계 요청 (본문 없이 메타만 전송)
+type NegotiateRequest struct {
+	OriginalName string `json:"original_name"`
+	Size         int64  `json:"size"`
+	MimeType     string `json:"mime_type"`
+	ChecksumMD5  string `json:"checksum_md5"`
+	ExternalID   string `json:"external_id,omitempty"` // 외부 시스템 참조 ID (선택, 살아 있는 파일 사이에서 유일)
+
+	// 사용자가 붙이는 설명과 키/값 메타데이터 (선택, 메타데이터는 JSON 객체)
+	Description    string         `json:"description,omitempty"`
+	CustomMetadata datatypes.JSON `json:"custom_metadata,omitempty"`
+}
+
+// ProofRequest 소유 증명 응답
+type ProofRequest struct {
+	ChallengeID string `json:"challenge_id"`
+	Response    string `json:"response"` // hex(HMAC-SHA256(nonce, SHA256(블록)))
+}
+
+// DedupChallenge 소유 증명 챌린지
+//
+// 클라이언트는 원본의 BlockIndex번째 블록(BlockSize 단위)의 SHA-256을 구한 뒤,
+// Nonce를 키로 한 HMAC-SHA256 값을 hex로 보내야 합니다.
+type DedupChallenge struct {
+	ID         string    `json:"id"`
+	BlockIndex int       `json:"block_index"`
+	BlockSize  int       `json:"block_size"`
+	Nonce      string    `json:"nonce"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// UploadSession 본문 업로드 세션
+type UploadSession struct {
+	ID        string    `json:"id"`
+	UploadURL string    `json:"upload_url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NegotiateResult 업로드 협상 결과
+type NegotiateResult struct {
+	Action    string          `json:"action"`
+	File      *model.File     `json:"file,omitempty"`
+	Challenge *DedupChallenge `json:"challenge,omitempty"`
+	Session   *UploadSession  `json:"session,omitempty"`
+}
+
+// DedupService 업로드 협상 서비스
+type DedupService interface {
+	// Negotiate 체크섬/크기로 중복 여부를 확인하고 다음 동작을 결정합니다
+	Negotiate(ctx context.Context, req *NegotiateRequest) (*NegotiateResult, error)
+
+	// VerifyProof 소유 증명을 검증하고 참조 레코드를 생성합니다
+	VerifyProof(ctx context.Context, req *ProofRequest) (*NegotiateResult, error)
+
+	// ClaimUploadSession 업로드 세션을 소비하고 협상된 파일 정보를 반환합니다
+	ClaimUploadSession(id string) (*NegotiateRequest, error)
+}
+
+// pendingChallenge 검증 대기 중인 챌린지
+type pendingChallenge struct {
+	request   NegotiateRequest
+	sourceID  uint
+	simHash   *int64 // 원본의 유사도 시그니처 (참조 레코드에 복사)
+	expected  []byte
+	expiresAt time.Time
+}
+
+// pendingSession 본문 업로드를 기다리는 세션
+type pendingSession struct {
+	session *UploadSession
+	request NegotiateRequest
+}
+
+// dedupService 업로드 협상 서비스 구현체
+type dedupService struct {
+	enabled       bool
+	proofRequired bool
+	fileRepo      repository.FileRepository
+	clock         clock.Clock
+
+	mu         sync.Mutex
+	challenges map[string]*pendingChallenge
+	sessions   map[string]*pendingSession
+}
+
+// NewDedupService 새로운 업로드 협상 서비스를 생성합니다
+func NewDedupService(cfg config.SecurityConfig, fileRepo repository.FileRepository) DedupService {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	return &dedupService{
+		enabled:       cfg.DedupEnabled,
+		proofRequired: cfg.DedupProofRequired,
+		fileRepo:      fileRepo,
+		clock:         clock.Real{},
+		challenges:    make(map[string]*pendingChallenge),
+		sessions:      make(map[string]*pendingSession),
+	}
+}
+
+// Negotiate 업로드 협상 1단계
+func (s *dedupService) Negotiate(ctx context.Context, req *NegotiateRequest) (*NegotiateResult, error) {
+	if err := validateNegotiateRequest(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkExternalID(ctx, req.ExternalID); err != nil {
+		return nil, err
+	}
+
+	if !s.enabled {
+		return s.newUploadResult(req)
+	}
+
+	source, err := s.findSource(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return s.newUploadResult(req)
+	}
+
+	if !s.proofRequired {
+		return s.link(ctx, req, source.ID, source.SimHash)
+	}
+
+	// 블록 해시가 없는 blob은 소유 증명이 불가능하므로 일반 업로드
+	blockHashes, err := splitBlockHashes(source.BlockHashes)
+	if err != nil || len(blockHashes) == 0 {
+		return s.newUploadResult(req)
+	}
+
+	return s.newChallenge(req, source, blockHashes)
+}
+
+// VerifyProof 소유 증명 검증 후 참조 레코드를 생성합니다
+func (s *dedupService) VerifyProof(ctx context.Context, req *ProofRequest) (*NegotiateResult, error) {
+	if req == nil || req.ChallengeID == "" || req.Response == "" {
+		return nil, fmt.Errorf("%w: 챌린지 ID와 응답이 필요합니다", ErrInvalidNegotiation)
+	}
+
+	// 챌린지는 한 번만 사용 가능
+	s.mu.Lock()
+	challenge, exists := s.challenges[req.ChallengeID]
+	delete(s.challenges, req.ChallengeID)
+	s.mu.Unlock()
+
+	if !exists || s.clock.Now().After(challenge.expiresAt) {
+		return nil, ErrChallengeNotFound
+	}
+
+	response, err := hex.DecodeString(req.Response)
+	if err != nil || !hmac.Equal(response, challenge.expected) {
+		return nil, ErrProofFailed
+	}
+
+	return s.link(ctx, &challenge.request, challenge.sourceID, challenge.simHash)
+}
+
+// ClaimUploadSession 업로드 세션을 한 번만 사용할 수 있도록 소비합니다
+func (s *dedupService) ClaimUploadSession(id string) (*NegotiateRequest, error) {
+	s.mu.Lock()
+	pending, exists := s.sessions[id]
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	if !exists || s.clock.Now().After(pending.session.ExpiresAt) {
+		return nil, ErrSessionNotFound
+	}
+
+	request := pending.request
+	return &request, nil
+}
+
+// Reset 대기 중인 챌린지와 업로드 세션을 모두 버리고 버린 수를 반환합니다 (공장 초기화용)
+func (s *dedupService) Reset() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.challenges) + len(s.sessions)
+	clear(s.challenges)
+	clear(s.sessions)
+	return n
+}
+
+// checkExternalID 외부 참조 ID가 이미 살아 있는 파일에 쓰이고 있는지 확인합니다
+//
+// 본문 업로드 전에 미리 거부하기 위한 확인이며, 동시에 같은 ID로 협상한 요청은
+// 레코드를 생성할 때 유일 인덱스로 걸러집니다.
+func (s *dedupService) checkExternalID(ctx context.Context, externalID string) error {
+	if externalID == "" {
+		return nil
+	}
+
+	_, err := s.fileRepo.GetByExternalID(ctx, externalID)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: %s", ErrExternalIDInUse, externalID)
+	case errors.Is(err, model.ErrRecordNotFound):
+		return nil
+	default:
+		return fmt.Errorf("외부 참조 ID 확인 실패: %w", err)
+	}
+}
+
+// findSource 같은 체크섬과 크기를 가진 원본 blob 파일을 찾습니다 (없으면 nil)
+func (s *dedupService) findSource(ctx context.Context, req *NegotiateRequest) (*model.File, error) {
+	existing, err := s.fileRepo.GetByChecksumMD5(ctx, req.ChecksumMD5)
+	if errors.Is(err, model.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("중복 파일 확인 실패: %w", err)
+	}
+	if existing.Size != req.Size || !existing.IsEncrypted() {
+		return nil, nil
+	}
+
+	// 참조 레코드라면 실제 blob을 가진 원본으로 이동
+	if existing.IsBlobReference() {
+		source, err := s.fileRepo.GetByID(ctx, *existing.BlobFileID)
+		if err != nil {
+			return nil, fmt.Errorf("원본 blob 조회 실패: %w", err)
+		}
+		return source, nil
+	}
+
+	return existing, nil
+}
+
+// link 원본 blob을 참조하는 새 파일 레코드를 생성합니다
+//
+// 내용이 원본과 같으므로 유사 중복 탐지용 시그니처도 원본의 값을 그대로 사용합니다.
+func (s *dedupService) link(ctx context.Context, req *NegotiateRequest, sourceID uint, simHash *int64) (*NegotiateResult, error) {
+	suffix, err := randomHex(negotiationIDSize)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &model.File{
+		OriginalName:   req.OriginalName,
+		EncryptedPath:  fmt.Sprintf("%s%d/%s", blobReferencePathPrefix, sourceID, suffix),
+		Size:           req.Size,
+		MimeType:       req.MimeType,
+		ChecksumMD5:    req.ChecksumMD5,
+		Status:         model.FileStatusEncrypted,
+		BlobFileID:     &sourceID,
+		SimHash:        simHash,
+		ExternalID:     req.ExternalID,
+		Description:    req.Description,
+		CustomMetadata: req.CustomMetadata,
+	}
+	if err := s.fileRepo.Create(ctx, file); err != nil {
+		return nil, fmt.Errorf("참조 레코드 생성 실패: %w", externalIDConflict(file, err))
+	}
+
+	return &NegotiateResult{Action: NegotiateActionLinked, File: file}, nil
+}
+
+// newChallenge 임의 블록에 대한 소유 증명 챌린지를 발급합니다
+func (s *dedupService) newChallenge(req *NegotiateRequest, source *model.File, blockHashes [][]byte) (*NegotiateResult, error) {
+	index, err := rand.Int(rand.Reader, big.NewInt(int64(len(blockHashes))))
+	if err != nil {
+		return nil, fmt.Errorf("챌린지 생성 실패: %w", err)
+	}
+
+	nonce := make([]byte, challengeNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("챌린지 생성 실패: %w", err)
+	}
+
+	id, err := randomHex(negotiationIDSize)
+	if err != nil {
+		return nil, err
+	}
+
+	blockIndex := int(index.Int64())
+	expiresAt := s.clock.Now().Add(DedupChallengeTTL)
+
+	s.mu.Lock()
+	s.pruneExpiredLocked()
+	s.challenges[id] = &pendingChallenge{
+		request:   *req,
+		sourceID:  source.ID,
+		simHash:   source.SimHash,
+		expected:  ProofResponse(nonce, blockHashes[blockIndex]),
+		expiresAt: expiresAt,
+	}
+	s.mu.Unlock()
+
+	return &NegotiateResult{
+		Action: NegotiateActionChallenge,
+		Challenge: &DedupChallenge{
+			ID:         id,
+			BlockIndex: blockIndex,
+			BlockSize:  ContentBlockSize,
+			Nonce:      hex.EncodeToString(nonce),
+			ExpiresAt:  expiresAt,
+		},
+	}, nil
+}
+
+// newUploadResult 본문 업로드 세션을 발급합니다
+func (s *dedupService) newUploadResult(req *NegotiateRequest) (*NegotiateResult, error) {
+	id, err := randomHex(negotiationIDSize)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &UploadSession{
+		ID:        id,
+		UploadURL: uploadURLPrefix + id,
+		ExpiresAt: s.clock.Now().Add(UploadSessionTTL),
+	}
+
+	s.mu.Lock()
+	s.pruneExpiredLocked()
+	s.sessions[id] = &pendingSession{session: session, request: *req}
+	s.mu.Unlock()
+
+	return &NegotiateResult{Action: NegotiateActionUpload, Session: session}, nil
+}
+
+// pruneExpiredLocked 만료된 챌린지와 세션을 정리합니다
+func (s *dedupService) pruneExpiredLocked() {
+	now := s.clock.Now()
+	for id, challenge := range s.challenges {
+		if now.After(challenge.expiresAt) {
+			delete(s.challenges, id)
+		}
+	}
+	for id, pending := range s.sessions {
+		if now.After(pending.session.ExpiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// validateNegotiateRequest 협상 요청을 검증합니다
+func validateNegotiateRequest(req *NegotiateRequest) error {
+	if req == nil {
+		return fmt.Errorf("%w: 요청이 비어있습니다", ErrInvalidNegotiation)
+	}
+
+	if req.OriginalName == "" || req.MimeType == "" {
+		return fmt.Errorf("%w: 파일명과 MIME 타입이 필요합니다", ErrInvalidNegotiation)
+	}
+
+	if req.Size <= 0 {
+		return fmt.Errorf("%w: 파일 크기는 0보다 커야 합니다", ErrInvalidNegotiation)
+	}
+
+	if len(req.ChecksumMD5) != hex.EncodedLen(16) || !model.IsValidHex(req.ChecksumMD5) {
+		return fmt.Errorf("%w: 잘못된 MD5 체크섬입니다", ErrInvalidNegotiation)
+	}
+
+	if len(req.ExternalID) > model.MaxExternalIDLength || strings.TrimSpace(req.ExternalID) != req.ExternalID {
+		return fmt.Errorf("%w: 외부 참조 ID는 앞뒤 공백 없이 %d자 이하여야 합니다", ErrInvalidNegotiation, model.MaxExternalIDLength)
+	}
+
+	if err := model.ValidateDescription(req.Description); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidNegotiation, err)
+	}
+
+	if err := model.ValidateCustomMetadata(model.NormalizeCustomMetadata(req.CustomMetadata)); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidNegotiation, err)
+	}
+
+	return nil
+}
+
+// externalIDConflict 외부 참조 ID가 있는 파일의 중복 에러를 ErrExternalIDInUse로 감쌉니다
+//
+// 암호화 경로는 서버가 임의로 만들므로 유일 인덱스 충돌은 외부 참조 ID에서 생깁니다.
+func externalIDConflict(file *model.File, err error) error {
+	if file.ExternalID != "" && errors.Is(err, repository.ErrDuplicate) {
+		return fmt.Errorf("%w: %w", ErrExternalIDInUse, err)
+	}
+	return err
+}
+
+// ProofResponse 소유 증명 응답값을 계산합니다 (HMAC-SHA256(nonce, 블록 해시))
+func ProofResponse(nonce, blockHash []byte) []byte {
+	mac := hmac.New(sha256.New, nonce)
+	mac.Write(blockHash)
+	return mac.Sum(nil)
+}
+
+// BlockHasher 쓰여진 데이터를 ContentBlockSize 블록별 SHA-256으로 요약하는 Writer
+type BlockHasher struct {
+	current hash.Hash
+	filled  int
+	hashes  []byte
+}
+
+// NewBlockHasher 새로운 블록 해시 계산기를 생성합니다
+func NewBlockHasher() *BlockHasher {
+	return &BlockHasher{current: sha256.New()}
+}
+
+// Write 데이터를 블록 단위로 해시합니다
+func (b *BlockHasher) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		n := min(ContentBlockSize-b.filled, len(p))
+		b.current.Write(p[:n])
+		b.filled += n
+		p = p[n:]
+
+		if b.filled == ContentBlockSize {
+			b.hashes = b.current.Sum(b.hashes)
+			b.current.Reset()
+			b.filled = 0
+		}
+	}
+	return written, nil
+}
+
+// Hex 마지막 부분 블록을 포함한 블록 해시들을 hex 문자열로 반환합니다
+func (b *BlockHasher) Hex() string {
+	hashes := b.hashes
+	if b.filled > 0 {
+		hashes = b.current.Sum(append([]byte(nil), hashes...))
+	}
+	return hex.EncodeToString(hashes)
+}
+
+// splitBlockHashes 연결된 hex 블록 해시를 블록별로 분리합니다
+func splitBlockHashes(value string) ([][]byte, error) {
+	if len(value)%blockHashHexLength != 0 {
+		return nil, fmt.Errorf("잘못된 블록 해시 길이: %d", len(value))
+	}
+
+	raw, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("잘못된 블록 해시 형식: %w", err)
+	}
+
+	hashes := make([][]byte, 0, len(raw)/sha256.Size)
+	for i := 0; i < len(raw); i += sha256.Size {
+		hashes = append(hashes, raw[i:i+sha256.Size])
+	}
+	return hashes, nil
+}
+
+// randomHex 랜덤 hex 문자열을 생성합니다
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("랜덤 값 생성 실패: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec // 파일 모델의 체크섬 형식(MD5)과 일치
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// dedupTestContent 블록 3개(마지막은 부분 블록)에 걸친 테스트 데이터
+var dedupTestContent = bytes.Repeat([]byte("0123456789abcdef"), ContentBlockSize*5/2/16)
+
+// setupDedupTest 원본 blob 파일 하나가 등록된 협상 서비스를 구성합니다
+func setupDedupTest(t *testing.T, cfg config.SecurityConfig, withBlockHashes bool) (DedupService, repository.FileRepository, *model.File) {
+	t.Helper()
+	ctx := context.Background()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+
+	hasher := NewBlockHasher()
+	_, _ = hasher.Write(dedupTestContent)
+
+	source := &model.File{
+		OriginalName:  "original.txt",
+		EncryptedPath: "/storage/original.dlk",
+		Size:          int64(len(dedupTestContent)),
+		MimeType:      "text/plain",
+		ChecksumMD5:   md5Hex(dedupTestContent),
+		Status:        model.FileStatusEncrypted,
+	}
+	if withBlockHashes {
+		source.BlockHashes = hasher.Hex()
+	}
+	source.SetSimilaritySignature(0x0123456789abcdef)
+	require.NoError(t, fileRepo.Create(ctx, source))
+
+	return NewDedupService(cfg, fileRepo), fileRepo, source
+}
+
+// md5Hex MD5 체크섬을 hex로 반환합니다
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data) //nolint:gosec // 파일 모델의 체크섬 형식(MD5)과 일치
+	return hex.EncodeToString(sum[:])
+}
+
+// duplicateRequest 원본과 같은 내용의 협상 요청
+func duplicateRequest() *NegotiateRequest {
+	return &NegotiateRequest{
+		OriginalName: "copy.txt",
+		Size:         int64(len(dedupTestContent)),
+		MimeType:     "text/plain",
+		ChecksumMD5:  md5Hex(dedupTestContent),
+	}
+}
+
+// answerChallenge 클라이언트 측 소유 증명 응답을 계산합니다
+func answerChallenge(t *testing.T, challenge *DedupChallenge, content []byte) string {
+	t.Helper()
+	start := challenge.BlockIndex * challenge.BlockSize
+	end := min(start+challenge.BlockSize, len(content))
+	blockHash := sha256.Sum256(content[start:end])
+
+	nonce, err := hex.DecodeString(challenge.Nonce)
+	require.NoError(t, err)
+	return hex.EncodeToString(ProofResponse(nonce, blockHash[:]))
+}
+
+func TestDedupService_Disabled(t *testing.T) {
+	svc, _, _ := setupDedupTest(t, config.SecurityConfig{}, true)
+
+	result, err := svc.Negotiate(context.Background(), duplicateRequest())
+	require.NoError(t, err)
+	assert.Equal(t, NegotiateActionUpload, result.Action)
+	require.NotNil(t, result.Session)
+	assert.Contains(t, result.Session.UploadURL, result.Session.ID)
+}
+
+func TestDedupService_LinkWithoutProof(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, source := setupDedupTest(t, config.SecurityConfig{DedupEnabled: true}, false)
+
+	result, err := svc.Negotiate(context.Background(), duplicateRequest())
+	require.NoError(t, err)
+	assert.Equal(t, NegotiateActionLinked, result.Action)
+	require.NotNil(t, result.File)
+	require.NotNil(t, result.File.BlobFileID)
+	assert.Equal(t, source.ID, *result.File.BlobFileID)
+	assert.Equal(t, "copy.txt", result.File.OriginalName)
+	assert.Equal(t, source.SimHash, result.File.SimHash, "내용이 같으므로 원본의 시그니처를 사용")
+
+	// 새 체크섬은 업로드 필요
+	req := duplicateRequest()
+	req.ChecksumMD5 = md5Hex([]byte("different"))
+	result, err = svc.Negotiate(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, NegotiateActionUpload, result.Action)
+
+	// 크기가 다르면 업로드 필요
+	req = duplicateRequest()
+	req.Size++
+	result, err = svc.Negotiate(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, NegotiateActionUpload, result.Action)
+
+	count, err := fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestDedupService_ExternalID(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, _ := setupDedupTest(t, config.SecurityConfig{DedupEnabled: true}, false)
+
+	req := duplicateRequest()
+	req.ExternalID = "erp-1"
+	result, err := svc.Negotiate(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, NegotiateActionLinked, result.Action)
+	assert.Equal(t, "erp-1", result.File.ExternalID)
+
+	found, err := fileRepo.GetByExternalID(ctx, "erp-1")
+	require.NoError(t, err)
+	assert.Equal(t, result.File.ID, found.ID)
+
+	// 살아 있는 파일이 쓰는 ID는 업로드가 필요한 요청이라도 협상 단계에서 거부
+	for _, checksum := range []string{req.ChecksumMD5, md5Hex([]byte("different"))} {
+		again := duplicateRequest()
+		again.ChecksumMD5 = checksum
+		again.ExternalID = "erp-1"
+		_, err = svc.Negotiate(ctx, again)
+		assert.ErrorIs(t, err, ErrExternalIDInUse)
+	}
+
+	// 삭제하면 같은 ID를 다시 쓸 수 있음
+	require.NoError(t, fileRepo.Delete(ctx, result.File.ID))
+	result, err = svc.Negotiate(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, NegotiateActionLinked, result.Action)
+}
+
+func TestDedupService_LinkCarriesDetails(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, _ := setupDedupTest(t, config.SecurityConfig{DedupEnabled: true}, false)
+
+	req := duplicateRequest()
+	req.Description = "3분기 보고서 사본"
+	req.CustomMetadata = datatypes.JSON(`{"client":{"name":"acme"}}`)
+	result, err := svc.Negotiate(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, NegotiateActionLinked, result.Action)
+
+	linked, err := fileRepo.GetByID(ctx, result.File.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "3분기 보고서 사본", linked.Description)
+	assert.JSONEq(t, `{"client":{"name":"acme"}}`, string(linked.CustomMetadata))
+
+	files, total, err := fileRepo.SearchMetadata(ctx, "client.name", "acme", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, files, 1)
+	assert.Equal(t, linked.ID, files[0].ID)
+}
+
+func TestDedupService_ProofOfOwnership(t *testing.T) {
+	cfg := config.SecurityConfig{DedupEnabled: true, DedupProofRequired: true}
+	svc, _, source := setupDedupTest(t, cfg, true)
+	ctx := context.Background()
+
+	// 챌린지 발급
+	result, err := svc.Negotiate(ctx, duplicateRequest())
+	require.NoError(t, err)
+	assert.Equal(t, NegotiateActionChallenge, result.Action)
+	require.NotNil(t, result.Challenge)
+	assert.Less(t, result.Challenge.BlockIndex, 3)
+
+	// 잘못된 응답은 실패하고 챌린지는 소진됨
+	_, err = svc.VerifyProof(ctx, &ProofRequest{ChallengeID: result.Challenge.ID, Response: "00"})
+	assert.ErrorIs(t, err, ErrProofFailed)
+
+	_, err = svc.VerifyProof(ctx, &ProofRequest{
+		ChallengeID: result.Challenge.ID,
+		Response:    answerChallenge(t, result.Challenge, dedupTestContent),
+	})
+	assert.ErrorIs(t, err, ErrChallengeNotFound)
+
+	// 새 챌린지에 올바르게 응답하면 참조 레코드 생성
+	result, err = svc.Negotiate(ctx, duplicateRequest())
+	require.NoError(t, err)
+
+	linked, err := svc.VerifyProof(ctx, &ProofRequest{
+		ChallengeID: result.Challenge.ID,
+		Response:    answerChallenge(t, result.Challenge, dedupTestContent),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, NegotiateActionLinked, linked.Action)
+	assert.Equal(t, source.ID, *linked.File.BlobFileID)
+	assert.Equal(t, source.SimHash, linked.File.SimHash)
+
+	// 내용을 모르는 클라이언트(거짓 체크섬)는 통과하지 못함
+	result, err = svc.Negotiate(ctx, duplicateRequest())
+	require.NoError(t, err)
+	forged := bytes.Repeat([]byte("x"), len(dedupTestContent))
+	_, err = svc.VerifyProof(ctx, &ProofRequest{
+		ChallengeID: result.Challenge.ID,
+		Response:    answerChallenge(t, result.Challenge, forged),
+	})
+	assert.ErrorIs(t, err, ErrProofFailed)
+}
+
+func TestDedupService_ChallengeExpires(t *testing.T) {
+	cfg := config.SecurityConfig{DedupEnabled: true, DedupProofRequired: true}
+	svc, _, _ := setupDedupTest(t, cfg, true)
+	clk := clock.NewFake(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	svc.(*dedupService).clock = clk
+	ctx := context.Background()
+
+	// 만료 시각까지는 응답할 수 있음
+	result, err := svc.Negotiate(ctx, duplicateRequest())
+	require.NoError(t, err)
+	assert.Equal(t, clk.Now().Add(DedupChallengeTTL), result.Challenge.ExpiresAt)
+	clk.Advance(DedupChallengeTTL)
+	_, err = svc.VerifyProof(ctx, &ProofRequest{
+		ChallengeID: result.Challenge.ID,
+		Response:    answerChallenge(t, result.Challenge, dedupTestContent),
+	})
+	require.NoError(t, err)
+
+	// 만료 뒤에는 올바른 응답도 거부
+	result, err = svc.Negotiate(ctx, duplicateRequest())
+	require.NoError(t, err)
+	clk.Advance(DedupChallengeTTL + time.Nanosecond)
+	_, err = svc.VerifyProof(ctx, &ProofRequest{
+		ChallengeID: result.Challenge.ID,
+		Response:    answerChallenge(t, result.Challenge, dedupTestContent),
+	})
+	assert.ErrorIs(t, err, ErrChallengeNotFound)
+}
+
+func TestDedupService_ProofUnavailable(t *testing.T) {
+	cfg := config.SecurityConfig{DedupEnabled: true, DedupProofRequired: true}
+	svc, _, _ := setupDedupTest(t, cfg, false)
+
+	// 블록 해시가 없는 blob은 소유 증명이 불가능하므로 업로드로 진행
+	result, err := svc.Negotiate(context.Background(), duplicateRequest())
+	require.NoError(t, err)
+	assert.Equal(t, NegotiateActionUpload, result.Action)
+}
+
+func TestDedupService_InvalidRequests(t *testing.T) {
+	svc, _, _ := setupDedupTest(t, config.SecurityConfig{DedupEnabled: true}, false)
+
+	testCases := []struct {
+		name   string
+		modify func(*NegotiateRequest)
+	}{
+		{name: "빈 파일명", modify: func(r *NegotiateRequest) { r.OriginalName = "" }},
+		{name: "0 크기", modify: func(r *NegotiateRequest) { r.Size = 0 }},
+		{name: "잘못된 체크섬", modify: func(r *NegotiateRequest) { r.ChecksumMD5 = "not-a-checksum" }},
+		{name: "긴 외부 참조 ID", modify: func(r *NegotiateRequest) { r.ExternalID = strings.Repeat("a", model.MaxExternalIDLength+1) }},
+		{name: "외부 참조 ID 앞뒤 공백", modify: func(r *NegotiateRequest) { r.ExternalID = " erp-1" }},
+		{name: "긴 설명", modify: func(r *NegotiateRequest) { r.Description = strings.Repeat("가", model.MaxDescriptionLength+1) }},
+		{name: "객체가 아닌 메타데이터", modify: func(r *NegotiateRequest) { r.CustomMetadata = datatypes.JSON(`["a"]`) }},
+		{name: "잘못된 메타데이터 JSON", modify: func(r *NegotiateRequest) { r.CustomMetadata = datatypes.JSON(`{"a":`) }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := duplicateRequest()
+			tc.modify(req)
+			_, err := svc.Negotiate(context.Background(), req)
+			assert.ErrorIs(t, err, ErrInvalidNegotiation)
+		})
+	}
+
+	_, err := svc.VerifyProof(context.Background(), &ProofRequest{})
+	assert.ErrorIs(t, err, ErrInvalidNegotiation)
+}
+
+func TestBlockHasher(t *testing.T) {
+	hasher := NewBlockHasher()
+
+	// 여러 번 나누어 써도 블록 경계 기준으로 해시
+	for i := 0; i < len(dedupTestContent); i += 100000 {
+		_, _ = hasher.Write(dedupTestContent[i:min(i+100000, len(dedupTestContent))])
+	}
+
+	hashes, err := splitBlockHashes(hasher.Hex())
+	require.NoError(t, err)
+	require.Len(t, hashes, 3)
+
+	last := sha256.Sum256(dedupTestContent[2*ContentBlockSize:])
+	assert.Equal(t, last[:], hashes[2])
+}
@@ -0,0 +1,315 @@
+// Package service provides business logic for DataLocker.
+// This file implements the two-step factory reset that wipes the database, encrypted files and in-memory state.
+package service
+
+import (
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 공장 초기화 관련 상수
+const (
+	// FactoryResetTokenTTL 확인 토큰 유효 시간 (지나면 다시 발급받아야 함)
+	FactoryResetTokenTTL = 2 * time.Minute
+
+	// 확인 토큰 길이 (랜덤 바이트)
+	factoryResetTokenBytes = 32
+
+	// 안전 삭제 시 한 번에 덮어쓰는 크기
+	secureDeleteChunkSize = 64 * 1024
+)
+
+// 공장 초기화 에러
+var (
+	ErrInvalidResetToken = errors.New("공장 초기화 확인 토큰이 없거나 만료되었습니다")
+)
+
+// ResetDatabaseFunc 모든 테이블을 지우고 다시 만듭니다 (model.ResetDatabase)
+type ResetDatabaseFunc func(ctx context.Context) error
+
+// Resetter 공장 초기화 때 메모리에 남은 캐시나 대기열을 비울 수 있는 구성요소
+type Resetter interface {
+	// Reset 보관 중인 항목을 모두 버리고 버린 수를 반환합니다
+	Reset() int
+}
+
+// FactoryResetRequest 공장 초기화 요청 (토큰 없이 보내면 확인 토큰을 발급)
+type FactoryResetRequest struct {
+	ConfirmationToken string `json:"confirmation_token"`
+	SecureDelete      bool   `json:"secure_delete"` // 지우기 전에 0으로 덮어씀
+}
+
+// FactoryResetToken 실행 전에 발급하는 확인 토큰
+type FactoryResetToken struct {
+	Token     string    `json:"confirmation_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FactoryResetResult 공장 초기화로 지운 항목
+type FactoryResetResult struct {
+	SecureDelete  bool           `json:"secure_delete"`
+	DeletedFiles  int            `json:"deleted_files"`    // 지운 암호화 파일 수 (업로드 중인 임시 파일 포함)
+	DeletedBytes  int64          `json:"deleted_bytes"`    // 지운 암호화 파일 크기 합계
+	Failed        []string       `json:"failed,omitempty"` // 지우지 못한 파일/디렉터리와 사유
+	ClearedCaches map[string]int `json:"cleared_caches"`   // 캐시/대기열 이름별 버린 항목 수
+	StartedAt     time.Time      `json:"started_at"`
+	CompletedAt   time.Time      `json:"completed_at"`
+}
+
+// FactoryResetService 모든 데이터를 지우는 공장 초기화 서비스
+type FactoryResetService interface {
+	// IssueToken 실행에 필요한 확인 토큰을 발급합니다
+	//
+	// 한 번에 하나의 토큰만 유효하며, 새로 발급하면 이전 토큰은 쓸 수 없습니다.
+	IssueToken(ctx context.Context) (*FactoryResetToken, error)
+
+	// Reset 확인 토큰을 소비하고 DB 초기화 → 암호화 파일 삭제 → 캐시/대기열 비우기 순서로 실행합니다
+	//
+	// 토큰이 다르거나 만료되었으면 ErrInvalidResetToken을 반환합니다. DB 초기화에
+	// 실패하면 파일을 지우지 않고 에러를 반환하며, 그 뒤 단계의 실패는 결과의
+	// Failed에 담습니다. secureDelete이면 파일을 0으로 덮어쓴 뒤 지웁니다.
+	Reset(ctx context.Context, token string, secureDelete bool) (*FactoryResetResult, error)
+}
+
+// pendingResetToken 발급한 확인 토큰과 만료 시각
+type pendingResetToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// namedResetter 이름을 붙인 캐시/대기열
+type namedResetter struct {
+	name     string
+	resetter Resetter
+}
+
+// factoryResetService 공장 초기화 서비스 구현체
+type factoryResetService struct {
+	resetDB   ResetDatabaseFunc
+	dirs      []string
+	resetters []namedResetter // 이름순
+	logger    *logrus.Logger
+	clock     clock.Clock
+
+	mu      sync.Mutex
+	pending *pendingResetToken
+
+	running sync.Mutex // 초기화는 한 번에 하나만
+}
+
+// NewFactoryResetService 새로운 공장 초기화 서비스를 생성합니다
+//
+// dirs는 암호화 파일을 지울 저장소 디렉터리입니다(하위 디렉터리와 다른 확장자의
+// 파일은 건드리지 않음). resetters는 이름별로 비울 캐시/대기열입니다.
+func NewFactoryResetService(resetDB ResetDatabaseFunc, dirs []string, resetters map[string]Resetter, logger *logrus.Logger) FactoryResetService {
+	if resetDB == nil {
+		panic("DB 초기화 함수가 필요합니다")
+	}
+
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	s := &factoryResetService{
+		resetDB:   resetDB,
+		resetters: make([]namedResetter, 0, len(resetters)),
+		logger:    logger,
+		clock:     clock.Real{},
+	}
+
+	// 여러 볼륨이 같은 디렉터리를 가리켜도 한 번만 훑음
+	for _, dir := range dirs {
+		if dir = filepath.Clean(dir); !slices.Contains(s.dirs, dir) {
+			s.dirs = append(s.dirs, dir)
+		}
+	}
+
+	for name, resetter := range resetters {
+		if resetter == nil {
+			panic(fmt.Sprintf("%s 초기화 대상이 필요합니다", name))
+		}
+		s.resetters = append(s.resetters, namedResetter{name: name, resetter: resetter})
+	}
+	slices.SortFunc(s.resetters, func(a, b namedResetter) int { return cmp.Compare(a.name, b.name) })
+
+	return s
+}
+
+// IssueToken 랜덤 토큰을 만들어 이전 토큰을 대체합니다
+func (s *factoryResetService) IssueToken(ctx context.Context) (*FactoryResetToken, error) {
+	token, err := randomHex(factoryResetTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("확인 토큰 생성 실패: %w", err)
+	}
+
+	expiresAt := s.clock.Now().Add(FactoryResetTokenTTL)
+	s.mu.Lock()
+	s.pending = &pendingResetToken{token: token, expiresAt: expiresAt}
+	s.mu.Unlock()
+
+	s.logger.WithFields(logrus.Fields{
+		"audit":      "factory_reset",
+		"actor":      model.ActorFromContext(ctx),
+		"expires_at": expiresAt,
+	}).Warn("공장 초기화 확인 토큰을 발급했습니다")
+
+	return &FactoryResetToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// Reset 토큰을 확인한 뒤 단계별로 초기화합니다
+func (s *factoryResetService) Reset(ctx context.Context, token string, secureDelete bool) (*FactoryResetResult, error) {
+	if !s.consumeToken(token) {
+		return nil, ErrInvalidResetToken
+	}
+
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	entry := s.logger.WithFields(logrus.Fields{
+		"audit":         "factory_reset",
+		"actor":         model.ActorFromContext(ctx),
+		"secure_delete": secureDelete,
+	})
+
+	result := &FactoryResetResult{
+		SecureDelete:  secureDelete,
+		ClearedCaches: make(map[string]int, len(s.resetters)),
+		StartedAt:     s.clock.Now(),
+	}
+
+	// 1. DB 초기화 (실패하면 레코드가 남아 있으므로 파일도 지우지 않음)
+	if err := s.resetDB(ctx); err != nil {
+		entry.WithError(err).Error("공장 초기화 중 DB 초기화에 실패했습니다")
+		return nil, fmt.Errorf("DB 초기화 실패: %w", err)
+	}
+
+	// 2. 저장소 디렉터리의 암호화 파일 삭제 (레코드가 이미 없으므로 실패해도 계속 진행)
+	for _, dir := range s.dirs {
+		s.wipeDir(dir, secureDelete, result)
+	}
+
+	// 3. 메모리에 남은 캐시/대기열 비우기
+	for _, r := range s.resetters {
+		result.ClearedCaches[r.name] = r.resetter.Reset()
+	}
+
+	result.CompletedAt = s.clock.Now()
+	entry = entry.WithFields(logrus.Fields{
+		"deleted_files":  result.DeletedFiles,
+		"deleted_bytes":  result.DeletedBytes,
+		"failed":         len(result.Failed),
+		"cleared_caches": result.ClearedCaches,
+	})
+	if len(result.Failed) > 0 {
+		entry.WithField("failures", result.Failed).Warn("공장 초기화를 마쳤지만 지우지 못한 파일이 있습니다 (수동 정리 필요)")
+	} else {
+		entry.Warn("공장 초기화를 마쳤습니다")
+	}
+
+	return result, nil
+}
+
+// consumeToken 발급한 토큰과 같고 만료되지 않았는지 확인하고, 맞으면 소비합니다
+func (s *factoryResetService) consumeToken(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := s.pending
+	if pending == nil || token == "" ||
+		subtle.ConstantTimeCompare([]byte(token), []byte(pending.token)) != 1 {
+		return false
+	}
+
+	s.pending = nil
+	return s.clock.Now().Before(pending.expiresAt)
+}
+
+// wipeDir 디렉터리 바로 아래의 암호화 파일과 업로드 임시 파일, 저장 경로 예약을 지웁니다
+//
+// 디렉터리가 없으면 지울 것이 없는 것으로 봅니다.
+func (s *factoryResetService) wipeDir(dir string, secureDelete bool, result *FactoryResetResult) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		result.Failed = append(result.Failed, fmt.Sprintf("%s: %v", dir, err))
+		return
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() ||
+			!(strings.HasSuffix(name, EncryptedFileExt) || strings.HasSuffix(name, EncryptedFileExt+partialFileExt) ||
+				strings.HasSuffix(name, EncryptedFileExt+pathReservationExt)) {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		size, err := removeFile(path, secureDelete)
+		if err != nil {
+			result.Failed = append(result.Failed, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		result.DeletedFiles++
+		result.DeletedBytes += size
+	}
+}
+
+// removeFile 파일을 지우고 크기를 반환합니다 (secure이면 먼저 0으로 덮어쓰고 디스크에 동기화)
+//
+// 저널링/카피 온 라이트 파일 시스템이나 SSD에서는 덮어쓴 블록과 원래 블록이 다를 수
+// 있으므로, 안전 삭제는 복구를 어렵게 할 뿐 보장하지는 않습니다.
+func removeFile(path string, secure bool) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if secure {
+		if err := overwriteZeros(path, info.Size()); err != nil {
+			return 0, fmt.Errorf("덮어쓰기 실패: %w", err)
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// overwriteZeros 파일 내용을 size 바이트만큼 0으로 덮어씁니다
+func overwriteZeros(path string, size int64) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0) //nolint:gosec // 저장소 내부 경로
+	if err != nil {
+		return err
+	}
+
+	zeros := make([]byte, secureDeleteChunkSize)
+	for written := int64(0); written < size && err == nil; {
+		n := min(size-written, int64(len(zeros)))
+		_, err = f.Write(zeros[:n])
+		written += n
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
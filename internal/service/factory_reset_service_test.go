@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingResetter 비운 횟수를 세고 고정 개수를 반환하는 초기화 대상
+type countingResetter struct {
+	items int
+	calls int
+}
+
+func (r *countingResetter) Reset() int {
+	r.calls++
+	n := r.items
+	r.items = 0
+	return n
+}
+
+func TestFactoryResetService_Reset(t *testing.T) {
+	ctx := context.Background()
+	db := setupServiceTestDB(t)
+	fileRepo := repository.NewFileRepository(db)
+	require.NoError(t, fileRepo.Create(ctx, &model.File{
+		OriginalName:  "report.pdf",
+		EncryptedPath: "/encrypted/report.enc",
+		Size:          10,
+		MimeType:      "application/pdf",
+		ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+		Status:        model.FileStatusEncrypted,
+	}))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a"+EncryptedFileExt), make([]byte, 100), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b"+EncryptedFileExt+partialFileExt), make([]byte, 20), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"+EncryptedFileExt), 0o750))
+
+	cache := &countingResetter{items: 3}
+	svc := NewFactoryResetService(func(ctx context.Context) error {
+		return model.ResetDatabase(db.WithContext(ctx))
+	}, []string{dir, dir + "/", filepath.Join(t.TempDir(), "missing")}, map[string]Resetter{"cache": cache}, newSilentLogger())
+
+	// 토큰 없이는 실행하지 않음
+	_, err := svc.Reset(ctx, "", false)
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+	_, err = svc.Reset(ctx, "guess", false)
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+
+	token, err := svc.IssueToken(ctx)
+	require.NoError(t, err)
+	assert.Len(t, token.Token, 2*factoryResetTokenBytes)
+
+	result, err := svc.Reset(ctx, token.Token, true)
+	require.NoError(t, err)
+	assert.True(t, result.SecureDelete)
+	assert.Equal(t, 2, result.DeletedFiles, "같은 디렉터리는 한 번만 훑음")
+	assert.Equal(t, int64(120), result.DeletedBytes)
+	assert.Empty(t, result.Failed)
+	assert.Equal(t, map[string]int{"cache": 3}, result.ClearedCaches)
+	assert.Equal(t, 1, cache.calls)
+
+	count, err := fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.NoFileExists(t, filepath.Join(dir, "a"+EncryptedFileExt))
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"), "암호화 파일이 아니면 남김")
+	assert.DirExists(t, filepath.Join(dir, "sub"+EncryptedFileExt))
+
+	// 토큰은 한 번만 사용
+	_, err = svc.Reset(ctx, token.Token, false)
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+}
+
+func TestFactoryResetService_TokenRules(t *testing.T) {
+	ctx := context.Background()
+	resets := 0
+	svc := NewFactoryResetService(func(context.Context) error {
+		resets++
+		return nil
+	}, nil, nil, newSilentLogger())
+
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	svc.(*factoryResetService).clock = clk
+
+	// 새로 발급하면 이전 토큰은 무효
+	first, err := svc.IssueToken(ctx)
+	require.NoError(t, err)
+	second, err := svc.IssueToken(ctx)
+	require.NoError(t, err)
+	_, err = svc.Reset(ctx, first.Token, false)
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+
+	// 틀린 토큰을 보내도 발급한 토큰은 유지
+	_, err = svc.Reset(ctx, second.Token, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, resets)
+
+	// 만료된 토큰은 거부하고 소비
+	expired, err := svc.IssueToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(FactoryResetTokenTTL), expired.ExpiresAt)
+	clk.Advance(FactoryResetTokenTTL)
+	_, err = svc.Reset(ctx, expired.Token, false)
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+	assert.Equal(t, 1, resets)
+
+	assert.Panics(t, func() { NewFactoryResetService(nil, nil, nil, newSilentLogger()) })
+	assert.Panics(t, func() {
+		NewFactoryResetService(func(context.Context) error { return nil }, nil, map[string]Resetter{"cache": nil}, newSilentLogger())
+	})
+}
+
+func TestFactoryResetService_DatabaseFailureKeepsFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a"+EncryptedFileExt)
+	require.NoError(t, os.WriteFile(path, []byte("ciphertext"), 0o600))
+
+	cache := &countingResetter{items: 1}
+	svc := NewFactoryResetService(func(context.Context) error {
+		return errors.New("database is locked")
+	}, []string{dir}, map[string]Resetter{"cache": cache}, newSilentLogger())
+
+	token, err := svc.IssueToken(ctx)
+	require.NoError(t, err)
+	_, err = svc.Reset(ctx, token.Token, false)
+	assert.ErrorContains(t, err, "database is locked")
+	assert.FileExists(t, path, "DB 초기화에 실패하면 파일을 지우지 않음")
+	assert.Zero(t, cache.calls)
+}
+
+func TestOverwriteZeros(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a"+EncryptedFileExt)
+	data := make([]byte, secureDeleteChunkSize+10)
+	for i := range data {
+		data[i] = 0xAB
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	require.NoError(t, overwriteZeros(path, int64(len(data))))
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, len(data)), got)
+}
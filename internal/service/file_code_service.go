@@ -0,0 +1,75 @@
+// Package service provides business logic for DataLocker.
+// This file implements lookup and reissue of the short file codes people share by hand.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+)
+
+// 짧은 파일 코드 서비스 에러
+var (
+	// ErrInvalidFileCode 코드가 Crockford Base32 8자로 정규화되지 않음
+	ErrInvalidFileCode = errors.New("잘못된 파일 코드입니다")
+
+	// ErrFileCodeNotFound 코드에 해당하는 살아 있는 파일이 없음 (재발급 전 코드 포함)
+	ErrFileCodeNotFound = errors.New("코드에 해당하는 파일을 찾을 수 없습니다")
+)
+
+// ReissuedFileCode 재발급 결과
+type ReissuedFileCode struct {
+	FileID    uint   `json:"file_id"`
+	ShortCode string `json:"short_code"`
+}
+
+// FileCodeService 짧은 파일 코드 조회와 재발급 서비스
+type FileCodeService interface {
+	// Lookup 코드로 살아 있는 파일을 조회합니다 (대소문자, 하이픈, I/L/O 혼동 허용)
+	Lookup(ctx context.Context, code string) (*model.File, error)
+
+	// Reissue 파일에 새 코드를 부여합니다 (이전 코드는 바로 무효)
+	Reissue(ctx context.Context, fileID uint) (*ReissuedFileCode, error)
+}
+
+// fileCodeService 짧은 파일 코드 서비스 구현체
+type fileCodeService struct {
+	fileRepo repository.FileRepository
+}
+
+// NewFileCodeService 새로운 짧은 파일 코드 서비스를 생성합니다
+func NewFileCodeService(fileRepo repository.FileRepository) FileCodeService {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	return &fileCodeService{fileRepo: fileRepo}
+}
+
+// Lookup 코드로 살아 있는 파일을 조회합니다
+func (s *fileCodeService) Lookup(ctx context.Context, code string) (*model.File, error) {
+	file, err := s.fileRepo.GetByShortCode(ctx, code)
+	switch {
+	case errors.Is(err, repository.ErrInvalidID):
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFileCode, err)
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, ErrFileCodeNotFound
+	case err != nil:
+		return nil, fmt.Errorf("파일 코드 조회 실패: %w", err)
+	}
+
+	return file, nil
+}
+
+// Reissue 파일에 새 코드를 부여합니다
+func (s *fileCodeService) Reissue(ctx context.Context, fileID uint) (*ReissuedFileCode, error) {
+	code, err := s.fileRepo.ReissueShortCode(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("파일 %d 코드 재발급 실패: %w", fileID, err)
+	}
+
+	return &ReissuedFileCode{FileID: fileID, ShortCode: code}, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCodeService_LookupAndReissue(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+	svc := NewFileCodeService(fileRepo)
+
+	file := &model.File{
+		OriginalName:  "shared.txt",
+		EncryptedPath: "/encrypted/shared.enc",
+		Size:          10,
+		MimeType:      "text/plain",
+		ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+		Status:        model.FileStatusEncrypted,
+	}
+	require.NoError(t, fileRepo.Create(ctx, file))
+
+	found, err := svc.Lookup(ctx, strings.ToLower(file.ShortCode))
+	require.NoError(t, err)
+	assert.Equal(t, file.ID, found.ID)
+
+	reissued, err := svc.Reissue(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, file.ID, reissued.FileID)
+	assert.NotEqual(t, file.ShortCode, reissued.ShortCode)
+
+	_, err = svc.Lookup(ctx, file.ShortCode)
+	assert.ErrorIs(t, err, ErrFileCodeNotFound)
+	assert.NotContains(t, err.Error(), file.ShortCode, "없는 코드를 에러에 되풀이하지 않음")
+
+	_, err = svc.Lookup(ctx, "short")
+	assert.ErrorIs(t, err, ErrInvalidFileCode)
+
+	_, err = svc.Reissue(ctx, file.ID+100)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	assert.Panics(t, func() { NewFileCodeService(nil) })
+}
@@ -0,0 +1,227 @@
+// Package service provides business logic for DataLocker.
+// This file implements dependency health checks that run concurrently with per-check timeouts.
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"DataLocker/internal/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 의존 서비스 점검 상태
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusUnhealthy = "unhealthy"
+	HealthStatusTimeout   = "timeout" // 제한 시간 안에 끝나지 않음 (점검은 뒤에서 계속 진행)
+)
+
+// healthTimeoutWarnEvery 연속 타임아웃이 이 횟수의 배수가 될 때마다 경고 로그를 남김
+const healthTimeoutWarnEvery = 3
+
+// HealthCheckFunc 의존 서비스 하나의 상태를 확인합니다
+//
+// ctx는 제한 시간이 지나면 끝나므로 가능한 한 빨리 반환해야 합니다. ctx를 따르지
+// 않는 점검도 응답을 막지는 않지만, 끝날 때까지 같은 점검을 새로 시작하지 않습니다.
+type HealthCheckFunc func(ctx context.Context) error
+
+// HealthCheckResult 의존 서비스 하나의 점검 결과
+type HealthCheckResult struct {
+	Status         string     `json:"status"`
+	Message        string     `json:"message,omitempty"`
+	DurationMillis int64      `json:"duration_ms"`            // 점검에 걸린 시간 (timeout이면 지금까지 진행된 시간)
+	LastSuccess    *time.Time `json:"last_success,omitempty"` // 마지막으로 성공한 시각 (성공한 적이 없으면 없음)
+}
+
+// HealthService 의존 서비스(DB, 저장소 볼륨 등) 점검 서비스
+type HealthService interface {
+	// Check 등록된 점검을 동시에 실행해 이름별 결과를 반환합니다
+	//
+	// 점검마다 제한 시간을 두므로 느린 의존 서비스가 있어도 제한 시간 안에 반환하며,
+	// 넘은 점검은 HealthStatusTimeout으로 보고합니다.
+	Check(ctx context.Context) map[string]HealthCheckResult
+}
+
+// healthService 의존 서비스 점검 서비스 구현체
+type healthService struct {
+	timeout time.Duration
+	checks  []*healthCheck // 이름순
+	logger  *logrus.Logger
+}
+
+// healthCheck 점검 하나와 진행/성공 기록
+type healthCheck struct {
+	name string
+	fn   HealthCheckFunc
+
+	mu          sync.Mutex
+	inflight    *healthRun // 진행 중인 점검 (없으면 nil)
+	lastSuccess time.Time
+	timeouts    int // 연속 타임아웃 횟수
+}
+
+// healthRun 점검 한 번의 실행 (done이 닫힌 뒤에만 err, finished를 읽음)
+type healthRun struct {
+	started  time.Time
+	finished time.Time
+	err      error
+	done     chan struct{}
+}
+
+// NewHealthService 새로운 의존 서비스 점검 서비스를 생성합니다
+//
+// timeout이 0 이하이면 config.DefaultHealthCheckTimeoutMillis를 사용합니다.
+func NewHealthService(timeout time.Duration, checks map[string]HealthCheckFunc, logger *logrus.Logger) HealthService {
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	if timeout <= 0 {
+		timeout = time.Duration(config.DefaultHealthCheckTimeoutMillis) * time.Millisecond
+	}
+
+	s := &healthService{
+		timeout: timeout,
+		checks:  make([]*healthCheck, 0, len(checks)),
+		logger:  logger,
+	}
+	for name, fn := range checks {
+		if fn == nil {
+			panic(fmt.Sprintf("%s 점검 함수가 필요합니다", name))
+		}
+		s.checks = append(s.checks, &healthCheck{name: name, fn: fn})
+	}
+	slices.SortFunc(s.checks, func(a, b *healthCheck) int { return cmp.Compare(a.name, b.name) })
+
+	return s
+}
+
+// Check 점검마다 고루틴에서 결과를 기다리고 모두 모이면 반환합니다
+func (s *healthService) Check(ctx context.Context) map[string]HealthCheckResult {
+	results := make(map[string]HealthCheckResult, len(s.checks))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := s.wait(ctx, check)
+
+			mu.Lock()
+			results[check.name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// wait 진행 중인 점검에 합류하거나 새로 시작해 제한 시간까지만 기다립니다
+func (s *healthService) wait(ctx context.Context, check *healthCheck) HealthCheckResult {
+	run := check.start(s.timeout)
+
+	waitCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	select {
+	case <-run.done:
+	case <-waitCtx.Done():
+		return s.timedOut(check, run, time.Since(run.started))
+	}
+
+	if errors.Is(run.err, context.DeadlineExceeded) {
+		return s.timedOut(check, run, run.finished.Sub(run.started))
+	}
+
+	check.mu.Lock()
+	check.timeouts = 0
+	result := check.resultLocked(HealthStatusHealthy, run.finished.Sub(run.started))
+	check.mu.Unlock()
+
+	if run.err != nil {
+		result.Status = HealthStatusUnhealthy
+		result.Message = run.err.Error()
+	}
+	return result
+}
+
+// timedOut 연속 타임아웃을 세고 반복되면 경고 로그를 남깁니다
+func (s *healthService) timedOut(check *healthCheck, run *healthRun, elapsed time.Duration) HealthCheckResult {
+	check.mu.Lock()
+	check.timeouts++
+	timeouts := check.timeouts
+	result := check.resultLocked(HealthStatusTimeout, elapsed)
+	check.mu.Unlock()
+
+	result.Message = fmt.Sprintf("%s 안에 응답하지 않았습니다", s.timeout)
+
+	if timeouts%healthTimeoutWarnEvery == 0 {
+		fields := logrus.Fields{
+			"check":                check.name,
+			"consecutive_timeouts": timeouts,
+			"timeout":              s.timeout.String(),
+			"running_for":          time.Since(run.started).String(),
+		}
+		if result.LastSuccess != nil {
+			fields["last_success"] = *result.LastSuccess
+		}
+		s.logger.WithFields(fields).Warn("헬스체크가 반복해서 제한 시간을 넘었습니다")
+	}
+
+	return result
+}
+
+// start 진행 중인 점검이 있으면 반환하고, 없으면 새 점검을 시작합니다
+//
+// 점검은 요청과 분리된 컨텍스트로 실행하므로 먼저 온 요청이 끝나도 중단되지 않습니다.
+func (c *healthCheck) start(timeout time.Duration) *healthRun {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.inflight != nil {
+		return c.inflight
+	}
+
+	run := &healthRun{started: time.Now(), done: make(chan struct{})}
+	c.inflight = run
+	go c.execute(run, timeout)
+	return run
+}
+
+// execute 점검을 실행하고 결과를 기록한 뒤 기다리는 요청에 알립니다
+func (c *healthCheck) execute(run *healthRun, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := c.fn(ctx)
+	finished := time.Now()
+
+	c.mu.Lock()
+	run.err, run.finished = err, finished
+	if err == nil {
+		c.lastSuccess = finished
+	}
+	c.inflight = nil
+	c.mu.Unlock()
+
+	close(run.done)
+}
+
+// resultLocked 상태와 소요 시간, 마지막 성공 시각으로 결과를 만듭니다 (c.mu를 잡은 상태에서 호출)
+func (c *healthCheck) resultLocked(status string, elapsed time.Duration) HealthCheckResult {
+	result := HealthCheckResult{Status: status, DurationMillis: elapsed.Milliseconds()}
+	if !c.lastSuccess.IsZero() {
+		lastSuccess := c.lastSuccess
+		result.LastSuccess = &lastSuccess
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthTestTimeout 테스트에서 점검 하나를 기다리는 시간
+const healthTestTimeout = 50 * time.Millisecond
+
+// slowDependency 풀어 줄 때까지 컨텍스트를 무시하고 멈춰 있는 의존 서비스
+type slowDependency struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func newSlowDependency() *slowDependency {
+	return &slowDependency{release: make(chan struct{})}
+}
+
+func (d *slowDependency) check(context.Context) error {
+	d.calls.Add(1)
+	<-d.release
+	return nil
+}
+
+func TestHealthService_PartialTimeout(t *testing.T) {
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+
+	slow := newSlowDependency()
+	svc := NewHealthService(healthTestTimeout, map[string]HealthCheckFunc{
+		"database":   slow.check,
+		"filesystem": func(context.Context) error { return nil },
+		"webhook":    func(context.Context) error { return errors.New("connection refused") },
+	}, logger)
+
+	for i := 1; i <= healthTimeoutWarnEvery; i++ {
+		started := time.Now()
+		results := svc.Check(context.Background())
+		assert.Less(t, time.Since(started), healthTestTimeout+time.Second, "느린 점검이 있어도 제한 시간 안에 반환")
+
+		require.Len(t, results, 3)
+		assert.Equal(t, HealthStatusTimeout, results["database"].Status)
+		assert.GreaterOrEqual(t, results["database"].DurationMillis, healthTestTimeout.Milliseconds())
+		assert.Nil(t, results["database"].LastSuccess)
+
+		assert.Equal(t, HealthStatusHealthy, results["filesystem"].Status)
+		require.NotNil(t, results["filesystem"].LastSuccess)
+
+		assert.Equal(t, HealthStatusUnhealthy, results["webhook"].Status)
+		assert.Equal(t, "connection refused", results["webhook"].Message)
+
+		// 경고는 연속 타임아웃이 반복될 때만
+		assert.Equal(t, i == healthTimeoutWarnEvery, strings.Contains(logs.String(), "level=warning"))
+	}
+
+	// 멈춘 점검이 끝나기 전에는 새로 시작하지 않음
+	assert.Equal(t, int32(1), slow.calls.Load())
+
+	close(slow.release)
+	require.Eventually(t, func() bool {
+		return svc.Check(context.Background())["database"].Status == HealthStatusHealthy
+	}, time.Second, 10*time.Millisecond)
+
+	result := svc.Check(context.Background())["database"]
+	require.NotNil(t, result.LastSuccess)
+	assert.Empty(t, result.Message)
+}
+
+func TestHealthService_CheckHonorsDeadline(t *testing.T) {
+	svc := NewHealthService(healthTestTimeout, map[string]HealthCheckFunc{
+		"database": func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}, newSilentLogger())
+
+	result := svc.Check(context.Background())["database"]
+	assert.Equal(t, HealthStatusTimeout, result.Status)
+	assert.NotEmpty(t, result.Message)
+}
+
+func TestNewHealthService(t *testing.T) {
+	assert.Panics(t, func() { NewHealthService(time.Second, nil, nil) })
+	assert.Panics(t, func() {
+		NewHealthService(time.Second, map[string]HealthCheckFunc{"database": nil}, newSilentLogger())
+	})
+
+	// 점검이 없으면 빈 결과
+	assert.Empty(t, NewHealthService(0, nil, newSilentLogger()).Check(context.Background()))
+}
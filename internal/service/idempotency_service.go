@@ -0,0 +1,211 @@
+// Package service provides business logic for DataLocker.
+// This file implements Idempotency-Key reservation and response replay for retried requests.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 멱등 처리 관련 상수
+const (
+	// IdempotencyKeyTTL 처리한 요청의 응답을 보관하는 기간
+	IdempotencyKeyTTL = 24 * time.Hour
+
+	// IdempotencyPendingTimeout 처리 중인 기록을 버려진 것으로 보는 시간
+	//
+	// 서버가 요청을 처리하다 종료되면 기록이 처리 중으로 남으므로, 이 시간이 지나면
+	// 같은 키로 다시 처리할 수 있게 합니다. 큰 파일의 본문 업로드보다 길어야 합니다.
+	IdempotencyPendingTimeout = time.Hour
+
+	// idempotencyPruneInterval 새 키를 예약할 때 만료된 기록을 정리하는 최소 간격
+	idempotencyPruneInterval = time.Hour
+)
+
+// 멱등 처리 에러
+var (
+	ErrInvalidIdempotencyKey  = errors.New("Idempotency-Key는 1자 이상 255자 이하의 출력 가능한 ASCII 문자여야 합니다")
+	ErrIdempotencyKeyReused   = errors.New("같은 Idempotency-Key로 다른 요청을 보냈습니다")
+	ErrIdempotencyKeyInFlight = errors.New("같은 Idempotency-Key의 요청을 처리 중입니다")
+)
+
+// IdempotencyService Idempotency-Key로 재시도된 요청을 한 번만 처리하는 서비스
+//
+// 키는 요청 컨텍스트의 행위자(model.ActorFromContext)별로 구분합니다.
+type IdempotencyService interface {
+	// Begin 키를 예약하거나, 이미 처리가 끝난 키면 저장된 응답을 반환합니다
+	//
+	// 반환한 기록의 IsCompleted가 false이면 새로 예약한 것이므로 요청을 처리한 뒤
+	// Complete 또는 Release를 호출해야 합니다. 같은 키에 requestHash가 다르면
+	// ErrIdempotencyKeyReused, 아직 처리 중이면 ErrIdempotencyKeyInFlight를 반환합니다.
+	Begin(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, error)
+
+	// Complete 예약한 키에 응답을 저장합니다
+	Complete(ctx context.Context, record *model.IdempotencyRecord, statusCode int, contentType string, body []byte) error
+
+	// Release 응답을 저장하지 않고 예약을 풀어 같은 키로 다시 시도할 수 있게 합니다
+	Release(ctx context.Context, record *model.IdempotencyRecord) error
+
+	// PruneExpired 보관 기간이 지난 기록을 삭제합니다
+	PruneExpired(ctx context.Context) (int64, error)
+}
+
+// idempotencyService 멱등 처리 서비스 구현체
+type idempotencyService struct {
+	repo   repository.IdempotencyRepository
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewIdempotencyService 새로운 멱등 처리 서비스를 생성합니다
+func NewIdempotencyService(repo repository.IdempotencyRepository, logger *logrus.Logger) IdempotencyService {
+	if repo == nil {
+		panic("Idempotency-Key 기록 저장소가 필요합니다")
+	}
+
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	return &idempotencyService{
+		repo:   repo,
+		logger: logger,
+		clock:  clock.Real{},
+	}
+}
+
+// Begin 키를 예약하거나 저장된 응답을 반환합니다
+func (s *idempotencyService) Begin(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, error) {
+	if !isValidIdempotencyKey(key) {
+		return nil, ErrInvalidIdempotencyKey
+	}
+
+	owner := model.ActorFromContext(ctx)
+	now := s.clock.Now()
+
+	existing, err := s.repo.Get(ctx, key, owner)
+	switch {
+	case errors.Is(err, model.ErrRecordNotFound):
+	case err != nil:
+		return nil, fmt.Errorf("Idempotency-Key 확인 실패: %w", err)
+	case s.isLive(existing, now):
+		if existing.RequestHash != requestHash {
+			return nil, ErrIdempotencyKeyReused
+		}
+		if !existing.IsCompleted() {
+			return nil, ErrIdempotencyKeyInFlight
+		}
+		return existing, nil
+	default:
+		// 만료되었거나 버려진 기록은 지우고 새로 예약
+		if err := s.repo.Delete(ctx, existing.ID); err != nil {
+			return nil, fmt.Errorf("만료된 Idempotency-Key 정리 실패: %w", err)
+		}
+	}
+
+	s.pruneOccasionally(ctx, now)
+
+	record := &model.IdempotencyRecord{
+		CreatedAt:   now,
+		Key:         key,
+		Owner:       owner,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(IdempotencyKeyTTL),
+	}
+	if err := s.repo.Create(ctx, record); err != nil {
+		// 동시에 같은 키로 들어온 요청이 먼저 예약함
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrIdempotencyKeyInFlight
+		}
+		return nil, fmt.Errorf("Idempotency-Key 예약 실패: %w", err)
+	}
+
+	return record, nil
+}
+
+// Complete 예약한 키에 응답을 저장합니다
+func (s *idempotencyService) Complete(ctx context.Context, record *model.IdempotencyRecord, statusCode int, contentType string, body []byte) error {
+	if err := s.repo.Complete(ctx, record.ID, statusCode, contentType, body); err != nil {
+		return fmt.Errorf("Idempotency-Key 응답 저장 실패: %w", err)
+	}
+
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = body
+	return nil
+}
+
+// Release 예약을 풉니다
+func (s *idempotencyService) Release(ctx context.Context, record *model.IdempotencyRecord) error {
+	if err := s.repo.Delete(ctx, record.ID); err != nil {
+		return fmt.Errorf("Idempotency-Key 예약 해제 실패: %w", err)
+	}
+	return nil
+}
+
+// PruneExpired 보관 기간이 지난 기록을 삭제합니다
+func (s *idempotencyService) PruneExpired(ctx context.Context) (int64, error) {
+	deleted, err := s.repo.DeleteExpired(ctx, s.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if deleted > 0 {
+		s.logger.WithField("records", deleted).Debug("만료된 Idempotency-Key 기록을 정리했습니다")
+	}
+	return deleted, nil
+}
+
+// pruneOccasionally 마지막 정리 후 idempotencyPruneInterval이 지났으면 만료된 기록을 정리합니다
+//
+// 정리 실패는 예약을 막지 않습니다.
+func (s *idempotencyService) pruneOccasionally(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if !s.lastPrune.IsZero() && now.Sub(s.lastPrune) < idempotencyPruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	if _, err := s.PruneExpired(ctx); err != nil {
+		s.logger.WithError(err).Warn("만료된 Idempotency-Key 기록 정리에 실패했습니다")
+	}
+}
+
+// isLive 보관 기간 안이고, 처리 중이라면 버려지지 않은 기록인지 확인합니다
+func (s *idempotencyService) isLive(record *model.IdempotencyRecord, now time.Time) bool {
+	if !now.Before(record.ExpiresAt) {
+		return false
+	}
+	if !record.IsCompleted() && !now.Before(record.CreatedAt.Add(IdempotencyPendingTimeout)) {
+		return false
+	}
+	return true
+}
+
+// isValidIdempotencyKey 키가 길이 제한 안의 출력 가능한 ASCII 문자로만 이루어졌는지 확인합니다
+func isValidIdempotencyKey(key string) bool {
+	if key == "" || len(key) > model.MaxIdempotencyKeyLength {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
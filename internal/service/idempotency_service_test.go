@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupIdempotencyTest 시각을 조정할 수 있는 멱등 처리 서비스를 생성합니다
+func setupIdempotencyTest(t *testing.T) (*idempotencyService, repository.IdempotencyRepository, *clock.Fake) {
+	t.Helper()
+	repo := repository.NewIdempotencyRepository(setupServiceTestDB(t))
+	svc := NewIdempotencyService(repo, newSilentLogger()).(*idempotencyService)
+
+	clk := clock.NewFake(time.Now())
+	svc.clock = clk
+	return svc, repo, clk
+}
+
+func TestIdempotencyService_Replay(t *testing.T) {
+	svc, _, clk := setupIdempotencyTest(t)
+	ctx := model.WithActor(context.Background(), model.ActorAnonymous)
+
+	record, err := svc.Begin(ctx, "key-1", "hash-a")
+	require.NoError(t, err)
+	assert.False(t, record.IsCompleted())
+	assert.Equal(t, model.ActorAnonymous, record.Owner)
+
+	// 처리 중에는 같은 요청도 거부
+	_, err = svc.Begin(ctx, "key-1", "hash-a")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyInFlight)
+
+	require.NoError(t, svc.Complete(ctx, record, 201, "application/json", []byte(`{"id":1}`)))
+
+	clk.Advance(IdempotencyKeyTTL - time.Second)
+	replay, err := svc.Begin(ctx, "key-1", "hash-a")
+	require.NoError(t, err)
+	require.True(t, replay.IsCompleted())
+	assert.Equal(t, 201, replay.StatusCode)
+	assert.Equal(t, []byte(`{"id":1}`), replay.Body)
+
+	// 같은 키에 다른 내용
+	_, err = svc.Begin(ctx, "key-1", "hash-b")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+
+	// 행위자가 다르면 별도 키
+	other, err := svc.Begin(model.WithActor(context.Background(), model.ActorAdmin), "key-1", "hash-b")
+	require.NoError(t, err)
+	assert.False(t, other.IsCompleted())
+
+	// 보관 기간이 지나면 새로 예약
+	clk.Advance(time.Second)
+	record, err = svc.Begin(ctx, "key-1", "hash-b")
+	require.NoError(t, err)
+	assert.False(t, record.IsCompleted())
+}
+
+func TestIdempotencyService_ReleaseAndAbandoned(t *testing.T) {
+	svc, _, clk := setupIdempotencyTest(t)
+	ctx := model.WithActor(context.Background(), model.ActorAnonymous)
+
+	// 예약을 풀면 같은 키로 바로 다시 시도
+	record, err := svc.Begin(ctx, "key-1", "hash-a")
+	require.NoError(t, err)
+	require.NoError(t, svc.Release(ctx, record))
+	record, err = svc.Begin(ctx, "key-1", "hash-a")
+	require.NoError(t, err)
+
+	// 처리 중으로 남은 기록은 시간이 지나면 버려진 것으로 보고 다시 예약
+	clk.Advance(IdempotencyPendingTimeout - time.Second)
+	_, err = svc.Begin(ctx, "key-1", "hash-a")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyInFlight)
+
+	clk.Advance(time.Second)
+	retried, err := svc.Begin(ctx, "key-1", "hash-a")
+	require.NoError(t, err)
+	assert.NotEqual(t, record.ID, retried.ID)
+
+	// 버려진 예약의 늦은 응답은 저장하지 않음
+	assert.ErrorIs(t, svc.Complete(ctx, record, 200, "application/json", nil), model.ErrRecordNotFound)
+}
+
+func TestIdempotencyService_PruneExpired(t *testing.T) {
+	svc, repo, clk := setupIdempotencyTest(t)
+	ctx := model.WithActor(context.Background(), model.ActorAnonymous)
+
+	_, err := svc.Begin(ctx, "old", "hash")
+	require.NoError(t, err)
+
+	clk.Advance(IdempotencyKeyTTL)
+	deleted, err := svc.PruneExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	// 새 키를 예약할 때도 간격마다 정리
+	_, err = svc.Begin(ctx, "second", "hash")
+	require.NoError(t, err)
+	clk.Advance(IdempotencyKeyTTL + idempotencyPruneInterval)
+	_, err = svc.Begin(ctx, "third", "hash")
+	require.NoError(t, err)
+
+	_, err = repo.Get(ctx, "second", model.ActorAnonymous)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+}
+
+func TestIdempotencyService_InvalidKey(t *testing.T) {
+	svc, _, _ := setupIdempotencyTest(t)
+	ctx := context.Background()
+
+	for name, key := range map[string]string{
+		"빈 키":    "",
+		"공백 포함":  "key 1",
+		"비ASCII": "키",
+		"너무 김":   string(make([]byte, model.MaxIdempotencyKeyLength+1)),
+	} {
+		_, err := svc.Begin(ctx, key, "hash")
+		assert.ErrorIs(t, err, ErrInvalidIdempotencyKey, name)
+	}
+
+	assert.Panics(t, func() { NewIdempotencyService(nil, newSilentLogger()) })
+}
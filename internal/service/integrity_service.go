@@ -0,0 +1,143 @@
+// Package service provides business logic for DataLocker.
+// This file implements integrity verification of stored encrypted files.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/crypto"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 무결성 검사 에러
+var (
+	ErrIntegrityPasswordMismatch = errors.New("패스워드가 일치하지 않아 무결성을 검사할 수 없습니다")
+)
+
+// IntegrityResult 파일 하나의 무결성 검사 결과
+type IntegrityResult struct {
+	FileID uint   `json:"file_id"`
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// IntegrityService 저장된 암호화 파일의 무결성을 검사하는 서비스
+type IntegrityService interface {
+	// VerifyFile 청크 인증 태그와 MAC 트레일러를 검사하고, 손상 시 corrupted로 표시합니다
+	//
+	// 평문은 메모리에 올리지 않고 버립니다. 패스워드가 틀린 경우는 손상과 구분할 수
+	// 없으므로 상태를 바꾸지 않고 ErrIntegrityPasswordMismatch를 반환합니다. 볼륨이
+	// 오프라인이어도 상태를 바꾸지 않고 ErrVolumeOffline을 반환합니다.
+	VerifyFile(ctx context.Context, fileID uint, password string) (*IntegrityResult, error)
+}
+
+// integrityService 무결성 검사 서비스 구현체
+type integrityService struct {
+	fileRepo repository.FileRepository
+	storage  StorageService
+	logger   *logrus.Logger
+}
+
+// NewIntegrityService 새로운 무결성 검사 서비스를 생성합니다
+func NewIntegrityService(fileRepo repository.FileRepository, storage StorageService, logger *logrus.Logger) IntegrityService {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	if storage == nil {
+		panic("저장소 볼륨 서비스가 필요합니다")
+	}
+
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	return &integrityService{
+		fileRepo: fileRepo,
+		storage:  storage,
+		logger:   logger,
+	}
+}
+
+// VerifyFile 파일의 암호화 blob을 검사합니다
+func (s *integrityService) VerifyFile(ctx context.Context, fileID uint, password string) (*IntegrityResult, error) {
+	if password == "" {
+		return nil, errors.New("패스워드가 필요합니다")
+	}
+
+	file, err := s.fileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("파일 조회 실패: %w", err)
+	}
+
+	if !file.IsEncrypted() && !file.IsCorrupted() {
+		return nil, fmt.Errorf("암호화가 완료되지 않은 파일입니다: 상태 %s", file.Status)
+	}
+
+	// 참조 레코드는 원본 blob을 검사 (원본이 소프트 삭제되어도 blob은 남아 있음)
+	blob := file
+	if file.IsBlobReference() {
+		if blob, err = s.fileRepo.GetByIDWithDeleted(ctx, *file.BlobFileID); err != nil {
+			return nil, fmt.Errorf("원본 blob 조회 실패: %w", err)
+		}
+	}
+
+	// 볼륨이 오프라인이면 손상으로 표시하지 않고 에러를 반환
+	path, err := s.storage.Locate(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	verifyErr := verifyBlob(path, password)
+	if verifyErr == nil {
+		return &IntegrityResult{FileID: file.ID, Valid: true}, nil
+	}
+
+	if errors.Is(verifyErr, crypto.ErrWrongPassword) {
+		return nil, ErrIntegrityPasswordMismatch
+	}
+
+	// 손상된 blob과 이를 검사 요청한 레코드를 corrupted로 표시
+	for _, target := range uniqueFiles(file, blob) {
+		if target.IsCorrupted() {
+			continue
+		}
+		if err := s.fileRepo.UpdateStatus(ctx, target.ID, model.FileStatusCorrupted); err != nil {
+			return nil, fmt.Errorf("손상 상태 기록 실패: %w", err)
+		}
+		target.MarkAsCorrupted()
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"file_id": file.ID,
+		"blob_id": blob.ID,
+		"reason":  verifyErr.Error(),
+	}).Warn("암호화 파일 무결성 검사에 실패했습니다")
+
+	return &IntegrityResult{FileID: file.ID, Valid: false, Reason: verifyErr.Error()}, nil
+}
+
+// verifyBlob 디스크의 암호화 파일을 검사합니다
+func verifyBlob(path, password string) error {
+	src, err := os.Open(path) //nolint:gosec // 저장소에 기록된 경로
+	if err != nil {
+		return fmt.Errorf("암호화 파일 열기 실패: %w", err)
+	}
+	defer src.Close()
+
+	return crypto.VerifyStream(src, password)
+}
+
+// uniqueFiles 같은 레코드가 중복되지 않도록 목록을 만듭니다
+func uniqueFiles(file, blob *model.File) []*model.File {
+	if file.ID == blob.ID {
+		return []*model.File{file}
+	}
+	return []*model.File{file, blob}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/crypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const integrityTestPassword = "integrity-password"
+
+// setupIntegrityTest 암호화 파일 하나가 저장된 무결성 검사 서비스를 구성합니다
+func setupIntegrityTest(t *testing.T) (IntegrityService, repository.FileRepository, *model.File) {
+	t.Helper()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+	file := createIntegrityTarget(t, fileRepo)
+
+	return NewIntegrityService(fileRepo, newDirStorage(t, filepath.Dir(file.EncryptedPath), fileRepo), newSilentLogger()), fileRepo, file
+}
+
+// createIntegrityTarget 암호화본만 있고 메타데이터 레코드는 없는 파일을 생성합니다
+func createIntegrityTarget(t *testing.T, fileRepo repository.FileRepository) *model.File {
+	t.Helper()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "blob"+EncryptedFileExt)
+	dst, err := os.Create(path)
+	require.NoError(t, err)
+	encWriter, err := crypto.NewEncryptWriter(dst, integrityTestPassword)
+	require.NoError(t, err)
+	_, err = encWriter.Write([]byte("integrity scan target"))
+	require.NoError(t, err)
+	require.NoError(t, encWriter.Close())
+	require.NoError(t, dst.Close())
+
+	file := &model.File{
+		OriginalName:  "target.txt",
+		EncryptedPath: path,
+		Size:          21,
+		MimeType:      "text/plain",
+		ChecksumMD5:   md5Hex([]byte("integrity scan target")),
+		Status:        model.FileStatusEncrypted,
+	}
+	require.NoError(t, fileRepo.Create(ctx, file))
+
+	return file
+}
+
+// tamperFile 파일의 마지막 바이트를 변조합니다
+func tamperFile(t *testing.T, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[len(data)-1] ^= 0x01
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestIntegrityService_Valid(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, file := setupIntegrityTest(t)
+
+	result, err := svc.VerifyFile(context.Background(), file.ID, integrityTestPassword)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	stored, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.IsEncrypted())
+}
+
+func TestIntegrityService_MarksCorrupted(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, file := setupIntegrityTest(t)
+	tamperFile(t, file.EncryptedPath)
+
+	result, err := svc.VerifyFile(context.Background(), file.ID, integrityTestPassword)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.NotEmpty(t, result.Reason)
+
+	stored, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.IsCorrupted())
+}
+
+func TestIntegrityService_VolumeOffline(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, file := setupIntegrityTest(t)
+
+	// 설정에 없는 볼륨의 파일은 손상으로 표시하지 않음
+	file.VolumeID = "unmounted"
+	require.NoError(t, fileRepo.Update(ctx, file))
+
+	_, err := svc.VerifyFile(context.Background(), file.ID, integrityTestPassword)
+	assert.ErrorIs(t, err, ErrVolumeOffline)
+
+	stored, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.IsEncrypted())
+}
+
+func TestIntegrityService_BlobReference(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, source := setupIntegrityTest(t)
+
+	reference := &model.File{
+		OriginalName:  "copy.txt",
+		EncryptedPath: "ref/copy",
+		Size:          source.Size,
+		MimeType:      source.MimeType,
+		ChecksumMD5:   source.ChecksumMD5,
+		Status:        model.FileStatusEncrypted,
+		BlobFileID:    &source.ID,
+	}
+	require.NoError(t, fileRepo.Create(ctx, reference))
+	tamperFile(t, source.EncryptedPath)
+
+	result, err := svc.VerifyFile(context.Background(), reference.ID, integrityTestPassword)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+
+	for _, id := range []uint{source.ID, reference.ID} {
+		stored, getErr := fileRepo.GetByID(ctx, id)
+		require.NoError(t, getErr)
+		assert.True(t, stored.IsCorrupted(), "file %d", id)
+	}
+}
+
+func TestIntegrityService_WrongPassword(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, file := setupIntegrityTest(t)
+
+	_, err := svc.VerifyFile(context.Background(), file.ID, "wrong-password")
+	assert.ErrorIs(t, err, ErrIntegrityPasswordMismatch)
+
+	// 패스워드 오류는 손상으로 기록하지 않음
+	stored, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.IsEncrypted())
+}
@@ -0,0 +1,275 @@
+// Package service provides business logic for DataLocker.
+// This file implements daily metrics snapshots and the scheduler that records them at UTC midnight.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidMetricsDays 조회/backfill 기간이 1일 이상 보존 기간 이하가 아님
+var ErrInvalidMetricsDays = errors.New("기간은 1일 이상 스냅샷 보존 기간 이하여야 합니다")
+
+// MetricsBackfillResult 누락일 backfill 결과
+type MetricsBackfillResult struct {
+	From     string   `json:"from"` // 대상 기간 첫날 (포함)
+	To       string   `json:"to"`   // 대상 기간 마지막 날 (어제, 포함)
+	Created  []string `json:"created_dates"`
+	Existing int      `json:"existing"` // 이미 스냅샷이 있어 건너뛴 날 수
+}
+
+// MetricsService 일별 통계 스냅샷 서비스 인터페이스
+//
+// 날짜는 모두 UTC 기준이며, 끝나지 않은 오늘은 집계하지 않습니다.
+type MetricsService interface {
+	// RecordDay day가 속한 날의 통계를 집계해 저장합니다 (이미 있으면 저장하지 않고 false)
+	RecordDay(ctx context.Context, day time.Time) (bool, error)
+
+	// History 어제까지 최근 days일의 스냅샷을 날짜순으로 반환합니다 (0이면 기본 기간)
+	//
+	// 스냅샷이 없는 날은 채우지 않으므로 결과가 days개보다 적을 수 있습니다.
+	History(ctx context.Context, days int) ([]*model.MetricsSnapshot, error)
+
+	// Backfill 어제까지 최근 days일 중 스냅샷이 없는 날을 집계해 저장합니다 (0이면 기본 기간)
+	//
+	// 지난 날짜는 현재 남아 있는 레코드로 다시 집계하므로, 그 사이 영구 삭제된
+	// 파일이나 바뀐 상태는 반영되지 않습니다.
+	Backfill(ctx context.Context, days int) (*MetricsBackfillResult, error)
+
+	// Prune 보존 기간이 지난 스냅샷을 삭제하고 삭제한 수를 반환합니다
+	Prune(ctx context.Context) (int64, error)
+}
+
+// metricsService 일별 통계 스냅샷 서비스 구현체
+type metricsService struct {
+	repo          repository.MetricsRepository
+	retentionDays int
+	clock         clock.Clock
+}
+
+// NewMetricsService 새로운 일별 통계 스냅샷 서비스를 생성합니다
+//
+// 보존 기간이 0 이하이면 config.DefaultMetricsRetentionDays를 사용합니다.
+func NewMetricsService(cfg config.MetricsConfig, repo repository.MetricsRepository) MetricsService {
+	if repo == nil {
+		panic("일별 통계 스냅샷 저장소가 필요합니다")
+	}
+
+	retentionDays := cfg.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = config.DefaultMetricsRetentionDays
+	}
+
+	return &metricsService{
+		repo:          repo,
+		retentionDays: retentionDays,
+		clock:         clock.Real{},
+	}
+}
+
+// RecordDay 집계 후 날짜 유일 조건으로 저장합니다
+func (s *metricsService) RecordDay(ctx context.Context, day time.Time) (bool, error) {
+	snapshot, err := s.repo.Collect(ctx, day)
+	if err != nil {
+		return false, err
+	}
+
+	return s.repo.CreateIfAbsent(ctx, snapshot)
+}
+
+// History 기간 첫날 이후 스냅샷을 조회합니다
+func (s *metricsService) History(ctx context.Context, days int) ([]*model.MetricsSnapshot, error) {
+	from, _, err := s.window(days)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListSince(ctx, from.Format(model.MetricsDateLayout))
+}
+
+// Backfill 기간의 날짜 중 저장된 날짜를 빼고 하루씩 집계합니다
+func (s *metricsService) Backfill(ctx context.Context, days int) (*MetricsBackfillResult, error) {
+	from, to, err := s.window(days)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MetricsBackfillResult{
+		From:    from.Format(model.MetricsDateLayout),
+		To:      to.Format(model.MetricsDateLayout),
+		Created: make([]string, 0),
+	}
+
+	existing, err := s.repo.ListDates(ctx, result.From, result.To)
+	if err != nil {
+		return nil, err
+	}
+	recorded := make(map[string]bool, len(existing))
+	for _, date := range existing {
+		recorded[date] = true
+	}
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(model.MetricsDateLayout)
+		if recorded[date] {
+			result.Existing++
+			continue
+		}
+
+		created, err := s.RecordDay(ctx, day)
+		if err != nil {
+			return nil, fmt.Errorf("%s 스냅샷 저장 실패: %w", date, err)
+		}
+		if created {
+			result.Created = append(result.Created, date)
+		} else {
+			// 그 사이 스케줄러가 먼저 저장함
+			result.Existing++
+		}
+	}
+
+	return result, nil
+}
+
+// Prune 오늘 기준 보존 기간 이전 날짜의 스냅샷을 삭제합니다
+func (s *metricsService) Prune(ctx context.Context) (int64, error) {
+	cutoff := s.today().AddDate(0, 0, -s.retentionDays)
+	return s.repo.DeleteBefore(ctx, cutoff.Format(model.MetricsDateLayout))
+}
+
+// window 어제로 끝나는 days일 기간의 첫날과 마지막 날을 반환합니다
+func (s *metricsService) window(days int) (from, to time.Time, err error) {
+	if days == 0 {
+		days = config.DefaultMetricsHistoryDays
+	}
+	if days < 1 || days > s.retentionDays {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %d일 (최대 %d일)", ErrInvalidMetricsDays, days, s.retentionDays)
+	}
+
+	to = s.today().AddDate(0, 0, -1)
+	return to.AddDate(0, 0, 1-days), to, nil
+}
+
+// today 오늘(UTC) 0시를 반환합니다
+func (s *metricsService) today() time.Time {
+	return startOfDayUTC(s.clock.Now())
+}
+
+// startOfDayUTC t가 속한 UTC 날짜의 0시를 반환합니다
+func startOfDayUTC(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// MetricsScheduler 매일 자정(UTC)에 전날 스냅샷을 저장하고 오래된 스냅샷을 정리하는 스케줄러
+//
+// 시작할 때도 한 번 실행하므로 자정에 서버가 꺼져 있었어도 재시작하면 어제
+// 스냅샷이 저장됩니다. 그보다 오래된 누락일은 Backfill로 채웁니다.
+type MetricsScheduler struct {
+	metrics MetricsService
+	logger  *logrus.Logger
+	clock   clock.Clock
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	mu        sync.Mutex
+	started   bool
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewMetricsScheduler 새로운 일별 통계 스케줄러를 생성합니다 (Start로 시작)
+func NewMetricsScheduler(metrics MetricsService, logger *logrus.Logger) *MetricsScheduler {
+	if metrics == nil {
+		panic("일별 통계 스냅샷 서비스가 필요합니다")
+	}
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	return &MetricsScheduler{
+		metrics: metrics,
+		logger:  logger,
+		clock:   clock.Real{},
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start 스케줄러 고루틴을 시작합니다 (두 번째 호출부터는 무시)
+func (s *MetricsScheduler) Start() {
+	s.startOnce.Do(func() {
+		s.mu.Lock()
+		s.started = true
+		s.mu.Unlock()
+		go s.run()
+	})
+}
+
+// Stop 스케줄러를 멈추고 진행 중인 저장이 끝날 때까지 기다립니다 (여러 번 호출해도 안전)
+func (s *MetricsScheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+	if started {
+		<-s.done
+	}
+}
+
+// run 시작 시 한 번, 이후 매일 자정마다 전날 스냅샷을 저장합니다
+func (s *MetricsScheduler) run() {
+	defer close(s.done)
+
+	s.runOnce(context.Background(), startOfDayUTC(s.clock.Now()).AddDate(0, 0, -1))
+
+	for {
+		// 타이머가 자정보다 조금 일찍 깨어나도 같은 날짜를 저장하도록 대상을 미리 정함
+		next := startOfDayUTC(s.clock.Now()).AddDate(0, 0, 1)
+		timer := s.clock.NewTimer(next.Sub(s.clock.Now()))
+		select {
+		case <-timer.C():
+			s.runOnce(context.Background(), next.AddDate(0, 0, -1))
+		case <-s.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// runOnce day의 스냅샷을 저장하고 보존 기간이 지난 스냅샷을 정리합니다
+//
+// 실패는 로그만 남기고 다음 자정에 다시 시도합니다.
+func (s *MetricsScheduler) runOnce(ctx context.Context, day time.Time) {
+	entry := s.logger.WithField("date", day.Format(model.MetricsDateLayout))
+
+	created, err := s.metrics.RecordDay(ctx, day)
+	switch {
+	case err != nil:
+		entry.WithError(err).Error("일별 통계 스냅샷 저장에 실패했습니다")
+	case created:
+		entry.Info("일별 통계 스냅샷을 저장했습니다")
+	default:
+		entry.Debug("이미 저장된 일별 통계 스냅샷입니다")
+	}
+
+	pruned, err := s.metrics.Prune(ctx)
+	if err != nil {
+		s.logger.WithError(err).Warn("보존 기간이 지난 통계 스냅샷 정리에 실패했습니다")
+	} else if pruned > 0 {
+		s.logger.WithField("pruned", pruned).Info("보존 기간이 지난 통계 스냅샷을 정리했습니다")
+	}
+}
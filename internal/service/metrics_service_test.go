@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricsTestNow 테스트의 현재 시각 (UTC 2024-03-10 오후)
+var metricsTestNow = time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+
+// newTestMetricsService 현재 시각을 고정한 일별 통계 서비스와 저장소를 생성합니다
+func newTestMetricsService(t *testing.T, retentionDays int) (*metricsService, repository.MetricsRepository, repository.FileRepository) {
+	t.Helper()
+
+	db := setupServiceTestDB(t)
+	repo := repository.NewMetricsRepository(db)
+	svc := NewMetricsService(config.MetricsConfig{RetentionDays: retentionDays}, repo).(*metricsService)
+	svc.clock = clock.NewFake(metricsTestNow)
+	return svc, repo, repository.NewFileRepository(db)
+}
+
+func TestMetricsService_BackfillAndHistory(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, files := newTestMetricsService(t, 10)
+
+	// 3/7, 3/9에 하나씩, 오늘(3/10)에 하나 업로드
+	for i, day := range []int{7, 9, 10} {
+		require.NoError(t, files.Create(ctx, &model.File{
+			CreatedAt:     time.Date(2024, 3, day, 9, 0, 0, 0, time.UTC),
+			OriginalName:  fmt.Sprintf("metrics_%d", i),
+			EncryptedPath: fmt.Sprintf("/encrypted/metrics_%d.enc", i),
+			Size:          100,
+			MimeType:      "text/plain",
+			ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+			Status:        model.FileStatusEncrypted,
+		}))
+	}
+
+	// 스케줄러가 이미 저장한 날은 건너뜀
+	created, err := svc.RecordDay(ctx, time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	result, err := svc.Backfill(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-07", result.From)
+	assert.Equal(t, "2024-03-09", result.To, "끝나지 않은 오늘은 제외")
+	assert.Equal(t, []string{"2024-03-07", "2024-03-09"}, result.Created)
+	assert.Equal(t, 1, result.Existing)
+
+	// 다시 실행해도 중복 저장하지 않음
+	result, err = svc.Backfill(ctx, 3)
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Equal(t, 3, result.Existing)
+
+	history, err := svc.History(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "2024-03-08", history[0].Date)
+	assert.Equal(t, int64(1), history[0].TotalFiles)
+	assert.Zero(t, history[0].Uploads)
+	assert.Equal(t, "2024-03-09", history[1].Date)
+	assert.Equal(t, int64(2), history[1].TotalFiles)
+	assert.Equal(t, int64(200), history[1].TotalBytes)
+	assert.Equal(t, int64(1), history[1].Uploads)
+
+	// 0이면 기본 기간 (보존 기간보다 길면 거부)
+	for _, days := range []int{-1, 11} {
+		_, err = svc.History(ctx, days)
+		assert.ErrorIs(t, err, ErrInvalidMetricsDays)
+		_, err = svc.Backfill(ctx, days)
+		assert.ErrorIs(t, err, ErrInvalidMetricsDays)
+	}
+	_, err = svc.History(ctx, 0)
+	assert.ErrorIs(t, err, ErrInvalidMetricsDays, "기본 30일이 보존 기간 10일을 넘음")
+
+	// 보존 기간(어제까지 10일)이 지난 스냅샷만 정리
+	for _, date := range []string{"2024-02-28", "2024-02-29"} {
+		_, err = repo.CreateIfAbsent(ctx, &model.MetricsSnapshot{Date: date})
+		require.NoError(t, err)
+	}
+	pruned, err := svc.Prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+}
+
+func TestMetricsScheduler_StartRecordsYesterday(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newTestMetricsService(t, 0)
+	_, err := repo.CreateIfAbsent(ctx, &model.MetricsSnapshot{Date: "2023-01-01"})
+	require.NoError(t, err)
+
+	scheduler := NewMetricsScheduler(svc, newSilentLogger())
+	scheduler.clock = svc.clock
+	scheduler.Start()
+	scheduler.Stop()
+	scheduler.Stop()
+
+	snapshots, err := repo.ListSince(ctx, "2000-01-01")
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1, "보존 기간이 지난 스냅샷은 정리")
+	assert.Equal(t, "2024-03-09", snapshots[0].Date)
+
+	// 시작하지 않은 스케줄러도 바로 멈춤
+	NewMetricsScheduler(svc, newSilentLogger()).Stop()
+}
+
+func TestMetricsScheduler_RecordsAtMidnight(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newTestMetricsService(t, 0)
+	clk := clock.NewFake(metricsTestNow)
+	svc.clock = clk
+
+	scheduler := NewMetricsScheduler(svc, newSilentLogger())
+	scheduler.clock = clk
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	// 시작하면서 어제 것을 저장하고 다음 자정까지 기다림
+	clk.BlockUntil(1)
+	clk.Advance(9*time.Hour - time.Second)
+	assert.Equal(t, 1, clk.Timers(), "자정 전에는 깨어나지 않음")
+
+	// 자정이 지나면 전날 스냅샷을 저장하고 다음 자정 타이머를 다시 검
+	for day := 0; day < 2; day++ {
+		clk.Advance(24 * time.Hour)
+		clk.BlockUntil(1)
+	}
+
+	snapshots, err := repo.ListSince(ctx, "2000-01-01")
+	require.NoError(t, err)
+	dates := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		dates = append(dates, snapshot.Date)
+	}
+	assert.Equal(t, []string{"2024-03-09", "2024-03-10", "2024-03-11"}, dates)
+}
+
+func TestNewMetricsService(t *testing.T) {
+	assert.Panics(t, func() { NewMetricsService(config.MetricsConfig{}, nil) })
+	assert.Panics(t, func() { NewMetricsScheduler(nil, newSilentLogger()) })
+}
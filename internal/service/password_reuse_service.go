@@ -0,0 +1,134 @@
+// Package service provides business logic for DataLocker.
+// This file implements opt-in password reuse detection via password fingerprints.
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/crypto"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrFingerprintSaltRequired 지문 기능 활성 시 전용 salt가 필요함
+var ErrFingerprintSaltRequired = errors.New("패스워드 지문 salt(hex)가 필요합니다")
+
+// PasswordReuseWarning 패스워드 재사용 경고 (업로드 응답에 포함)
+type PasswordReuseWarning struct {
+	ReuseCount int64  `json:"reuse_count"` // 같은 패스워드로 암호화된 기존 파일 수
+	Message    string `json:"message"`
+}
+
+// PasswordReuseService 패스워드 재사용 감지 서비스
+type PasswordReuseService interface {
+	// Enabled 지문 저장 기능이 켜져 있는지 반환합니다
+	Enabled() bool
+
+	// Fingerprint 패스워드 지문을 계산합니다 (비활성 시 빈 문자열)
+	//
+	// 패스워드를 복사하지 않으므로 호출자가 사용 후 crypto.ZeroBytes로 지워야 합니다.
+	Fingerprint(password []byte) (string, error)
+
+	// CheckReuse 같은 지문이 임계값 이상이면 경고를 반환합니다 (아니면 nil)
+	CheckReuse(ctx context.Context, fingerprint string) (*PasswordReuseWarning, error)
+}
+
+// passwordReuseService 패스워드 재사용 감지 서비스 구현체
+type passwordReuseService struct {
+	enabled   bool
+	salt      []byte
+	threshold int64
+	encRepo   repository.EncryptionRepository
+	logger    *logrus.Logger
+}
+
+// NewPasswordReuseService 새로운 패스워드 재사용 감지 서비스를 생성합니다
+func NewPasswordReuseService(
+	cfg config.SecurityConfig,
+	encRepo repository.EncryptionRepository,
+	logger *logrus.Logger,
+) (PasswordReuseService, error) {
+	if encRepo == nil || logger == nil {
+		return nil, fmt.Errorf("패스워드 재사용 감지 서비스 의존성이 필요합니다")
+	}
+
+	svc := &passwordReuseService{
+		enabled:   cfg.PasswordFingerprintEnabled,
+		threshold: int64(cfg.PasswordReuseThreshold),
+		encRepo:   encRepo,
+		logger:    logger,
+	}
+
+	if !svc.enabled {
+		return svc, nil
+	}
+
+	salt, err := hex.DecodeString(cfg.PasswordFingerprintSalt)
+	if err != nil || len(salt) < crypto.FingerprintMinSaltSize {
+		return nil, ErrFingerprintSaltRequired
+	}
+	svc.salt = salt
+
+	if svc.threshold <= 0 {
+		svc.threshold = config.DefaultPasswordReuseThreshold
+	}
+
+	// 감사 로그: 패스워드 파생 정보가 저장됨을 기록
+	logger.WithFields(logrus.Fields{
+		"audit":     "privacy",
+		"feature":   "password_fingerprint",
+		"threshold": svc.threshold,
+	}).Warn("패스워드 지문 저장이 활성화되었습니다 (재사용 감지 목적, 원문 복원 불가)")
+
+	return svc, nil
+}
+
+// Enabled 지문 저장 기능이 켜져 있는지 반환합니다
+func (s *passwordReuseService) Enabled() bool {
+	return s.enabled
+}
+
+// Fingerprint 패스워드 지문을 계산합니다
+func (s *passwordReuseService) Fingerprint(password []byte) (string, error) {
+	if !s.enabled {
+		return "", nil
+	}
+
+	fingerprint, err := crypto.PasswordFingerprintBytes(password, s.salt)
+	if err != nil {
+		return "", fmt.Errorf("패스워드 지문 계산 실패: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"audit":   "privacy",
+		"feature": "password_fingerprint",
+	}).Info("패스워드 지문을 계산했습니다")
+
+	return fingerprint, nil
+}
+
+// CheckReuse 같은 지문이 임계값 이상이면 경고를 반환합니다
+func (s *passwordReuseService) CheckReuse(ctx context.Context, fingerprint string) (*PasswordReuseWarning, error) {
+	if !s.enabled || fingerprint == "" {
+		return nil, nil
+	}
+
+	count, err := s.encRepo.CountByPasswordFingerprint(ctx, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("패스워드 재사용 확인 실패: %w", err)
+	}
+
+	if count < s.threshold {
+		return nil, nil
+	}
+
+	return &PasswordReuseWarning{
+		ReuseCount: count,
+		Message:    fmt.Sprintf("같은 패스워드가 다른 파일 %d개에 이미 사용되었습니다", count),
+	}, nil
+}
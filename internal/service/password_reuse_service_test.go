@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 테스트용 지문 salt (16바이트 hex)
+const testFingerprintSaltHex = "00112233445566778899aabbccddeeff"
+
+func TestNewPasswordReuseService_Validation(t *testing.T) {
+	encRepo := repository.NewEncryptionRepository(setupServiceTestDB(t))
+
+	// 비활성 시 salt 불필요
+	svc, err := NewPasswordReuseService(config.SecurityConfig{}, encRepo, newSilentLogger())
+	require.NoError(t, err)
+	assert.False(t, svc.Enabled())
+
+	// 활성 시 salt 필수
+	_, err = NewPasswordReuseService(config.SecurityConfig{
+		PasswordFingerprintEnabled: true,
+		PasswordFingerprintSalt:    "abcd",
+	}, encRepo, newSilentLogger())
+	assert.ErrorIs(t, err, ErrFingerprintSaltRequired)
+
+	_, err = NewPasswordReuseService(config.SecurityConfig{}, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestPasswordReuseService_Disabled(t *testing.T) {
+	ctx := context.Background()
+	encRepo := repository.NewEncryptionRepository(setupServiceTestDB(t))
+	svc, err := NewPasswordReuseService(config.SecurityConfig{}, encRepo, newSilentLogger())
+	require.NoError(t, err)
+
+	// 비활성 시 지문 컬럼은 비어 있고 경고도 없음
+	fingerprint, err := svc.Fingerprint([]byte("any-password"))
+	require.NoError(t, err)
+	assert.Empty(t, fingerprint)
+
+	warning, err := svc.CheckReuse(ctx, fingerprint)
+	require.NoError(t, err)
+	assert.Nil(t, warning)
+}
+
+func TestPasswordReuseService_WarnsOnReuse(t *testing.T) {
+	ctx := context.Background()
+	db := setupServiceTestDB(t)
+	encRepo := repository.NewEncryptionRepository(db)
+	svc, err := NewPasswordReuseService(config.SecurityConfig{
+		PasswordFingerprintEnabled: true,
+		PasswordFingerprintSalt:    testFingerprintSaltHex,
+		PasswordReuseThreshold:     1,
+	}, encRepo, newSilentLogger())
+	require.NoError(t, err)
+
+	fingerprint, err := svc.Fingerprint([]byte("reused-password"))
+	require.NoError(t, err)
+	require.Len(t, fingerprint, model.PasswordFingerprintHexLength)
+
+	// 아직 같은 지문이 없으면 경고 없음
+	warning, err := svc.CheckReuse(ctx, fingerprint)
+	require.NoError(t, err)
+	assert.Nil(t, warning)
+
+	// 첫 파일에 지문 저장
+	file := &model.File{
+		OriginalName:  "first.txt",
+		EncryptedPath: "/encrypted/first.enc",
+		MimeType:      "text/plain",
+		ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+		Status:        model.FileStatusEncrypted,
+	}
+	require.NoError(t, db.Create(file).Error)
+	require.NoError(t, encRepo.Create(ctx, &model.EncryptionMetadata{
+		FileID:              file.ID,
+		Algorithm:           model.EncryptionAlgorithmAES256GCM,
+		KeyDerivation:       model.KeyDerivationPBKDF2SHA256,
+		Iterations:          model.DefaultIterations,
+		SaltHex:             strings.Repeat("01", model.ExpectedSaltSize),
+		NonceHex:            strings.Repeat("02", model.ExpectedNonceSize),
+		PasswordFingerprint: fingerprint,
+	}))
+
+	// 같은 패스워드로 새 업로드 시 경고
+	warning, err = svc.CheckReuse(ctx, fingerprint)
+	require.NoError(t, err)
+	require.NotNil(t, warning)
+	assert.Equal(t, int64(1), warning.ReuseCount)
+
+	// 다른 패스워드는 경고 없음
+	other, err := svc.Fingerprint([]byte("fresh-password"))
+	require.NoError(t, err)
+	warning, err = svc.CheckReuse(ctx, other)
+	require.NoError(t, err)
+	assert.Nil(t, warning)
+}
@@ -0,0 +1,198 @@
+// Package service provides business logic for DataLocker.
+// This file implements text previews of encrypted files with optional UTF-8 conversion.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/charset"
+	"DataLocker/pkg/crypto"
+)
+
+// PreviewMaxBytes 미리보기로 복호화하는 앞부분 최대 크기 (인코딩 감지 샘플과 동일)
+const PreviewMaxBytes = charset.SampleSize
+
+// 미리보기 서비스 에러
+var (
+	ErrPreviewFileNotFound     = errors.New("파일을 찾을 수 없습니다")
+	ErrPreviewNotText          = errors.New("텍스트 파일만 미리보기할 수 있습니다")
+	ErrPreviewPasswordMismatch = errors.New("패스워드가 일치하지 않습니다")
+	ErrPreviewFileNotEncrypted = errors.New("암호화가 완료되지 않은 파일입니다")
+	ErrPreviewPasswordRequired = errors.New("패스워드가 필요합니다")
+)
+
+// TextPreview 텍스트 파일 앞부분의 미리보기
+type TextPreview struct {
+	FileID    uint   `json:"file_id"`
+	Encoding  string `json:"encoding"`  // 업로드 시 감지한 원본 인코딩
+	Converted bool   `json:"converted"` // Content가 UTF-8로 변환되었는지
+	Truncated bool   `json:"truncated"` // PreviewMaxBytes에서 잘렸는지
+	Content   []byte `json:"-"`
+}
+
+// PreviewService 암호화된 텍스트 파일의 앞부분을 보여주는 서비스
+type PreviewService interface {
+	// Preview 파일 앞부분을 복호화해 반환합니다
+	//
+	// toUTF8이면 기록된 인코딩에서 UTF-8로 변환하며, 변환할 수 없는 바이트는
+	// U+FFFD로 바꿉니다. 인코딩이 unknown이거나 기록되지 않은 파일은 UTF-8로 간주합니다.
+	Preview(ctx context.Context, fileID uint, password string, toUTF8 bool) (*TextPreview, error)
+}
+
+// previewService 미리보기 서비스 구현체
+type previewService struct {
+	fileRepo repository.FileRepository
+	storage  StorageService
+}
+
+// NewPreviewService 새로운 미리보기 서비스를 생성합니다
+func NewPreviewService(fileRepo repository.FileRepository, storage StorageService) PreviewService {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	if storage == nil {
+		panic("저장소 볼륨 서비스가 필요합니다")
+	}
+
+	return &previewService{
+		fileRepo: fileRepo,
+		storage:  storage,
+	}
+}
+
+// Preview 파일 앞부분을 복호화하고 필요하면 UTF-8로 변환합니다
+func (s *previewService) Preview(ctx context.Context, fileID uint, password string, toUTF8 bool) (*TextPreview, error) {
+	if password == "" {
+		return nil, ErrPreviewPasswordRequired
+	}
+
+	file, err := s.lookup(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !charset.IsTextMIME(file.MimeType) {
+		return nil, fmt.Errorf("%w: %s", ErrPreviewNotText, file.MimeType)
+	}
+
+	if !file.IsEncrypted() {
+		return nil, fmt.Errorf("%w: 상태 %s", ErrPreviewFileNotEncrypted, file.Status)
+	}
+
+	// 참조 레코드는 원본 blob을 읽음 (원본이 소프트 삭제되어도 blob은 남아 있음)
+	blob := file
+	if file.IsBlobReference() {
+		if blob, err = s.fileRepo.GetByIDWithDeleted(ctx, *file.BlobFileID); err != nil {
+			return nil, fmt.Errorf("원본 blob 조회 실패: %w", err)
+		}
+	}
+
+	path, err := s.storage.Locate(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	content, truncated, err := readPlaintextHead(ctx, path, password, PreviewMaxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// 보존 정책용 접근 기록 (미리보기는 이미 복호화되었으므로 기록 실패로 응답을 막지 않음)
+	_ = s.fileRepo.Touch(ctx, file.ID)
+
+	encoding := charset.Encoding(file.TextEncoding)
+	if encoding == "" {
+		encoding = charset.Unknown
+	}
+
+	preview := &TextPreview{
+		FileID:    file.ID,
+		Encoding:  string(encoding),
+		Truncated: truncated,
+		Content:   content,
+	}
+
+	if toUTF8 {
+		if truncated {
+			// 잘린 지점의 불완전한 문자가 대체 문자로 바뀌지 않도록 제거
+			content = charset.TrimPartial(content, encoding)
+		}
+		converted, err := charset.ToUTF8(content, encoding)
+		if err != nil {
+			return nil, fmt.Errorf("UTF-8 변환 실패: %w", err)
+		}
+		preview.Content = converted
+		preview.Converted = true
+	}
+
+	return preview, nil
+}
+
+// lookup 파일을 조회하고 없으면 ErrPreviewFileNotFound를 반환합니다
+func (s *previewService) lookup(ctx context.Context, fileID uint) (*model.File, error) {
+	if fileID == 0 {
+		return nil, fmt.Errorf("%w: ID %d", ErrPreviewFileNotFound, fileID)
+	}
+
+	exists, err := s.fileRepo.Exists(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("파일 존재 확인 실패: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: ID %d", ErrPreviewFileNotFound, fileID)
+	}
+
+	file, err := s.fileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("파일 조회 실패: %w", err)
+	}
+	return file, nil
+}
+
+// readPlaintextHead 암호화 파일을 복호화해 앞부분 limit 바이트를 읽습니다
+//
+// 앞부분만 읽으므로 종료 레코드와 MAC 트레일러는 확인하지 않습니다. 미리보기는
+// 표시 용도이며, 무결성은 IntegrityService로 검사합니다.
+func readPlaintextHead(ctx context.Context, path, password string, limit int) ([]byte, bool, error) {
+	src, err := os.Open(path) //nolint:gosec // 저장소에 기록된 경로
+	if err != nil {
+		return nil, false, fmt.Errorf("암호화 파일 열기 실패: %w", err)
+	}
+	defer src.Close()
+
+	plain, err := crypto.NewDecryptReader(src, password)
+	if err != nil {
+		return nil, false, fmt.Errorf("복호화 준비 실패: %w", err)
+	}
+
+	// 1바이트 더 읽어 잘림 여부 판단 (잘못된 패스워드는 첫 Read에서 보고됨)
+	head, err := io.ReadAll(io.LimitReader(&uploadReader{ctx: ctx, reader: plain}, int64(limit)+1))
+	if err != nil {
+		// 레거시 포맷은 첫 청크 실패를 구분할 수 없으므로 손상이 아닌 인증 실패는 패스워드 오류로 봄
+		if errors.Is(err, crypto.ErrWrongPassword) ||
+			(errors.Is(err, crypto.ErrAuthenticationFailed) && !errors.Is(err, crypto.ErrCorruptedChunk)) {
+			return nil, false, ErrPreviewPasswordMismatch
+		}
+		return nil, false, fmt.Errorf("미리보기 복호화 실패: %w", err)
+	}
+
+	if len(head) > limit {
+		return head[:limit], true, nil
+	}
+	return head, false, nil
+}
+
+// textEncodingFor 텍스트 계열 MIME이면 감지한 인코딩을, 아니면 빈 값을 반환합니다
+func textEncodingFor(mimeType string, sniffer *charset.Sniffer) string {
+	if !charset.IsTextMIME(mimeType) {
+		return ""
+	}
+	return string(sniffer.Encoding())
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/charset"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/korean"
+)
+
+// setupPreviewTest 낮은 반복 횟수의 업로드 서비스와 미리보기 서비스를 생성합니다
+func setupPreviewTest(t *testing.T) (UploadService, PreviewService, repository.FileRepository) {
+	t.Helper()
+	db := setupServiceTestDB(t)
+	fileRepo := repository.NewFileRepository(db)
+	storage := newDirStorage(t, filepath.Join(t.TempDir(), "storage"), fileRepo)
+	upload := NewUploadService(storage, 1000, repository.NewTxManager(db), nil, newSilentLogger())
+	return upload, NewPreviewService(fileRepo, storage), fileRepo
+}
+
+// uploadContent 내용을 업로드하고 파일 레코드를 반환합니다
+func uploadContent(t *testing.T, svc UploadService, name, mimeType string, content []byte) *model.File {
+	t.Helper()
+	result, err := svc.Upload(context.Background(), &UploadRequest{
+		OriginalName: name,
+		MimeType:     mimeType,
+		Size:         int64(len(content)),
+		ChecksumMD5:  md5Hex(content),
+		Password:     []byte(uploadTestPassword),
+	}, bytes.NewReader(content))
+	require.NoError(t, err)
+	return result.File
+}
+
+func TestUploadService_DetectsTextEncoding(t *testing.T) {
+	ctx := context.Background()
+	upload, _, fileRepo := setupPreviewTest(t)
+
+	euckr, err := korean.EUCKR.NewEncoder().String("한글 텍스트 파일입니다")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		mimeType string
+		content  []byte
+		want     string
+	}{
+		{name: "UTF-8 텍스트", mimeType: "text/plain", content: []byte("안녕하세요"), want: "utf-8"},
+		{name: "EUC-KR 텍스트", mimeType: "text/csv", content: []byte(euckr), want: "euc-kr"},
+		{name: "판별 불가", mimeType: "text/plain", content: []byte{'a', 0x00, 0xFF, 0x00, 0x00, 0x80}, want: "unknown"},
+		{name: "텍스트가 아닌 MIME", mimeType: "image/png", content: []byte("not really a png"), want: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := uploadContent(t, upload, tc.name+".bin", tc.mimeType, tc.content)
+
+			stored, err := fileRepo.GetByID(ctx, file.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, stored.TextEncoding)
+		})
+	}
+}
+
+func TestPreviewService_ConvertsToUTF8(t *testing.T) {
+	upload, preview, fileRepo := setupPreviewTest(t)
+
+	text := "데이터락커 미리보기\n"
+	euckr, err := korean.EUCKR.NewEncoder().String(text)
+	require.NoError(t, err)
+	file := uploadContent(t, upload, "legacy.txt", "text/plain", []byte(euckr))
+
+	// 변환 없이 원본 바이트
+	raw, err := preview.Preview(context.Background(), file.ID, uploadTestPassword, false)
+	require.NoError(t, err)
+	assert.Equal(t, "euc-kr", raw.Encoding)
+	assert.False(t, raw.Converted)
+	assert.Equal(t, []byte(euckr), raw.Content)
+
+	// UTF-8로 변환
+	converted, err := preview.Preview(context.Background(), file.ID, uploadTestPassword, true)
+	require.NoError(t, err)
+	assert.True(t, converted.Converted)
+	assert.False(t, converted.Truncated)
+	assert.Equal(t, text, string(converted.Content))
+
+	// 복호화에 성공하면 접근 시각 기록
+	stored, err := fileRepo.GetByID(context.Background(), file.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastAccessedAt)
+}
+
+func TestPreviewService_TruncatesLargeText(t *testing.T) {
+	upload, preview, _ := setupPreviewTest(t)
+
+	// 3바이트 문자가 PreviewMaxBytes 경계에 걸치도록 구성
+	content := []byte("a" + strings.Repeat("가", PreviewMaxBytes/3+10))
+	file := uploadContent(t, upload, "large.txt", "text/plain", content)
+
+	result, err := preview.Preview(context.Background(), file.ID, uploadTestPassword, true)
+	require.NoError(t, err)
+	assert.True(t, result.Truncated)
+	assert.LessOrEqual(t, len(result.Content), PreviewMaxBytes)
+	assert.NotContains(t, string(result.Content), "�", "잘린 문자는 대체 문자 대신 제거")
+}
+
+func TestPreviewService_Errors(t *testing.T) {
+	upload, preview, fileRepo := setupPreviewTest(t)
+
+	text := uploadContent(t, upload, "note.txt", "text/plain", []byte("hello"))
+	image := uploadContent(t, upload, "photo.png", "image/png", []byte("binary"))
+
+	_, err := preview.Preview(context.Background(), text.ID, "wrong-password", true)
+	assert.ErrorIs(t, err, ErrPreviewPasswordMismatch)
+
+	// 복호화에 실패하면 접근으로 보지 않음
+	stored, err := fileRepo.GetByID(context.Background(), text.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.LastAccessedAt)
+
+	_, err = preview.Preview(context.Background(), image.ID, uploadTestPassword, true)
+	assert.ErrorIs(t, err, ErrPreviewNotText)
+
+	_, err = preview.Preview(context.Background(), 9999, uploadTestPassword, true)
+	assert.ErrorIs(t, err, ErrPreviewFileNotFound)
+
+	_, err = preview.Preview(context.Background(), text.ID, "", true)
+	assert.ErrorIs(t, err, ErrPreviewPasswordRequired)
+
+	assert.Empty(t, textEncodingFor("image/png", &charset.Sniffer{}))
+}
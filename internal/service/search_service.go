@@ -0,0 +1,265 @@
+// Package service provides business logic for DataLocker.
+// This file implements unified file search combining name, description, tag, status, MIME type and date filters.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/simhash"
+)
+
+// 매칭 사유
+const (
+	MatchReasonName        = "name"
+	MatchReasonTag         = "tag"
+	MatchReasonDescription = "description"
+)
+
+// 검색 서비스 에러
+var (
+	ErrEmptySearchQuery    = errors.New("검색어 또는 필터가 하나 이상 필요합니다")
+	ErrInvalidSearchSort   = errors.New("지원하지 않는 정렬 방식입니다")
+	ErrInvalidSearchFilter = errors.New("잘못된 검색 필터입니다")
+
+	// ErrInvalidSimilarityThreshold 유사도 임계값이 0~simhash.MaxThreshold 범위를 벗어남
+	ErrInvalidSimilarityThreshold = errors.New("유사도 임계값이 허용 범위를 벗어났습니다")
+)
+
+// SearchRequest 통합 검색 요청
+type SearchRequest struct {
+	Query    string     `json:"q"`
+	Tags     []string   `json:"tags,omitempty"`
+	Status   string     `json:"status,omitempty"`
+	MimeType string     `json:"mime,omitempty"`
+	From     *time.Time `json:"from,omitempty"` // 생성 시각 하한 (포함)
+	To       *time.Time `json:"to,omitempty"`   // 생성 시각 상한 (미포함)
+	Sort     string     `json:"sort,omitempty"` // relevance 또는 latest
+	Page     int        `json:"page"`
+	PageSize int        `json:"page_size"`
+}
+
+// SearchHit 검색 결과 한 건과 매칭 사유
+type SearchHit struct {
+	File      *model.File `json:"file"`
+	MatchedBy []string    `json:"matched_by"`
+}
+
+// SearchResult 통합 검색 결과
+type SearchResult struct {
+	Items    []SearchHit `json:"items"`
+	Total    int64       `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	Sort     string      `json:"sort"`
+}
+
+// SimilarRequest 유사 중복 조회 요청
+type SimilarRequest struct {
+	FileID    uint
+	Threshold *int // 최대 해밍 거리 (nil이면 설정 기본값)
+}
+
+// SimilarHit 유사 파일 한 건과 기준 파일과의 해밍 거리
+type SimilarHit struct {
+	File     *model.File `json:"file"`
+	Distance int         `json:"distance"`
+}
+
+// SimilarResult 유사 중복 조회 결과 (거리, ID순)
+type SimilarResult struct {
+	FileID    uint         `json:"file_id"`
+	Threshold int          `json:"threshold"`
+	Items     []SimilarHit `json:"items"`
+}
+
+// SearchService 파일 통합 검색 서비스
+type SearchService interface {
+	// Search 이름·설명/태그/상태/MIME/기간 조건을 조합해 파일을 검색합니다
+	Search(ctx context.Context, req *SearchRequest) (*SearchResult, error)
+
+	// FindSimilar 내용의 SimHash가 임계값 이내인 유사 중복 파일을 조회합니다
+	FindSimilar(ctx context.Context, req *SimilarRequest) (*SimilarResult, error)
+}
+
+// searchService 파일 통합 검색 서비스 구현체
+type searchService struct {
+	fileRepo            repository.FileRepository
+	similarityThreshold int
+}
+
+// NewSearchService 새로운 검색 서비스를 생성합니다
+//
+// 설정의 유사도 임계값이 0~simhash.MaxThreshold 범위를 벗어나면 기본값을 사용합니다.
+func NewSearchService(cfg config.SecurityConfig, fileRepo repository.FileRepository) SearchService {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	threshold := cfg.SimilarityThreshold
+	if threshold < 0 || threshold > simhash.MaxThreshold {
+		threshold = config.DefaultSimilarityThreshold
+	}
+
+	return &searchService{
+		fileRepo:            fileRepo,
+		similarityThreshold: threshold,
+	}
+}
+
+// Search 파일을 검색합니다
+func (s *searchService) Search(ctx context.Context, req *SearchRequest) (*SearchResult, error) {
+	if req == nil {
+		return nil, ErrEmptySearchQuery
+	}
+
+	// 저장된 파일명과 같은 NFC로 맞춰 비교 (macOS 입력은 NFD)
+	query := model.NormalizeFileName(strings.TrimSpace(req.Query))
+	if query == "" && len(req.Tags) == 0 && req.Status == "" && req.MimeType == "" && req.From == nil && req.To == nil {
+		return nil, ErrEmptySearchQuery
+	}
+
+	sortBy, err := resolveSearchSort(req.Sort, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := normalizeSearchPage(req.Page, req.PageSize)
+
+	files, total, err := s.fileRepo.Search(ctx, repository.FileSearchParams{
+		Query:            query,
+		MatchDescription: true,
+		Tags:             req.Tags,
+		Status:           req.Status,
+		MimeType:         req.MimeType,
+		From:             req.From,
+		To:               req.To,
+		SortBy:           sortBy,
+		Offset:           (page - 1) * pageSize,
+		Limit:            pageSize,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidFileFilter) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSearchFilter, err)
+		}
+		return nil, fmt.Errorf("파일 검색 실패: %w", err)
+	}
+
+	result := &SearchResult{
+		Items:    make([]SearchHit, 0, len(files)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Sort:     sortBy,
+	}
+
+	for _, file := range files {
+		result.Items = append(result.Items, SearchHit{
+			File:      file,
+			MatchedBy: matchReasons(file, query, len(req.Tags) > 0),
+		})
+	}
+
+	return result, nil
+}
+
+// FindSimilar 기준 파일과 SimHash 해밍 거리가 임계값 이하인 파일을 조회합니다
+//
+// 임계값이 클수록 내용이 다른 파일까지 유사로 판정(오탐)하므로 simhash.MaxThreshold를
+// 넘는 값은 거부합니다. 기준 파일이 없거나 시그니처가 없으면 저장소 에러를 그대로 감쌉니다.
+func (s *searchService) FindSimilar(ctx context.Context, req *SimilarRequest) (*SimilarResult, error) {
+	if req == nil || req.FileID == 0 {
+		return nil, fmt.Errorf("유효하지 않은 파일 ID입니다")
+	}
+
+	threshold := s.similarityThreshold
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+	}
+	if threshold < 0 || threshold > simhash.MaxThreshold {
+		return nil, fmt.Errorf("%w: %d (0~%d)", ErrInvalidSimilarityThreshold, threshold, simhash.MaxThreshold)
+	}
+
+	similar, err := s.fileRepo.FindSimilar(ctx, req.FileID, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("유사 파일 조회 실패: %w", err)
+	}
+
+	result := &SimilarResult{
+		FileID:    req.FileID,
+		Threshold: threshold,
+		Items:     make([]SimilarHit, 0, len(similar)),
+	}
+	for _, match := range similar {
+		result.Items = append(result.Items, SimilarHit{File: match.File, Distance: match.Distance})
+	}
+
+	return result, nil
+}
+
+// resolveSearchSort 정렬 방식을 결정합니다 (검색어가 있으면 관련도순이 기본)
+func resolveSearchSort(sort, query string) (string, error) {
+	switch sort {
+	case "":
+		if query == "" {
+			return repository.SearchSortLatest, nil
+		}
+		return repository.SearchSortRelevance, nil
+	case repository.SearchSortRelevance, repository.SearchSortLatest:
+		return sort, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidSearchSort, sort)
+	}
+}
+
+// normalizeSearchPage 페이지 번호와 크기를 정규화합니다
+func normalizeSearchPage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize <= 0 || pageSize > repository.MaxPageSize {
+		pageSize = repository.DefaultPageSize
+	}
+
+	return page, pageSize
+}
+
+// matchReasons 검색어와 일치한 필드를 반환합니다 (태그 필터가 있으면 모든 결과가 태그 일치)
+func matchReasons(file *model.File, query string, tagged bool) []string {
+	reasons := make([]string, 0, 3)
+	if query != "" {
+		lowered := foldASCII(query)
+		if strings.Contains(foldASCII(file.OriginalName), lowered) {
+			reasons = append(reasons, MatchReasonName)
+		}
+		if strings.Contains(foldASCII(file.Description), lowered) {
+			reasons = append(reasons, MatchReasonDescription)
+		}
+	}
+
+	if tagged {
+		reasons = append(reasons, MatchReasonTag)
+	}
+
+	return reasons
+}
+
+// foldASCII ASCII 대문자만 소문자로 바꿉니다
+//
+// SQLite LIKE는 ASCII 범위만 대소문자를 무시하므로, 일치 필드도 같은 기준으로 판단해야
+// 조회된 적 없는 필드(예: É와 é만 다른 이름)를 일치로 표시하지 않습니다.
+func foldASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + ('a' - 'A')
+		}
+		return r
+	}, s)
+}
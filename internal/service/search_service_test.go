@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/simhash"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
+)
+
+// setupSearchTest 검색 테스트용 파일을 생성합니다
+func setupSearchTest(t *testing.T, names ...string) SearchService {
+	t.Helper()
+	ctx := context.Background()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+
+	for i, name := range names {
+		require.NoError(t, fileRepo.Create(ctx, &model.File{
+			OriginalName:  name,
+			EncryptedPath: fmt.Sprintf("/encrypted/search_%d.enc", i),
+			Size:          1024,
+			MimeType:      "text/plain",
+			ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+			Status:        model.FileStatusEncrypted,
+		}))
+	}
+
+	return NewSearchService(config.SecurityConfig{SimilarityThreshold: config.DefaultSimilarityThreshold}, fileRepo)
+}
+
+func TestSearchService_Search(t *testing.T) {
+	svc := setupSearchTest(t, "tax_2024.pdf", "Tax-notes.txt", "holiday.png")
+
+	result, err := svc.Search(context.Background(), &SearchRequest{Query: "tax"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Total)
+	assert.Equal(t, repository.SearchSortRelevance, result.Sort)
+	assert.Equal(t, 1, result.Page)
+	assert.Equal(t, repository.DefaultPageSize, result.PageSize)
+
+	for _, hit := range result.Items {
+		assert.Equal(t, []string{MatchReasonName}, hit.MatchedBy)
+	}
+
+	// 필터만 있는 경우 최신순, 매칭 사유 없음
+	result, err = svc.Search(context.Background(), &SearchRequest{Status: model.FileStatusEncrypted})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Total)
+	assert.Equal(t, repository.SearchSortLatest, result.Sort)
+	assert.Empty(t, result.Items[0].MatchedBy)
+}
+
+func TestSearchService_SearchDescription(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+	svc := NewSearchService(config.SecurityConfig{SimilarityThreshold: config.DefaultSimilarityThreshold}, fileRepo)
+
+	for i, fixture := range []struct{ name, description string }{
+		{"scan_001.png", "Invoice for client ACME"},
+		{"acme_invoice.pdf", "Invoice for client ACME"},
+		{"holiday.png", ""},
+	} {
+		require.NoError(t, fileRepo.Create(ctx, &model.File{
+			OriginalName:  fixture.name,
+			Description:   fixture.description,
+			EncryptedPath: fmt.Sprintf("/encrypted/described_%d.enc", i),
+			Size:          1024,
+			MimeType:      "text/plain",
+			ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+			Status:        model.FileStatusEncrypted,
+		}))
+	}
+
+	// 이름에서 찾은 파일이 설명에서만 찾은 파일보다 앞
+	result, err := svc.Search(ctx, &SearchRequest{Query: "acme"})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.Total)
+	assert.Equal(t, "acme_invoice.pdf", result.Items[0].File.OriginalName)
+	assert.Equal(t, []string{MatchReasonName, MatchReasonDescription}, result.Items[0].MatchedBy)
+	assert.Equal(t, "scan_001.png", result.Items[1].File.OriginalName)
+	assert.Equal(t, []string{MatchReasonDescription}, result.Items[1].MatchedBy)
+}
+
+func TestSearchService_MatchReasonsFoldASCIIOnly(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+	svc := NewSearchService(config.SecurityConfig{SimilarityThreshold: config.DefaultSimilarityThreshold}, fileRepo)
+
+	require.NoError(t, fileRepo.Create(ctx, &model.File{
+		OriginalName:  "ÉTÉ_Notes.txt",
+		Description:   "été photos",
+		EncryptedPath: "/encrypted/folded.enc",
+		Size:          1024,
+		MimeType:      "text/plain",
+		ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+		Status:        model.FileStatusEncrypted,
+	}))
+
+	// SQLite LIKE처럼 ASCII만 대소문자를 무시하므로 É는 é와 다른 문자
+	result, err := svc.Search(ctx, &SearchRequest{Query: "été"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Total)
+	assert.Equal(t, []string{MatchReasonDescription}, result.Items[0].MatchedBy)
+
+	result, err = svc.Search(ctx, &SearchRequest{Query: "notes"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Total)
+	assert.Equal(t, []string{MatchReasonName}, result.Items[0].MatchedBy)
+}
+
+func TestSearchService_SearchByTags(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+	svc := NewSearchService(config.SecurityConfig{SimilarityThreshold: config.DefaultSimilarityThreshold}, fileRepo)
+
+	ids := make([]uint, 3)
+	for i, name := range []string{"tax_2024.pdf", "receipt.png", "holiday.png"} {
+		file := &model.File{
+			OriginalName:  name,
+			EncryptedPath: fmt.Sprintf("/encrypted/tagged_%d.enc", i),
+			Size:          1024,
+			MimeType:      "text/plain",
+			ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+			Status:        model.FileStatusEncrypted,
+		}
+		require.NoError(t, fileRepo.Create(ctx, file))
+		ids[i] = file.ID
+	}
+	require.NoError(t, fileRepo.AddTags(ctx, ids[0], []string{"tax-2024"}))
+	require.NoError(t, fileRepo.AddTags(ctx, ids[1], []string{"tax-2024", "personal"}))
+
+	// 태그만 있으면 태그 일치로 표시
+	result, err := svc.Search(ctx, &SearchRequest{Tags: []string{"Tax-2024"}})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.Total)
+	for _, hit := range result.Items {
+		assert.Equal(t, []string{MatchReasonTag}, hit.MatchedBy)
+	}
+
+	// 이름과 태그를 함께 주면 둘 다 맞는 파일만
+	result, err = svc.Search(ctx, &SearchRequest{Query: "tax", Tags: []string{"tax-2024"}})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Total)
+	assert.Equal(t, ids[0], result.Items[0].File.ID)
+	assert.Equal(t, []string{MatchReasonName, MatchReasonTag}, result.Items[0].MatchedBy)
+}
+
+func TestSearchService_SearchUnicodeNormalization(t *testing.T) {
+	svc := setupSearchTest(t, norm.NFD.String("여권사본.jpg"), "여권_갱신.pdf")
+
+	// NFD와 NFC 검색어 모두 두 파일을 찾고 이름 일치로 표시
+	for _, query := range []string{"여권", norm.NFD.String("여권")} {
+		result, err := svc.Search(context.Background(), &SearchRequest{Query: query})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Total)
+		for _, hit := range result.Items {
+			assert.Equal(t, []string{MatchReasonName}, hit.MatchedBy)
+		}
+	}
+}
+
+func TestSearchService_InvalidRequests(t *testing.T) {
+	svc := setupSearchTest(t)
+
+	testCases := []struct {
+		name    string
+		req     *SearchRequest
+		wantErr error
+	}{
+		{name: "nil 요청", req: nil, wantErr: ErrEmptySearchQuery},
+		{name: "빈 질의", req: &SearchRequest{Query: "   "}, wantErr: ErrEmptySearchQuery},
+		{name: "잘못된 정렬", req: &SearchRequest{Query: "a", Sort: "size"}, wantErr: ErrInvalidSearchSort},
+		{name: "잘못된 태그", req: &SearchRequest{Tags: []string{strings.Repeat("t", model.MaxTagNameLength+1)}}, wantErr: ErrInvalidSearchFilter},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.Search(context.Background(), tc.req)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSearchService_FindSimilar(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+
+	// 기준 파일과 거리 0, 2, 5인 파일, 마지막은 시그니처 없는 파일
+	const base = uint64(0xfedcba9876543210)
+	signatures := []uint64{base, base, base ^ 0b11, base ^ 0b11111, 0}
+	files := make([]*model.File, len(signatures))
+	for i, signature := range signatures {
+		files[i] = &model.File{
+			OriginalName:  fmt.Sprintf("similar_%d.txt", i),
+			EncryptedPath: fmt.Sprintf("/encrypted/similar_%d.enc", i),
+			Size:          1024,
+			MimeType:      "text/plain",
+			ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+			Status:        model.FileStatusEncrypted,
+		}
+		if i < len(signatures)-1 {
+			files[i].SetSimilaritySignature(signature)
+		}
+		require.NoError(t, fileRepo.Create(ctx, files[i]))
+	}
+
+	// 잘못된 설정 값은 기본값으로 대체
+	svc := NewSearchService(config.SecurityConfig{SimilarityThreshold: 64}, fileRepo)
+
+	result, err := svc.FindSimilar(context.Background(), &SimilarRequest{FileID: files[0].ID})
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultSimilarityThreshold, result.Threshold)
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, files[1].ID, result.Items[0].File.ID)
+	assert.Equal(t, 0, result.Items[0].Distance)
+	assert.Equal(t, files[2].ID, result.Items[1].File.ID)
+	assert.Equal(t, 2, result.Items[1].Distance)
+
+	threshold := 5
+	result, err = svc.FindSimilar(context.Background(), &SimilarRequest{FileID: files[0].ID, Threshold: &threshold})
+	require.NoError(t, err)
+	assert.Len(t, result.Items, 3)
+
+	threshold = simhash.MaxThreshold + 1
+	_, err = svc.FindSimilar(context.Background(), &SimilarRequest{FileID: files[0].ID, Threshold: &threshold})
+	assert.ErrorIs(t, err, ErrInvalidSimilarityThreshold)
+
+	_, err = svc.FindSimilar(context.Background(), &SimilarRequest{FileID: files[4].ID})
+	assert.ErrorIs(t, err, repository.ErrNoSimilaritySignature)
+
+	_, err = svc.FindSimilar(context.Background(), &SimilarRequest{FileID: 9999})
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+}
@@ -0,0 +1,107 @@
+// Package service provides business logic for DataLocker.
+// This file implements storage statistics aggregated in the database.
+package service
+
+import (
+	"context"
+
+	"DataLocker/internal/repository"
+)
+
+// StatusUsage 상태별 파일 수와 원본/암호화본 크기 합계
+type StatusUsage struct {
+	Files          int64 `json:"files"`
+	Bytes          int64 `json:"bytes"`           // 보호하는 데이터 양 (원본 크기)
+	EncryptedBytes int64 `json:"encrypted_bytes"` // 디스크 사용량 (암호화본 크기)
+}
+
+// CategoryUsage MIME 대분류별 파일 수와 원본/암호화본 크기 합계
+type CategoryUsage struct {
+	Category       string `json:"category"`
+	Prefix         string `json:"prefix,omitempty"` // other는 비어 있음
+	Files          int64  `json:"files"`
+	Bytes          int64  `json:"bytes"`
+	EncryptedBytes int64  `json:"encrypted_bytes"`
+}
+
+// StorageStats 저장소 통계
+//
+// Bytes는 암호화 전 원본 크기(보호하는 데이터 양)이고 EncryptedBytes는 청크별
+// 암호화 오버헤드를 포함한 디스크 사용량입니다(크기 기록 도입 전 파일 중 암호화본을
+// 찾지 못한 파일은 0으로 셈). 중복 제거로 다른 파일의 blob을 참조하는 레코드는
+// 파일 수에만 포함하고 크기에는 포함하지 않습니다. 소프트 삭제된 파일은 includeDeleted로
+// 요청한 경우에만 포함하며, 이때 그중 휴지통 몫을 Trash로 따로 알려줍니다.
+type StorageStats struct {
+	TotalFiles          int64                  `json:"total_files"`
+	TotalBytes          int64                  `json:"total_bytes"`
+	TotalEncryptedBytes int64                  `json:"total_encrypted_bytes"`
+	Statuses            map[string]StatusUsage `json:"statuses"`        // 파일이 없는 상태도 0으로 포함
+	Categories          []CategoryUsage        `json:"categories"`      // repository.MimeFamilies 순서, 마지막은 other
+	Trash               *StatusUsage           `json:"trash,omitempty"` // includeDeleted일 때만
+}
+
+// StatsService 저장소 통계 서비스 인터페이스
+type StatsService interface {
+	// StorageStats 상태별/MIME 대분류별 파일 수와 크기를 집계합니다 (행을 메모리로 읽지 않음)
+	StorageStats(ctx context.Context, includeDeleted bool) (*StorageStats, error)
+}
+
+// statsService 저장소 통계 서비스 구현체
+type statsService struct {
+	fileRepo repository.FileRepository
+}
+
+// NewStatsService 새로운 저장소 통계 서비스를 생성합니다
+func NewStatsService(fileRepo repository.FileRepository) StatsService {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	return &statsService{
+		fileRepo: fileRepo,
+	}
+}
+
+// StorageStats 저장소 집계 한 번으로 상태별, MIME 대분류별 합계를 만듭니다
+func (s *statsService) StorageStats(ctx context.Context, includeDeleted bool) (*StorageStats, error) {
+	aggregates, err := s.fileRepo.Aggregate(ctx, repository.AggregateOptions{IncludeDeleted: includeDeleted})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &StorageStats{
+		TotalFiles:          aggregates.Total.Files,
+		TotalBytes:          aggregates.Total.Bytes,
+		TotalEncryptedBytes: aggregates.Total.EncryptedBytes,
+		Statuses:            make(map[string]StatusUsage, len(aggregates.ByStatus)),
+		Categories:          make([]CategoryUsage, 0, len(repository.MimeFamilies)+1),
+	}
+	for status, usage := range aggregates.ByStatus {
+		stats.Statuses[status] = StatusUsage(usage)
+	}
+
+	for _, family := range repository.MimeFamilies {
+		usage := aggregates.ByMimeFamily[family]
+		stats.Categories = append(stats.Categories, CategoryUsage{
+			Category:       family,
+			Prefix:         family + "/",
+			Files:          usage.Files,
+			Bytes:          usage.Bytes,
+			EncryptedBytes: usage.EncryptedBytes,
+		})
+	}
+	other := aggregates.ByMimeFamily[repository.MimeFamilyOther]
+	stats.Categories = append(stats.Categories, CategoryUsage{
+		Category:       repository.MimeFamilyOther,
+		Files:          other.Files,
+		Bytes:          other.Bytes,
+		EncryptedBytes: other.EncryptedBytes,
+	})
+
+	if includeDeleted {
+		trash := StatusUsage(aggregates.Trash)
+		stats.Trash = &trash
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsService_StorageStats(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+	svc := NewStatsService(fileRepo)
+
+	// 파일이 없어도 모든 항목을 0으로 채움
+	stats, err := svc.StorageStats(ctx, false)
+	require.NoError(t, err)
+	assert.Zero(t, stats.TotalFiles)
+	assert.Zero(t, stats.TotalBytes)
+	assert.Len(t, stats.Statuses, len(model.FileStatuses()))
+	require.Len(t, stats.Categories, len(repository.MimeFamilies)+1)
+	assert.Equal(t, repository.MimeFamilyOther, stats.Categories[len(stats.Categories)-1].Category)
+
+	seed := []struct {
+		mime   string
+		status string
+		size   int64
+	}{
+		{"text/plain", model.FileStatusEncrypted, 100},
+		{"image/png", model.FileStatusEncrypted, 2000},
+		{"IMAGE/JPEG", model.FileStatusFailed, 3000},
+		{"application/pdf", model.FileStatusPending, 400},
+		{"font/woff2", model.FileStatusEncrypted, 50},
+	}
+	var files []*model.File
+	for i, row := range seed {
+		file := &model.File{
+			OriginalName:  fmt.Sprintf("stats_%d", i),
+			EncryptedPath: fmt.Sprintf("/encrypted/stats_%d.enc", i),
+			Size:          row.size,
+			EncryptedSize: row.size + 28, // 헤더/인증 태그만큼 큼
+			MimeType:      row.mime,
+			ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+			Status:        row.status,
+		}
+		require.NoError(t, fileRepo.Create(ctx, file))
+		files = append(files, file)
+	}
+	require.NoError(t, fileRepo.Delete(ctx, files[1].ID))
+
+	stats, err = svc.StorageStats(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), stats.TotalFiles)
+	assert.Equal(t, int64(3550), stats.TotalBytes)
+	assert.Equal(t, int64(3550+4*28), stats.TotalEncryptedBytes, "디스크 사용량은 암호화 크기 합계")
+	assert.Equal(t, StatusUsage{Files: 2, Bytes: 150, EncryptedBytes: 150 + 2*28}, stats.Statuses[model.FileStatusEncrypted])
+	assert.Equal(t, StatusUsage{}, stats.Statuses[model.FileStatusCorrupted])
+	assert.Nil(t, stats.Trash)
+
+	bytes := make(map[string]int64)
+	for _, category := range stats.Categories {
+		bytes[category.Category] = category.Bytes
+	}
+	assert.Equal(t, map[string]int64{
+		"text": 100, "image": 3000, "audio": 0, "video": 0, "application": 400, repository.MimeFamilyOther: 50,
+	}, bytes, "MIME 대분류는 대소문자 무시, 나머지는 other")
+
+	// 휴지통을 포함하면 삭제된 파일도 합계에 들어가고 그 몫을 따로 알려줌
+	stats, err = svc.StorageStats(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stats.TotalFiles)
+	assert.Equal(t, int64(5550), stats.TotalBytes)
+	assert.Equal(t, int64(5550+5*28), stats.TotalEncryptedBytes)
+	assert.Equal(t, StatusUsage{Files: 3, Bytes: 2150, EncryptedBytes: 2150 + 3*28}, stats.Statuses[model.FileStatusEncrypted])
+	assert.Equal(t, &StatusUsage{Files: 1, Bytes: 2000, EncryptedBytes: 2028}, stats.Trash)
+	assert.Equal(t, CategoryUsage{Category: "image", Prefix: "image/", Files: 2, Bytes: 5000, EncryptedBytes: 5056}, stats.Categories[1])
+
+	assert.Panics(t, func() { NewStatsService(nil) })
+}
@@ -0,0 +1,646 @@
+// Package service provides business logic for DataLocker.
+// This file implements placement of encrypted files across storage volumes.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 볼륨 재배치 관련 상수
+const (
+	// DefaultRebalanceMaxFiles 재배치 한 번에 옮기는 기본 최대 파일 수
+	DefaultRebalanceMaxFiles = 100
+)
+
+// 저장 경로 예약 관련 상수
+const (
+	// PathReservationTTL 이 시간이 지난 예약은 프로세스가 비정상 종료해 남은 것으로 보고 정리
+	PathReservationTTL = 24 * time.Hour
+
+	// 예약 표시 파일 확장자 (암호화본 경로 뒤에 붙임)
+	pathReservationExt = ".reserved"
+
+	// 예약한 경로가 이미 쓰이고 있을 때 새 이름으로 다시 시도하는 최대 횟수
+	maxReserveAttempts = 8
+)
+
+// 저장소 볼륨 에러
+var (
+	ErrInvalidVolumes   = errors.New("잘못된 저장소 볼륨 설정입니다")
+	ErrVolumeOffline    = errors.New("저장소 볼륨을 사용할 수 없습니다")
+	ErrNoVolumeCapacity = errors.New("새 파일을 저장할 여유 용량이 있는 볼륨이 없습니다")
+	ErrPathReservation  = errors.New("새 파일의 저장 경로를 예약하지 못했습니다")
+)
+
+// VolumeStats 볼륨별 사용량 통계
+type VolumeStats struct {
+	ID        string  `json:"id"`
+	Path      string  `json:"path"`
+	Weight    int     `json:"weight"`
+	Online    bool    `json:"online"`
+	Files     int64   `json:"files"`
+	UsedBytes int64   `json:"used_bytes"`           // 원본 크기 합계 (MaxBytes와 비교하는 값)
+	DiskBytes int64   `json:"disk_bytes"`           // 암호화본 크기 합계 (실제 디스크 사용량)
+	MaxBytes  int64   `json:"max_bytes"`            // 0이면 무제한
+	UsedRatio float64 `json:"used_ratio,omitempty"` // MaxBytes 대비 사용률 (무제한이면 생략)
+}
+
+// RebalanceMove 재배치로 옮긴 (dry run이면 옮길) 파일
+type RebalanceMove struct {
+	FileID uint   `json:"file_id"`
+	Size   int64  `json:"size"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// RebalanceResult 볼륨 재배치 결과
+type RebalanceResult struct {
+	DryRun  bool            `json:"dry_run"`
+	Moves   []RebalanceMove `json:"moves"`
+	Failed  []string        `json:"failed,omitempty"` // 옮기지 못한 파일과 사유
+	Volumes []VolumeStats   `json:"volumes"`          // 재배치 후 (dry run이면 예상) 사용량
+}
+
+// StorageService 암호화 파일을 여러 저장소 볼륨에 나눠 배치하는 서비스
+type StorageService interface {
+	// Place size 바이트 원본의 암호화본을 저장할 볼륨을 고르고 경로를 반환합니다
+	//
+	// 남은 용량 × 가중치가 가장 큰 온라인 볼륨을 고르며, 모든 볼륨이 가득 찼거나
+	// 오프라인이면 ErrNoVolumeCapacity를 반환합니다.
+	Place(ctx context.Context, size int64) (volumeID, path string, err error)
+
+	// ReservePath Place로 고른 경로를 다른 업로드가 쓰지 못하도록 예약합니다
+	//
+	// 경로 옆에 예약 표시 파일을 O_EXCL로 만들어 같은 볼륨을 쓰는 다른 프로세스와도
+	// 겹치지 않으며, 디스크나 레코드(소프트 삭제 포함)에 이미 있는 경로는 새 이름으로
+	// 다시 고릅니다. 여러 번 겹치면 ErrPathReservation을 반환합니다. release는 예약을
+	// 반납하며 여러 번 호출해도 안전합니다. 업로드는 성공/실패와 관계없이 끝나면
+	// release를 호출합니다(성공하면 레코드가 경로를 소유함).
+	ReservePath(ctx context.Context, size int64) (volumeID, path string, release func(), err error)
+
+	// PruneReservations PathReservationTTL이 지난 예약과 그 업로드 임시 파일을 지우고 지운 예약 수를 반환합니다
+	PruneReservations(ctx context.Context) (int, error)
+
+	// Locate 파일의 암호화본 경로를 반환합니다
+	//
+	// 볼륨이 오프라인이거나 설정에서 빠졌으면 ErrVolumeOffline을 반환합니다.
+	// 볼륨이 없는 레코드(볼륨 도입 전 파일)는 EncryptedPath를 그대로 반환합니다.
+	Locate(file *model.File) (string, error)
+
+	// Stats 볼륨별 사용량을 조회합니다
+	Stats(ctx context.Context) ([]VolumeStats, error)
+
+	// CheckVolumes 모든 볼륨에 접근할 수 있는지 확인합니다 (DB를 조회하지 않음)
+	//
+	// 오프라인인 볼륨이 있으면 그 ID를 담아 ErrVolumeOffline을 감싼 에러를 반환합니다.
+	CheckVolumes(ctx context.Context) error
+
+	// Rebalance 가중치 비율보다 많이 쓴 볼륨의 파일을 덜 쓴 볼륨으로 옮깁니다
+	//
+	// 한 번에 최대 maxFiles개(0 이하이면 DefaultRebalanceMaxFiles)를 옮기며,
+	// dryRun이면 옮길 계획만 반환합니다.
+	Rebalance(ctx context.Context, dryRun bool, maxFiles int) (*RebalanceResult, error)
+}
+
+// storageVolume 설정된 볼륨과 배치 정보
+type storageVolume struct {
+	config.StorageVolume
+	autoCreate bool // STORAGE_DIR 단일 볼륨이면 없을 때 생성 (기존 동작)
+}
+
+// storageService 저장소 볼륨 서비스 구현체
+type storageService struct {
+	volumes  []*storageVolume
+	byID     map[string]*storageVolume
+	fileRepo repository.FileRepository
+	locker   repository.FileLocker
+	logger   *logrus.Logger
+	newName  func() (string, error)
+	clock    clock.Clock
+}
+
+// NewStorageService 새로운 저장소 볼륨 서비스를 생성합니다
+//
+// cfg.Volumes가 비어 있으면 cfg.Dir 하나를 config.DefaultStorageVolumeID 볼륨으로
+// 사용합니다. 볼륨 ID가 비어 있거나 중복되면 ErrInvalidVolumes를 반환합니다.
+func NewStorageService(cfg config.StorageConfig, fileRepo repository.FileRepository, locker repository.FileLocker, logger *logrus.Logger) (StorageService, error) {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	if locker == nil {
+		panic("파일 락이 필요합니다")
+	}
+
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	s := &storageService{
+		byID:     make(map[string]*storageVolume),
+		fileRepo: fileRepo,
+		locker:   locker,
+		logger:   logger,
+		newName:  randomFileName,
+		clock:    clock.Real{},
+	}
+
+	for _, volume := range cfg.EffectiveVolumes() {
+		switch {
+		case volume.ID == "" || len(volume.ID) > model.MaxVolumeIDLength:
+			return nil, fmt.Errorf("%w: 볼륨 ID는 1~%d자여야 합니다: %q", ErrInvalidVolumes, model.MaxVolumeIDLength, volume.ID)
+		case volume.Path == "":
+			return nil, fmt.Errorf("%w: 볼륨 %s의 경로가 비어 있습니다", ErrInvalidVolumes, volume.ID)
+		case volume.Weight < 0 || volume.MaxBytes < 0:
+			return nil, fmt.Errorf("%w: 볼륨 %s의 가중치와 최대 용량은 0 이상이어야 합니다", ErrInvalidVolumes, volume.ID)
+		case s.byID[volume.ID] != nil:
+			return nil, fmt.Errorf("%w: 중복된 볼륨 ID %s", ErrInvalidVolumes, volume.ID)
+		}
+
+		if volume.Weight == 0 {
+			volume.Weight = config.DefaultStorageVolumeWeight
+		}
+
+		v := &storageVolume{StorageVolume: volume, autoCreate: len(cfg.Volumes) == 0}
+		s.volumes = append(s.volumes, v)
+		s.byID[v.ID] = v
+	}
+
+	return s, nil
+}
+
+// Place 남은 용량과 가중치로 볼륨을 골라 새 암호화본 경로를 만듭니다
+func (s *storageService) Place(ctx context.Context, size int64) (string, string, error) {
+	used, err := s.usage(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	var (
+		best      *storageVolume
+		bestScore float64
+	)
+	for _, v := range s.volumes {
+		if v.autoCreate {
+			if err := os.MkdirAll(v.Path, watchDirPerm); err != nil {
+				return "", "", fmt.Errorf("저장소 디렉터리 생성 실패: %w", err)
+			}
+		}
+
+		if !v.online() || !v.fits(used[v.ID].Bytes, size) {
+			continue
+		}
+
+		// 무제한 볼륨은 남은 용량이 무한하다고 보고, 동점이면 가중치 대비 사용량이 적은 쪽
+		score := math.Inf(1)
+		if v.MaxBytes > 0 {
+			score = float64(v.MaxBytes-used[v.ID].Bytes) * float64(v.Weight)
+		}
+		if best == nil || score > bestScore ||
+			(score == bestScore && weightedUsage(used[v.ID].Bytes, v) < weightedUsage(used[best.ID].Bytes, best)) {
+			best, bestScore = v, score
+		}
+	}
+
+	if best == nil {
+		return "", "", fmt.Errorf("%w: %d 바이트", ErrNoVolumeCapacity, size)
+	}
+
+	name, err := s.newName()
+	if err != nil {
+		return "", "", err
+	}
+
+	return best.ID, filepath.Join(best.Path, name), nil
+}
+
+// ReservePath 경로를 고르고 예약 표시 파일을 만든 뒤 디스크와 레코드에서 쓰이지 않는지 확인합니다
+func (s *storageService) ReservePath(ctx context.Context, size int64) (string, string, func(), error) {
+	for range maxReserveAttempts {
+		volumeID, path, err := s.Place(ctx, size)
+		if err != nil {
+			return "", "", nil, err
+		}
+
+		release, err := s.reserve(ctx, path)
+		if err != nil {
+			return "", "", nil, err
+		}
+		if release != nil {
+			return volumeID, path, release, nil
+		}
+	}
+
+	return "", "", nil, fmt.Errorf("%w: %d번 모두 이미 쓰이는 경로였습니다", ErrPathReservation, maxReserveAttempts)
+}
+
+// reserve 경로 하나를 예약합니다 (이미 쓰이는 경로이면 nil, nil)
+func (s *storageService) reserve(ctx context.Context, path string) (func(), error) {
+	marker := path + pathReservationExt
+	f, err := os.OpenFile(marker, os.O_WRONLY|os.O_CREATE|os.O_EXCL, watchFilePerm) //nolint:gosec // 저장소 내부 랜덤 경로
+	if errors.Is(err, os.ErrExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: 예약 파일 생성 실패: %w", ErrPathReservation, err)
+	}
+	if err := f.Close(); err != nil {
+		s.removeReservation(marker)
+		return nil, fmt.Errorf("%w: 예약 파일 생성 실패: %w", ErrPathReservation, err)
+	}
+
+	inUse, err := s.pathInUse(ctx, path)
+	if err != nil || inUse {
+		s.removeReservation(marker)
+		return nil, err
+	}
+
+	return sync.OnceFunc(func() { s.removeReservation(marker) }), nil
+}
+
+// pathInUse 경로의 암호화본이나 업로드 임시 파일, 또는 그 경로를 가리키는 레코드가 있는지 확인합니다
+func (s *storageService) pathInUse(ctx context.Context, path string) (bool, error) {
+	for _, p := range []string{path, path + partialFileExt} {
+		if _, err := os.Lstat(p); err == nil {
+			return true, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("%w: %w", ErrPathReservation, err)
+		}
+	}
+
+	_, err := s.fileRepo.GetByEncryptedPath(ctx, path)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, model.ErrRecordNotFound):
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: %w", ErrPathReservation, err)
+	}
+}
+
+// removeReservation 예약 표시 파일을 지웁니다 (실패해도 PruneReservations가 정리하므로 경고만 남김)
+func (s *storageService) removeReservation(marker string) {
+	if err := os.Remove(marker); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.logger.WithError(err).WithField("path", marker).Warn("저장 경로 예약을 반납하지 못했습니다")
+	}
+}
+
+// PruneReservations 온라인 볼륨마다 오래된 예약 표시 파일을 찾아 지웁니다
+//
+// 예약이 남았다는 것은 업로드 중에 프로세스가 끝났다는 뜻이므로 같은 경로의
+// 업로드 임시 파일도 함께 지웁니다. 오프라인 볼륨은 건너뜁니다.
+func (s *storageService) PruneReservations(ctx context.Context) (int, error) {
+	cutoff := s.clock.Now().Add(-PathReservationTTL)
+	pruned := 0
+	for _, v := range s.volumes {
+		if err := ctx.Err(); err != nil {
+			return pruned, err
+		}
+		if !v.online() {
+			continue
+		}
+
+		entries, err := os.ReadDir(v.Path)
+		if err != nil {
+			return pruned, fmt.Errorf("볼륨 %s 예약 조회 실패: %w", v.ID, err)
+		}
+		for _, e := range entries {
+			if !e.Type().IsRegular() || !strings.HasSuffix(e.Name(), pathReservationExt) {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil || info.ModTime().After(cutoff) {
+				continue
+			}
+
+			marker := filepath.Join(v.Path, e.Name())
+			partPath := strings.TrimSuffix(marker, pathReservationExt) + partialFileExt
+			if err := os.Remove(partPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				s.logger.WithError(err).WithField("path", partPath).Warn("만료된 예약의 업로드 임시 파일을 지우지 못했습니다")
+				continue
+			}
+			if err := os.Remove(marker); err != nil && !errors.Is(err, os.ErrNotExist) {
+				s.logger.WithError(err).WithField("path", marker).Warn("만료된 저장 경로 예약을 지우지 못했습니다")
+				continue
+			}
+			pruned++
+		}
+	}
+
+	if pruned > 0 {
+		s.logger.WithField("pruned", pruned).Info("만료된 저장 경로 예약을 정리했습니다")
+	}
+	return pruned, nil
+}
+
+// Locate 볼륨이 사용 가능한지 확인하고 암호화본 경로를 반환합니다
+func (s *storageService) Locate(file *model.File) (string, error) {
+	if file.VolumeID == "" {
+		return file.EncryptedPath, nil
+	}
+
+	v := s.byID[file.VolumeID]
+	if v == nil {
+		return "", fmt.Errorf("%w: 볼륨 %s이(가) 설정에 없습니다 (파일 ID %d)", ErrVolumeOffline, file.VolumeID, file.ID)
+	}
+
+	if !v.online() {
+		return "", fmt.Errorf("%w: 볼륨 %s (%s)에 접근할 수 없습니다 (파일 ID %d)", ErrVolumeOffline, v.ID, v.Path, file.ID)
+	}
+
+	return file.EncryptedPath, nil
+}
+
+// Stats 볼륨별 사용량과 온라인 여부를 조회합니다
+func (s *storageService) Stats(ctx context.Context) ([]VolumeStats, error) {
+	used, err := s.usage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]VolumeStats, 0, len(s.volumes))
+	for _, v := range s.volumes {
+		stats = append(stats, v.stats(used[v.ID]))
+	}
+	return stats, nil
+}
+
+// CheckVolumes 볼륨 루트 디렉터리를 차례로 확인합니다 (ctx가 끝나면 남은 볼륨은 확인하지 않음)
+func (s *storageService) CheckVolumes(ctx context.Context) error {
+	var offline []string
+	for _, v := range s.volumes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if v.online() {
+			continue
+		}
+		// STORAGE_DIR 단일 볼륨은 첫 업로드 때 만들어지므로 아직 없어도 정상
+		if _, err := os.Stat(v.Path); v.autoCreate && errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		offline = append(offline, v.ID)
+	}
+
+	if len(offline) > 0 {
+		return fmt.Errorf("%w: %s", ErrVolumeOffline, strings.Join(offline, ", "))
+	}
+	return nil
+}
+
+// Rebalance 목표 사용량(전체 사용량을 가중치로 나눈 값)을 넘은 볼륨의 큰 파일부터 옮깁니다
+//
+// 오프라인 볼륨은 계산과 이동에서 제외합니다. 파일은 대상 볼륨에 복사해 동기화한
+// 뒤 레코드를 갱신하고 원본을 지우므로, 중간에 실패해도 레코드는 항상 존재하는
+// 암호화본을 가리킵니다.
+func (s *storageService) Rebalance(ctx context.Context, dryRun bool, maxFiles int) (*RebalanceResult, error) {
+	if maxFiles <= 0 {
+		maxFiles = DefaultRebalanceMaxFiles
+	}
+
+	used, err := s.usage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var online []*storageVolume
+	var totalBytes, totalWeight int64
+	for _, v := range s.volumes {
+		if v.online() {
+			online = append(online, v)
+			totalBytes += used[v.ID].Bytes
+			totalWeight += int64(v.Weight)
+		}
+	}
+
+	target := make(map[string]int64, len(online))
+	for _, v := range online {
+		target[v.ID] = int64(float64(totalBytes) * float64(v.Weight) / float64(totalWeight))
+		if v.MaxBytes > 0 && target[v.ID] > v.MaxBytes {
+			target[v.ID] = v.MaxBytes
+		}
+	}
+
+	result := &RebalanceResult{DryRun: dryRun, Moves: []RebalanceMove{}}
+	for _, src := range online {
+		if used[src.ID].Bytes <= target[src.ID] {
+			continue
+		}
+
+		files, _, err := s.fileRepo.GetByVolume(ctx, src.ID, 0, maxFiles)
+		if err != nil {
+			return nil, fmt.Errorf("볼륨 %s 파일 조회 실패: %w", src.ID, err)
+		}
+
+		for _, file := range files {
+			if len(result.Moves) >= maxFiles || used[src.ID].Bytes <= target[src.ID] {
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			if !file.IsEncrypted() {
+				continue
+			}
+
+			dst := pickRebalanceTarget(online, src, file.Size, used, target)
+			if dst == nil {
+				continue
+			}
+
+			if !dryRun {
+				if err := s.move(ctx, file, dst); err != nil {
+					result.Failed = append(result.Failed, fmt.Sprintf("파일 %d: %s", file.ID, err))
+					continue
+				}
+			}
+
+			used[src.ID] = repository.VolumeUsage{VolumeID: src.ID, Files: used[src.ID].Files - 1,
+				Bytes: used[src.ID].Bytes - file.Size, EncryptedBytes: used[src.ID].EncryptedBytes - file.EncryptedSize}
+			used[dst.ID] = repository.VolumeUsage{VolumeID: dst.ID, Files: used[dst.ID].Files + 1,
+				Bytes: used[dst.ID].Bytes + file.Size, EncryptedBytes: used[dst.ID].EncryptedBytes + file.EncryptedSize}
+			result.Moves = append(result.Moves, RebalanceMove{FileID: file.ID, Size: file.Size, From: src.ID, To: dst.ID})
+		}
+	}
+
+	for _, v := range s.volumes {
+		result.Volumes = append(result.Volumes, v.stats(used[v.ID]))
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"audit":   "storage",
+		"dry_run": dryRun,
+		"moved":   len(result.Moves),
+		"failed":  len(result.Failed),
+	}).Info("저장소 볼륨 재배치를 마쳤습니다")
+
+	return result, nil
+}
+
+// move 암호화본을 대상 볼륨으로 복사하고 레코드를 갱신한 뒤 원본을 지웁니다
+//
+// 이동하는 동안 파일 락을 가지며, 락을 얻은 뒤 레코드를 다시 읽어 그 사이
+// 영구 삭제되었거나 다른 볼륨으로 옮겨진 파일은 건너뜁니다.
+func (s *storageService) move(ctx context.Context, file *model.File, dst *storageVolume) error {
+	unlock, err := lockFile(ctx, s.locker, file.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := s.fileRepo.GetByID(ctx, file.ID)
+	if err != nil {
+		return fmt.Errorf("파일 레코드 재조회 실패: %w", err)
+	}
+	if current.VolumeID != file.VolumeID || current.EncryptedPath != file.EncryptedPath {
+		return fmt.Errorf("파일이 이미 다른 위치로 옮겨졌습니다: %s", current.VolumeID)
+	}
+	*file = *current
+
+	srcPath := file.EncryptedPath
+	dstPath := filepath.Join(dst.Path, filepath.Base(srcPath))
+
+	if err := copyFileSynced(srcPath, dstPath); err != nil {
+		return err
+	}
+
+	srcVolume := file.VolumeID
+	file.VolumeID = dst.ID
+	file.EncryptedPath = dstPath
+	if err := s.fileRepo.Update(ctx, file); err != nil {
+		file.VolumeID, file.EncryptedPath = srcVolume, srcPath
+		_ = os.Remove(dstPath)
+		return fmt.Errorf("파일 레코드 갱신 실패: %w", err)
+	}
+
+	entry := s.logger.WithFields(logrus.Fields{
+		"audit":   "storage",
+		"file_id": file.ID,
+		"from":    srcVolume,
+		"to":      dst.ID,
+	})
+	if err := os.Remove(srcPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		entry.WithError(err).WithField("path", srcPath).Warn("재배치한 파일의 원본을 지우지 못했습니다 (수동 정리 필요)")
+		return nil
+	}
+
+	entry.Info("파일을 다른 볼륨으로 옮겼습니다")
+	return nil
+}
+
+// usage 볼륨별 사용량을 조회합니다
+func (s *storageService) usage(ctx context.Context) (map[string]repository.VolumeUsage, error) {
+	usage, err := s.fileRepo.UsageByVolume(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]repository.VolumeUsage, len(usage))
+	for _, u := range usage {
+		byID[u.VolumeID] = u
+	}
+	return byID, nil
+}
+
+// online 볼륨 루트가 접근 가능한 디렉터리인지 확인합니다
+func (v *storageVolume) online() bool {
+	info, err := os.Stat(v.Path)
+	return err == nil && info.IsDir()
+}
+
+// fits 볼륨에 size 바이트를 더 저장할 수 있는지 확인합니다
+func (v *storageVolume) fits(used, size int64) bool {
+	return v.MaxBytes == 0 || used+size <= v.MaxBytes
+}
+
+// stats 사용량으로 볼륨 통계를 만듭니다
+func (v *storageVolume) stats(usage repository.VolumeUsage) VolumeStats {
+	stats := VolumeStats{
+		ID:        v.ID,
+		Path:      v.Path,
+		Weight:    v.Weight,
+		Online:    v.online(),
+		Files:     usage.Files,
+		UsedBytes: usage.Bytes,
+		DiskBytes: usage.EncryptedBytes,
+		MaxBytes:  v.MaxBytes,
+	}
+	if v.MaxBytes > 0 {
+		stats.UsedRatio = float64(usage.Bytes) / float64(v.MaxBytes)
+	}
+	return stats
+}
+
+// weightedUsage 가중치 대비 사용량 (배치 동점 처리용)
+func weightedUsage(used int64, v *storageVolume) float64 {
+	return float64(used) / float64(v.Weight)
+}
+
+// pickRebalanceTarget 파일을 옮겨도 목표 사용량을 넘지 않는 볼륨 중 여유가 가장 큰 볼륨을 고릅니다
+func pickRebalanceTarget(online []*storageVolume, src *storageVolume, size int64,
+	used map[string]repository.VolumeUsage, target map[string]int64) *storageVolume {
+	var (
+		best     *storageVolume
+		bestRoom int64
+	)
+	for _, v := range online {
+		if v == src || !v.fits(used[v.ID].Bytes, size) {
+			continue
+		}
+
+		room := target[v.ID] - used[v.ID].Bytes
+		if room >= size && (best == nil || room > bestRoom) {
+			best, bestRoom = v, room
+		}
+	}
+	return best
+}
+
+// copyFileSynced src를 dst로 복사하고 디스크에 동기화합니다 (임시 파일을 거쳐 원자적으로 생성)
+func copyFileSynced(src, dst string) error {
+	in, err := os.Open(src) //nolint:gosec // 저장소 내부 경로
+	if err != nil {
+		return fmt.Errorf("원본 암호화 파일 열기 실패: %w", err)
+	}
+	defer in.Close()
+
+	partPath := dst + partialFileExt
+	out, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, watchFilePerm) //nolint:gosec // 저장소 내부 랜덤 경로
+	if err != nil {
+		return fmt.Errorf("대상 파일 생성 실패: %w", err)
+	}
+
+	_, err = io.Copy(out, in)
+	if syncErr := out.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(partPath, dst)
+	}
+	if err != nil {
+		_ = os.Remove(partPath)
+		return fmt.Errorf("암호화 파일 복사 실패: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDirStorage dir 하나를 기본 볼륨으로 쓰는 저장소 볼륨 서비스를 생성합니다
+func newDirStorage(t *testing.T, dir string, fileRepo repository.FileRepository) StorageService {
+	t.Helper()
+	storage, err := NewStorageService(config.StorageConfig{Dir: dir}, fileRepo, repository.NewMemoryFileLocker(0), newSilentLogger())
+	require.NoError(t, err)
+	return storage
+}
+
+// setupVolumeTest 볼륨 두 개(hot, cold)를 쓰는 저장소 볼륨 서비스를 구성합니다
+func setupVolumeTest(t *testing.T, hot, cold config.StorageVolume) (StorageService, repository.FileRepository) {
+	t.Helper()
+	for _, v := range []config.StorageVolume{hot, cold} {
+		require.NoError(t, os.MkdirAll(v.Path, 0o750))
+	}
+
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+	storage, err := NewStorageService(config.StorageConfig{Volumes: []config.StorageVolume{hot, cold}}, fileRepo, repository.NewMemoryFileLocker(0), newSilentLogger())
+	require.NoError(t, err)
+	return storage, fileRepo
+}
+
+// createVolumeFile 볼륨에 암호화본이 있는 파일 레코드를 생성합니다
+func createVolumeFile(t *testing.T, fileRepo repository.FileRepository, volume config.StorageVolume, size int64) *model.File {
+	t.Helper()
+	ctx := context.Background()
+	name, err := randomFileName()
+	require.NoError(t, err)
+
+	path := filepath.Join(volume.Path, name)
+	require.NoError(t, os.WriteFile(path, []byte("blob "+name), 0o600))
+
+	file := &model.File{
+		OriginalName:  name,
+		EncryptedPath: path,
+		VolumeID:      volume.ID,
+		Size:          size,
+		MimeType:      "application/octet-stream",
+		ChecksumMD5:   md5Hex([]byte(name)),
+		Status:        model.FileStatusEncrypted,
+	}
+	require.NoError(t, fileRepo.Create(ctx, file))
+	return file
+}
+
+func TestNewStorageService_InvalidVolumes(t *testing.T) {
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+	dir := t.TempDir()
+
+	testCases := []struct {
+		name    string
+		volumes []config.StorageVolume
+	}{
+		{name: "빈 ID", volumes: []config.StorageVolume{{Path: dir}}},
+		{name: "빈 경로", volumes: []config.StorageVolume{{ID: "a"}}},
+		{name: "음수 가중치", volumes: []config.StorageVolume{{ID: "a", Path: dir, Weight: -1}}},
+		{name: "중복 ID", volumes: []config.StorageVolume{{ID: "a", Path: dir}, {ID: "a", Path: dir}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewStorageService(config.StorageConfig{Volumes: tc.volumes}, fileRepo, repository.NewMemoryFileLocker(0), newSilentLogger())
+			assert.ErrorIs(t, err, ErrInvalidVolumes)
+		})
+	}
+
+	assert.Panics(t, func() {
+		_, _ = NewStorageService(config.StorageConfig{Dir: dir}, nil, repository.NewMemoryFileLocker(0), newSilentLogger())
+	})
+}
+
+func TestStorageService_Place(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	hot := config.StorageVolume{ID: "hot", Path: filepath.Join(root, "hot"), Weight: 1, MaxBytes: 1000}
+	cold := config.StorageVolume{ID: "cold", Path: filepath.Join(root, "cold"), Weight: 3, MaxBytes: 1000}
+	storage, fileRepo := setupVolumeTest(t, hot, cold)
+
+	// 남은 용량이 같으면 가중치가 큰 볼륨
+	volumeID, path, err := storage.Place(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "cold", volumeID)
+	assert.Equal(t, cold.Path, filepath.Dir(path))
+
+	// cold의 남은 용량 × 가중치(100 × 3)가 hot(1000 × 1)보다 작아지면 hot
+	createVolumeFile(t, fileRepo, cold, 900)
+	volumeID, _, err = storage.Place(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "hot", volumeID)
+
+	// 어느 볼륨에도 들어가지 않으면 용량 부족
+	_, _, err = storage.Place(ctx, 1001)
+	assert.ErrorIs(t, err, ErrNoVolumeCapacity)
+
+	// 오프라인 볼륨에는 배치하지 않음
+	require.NoError(t, os.RemoveAll(hot.Path))
+	volumeID, _, err = storage.Place(ctx, 50)
+	require.NoError(t, err)
+	assert.Equal(t, "cold", volumeID)
+}
+
+func TestStorageService_PlaceDefaultVolume(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "storage")
+	storage := newDirStorage(t, dir, repository.NewFileRepository(setupServiceTestDB(t)))
+
+	// STORAGE_DIR 단일 볼륨은 없으면 생성 (기존 동작)
+	volumeID, path, err := storage.Place(ctx, 1<<40)
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultStorageVolumeID, volumeID)
+	assert.Equal(t, dir, filepath.Dir(path))
+	assert.DirExists(t, dir)
+}
+
+func TestStorageService_Locate(t *testing.T) {
+	root := t.TempDir()
+	hot := config.StorageVolume{ID: "hot", Path: filepath.Join(root, "hot")}
+	cold := config.StorageVolume{ID: "cold", Path: filepath.Join(root, "cold")}
+	storage, fileRepo := setupVolumeTest(t, hot, cold)
+
+	file := createVolumeFile(t, fileRepo, hot, 10)
+	path, err := storage.Locate(file)
+	require.NoError(t, err)
+	assert.Equal(t, file.EncryptedPath, path)
+
+	// 볼륨 도입 전 레코드는 경로를 그대로 사용
+	legacy := &model.File{ID: 99, EncryptedPath: "/old/storage/file.enc"}
+	path, err = storage.Locate(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, legacy.EncryptedPath, path)
+
+	// 설정에서 빠진 볼륨과 오프라인 볼륨
+	_, err = storage.Locate(&model.File{ID: 100, VolumeID: "removed"})
+	assert.ErrorIs(t, err, ErrVolumeOffline)
+
+	require.NoError(t, os.RemoveAll(hot.Path))
+	_, err = storage.Locate(file)
+	assert.ErrorIs(t, err, ErrVolumeOffline)
+	assert.Contains(t, err.Error(), "hot")
+}
+
+func TestStorageService_Stats(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	hot := config.StorageVolume{ID: "hot", Path: filepath.Join(root, "hot"), MaxBytes: 1000}
+	cold := config.StorageVolume{ID: "cold", Path: filepath.Join(root, "cold")}
+	storage, fileRepo := setupVolumeTest(t, hot, cold)
+
+	createVolumeFile(t, fileRepo, hot, 100)
+	createVolumeFile(t, fileRepo, hot, 150)
+
+	// 소프트 삭제된 파일도 암호화본이 남아 있으므로 사용량에 포함
+	deleted := createVolumeFile(t, fileRepo, hot, 250)
+	require.NoError(t, fileRepo.Delete(ctx, deleted.ID))
+	require.NoError(t, os.RemoveAll(cold.Path))
+
+	stats, err := storage.Stats(context.Background())
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, "hot", stats[0].ID)
+	assert.True(t, stats[0].Online)
+	assert.Equal(t, int64(3), stats[0].Files)
+	assert.Equal(t, int64(500), stats[0].UsedBytes)
+	assert.InDelta(t, 0.5, stats[0].UsedRatio, 1e-9)
+	assert.Equal(t, 1, stats[0].Weight)
+
+	assert.Equal(t, "cold", stats[1].ID)
+	assert.False(t, stats[1].Online)
+	assert.Zero(t, stats[1].UsedBytes)
+}
+
+func TestStorageService_CheckVolumes(t *testing.T) {
+	root := t.TempDir()
+	hot := config.StorageVolume{ID: "hot", Path: filepath.Join(root, "hot")}
+	cold := config.StorageVolume{ID: "cold", Path: filepath.Join(root, "cold")}
+	storage, _ := setupVolumeTest(t, hot, cold)
+
+	require.NoError(t, storage.CheckVolumes(context.Background()))
+
+	require.NoError(t, os.RemoveAll(cold.Path))
+	err := storage.CheckVolumes(context.Background())
+	assert.ErrorIs(t, err, ErrVolumeOffline)
+	assert.Contains(t, err.Error(), "cold")
+	assert.NotContains(t, err.Error(), "hot")
+}
+
+func TestStorageService_Rebalance(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	hot := config.StorageVolume{ID: "hot", Path: filepath.Join(root, "hot")}
+	cold := config.StorageVolume{ID: "cold", Path: filepath.Join(root, "cold")}
+	storage, fileRepo := setupVolumeTest(t, hot, cold)
+
+	var files []*model.File
+	for _, size := range []int64{400, 300, 200, 100} {
+		files = append(files, createVolumeFile(t, fileRepo, hot, size))
+	}
+
+	// dry run은 계획만 반환
+	plan, err := storage.Rebalance(context.Background(), true, 0)
+	require.NoError(t, err)
+	assert.True(t, plan.DryRun)
+	require.NotEmpty(t, plan.Moves)
+	assert.FileExists(t, files[0].EncryptedPath)
+
+	// 같은 가중치이므로 500바이트씩 나뉘도록 큰 파일부터, 목표를 넘지 않는 파일만 이동
+	result, err := storage.Rebalance(context.Background(), false, 0)
+	require.NoError(t, err)
+	assert.Equal(t, plan.Moves, result.Moves)
+	assert.Empty(t, result.Failed)
+	assert.Equal(t, []RebalanceMove{
+		{FileID: files[0].ID, Size: 400, From: "hot", To: "cold"},
+		{FileID: files[3].ID, Size: 100, From: "hot", To: "cold"},
+	}, result.Moves)
+
+	moved, err := fileRepo.GetByID(ctx, files[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "cold", moved.VolumeID)
+	assert.Equal(t, cold.Path, filepath.Dir(moved.EncryptedPath))
+	assert.FileExists(t, moved.EncryptedPath)
+	assert.NoFileExists(t, files[0].EncryptedPath)
+
+	content, err := os.ReadFile(moved.EncryptedPath)
+	require.NoError(t, err)
+	assert.Equal(t, "blob "+files[0].OriginalName, string(content))
+
+	stats, err := storage.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), stats[0].UsedBytes)
+	assert.Equal(t, int64(500), stats[1].UsedBytes)
+	assert.Equal(t, stats, result.Volumes)
+
+	// 더 옮길 파일이 없으면 아무것도 하지 않음
+	again, err := storage.Rebalance(context.Background(), false, 0)
+	require.NoError(t, err)
+	assert.Empty(t, again.Moves)
+}
+
+func TestStorageService_RebalanceSkipsOfflineVolume(t *testing.T) {
+	root := t.TempDir()
+	hot := config.StorageVolume{ID: "hot", Path: filepath.Join(root, "hot")}
+	cold := config.StorageVolume{ID: "cold", Path: filepath.Join(root, "cold")}
+	storage, fileRepo := setupVolumeTest(t, hot, cold)
+
+	file := createVolumeFile(t, fileRepo, hot, 400)
+	require.NoError(t, os.RemoveAll(cold.Path))
+
+	result, err := storage.Rebalance(context.Background(), false, 0)
+	require.NoError(t, err)
+	assert.Empty(t, result.Moves)
+	assert.FileExists(t, file.EncryptedPath)
+}
+
+// sequenceNames 정해진 이름을 차례로 내주는 파일명 생성기 (다 쓰면 마지막 이름 반복)
+func sequenceNames(names ...string) func() (string, error) {
+	var mu sync.Mutex
+	return func() (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		name := names[0]
+		if len(names) > 1 {
+			names = names[1:]
+		}
+		return name, nil
+	}
+}
+
+func TestStorageService_ReservePath(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+	storage := newDirStorage(t, dir, fileRepo).(*storageService)
+
+	// 레코드가 가리키는 경로(소프트 삭제 포함)와 업로드 중인 경로는 건너뜀
+	recorded := &model.File{
+		OriginalName:  "old.pdf",
+		EncryptedPath: filepath.Join(dir, "recorded"+EncryptedFileExt),
+		Size:          10,
+		MimeType:      "application/pdf",
+		ChecksumMD5:   "d41d8cd98f00b204e9800998ecf8427e",
+		Status:        model.FileStatusEncrypted,
+	}
+	require.NoError(t, fileRepo.Create(ctx, recorded))
+	require.NoError(t, fileRepo.Delete(ctx, recorded.ID))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "uploading"+EncryptedFileExt+partialFileExt), nil, 0o600))
+
+	storage.newName = sequenceNames("recorded"+EncryptedFileExt, "uploading"+EncryptedFileExt, "free"+EncryptedFileExt)
+	volumeID, path, release, err := storage.ReservePath(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultStorageVolumeID, volumeID)
+	assert.Equal(t, filepath.Join(dir, "free"+EncryptedFileExt), path)
+	assert.FileExists(t, path+pathReservationExt)
+	assert.NoFileExists(t, recorded.EncryptedPath+pathReservationExt, "쓰이는 경로의 예약은 바로 반납")
+
+	// 예약 중인 경로는 다른 업로드가 받지 못함
+	storage.newName = sequenceNames("free"+EncryptedFileExt, "next"+EncryptedFileExt)
+	_, next, releaseNext, err := storage.ReservePath(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "next"+EncryptedFileExt), next)
+	releaseNext()
+
+	// 반납은 여러 번 호출해도 안전하고, 반납한 경로는 다시 예약 가능
+	release()
+	release()
+	assert.NoFileExists(t, path+pathReservationExt)
+	storage.newName = sequenceNames("free" + EncryptedFileExt)
+	_, again, release, err := storage.ReservePath(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, path, again)
+
+	// 계속 겹치면 포기
+	_, _, _, err = storage.ReservePath(ctx, 10)
+	assert.ErrorIs(t, err, ErrPathReservation)
+	release()
+}
+
+func TestStorageService_ReservePathConcurrent(t *testing.T) {
+	const uploads = 1000
+	ctx := context.Background()
+	dir := t.TempDir()
+	storage := newDirStorage(t, dir, repository.NewFileRepository(setupServiceTestDB(t))).(*storageService)
+
+	// 이름마다 두 업로드가 동시에 고르도록 해 충돌을 강제
+	var counter atomic.Int64
+	storage.newName = func() (string, error) {
+		return fmt.Sprintf("%032x%s", counter.Add(1)/2, EncryptedFileExt), nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		paths    = make(map[string]int, uploads)
+		releases = make([]func(), 0, uploads)
+		errs     []error
+	)
+	for range uploads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, path, release, err := storage.ReservePath(ctx, 1)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			paths[path]++
+			releases = append(releases, release)
+		}()
+	}
+	wg.Wait()
+
+	require.Empty(t, errs)
+	assert.Len(t, paths, uploads, "모든 업로드가 서로 다른 경로를 받음")
+	for path, n := range paths {
+		assert.Equal(t, 1, n, path)
+	}
+
+	for _, release := range releases {
+		release()
+	}
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "반납하면 예약 파일이 남지 않음")
+}
+
+func TestStorageService_PruneReservations(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	storage := newDirStorage(t, dir, repository.NewFileRepository(setupServiceTestDB(t))).(*storageService)
+
+	now := time.Now()
+	storage.clock = clock.NewFake(now)
+
+	stale := filepath.Join(dir, "stale"+EncryptedFileExt)
+	require.NoError(t, os.WriteFile(stale+pathReservationExt, nil, 0o600))
+	require.NoError(t, os.WriteFile(stale+partialFileExt, []byte("partial"), 0o600))
+	old := now.Add(-PathReservationTTL - time.Minute)
+	require.NoError(t, os.Chtimes(stale+pathReservationExt, old, old))
+
+	fresh := filepath.Join(dir, "fresh"+EncryptedFileExt)
+	require.NoError(t, os.WriteFile(fresh+pathReservationExt, nil, 0o600))
+	require.NoError(t, os.WriteFile(fresh+partialFileExt, []byte("partial"), 0o600))
+
+	pruned, err := storage.PruneReservations(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+	assert.NoFileExists(t, stale+pathReservationExt)
+	assert.NoFileExists(t, stale+partialFileExt, "끝나지 못한 업로드 임시 파일도 정리")
+	assert.FileExists(t, fresh+pathReservationExt, "진행 중일 수 있는 예약은 유지")
+	assert.FileExists(t, fresh+partialFileExt)
+
+	// 아직 만들어지지 않은 기본 볼륨은 건너뜀
+	empty := newDirStorage(t, filepath.Join(dir, "missing"), repository.NewFileRepository(setupServiceTestDB(t)))
+	pruned, err = empty.PruneReservations(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+}
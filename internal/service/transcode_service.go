@@ -0,0 +1,379 @@
+// Package service provides business logic for DataLocker.
+// This file implements re-encryption of stored files to another password without temporary plaintext.
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/crypto"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMaxConcurrentTranscodes 동시에 진행할 수 있는 기본 트랜스코드 수
+//
+// 트랜스코드 하나는 복호화 청크(암호문과 평문)와 암호화 청크 버퍼를 합쳐 약
+// 청크 3개(3MB)를 상주시키므로, 전체 상주 메모리는 이 값에 비례합니다.
+const DefaultMaxConcurrentTranscodes = 4
+
+// transcodeCopyBufferSize 평문을 암호화 Writer로 넘기는 복사 버퍼 크기
+const transcodeCopyBufferSize = 32 * 1024
+
+// 트랜스코드 서비스 에러
+var (
+	ErrTranscodeFileNotFound     = errors.New("파일을 찾을 수 없습니다")
+	ErrTranscodeFileNotEncrypted = errors.New("암호화가 완료되지 않은 파일입니다")
+	ErrTranscodeInvalidProfile   = errors.New("잘못된 트랜스코드 프로필입니다")
+	ErrTranscodeBusy             = errors.New("동시에 진행할 수 있는 트랜스코드 수를 초과했습니다")
+	ErrTranscodeInterrupted      = errors.New("트랜스코드가 중간에 중단되었습니다")
+
+	// ErrTranscodeSourcePassword 원본 패스워드가 없거나 일치하지 않음
+	ErrTranscodeSourcePassword = errors.New("원본 패스워드가 일치하지 않습니다")
+
+	// ErrTranscodeTargetPassword 대상 패스워드로 암호화할 수 없음 (원본 패스워드 확인과 별개)
+	ErrTranscodeTargetPassword = errors.New("대상 패스워드가 올바르지 않습니다")
+)
+
+// TranscodeProfile 재암호화 결과의 형식
+type TranscodeProfile struct {
+	// Format 출력 형식 (binary, armored, 빈 값은 binary)
+	Format string `json:"format"`
+
+	// Compression 청크 압축 (none, gzip, 빈 값은 업로드와 같이 MIME 타입으로 결정)
+	Compression string `json:"compression"`
+
+	// Iterations 대상 키 슬롯의 PBKDF2 반복 횟수 (0이면 서버 설정값)
+	Iterations int `json:"iterations"`
+}
+
+// TranscodeRequest 트랜스코드 요청 (파일 ID는 경로에서 받음)
+type TranscodeRequest struct {
+	FileID         uint             `json:"-"`
+	SourcePassword string           `json:"source_password"`
+	TargetPassword string           `json:"target_password"`
+	Profile        TranscodeProfile `json:"profile"`
+}
+
+// TranscodeService 저장된 파일을 다른 패스워드로 다시 암호화해 내보내는 서비스
+type TranscodeService interface {
+	// Open 원본 패스워드, 대상 패스워드, 프로필을 모두 확인하고 스트림을 준비합니다
+	//
+	// 응답을 보내기 전에 판단할 수 있는 실패(파일 없음, 패스워드 오류, 잘못된
+	// 프로필, 동시 실행 초과)는 모두 여기서 반환하므로, 호출자는 Open이 성공한
+	// 뒤에만 응답 헤더를 보내면 됩니다. 반환된 스트림은 반드시 Close해야 합니다.
+	Open(ctx context.Context, req *TranscodeRequest) (*TranscodeStream, error)
+}
+
+// transcodeService 트랜스코드 서비스 구현체
+type transcodeService struct {
+	fileRepo   repository.FileRepository
+	storage    StorageService
+	iterations int
+	slots      chan struct{} // 동시 실행 제한 (버퍼 크기만큼 진행 가능)
+	logger     *logrus.Logger
+}
+
+// NewTranscodeService 새로운 트랜스코드 서비스를 생성합니다
+//
+// iterations는 프로필에 반복 횟수가 없을 때 쓰는 값으로 0이면 crypto.PBKDF2Iterations,
+// maxConcurrent가 0 이하이면 DefaultMaxConcurrentTranscodes를 사용합니다.
+func NewTranscodeService(
+	fileRepo repository.FileRepository,
+	storage StorageService,
+	iterations, maxConcurrent int,
+	logger *logrus.Logger,
+) TranscodeService {
+	if fileRepo == nil {
+		panic("파일 저장소가 필요합니다")
+	}
+
+	if storage == nil {
+		panic("저장소 볼륨 서비스가 필요합니다")
+	}
+
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	if iterations == 0 {
+		iterations = crypto.PBKDF2Iterations
+	}
+
+	if maxConcurrent <= 0 {
+		maxConcurrent = DefaultMaxConcurrentTranscodes
+	}
+
+	return &transcodeService{
+		fileRepo:   fileRepo,
+		storage:    storage,
+		iterations: iterations,
+		slots:      make(chan struct{}, maxConcurrent),
+		logger:     logger,
+	}
+}
+
+// Open 파일을 열고 원본 패스워드로 첫 청크까지 복호화해 확인합니다
+func (s *transcodeService) Open(ctx context.Context, req *TranscodeRequest) (*TranscodeStream, error) {
+	if req.SourcePassword == "" {
+		return nil, fmt.Errorf("%w: 원본 패스워드가 필요합니다", ErrTranscodeSourcePassword)
+	}
+
+	if req.TargetPassword == "" {
+		return nil, fmt.Errorf("%w: 대상 패스워드가 필요합니다", ErrTranscodeTargetPassword)
+	}
+
+	file, err := s.lookup(ctx, req.FileID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !file.IsEncrypted() {
+		return nil, fmt.Errorf("%w: 상태 %s", ErrTranscodeFileNotEncrypted, file.Status)
+	}
+
+	format, options, err := s.profileOptions(file, req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// 슬롯은 스트림을 Close할 때 반납 (기다리지 않고 바로 거절)
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		return nil, ErrTranscodeBusy
+	}
+
+	stream, err := s.open(ctx, file, req, format, options)
+	if err != nil {
+		<-s.slots
+		return nil, err
+	}
+	return stream, nil
+}
+
+// open 원본을 열어 복호화를 시작하고 스트림을 만듭니다
+func (s *transcodeService) open(
+	ctx context.Context,
+	file *model.File,
+	req *TranscodeRequest,
+	format crypto.OutputFormat,
+	options []crypto.Option,
+) (*TranscodeStream, error) {
+	// 참조 레코드는 원본 blob을 읽음 (원본이 소프트 삭제되어도 blob은 남아 있음)
+	blob := file
+	if file.IsBlobReference() {
+		var err error
+		if blob, err = s.fileRepo.GetByIDWithDeleted(ctx, *file.BlobFileID); err != nil {
+			return nil, fmt.Errorf("원본 blob 조회 실패: %w", err)
+		}
+	}
+
+	path, err := s.storage.Locate(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	// 연 파일은 스트리밍 중 영구 삭제나 볼륨 이동으로 경로가 사라져도 끝까지 읽을 수 있음
+	src, err := os.Open(path) //nolint:gosec // 저장소에 기록된 경로
+	if err != nil {
+		return nil, fmt.Errorf("암호화 파일 열기 실패: %w", err)
+	}
+
+	dec, err := crypto.NewDecryptReader(src, req.SourcePassword)
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("복호화 준비 실패: %w", err)
+	}
+
+	// 첫 청크까지 복호화해 패스워드를 확인 (응답 헤더를 보내기 전에 401로 구분)
+	plain := bufio.NewReader(dec)
+	if _, err := plain.Peek(1); err != nil && err != io.EOF {
+		src.Close()
+		// 레거시 포맷은 첫 청크 실패를 구분할 수 없으므로 손상이 아닌 인증 실패는 패스워드 오류로 봄
+		if errors.Is(err, crypto.ErrWrongPassword) ||
+			(errors.Is(err, crypto.ErrAuthenticationFailed) && !errors.Is(err, crypto.ErrCorruptedChunk)) {
+			return nil, ErrTranscodeSourcePassword
+		}
+		return nil, fmt.Errorf("원본 복호화 실패: %w", err)
+	}
+
+	contentType := "application/octet-stream"
+	if format == crypto.OutputArmored {
+		contentType = "text/plain; charset=us-ascii"
+	}
+
+	return &TranscodeStream{
+		FileID:      file.ID,
+		FileName:    file.OriginalName + EncryptedFileExt,
+		ContentType: contentType,
+		ctx:         ctx,
+		service:     s,
+		src:         src,
+		plain:       plain,
+		target:      []byte(req.TargetPassword),
+		format:      format,
+		options:     options,
+	}, nil
+}
+
+// profileOptions 프로필을 출력 형식과 암호화 옵션으로 바꿉니다
+func (s *transcodeService) profileOptions(file *model.File, profile TranscodeProfile) (crypto.OutputFormat, []crypto.Option, error) {
+	format, err := crypto.ParseOutputFormat(profile.Format)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrTranscodeInvalidProfile, err)
+	}
+
+	compression := crypto.CompressionForMIME(file.MimeType)
+	if profile.Compression != "" {
+		if compression, err = crypto.ParseCompression(profile.Compression); err != nil {
+			return "", nil, fmt.Errorf("%w: %v", ErrTranscodeInvalidProfile, err)
+		}
+	}
+
+	iterations := s.iterations
+	if profile.Iterations != 0 {
+		if profile.Iterations < crypto.MinIterations || profile.Iterations > crypto.MaxIterations {
+			return "", nil, fmt.Errorf("%w: 반복 횟수 %d (허용: %d ~ %d)",
+				ErrTranscodeInvalidProfile, profile.Iterations, crypto.MinIterations, crypto.MaxIterations)
+		}
+		iterations = profile.Iterations
+	}
+
+	return format, []crypto.Option{crypto.WithCompression(compression), crypto.WithIterations(iterations)}, nil
+}
+
+// lookup 파일을 조회하고 없으면 ErrTranscodeFileNotFound를 반환합니다
+func (s *transcodeService) lookup(ctx context.Context, fileID uint) (*model.File, error) {
+	if fileID == 0 {
+		return nil, fmt.Errorf("%w: ID %d", ErrTranscodeFileNotFound, fileID)
+	}
+
+	file, err := s.fileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: ID %d", ErrTranscodeFileNotFound, fileID)
+		}
+		return nil, fmt.Errorf("파일 조회 실패: %w", err)
+	}
+	return file, nil
+}
+
+// TranscodeStream 원본 패스워드 확인을 마친 재암호화 스트림
+//
+// 평문은 디스크에 쓰지 않고 복호화 Reader에서 암호화 Writer로 바로 넘기며,
+// 상주 메모리는 파일 크기와 관계없이 청크 몇 개로 제한됩니다.
+type TranscodeStream struct {
+	FileID      uint
+	FileName    string // 내려받을 파일 이름 (원본 이름 + EncryptedFileExt)
+	ContentType string
+
+	ctx     context.Context
+	service *transcodeService
+	src     *os.File
+	plain   *bufio.Reader
+	target  []byte // 대상 패스워드 (암호화 Writer를 만든 직후 0으로 덮어씀)
+	format  crypto.OutputFormat
+	options []crypto.Option
+	closed  bool
+}
+
+// WriteTo 재암호화한 스트림을 w에 기록하고 기록한 바이트 수를 반환합니다
+//
+// 중간에 실패하면(원본 청크 손상, 연결 끊김, ctx 취소) 종료 레코드와 MAC
+// 트레일러, armored 끝 줄을 쓰지 않고 ErrTranscodeInterrupted를 감싼 에러를
+// 반환합니다. 이미 보낸 앞부분은 받는 쪽에서 잘린 스트림(crypto.ErrTruncatedStream)
+// 으로 거부되므로 불완전한 결과가 정상 파일로 복호화되는 일은 없습니다.
+func (t *TranscodeStream) WriteTo(w io.Writer) (int64, error) {
+	out := &countingWriter{writer: w}
+	err := t.transcode(out)
+	if err != nil {
+		t.service.logger.WithError(err).WithFields(logrus.Fields{
+			"file_id":       t.FileID,
+			"written_bytes": out.n,
+		}).Warn("트랜스코드가 중간에 중단되었습니다")
+		return out.n, err
+	}
+
+	// 보존 정책용 접근 기록 (결과는 이미 보냈으므로 기록 실패로 응답을 막지 않음)
+	_ = t.service.fileRepo.Touch(t.ctx, t.FileID)
+
+	return out.n, nil
+}
+
+// transcode 평문을 대상 패스워드로 암호화해 out에 기록합니다
+func (t *TranscodeStream) transcode(out io.Writer) error {
+	if t.closed {
+		return fmt.Errorf("%w: 이미 닫힌 스트림입니다", ErrTranscodeInterrupted)
+	}
+
+	dst := out
+	var armor io.WriteCloser
+	if t.format == crypto.OutputArmored {
+		var err error
+		if armor, err = crypto.NewArmorWriter(out); err != nil {
+			return fmt.Errorf("%w: %w", ErrTranscodeInterrupted, err)
+		}
+		dst = armor
+	}
+
+	// 대상 패스워드와 옵션은 Open에서 확인했으므로 여기서의 실패는 출력 실패
+	enc, err := crypto.NewEncryptWriterBytes(dst, t.target, t.options...)
+	crypto.ZeroBytes(t.target)
+	if err != nil {
+		return fmt.Errorf("%w: 헤더 기록 실패: %w", ErrTranscodeInterrupted, err)
+	}
+
+	reader := &uploadReader{ctx: t.ctx, reader: t.plain}
+	buffer := make([]byte, transcodeCopyBufferSize)
+	if _, err := io.CopyBuffer(enc, reader, buffer); err != nil {
+		// 종료 레코드를 쓰지 않도록 enc를 닫지 않음
+		if reader.err != nil {
+			return fmt.Errorf("%w: 원본 복호화 실패: %w", ErrTranscodeInterrupted, reader.err)
+		}
+		return fmt.Errorf("%w: 출력 실패: %w", ErrTranscodeInterrupted, err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("%w: 종료 레코드 기록 실패: %w", ErrTranscodeInterrupted, err)
+	}
+
+	if armor != nil {
+		if err := armor.Close(); err != nil {
+			return fmt.Errorf("%w: armored 끝 줄 기록 실패: %w", ErrTranscodeInterrupted, err)
+		}
+	}
+
+	return nil
+}
+
+// Close 원본 파일을 닫고 동시 실행 슬롯을 반납합니다 (여러 번 호출해도 안전)
+func (t *TranscodeStream) Close() error {
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+
+	crypto.ZeroBytes(t.target)
+	<-t.service.slots
+	return t.src.Close()
+}
+
+// countingWriter 기록한 바이트 수를 세는 Writer
+type countingWriter struct {
+	writer io.Writer
+	n      int64
+}
+
+// Write 기록하고 바이트 수를 더합니다
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/crypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transcodeTargetPassword 재암호화 대상 패스워드
+const transcodeTargetPassword = "target-password"
+
+// setupTranscodeTest 낮은 반복 횟수의 업로드 서비스와 동시 실행이 1개인 트랜스코드 서비스를 생성합니다
+func setupTranscodeTest(t *testing.T) (UploadService, TranscodeService, repository.FileRepository) {
+	t.Helper()
+	db := setupServiceTestDB(t)
+	fileRepo := repository.NewFileRepository(db)
+	storage := newDirStorage(t, filepath.Join(t.TempDir(), "storage"), fileRepo)
+	upload := NewUploadService(storage, 1000, repository.NewTxManager(db), nil, newSilentLogger())
+	return upload, NewTranscodeService(fileRepo, storage, 1000, 1, newSilentLogger()), fileRepo
+}
+
+// failingWriter limit 바이트를 받은 뒤부터 실패하는 Writer
+type failingWriter struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.limit {
+		return 0, errors.New("연결이 끊겼습니다")
+	}
+	return w.buf.Write(p)
+}
+
+func TestTranscodeService_ReencryptsToTargetPassword(t *testing.T) {
+	ctx := context.Background()
+	upload, svc, fileRepo := setupTranscodeTest(t)
+
+	content := bytes.Repeat([]byte("transcode "), 150000) // 청크 여러 개
+	file := uploadContent(t, upload, "report.txt", "text/plain", content)
+
+	stream, err := svc.Open(ctx, &TranscodeRequest{
+		FileID:         file.ID,
+		SourcePassword: uploadTestPassword,
+		TargetPassword: transcodeTargetPassword,
+		Profile:        TranscodeProfile{Compression: "none"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "report.txt"+EncryptedFileExt, stream.FileName)
+	assert.Equal(t, "application/octet-stream", stream.ContentType)
+
+	var out bytes.Buffer
+	n, err := stream.WriteTo(&out)
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+	assert.Equal(t, int64(out.Len()), n)
+
+	// 대상 패스워드로만 열림
+	ok, err := crypto.CheckPassword(bytes.NewReader(out.Bytes()), uploadTestPassword)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	info, err := crypto.ReadStreamInfo(bytes.NewReader(out.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, crypto.CompressionNone, info.Compression)
+	assert.Equal(t, 1000, info.KeySlots[0].Iterations)
+
+	var plain bytes.Buffer
+	require.NoError(t, crypto.NewCryptoEngine().DecryptStream(bytes.NewReader(out.Bytes()), &plain, transcodeTargetPassword))
+	assert.Equal(t, content, plain.Bytes())
+
+	stored, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastAccessedAt)
+}
+
+func TestTranscodeService_ArmoredProfile(t *testing.T) {
+	upload, svc, _ := setupTranscodeTest(t)
+	file := uploadContent(t, upload, "notes.txt", "text/plain", []byte("armored transcode"))
+
+	stream, err := svc.Open(context.Background(), &TranscodeRequest{
+		FileID:         file.ID,
+		SourcePassword: uploadTestPassword,
+		TargetPassword: transcodeTargetPassword,
+		Profile:        TranscodeProfile{Format: "armored", Iterations: 2000},
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var out bytes.Buffer
+	_, err = stream.WriteTo(&out)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out.String(), crypto.ArmorHeader))
+
+	var plain bytes.Buffer
+	require.NoError(t, crypto.NewCryptoEngine().DecryptStreamArmored(&out, &plain, transcodeTargetPassword))
+	assert.Equal(t, "armored transcode", plain.String())
+}
+
+func TestTranscodeService_RejectsBeforeStreaming(t *testing.T) {
+	ctx := context.Background()
+	upload, svc, _ := setupTranscodeTest(t)
+	file := uploadContent(t, upload, "a.txt", "text/plain", []byte("content"))
+
+	valid := func() *TranscodeRequest {
+		return &TranscodeRequest{FileID: file.ID, SourcePassword: uploadTestPassword, TargetPassword: transcodeTargetPassword}
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(req *TranscodeRequest)
+		want   error
+	}{
+		{"원본 패스워드 불일치", func(req *TranscodeRequest) { req.SourcePassword = "wrong" }, ErrTranscodeSourcePassword},
+		{"원본 패스워드 없음", func(req *TranscodeRequest) { req.SourcePassword = "" }, ErrTranscodeSourcePassword},
+		{"대상 패스워드 없음", func(req *TranscodeRequest) { req.TargetPassword = "" }, ErrTranscodeTargetPassword},
+		{"없는 파일", func(req *TranscodeRequest) { req.FileID = file.ID + 100 }, ErrTranscodeFileNotFound},
+		{"잘못된 형식", func(req *TranscodeRequest) { req.Profile.Format = "pem" }, ErrTranscodeInvalidProfile},
+		{"잘못된 압축", func(req *TranscodeRequest) { req.Profile.Compression = "zstd" }, ErrTranscodeInvalidProfile},
+		{"반복 횟수 범위 밖", func(req *TranscodeRequest) { req.Profile.Iterations = 10 }, ErrTranscodeInvalidProfile},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := valid()
+			tc.modify(req)
+			_, err := svc.Open(ctx, req)
+			assert.ErrorIs(t, err, tc.want)
+		})
+	}
+
+	// 실패한 요청은 동시 실행 슬롯을 차지하지 않음
+	stream, err := svc.Open(ctx, valid())
+	require.NoError(t, err)
+
+	_, err = svc.Open(ctx, valid())
+	assert.ErrorIs(t, err, ErrTranscodeBusy)
+
+	require.NoError(t, stream.Close())
+	require.NoError(t, stream.Close())
+	stream, err = svc.Open(ctx, valid())
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+}
+
+func TestTranscodeService_InterruptedOutputIsTruncated(t *testing.T) {
+	upload, svc, fileRepo := setupTranscodeTest(t)
+	content := bytes.Repeat([]byte{0x5A}, 3*crypto.ChunkSize)
+	file := uploadContent(t, upload, "big.bin", "application/octet-stream", content)
+
+	stream, err := svc.Open(context.Background(), &TranscodeRequest{
+		FileID:         file.ID,
+		SourcePassword: uploadTestPassword,
+		TargetPassword: transcodeTargetPassword,
+		Profile:        TranscodeProfile{Compression: "none"},
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	// 헤더와 첫 청크 일부만 보낸 뒤 연결이 끊김
+	out := &failingWriter{limit: crypto.ChunkSize + crypto.ChunkSize/2}
+	n, err := stream.WriteTo(out)
+	assert.ErrorIs(t, err, ErrTranscodeInterrupted)
+	assert.Equal(t, int64(out.buf.Len()), n)
+
+	// 받은 앞부분은 정상 파일로 복호화되지 않음
+	err = crypto.VerifyStream(bytes.NewReader(out.buf.Bytes()), transcodeTargetPassword)
+	assert.ErrorIs(t, err, crypto.ErrTruncatedStream)
+
+	// 끝까지 보내지 못했으면 접근으로 기록하지 않음
+	stored, err := fileRepo.GetByID(context.Background(), file.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.LastAccessedAt)
+}
+
+func TestTranscodeService_CanceledContext(t *testing.T) {
+	upload, svc, _ := setupTranscodeTest(t)
+	file := uploadContent(t, upload, "big.bin", "application/octet-stream", bytes.Repeat([]byte{1}, 2*crypto.ChunkSize))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := svc.Open(ctx, &TranscodeRequest{
+		FileID:         file.ID,
+		SourcePassword: uploadTestPassword,
+		TargetPassword: transcodeTargetPassword,
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	cancel()
+	_, err = stream.WriteTo(&bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrTranscodeInterrupted)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewTranscodeService_Panics(t *testing.T) {
+	db := setupServiceTestDB(t)
+	fileRepo := repository.NewFileRepository(db)
+	storage := newDirStorage(t, t.TempDir(), fileRepo)
+
+	assert.Panics(t, func() { NewTranscodeService(nil, storage, 0, 0, newSilentLogger()) })
+	assert.Panics(t, func() { NewTranscodeService(fileRepo, nil, 0, 0, newSilentLogger()) })
+	assert.Panics(t, func() { NewTranscodeService(fileRepo, storage, 0, 0, nil) })
+}
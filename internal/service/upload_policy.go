@@ -0,0 +1,159 @@
+// Package service provides business logic for DataLocker.
+// This file builds the machine-readable upload policy document from the validation policy.
+package service
+
+import (
+	"crypto/sha256"
+	_ "embed" // 업로드 정책 스키마 템플릿
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// uploadPolicyVersionSize 정책 버전(스키마 SHA-256 앞부분)의 바이트 수
+const uploadPolicyVersionSize = 8
+
+// uploadPolicySchema 값이 채워지기 전의 업로드 정책 JSON 스키마
+//
+// 필드 설명과 형식처럼 설정과 무관한 부분만 담고, 크기/MIME/파일명 제한은
+// validationPolicy에서 채웁니다.
+//
+//go:embed upload_policy.schema.json
+var uploadPolicySchema []byte
+
+// UploadPolicyDocument 클라이언트 사전 검증용 업로드 정책 문서
+type UploadPolicyDocument struct {
+	Version string // 스키마 내용의 해시 (정책이 같으면 재시작해도 같음)
+	Schema  []byte // JSON 스키마 (x-policy-version 포함)
+}
+
+// newUploadPolicyDocument 검증 정책으로 업로드 정책 문서를 만듭니다
+//
+// 스키마는 검증과 같은 정책 스냅샷에서 만들어지므로 설정 리로드로 정책이
+// 교체되면 문서도 함께 바뀝니다.
+func newUploadPolicyDocument(policy *validationPolicy) (*UploadPolicyDocument, error) {
+	var schema map[string]any
+	if err := json.Unmarshal(uploadPolicySchema, &schema); err != nil {
+		return nil, fmt.Errorf("업로드 정책 스키마 템플릿 해석 실패: %w", err)
+	}
+
+	properties, ok := schema["properties"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("업로드 정책 스키마 템플릿에 properties가 없습니다")
+	}
+
+	name := properties["original_name"].(map[string]any)
+	name["maxLength"] = policy.maxNameLength
+	name["x-max-bytes"] = policy.maxNameLength
+
+	size := properties["size"].(map[string]any)
+	size["exclusiveMinimum"] = MinFileSize
+	size["maximum"] = policy.maxSize()
+
+	mimeType := properties["mime_type"].(map[string]any)
+	mimeType["enum"] = lowerAll(policy.allowedMimeTypes)
+
+	if conditions := policy.sizeConditions(); len(conditions) > 0 {
+		schema["allOf"] = conditions
+	}
+
+	// 스키마만 해석하지 않는 클라이언트를 위해 /api/v1/limits와 같은 값도 함께 제공
+	schema["x-upload-limits"] = policy.limits()
+
+	// 버전은 버전 필드를 넣기 전의 내용으로 계산 (map은 키 순서로 직렬화되어 결정적)
+	body, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("업로드 정책 스키마 직렬화 실패: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	version := hex.EncodeToString(sum[:uploadPolicyVersionSize])
+
+	schema["x-policy-version"] = version
+	body, err = json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("업로드 정책 스키마 직렬화 실패: %w", err)
+	}
+
+	return &UploadPolicyDocument{Version: version, Schema: body}, nil
+}
+
+// maxSize 어떤 MIME 타입이든 허용될 수 있는 가장 큰 크기를 반환합니다
+func (p *validationPolicy) maxSize() int64 {
+	maxSize := p.defaultMaxSize
+	for _, group := range p.groups {
+		maxSize = max(maxSize, group.MaxSize)
+	}
+	return maxSize
+}
+
+// sizeConditions MIME 그룹별 크기 제한을 if/then 조건으로 표현합니다
+//
+// 그룹 조건은 모두 함께 적용되므로 여러 그룹에 속하면 가장 작은 제한이 되고,
+// 어느 그룹에도 속하지 않는 타입에는 기본 제한 조건이 적용됩니다.
+func (p *validationPolicy) sizeConditions() []any {
+	conditions := make([]any, 0, len(p.groups)+1)
+	var allPatterns []string
+	for _, group := range p.groups {
+		// 패턴이 없는 그룹은 검증에서도 어떤 타입과도 일치하지 않음
+		if len(group.Patterns) == 0 {
+			continue
+		}
+
+		pattern := mimeGroupRegexp(group.Patterns)
+		allPatterns = append(allPatterns, pattern)
+		conditions = append(conditions, sizeCondition(
+			map[string]any{"pattern": pattern},
+			group.MaxSize,
+			fmt.Sprintf("%s 그룹 크기 제한", group.Name),
+		))
+	}
+
+	if len(allPatterns) == 0 {
+		return nil
+	}
+
+	conditions = append(conditions, sizeCondition(
+		map[string]any{"not": map[string]any{"pattern": strings.Join(allPatterns, "|")}},
+		p.defaultMaxSize,
+		"어느 그룹에도 속하지 않는 타입의 크기 제한",
+	))
+	return conditions
+}
+
+// sizeCondition mime_type이 조건을 만족하면 size 최대값을 적용하는 스키마 조각
+func sizeCondition(mimeType map[string]any, maxSize int64, description string) map[string]any {
+	return map[string]any{
+		"description": description,
+		"if": map[string]any{
+			"required":   []string{"mime_type"},
+			"properties": map[string]any{"mime_type": mimeType},
+		},
+		"then": map[string]any{
+			"properties": map[string]any{"size": map[string]any{"maximum": maxSize}},
+		},
+	}
+}
+
+// mimeGroupRegexp 그룹 패턴을 정규식 하나로 바꿉니다 ("image/*"는 접두 일치)
+func mimeGroupRegexp(patterns []string) string {
+	parts := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			parts = append(parts, "^"+regexp.QuoteMeta(prefix))
+		} else {
+			parts = append(parts, "^"+regexp.QuoteMeta(pattern)+"$")
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// lowerAll 문자열을 모두 소문자로 바꾼 복사본을 반환합니다
+func lowerAll(values []string) []string {
+	lowered := make([]string, 0, len(values))
+	for _, value := range values {
+		lowered = append(lowered, strings.ToLower(value))
+	}
+	return lowered
+}
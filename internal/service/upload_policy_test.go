@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeUploadPolicy 정책 문서의 스키마를 해석합니다
+func decodeUploadPolicy(t *testing.T, document *UploadPolicyDocument) map[string]any {
+	t.Helper()
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(document.Schema, &schema))
+	return schema
+}
+
+// schemaMaxSize 스키마의 allOf 조건으로 MIME 타입에 적용되는 최대 크기를 계산합니다
+func schemaMaxSize(t *testing.T, schema map[string]any, mimeType string) float64 {
+	t.Helper()
+	properties := schema["properties"].(map[string]any)
+	maxSize := properties["size"].(map[string]any)["maximum"].(float64)
+
+	conditions, _ := schema["allOf"].([]any)
+	for _, raw := range conditions {
+		condition := raw.(map[string]any)
+		mimeRule := condition["if"].(map[string]any)["properties"].(map[string]any)["mime_type"].(map[string]any)
+
+		matched := false
+		if pattern, ok := mimeRule["pattern"].(string); ok {
+			matched = regexp.MustCompile(pattern).MatchString(mimeType)
+		} else {
+			pattern := mimeRule["not"].(map[string]any)["pattern"].(string)
+			matched = !regexp.MustCompile(pattern).MatchString(mimeType)
+		}
+		if matched {
+			limit := condition["then"].(map[string]any)["properties"].(map[string]any)["size"].(map[string]any)["maximum"].(float64)
+			maxSize = min(maxSize, limit)
+		}
+	}
+	return maxSize
+}
+
+func TestValidationService_UploadPolicyMatchesValidation(t *testing.T) {
+	svc := NewValidationService(testUploadPolicy())
+	schema := decodeUploadPolicy(t, svc.UploadPolicy())
+
+	// 스키마의 크기 조건과 검증이 같은 제한을 적용
+	for _, mimeType := range []string{"image/jpeg", "image/png", "application/pdf", "text/plain", "application/zip"} {
+		result, err := svc.ValidateFile(context.Background(), "a", 10, mimeType)
+		require.NoError(t, err)
+		assert.InDelta(t, float64(result.MaxSize), schemaMaxSize(t, schema, mimeType), 0, mimeType)
+	}
+
+	properties := schema["properties"].(map[string]any)
+	assert.InDelta(t, model.MaxOriginalNameLength, properties["original_name"].(map[string]any)["maxLength"], 0)
+	assert.InDelta(t, MinFileSize, properties["size"].(map[string]any)["exclusiveMinimum"], 0)
+	assert.ElementsMatch(t, []any{"text/plain", "application/pdf", "image/jpeg", "image/png"},
+		properties["mime_type"].(map[string]any)["enum"])
+	assert.ElementsMatch(t, []any{"original_name", "size", "mime_type", "checksum_md5"}, schema["required"])
+	assert.Equal(t, svc.UploadPolicy().Version, schema["x-policy-version"])
+	assert.Equal(t, svc.UploadPolicy().Version, svc.Limits().PolicyVersion)
+
+	// 스키마의 파일명 제한은 검증에도 적용됨
+	result, err := svc.ValidateFile(context.Background(), strings.Repeat("a", model.MaxOriginalNameLength+1), 10, "text/plain")
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+}
+
+func TestValidationService_UploadPolicyVersion(t *testing.T) {
+	svc := NewValidationService(testUploadPolicy())
+	before := svc.UploadPolicy()
+
+	// 같은 정책이면 같은 버전 (재시작해도 캐시 유지)
+	assert.Equal(t, before.Version, NewValidationService(testUploadPolicy()).UploadPolicy().Version)
+
+	// 정책을 바꾸면 바로 새 문서
+	svc.UpdatePolicy(config.UploadConfig{DefaultMaxSize: 100, AllowedMimeTypes: []string{"text/CSV"}})
+	after := svc.UploadPolicy()
+	assert.NotEqual(t, before.Version, after.Version)
+
+	schema := decodeUploadPolicy(t, after)
+	properties := schema["properties"].(map[string]any)
+	assert.Equal(t, []any{"text/csv"}, properties["mime_type"].(map[string]any)["enum"])
+	assert.InDelta(t, 100, properties["size"].(map[string]any)["maximum"], 0)
+	assert.NotContains(t, schema, "allOf", "그룹이 없으면 조건도 없음")
+}
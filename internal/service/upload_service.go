@@ -0,0 +1,440 @@
+// Package service provides business logic for DataLocker.
+// This file implements streaming uploads with cleanup of interrupted transfers.
+package service
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // 파일 모델의 체크섬 형식(MD5)과 일치
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/charset"
+	"DataLocker/pkg/crypto"
+	"DataLocker/pkg/simhash"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/datatypes"
+)
+
+// 업로드 관련 상수
+const (
+	// 암호화 중인 임시 파일 확장자 (완료 후 최종 경로로 이동)
+	partialFileExt = ".part"
+)
+
+// 업로드 서비스 에러
+var (
+	ErrInvalidUpload     = errors.New("잘못된 업로드 요청입니다")
+	ErrUploadInterrupted = errors.New("업로드가 중단되었습니다")
+	ErrUploadMismatch    = errors.New("업로드된 내용이 협상된 크기 또는 체크섬과 다릅니다")
+)
+
+// UploadRequest 본문 업로드 요청 (협상에서 확정된 메타데이터 + 패스워드)
+type UploadRequest struct {
+	OriginalName string
+	MimeType     string
+	Size         int64
+	ChecksumMD5  string
+	ExternalID   string // 외부 시스템 참조 ID (선택)
+
+	// 사용자가 붙이는 설명과 키/값 메타데이터 (선택, 협상 요청에서 검증)
+	Description    string
+	CustomMetadata datatypes.JSON
+
+	// Password 암호화 패스워드 (키 슬롯을 만든 직후 0으로 덮어씀)
+	//
+	// 문자열 사본이 힙에 남지 않도록 바이트로 받으며, 호출자도 Upload가 반환된
+	// 뒤 crypto.ZeroBytes로 지워야 합니다 (검증 실패로 일찍 반환한 경우 대비).
+	Password []byte
+}
+
+// UploadResult 업로드 결과
+type UploadResult struct {
+	File *model.File
+
+	// PasswordReuse 같은 패스워드로 암호화된 기존 파일이 임계값 이상이면 채워짐
+	// (재사용 감지가 꺼져 있거나 확인에 실패하면 nil)
+	PasswordReuse *PasswordReuseWarning
+}
+
+// UploadService 업로드 본문을 스트리밍으로 암호화해 저장하는 서비스
+type UploadService interface {
+	// Upload body를 암호화해 저장하고 파일 레코드와 패스워드 재사용 경고를 반환합니다
+	//
+	// 실패하거나 클라이언트 연결이 끊기면 임시 파일을 정리하며, 레코드는 암호화본이
+	// 최종 경로에 있을 때만 남습니다.
+	Upload(ctx context.Context, req *UploadRequest, body io.Reader) (*UploadResult, error)
+}
+
+// uploadService 업로드 서비스 구현체
+type uploadService struct {
+	storage    StorageService
+	iterations int
+	txManager  repository.TxManager
+	reuse      PasswordReuseService // nil이면 패스워드 지문을 저장하지 않음
+	logger     *logrus.Logger
+	rename     func(oldPath, newPath string) error // 임시 파일을 최종 경로로 이동 (테스트에서 교체)
+}
+
+// uploadDigest 수신한 본문의 크기와 해시
+type uploadDigest struct {
+	size         int64
+	checksumMD5  string
+	blockHashes  string
+	textEncoding string
+	similarity   *simhash.Hasher
+}
+
+// NewUploadService 새로운 업로드 서비스를 생성합니다
+//
+// 암호화본은 storage가 고른 볼륨에 저장하며, iterations는 키 슬롯의 PBKDF2
+// 반복 횟수로 0이면 crypto.PBKDF2Iterations를 사용합니다. 레코드와 암호화
+// 메타데이터 생성, 최종 경로 이동은 txManager의 트랜잭션 하나로 묶습니다.
+// reuse가 nil이 아니면 패스워드 지문을 메타데이터에 저장하고 재사용을 경고합니다.
+func NewUploadService(
+	storage StorageService,
+	iterations int,
+	txManager repository.TxManager,
+	reuse PasswordReuseService,
+	logger *logrus.Logger,
+) UploadService {
+	if storage == nil {
+		panic("저장소 볼륨 서비스가 필요합니다")
+	}
+
+	if txManager == nil {
+		panic("트랜잭션 관리자가 필요합니다")
+	}
+
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	if iterations == 0 {
+		iterations = crypto.PBKDF2Iterations
+	}
+
+	return &uploadService{
+		storage:    storage,
+		iterations: iterations,
+		txManager:  txManager,
+		reuse:      reuse,
+		logger:     logger,
+		rename:     os.Rename,
+	}
+}
+
+// Upload 본문을 임시 경로에 암호화한 뒤 레코드를 만들고 최종 경로로 이동합니다
+//
+// 순서는 임시 경로에 암호화 완료 → 트랜잭션으로 File과 암호화 메타데이터 생성 →
+// 같은 트랜잭션 안에서 최종 경로로 이동입니다. 레코드는 암호화가 끝난 뒤에만
+// 만들어지므로 존재하지 않는 EncryptedPath를 가리키는 레코드가 남지 않고, 각
+// 단계의 실패는 discard로 임시 파일 또는 최종 파일을 지워 보상합니다.
+func (s *uploadService) Upload(ctx context.Context, req *UploadRequest, body io.Reader) (*UploadResult, error) {
+	if err := validateUploadRequest(req); err != nil {
+		return nil, err
+	}
+
+	// 패스워드는 암호화 준비 직후 지워지므로 지문을 먼저 계산
+	fingerprint, err := s.fingerprint(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// 동시 업로드가 같은 경로를 고르지 않도록 예약 (끝나면 레코드나 정리된 파일만 남음)
+	volumeID, finalPath, release, err := s.storage.ReservePath(ctx, req.Size)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	partPath := finalPath + partialFileExt
+
+	// 1. 임시 경로에 본문 수신 및 암호화 (레코드는 아직 없음)
+	digest, err := s.receive(ctx, partPath, req, body)
+	if err != nil {
+		s.discard(err, partPath)
+		return nil, err
+	}
+
+	if digest.checksumMD5 != req.ChecksumMD5 {
+		err := fmt.Errorf("%w: 체크섬 %s (예상: %s)", ErrUploadMismatch, digest.checksumMD5, req.ChecksumMD5)
+		s.discard(err, partPath)
+		return nil, err
+	}
+
+	// 헤더의 키 유도 설정을 메타데이터로 함께 저장
+	metadata, err := readStreamMetadata(partPath)
+	if err != nil {
+		s.discard(err, partPath)
+		return nil, err
+	}
+
+	info, err := os.Stat(partPath)
+	if err != nil {
+		err = fmt.Errorf("암호화 파일 크기 확인 실패: %w", err)
+		s.discard(err, partPath)
+		return nil, err
+	}
+
+	// 2~3. 레코드 생성과 최종 경로 이동
+	file := &model.File{
+		OriginalName:   req.OriginalName,
+		EncryptedPath:  finalPath,
+		VolumeID:       volumeID,
+		Size:           req.Size,
+		EncryptedSize:  info.Size(),
+		MimeType:       req.MimeType,
+		ChecksumMD5:    req.ChecksumMD5,
+		Status:         model.FileStatusEncrypted,
+		BlockHashes:    digest.blockHashes,
+		TextEncoding:   digest.textEncoding,
+		ExternalID:     req.ExternalID,
+		Description:    req.Description,
+		CustomMetadata: req.CustomMetadata,
+	}
+	if signature, ok := digest.similarity.Signature(); ok {
+		file.SetSimilaritySignature(signature)
+	}
+
+	// 새 메타데이터가 저장되기 전에 세어야 기존 파일 수만 경고에 포함됨
+	metadata.PasswordFingerprint = fingerprint
+	warning := s.checkReuse(ctx, fingerprint)
+
+	if err := s.commit(ctx, file, metadata, partPath); err != nil {
+		return nil, err
+	}
+
+	file.EncryptionMetadata = metadata
+	return &UploadResult{File: file, PasswordReuse: warning}, nil
+}
+
+// fingerprint 재사용 감지가 켜져 있으면 패스워드 지문을 계산합니다
+func (s *uploadService) fingerprint(password []byte) (string, error) {
+	if s.reuse == nil {
+		return "", nil
+	}
+	return s.reuse.Fingerprint(password)
+}
+
+// checkReuse 같은 지문을 가진 기존 파일이 임계값 이상이면 경고를 반환합니다
+//
+// 경고는 참고용이므로 확인에 실패해도 업로드는 계속하고 로그만 남깁니다.
+func (s *uploadService) checkReuse(ctx context.Context, fingerprint string) *PasswordReuseWarning {
+	if s.reuse == nil {
+		return nil
+	}
+
+	warning, err := s.reuse.CheckReuse(ctx, fingerprint)
+	if err != nil {
+		s.logger.WithError(err).Warn("패스워드 재사용 여부를 확인하지 못했습니다")
+		return nil
+	}
+	return warning
+}
+
+// commit 레코드와 암호화 메타데이터를 생성하고 같은 트랜잭션 안에서 임시 파일을 최종 경로로 옮깁니다
+//
+// 레코드 생성이나 이동이 실패하면 트랜잭션이 롤백되므로 임시 파일만 지웁니다.
+// 이동한 뒤 커밋이 실패하면 레코드가 롤백된 것이므로 최종 경로의 파일을 지웁니다.
+func (s *uploadService) commit(ctx context.Context, file *model.File, metadata *model.EncryptionMetadata, partPath string) error {
+	moved := false
+
+	// 본문을 모두 받은 뒤에는 클라이언트 연결이 끊겨도 완료 처리를 마침
+	// (저장소는 호출마다 받은 context를 쓰므로 트랜잭션 안의 호출에도 같은 context를 전달)
+	ctx = context.WithoutCancel(ctx)
+	err := s.txManager.WithinTransaction(ctx, func(repos repository.Repositories) error {
+		if err := createFileWithMetadata(ctx, repos, file, metadata); err != nil {
+			return err
+		}
+
+		if err := s.rename(partPath, file.EncryptedPath); err != nil {
+			return fmt.Errorf("암호화 파일 이동 실패: %w", err)
+		}
+		moved = true
+		return nil
+	})
+	if err == nil {
+		return nil
+	}
+
+	// 롤백된 레코드의 ID가 응답이나 로그에 쓰이지 않도록 초기화
+	file.ID = 0
+	if moved {
+		s.discard(err, file.EncryptedPath)
+	} else {
+		s.discard(err, partPath)
+	}
+	return err
+}
+
+// createFileWithMetadata 트랜잭션에 묶인 저장소로 파일 레코드와 primary 암호화 메타데이터를 생성합니다
+//
+// 업로드와 수집함 감시가 함께 사용하므로, 암호화본을 등록하는 경로는 모두
+// 메타데이터 없는 레코드(ListMissingMetadata 대상)를 남기지 않습니다.
+func createFileWithMetadata(ctx context.Context, repos repository.Repositories, file *model.File, metadata *model.EncryptionMetadata) error {
+	if err := repos.Files.Create(ctx, file); err != nil {
+		return fmt.Errorf("파일 레코드 생성 실패: %w", externalIDConflict(file, err))
+	}
+
+	metadata.FileID = file.ID
+	if err := repos.Encryption.Create(ctx, metadata); err != nil {
+		return fmt.Errorf("암호화 메타데이터 생성 실패: %w", err)
+	}
+
+	return nil
+}
+
+// receive 본문을 암호화해 임시 파일에 기록하고 해시를 계산합니다
+func (s *uploadService) receive(ctx context.Context, partPath string, req *UploadRequest, body io.Reader) (*uploadDigest, error) {
+	dst, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, watchFilePerm) //nolint:gosec // 저장소 내부 랜덤 경로
+	if err != nil {
+		return nil, fmt.Errorf("임시 파일 생성 실패: %w", err)
+	}
+
+	// 이미 압축된 형식이 아니면 청크를 압축한 뒤 암호화
+	compression := crypto.WithCompression(crypto.CompressionForMIME(req.MimeType))
+	encWriter, err := crypto.NewEncryptWriterBytes(dst, req.Password, compression, crypto.WithIterations(s.iterations))
+	crypto.ZeroBytes(req.Password)
+	if err != nil {
+		_ = dst.Close()
+		return nil, fmt.Errorf("암호화 준비 실패: %w", err)
+	}
+
+	md5Hash := md5.New() //nolint:gosec // 파일 모델의 체크섬 형식(MD5)과 일치
+	blockHasher := NewBlockHasher()
+	sniffer := &charset.Sniffer{}
+	similarity := simhash.New()
+
+	// 협상 크기보다 1바이트 더 읽어 초과 전송을 감지
+	src := &uploadReader{ctx: ctx, reader: io.LimitReader(body, req.Size+1)}
+	size, copyErr := io.Copy(io.MultiWriter(encWriter, md5Hash, blockHasher, sniffer, similarity), src)
+
+	closeErr := encWriter.Close()
+	if syncErr := dst.Sync(); closeErr == nil {
+		closeErr = syncErr
+	}
+	if fileErr := dst.Close(); closeErr == nil {
+		closeErr = fileErr
+	}
+
+	switch {
+	case src.err != nil:
+		// 클라이언트 끊김(context 취소, 잘린 본문 등)
+		return nil, fmt.Errorf("%w: %w", ErrUploadInterrupted, src.err)
+	case copyErr != nil:
+		return nil, fmt.Errorf("파일 암호화 실패: %w", copyErr)
+	case closeErr != nil:
+		return nil, fmt.Errorf("암호화 파일 저장 실패: %w", closeErr)
+	case size < req.Size:
+		return nil, fmt.Errorf("%w: %d/%d 바이트 수신: %w", ErrUploadInterrupted, size, req.Size, io.ErrUnexpectedEOF)
+	case size > req.Size:
+		return nil, fmt.Errorf("%w: 협상된 크기 %d 바이트를 초과했습니다", ErrUploadMismatch, req.Size)
+	}
+
+	return &uploadDigest{
+		size:         size,
+		checksumMD5:  hex.EncodeToString(md5Hash.Sum(nil)),
+		blockHashes:  blockHasher.Hex(),
+		textEncoding: textEncodingFor(req.MimeType, sniffer),
+		similarity:   similarity,
+	}, nil
+}
+
+// readStreamMetadata 암호화 파일의 스트림 헤더에서 primary 암호화 메타데이터를 만듭니다
+//
+// 첫 번째 키 슬롯의 salt, 감싼 키 nonce, 반복 횟수를 기록합니다. 복호화는 헤더의
+// 값을 사용하므로 메타데이터는 조회와 감사 용도입니다.
+func readStreamMetadata(path string) (*model.EncryptionMetadata, error) {
+	f, err := os.Open(path) //nolint:gosec // 저장소 내부 경로
+	if err != nil {
+		return nil, fmt.Errorf("암호화 파일 열기 실패: %w", err)
+	}
+	defer f.Close()
+
+	info, err := crypto.ReadStreamInfo(f)
+	if err != nil {
+		return nil, fmt.Errorf("암호화 헤더 읽기 실패: %w", err)
+	}
+
+	slot := info.KeySlots[0]
+	return &model.EncryptionMetadata{
+		Purpose:       model.MetadataPurposePrimary,
+		Algorithm:     model.EncryptionAlgorithmAES256GCM,
+		KeyDerivation: model.KeyDerivationPBKDF2SHA256,
+		SaltHex:       hex.EncodeToString(slot.Salt),
+		NonceHex:      hex.EncodeToString(slot.WrappedKey[:crypto.NonceSize]),
+		Iterations:    slot.Iterations,
+		Compression:   info.Compression.String(),
+	}, nil
+}
+
+// discard 실패한 업로드가 남긴 임시 파일 또는 최종 파일을 지웁니다
+//
+// 레코드는 파일이 최종 경로에 있을 때만 커밋되므로 파일만 지우면 됩니다. 삭제에
+// 실패한 파일은 레코드가 없는 고아 파일이므로 경로를 로그에 남깁니다.
+func (s *uploadService) discard(cause error, paths ...string) {
+	entry := s.logger.WithField("cause", cause.Error())
+
+	cleaned := true
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			entry.WithError(err).WithField("path", path).Error("실패한 업로드 파일을 삭제하지 못했습니다 (수동 정리 필요)")
+			cleaned = false
+		}
+	}
+
+	if cleaned {
+		entry.Info("중단된 업로드를 정리했습니다")
+	}
+}
+
+// uploadReader context 취소를 확인하고 본문 읽기 에러를 기록하는 Reader
+type uploadReader struct {
+	ctx    context.Context
+	reader io.Reader
+	err    error
+}
+
+// Read context가 취소되었으면 읽기를 중단합니다
+func (r *uploadReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		r.err = err
+		return 0, err
+	}
+
+	n, err := r.reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// validateUploadRequest 업로드 요청을 검증합니다
+func validateUploadRequest(req *UploadRequest) error {
+	if req == nil {
+		return fmt.Errorf("%w: 요청이 비어있습니다", ErrInvalidUpload)
+	}
+
+	if req.OriginalName == "" || req.MimeType == "" {
+		return fmt.Errorf("%w: 파일명과 MIME 타입이 필요합니다", ErrInvalidUpload)
+	}
+
+	if req.Size <= 0 {
+		return fmt.Errorf("%w: 파일 크기는 0보다 커야 합니다", ErrInvalidUpload)
+	}
+
+	if req.ChecksumMD5 == "" {
+		return fmt.Errorf("%w: 체크섬이 필요합니다", ErrInvalidUpload)
+	}
+
+	if len(req.Password) == 0 {
+		return fmt.Errorf("%w: 패스워드가 필요합니다", ErrInvalidUpload)
+	}
+
+	return nil
+}
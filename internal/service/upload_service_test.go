@@ -0,0 +1,430 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/crypto"
+	"DataLocker/pkg/simhash"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const uploadTestPassword = "upload-password"
+
+// uploadTestContent 여러 청크에 걸치는 업로드 테스트 데이터
+var uploadTestContent = []byte(strings.Repeat("streaming upload body ", 100000))
+
+// failingMetadataTx 트랜잭션 안의 암호화 메타데이터 생성만 실패시키는 트랜잭션 관리자
+type failingMetadataTx struct {
+	repository.TxManager
+}
+
+func (m *failingMetadataTx) WithinTransaction(ctx context.Context, fn func(repository.Repositories) error) error {
+	return m.TxManager.WithinTransaction(ctx, func(repos repository.Repositories) error {
+		repos.Encryption = &failingCreateEncryptionRepository{EncryptionRepository: repos.Encryption}
+		return fn(repos)
+	})
+}
+
+// failingCreateEncryptionRepository Create만 실패하는 암호화 메타데이터 저장소
+type failingCreateEncryptionRepository struct {
+	repository.EncryptionRepository
+}
+
+func (r *failingCreateEncryptionRepository) Create(context.Context, *model.EncryptionMetadata) error {
+	return errors.New("metadata insert failed")
+}
+
+// failingCommitTx 트랜잭션 본문은 성공했지만 커밋이 실패하는 트랜잭션 관리자
+//
+// 본문이 끝난 뒤 에러를 반환해 실제 트랜잭션을 롤백시키므로 커밋 실패와 같은
+// 상태(본문의 부수 효과는 남고 레코드는 없음)가 됩니다.
+type failingCommitTx struct {
+	repository.TxManager
+}
+
+func (m *failingCommitTx) WithinTransaction(ctx context.Context, fn func(repository.Repositories) error) error {
+	return m.TxManager.WithinTransaction(ctx, func(repos repository.Repositories) error {
+		if err := fn(repos); err != nil {
+			return err
+		}
+		return errors.New("commit failed")
+	})
+}
+
+// cancelBeforeCommitTx 트랜잭션을 시작하기 직전에 요청 context를 취소하는 트랜잭션 관리자 (본문 수신 후 연결 끊김)
+type cancelBeforeCommitTx struct {
+	repository.TxManager
+	cancel context.CancelFunc
+}
+
+func (m *cancelBeforeCommitTx) WithinTransaction(ctx context.Context, fn func(repository.Repositories) error) error {
+	m.cancel()
+	return m.TxManager.WithinTransaction(ctx, fn)
+}
+
+// cancelAfterReader 첫 읽기 후 context를 취소하는 Reader (연결 끊김 재현)
+type cancelAfterReader struct {
+	reader io.Reader
+	cancel context.CancelFunc
+}
+
+func (r *cancelAfterReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.cancel()
+	return n, err
+}
+
+// setupUploadTest 임시 저장소와 DB로 업로드 서비스를 구성합니다
+func setupUploadTest(t *testing.T) (UploadService, repository.FileRepository, string) {
+	t.Helper()
+	svc, db, storageDir := setupUploadTestWithTx(t, func(tx repository.TxManager) repository.TxManager { return tx })
+	return svc, repository.NewFileRepository(db), storageDir
+}
+
+// setupUploadTestWithTx 트랜잭션 관리자를 감싼 업로드 서비스를 구성합니다 (장애 주입용)
+func setupUploadTestWithTx(t *testing.T, wrap func(repository.TxManager) repository.TxManager) (*uploadService, *gorm.DB, string) {
+	t.Helper()
+	storageDir := filepath.Join(t.TempDir(), "storage")
+	db := setupServiceTestDB(t)
+	storage := newDirStorage(t, storageDir, repository.NewFileRepository(db))
+	svc := NewUploadService(storage, 0, wrap(repository.NewTxManager(db)), nil, newSilentLogger()).(*uploadService)
+	return svc, db, storageDir
+}
+
+// newUploadRequest 테스트 데이터에 맞는 업로드 요청
+func newUploadRequest() *UploadRequest {
+	return &UploadRequest{
+		OriginalName: "upload.txt",
+		MimeType:     "text/plain",
+		Size:         int64(len(uploadTestContent)),
+		ChecksumMD5:  md5Hex(uploadTestContent),
+		Password:     []byte(uploadTestPassword),
+	}
+}
+
+// assertStorageEmpty 저장소에 남은 파일(임시 파일 포함)이 없는지 확인합니다
+func assertStorageEmpty(t *testing.T, storageDir string) {
+	t.Helper()
+	entries, err := os.ReadDir(storageDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestUploadService_Success(t *testing.T) {
+	svc, fileRepo, storageDir := setupUploadTest(t)
+
+	ctx := model.WithActor(context.Background(), model.ActorAnonymous)
+	result, err := svc.Upload(ctx, newUploadRequest(), bytes.NewReader(uploadTestContent))
+	require.NoError(t, err)
+	file := result.File
+	assert.Equal(t, model.FileStatusEncrypted, file.Status)
+	assert.NotEmpty(t, file.BlockHashes)
+
+	stored, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStatusEncrypted, stored.Status)
+	assert.Equal(t, config.DefaultStorageVolumeID, stored.VolumeID)
+	assert.Equal(t, model.ActorAnonymous, stored.CreatedBy, "요청 컨텍스트의 행위자로 기록")
+
+	// 임시 파일 없이 최종 파일만 남고 복호화 가능
+	entries, err := os.ReadDir(storageDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, filepath.Base(file.EncryptedPath), entries[0].Name())
+	info, err := entries[0].Info()
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), stored.EncryptedSize, "디스크의 암호화본 크기 기록")
+
+	encrypted, err := os.Open(file.EncryptedPath)
+	require.NoError(t, err)
+	defer encrypted.Close()
+
+	decReader, err := crypto.NewDecryptReader(encrypted, uploadTestPassword)
+	require.NoError(t, err)
+	decrypted, err := io.ReadAll(decReader)
+	require.NoError(t, err)
+	assert.Equal(t, uploadTestContent, decrypted)
+}
+
+// TestUploadService_ZeroesPassword 키 슬롯을 만든 뒤 요청의 패스워드를 지우는지 확인합니다
+//
+// 힙에 남은 사본은 단위 테스트로 확인할 수 없으므로 변경 시 다음 절차로 수동 검증합니다.
+//  1. 서버를 띄우고 다른 곳에 나오지 않을 긴 패스워드(예: openssl rand -hex 24)로 업로드합니다.
+//  2. 응답을 받은 뒤 gcore <pid>로 코어 덤프를 뜹니다 (GC를 기다리지 않음).
+//  3. grep -c <패스워드> core.<pid>로 평문 사본 수를 셉니다.
+//
+// net/http가 헤더를 파싱하며 만든 문자열은 GC 전까지 남을 수 있으므로 1개까지는
+// 허용하고, 그보다 많으면 서비스나 암호화 계층에서 새 사본이 생긴 것입니다.
+func TestUploadService_ZeroesPassword(t *testing.T) {
+	svc, _, _ := setupUploadTest(t)
+
+	req := newUploadRequest()
+	password := req.Password
+	_, err := svc.Upload(context.Background(), req, bytes.NewReader(uploadTestContent))
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, len(uploadTestPassword)), password)
+}
+
+func TestUploadService_SimilaritySignature(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, _ := setupUploadTest(t)
+
+	// 반복 없는 본문은 시그니처를 가짐
+	var builder strings.Builder
+	for i := range 500 {
+		fmt.Fprintf(&builder, "분기 보고서 %d행: 매출 %d, 비용 %d\n", i, i*37%1000, i*91%1000)
+	}
+	content := []byte(builder.String())
+
+	req := newUploadRequest()
+	req.Size = int64(len(content))
+	req.ChecksumMD5 = md5Hex(content)
+	result, err := svc.Upload(context.Background(), req, bytes.NewReader(content))
+	require.NoError(t, err)
+	file := result.File
+
+	expected := simhash.New()
+	_, _ = expected.Write(content)
+	want, ok := expected.Signature()
+	require.True(t, ok)
+
+	stored, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	signature, ok := stored.SimilaritySignature()
+	require.True(t, ok, "업로드 스트림에서 한 번에 계산해 저장")
+	assert.Equal(t, want, signature)
+}
+
+func TestUploadService_RecordsIterations(t *testing.T) {
+	ctx := context.Background()
+	storageDir := filepath.Join(t.TempDir(), "storage")
+	db := setupServiceTestDB(t)
+	fileRepo := repository.NewFileRepository(db)
+	svc := NewUploadService(newDirStorage(t, storageDir, fileRepo), 2000, repository.NewTxManager(db), nil, newSilentLogger())
+
+	result, err := svc.Upload(context.Background(), newUploadRequest(), bytes.NewReader(uploadTestContent))
+	require.NoError(t, err)
+	file := result.File
+
+	// 지정한 반복 횟수가 헤더와 메타데이터에 함께 기록됨
+	stored, err := fileRepo.GetByID(ctx, file.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.EncryptionMetadata)
+	assert.Equal(t, 2000, stored.EncryptionMetadata.Iterations)
+	assert.Equal(t, model.MetadataPurposePrimary, stored.EncryptionMetadata.Purpose)
+	assert.Equal(t, model.CompressionGzip, stored.EncryptionMetadata.Compression)
+
+	encrypted, err := os.Open(file.EncryptedPath)
+	require.NoError(t, err)
+	defer encrypted.Close()
+
+	info, err := crypto.ReadStreamInfo(encrypted)
+	require.NoError(t, err)
+	require.Len(t, info.KeySlots, 1)
+	assert.Equal(t, 2000, info.KeySlots[0].Iterations)
+	assert.Equal(t, hex.EncodeToString(info.KeySlots[0].Salt), stored.EncryptionMetadata.SaltHex)
+}
+
+func TestUploadService_ContextCanceled(t *testing.T) {
+	svc, fileRepo, storageDir := setupUploadTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	body := &cancelAfterReader{reader: bytes.NewReader(uploadTestContent), cancel: cancel}
+
+	_, err := svc.Upload(ctx, newUploadRequest(), body)
+	assert.ErrorIs(t, err, ErrUploadInterrupted)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	count, err := fileRepo.Count(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assertStorageEmpty(t, storageDir)
+}
+
+func TestUploadService_TruncatedBody(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, storageDir := setupUploadTest(t)
+
+	// 전송 도중 끊긴 본문
+	truncated := io.MultiReader(
+		bytes.NewReader(uploadTestContent[:len(uploadTestContent)/2]),
+		iotest.ErrReader(io.ErrUnexpectedEOF),
+	)
+	_, err := svc.Upload(context.Background(), newUploadRequest(), truncated)
+	assert.ErrorIs(t, err, ErrUploadInterrupted)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// 정상 종료했지만 협상된 크기보다 짧은 본문
+	_, err = svc.Upload(context.Background(), newUploadRequest(), bytes.NewReader(uploadTestContent[:10]))
+	assert.ErrorIs(t, err, ErrUploadInterrupted)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	count, err := fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assertStorageEmpty(t, storageDir)
+}
+
+func TestUploadService_Mismatch(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, storageDir := setupUploadTest(t)
+
+	// 같은 크기, 다른 내용
+	forged := bytes.Repeat([]byte("x"), len(uploadTestContent))
+	_, err := svc.Upload(context.Background(), newUploadRequest(), bytes.NewReader(forged))
+	assert.ErrorIs(t, err, ErrUploadMismatch)
+
+	// 협상보다 긴 본문
+	longer := append(append([]byte{}, uploadTestContent...), '!')
+	_, err = svc.Upload(context.Background(), newUploadRequest(), bytes.NewReader(longer))
+	assert.ErrorIs(t, err, ErrUploadMismatch)
+
+	count, err := fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assertStorageEmpty(t, storageDir)
+}
+
+func TestUploadService_ExternalIDConflict(t *testing.T) {
+	ctx := context.Background()
+	svc, fileRepo, storageDir := setupUploadTest(t)
+
+	req := newUploadRequest()
+	req.ExternalID = "erp-1"
+	result, err := svc.Upload(ctx, req, bytes.NewReader(uploadTestContent))
+	require.NoError(t, err)
+	file := result.File
+	assert.Equal(t, "erp-1", file.ExternalID)
+
+	// 협상 뒤 다른 업로드가 같은 ID를 먼저 쓰면 커밋 단계에서 거부하고 암호화본 정리
+	req = newUploadRequest()
+	req.ExternalID = "erp-1"
+	_, err = svc.Upload(ctx, req, bytes.NewReader(uploadTestContent))
+	assert.ErrorIs(t, err, ErrExternalIDInUse)
+
+	count, err := fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	entries, err := os.ReadDir(storageDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestUploadService_FaultInjection(t *testing.T) {
+	ctx := context.Background()
+	passthrough := func(tx repository.TxManager) repository.TxManager { return tx }
+
+	testCases := []struct {
+		name    string
+		wrap    func(repository.TxManager) repository.TxManager
+		rename  func(oldPath, newPath string) error
+		body    io.Reader
+		wantErr string
+	}{
+		{
+			name: "1단계: 임시 경로 암호화 실패",
+			wrap: passthrough,
+			body: io.MultiReader(
+				bytes.NewReader(uploadTestContent[:len(uploadTestContent)/2]),
+				iotest.ErrReader(errors.New("disk full")),
+			),
+			wantErr: "disk full",
+		},
+		{
+			name:    "2단계: 레코드 생성 실패",
+			wrap:    func(tx repository.TxManager) repository.TxManager { return &failingMetadataTx{TxManager: tx} },
+			wantErr: "metadata insert failed",
+		},
+		{
+			name: "3단계: 최종 경로 이동 실패",
+			wrap: passthrough,
+			rename: func(string, string) error {
+				return errors.New("rename failed")
+			},
+			wantErr: "rename failed",
+		},
+		{
+			name:    "3단계 이후: 이동 뒤 커밋 실패",
+			wrap:    func(tx repository.TxManager) repository.TxManager { return &failingCommitTx{TxManager: tx} },
+			wantErr: "commit failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, db, storageDir := setupUploadTestWithTx(t, tc.wrap)
+			if tc.rename != nil {
+				svc.rename = tc.rename
+			}
+			body := tc.body
+			if body == nil {
+				body = bytes.NewReader(uploadTestContent)
+			}
+
+			_, err := svc.Upload(context.Background(), newUploadRequest(), body)
+			require.ErrorContains(t, err, tc.wantErr)
+
+			// 고아 레코드 없음 (소프트 삭제된 레코드 포함)
+			var records int64
+			require.NoError(t, db.Unscoped().Model(&model.File{}).Count(&records).Error)
+			assert.Zero(t, records)
+
+			metadataCount, err := repository.NewEncryptionRepository(db).Count(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, metadataCount)
+
+			// 고아 파일 없음 (임시 파일과 최종 파일 모두)
+			assertStorageEmpty(t, storageDir)
+		})
+	}
+}
+
+func TestUploadService_CommitSurvivesCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc, db, _ := setupUploadTestWithTx(t, func(tx repository.TxManager) repository.TxManager {
+		return &cancelBeforeCommitTx{TxManager: tx, cancel: cancel}
+	})
+
+	// 본문을 모두 받은 뒤 끊기면 레코드와 메타데이터를 모두 남김
+	result, err := svc.Upload(ctx, newUploadRequest(), bytes.NewReader(uploadTestContent))
+	require.NoError(t, err)
+	file := result.File
+	require.Error(t, ctx.Err())
+
+	stored, err := repository.NewFileRepository(db).GetByID(context.Background(), file.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.EncryptionMetadata)
+	assert.Equal(t, file.ID, stored.EncryptionMetadata.FileID)
+	assert.FileExists(t, stored.EncryptedPath)
+	assert.NoFileExists(t, stored.EncryptedPath+partialFileExt)
+}
+
+func TestUploadService_InvalidRequest(t *testing.T) {
+	svc, _, _ := setupUploadTest(t)
+
+	req := newUploadRequest()
+	req.Password = nil
+	_, err := svc.Upload(context.Background(), req, bytes.NewReader(uploadTestContent))
+	assert.ErrorIs(t, err, ErrInvalidUpload)
+
+	_, err = svc.Upload(context.Background(), nil, bytes.NewReader(uploadTestContent))
+	assert.ErrorIs(t, err, ErrInvalidUpload)
+}
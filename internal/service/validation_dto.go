@@ -2,6 +2,8 @@
 // This file defines validation DTOs for files and directories.
 package service
 
+import "time"
+
 // ItemType 검증 대상 타입
 type ItemType string
 
@@ -47,6 +49,20 @@ type ValidationResult struct {
 
 	// 개별 파일 결과 (디렉터리인 경우)
 	FileResults []FileValidationResult `json:"file_results,omitempty"`
+
+	// 개별 결과를 세션에 저장한 경우 FileResults 대신 세션 ID로 조회
+	SessionID string     `json:"session_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ValidationResultPage 검증 세션의 개별 결과 한 페이지
+type ValidationResultPage struct {
+	SessionID string                 `json:"session_id"`
+	Items     []FileValidationResult `json:"items"`
+	Total     int64                  `json:"total"`
+	Page      int                    `json:"page"`
+	PageSize  int                    `json:"page_size"`
+	ExpiresAt time.Time              `json:"expires_at"`
 }
 
 // FileValidationResult 개별 파일 검증 결과
@@ -54,9 +70,30 @@ type FileValidationResult struct {
 	FileName     string   `json:"file_name"`
 	RelativePath string   `json:"relative_path"`
 	IsValid      bool     `json:"is_valid"`
+	MIMEGroup    string   `json:"mime_group,omitempty"` // 크기 제한을 적용한 MIME 그룹 (없으면 기본 제한)
+	MaxSize      int64    `json:"max_size"`             // 적용된 최대 크기
 	Errors       []string `json:"errors,omitempty"`
 }
 
+// UploadLimits 클라이언트가 업로드 전에 표시할 수 있는 크기 정책
+type UploadLimits struct {
+	PolicyVersion     string           `json:"policy_version,omitempty"` // GET /api/v1/upload-policy의 ETag에 담긴 정책 버전
+	MinFileSize       int64            `json:"min_file_size"`
+	DefaultMaxSize    int64            `json:"default_max_size"` // 어느 그룹에도 속하지 않을 때의 제한
+	MaxDirectorySize  int64            `json:"max_directory_size"`
+	MaxFileCount      int              `json:"max_file_count"`
+	MaxFileNameLength int              `json:"max_file_name_length"` // bytes
+	AllowedMimeTypes  []string         `json:"allowed_mime_types"`
+	Groups            []MIMEGroupLimit `json:"groups"` // 여러 그룹에 속하면 가장 작은 제한 적용
+}
+
+// MIMEGroupLimit MIME 그룹별 크기 제한
+type MIMEGroupLimit struct {
+	Name     string   `json:"name"`
+	Patterns []string `json:"patterns"`
+	MaxSize  int64    `json:"max_size"`
+}
+
 // 제한 상수들
 const (
 	MaxFileSize      = 100 * 1024 * 1024  // 100MB
@@ -0,0 +1,49 @@
+// Package service provides business logic for DataLocker.
+// This file maps directory validation results onto the folder hierarchy.
+package service
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+)
+
+// MapResultFolders 디렉터리 검증 결과의 상대 경로대로 폴더를 만들고 파일별 폴더를 반환합니다
+//
+// 반환하는 맵의 키는 결과의 RelativePath이고, 값은 파일을 넣을 폴더입니다(업로드할
+// 때 File.FolderID로 지정). 상대 경로의 디렉터리 부분을 parentID 아래에 만들며, 이미
+// 있는 폴더는 그대로 사용합니다. 검증에 실패한 파일은 올리지 않으므로 건너뛰고,
+// 디렉터리 바로 아래 파일은 parentID의 폴더에 넣습니다(parentID가 nil이면 맵에서
+// 빠지며 폴더 없이 올립니다). 경로가 잘못되었거나 너무 깊으면 그 경로를 담은 에러를
+// 반환하며, 그 전에 만든 폴더는 남습니다.
+func MapResultFolders(ctx context.Context, fileRepo repository.FileRepository, parentID *uint, results []FileValidationResult) (map[string]*model.Folder, error) {
+	folders := make(map[string]*model.Folder, len(results))
+	byDir := make(map[string]*model.Folder)
+
+	for _, result := range results {
+		if !result.IsValid {
+			continue
+		}
+
+		dir := path.Dir(strings.ReplaceAll(result.RelativePath, "\\", "/"))
+		folder, ok := byDir[dir]
+		if !ok {
+			var err error
+			folder, err = fileRepo.EnsureFolderPath(ctx, parentID, dir)
+			if err != nil {
+				return nil, fmt.Errorf("%s 폴더 생성 실패: %w", result.RelativePath, err)
+			}
+			byDir[dir] = folder
+		}
+
+		if folder != nil {
+			folders[result.RelativePath] = folder
+		}
+	}
+
+	return folders, nil
+}
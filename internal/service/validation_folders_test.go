@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapResultFolders(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := repository.NewFileRepository(setupServiceTestDB(t))
+
+	results := []FileValidationResult{
+		{FileName: "readme.txt", RelativePath: "readme.txt", IsValid: true},
+		{FileName: "a.pdf", RelativePath: "docs/2024/a.pdf", IsValid: true},
+		{FileName: "b.pdf", RelativePath: `docs\2024\b.pdf`, IsValid: true},
+		{FileName: "c.png", RelativePath: "photos/c.png", IsValid: true},
+		{FileName: "d.zip", RelativePath: "archive/d.zip", IsValid: false},
+	}
+
+	// 최상위에 만들면 디렉터리 바로 아래 파일은 폴더 없이 올림
+	folders, err := MapResultFolders(ctx, fileRepo, nil, results)
+	require.NoError(t, err)
+	assert.Len(t, folders, 3)
+	assert.NotContains(t, folders, "readme.txt")
+	assert.NotContains(t, folders, "archive/d.zip", "실패한 파일은 폴더를 만들지 않음")
+	assert.Equal(t, "2024", folders["docs/2024/a.pdf"].Name)
+	assert.Equal(t, folders["docs/2024/a.pdf"].ID, folders[`docs\2024\b.pdf`].ID)
+	assert.Equal(t, "photos", folders["photos/c.png"].Name)
+
+	tree, err := fileRepo.GetFolderTree(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "docs", tree[0].Name)
+	assert.Equal(t, "photos", tree[1].Name)
+
+	// 업로드 폴더 아래에 만들면 바로 아래 파일은 그 폴더에 넣음
+	upload := &model.Folder{Name: "upload-1"}
+	require.NoError(t, fileRepo.CreateFolder(ctx, upload))
+	folders, err = MapResultFolders(ctx, fileRepo, &upload.ID, results)
+	require.NoError(t, err)
+	assert.Len(t, folders, 4)
+	assert.Equal(t, upload.ID, folders["readme.txt"].ID)
+	require.NotNil(t, folders["photos/c.png"].ParentID)
+	assert.Equal(t, upload.ID, *folders["photos/c.png"].ParentID)
+
+	_, err = MapResultFolders(ctx, fileRepo, nil, []FileValidationResult{
+		{FileName: "x.txt", RelativePath: "../x.txt", IsValid: true},
+	})
+	assert.ErrorIs(t, err, model.ErrInvalidFolderName)
+}
@@ -2,7 +2,11 @@
 // This file defines validation interface for files and directories.
 package service
 
-import "context"
+import (
+	"context"
+
+	"DataLocker/internal/config"
+)
 
 // ValidationService 파일/디렉터리 검증 서비스
 type ValidationService interface {
@@ -14,4 +18,13 @@ type ValidationService interface {
 
 	// ValidateDirectory 디렉터리 전체를 검증
 	ValidateDirectory(ctx context.Context, directoryPath string, files []FileInfo) (*ValidationResult, error)
+
+	// Limits 업로드 크기 정책을 반환
+	Limits() *UploadLimits
+
+	// UploadPolicy 클라이언트 사전 검증용 업로드 정책 문서(JSON 스키마)를 반환
+	UploadPolicy() *UploadPolicyDocument
+
+	// UpdatePolicy 업로드 정책(크기 제한, MIME 화이트리스트)을 교체
+	UpdatePolicy(policy config.UploadConfig)
 }
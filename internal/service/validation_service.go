@@ -5,15 +5,89 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync/atomic"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/pkg/concurrent"
 )
 
+// validationWorkers 디렉터리 검증 시 동시에 검증하는 파일 수
+const validationWorkers = 8
+
 // validationService 파일/디렉터리 검증 서비스 구현체
-type validationService struct{}
+type validationService struct {
+	policy atomic.Pointer[validationPolicy]
+}
+
+// validationPolicy 검증에 사용하는 정책 스냅샷 (설정 리로드 시 통째로 교체)
+//
+// 클라이언트에 공개하는 제한과 업로드 정책 문서도 이 스냅샷에서 만들어
+// 검증 규칙과 어긋나지 않게 합니다.
+type validationPolicy struct {
+	defaultMaxSize   int64
+	groups           []MIMEGroupLimit // 이름순 정렬
+	allowedMimeTypes []string
+	maxNameLength    int // 파일명 최대 길이 (bytes)
+	document         *UploadPolicyDocument
+}
 
 // NewValidationService 새로운 검증 서비스를 생성합니다
-func NewValidationService() ValidationService {
-	return &validationService{}
+//
+// MIME 그룹별 제한이 없거나 기본 제한이 0이면 MaxFileSize를 기본 제한으로 사용합니다.
+// 허용 MIME 타입 목록이 비어 있으면 AllowedMimeTypes를 사용합니다.
+func NewValidationService(policy config.UploadConfig) ValidationService {
+	s := &validationService{}
+	s.UpdatePolicy(policy)
+	return s
+}
+
+// UpdatePolicy 업로드 정책을 교체합니다
+//
+// 진행 중인 검증은 이전 정책으로 끝나고, 이후 검증부터 새 정책이 적용됩니다.
+func (s *validationService) UpdatePolicy(policy config.UploadConfig) {
+	s.policy.Store(newValidationPolicy(policy))
+}
+
+// newValidationPolicy 업로드 설정으로 정책 스냅샷을 만듭니다
+func newValidationPolicy(policy config.UploadConfig) *validationPolicy {
+	defaultMaxSize := policy.DefaultMaxSize
+	if defaultMaxSize <= 0 {
+		defaultMaxSize = MaxFileSize
+	}
+
+	groups := make([]MIMEGroupLimit, 0, len(policy.MIMEGroups))
+	for name, group := range policy.MIMEGroups {
+		patterns := make([]string, 0, len(group.Patterns))
+		for _, pattern := range group.Patterns {
+			patterns = append(patterns, strings.ToLower(strings.TrimSpace(pattern)))
+		}
+		groups = append(groups, MIMEGroupLimit{Name: name, Patterns: patterns, MaxSize: group.MaxSize})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+
+	allowed := AllowedMimeTypes
+	if len(policy.AllowedMimeTypes) > 0 {
+		allowed = policy.AllowedMimeTypes
+	}
+
+	p := &validationPolicy{
+		defaultMaxSize:   defaultMaxSize,
+		groups:           groups,
+		allowedMimeTypes: append([]string(nil), allowed...),
+		maxNameLength:    model.MaxOriginalNameLength,
+	}
+
+	// 템플릿은 바이너리에 포함되어 있으므로 실패는 빌드 결함
+	document, err := newUploadPolicyDocument(p)
+	if err != nil {
+		panic(err)
+	}
+	p.document = document
+
+	return p
 }
 
 // ValidateItem 파일 또는 디렉터리를 검증합니다
@@ -29,7 +103,10 @@ func (s *validationService) ValidateItem(ctx context.Context, req *ValidationReq
 }
 
 // ValidateFile 단일 파일 검증 (기존 호환성)
+//
+// 파일명은 저장할 때와 같이 NFC로 정규화한 뒤 검증하고 결과에도 정규화한 이름을 담습니다.
 func (s *validationService) ValidateFile(ctx context.Context, fileName string, fileSize int64, mimeType string) (*FileValidationResult, error) {
+	fileName = model.NormalizeFileName(fileName)
 	result := &FileValidationResult{
 		FileName: fileName,
 		IsValid:  true,
@@ -37,22 +114,35 @@ func (s *validationService) ValidateFile(ctx context.Context, fileName string, f
 	}
 
 	// 기본 검증들
+	policy := s.policy.Load()
+
 	if fileName == "" {
 		result.IsValid = false
 		result.Errors = append(result.Errors, "파일명이 비어있습니다")
 	}
 
+	if len(fileName) > policy.maxNameLength {
+		result.IsValid = false
+		result.Errors = append(result.Errors, fmt.Sprintf("파일명이 너무 깁니다 (최대 %d bytes)", policy.maxNameLength))
+	}
+
 	if fileSize <= MinFileSize {
 		result.IsValid = false
 		result.Errors = append(result.Errors, "파일이 너무 작습니다")
 	}
 
-	if fileSize > MaxFileSize {
+	result.MIMEGroup, result.MaxSize = policy.limitFor(mimeType)
+	if fileSize > result.MaxSize {
 		result.IsValid = false
-		result.Errors = append(result.Errors, "파일이 너무 큽니다")
+		if result.MIMEGroup != "" {
+			result.Errors = append(result.Errors,
+				fmt.Sprintf("파일이 너무 큽니다 (%s 그룹 최대 %d bytes)", result.MIMEGroup, result.MaxSize))
+		} else {
+			result.Errors = append(result.Errors, fmt.Sprintf("파일이 너무 큽니다 (최대 %d bytes)", result.MaxSize))
+		}
 	}
 
-	if !s.isAllowedMimeType(mimeType) {
+	if !policy.isAllowedMimeType(mimeType) {
 		result.IsValid = false
 		result.Errors = append(result.Errors, "지원하지 않는 파일 형식입니다")
 	}
@@ -61,6 +151,9 @@ func (s *validationService) ValidateFile(ctx context.Context, fileName string, f
 }
 
 // ValidateDirectory 디렉터리 전체를 검증
+//
+// 개별 결과의 파일명과 상대 경로는 NFC로 정규화하므로, macOS(NFD)에서 올린 디렉터리도
+// 같은 경로의 폴더로 매핑됩니다.
 func (s *validationService) ValidateDirectory(ctx context.Context, directoryPath string, files []FileInfo) (*ValidationResult, error) {
 	result := &ValidationResult{
 		Type:        ItemTypeDirectory,
@@ -83,19 +176,41 @@ func (s *validationService) ValidateDirectory(ctx context.Context, directoryPath
 		result.Errors = append(result.Errors, fmt.Sprintf("파일이 너무 많습니다 (최대 %d개)", MaxFileCount))
 	}
 
-	// 3. 각 파일 검증
+	// 3. 각 파일 병렬 검증 (실패한 파일도 모두 결과에 포함)
+	fileResults := make([]*FileValidationResult, len(files))
+	err := concurrent.ForEach(ctx, files, concurrent.CollectAll, validationWorkers,
+		func(ctx context.Context, i int, file FileInfo) error {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("%s: %w", file.RelativePath, err)
+			}
+
+			fileResult, err := s.ValidateFile(ctx, file.Name, file.Size, file.MimeType)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file.RelativePath, err)
+			}
+
+			fileResult.RelativePath = model.NormalizeFileName(file.RelativePath)
+			fileResults[i] = fileResult
+			return nil
+		})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("디렉터리 검증이 중단되었습니다: %w", ctxErr)
+	}
+
+	for _, fileErr := range concurrent.Errors(err) {
+		result.IsValid = false
+		result.Errors = append(result.Errors, fmt.Sprintf("파일 검증 실패: %s", fileErr))
+	}
+
 	var totalSize int64
-	for _, file := range files {
-		fileResult, err := s.ValidateFile(ctx, file.Name, file.Size, file.MimeType)
-		if err != nil {
-			continue // 에러난 파일은 건너뛰기
+	for i, fileResult := range fileResults {
+		totalSize += files[i].Size
+		if fileResult == nil {
+			result.InvalidFiles++
+			continue
 		}
 
-		fileResult.RelativePath = file.RelativePath
 		result.FileResults = append(result.FileResults, *fileResult)
-
-		totalSize += file.Size
-
 		if fileResult.IsValid {
 			result.ValidFiles++
 		} else {
@@ -114,8 +229,82 @@ func (s *validationService) ValidateDirectory(ctx context.Context, directoryPath
 	return result, nil
 }
 
+// Limits 클라이언트에 공개할 업로드 크기 정책을 반환합니다
+func (s *validationService) Limits() *UploadLimits {
+	policy := s.policy.Load()
+	limits := policy.limits()
+	limits.PolicyVersion = policy.document.Version
+	return limits
+}
+
+// UploadPolicy 현재 정책의 업로드 정책 문서를 반환합니다
+func (s *validationService) UploadPolicy() *UploadPolicyDocument {
+	return s.policy.Load().document
+}
+
 // 내부 헬퍼 메서드들
 
+// limits 정책 스냅샷의 공개용 복사본을 만듭니다
+func (p *validationPolicy) limits() *UploadLimits {
+	groups := make([]MIMEGroupLimit, 0, len(p.groups))
+	for _, group := range p.groups {
+		groups = append(groups, MIMEGroupLimit{
+			Name:     group.Name,
+			Patterns: append([]string(nil), group.Patterns...),
+			MaxSize:  group.MaxSize,
+		})
+	}
+
+	return &UploadLimits{
+		MinFileSize:       MinFileSize,
+		DefaultMaxSize:    p.defaultMaxSize,
+		MaxDirectorySize:  MaxDirectorySize,
+		MaxFileCount:      MaxFileCount,
+		MaxFileNameLength: p.maxNameLength,
+		AllowedMimeTypes:  append([]string(nil), p.allowedMimeTypes...),
+		Groups:            groups,
+	}
+}
+
+// limitFor MIME 타입이 속한 그룹과 최대 크기를 반환합니다
+//
+// 여러 그룹에 속하면 가장 작은 제한을 적용하고, 어느 그룹에도 속하지 않으면
+// 그룹 이름 없이 기본 제한을 반환합니다.
+func (p *validationPolicy) limitFor(mimeType string) (string, int64) {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:i])
+	}
+
+	name, maxSize := "", p.defaultMaxSize
+	matched := false
+	for _, group := range p.groups {
+		if !matchesMIMEGroup(mimeType, group.Patterns) {
+			continue
+		}
+		if !matched || group.MaxSize < maxSize {
+			name, maxSize = group.Name, group.MaxSize
+			matched = true
+		}
+	}
+
+	return name, maxSize
+}
+
+// matchesMIMEGroup MIME 타입이 패턴 중 하나와 일치하는지 확인합니다 ("image/*"는 접두사 일치)
+func matchesMIMEGroup(mimeType string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(mimeType, prefix) {
+				return true
+			}
+		} else if mimeType == pattern {
+			return true
+		}
+	}
+	return false
+}
+
 // validateSingleFile 단일 파일 검증 (내부용)
 func (s *validationService) validateSingleFile(req *ValidationRequest) (*ValidationResult, error) {
 	fileResult, err := s.ValidateFile(context.Background(), req.FileName, req.FileSize, req.MimeType)
@@ -149,8 +338,8 @@ func (s *validationService) validateDirectoryInternal(req *ValidationRequest) (*
 }
 
 // isAllowedMimeType 허용된 MIME 타입인지 확인
-func (s *validationService) isAllowedMimeType(mimeType string) bool {
-	for _, allowed := range AllowedMimeTypes {
+func (p *validationPolicy) isAllowedMimeType(mimeType string) bool {
+	for _, allowed := range p.allowedMimeTypes {
 		if strings.EqualFold(mimeType, allowed) {
 			return true
 		}
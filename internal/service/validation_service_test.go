@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
+)
+
+// testUploadPolicy 그룹별 제한이 겹치는 테스트 정책
+func testUploadPolicy() config.UploadConfig {
+	return config.UploadConfig{
+		DefaultMaxSize: 50 * config.BytesPerMB,
+		MIMEGroups: map[string]config.MIMEGroupLimit{
+			"image":    {Patterns: []string{"image/*"}, MaxSize: 20 * config.BytesPerMB},
+			"document": {Patterns: []string{"application/pdf", "text/*"}, MaxSize: 100 * config.BytesPerMB},
+			"png":      {Patterns: []string{"image/png"}, MaxSize: 5 * config.BytesPerMB},
+		},
+	}
+}
+
+func TestValidationService_MIMEGroupLimits(t *testing.T) {
+	svc := NewValidationService(testUploadPolicy())
+
+	testCases := []struct {
+		name      string
+		mimeType  string
+		wantGroup string
+		wantMax   int64
+	}{
+		{name: "이미지", mimeType: "image/jpeg", wantGroup: "image", wantMax: 20 * config.BytesPerMB},
+		{name: "문서는 기본보다 큰 제한", mimeType: "application/pdf", wantGroup: "document", wantMax: 100 * config.BytesPerMB},
+		{name: "중복 매칭 시 가장 엄격한 제한", mimeType: "image/png", wantGroup: "png", wantMax: 5 * config.BytesPerMB},
+		{name: "그룹 없음", mimeType: "application/zip", wantGroup: "", wantMax: 50 * config.BytesPerMB},
+		{name: "파라미터와 대소문자 무시", mimeType: "Text/Plain; charset=utf-8", wantGroup: "document", wantMax: 100 * config.BytesPerMB},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// 허용 MIME 목록 검사와 분리하기 위해 그룹 제한만 확인
+			group, maxSize := svc.(*validationService).policy.Load().limitFor(tc.mimeType)
+			assert.Equal(t, tc.wantGroup, group)
+			assert.Equal(t, tc.wantMax, maxSize)
+		})
+	}
+}
+
+func TestValidationService_ValidateFileAppliesGroupLimit(t *testing.T) {
+	svc := NewValidationService(testUploadPolicy())
+
+	result, err := svc.ValidateFile(context.Background(), "photo.png", 6*config.BytesPerMB, "image/png")
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "png", result.MIMEGroup)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "png 그룹")
+
+	// 경계값: 그룹 제한과 같은 크기는 허용, 1바이트 초과는 거부
+	result, err = svc.ValidateFile(context.Background(), "photo.jpg", 20*config.BytesPerMB, "image/jpeg")
+	require.NoError(t, err)
+	assert.True(t, result.IsValid)
+
+	result, err = svc.ValidateFile(context.Background(), "photo.jpg", 20*config.BytesPerMB+1, "image/jpeg")
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+
+	// 문서 그룹은 기본 제한보다 큰 파일도 허용
+	result, err = svc.ValidateFile(context.Background(), "doc.pdf", 80*config.BytesPerMB, "application/pdf")
+	require.NoError(t, err)
+	assert.True(t, result.IsValid)
+}
+
+func TestValidationService_DefaultLimit(t *testing.T) {
+	// 정책이 비어 있으면 기존 MaxFileSize를 기본 제한으로 사용
+	svc := NewValidationService(config.UploadConfig{})
+
+	group, maxSize := svc.(*validationService).policy.Load().limitFor("application/pdf")
+	assert.Empty(t, group)
+	assert.Equal(t, int64(MaxFileSize), maxSize)
+
+	limits := svc.Limits()
+	assert.Equal(t, int64(MaxFileSize), limits.DefaultMaxSize)
+	assert.Empty(t, limits.Groups)
+	assert.Equal(t, AllowedMimeTypes, limits.AllowedMimeTypes)
+}
+
+func TestValidationService_LimitsSortedByName(t *testing.T) {
+	limits := NewValidationService(testUploadPolicy()).Limits()
+
+	require.Len(t, limits.Groups, 3)
+	assert.Equal(t, "document", limits.Groups[0].Name)
+	assert.Equal(t, "image", limits.Groups[1].Name)
+	assert.Equal(t, "png", limits.Groups[2].Name)
+}
+
+func TestValidationService_UpdatePolicy(t *testing.T) {
+	svc := NewValidationService(config.UploadConfig{})
+
+	result, err := svc.ValidateFile(context.Background(), "data.csv", 10, "text/csv")
+	require.NoError(t, err)
+	assert.False(t, result.IsValid, "기본 화이트리스트에 없는 타입")
+
+	svc.UpdatePolicy(config.UploadConfig{
+		DefaultMaxSize:   100,
+		AllowedMimeTypes: []string{"text/csv"},
+	})
+
+	result, err = svc.ValidateFile(context.Background(), "data.csv", 10, "text/csv")
+	require.NoError(t, err)
+	assert.True(t, result.IsValid)
+
+	result, err = svc.ValidateFile(context.Background(), "data.csv", 101, "text/csv")
+	require.NoError(t, err)
+	assert.False(t, result.IsValid, "새 기본 제한 적용")
+
+	assert.Equal(t, []string{"text/csv"}, svc.Limits().AllowedMimeTypes)
+}
+
+func TestValidationService_NormalizesNames(t *testing.T) {
+	svc := NewValidationService(config.UploadConfig{})
+
+	// macOS(NFD)에서 올린 디렉터리의 이름과 경로를 NFC로 돌려줌
+	result, err := svc.ValidateDirectory(context.Background(), "docs", []FileInfo{
+		{Name: norm.NFD.String("계약서.pdf"), RelativePath: norm.NFD.String("문서/계약서.pdf"), Size: 1024, MimeType: "application/pdf"},
+		{Name: "견적서.pdf", RelativePath: "문서/견적서.pdf", Size: 1024, MimeType: "application/pdf"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.FileResults, 2)
+	assert.Equal(t, "계약서.pdf", result.FileResults[0].FileName)
+	assert.Equal(t, "문서/계약서.pdf", result.FileResults[0].RelativePath)
+	assert.Equal(t, "문서/견적서.pdf", result.FileResults[1].RelativePath)
+
+	// 길이 제한은 정규화한 이름 기준 (NFD 한글은 NFC의 세 배 길이)
+	name := strings.Repeat("가", model.MaxOriginalNameLength/3)
+	require.Greater(t, len(norm.NFD.String(name)), model.MaxOriginalNameLength)
+	fileResult, err := svc.ValidateFile(context.Background(), norm.NFD.String(name), 1024, "text/plain")
+	require.NoError(t, err)
+	assert.True(t, fileResult.IsValid, fileResult.Errors)
+	assert.Equal(t, name, fileResult.FileName)
+}
@@ -0,0 +1,236 @@
+// Package service provides business logic for DataLocker.
+// This file implements directory validation sessions that keep per-file results for paged retrieval.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ValidationSessionTTL 디렉터리 검증 결과 보관 시간
+const ValidationSessionTTL = time.Hour
+
+// ErrValidationSessionNotFound 검증 세션이 없거나 만료됨
+var ErrValidationSessionNotFound = errors.New("검증 세션을 찾을 수 없거나 만료되었습니다")
+
+// ValidationSessionService 디렉터리 검증 결과를 세션으로 보관하는 서비스
+//
+// 파일 1000개 디렉터리의 개별 결과를 응답 하나에 담으면 수 MB가 되므로, 검증
+// 응답에는 요약과 세션 ID만 담고 개별 결과는 DB에 ValidationSessionTTL 동안
+// 보관합니다. 만료된 세션은 새 검증을 시작할 때 정리합니다.
+type ValidationSessionService interface {
+	// ValidateDirectory 디렉터리를 검증하고 개별 결과는 세션에 저장한 뒤 요약만 반환합니다
+	ValidateDirectory(ctx context.Context, directoryPath string, files []FileInfo) (*ValidationResult, error)
+
+	// Results 세션의 개별 결과를 요청 순서대로 페이지 조회합니다
+	Results(ctx context.Context, sessionID string, page, pageSize int) (*ValidationResultPage, error)
+
+	// StreamResults 세션의 개별 결과를 요청 순서대로 fn에 전달합니다
+	//
+	// 세션이 없거나 만료되었으면 fn을 호출하기 전에 ErrValidationSessionNotFound를
+	// 반환합니다. fn이 에러를 반환하면 중단하고 그 에러를 반환합니다.
+	StreamResults(ctx context.Context, sessionID string, fn func(*FileValidationResult) error) error
+
+	// PruneExpired 만료된 세션을 삭제하고 삭제한 세션 수를 반환합니다
+	PruneExpired(ctx context.Context) (int64, error)
+}
+
+// validationSessionService 검증 세션 서비스 구현체
+type validationSessionService struct {
+	validation ValidationService
+	repo       repository.ValidationRepository
+	logger     *logrus.Logger
+	clock      clock.Clock
+}
+
+// NewValidationSessionService 새로운 검증 세션 서비스를 생성합니다
+func NewValidationSessionService(
+	validation ValidationService,
+	repo repository.ValidationRepository,
+	logger *logrus.Logger,
+) ValidationSessionService {
+	if validation == nil {
+		panic("검증 서비스가 필요합니다")
+	}
+
+	if repo == nil {
+		panic("검증 세션 저장소가 필요합니다")
+	}
+
+	if logger == nil {
+		panic("로거가 필요합니다")
+	}
+
+	return &validationSessionService{
+		validation: validation,
+		repo:       repo,
+		logger:     logger,
+		clock:      clock.Real{},
+	}
+}
+
+// ValidateDirectory 디렉터리를 검증하고 결과를 세션에 저장합니다
+func (s *validationSessionService) ValidateDirectory(ctx context.Context, directoryPath string, files []FileInfo) (*ValidationResult, error) {
+	// 정리 실패는 새 검증을 막지 않음
+	if _, err := s.PruneExpired(ctx); err != nil {
+		s.logger.WithError(err).Warn("만료된 검증 세션 정리에 실패했습니다")
+	}
+
+	result, err := s.validation.ValidateDirectory(ctx, directoryPath, files)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := randomHex(negotiationIDSize)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := s.clock.Now().Add(ValidationSessionTTL)
+	session := &model.ValidationSession{
+		ID:            id,
+		ExpiresAt:     expiresAt,
+		DirectoryPath: directoryPath,
+		IsValid:       result.IsValid,
+		TotalFiles:    result.TotalFiles,
+		TotalSize:     result.TotalSize,
+		ValidFiles:    result.ValidFiles,
+		InvalidFiles:  result.InvalidFiles,
+		Errors:        result.Errors,
+	}
+
+	results := make([]*model.ValidationFileResult, 0, len(result.FileResults))
+	for i, fileResult := range result.FileResults {
+		results = append(results, &model.ValidationFileResult{
+			Seq:          i,
+			FileName:     fileResult.FileName,
+			RelativePath: fileResult.RelativePath,
+			IsValid:      fileResult.IsValid,
+			MIMEGroup:    fileResult.MIMEGroup,
+			MaxSize:      fileResult.MaxSize,
+			Errors:       fileResult.Errors,
+		})
+	}
+
+	if err := s.repo.CreateSession(session, results); err != nil {
+		return nil, fmt.Errorf("검증 결과 저장 실패: %w", err)
+	}
+
+	result.FileResults = nil
+	result.SessionID = id
+	result.ExpiresAt = &expiresAt
+	return result, nil
+}
+
+// Results 세션의 개별 결과를 페이지 조회합니다
+func (s *validationSessionService) Results(_ context.Context, sessionID string, page, pageSize int) (*ValidationResultPage, error) {
+	session, err := s.liveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize = normalizeSearchPage(page, pageSize)
+	results, total, err := s.repo.ListResults(session.ID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("검증 결과 조회 실패: %w", err)
+	}
+
+	items := make([]FileValidationResult, 0, len(results))
+	for _, result := range results {
+		items = append(items, toFileValidationResult(result))
+	}
+
+	return &ValidationResultPage{
+		SessionID: session.ID,
+		Items:     items,
+		Total:     total,
+		Page:      page,
+		PageSize:  pageSize,
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// StreamResults 세션의 개별 결과를 MaxPageSize 단위로 읽어 fn에 전달합니다
+func (s *validationSessionService) StreamResults(ctx context.Context, sessionID string, fn func(*FileValidationResult) error) error {
+	session, err := s.liveSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	for offset := 0; ; offset += repository.MaxPageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		results, _, err := s.repo.ListResults(session.ID, offset, repository.MaxPageSize)
+		if err != nil {
+			return fmt.Errorf("검증 결과 조회 실패: %w", err)
+		}
+
+		for _, result := range results {
+			item := toFileValidationResult(result)
+			if err := fn(&item); err != nil {
+				return err
+			}
+		}
+
+		if len(results) < repository.MaxPageSize {
+			return nil
+		}
+	}
+}
+
+// PruneExpired 만료된 세션과 개별 결과를 삭제합니다
+func (s *validationSessionService) PruneExpired(_ context.Context) (int64, error) {
+	deleted, err := s.repo.DeleteExpired(s.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if deleted > 0 {
+		s.logger.WithField("sessions", deleted).Debug("만료된 검증 세션을 정리했습니다")
+	}
+	return deleted, nil
+}
+
+// liveSession 만료되지 않은 세션을 조회합니다 (없거나 만료되면 ErrValidationSessionNotFound)
+func (s *validationSessionService) liveSession(sessionID string) (*model.ValidationSession, error) {
+	if sessionID == "" {
+		return nil, ErrValidationSessionNotFound
+	}
+
+	session, err := s.repo.GetSession(sessionID)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrValidationSessionNotFound, sessionID)
+		}
+		return nil, fmt.Errorf("검증 세션 조회 실패: %w", err)
+	}
+
+	// 정리 전이라도 만료된 세션은 없는 것으로 취급
+	if !s.clock.Now().Before(session.ExpiresAt) {
+		return nil, fmt.Errorf("%w: %s", ErrValidationSessionNotFound, sessionID)
+	}
+
+	return session, nil
+}
+
+// toFileValidationResult 저장된 개별 결과를 응답 DTO로 변환합니다
+func toFileValidationResult(result *model.ValidationFileResult) FileValidationResult {
+	return FileValidationResult{
+		FileName:     result.FileName,
+		RelativePath: result.RelativePath,
+		IsValid:      result.IsValid,
+		MIMEGroup:    result.MIMEGroup,
+		MaxSize:      result.MaxSize,
+		Errors:       result.Errors,
+	}
+}
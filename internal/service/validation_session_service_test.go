@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupValidationSessionTest 시각을 조정할 수 있는 검증 세션 서비스를 생성합니다
+func setupValidationSessionTest(t *testing.T) (*validationSessionService, *clock.Fake) {
+	t.Helper()
+	repo := repository.NewValidationRepository(setupServiceTestDB(t))
+	svc := NewValidationSessionService(NewValidationService(config.UploadConfig{}), repo, newSilentLogger()).(*validationSessionService)
+
+	clk := clock.NewFake(time.Now())
+	svc.clock = clk
+	return svc, clk
+}
+
+// directoryFiles 홀수 번째 파일은 허용되지 않는 형식인 디렉터리 파일 목록을 만듭니다
+func directoryFiles(count int) []FileInfo {
+	files := make([]FileInfo, 0, count)
+	for i := range count {
+		mimeType := "text/plain"
+		if i%2 == 1 {
+			mimeType = "application/zip"
+		}
+		files = append(files, FileInfo{
+			Name:         fmt.Sprintf("file_%04d", i),
+			RelativePath: fmt.Sprintf("docs/file_%04d", i),
+			Size:         1024,
+			MimeType:     mimeType,
+		})
+	}
+	return files
+}
+
+func TestValidationSessionService_SummaryAndPages(t *testing.T) {
+	svc, _ := setupValidationSessionTest(t)
+	ctx := context.Background()
+
+	result, err := svc.ValidateDirectory(ctx, "docs", directoryFiles(MaxFileCount))
+	require.NoError(t, err)
+	assert.Empty(t, result.FileResults, "응답에는 요약만 담아야 함")
+	assert.NotEmpty(t, result.SessionID)
+	require.NotNil(t, result.ExpiresAt)
+	assert.Equal(t, MaxFileCount, result.TotalFiles)
+	assert.Equal(t, MaxFileCount/2, result.ValidFiles)
+	assert.Equal(t, MaxFileCount/2, result.InvalidFiles)
+	assert.False(t, result.IsValid)
+
+	page, err := svc.Results(ctx, result.SessionID, 3, 50)
+	require.NoError(t, err)
+	assert.Equal(t, int64(MaxFileCount), page.Total)
+	assert.Equal(t, 3, page.Page)
+	require.Len(t, page.Items, 50)
+	assert.Equal(t, "docs/file_0100", page.Items[0].RelativePath)
+	assert.True(t, page.Items[0].IsValid)
+	assert.False(t, page.Items[1].IsValid)
+	assert.NotEmpty(t, page.Items[1].Errors)
+	assert.True(t, result.ExpiresAt.Equal(page.ExpiresAt))
+
+	// 마지막 페이지 이후는 빈 목록
+	page, err = svc.Results(ctx, result.SessionID, 100, 50)
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+
+	_, err = svc.Results(ctx, "missing", 1, 10)
+	assert.ErrorIs(t, err, ErrValidationSessionNotFound)
+}
+
+func TestValidationSessionService_StreamResults(t *testing.T) {
+	svc, _ := setupValidationSessionTest(t)
+	ctx := context.Background()
+
+	result, err := svc.ValidateDirectory(ctx, "docs", directoryFiles(250))
+	require.NoError(t, err)
+
+	var paths []string
+	require.NoError(t, svc.StreamResults(ctx, result.SessionID, func(item *FileValidationResult) error {
+		paths = append(paths, item.RelativePath)
+		return nil
+	}))
+	require.Len(t, paths, 250)
+	assert.Equal(t, "docs/file_0000", paths[0])
+	assert.Equal(t, "docs/file_0249", paths[249])
+
+	// 콜백 에러는 그대로 전달되고 스트리밍을 멈춤
+	stop := errors.New("중단")
+	calls := 0
+	err = svc.StreamResults(ctx, result.SessionID, func(*FileValidationResult) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+
+	err = svc.StreamResults(ctx, "missing", func(*FileValidationResult) error {
+		t.Fatal("없는 세션에서 콜백이 호출됨")
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrValidationSessionNotFound)
+}
+
+func TestValidationSessionService_Expiry(t *testing.T) {
+	svc, clk := setupValidationSessionTest(t)
+	ctx := context.Background()
+
+	expired, err := svc.ValidateDirectory(ctx, "docs", directoryFiles(3))
+	require.NoError(t, err)
+
+	// TTL이 지나면 정리 전이라도 조회할 수 없음
+	clk.Advance(ValidationSessionTTL)
+	_, err = svc.Results(ctx, expired.SessionID, 1, 10)
+	assert.ErrorIs(t, err, ErrValidationSessionNotFound)
+
+	// 새 검증을 시작하면 만료된 세션이 삭제됨
+	alive, err := svc.ValidateDirectory(ctx, "docs", directoryFiles(2))
+	require.NoError(t, err)
+	_, err = svc.repo.GetSession(expired.SessionID)
+	require.Error(t, err)
+
+	deleted, err := svc.PruneExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	page, err := svc.Results(ctx, alive.SessionID, 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+}
+
+func TestNewValidationSessionService_Panics(t *testing.T) {
+	repo := repository.NewValidationRepository(setupServiceTestDB(t))
+	validation := NewValidationService(config.UploadConfig{})
+
+	assert.Panics(t, func() { NewValidationSessionService(nil, repo, newSilentLogger()) })
+	assert.Panics(t, func() { NewValidationSessionService(validation, nil, newSilentLogger()) })
+	assert.Panics(t, func() { NewValidationSessionService(validation, repo, nil) })
+}
@@ -0,0 +1,613 @@
+// Package service provides business logic for DataLocker.
+// This file implements automatic ingestion of files dropped into watched directories.
+package service
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // 파일 모델의 체크섬 형식(MD5)과 일치
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/charset"
+	"DataLocker/pkg/clock"
+	"DataLocker/pkg/crypto"
+	"DataLocker/pkg/simhash"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// 감시 서비스 관련 상수
+const (
+	// 워커 대기열 크기 (워커당)
+	watchQueueSizePerWorker = 64
+
+	// MIME 타입 판별에 사용하는 최대 바이트 수
+	mimeSniffSize = 512
+
+	// 생성 디렉터리/파일 권한
+	watchDirPerm  = 0o750
+	watchFilePerm = 0o600
+
+	// 암호화 파일 이름 길이 (랜덤 바이트)
+	encryptedNameBytes = 16
+
+	// 암호화 파일 확장자
+	EncryptedFileExt = ".dlk"
+)
+
+// 감시 서비스 에러
+var (
+	ErrWatchAlreadyStarted = errors.New("감시 서비스가 이미 시작되었습니다")
+	ErrWatchNoPassword     = errors.New("자동 암호화 패스워드가 설정되지 않았습니다")
+	ErrWatchInvalidPolicy  = errors.New("지원하지 않는 원본 처리 정책입니다")
+	ErrWatchInvalidFile    = errors.New("수집할 수 없는 파일입니다")
+)
+
+// WatchService 수집함 디렉터리를 감시해 새 파일을 자동으로 암호화/등록하는 서비스
+type WatchService interface {
+	// Start 감시를 시작합니다 (ctx 종료 시 감시도 정리됩니다)
+	Start(ctx context.Context) error
+
+	// Stop 감시를 중단하고 진행 중인 작업이 끝날 때까지 기다립니다
+	Stop() error
+}
+
+// watchService fsnotify 기반 감시 서비스 구현체
+type watchService struct {
+	cfg        config.WatchConfig
+	iterations int // 키 슬롯의 PBKDF2 반복 횟수
+	storage    StorageService
+	fileRepo   repository.FileRepository
+	txManager  repository.TxManager
+	validator  ValidationService
+	engine     *crypto.CryptoEngine
+	logger     *logrus.Logger
+	clock      clock.Clock
+
+	watcher *fsnotify.Watcher
+	jobs    chan string
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	pending map[string]*pendingFile
+	started bool
+}
+
+// pendingFile 안정화를 기다리는 파일
+type pendingFile struct {
+	timer   clock.Timer
+	size    int64
+	dropped chan struct{} // Stop이 대기를 취소하면 닫힘
+}
+
+// NewWatchService 새로운 감시 서비스를 생성합니다
+//
+// 암호화본은 업로드와 같이 storage가 고르고 예약한 볼륨 경로에 저장합니다.
+func NewWatchService(
+	cfg config.WatchConfig,
+	iterations int,
+	storage StorageService,
+	fileRepo repository.FileRepository,
+	txManager repository.TxManager,
+	validator ValidationService,
+	logger *logrus.Logger,
+) (WatchService, error) {
+	if storage == nil || fileRepo == nil || txManager == nil || validator == nil || logger == nil {
+		return nil, fmt.Errorf("감시 서비스 의존성이 필요합니다")
+	}
+
+	if cfg.Password == "" {
+		return nil, ErrWatchNoPassword
+	}
+
+	switch cfg.SourcePolicy {
+	case config.WatchSourcePolicyMove, config.WatchSourcePolicyDelete, config.WatchSourcePolicyKeep:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrWatchInvalidPolicy, cfg.SourcePolicy)
+	}
+
+	if cfg.Workers <= 0 {
+		cfg.Workers = config.DefaultWatchWorkers
+	}
+
+	if iterations == 0 {
+		iterations = crypto.PBKDF2Iterations
+	}
+
+	return &watchService{
+		cfg:        cfg,
+		iterations: iterations,
+		storage:    storage,
+		fileRepo:   fileRepo,
+		txManager:  txManager,
+		validator:  validator,
+		engine:     crypto.NewCryptoEngine(),
+		logger:     logger,
+		clock:      clock.Real{},
+		pending:    make(map[string]*pendingFile),
+	}, nil
+}
+
+// Start 감시를 시작합니다
+func (s *watchService) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return ErrWatchAlreadyStarted
+	}
+
+	if err := s.prepareDirs(); err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("파일 감시자 생성 실패: %w", err)
+	}
+
+	for _, dir := range s.cfg.Dirs {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return fmt.Errorf("디렉터리 감시 등록 실패 (%s): %w", dir, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.watcher = watcher
+	s.cancel = cancel
+	s.jobs = make(chan string, s.cfg.Workers*watchQueueSizePerWorker)
+	s.started = true
+
+	// 워커 풀 시작 (대량 유입 시 동시 처리 수 제한)
+	for i := 0; i < s.cfg.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker(ctx)
+	}
+
+	s.wg.Add(1)
+	go s.eventLoop(ctx)
+
+	// 감시 시작 전에 이미 놓여 있던 파일도 수집
+	for _, dir := range s.cfg.Dirs {
+		s.scanExisting(dir)
+	}
+
+	s.logger.WithField("dirs", s.cfg.Dirs).Info("수집함 감시를 시작합니다")
+	return nil
+}
+
+// Stop 감시를 중단하고 진행 중인 작업을 기다립니다
+func (s *watchService) Stop() error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return nil
+	}
+	s.started = false
+
+	// 대기 중인 안정화 타이머 정리
+	for path, p := range s.pending {
+		p.timer.Stop()
+		close(p.dropped)
+		delete(s.pending, path)
+	}
+	s.cancel()
+	err := s.watcher.Close()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	if err != nil {
+		return fmt.Errorf("파일 감시자 종료 실패: %w", err)
+	}
+
+	s.logger.Info("수집함 감시를 종료했습니다")
+	return nil
+}
+
+// prepareDirs 감시 디렉터리를 확인하고 move 정책의 보관 디렉터리를 준비합니다
+//
+// 암호화본은 저장소 볼륨에 저장하므로 따로 준비할 출력 디렉터리는 없습니다.
+func (s *watchService) prepareDirs() error {
+	if len(s.cfg.Dirs) == 0 {
+		return fmt.Errorf("감시할 디렉터리가 없습니다")
+	}
+
+	if s.cfg.SourcePolicy != config.WatchSourcePolicyMove {
+		return nil
+	}
+
+	if s.cfg.ArchiveDir == "" {
+		return fmt.Errorf("보관 디렉터리가 설정되지 않았습니다")
+	}
+	if err := os.MkdirAll(s.cfg.ArchiveDir, watchDirPerm); err != nil {
+		return fmt.Errorf("디렉터리 생성 실패 (%s): %w", s.cfg.ArchiveDir, err)
+	}
+
+	return nil
+}
+
+// eventLoop fsnotify 이벤트를 받아 안정화 대기열에 등록합니다
+func (s *watchService) eventLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) {
+				s.schedule(ctx, event.Name)
+			}
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+			s.logger.WithError(err).Warn("파일 감시 오류가 발생했습니다")
+		}
+	}
+}
+
+// scanExisting 디렉터리에 이미 존재하는 파일을 대기열에 등록합니다
+func (s *watchService) scanExisting(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		s.logger.WithError(err).WithField("dir", dir).Warn("수집함 초기 스캔에 실패했습니다")
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			s.scheduleLocked(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+// schedule 파일을 안정화 대기열에 등록합니다 (중복 이벤트는 타이머 재설정으로 debounce)
+func (s *watchService) schedule(ctx context.Context, path string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		s.scheduleLocked(path)
+	}
+}
+
+// scheduleLocked 잠금을 보유한 상태에서 안정화 타이머를 설정합니다
+func (s *watchService) scheduleLocked(path string) {
+	if isTemporaryFile(path) {
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return
+	}
+
+	if p, exists := s.pending[path]; exists {
+		p.size = info.Size()
+		p.timer.Reset(s.cfg.StableDelay)
+		return
+	}
+
+	p := &pendingFile{
+		size:    info.Size(),
+		timer:   s.clock.NewTimer(s.cfg.StableDelay),
+		dropped: make(chan struct{}),
+	}
+	s.pending[path] = p
+	go s.awaitStable(path, p)
+}
+
+// awaitStable 안정화 타이머가 울릴 때마다 확인하며, 대기열에서 빠지면 끝납니다
+func (s *watchService) awaitStable(path string, p *pendingFile) {
+	for {
+		select {
+		case <-p.timer.C():
+		case <-p.dropped:
+			return
+		}
+
+		if !s.checkStable(path, p) {
+			return
+		}
+	}
+}
+
+// checkStable 대기 시간 동안 크기 변화가 없었는지 확인하고 작업을 제출합니다
+//
+// 타이머를 다시 걸어 계속 기다려야 하면 true를 반환합니다.
+func (s *watchService) checkStable(path string, p *pendingFile) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, exists := s.pending[path]; !exists || current != p || !s.started {
+		return false
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		delete(s.pending, path)
+		return false
+	}
+
+	// 아직 쓰는 중이면 다시 대기
+	if info.Size() != p.size {
+		p.size = info.Size()
+		p.timer.Reset(s.cfg.StableDelay)
+		return true
+	}
+
+	select {
+	case s.jobs <- path:
+		delete(s.pending, path)
+		return false
+	default:
+		// 대기열이 가득 찬 경우 잠시 후 다시 시도
+		p.timer.Reset(s.cfg.StableDelay)
+		return true
+	}
+}
+
+// worker 대기열의 파일을 순서대로 수집합니다
+func (s *watchService) worker(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case path := <-s.jobs:
+			if err := s.ingest(ctx, path); err != nil {
+				s.logger.WithError(err).WithField("path", path).Error("파일 자동 수집에 실패했습니다")
+			}
+		}
+	}
+}
+
+// ingest 검증 → 암호화 → 레코드 생성 → 원본 처리 순서로 파일을 수집합니다
+//
+// 업로드와 같이 저장소가 예약한 경로의 임시 파일에 암호화한 뒤, 레코드를 만드는
+// 트랜잭션 안에서 최종 경로로 옮깁니다.
+func (s *watchService) ingest(ctx context.Context, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("파일 정보 조회 실패: %w", err)
+	}
+
+	digest, err := inspectFile(path)
+	if err != nil {
+		return err
+	}
+
+	// 1. 검증
+	result, err := s.validator.ValidateFile(ctx, info.Name(), info.Size(), digest.mimeType)
+	if err != nil {
+		return fmt.Errorf("파일 검증 실패: %w", err)
+	}
+	if !result.IsValid {
+		return fmt.Errorf("%w: %s", ErrWatchInvalidFile, strings.Join(result.Errors, ", "))
+	}
+
+	// 이미 등록된 내용이면 이번 수집은 레코드를 남기지 않으므로 원본도 그대로 둠
+	// (delete 정책이 레코드 없이 원본을 지우지 않도록 함)
+	existing, err := s.fileRepo.GetByChecksumMD5(ctx, digest.checksumMD5)
+	if err != nil && !errors.Is(err, model.ErrRecordNotFound) {
+		return fmt.Errorf("중복 파일 확인 실패: %w", err)
+	}
+	if err == nil {
+		s.logger.WithFields(logrus.Fields{
+			"path":    path,
+			"file_id": existing.ID,
+		}).Warn("이미 등록된 내용이라 수집하지 않고 원본을 그대로 둡니다")
+		return nil
+	}
+
+	// 2. 예약한 저장소 경로의 임시 파일에 암호화 (레코드는 아직 없음)
+	volumeID, finalPath, release, err := s.storage.ReservePath(ctx, info.Size())
+	if err != nil {
+		return err
+	}
+	defer release()
+	partPath := finalPath + partialFileExt
+
+	if err := s.encryptToStorage(path, partPath, digest.mimeType); err != nil {
+		return err
+	}
+
+	metadata, err := readStreamMetadata(partPath)
+	if err != nil {
+		_ = os.Remove(partPath)
+		return err
+	}
+
+	encryptedInfo, err := os.Stat(partPath)
+	if err != nil {
+		_ = os.Remove(partPath)
+		return fmt.Errorf("암호화 파일 크기 확인 실패: %w", err)
+	}
+
+	// 3. 레코드와 암호화 메타데이터를 한 트랜잭션으로 생성하고 최종 경로로 이동
+	file := &model.File{
+		OriginalName:  info.Name(),
+		EncryptedPath: finalPath,
+		VolumeID:      volumeID,
+		Size:          info.Size(),
+		EncryptedSize: encryptedInfo.Size(),
+		MimeType:      digest.mimeType,
+		ChecksumMD5:   digest.checksumMD5,
+		Status:        model.FileStatusEncrypted,
+		BlockHashes:   digest.blockHashes,
+		TextEncoding:  digest.textEncoding,
+	}
+	if signature, ok := digest.similarity.Signature(); ok {
+		file.SetSimilaritySignature(signature)
+	}
+	moved := false
+	err = s.txManager.WithinTransaction(ctx, func(repos repository.Repositories) error {
+		if err := createFileWithMetadata(ctx, repos, file, metadata); err != nil {
+			return err
+		}
+
+		if err := os.Rename(partPath, finalPath); err != nil {
+			return fmt.Errorf("암호화 파일 이동 실패: %w", err)
+		}
+		moved = true
+		return nil
+	})
+	if err != nil {
+		if moved {
+			_ = os.Remove(finalPath)
+		} else {
+			_ = os.Remove(partPath)
+		}
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"path":    path,
+		"file_id": file.ID,
+	}).Info("파일을 자동으로 암호화해 등록했습니다")
+
+	// 4. 원본 처리
+	return s.handleSource(path)
+}
+
+// encryptToStorage 원본 파일을 저장소의 임시 경로에 암호화해 저장합니다
+//
+// 이미 압축된 형식(jpeg, zip 등)이 아니면 청크를 압축한 뒤 암호화합니다.
+func (s *watchService) encryptToStorage(path, partPath, mimeType string) error {
+	src, err := os.Open(path) //nolint:gosec // 감시 디렉터리 내부 경로
+	if err != nil {
+		return fmt.Errorf("원본 파일 열기 실패: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, watchFilePerm) //nolint:gosec // 저장소 내부 랜덤 경로
+	if err != nil {
+		return fmt.Errorf("암호화 파일 생성 실패: %w", err)
+	}
+
+	compression := crypto.WithCompression(crypto.CompressionForMIME(mimeType))
+	encErr := s.engine.EncryptStreamWithOptions(src, dst, []string{s.cfg.Password}, compression, crypto.WithIterations(s.iterations))
+	closeErr := dst.Close()
+	if encErr == nil {
+		encErr = closeErr
+	}
+	if encErr != nil {
+		_ = os.Remove(partPath)
+		return fmt.Errorf("파일 암호화 실패: %w", encErr)
+	}
+
+	return nil
+}
+
+// handleSource 정책에 따라 원본 파일을 이동/삭제/유지합니다
+func (s *watchService) handleSource(path string) error {
+	switch s.cfg.SourcePolicy {
+	case config.WatchSourcePolicyDelete:
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("원본 파일 삭제 실패: %w", err)
+		}
+	case config.WatchSourcePolicyMove:
+		target := filepath.Join(s.cfg.ArchiveDir, filepath.Base(path))
+		if _, err := os.Stat(target); err == nil {
+			target = filepath.Join(s.cfg.ArchiveDir,
+				fmt.Sprintf("%d_%s", s.clock.Now().UnixNano(), filepath.Base(path)))
+		}
+		if err := os.Rename(path, target); err != nil {
+			return fmt.Errorf("원본 파일 이동 실패: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// fileDigest 수집 대상 파일의 요약 정보
+type fileDigest struct {
+	mimeType     string
+	checksumMD5  string
+	blockHashes  string // 소유 증명용 블록별 SHA-256
+	textEncoding string // 텍스트 파일의 문자 인코딩 (텍스트가 아니면 빈 값)
+	similarity   *simhash.Hasher
+}
+
+// inspectFile 파일의 MIME 타입, MD5 체크섬, 블록 해시, 문자 인코딩, SimHash를 계산합니다
+func inspectFile(path string) (*fileDigest, error) {
+	file, err := os.Open(path) //nolint:gosec // 감시 디렉터리 내부 경로
+	if err != nil {
+		return nil, fmt.Errorf("원본 파일 열기 실패: %w", err)
+	}
+	defer file.Close()
+
+	head := make([]byte, mimeSniffSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("파일 읽기 실패: %w", err)
+	}
+
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return nil, fmt.Errorf("MIME 타입 판별 실패: %w", err)
+	}
+
+	md5Hash := md5.New() //nolint:gosec // 파일 모델의 체크섬 형식(MD5)과 일치
+	blockHasher := NewBlockHasher()
+	sniffer := &charset.Sniffer{}
+	similarity := simhash.New()
+	digest := io.MultiWriter(md5Hash, blockHasher, sniffer, similarity)
+
+	_, _ = digest.Write(head[:n])
+	if _, err := io.Copy(digest, file); err != nil {
+		return nil, fmt.Errorf("체크섬 계산 실패: %w", err)
+	}
+
+	return &fileDigest{
+		mimeType:     mimeType,
+		checksumMD5:  hex.EncodeToString(md5Hash.Sum(nil)),
+		blockHashes:  blockHasher.Hex(),
+		textEncoding: textEncodingFor(mimeType, sniffer),
+		similarity:   similarity,
+	}, nil
+}
+
+// randomFileName 암호화 파일에 사용할 랜덤 파일명을 생성합니다
+func randomFileName() (string, error) {
+	buf := make([]byte, encryptedNameBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("파일명 생성 실패: %w", err)
+	}
+	return hex.EncodeToString(buf) + EncryptedFileExt, nil
+}
+
+// isTemporaryFile 편집기/브라우저가 만드는 임시 파일인지 확인합니다
+func isTemporaryFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, ".") ||
+		strings.HasSuffix(name, "~") ||
+		strings.HasSuffix(name, ".tmp") ||
+		strings.HasSuffix(name, ".part") ||
+		strings.HasSuffix(name, ".crdownload")
+}
@@ -0,0 +1,410 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"DataLocker/internal/config"
+	"DataLocker/internal/database"
+	"DataLocker/internal/model"
+	"DataLocker/internal/repository"
+	"DataLocker/pkg/clock"
+	"DataLocker/pkg/crypto"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 테스트용 상수
+const (
+	testWatchPassword    = "watch-test-password"
+	testWatchStableDelay = 100 * time.Millisecond
+	testWatchTimeout     = 15 * time.Second
+	testWatchTick        = 20 * time.Millisecond
+)
+
+// watchTestEnv 감시 서비스 통합 테스트 환경
+type watchTestEnv struct {
+	cfg        config.WatchConfig
+	storageDir string
+	fileRepo   repository.FileRepository
+	service    WatchService
+}
+
+// setupServiceTestDB 임시 디렉터리에 마이그레이션된 테스트 DB를 생성합니다
+func setupServiceTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "service.db") + "?_foreign_keys=ON&_journal_mode=WAL&_busy_timeout=5000"
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
+	})
+	require.NoError(t, err)
+	_, err = model.MigrateUp(db)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, dbErr := db.DB(); dbErr == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	return db
+}
+
+// newSilentLogger 출력을 버리는 테스트용 로거를 생성합니다
+func newSilentLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+// newWatchStorage dir 하나를 기본 볼륨으로 쓰는 저장소 서비스를 생성합니다
+func newWatchStorage(t *testing.T, dir string, fileRepo repository.FileRepository) StorageService {
+	t.Helper()
+	storage, err := NewStorageService(config.StorageConfig{Dir: dir}, fileRepo, repository.NewMemoryFileLocker(0), newSilentLogger())
+	require.NoError(t, err)
+	return storage
+}
+
+// setupWatchTest 임시 디렉터리와 DB로 감시 서비스를 구성합니다
+func setupWatchTest(t *testing.T, policy string) *watchTestEnv {
+	t.Helper()
+	root := t.TempDir()
+	db := setupServiceTestDB(t)
+
+	inbox := filepath.Join(root, "inbox")
+	require.NoError(t, os.MkdirAll(inbox, 0o750))
+
+	cfg := config.WatchConfig{
+		Dirs:         []string{inbox},
+		ArchiveDir:   filepath.Join(root, "archive"),
+		SourcePolicy: policy,
+		StableDelay:  testWatchStableDelay,
+		Workers:      2,
+		Password:     testWatchPassword,
+	}
+
+	storageDir := filepath.Join(root, "storage")
+	fileRepo := repository.NewFileRepository(db)
+	svc, err := NewWatchService(cfg, 0, newWatchStorage(t, storageDir, fileRepo), fileRepo, repository.NewTxManager(db),
+		NewValidationService(config.UploadConfig{}), newSilentLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Start(context.Background()))
+	t.Cleanup(func() { _ = svc.Stop() })
+
+	return &watchTestEnv{cfg: cfg, storageDir: storageDir, fileRepo: fileRepo, service: svc}
+}
+
+// waitForFiles 등록된 파일 수가 기대값이 될 때까지 기다립니다
+func (env *watchTestEnv) waitForFiles(t *testing.T, expected int64) {
+	t.Helper()
+	ctx := context.Background()
+	require.Eventually(t, func() bool {
+		count, err := env.fileRepo.Count(ctx)
+		return err == nil && count == expected
+	}, testWatchTimeout, testWatchTick)
+}
+
+func TestNewWatchService_Validation(t *testing.T) {
+	log := logrus.New()
+	repo := repository.NewFileRepository(&gorm.DB{})
+	tx := repository.NewTxManager(&gorm.DB{})
+	storage := newWatchStorage(t, t.TempDir(), repo)
+
+	testCases := []struct {
+		name    string
+		cfg     config.WatchConfig
+		wantErr error
+	}{
+		{
+			name:    "패스워드 없음",
+			cfg:     config.WatchConfig{SourcePolicy: config.WatchSourcePolicyKeep},
+			wantErr: ErrWatchNoPassword,
+		},
+		{
+			name:    "잘못된 원본 처리 정책",
+			cfg:     config.WatchConfig{SourcePolicy: "shred", Password: testWatchPassword},
+			wantErr: ErrWatchInvalidPolicy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewWatchService(tc.cfg, 0, storage, repo, tx, NewValidationService(config.UploadConfig{}), log)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+
+	_, err := NewWatchService(config.WatchConfig{}, 0, nil, nil, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestWatchService_IngestAndMove(t *testing.T) {
+	ctx := context.Background()
+	env := setupWatchTest(t, config.WatchSourcePolicyMove)
+	content := []byte("자동 수집 대상 문서입니다")
+
+	source := filepath.Join(env.cfg.Dirs[0], "report.txt")
+	require.NoError(t, os.WriteFile(source, content, 0o600))
+
+	env.waitForFiles(t, 1)
+
+	files, _, err := env.fileRepo.GetAll(ctx, 0, 10, repository.SortOption{})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "report.txt", files[0].OriginalName)
+	assert.Equal(t, model.FileStatusEncrypted, files[0].Status)
+	assert.Equal(t, int64(len(content)), files[0].Size)
+
+	// 업로드와 같이 저장소 볼륨의 예약한 경로에 저장 (예약 표시와 임시 파일은 남지 않음)
+	assert.Equal(t, config.DefaultStorageVolumeID, files[0].VolumeID)
+	assert.Equal(t, env.storageDir, filepath.Dir(files[0].EncryptedPath))
+	entries, err := os.ReadDir(env.storageDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, filepath.Base(files[0].EncryptedPath), entries[0].Name())
+
+	// 원본은 보관 디렉터리로 이동
+	require.Eventually(t, func() bool {
+		_, statErr := os.Stat(filepath.Join(env.cfg.ArchiveDir, "report.txt"))
+		return statErr == nil
+	}, testWatchTimeout, testWatchTick)
+	assert.NoFileExists(t, source)
+
+	// 암호화 파일은 패스워드로 복호화 가능
+	encrypted, err := os.Open(files[0].EncryptedPath)
+	require.NoError(t, err)
+	defer encrypted.Close()
+
+	var plain bytes.Buffer
+	require.NoError(t, crypto.NewCryptoEngine().DecryptStream(encrypted, &plain, testWatchPassword))
+	assert.Equal(t, content, plain.Bytes())
+
+	// 업로드와 같이 primary 암호화 메타데이터가 함께 생성됨
+	stored, err := env.fileRepo.GetByID(ctx, files[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.EncryptionMetadata)
+	assert.Equal(t, model.MetadataPurposePrimary, stored.EncryptionMetadata.Purpose)
+	assert.Equal(t, crypto.PBKDF2Iterations, stored.EncryptionMetadata.Iterations)
+
+	missing, err := env.fileRepo.ListMissingMetadata(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestWatchService_MetadataFailureLeavesNoRecord(t *testing.T) {
+	root := t.TempDir()
+	db := setupServiceTestDB(t)
+	cfg := config.WatchConfig{
+		Dirs:         []string{filepath.Join(root, "inbox")},
+		SourcePolicy: config.WatchSourcePolicyKeep,
+		Password:     testWatchPassword,
+	}
+	storageDir := filepath.Join(root, "storage")
+	require.NoError(t, os.MkdirAll(cfg.Dirs[0], 0o750))
+
+	fileRepo := repository.NewFileRepository(db)
+	tx := &failingMetadataTx{TxManager: repository.NewTxManager(db)}
+	svc, err := NewWatchService(cfg, 0, newWatchStorage(t, storageDir, fileRepo), fileRepo, tx,
+		NewValidationService(config.UploadConfig{}), newSilentLogger())
+	require.NoError(t, err)
+
+	source := filepath.Join(cfg.Dirs[0], "report.txt")
+	require.NoError(t, os.WriteFile(source, []byte("메타데이터 실패 테스트"), 0o600))
+
+	err = svc.(*watchService).ingest(context.Background(), source)
+	require.ErrorContains(t, err, "metadata insert failed")
+
+	// 먼저 생성한 파일 레코드도 롤백되어 소프트 삭제 레코드조차 없어야 함
+	var rows int64
+	require.NoError(t, db.Unscoped().Model(&model.File{}).Count(&rows).Error)
+	assert.Zero(t, rows)
+
+	entries, err := os.ReadDir(storageDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.FileExists(t, source)
+}
+
+func TestWatchService_DebounceUntilStable(t *testing.T) {
+	ctx := context.Background()
+	env := setupWatchTest(t, config.WatchSourcePolicyDelete)
+	source := filepath.Join(env.cfg.Dirs[0], "growing.txt")
+
+	// 여러 번 나누어 쓰는 동안에는 수집되지 않아야 함
+	file, err := os.Create(source)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err = file.WriteString("chunk of slowly written text\n")
+		require.NoError(t, err)
+		time.Sleep(testWatchStableDelay / 2)
+	}
+	require.NoError(t, file.Close())
+
+	env.waitForFiles(t, 1)
+	require.Eventually(t, func() bool {
+		_, statErr := os.Stat(source)
+		return os.IsNotExist(statErr)
+	}, testWatchTimeout, testWatchTick)
+
+	// 중복 이벤트로 여러 번 등록되지 않아야 함
+	time.Sleep(testWatchStableDelay * 3)
+	count, err := env.fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	files, _, err := env.fileRepo.GetAll(ctx, 0, 10, repository.SortOption{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5*len("chunk of slowly written text\n")), files[0].Size)
+}
+
+// newClockedWatchService 감시를 시작하지 않고 가짜 시계를 쓰는 감시 서비스를 생성합니다
+func newClockedWatchService(t *testing.T, cfg config.WatchConfig) (*watchService, *clock.Fake) {
+	t.Helper()
+	fileRepo := repository.NewFileRepository(&gorm.DB{})
+	svc, err := NewWatchService(cfg, 0, newWatchStorage(t, t.TempDir(), fileRepo), fileRepo,
+		repository.NewTxManager(&gorm.DB{}), NewValidationService(config.UploadConfig{}), newSilentLogger())
+	require.NoError(t, err)
+
+	clk := clock.NewFake(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	s := svc.(*watchService)
+	s.clock = clk
+	return s, clk
+}
+
+func TestWatchService_DebounceOnClock(t *testing.T) {
+	s, clk := newClockedWatchService(t, config.WatchConfig{
+		SourcePolicy: config.WatchSourcePolicyKeep,
+		StableDelay:  time.Second,
+		Password:     testWatchPassword,
+	})
+	s.jobs = make(chan string, 1)
+	s.started = true
+
+	source := filepath.Join(t.TempDir(), "growing.txt")
+	require.NoError(t, os.WriteFile(source, []byte("first"), 0o600))
+	s.schedule(context.Background(), source)
+
+	// 대기 시간 안에 크기가 바뀌면 한 번 더 기다림
+	clk.BlockUntil(1)
+	require.NoError(t, os.WriteFile(source, []byte("first second"), 0o600))
+	clk.Advance(time.Second)
+	clk.BlockUntil(1)
+	assert.Empty(t, s.jobs)
+
+	clk.Advance(time.Second)
+	select {
+	case path := <-s.jobs:
+		assert.Equal(t, source, path)
+	case <-time.After(testWatchTimeout):
+		t.Fatal("안정된 파일이 대기열에 들어가지 않았습니다")
+	}
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.pending) == 0
+	}, testWatchTimeout, testWatchTick)
+}
+
+func TestWatchService_ArchiveNameCollision(t *testing.T) {
+	root := t.TempDir()
+	archive := filepath.Join(root, "archive")
+	require.NoError(t, os.MkdirAll(archive, 0o750))
+	s, clk := newClockedWatchService(t, config.WatchConfig{
+		ArchiveDir:   archive,
+		SourcePolicy: config.WatchSourcePolicyMove,
+		Password:     testWatchPassword,
+	})
+
+	// 같은 이름이 이미 보관되어 있으면 시계의 시각을 앞에 붙임
+	require.NoError(t, os.WriteFile(filepath.Join(archive, "report.txt"), []byte("old"), 0o600))
+	source := filepath.Join(root, "report.txt")
+	require.NoError(t, os.WriteFile(source, []byte("new"), 0o600))
+
+	require.NoError(t, s.handleSource(source))
+	assert.NoFileExists(t, source)
+	assert.FileExists(t, filepath.Join(archive, fmt.Sprintf("%d_report.txt", clk.Now().UnixNano())))
+}
+
+func TestWatchService_BulkIngest(t *testing.T) {
+	env := setupWatchTest(t, config.WatchSourcePolicyDelete)
+
+	const fileCount = 20
+	for i := 0; i < fileCount; i++ {
+		name := filepath.Join(env.cfg.Dirs[0], "bulk_"+string(rune('a'+i))+".txt")
+		require.NoError(t, os.WriteFile(name, []byte("bulk content "+name), 0o600))
+	}
+
+	env.waitForFiles(t, fileCount)
+}
+
+func TestWatchService_DuplicateSourceKept(t *testing.T) {
+	ctx := context.Background()
+	env := setupWatchTest(t, config.WatchSourcePolicyDelete)
+	content := []byte("같은 내용으로 두 번 들어오는 문서입니다")
+
+	first := filepath.Join(env.cfg.Dirs[0], "first.txt")
+	require.NoError(t, os.WriteFile(first, content, 0o600))
+	env.waitForFiles(t, 1)
+	require.Eventually(t, func() bool {
+		_, statErr := os.Stat(first)
+		return os.IsNotExist(statErr)
+	}, testWatchTimeout, testWatchTick)
+
+	// 이미 등록된 내용은 이번 수집의 레코드가 없으므로 delete 정책이어도 원본을 지우지 않음
+	second := filepath.Join(env.cfg.Dirs[0], "second.txt")
+	require.NoError(t, os.WriteFile(second, content, 0o600))
+	require.NoError(t, env.service.(*watchService).ingest(ctx, second))
+
+	count, err := env.fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.FileExists(t, second)
+}
+
+func TestWatchService_InvalidFileKept(t *testing.T) {
+	ctx := context.Background()
+	env := setupWatchTest(t, config.WatchSourcePolicyDelete)
+
+	// 허용되지 않는 형식(zip)은 등록하지 않고 원본을 유지
+	source := filepath.Join(env.cfg.Dirs[0], "archive.zip")
+	require.NoError(t, os.WriteFile(source, []byte("PK\x03\x04not really a zip"), 0o600))
+
+	time.Sleep(testWatchStableDelay * 5)
+
+	count, err := env.fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.FileExists(t, source)
+}
+
+func TestWatchService_StopCleansUp(t *testing.T) {
+	ctx := context.Background()
+	env := setupWatchTest(t, config.WatchSourcePolicyKeep)
+
+	require.NoError(t, env.service.Stop())
+	require.NoError(t, env.service.Stop())
+
+	// 종료 후에는 새 파일을 수집하지 않음
+	source := filepath.Join(env.cfg.Dirs[0], "late.txt")
+	require.NoError(t, os.WriteFile(source, []byte("late arrival"), 0o600))
+
+	time.Sleep(testWatchStableDelay * 3)
+
+	count, err := env.fileRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.FileExists(t, source)
+}
@@ -0,0 +1,92 @@
+// Package testutil provides assertion helpers shared by DataLocker tests.
+// This file checks JSON responses for fields that must never be serialized to clients.
+package testutil
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ForbiddenResponseKeys 응답 JSON 키에 들어 있으면 안 되는 단어
+//
+// 키를 소문자로 바꿔 부분 일치로 비교하므로 "salt_hex", "PasswordHint",
+// "block_hashes"처럼 단어를 포함한 키도 모두 잡습니다.
+var ForbiddenResponseKeys = []string{"salt", "nonce", "password", "secret", "hash"}
+
+// SensitiveKey 응답에서 발견된 금지 키
+type SensitiveKey struct {
+	Path string // 키의 JSON 경로 (예: "data.files[].encryption_metadata.salt_hex")
+	Word string // 일치한 금지 단어
+}
+
+// FindSensitiveKeys JSON 본문을 재귀 순회하며 금지 단어를 포함한 키를 찾습니다
+//
+// 경로는 객체 키를 '.'으로, 배열 요소를 "[]"로 이어 붙인 형식이며, allow에
+// 들어 있는 경로는 건너뜁니다 (하위 키는 계속 검사). 본문이 JSON이 아니면
+// 에러를 반환합니다.
+func FindSensitiveKeys(body []byte, allow ...string) ([]SensitiveKey, error) {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, err
+	}
+
+	var found []SensitiveKey
+	walkJSON(value, "", func(path, key string) {
+		if slices.Contains(allow, path) {
+			return
+		}
+		lower := strings.ToLower(key)
+		for _, word := range ForbiddenResponseKeys {
+			if strings.Contains(lower, word) {
+				found = append(found, SensitiveKey{Path: path, Word: word})
+				return
+			}
+		}
+	})
+
+	// 맵 순회 순서와 무관하게 같은 결과를 보고
+	slices.SortFunc(found, func(a, b SensitiveKey) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return found, nil
+}
+
+// AssertNoSensitiveKeys 응답 본문에 금지 키가 없는지 검사합니다
+//
+// 금지 키가 꼭 필요한 내부 전용 응답은 allow에 해당 경로를 명시해 예외로
+// 둡니다. 실패하면 발견한 경로를 모두 보고하고 false를 반환합니다.
+func AssertNoSensitiveKeys(t testing.TB, body []byte, allow ...string) bool {
+	t.Helper()
+
+	found, err := FindSensitiveKeys(body, allow...)
+	if err != nil {
+		t.Errorf("응답이 JSON이 아닙니다: %v\n%s", err, body)
+		return false
+	}
+
+	for _, key := range found {
+		t.Errorf("응답에 민감한 키가 포함되었습니다: %s (%q)", key.Path, key.Word)
+	}
+	return len(found) == 0
+}
+
+// walkJSON 디코딩한 JSON 값의 모든 객체 키를 경로와 함께 방문합니다
+func walkJSON(value any, path string, visit func(path, key string)) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			visit(childPath, key)
+			walkJSON(child, childPath, visit)
+		}
+	case []any:
+		for _, child := range v {
+			walkJSON(child, path+"[]", visit)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSensitiveKeys(t *testing.T) {
+	body := []byte(`{
+		"success": true,
+		"data": {
+			"files": [
+				{"id": 1, "checksum_md5": "abc", "encryption_metadata": {"SaltHex": "00", "nonce_hex": "11"}},
+				{"id": 2, "tags": [{"name": "password"}]}
+			],
+			"password_hint": null,
+			"challenge": {"nonce": "22"}
+		}
+	}`)
+
+	found, err := FindSensitiveKeys(body)
+	require.NoError(t, err)
+	assert.Equal(t, []SensitiveKey{
+		{Path: "data.challenge.nonce", Word: "nonce"},
+		{Path: "data.files[].encryption_metadata.SaltHex", Word: "salt"},
+		{Path: "data.files[].encryption_metadata.nonce_hex", Word: "nonce"},
+		{Path: "data.password_hint", Word: "password"},
+	}, found, "값이 아닌 키만 검사하고, 대소문자를 무시")
+
+	found, err = FindSensitiveKeys(body,
+		"data.challenge.nonce",
+		"data.files[].encryption_metadata.SaltHex",
+		"data.files[].encryption_metadata.nonce_hex",
+		"data.password_hint",
+	)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	_, err = FindSensitiveKeys([]byte("not json"))
+	assert.Error(t, err)
+}
+
+// failRecorder 실패를 기록만 하는 testing.TB (헬퍼 자신의 실패 보고를 검사)
+type failRecorder struct {
+	testing.TB
+	errors int
+}
+
+func (r *failRecorder) Helper() {}
+
+func (r *failRecorder) Errorf(string, ...any) {
+	r.errors++
+}
+
+func TestAssertNoSensitiveKeys(t *testing.T) {
+	assert.True(t, AssertNoSensitiveKeys(t, []byte(`{"data":[{"id":1,"checksum_md5":"abc"}]}`)))
+
+	recorder := &failRecorder{TB: t}
+	assert.False(t, AssertNoSensitiveKeys(recorder, []byte(`{"data":{"secret_key":"x","block_hashes":"y"}}`)))
+	assert.Equal(t, 2, recorder.errors, "발견한 키마다 보고")
+
+	recorder = &failRecorder{TB: t}
+	assert.False(t, AssertNoSensitiveKeys(recorder, []byte(`<html>`)))
+	assert.Equal(t, 1, recorder.errors)
+}
@@ -0,0 +1,253 @@
+// Package charset provides text encoding detection and UTF-8 conversion for DataLocker.
+// Detection is heuristic and only looks at a bounded sample of the content.
+package charset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Encoding 감지된 문자 인코딩 이름 (File.TextEncoding에 저장)
+type Encoding string
+
+// 감지 가능한 인코딩
+const (
+	UTF8    Encoding = "utf-8"
+	UTF16LE Encoding = "utf-16le"
+	UTF16BE Encoding = "utf-16be"
+	EUCKR   Encoding = "euc-kr" // CP949 확장 포함
+
+	// Unknown 감지에 실패함 (바이너리이거나 지원하지 않는 인코딩)
+	Unknown Encoding = "unknown"
+)
+
+// 감지 관련 상수
+const (
+	// SampleSize 인코딩 감지에 사용하는 앞부분 크기 (64KB)
+	SampleSize = 64 * 1024
+
+	// utf16ZeroRatio UTF-16으로 판단하는 한쪽 위치의 0 바이트 비율 (ASCII 위주 텍스트 기준)
+	utf16ZeroRatio = 0.3
+)
+
+// ErrUnsupportedEncoding 변환할 수 없는 인코딩
+var ErrUnsupportedEncoding = errors.New("지원하지 않는 문자 인코딩입니다")
+
+// 바이트 순서 표식
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// textMIMETypes text/* 외에 텍스트로 취급하는 MIME 타입
+var textMIMETypes = []string{
+	"application/json",
+	"application/xml",
+	"application/javascript",
+	"application/x-yaml",
+	"application/yaml",
+	"application/csv",
+}
+
+// IsTextMIME 인코딩 감지 대상인 텍스트 계열 MIME 타입인지 확인합니다
+func IsTextMIME(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:i])
+	}
+
+	if strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	for _, t := range textMIMETypes {
+		if mimeType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect 샘플의 문자 인코딩을 추정합니다
+//
+// BOM이 있으면 BOM을 따르고, 없으면 UTF-16(0 바이트 분포), UTF-8, EUC-KR 순으로
+// 바이트 구조가 맞는지 확인합니다. 샘플은 내용의 앞부분이므로 끝에서 잘린
+// 멀티바이트 문자는 무시합니다. 빈 샘플과 ASCII 텍스트는 UTF-8로 판단합니다.
+func Detect(sample []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(sample, bomUTF8):
+		return UTF8
+	case bytes.HasPrefix(sample, bomUTF16LE):
+		return UTF16LE
+	case bytes.HasPrefix(sample, bomUTF16BE):
+		return UTF16BE
+	}
+
+	if enc, ok := detectUTF16(sample); ok {
+		return enc
+	}
+
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return Unknown
+	}
+
+	if utf8.Valid(trimIncompleteUTF8(sample)) {
+		return UTF8
+	}
+
+	if isEUCKR(sample) {
+		return EUCKR
+	}
+
+	return Unknown
+}
+
+// ToUTF8 인코딩된 텍스트를 UTF-8로 변환합니다
+//
+// 변환할 수 없는 바이트는 U+FFFD(대체 문자)로 바꾸고, BOM은 제거합니다.
+// Unknown은 UTF-8로 간주해 잘못된 바이트만 대체합니다.
+func ToUTF8(data []byte, enc Encoding) ([]byte, error) {
+	var decoder *encoding.Decoder
+	switch enc {
+	case UTF8, Unknown:
+		data = bytes.TrimPrefix(data, bomUTF8)
+		return bytes.ToValidUTF8(data, []byte(string(utf8.RuneError))), nil
+	case UTF16LE:
+		decoder = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+		data = bytes.TrimPrefix(data, bomUTF16LE)
+	case UTF16BE:
+		decoder = unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()
+		data = bytes.TrimPrefix(data, bomUTF16BE)
+	case EUCKR:
+		decoder = korean.EUCKR.NewDecoder()
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, enc)
+	}
+
+	out, err := decoder.Bytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s 텍스트 변환 실패: %w", enc, err)
+	}
+	return out, nil
+}
+
+// TrimPartial 중간에서 잘린 텍스트 끝의 불완전한 문자를 제거합니다
+func TrimPartial(data []byte, enc Encoding) []byte {
+	switch enc {
+	case UTF8, Unknown:
+		return trimIncompleteUTF8(data)
+	case UTF16LE, UTF16BE:
+		return data[:len(data)&^1]
+	case EUCKR:
+		// 끝 바이트가 짝 없는 선행 바이트인지는 앞에서부터 세어야 알 수 있음
+		for i := 0; i < len(data); i++ {
+			if data[i] < utf8.RuneSelf {
+				continue
+			}
+			if i+1 == len(data) {
+				return data[:i]
+			}
+			i++
+		}
+		return data
+	default:
+		return data
+	}
+}
+
+// detectUTF16 BOM 없는 UTF-16을 짝수/홀수 위치의 0 바이트 분포로 판단합니다
+func detectUTF16(sample []byte) (Encoding, bool) {
+	pairs := len(sample) / 2
+	if pairs == 0 {
+		return "", false
+	}
+
+	var evenZeros, oddZeros int
+	for i := 0; i+1 < len(sample); i += 2 {
+		if sample[i] == 0 {
+			evenZeros++
+		}
+		if sample[i+1] == 0 {
+			oddZeros++
+		}
+	}
+
+	threshold := int(float64(pairs) * utf16ZeroRatio)
+	switch {
+	case oddZeros > threshold && evenZeros == 0:
+		return UTF16LE, true
+	case evenZeros > threshold && oddZeros == 0:
+		return UTF16BE, true
+	default:
+		return "", false
+	}
+}
+
+// isEUCKR 샘플이 EUC-KR(CP949) 바이트 구조에 맞는지 확인합니다
+//
+// 0x80 이상 바이트는 모두 선행(0x81-0xFE) + 후행(0x41-0x5A, 0x61-0x7A, 0x81-0xFE)
+// 쌍이어야 하며, 샘플 끝에서 잘린 선행 바이트 하나는 허용합니다.
+func isEUCKR(sample []byte) bool {
+	pairs := 0
+	for i := 0; i < len(sample); i++ {
+		b := sample[i]
+		if b < utf8.RuneSelf {
+			continue
+		}
+		if b < 0x81 || b == 0xFF {
+			return false
+		}
+		if i+1 == len(sample) {
+			break
+		}
+
+		trail := sample[i+1]
+		if !(trail >= 0x41 && trail <= 0x5A) && !(trail >= 0x61 && trail <= 0x7A) && !(trail >= 0x81 && trail <= 0xFE) {
+			return false
+		}
+		pairs++
+		i++
+	}
+	return pairs > 0
+}
+
+// trimIncompleteUTF8 샘플 끝에서 잘린 멀티바이트 문자를 제거합니다
+func trimIncompleteUTF8(sample []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(sample); i++ {
+		start := len(sample) - i
+		if utf8.RuneStart(sample[start]) {
+			if !utf8.FullRune(sample[start:]) {
+				return sample[:start]
+			}
+			return sample
+		}
+	}
+	return sample
+}
+
+// Sniffer 기록되는 내용의 앞부분을 모아 인코딩을 감지하는 Writer
+//
+// io.MultiWriter에 함께 연결해 스트림을 다시 읽지 않고 감지할 수 있습니다.
+type Sniffer struct {
+	sample []byte
+}
+
+// Write 앞부분 SampleSize 바이트만 보관하고 나머지는 버립니다
+func (s *Sniffer) Write(p []byte) (int, error) {
+	if room := SampleSize - len(s.sample); room > 0 {
+		s.sample = append(s.sample, p[:min(room, len(p))]...)
+	}
+	return len(p), nil
+}
+
+// Encoding 지금까지 모은 샘플의 인코딩을 반환합니다
+func (s *Sniffer) Encoding() Encoding {
+	return Detect(s.sample)
+}
@@ -0,0 +1,156 @@
+package charset
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/unicode"
+)
+
+const koreanText = "안녕하세요, DataLocker 미리보기 테스트입니다.\n"
+
+// encodeWith 테스트 문자열을 인코딩합니다
+func encodeWith(t *testing.T, enc Encoding, text string) []byte {
+	t.Helper()
+
+	var (
+		out []byte
+		err error
+	)
+	switch enc {
+	case EUCKR:
+		out, err = korean.EUCKR.NewEncoder().Bytes([]byte(text))
+	case UTF16LE:
+		out, err = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder().Bytes([]byte(text))
+	case UTF16BE:
+		out, err = unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewEncoder().Bytes([]byte(text))
+	default:
+		out = []byte(text)
+	}
+	require.NoError(t, err)
+	return out
+}
+
+func TestDetect(t *testing.T) {
+	utf8Sample := []byte(strings.Repeat(koreanText, 10))
+
+	testCases := []struct {
+		name   string
+		sample []byte
+		want   Encoding
+	}{
+		{name: "빈 샘플", sample: nil, want: UTF8},
+		{name: "ASCII", sample: []byte("hello, world\n"), want: UTF8},
+		{name: "UTF-8 한글", sample: utf8Sample, want: UTF8},
+		{name: "UTF-8 BOM", sample: append([]byte{0xEF, 0xBB, 0xBF}, koreanText...), want: UTF8},
+		{name: "잘린 UTF-8 샘플", sample: utf8Sample[:len(utf8Sample)-len("\n")-1], want: UTF8},
+		{name: "UTF-16LE BOM", sample: append([]byte{0xFF, 0xFE}, encodeWith(t, UTF16LE, koreanText)...), want: UTF16LE},
+		{name: "UTF-16BE BOM", sample: append([]byte{0xFE, 0xFF}, encodeWith(t, UTF16BE, koreanText)...), want: UTF16BE},
+		{name: "BOM 없는 UTF-16LE", sample: encodeWith(t, UTF16LE, "plain ascii text"), want: UTF16LE},
+		{name: "BOM 없는 UTF-16BE", sample: encodeWith(t, UTF16BE, "plain ascii text"), want: UTF16BE},
+		{name: "EUC-KR", sample: encodeWith(t, EUCKR, koreanText), want: EUCKR},
+		{name: "바이너리", sample: []byte{0x89, 'P', 'N', 'G', 0x00, 0x1A, 0x00, 0x00, 0x7F}, want: Unknown},
+		{name: "깨진 바이트", sample: []byte{'a', 0x80, 0x80, 'b'}, want: Unknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Detect(tc.sample))
+		})
+	}
+}
+
+func TestToUTF8(t *testing.T) {
+	for _, enc := range []Encoding{UTF8, UTF16LE, UTF16BE, EUCKR} {
+		t.Run(string(enc), func(t *testing.T) {
+			out, err := ToUTF8(encodeWith(t, enc, koreanText), enc)
+			require.NoError(t, err)
+			assert.Equal(t, koreanText, string(out))
+		})
+	}
+
+	// BOM은 제거
+	out, err := ToUTF8(append([]byte{0xFF, 0xFE}, encodeWith(t, UTF16LE, "hi")...), UTF16LE)
+	require.NoError(t, err)
+	assert.Equal(t, "hi", string(out))
+
+	_, err = ToUTF8([]byte("x"), Encoding("shift_jis"))
+	assert.ErrorIs(t, err, ErrUnsupportedEncoding)
+}
+
+func TestToUTF8_ReplacesInvalidBytes(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+		enc  Encoding
+		want string
+	}{
+		{name: "UTF-8 잘못된 바이트", data: []byte{'a', 0xFF, 'b'}, enc: UTF8, want: "a�b"},
+		{name: "unknown은 UTF-8로 간주", data: []byte{'a', 0xC0, 'b'}, enc: Unknown, want: "a�b"},
+		{name: "EUC-KR 짝 없는 선행 바이트", data: append(encodeWith(t, EUCKR, "가"), 0xB0), enc: EUCKR, want: "가�"},
+		{name: "UTF-16 짝 없는 서로게이트", data: []byte{0x00, 0xD8, 'a', 0x00}, enc: UTF16LE, want: "�a"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := ToUTF8(tc.data, tc.enc)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(out))
+		})
+	}
+}
+
+func TestTrimPartial(t *testing.T) {
+	utf8Text := []byte("가나")
+	assert.Equal(t, []byte("가"), TrimPartial(utf8Text[:len(utf8Text)-1], UTF8))
+	assert.Equal(t, utf8Text, TrimPartial(utf8Text, UTF8))
+
+	euckr := encodeWith(t, EUCKR, "a가나")
+	assert.Equal(t, encodeWith(t, EUCKR, "a가"), TrimPartial(euckr[:len(euckr)-1], EUCKR))
+	assert.Equal(t, euckr, TrimPartial(euckr, EUCKR))
+
+	utf16 := encodeWith(t, UTF16LE, "ab")
+	assert.Equal(t, encodeWith(t, UTF16LE, "a"), TrimPartial(utf16[:3], UTF16LE))
+}
+
+func TestIsTextMIME(t *testing.T) {
+	testCases := map[string]bool{
+		"text/plain":                true,
+		"text/csv; charset=euc-kr":  true,
+		"TEXT/HTML":                 true,
+		"application/json":          true,
+		"application/pdf":           false,
+		"image/png":                 false,
+		"application/octet-stream":  false,
+		"application/vnd.ms-excel":  false,
+		"application/x-yaml":        true,
+		"application/javascript":    true,
+		"application/xml; q=0.9":    true,
+		"multipart/form-data":       false,
+		"application/x-unknown-txt": false,
+	}
+
+	for mimeType, want := range testCases {
+		assert.Equal(t, want, IsTextMIME(mimeType), mimeType)
+	}
+}
+
+func TestSniffer_KeepsOnlySample(t *testing.T) {
+	sniffer := &Sniffer{}
+
+	// 샘플 이후의 바이트는 감지에 영향을 주지 않음
+	n, err := sniffer.Write(bytes.Repeat([]byte("a"), SampleSize))
+	require.NoError(t, err)
+	assert.Equal(t, SampleSize, n)
+
+	n, err = sniffer.Write([]byte{0x00, 0xFF, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	assert.Len(t, sniffer.sample, SampleSize)
+	assert.Equal(t, UTF8, sniffer.Encoding())
+}
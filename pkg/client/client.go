@@ -0,0 +1,367 @@
+// Package client provides a Go client for the DataLocker HTTP API.
+// Error responses are decoded from the pkg/response envelope into *APIError values
+// that can be matched with errors.Is against the sentinel errors of this package.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"DataLocker/pkg/response"
+)
+
+// 요청 헤더
+const (
+	headerEncryptionPassword = "X-Encryption-Password" //nolint:gosec // 헤더 이름
+	headerIdempotencyKey     = "Idempotency-Key"
+)
+
+// 기본 설정
+const (
+	// DefaultTimeout 응답 헤더를 기다리는 기본 시간 (본문 스트리밍 시간은 포함하지 않음)
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultRetries 재시도할 수 있는 요청의 기본 재시도 횟수
+	DefaultRetries = 2
+
+	// DefaultRetryWait 첫 재시도 전 대기 시간 (재시도마다 두 배로 늘어남)
+	DefaultRetryWait = 500 * time.Millisecond
+
+	// maxRetryWait Retry-After를 포함한 재시도 대기 시간의 상한
+	maxRetryWait = 30 * time.Second
+
+	// maxResponseBytes JSON 응답 본문의 최대 크기
+	maxResponseBytes = 16 << 20
+)
+
+// 클라이언트 에러
+//
+// 서버가 보낸 에러는 *APIError로 반환되며, 상태 코드에 맞는 아래 에러와
+// errors.Is로 비교할 수 있습니다.
+var (
+	ErrNetwork         = errors.New("서버와 통신하지 못했습니다")
+	ErrBadRequest      = errors.New("잘못된 요청입니다")
+	ErrUnauthorized    = errors.New("인증이 필요하거나 패스워드가 일치하지 않습니다")
+	ErrForbidden       = errors.New("권한이 없습니다")
+	ErrNotFound        = errors.New("대상을 찾을 수 없습니다")
+	ErrConflict        = errors.New("요청이 현재 상태와 충돌합니다")
+	ErrTooLarge        = errors.New("요청 본문이 너무 큽니다")
+	ErrTooManyRequests = errors.New("요청이 너무 많습니다")
+	ErrUnavailable     = errors.New("서버를 일시적으로 사용할 수 없습니다")
+	ErrServer          = errors.New("서버 내부 오류입니다")
+)
+
+// APIError 서버가 반환한 에러 응답
+type APIError struct {
+	StatusCode int
+	Code       string // 서버의 에러 코드 (NOT_FOUND 등, 표준 응답이 아니면 빈 값)
+	Message    string
+	Details    string // 서버가 상세 노출을 끈 경우 빈 값
+	RequestID  string // 서버 로그와 대조할 수 있는 요청 ID
+}
+
+// Error 에러 메시지를 반환합니다
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("서버 오류 (HTTP %d): %s", e.StatusCode, e.Message)
+	if e.Details != "" {
+		msg += ": " + e.Details
+	}
+	if e.RequestID != "" {
+		msg += " [request_id=" + e.RequestID + "]"
+	}
+	return msg
+}
+
+// Unwrap 상태 코드에 해당하는 패키지 에러를 반환합니다 (해당하는 에러가 없으면 nil)
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusRequestEntityTooLarge:
+		return ErrTooLarge
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrTooManyRequests
+	case e.StatusCode == http.StatusServiceUnavailable:
+		return ErrUnavailable
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+// Client DataLocker API 클라이언트
+//
+// 여러 고루틴에서 함께 사용해도 안전합니다.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	retries    int
+	retryWait  time.Duration
+}
+
+// Option 클라이언트 설정 옵션
+type Option func(*Client)
+
+// WithToken 관리 API 호출에 사용할 Bearer 토큰을 설정합니다
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHTTPClient 요청에 사용할 http.Client를 설정합니다
+//
+// 지정한 클라이언트의 Timeout은 업로드 본문 전송 시간까지 포함하므로, 큰 파일을
+// 업로드한다면 Timeout 대신 WithTimeout을 사용하는 것이 좋습니다.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout 요청마다 응답 헤더를 기다리는 최대 시간을 설정합니다 (0이면 제한 없음)
+//
+// 본문을 주고받는 시간은 포함하지 않으므로 스트리밍 업로드가 길어져도 끊기지
+// 않습니다. 전체 시간을 제한하려면 ctx에 마감 시간을 지정하세요.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.ResponseHeaderTimeout = timeout
+		c.httpClient = &http.Client{Transport: transport}
+	}
+}
+
+// WithRetries 재시도할 수 있는 요청의 최대 재시도 횟수와 첫 대기 시간을 설정합니다
+//
+// 조회 요청과 Idempotency-Key를 붙인 협상 요청만 재시도하며, 본문을 스트리밍하는
+// 업로드는 다시 보낼 수 없으므로 재시도하지 않습니다.
+func WithRetries(retries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.retryWait = wait
+	}
+}
+
+// New 새로운 API 클라이언트를 생성합니다
+//
+// baseURL은 http(s)://host:port 형식이며, 스킴이 없으면 http로 간주합니다.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	if baseURL == "" {
+		return nil, errors.New("서버 주소가 필요합니다")
+	}
+
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "http://" + baseURL
+	}
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("잘못된 서버 주소입니다: %s", baseURL)
+	}
+
+	c := &Client{
+		baseURL:   strings.TrimRight(parsed.String(), "/"),
+		retries:   DefaultRetries,
+		retryWait: DefaultRetryWait,
+	}
+	WithTimeout(DefaultTimeout)(c)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.httpClient == nil {
+		return nil, errors.New("http.Client가 필요합니다")
+	}
+	if c.retries < 0 || c.retryWait < 0 {
+		return nil, errors.New("재시도 횟수와 대기 시간은 0 이상이어야 합니다")
+	}
+
+	return c, nil
+}
+
+// request 한 번의 API 호출 정보
+type request struct {
+	method string
+	path   string // 쿼리를 포함한 경로
+	header http.Header
+
+	// 재시도할 때마다 새로 만들 수 있는 본문 (JSON 요청)
+	body []byte
+
+	// 한 번만 읽을 수 있는 스트리밍 본문 (설정되면 재시도하지 않음)
+	stream        io.Reader
+	contentLength int64
+}
+
+// retryable 실패했을 때 같은 요청을 다시 보내도 되는지 확인합니다
+func (r *request) retryable() bool {
+	if r.stream != nil {
+		return false
+	}
+	return r.method == http.MethodGet || r.header.Get(headerIdempotencyKey) != ""
+}
+
+// do 요청을 보내고 표준 응답의 data를 out으로 디코딩합니다
+//
+// 네트워크 오류와 429/502/503/504 응답은 재시도할 수 있는 요청에 한해
+// 지수 백오프(서버가 Retry-After를 보내면 그 값)로 다시 보냅니다.
+func (c *Client) do(ctx context.Context, req *request, out interface{}) error {
+	wait := c.retryWait
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.send(ctx, req, out)
+		if err == nil || attempt >= c.retries || !req.retryable() || !shouldRetry(err) {
+			return err
+		}
+
+		delay := wait
+		if retryAfter > 0 {
+			delay = retryAfter
+		}
+		delay = min(delay, maxRetryWait)
+		wait *= 2
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// send 요청을 한 번 보내고, 응답이 Retry-After를 포함하면 그 대기 시간을 함께 반환합니다
+func (c *Client) send(ctx context.Context, req *request, out interface{}) (time.Duration, error) {
+	var body io.Reader = http.NoBody
+	switch {
+	case req.stream != nil:
+		body = req.stream
+	case req.body != nil:
+		body = bytes.NewReader(req.body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, c.baseURL+req.path, body)
+	if err != nil {
+		return 0, fmt.Errorf("요청 생성 실패: %w", err)
+	}
+
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+	if req.stream != nil {
+		httpReq.ContentLength = req.contentLength
+		httpReq.Header.Set("Content-Type", "application/octet-stream")
+	} else if req.body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrNetwork, err)
+	}
+	defer resp.Body.Close()
+
+	return parseRetryAfter(resp.Header.Get("Retry-After")), decodeResponse(resp, out)
+}
+
+// decodeResponse 표준 응답 본문을 읽어 에러는 *APIError로, 성공은 data를 out으로 변환합니다
+func decodeResponse(resp *http.Response, out interface{}) error {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("%w: 응답 읽기 실패: %w", ErrNetwork, err)
+	}
+
+	var envelope struct {
+		response.Response
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		// 프록시 등이 보낸 표준이 아닌 에러 응답
+		if resp.StatusCode >= http.StatusBadRequest {
+			return &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		}
+		return fmt.Errorf("응답 형식이 올바르지 않습니다: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest || !envelope.Success {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: envelope.Message}
+		if envelope.Error != nil {
+			apiErr.Code = envelope.Error.Code
+			apiErr.Details = envelope.Error.Details
+			apiErr.RequestID = envelope.Error.RequestID
+		}
+		return apiErr
+	}
+
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("응답 데이터 형식이 올바르지 않습니다: %w", err)
+	}
+	return nil
+}
+
+// shouldRetry 일시적인 실패인지 확인합니다
+func shouldRetry(err error) bool {
+	if errors.Is(err, ErrNetwork) {
+		return true
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter 초 단위 Retry-After 헤더를 해석합니다 (없거나 형식이 다르면 0)
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// newIdempotencyKey 요청 하나를 구분하는 임의의 Idempotency-Key를 생성합니다
+func newIdempotencyKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("Idempotency-Key 생성 실패: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
@@ -0,0 +1,363 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // 테스트용 체크섬
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"DataLocker/pkg/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeEnvelope 서버와 같은 표준 응답을 씁니다
+func writeEnvelope(t *testing.T, w http.ResponseWriter, status int, data interface{}) {
+	t.Helper()
+
+	body := response.Response{Success: status < http.StatusBadRequest, Message: "ok", Data: data}
+	if status >= http.StatusBadRequest {
+		body.Message = "실패"
+		body.Error = &response.ErrorInfo{Code: "ERROR", Message: "실패", RequestID: "req-1"}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(body))
+}
+
+// newTestClient 재시도 대기 없이 server를 호출하는 클라이언트를 생성합니다
+func newTestClient(t *testing.T, server *httptest.Server, opts ...Option) *Client {
+	t.Helper()
+
+	c, err := New(server.URL, append([]Option{WithRetries(2, time.Millisecond)}, opts...)...)
+	require.NoError(t, err)
+	return c
+}
+
+// patternReader size 바이트의 반복 패턴을 만드는 io.Reader (io.Seeker가 아님)
+type patternReader struct {
+	remaining int64
+	offset    byte
+}
+
+func (r *patternReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	for i := range p {
+		p[i] = r.offset
+		r.offset++
+	}
+	r.remaining -= int64(len(p))
+	return len(p), nil
+}
+
+func TestNew(t *testing.T) {
+	c, err := New("localhost:8080/")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", c.baseURL)
+
+	_, err = New("")
+	assert.Error(t, err)
+
+	_, err = New("http://")
+	assert.Error(t, err)
+
+	_, err = New("localhost", WithRetries(-1, 0))
+	assert.Error(t, err)
+}
+
+func TestUploadFile_StreamsBody(t *testing.T) {
+	const size = 64 << 20
+
+	h := md5.New() //nolint:gosec // 테스트용 체크섬
+	_, err := io.Copy(h, &patternReader{remaining: size})
+	require.NoError(t, err)
+	checksum := hex.EncodeToString(h.Sum(nil))
+
+	var received int64
+	var receivedChecksum string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/files/negotiate", func(w http.ResponseWriter, r *http.Request) {
+		var req negotiateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "big.bin", req.OriginalName)
+		assert.Equal(t, int64(size), req.Size)
+		assert.Equal(t, checksum, req.ChecksumMD5)
+		assert.NotEmpty(t, r.Header.Get(headerIdempotencyKey))
+
+		writeEnvelope(t, w, http.StatusOK, map[string]interface{}{
+			"action":  "upload",
+			"session": map[string]string{"id": "s1", "upload_url": "/api/v1/files/upload/s1"},
+		})
+	})
+	mux.HandleFunc("PUT /api/v1/files/upload/s1", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get(headerEncryptionPassword))
+		assert.Equal(t, int64(size), r.ContentLength)
+
+		h := md5.New() //nolint:gosec // 테스트용 체크섬
+		received, _ = io.Copy(h, r.Body)
+		receivedChecksum = hex.EncodeToString(h.Sum(nil))
+
+		writeEnvelope(t, w, http.StatusCreated, File{ID: 7, OriginalName: "big.bin", Size: size, ChecksumMD5: receivedChecksum})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := newTestClient(t, server)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	file, err := c.UploadFile(context.Background(), &patternReader{remaining: size}, UploadMeta{
+		OriginalName: "big.bin",
+		MimeType:     "application/octet-stream",
+		Size:         size,
+		ChecksumMD5:  checksum,
+	}, "secret")
+
+	runtime.ReadMemStats(&after)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint(7), file.ID)
+	assert.Equal(t, int64(size), received)
+	assert.Equal(t, checksum, receivedChecksum)
+
+	// 본문 전체를 메모리에 모았다면 할당량이 파일 크기를 넘음 (클라이언트와 서버 버퍼 포함)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(size/4))
+}
+
+func TestUploadFile_MeasuresSeekerAndLinks(t *testing.T) {
+	content := []byte("hello datalocker")
+	sum := md5.Sum(content) //nolint:gosec // 테스트용 체크섬
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/files/negotiate", func(w http.ResponseWriter, r *http.Request) {
+		var req negotiateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, int64(len(content)), req.Size)
+		assert.Equal(t, hex.EncodeToString(sum[:]), req.ChecksumMD5)
+		assert.Equal(t, "ext-1", req.ExternalID)
+
+		blob := uint(3)
+		writeEnvelope(t, w, http.StatusCreated, map[string]interface{}{
+			"action": "linked",
+			"file":   File{ID: 9, BlobFileID: &blob, ExternalID: "ext-1"},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	file, err := c.UploadFile(context.Background(), bytes.NewReader(content), UploadMeta{OriginalName: "a.txt", ExternalID: "ext-1"}, "secret")
+	require.NoError(t, err)
+
+	assert.Equal(t, uint(9), file.ID)
+	require.NotNil(t, file.BlobFileID)
+	assert.Equal(t, uint(3), *file.BlobFileID)
+}
+
+func TestUploadFile_AnswersChallenge(t *testing.T) {
+	const blockSize = 4
+	content := []byte("aaaabbbbcc")
+	nonce := []byte("nonce-123")
+
+	blockHash := sha256.Sum256(content[4:8])
+	mac := hmac.New(sha256.New, nonce)
+	mac.Write(blockHash[:])
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/files/negotiate", func(w http.ResponseWriter, r *http.Request) {
+		writeEnvelope(t, w, http.StatusOK, map[string]interface{}{
+			"action": "challenge",
+			"challenge": map[string]interface{}{
+				"id": "c1", "block_index": 1, "block_size": blockSize, "nonce": hex.EncodeToString(nonce),
+			},
+		})
+	})
+	mux.HandleFunc("POST /api/v1/files/negotiate/verify", func(w http.ResponseWriter, r *http.Request) {
+		var req proofRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.ChallengeID != "c1" || req.Response != expected {
+			writeEnvelope(t, w, http.StatusForbidden, nil)
+			return
+		}
+		writeEnvelope(t, w, http.StatusCreated, map[string]interface{}{"action": "linked", "file": File{ID: 11}})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := newTestClient(t, server)
+
+	file, err := c.UploadFile(context.Background(), bytes.NewReader(content), UploadMeta{OriginalName: "a.txt"}, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, uint(11), file.ID)
+
+	// 블록을 다시 읽을 수 없는 reader는 증명할 수 없음
+	sum := md5.Sum(content) //nolint:gosec // 테스트용 체크섬
+	_, err = c.UploadFile(context.Background(), io.MultiReader(bytes.NewReader(content)), UploadMeta{
+		OriginalName: "a.txt",
+		Size:         int64(len(content)),
+		ChecksumMD5:  hex.EncodeToString(sum[:]),
+	}, "secret")
+	assert.ErrorIs(t, err, ErrProofUnavailable)
+}
+
+func TestUploadFile_InvalidInput(t *testing.T) {
+	c, err := New("localhost")
+	require.NoError(t, err)
+
+	_, err = c.UploadFile(context.Background(), bytes.NewReader(nil), UploadMeta{}, "")
+	assert.Error(t, err)
+
+	_, err = c.UploadFile(context.Background(), &patternReader{remaining: 10}, UploadMeta{Size: 10}, "secret")
+	assert.ErrorIs(t, err, ErrUploadMetaRequired)
+}
+
+func TestListFiles(t *testing.T) {
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/admin/files", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer admin-token", r.Header.Get("Authorization"))
+
+		query := r.URL.Query()
+		assert.Equal(t, "encrypted", query.Get("status"))
+		assert.Equal(t, "text/", query.Get("mime"))
+		assert.Equal(t, "ext-1", query.Get("external_id"))
+		assert.Equal(t, "100", query.Get("min_size"))
+		assert.Equal(t, "2026-10-01T00:00:00Z", query.Get("from"))
+		assert.Equal(t, "2", query.Get("page"))
+		assert.Equal(t, "size", query.Get("sort"))
+		assert.False(t, query.Has("max_size"))
+		assert.False(t, query.Has("to"))
+
+		writeEnvelope(t, w, http.StatusOK, map[string]interface{}{
+			"files":     []File{{ID: 1, Status: "encrypted", CreatedAt: created}},
+			"total":     21,
+			"page":      2,
+			"page_size": 20,
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := newTestClient(t, server, WithToken("admin-token"))
+	list, err := c.ListFiles(context.Background(), FileFilter{
+		Status:       "encrypted",
+		MimePrefix:   "text/",
+		ExternalID:   "ext-1",
+		MinSize:      100,
+		CreatedAfter: created,
+		Page:         2,
+		Sort:         "size",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(21), list.Total)
+	assert.Equal(t, 2, list.Page)
+	require.Len(t, list.Files, 1)
+	assert.True(t, list.Files[0].CreatedAt.Equal(created))
+}
+
+func TestAPIError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/admin/files", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			writeEnvelope(t, w, http.StatusUnauthorized, nil)
+			return
+		}
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	_, err := newTestClient(t, server).ListFiles(context.Background(), FileFilter{})
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, "ERROR", apiErr.Code)
+	assert.Equal(t, "req-1", apiErr.RequestID)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	// 표준 응답이 아닌 프록시 에러도 상태 코드로 분류
+	_, err = newTestClient(t, server, WithToken("t")).ListFiles(context.Background(), FileFilter{})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.ErrorIs(t, err, ErrServer)
+
+	// 연결할 수 없는 서버
+	server.Close()
+	_, err = newTestClient(t, server).ListFiles(context.Background(), FileFilter{})
+	assert.ErrorIs(t, err, ErrNetwork)
+}
+
+func TestRetries(t *testing.T) {
+	var listCalls, negotiateCalls, uploadCalls atomic.Int32
+	keys := make(chan string, 3)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/admin/files", func(w http.ResponseWriter, r *http.Request) {
+		if listCalls.Add(1) < 3 {
+			writeEnvelope(t, w, http.StatusServiceUnavailable, nil)
+			return
+		}
+		writeEnvelope(t, w, http.StatusOK, FileList{})
+	})
+	mux.HandleFunc("POST /api/v1/files/negotiate", func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get(headerIdempotencyKey)
+		if negotiateCalls.Add(1) < 2 {
+			writeEnvelope(t, w, http.StatusTooManyRequests, nil)
+			return
+		}
+		writeEnvelope(t, w, http.StatusOK, map[string]interface{}{
+			"action":  "upload",
+			"session": map[string]string{"id": "s1", "upload_url": "/api/v1/files/upload/s1"},
+		})
+	})
+	mux.HandleFunc("PUT /api/v1/files/upload/s1", func(w http.ResponseWriter, r *http.Request) {
+		uploadCalls.Add(1)
+		writeEnvelope(t, w, http.StatusServiceUnavailable, nil)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := newTestClient(t, server)
+
+	_, err := c.ListFiles(context.Background(), FileFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), listCalls.Load())
+
+	// 협상은 같은 Idempotency-Key로 재시도하고, 스트리밍 본문 업로드는 재시도하지 않음
+	_, err = c.UploadFile(context.Background(), bytes.NewReader([]byte("data")), UploadMeta{OriginalName: "a.txt"}, "secret")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, int32(2), negotiateCalls.Load())
+	assert.Equal(t, int32(1), uploadCalls.Load())
+	assert.Equal(t, <-keys, <-keys)
+
+	// 재시도 횟수를 넘으면 마지막 에러를 반환
+	listCalls.Store(-10)
+	_, err = c.ListFiles(context.Background(), FileFilter{})
+	assert.True(t, errors.Is(err, ErrUnavailable))
+	assert.Equal(t, int32(-7), listCalls.Load())
+}
@@ -0,0 +1,321 @@
+// Package client provides a Go client for the DataLocker HTTP API.
+// This file implements the upload negotiation flow and the admin file listing.
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // 서버와 같은 중복 판별용 체크섬
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// API 경로
+const (
+	negotiatePath  = "/api/v1/files/negotiate"
+	verifyPath     = "/api/v1/files/negotiate/verify"
+	adminFilesPath = "/api/v1/admin/files"
+)
+
+// 업로드 협상 결과 동작 (서버의 service.NegotiateAction* 값)
+const (
+	negotiateActionUpload    = "upload"
+	negotiateActionLinked    = "linked"
+	negotiateActionChallenge = "challenge"
+)
+
+// 업로드 에러
+var (
+	// ErrUploadMetaRequired 크기나 체크섬을 알 수 없는데 reader가 io.ReadSeeker가 아닌 경우
+	ErrUploadMetaRequired = errors.New("io.ReadSeeker가 아니면 크기와 MD5 체크섬을 지정해야 합니다")
+
+	// ErrProofUnavailable 서버가 소유 증명을 요구했지만 reader에서 블록을 다시 읽을 수 없는 경우
+	ErrProofUnavailable = errors.New("소유 증명에 필요한 블록을 읽으려면 io.ReadSeeker가 필요합니다")
+)
+
+// File 서버의 파일 레코드
+type File struct {
+	ID            uint      `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	OriginalName  string    `json:"original_name"`
+	Size          int64     `json:"size"`
+	EncryptedSize int64     `json:"encrypted_size"`
+	MimeType      string    `json:"mime_type"`
+	ChecksumMD5   string    `json:"checksum_md5"`
+	Status        string    `json:"status"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	TextEncoding  string    `json:"text_encoding,omitempty"`
+	CreatedBy     string    `json:"created_by"`
+	UpdatedBy     string    `json:"updated_by"`
+	VolumeID      string    `json:"volume_id,omitempty"`
+	ExternalID    string    `json:"external_id,omitempty"`
+	BlobFileID    *uint     `json:"blob_file_id,omitempty"` // 중복 제거로 다른 파일의 암호화본을 참조하면 그 파일 ID
+	Version       uint      `json:"version"`
+
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"` // 마지막 복호화 시각 (없으면 nil)
+}
+
+// UploadMeta 업로드할 파일의 메타데이터
+type UploadMeta struct {
+	OriginalName string
+	MimeType     string
+	ExternalID   string // 외부 시스템 참조 ID (선택)
+
+	// ChecksumMD5를 지정하지 않으면 reader(io.ReadSeeker)를 한 번 읽어 Size와 함께 계산합니다
+	Size        int64
+	ChecksumMD5 string
+}
+
+// FileFilter 파일 목록 조회 조건 (빈 값은 조건 없음)
+type FileFilter struct {
+	Status        string
+	MimePrefix    string
+	NameContains  string
+	ExternalID    string
+	MinSize       int64
+	MaxSize       int64
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	Page     int
+	PageSize int
+	Sort     string // 정렬 필드 (서버 기본값: created_at)
+	Order    string // asc 또는 desc
+}
+
+// FileList 파일 목록 페이지
+type FileList struct {
+	Files    []*File `json:"files"`
+	Total    int64   `json:"total"`
+	Page     int     `json:"page"`
+	PageSize int     `json:"page_size"`
+}
+
+// negotiateRequest 업로드 협상 요청 본문
+type negotiateRequest struct {
+	OriginalName string `json:"original_name"`
+	Size         int64  `json:"size"`
+	MimeType     string `json:"mime_type"`
+	ChecksumMD5  string `json:"checksum_md5"`
+	ExternalID   string `json:"external_id,omitempty"`
+}
+
+// proofRequest 소유 증명 응답 본문
+type proofRequest struct {
+	ChallengeID string `json:"challenge_id"`
+	Response    string `json:"response"`
+}
+
+// negotiateResult 업로드 협상 결과
+type negotiateResult struct {
+	Action    string `json:"action"`
+	File      *File  `json:"file,omitempty"`
+	Challenge *struct {
+		ID         string `json:"id"`
+		BlockIndex int    `json:"block_index"`
+		BlockSize  int    `json:"block_size"`
+		Nonce      string `json:"nonce"`
+	} `json:"challenge,omitempty"`
+	Session *struct {
+		ID        string `json:"id"`
+		UploadURL string `json:"upload_url"`
+	} `json:"session,omitempty"`
+}
+
+// UploadFile 파일을 password로 암호화해 저장하도록 업로드합니다
+//
+// 협상 단계에서 서버에 같은 내용의 파일이 있으면 본문을 보내지 않고 참조
+// 레코드를 만들며, 서버가 소유 증명을 요구하면 요청받은 블록만 다시 읽어
+// 응답합니다. 본문은 메모리에 모으지 않고 reader에서 그대로 스트리밍합니다.
+//
+// 협상 요청은 Idempotency-Key를 붙여 재시도하지만, 본문 업로드는 다시 보낼
+// 수 없으므로 실패하면 UploadFile을 처음부터 다시 호출해야 합니다.
+func (c *Client) UploadFile(ctx context.Context, reader io.Reader, meta UploadMeta, password string) (*File, error) {
+	if password == "" {
+		return nil, errors.New("암호화 패스워드가 필요합니다")
+	}
+
+	if meta.ChecksumMD5 == "" {
+		seeker, ok := reader.(io.ReadSeeker)
+		if !ok {
+			return nil, ErrUploadMetaRequired
+		}
+		size, checksum, err := measure(seeker)
+		if err != nil {
+			return nil, err
+		}
+		meta.Size, meta.ChecksumMD5 = size, checksum
+	}
+
+	result, err := c.negotiate(ctx, negotiatePath, &negotiateRequest{
+		OriginalName: meta.OriginalName,
+		Size:         meta.Size,
+		MimeType:     meta.MimeType,
+		ChecksumMD5:  meta.ChecksumMD5,
+		ExternalID:   meta.ExternalID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Action == negotiateActionChallenge && result.Challenge != nil {
+		seeker, ok := reader.(io.ReadSeeker)
+		if !ok {
+			return nil, ErrProofUnavailable
+		}
+
+		challenge := result.Challenge
+		proof, err := proofResponse(seeker, challenge.BlockIndex, challenge.BlockSize, challenge.Nonce)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err = c.negotiate(ctx, verifyPath, &proofRequest{ChallengeID: challenge.ID, Response: proof})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case result.Action == negotiateActionLinked && result.File != nil:
+		return result.File, nil
+	case result.Action == negotiateActionUpload && result.Session != nil:
+		header := http.Header{}
+		header.Set(headerEncryptionPassword, password)
+
+		var file File
+		err := c.do(ctx, &request{
+			method:        http.MethodPut,
+			path:          result.Session.UploadURL,
+			header:        header,
+			stream:        io.LimitReader(reader, meta.Size),
+			contentLength: meta.Size,
+		}, &file)
+		if err != nil {
+			return nil, err
+		}
+		return &file, nil
+	default:
+		return nil, fmt.Errorf("알 수 없는 업로드 협상 결과입니다: %s", result.Action)
+	}
+}
+
+// negotiate 협상 단계 요청을 Idempotency-Key와 함께 보냅니다
+func (c *Client) negotiate(ctx context.Context, path string, payload interface{}) (*negotiateResult, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("요청 인코딩 실패: %w", err)
+	}
+
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set(headerIdempotencyKey, key)
+
+	var result negotiateResult
+	if err := c.do(ctx, &request{method: http.MethodPost, path: path, header: header, body: body}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// measure reader 전체를 읽어 크기와 MD5를 계산한 뒤 처음으로 되돌립니다
+func measure(reader io.ReadSeeker) (int64, string, error) {
+	h := md5.New() //nolint:gosec // 서버와 같은 중복 판별용 체크섬
+	size, err := io.Copy(h, reader)
+	if err != nil {
+		return 0, "", fmt.Errorf("체크섬 계산 실패: %w", err)
+	}
+
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return 0, "", fmt.Errorf("체크섬 계산 후 되감기 실패: %w", err)
+	}
+
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// proofResponse 챌린지가 지정한 블록의 SHA-256에 nonce로 HMAC-SHA256을 계산합니다
+//
+// 응답 후 업로드할 수 있도록 reader를 처음으로 되돌립니다.
+func proofResponse(reader io.ReadSeeker, blockIndex, blockSize int, nonceHex string) (string, error) {
+	nonce, err := hex.DecodeString(nonceHex)
+	if err != nil {
+		return "", fmt.Errorf("챌린지 nonce 형식이 올바르지 않습니다: %w", err)
+	}
+
+	if _, err := reader.Seek(int64(blockIndex)*int64(blockSize), io.SeekStart); err != nil {
+		return "", fmt.Errorf("소유 증명 블록 탐색 실패: %w", err)
+	}
+
+	blockHash := sha256.New()
+	if _, err := io.CopyN(blockHash, reader, int64(blockSize)); err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("소유 증명 블록 읽기 실패: %w", err)
+	}
+
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("소유 증명 후 되감기 실패: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, nonce)
+	mac.Write(blockHash.Sum(nil))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// ListFiles 조건에 맞는 파일 목록을 조회합니다 (관리 API, WithToken 필요)
+func (c *Client) ListFiles(ctx context.Context, filter FileFilter) (*FileList, error) {
+	query := url.Values{}
+	setQuery(query, "status", filter.Status)
+	setQuery(query, "mime", filter.MimePrefix)
+	setQuery(query, "name", filter.NameContains)
+	setQuery(query, "external_id", filter.ExternalID)
+	setQuery(query, "sort", filter.Sort)
+	setQuery(query, "order", filter.Order)
+	if filter.MinSize > 0 {
+		query.Set("min_size", strconv.FormatInt(filter.MinSize, 10))
+	}
+	if filter.MaxSize > 0 {
+		query.Set("max_size", strconv.FormatInt(filter.MaxSize, 10))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query.Set("from", filter.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query.Set("to", filter.CreatedBefore.UTC().Format(time.RFC3339))
+	}
+	if filter.Page > 0 {
+		query.Set("page", strconv.Itoa(filter.Page))
+	}
+	if filter.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(filter.PageSize))
+	}
+
+	path := adminFilesPath
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var list FileList
+	if err := c.do(ctx, &request{method: http.MethodGet, path: path}, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// setQuery 값이 있을 때만 쿼리 파라미터를 설정합니다
+func setQuery(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
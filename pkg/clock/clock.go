@@ -0,0 +1,57 @@
+// Package clock provides an injectable time source for DataLocker.
+// Services read the time and wait through Clock so that tests can drive expiry and schedules with a fake clock.
+package clock
+
+import "time"
+
+// Clock 현재 시각과 타이머를 제공하는 시간 원천
+type Clock interface {
+	// Now 현재 시각을 반환합니다
+	Now() time.Time
+
+	// After d가 지나면 그 시각을 한 번 보내는 채널을 반환합니다
+	After(d time.Duration) <-chan time.Time
+
+	// NewTimer d가 지나면 C로 시각을 한 번 보내는 타이머를 생성합니다
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer Clock이 만든 일회성 타이머 (time.Timer와 같은 의미)
+type Timer interface {
+	// C 만료 시각을 받는 채널
+	C() <-chan time.Time
+
+	// Stop 타이머를 멈추고, 만료 전에 멈췄으면 true를 반환합니다
+	Stop() bool
+
+	// Reset 타이머를 d 뒤에 다시 만료되도록 하고, 만료 전이었으면 true를 반환합니다
+	Reset(d time.Duration) bool
+}
+
+// Real 시스템 시계 (time 패키지를 그대로 사용)
+type Real struct{}
+
+// Now time.Now를 반환합니다
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// After time.After와 같습니다
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewTimer time.NewTimer를 감싼 타이머를 생성합니다
+func (Real) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// realTimer time.Timer를 Timer로 감싼 것
+type realTimer struct {
+	*time.Timer
+}
+
+// C 만료 시각을 받는 채널
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
@@ -0,0 +1,106 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStart = time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// received 채널에 이미 도착한 값을 꺼냅니다 (없으면 false)
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case v := <-c:
+		return v, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestReal(t *testing.T) {
+	var c Clock = Real{}
+	assert.WithinDuration(t, time.Now(), c.Now(), time.Second)
+
+	timer := c.NewTimer(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("타이머가 만료되지 않음")
+	}
+	assert.False(t, timer.Stop(), "이미 만료된 타이머")
+
+	timer = c.NewTimer(time.Hour)
+	assert.True(t, timer.Stop())
+}
+
+func TestFake_AdvanceFiresInOrder(t *testing.T) {
+	f := NewFake(testStart)
+	later := f.NewTimer(2 * time.Minute)
+	sooner := f.After(time.Minute)
+	same := f.NewTimer(2 * time.Minute)
+	assert.Equal(t, 3, f.Timers())
+
+	f.Advance(59 * time.Second)
+	_, ok := received(sooner)
+	assert.False(t, ok, "만료 전")
+	assert.Equal(t, testStart.Add(59*time.Second), f.Now())
+
+	f.Advance(2 * time.Minute)
+	at, ok := received(sooner)
+	require.True(t, ok)
+	assert.Equal(t, testStart.Add(time.Minute), at, "만료 시각을 그대로 보냄")
+	at, ok = received(later.C())
+	require.True(t, ok)
+	assert.Equal(t, testStart.Add(2*time.Minute), at)
+	_, ok = received(same.C())
+	assert.True(t, ok)
+	assert.Equal(t, testStart.Add(179*time.Second), f.Now())
+	assert.Zero(t, f.Timers())
+
+	// 0 이하이면 바로 만료
+	_, ok = received(f.After(0))
+	assert.True(t, ok)
+}
+
+func TestFake_StopAndReset(t *testing.T) {
+	f := NewFake(testStart)
+	timer := f.NewTimer(time.Minute)
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+	f.Advance(time.Hour)
+	_, ok := received(timer.C())
+	assert.False(t, ok, "멈춘 타이머는 발화하지 않음")
+
+	assert.False(t, timer.Reset(time.Minute), "이미 멈춘 타이머")
+	assert.True(t, timer.Reset(2*time.Minute), "다시 건 타이머는 대기 중")
+	f.Advance(time.Minute)
+	_, ok = received(timer.C())
+	assert.False(t, ok, "Reset 시점부터 다시 셈")
+	f.Advance(time.Minute)
+	_, ok = received(timer.C())
+	assert.True(t, ok)
+
+	// 과거로 옮기면 시각만 바뀜
+	f.Set(testStart)
+	assert.Equal(t, testStart, f.Now())
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	f := NewFake(testStart)
+	done := make(chan time.Time)
+	go func() {
+		done <- <-f.After(time.Hour)
+	}()
+
+	f.BlockUntil(1)
+	f.Advance(time.Hour)
+	select {
+	case at := <-done:
+		assert.Equal(t, testStart.Add(time.Hour), at)
+	case <-time.After(time.Second):
+		t.Fatal("고루틴이 깨어나지 않음")
+	}
+}
@@ -0,0 +1,164 @@
+// Package clock provides an injectable time source for DataLocker.
+// This file implements the fake clock that tests advance by hand.
+package clock
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Fake 직접 움직이기 전까지 멈춰 있는 테스트용 시계
+//
+// Advance나 Set으로 시각을 옮기면 그 시각까지 만료된 타이머가 만료 순서대로
+// 발화합니다. 타이머 채널은 버퍼가 1이므로 받는 쪽이 없어도 Advance는 막히지
+// 않습니다. 다른 고루틴이 타이머를 만들 때까지 기다리려면 BlockUntil을 사용합니다.
+type Fake struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer // 대기 중인 타이머
+}
+
+// NewFake now에 멈춘 가짜 시계를 생성합니다
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now 가짜 시계의 현재 시각을 반환합니다
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After 가짜 시계가 d만큼 움직이면 시각을 보내는 채널을 반환합니다
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer 가짜 시계가 d만큼 움직이면 만료되는 타이머를 생성합니다 (d가 0 이하이면 바로 만료)
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: f, c: make(chan time.Time, 1)}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.schedule(t, d)
+	return t
+}
+
+// Advance 시각을 d만큼 앞으로 옮기고 그 사이 만료된 타이머를 발화합니다
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.moveTo(f.now.Add(d))
+}
+
+// Set 시각을 now로 옮기고 그때까지 만료된 타이머를 발화합니다 (과거로 옮기면 발화 없음)
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.moveTo(now)
+}
+
+// Timers 대기 중인 타이머 수를 반환합니다
+func (f *Fake) Timers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.timers)
+}
+
+// BlockUntil 대기 중인 타이머가 n개 이상이 될 때까지 기다립니다
+//
+// 스케줄러 고루틴이 다음 타이머를 건 뒤에 Advance해야 발화를 놓치지 않습니다.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.timers) < n {
+		f.cond.Wait()
+	}
+}
+
+// moveTo 시각을 옮기며 만료된 타이머를 만료 순서대로 발화합니다 (f.mu를 잡은 상태)
+//
+// 타이머마다 시각을 그 만료 시각으로 맞춰 보내므로 받는 쪽은 정확한 만료 시각을 받습니다.
+func (f *Fake) moveTo(now time.Time) {
+	for len(f.timers) > 0 && !f.timers[0].deadline.After(now) {
+		t := f.timers[0]
+		f.timers = f.timers[1:]
+		if t.deadline.After(f.now) {
+			f.now = t.deadline
+		}
+		t.fire(f.now)
+	}
+
+	f.now = now
+	f.cond.Broadcast()
+}
+
+// schedule 타이머를 d 뒤에 만료되도록 대기열에 넣습니다 (f.mu를 잡은 상태)
+func (f *Fake) schedule(t *fakeTimer, d time.Duration) {
+	t.deadline = f.now.Add(d)
+	if d <= 0 {
+		t.fire(f.now)
+		return
+	}
+
+	i, _ := slices.BinarySearchFunc(f.timers, t.deadline, func(e *fakeTimer, deadline time.Time) int {
+		if e.deadline.After(deadline) {
+			return 1
+		}
+		return -1 // 만료 시각이 같으면 먼저 건 타이머가 먼저 발화
+	})
+	f.timers = slices.Insert(f.timers, i, t)
+	f.cond.Broadcast()
+}
+
+// unschedule 대기열에서 타이머를 빼고, 대기 중이었으면 true를 반환합니다 (f.mu를 잡은 상태)
+func (f *Fake) unschedule(t *fakeTimer) bool {
+	i := slices.Index(f.timers, t)
+	if i < 0 {
+		return false
+	}
+	f.timers = slices.Delete(f.timers, i, i+1)
+	f.cond.Broadcast()
+	return true
+}
+
+// fakeTimer 가짜 시계의 타이머
+type fakeTimer struct {
+	clock    *Fake
+	c        chan time.Time
+	deadline time.Time
+}
+
+// C 만료 시각을 받는 채널
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop 대기열에서 빼고, 만료 전이었으면 true를 반환합니다
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.unschedule(t)
+}
+
+// Reset 지금부터 d 뒤에 다시 만료되도록 하고, 만료 전이었으면 true를 반환합니다
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.clock.unschedule(t)
+	t.clock.schedule(t, d)
+	return active
+}
+
+// fire 채널에 시각을 보냅니다 (이전 값을 아무도 받지 않았으면 버림)
+func (t *fakeTimer) fire(now time.Time) {
+	select {
+	case t.c <- now:
+	default:
+	}
+}
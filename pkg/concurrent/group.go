@@ -0,0 +1,134 @@
+// Package concurrent provides structured concurrency helpers for DataLocker.
+// It wraps errgroup so that every failure of a parallel job reaches the caller.
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Mode 작업 실패 시 나머지 작업의 처리 방식
+type Mode int
+
+const (
+	// FailFast 첫 에러에서 context를 취소하고 아직 시작하지 않은 작업을 건너뜁니다
+	FailFast Mode = iota
+
+	// CollectAll 에러와 관계없이 모든 작업을 수행합니다
+	CollectAll
+)
+
+// String 모드 이름을 반환합니다
+func (m Mode) String() string {
+	switch m {
+	case FailFast:
+		return "fail-fast"
+	case CollectAll:
+		return "collect-all"
+	default:
+		return "unknown"
+	}
+}
+
+// Group 병렬 작업을 실행하고 모든 에러를 모으는 작업 그룹
+//
+// errgroup.Group은 첫 에러만 반환하지만, Group은 실패한 작업의 에러를 모두
+// errors.Join으로 묶어 반환합니다. FailFast 모드에서 취소로 인해 발생한
+// context.Canceled 에러는 원인 에러가 아니므로 목록에서 제외합니다.
+type Group struct {
+	eg     *errgroup.Group
+	ctx    context.Context
+	cancel context.CancelFunc
+	mode   Mode
+
+	mu     sync.Mutex
+	errs   []error
+	failed bool
+}
+
+// NewGroup 작업 그룹과 작업에 전달할 context를 생성합니다
+//
+// limit가 0보다 크면 동시에 실행되는 작업 수를 limit개로 제한합니다.
+func NewGroup(ctx context.Context, mode Mode, limit int) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	eg := &errgroup.Group{}
+	if limit > 0 {
+		eg.SetLimit(limit)
+	}
+
+	g := &Group{eg: eg, ctx: ctx, cancel: cancel, mode: mode}
+	return g, ctx
+}
+
+// Go 작업을 시작합니다
+//
+// FailFast 모드에서 이미 실패한 그룹이면 작업을 실행하지 않습니다.
+func (g *Group) Go(task func(ctx context.Context) error) {
+	g.eg.Go(func() error {
+		if g.mode == FailFast && g.ctx.Err() != nil {
+			return nil
+		}
+
+		if err := task(g.ctx); err != nil {
+			g.record(err)
+		}
+		return nil
+	})
+}
+
+// Wait 모든 작업이 끝날 때까지 기다리고 모은 에러를 반환합니다
+//
+// 실패한 작업이 없으면 nil을 반환합니다. 반환된 에러는 Errors로 펼칠 수 있습니다.
+func (g *Group) Wait() error {
+	_ = g.eg.Wait()
+	g.cancel()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
+
+// record 작업 에러를 기록하고, FailFast 모드면 나머지 작업을 취소합니다
+func (g *Group) record(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// 그룹이 취소한 뒤 발생한 취소 에러는 원인 에러가 아님
+	if g.failed && g.mode == FailFast && errors.Is(err, context.Canceled) {
+		return
+	}
+
+	g.errs = append(g.errs, err)
+	if g.mode == FailFast && !g.failed {
+		g.failed = true
+		g.cancel()
+	}
+}
+
+// ForEach items의 각 항목에 task를 병렬로 실행하고 모든 에러를 모아 반환합니다
+func ForEach[T any](ctx context.Context, items []T, mode Mode, limit int, task func(ctx context.Context, index int, item T) error) error {
+	g, _ := NewGroup(ctx, mode, limit)
+	for i, item := range items {
+		g.Go(func(ctx context.Context) error {
+			return task(ctx, i, item)
+		})
+	}
+	return g.Wait()
+}
+
+// Errors Wait 또는 ForEach가 반환한 에러를 개별 에러 목록으로 펼칩니다
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
@@ -0,0 +1,97 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEach_CollectAll(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6}
+	var ran atomic.Int32
+
+	err := ForEach(context.Background(), items, CollectAll, 2, func(_ context.Context, _ int, item int) error {
+		ran.Add(1)
+		if item%2 == 0 {
+			return fmt.Errorf("항목 %d 실패", item)
+		}
+		return nil
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, int32(len(items)), ran.Load(), "모든 작업이 수행되어야 함")
+
+	errs := Errors(err)
+	assert.Len(t, errs, 3, "부분 실패가 모두 전달되어야 함")
+	for _, item := range []int{2, 4, 6} {
+		assert.Contains(t, err.Error(), fmt.Sprintf("항목 %d 실패", item))
+	}
+}
+
+func TestForEach_FailFast(t *testing.T) {
+	errBoom := errors.New("boom")
+	items := make([]int, 100)
+	var ran atomic.Int32
+
+	// 동시 실행 1개: 첫 작업 실패 후 나머지는 시작하지 않아야 함
+	err := ForEach(context.Background(), items, FailFast, 1, func(ctx context.Context, i int, _ int) error {
+		ran.Add(1)
+		if i == 0 {
+			return errBoom
+		}
+		return ctx.Err()
+	})
+
+	require.ErrorIs(t, err, errBoom)
+	assert.Len(t, Errors(err), 1)
+	assert.Less(t, ran.Load(), int32(len(items)))
+}
+
+func TestGroup_FailFastCancelsRunningTasks(t *testing.T) {
+	errBoom := errors.New("boom")
+	g, _ := NewGroup(context.Background(), FailFast, 0)
+
+	for i := 0; i < 3; i++ {
+		g.Go(func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return errors.New("취소되지 않음")
+			}
+		})
+	}
+	g.Go(func(context.Context) error { return errBoom })
+
+	err := g.Wait()
+	require.ErrorIs(t, err, errBoom)
+	assert.NotErrorIs(t, err, context.Canceled, "그룹 취소로 인한 에러는 제외되어야 함")
+}
+
+func TestGroup_NoErrors(t *testing.T) {
+	for _, mode := range []Mode{FailFast, CollectAll} {
+		g, _ := NewGroup(context.Background(), mode, 0)
+		g.Go(func(context.Context) error { return nil })
+		assert.NoError(t, g.Wait(), mode.String())
+	}
+
+	assert.Nil(t, Errors(nil))
+}
+
+func TestGroup_ParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// 부모 context 취소는 그룹이 일으킨 취소가 아니므로 CollectAll에서 그대로 전달
+	err := ForEach(ctx, []int{1, 2}, CollectAll, 0, func(ctx context.Context, _ int, _ int) error {
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, Errors(err), 2)
+}
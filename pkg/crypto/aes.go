@@ -9,7 +9,6 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
-	"io"
 
 	"golang.org/x/crypto/pbkdf2"
 )
@@ -34,35 +33,129 @@ const (
 	// 청크 크기 정보를 저장할 바이트 수
 	ChunkSizeBytes = 4
 
-	// 비트 시프트 상수
-	BitShift8  = 8
-	BitShift16 = 16
-	BitShift24 = 24
-
 	// 최대 청크 크기 (4GB)
 	MaxChunkSize = 1<<32 - 1
+
+	// GCM 인증 태그 크기 (16 바이트)
+	GCMTagSize = 16
+
+	// 파일당 최대 키 슬롯(패스워드) 수
+	MaxKeySlots = 8
+
+	// 감싸진 데이터 키 크기 (nonce + 키 + 인증 태그)
+	WrappedKeySize = NonceSize + KeySize + GCMTagSize
+
+	// 키 슬롯에 허용되는 최대 반복 횟수 (악의적 헤더로 인한 과도한 연산 방지)
+	MaxSlotIterations = 10000000
+)
+
+// EncryptedData 포맷 버전
+const (
+	// DataFormatRawKey 유도한 키(또는 데이터 키)를 그대로 AES-GCM 키로 쓰는 초기 포맷
+	DataFormatRawKey byte = 0
+
+	// DataFormatSubKeys HKDF로 분리한 암호화 서브키를 쓰는 포맷
+	DataFormatSubKeys byte = 1
+
+	// CurrentDataFormat 새로 암호화할 때 사용하는 포맷
+	CurrentDataFormat = DataFormatSubKeys
+)
+
+var (
+	// ErrUnsupportedDataFormat 지원하지 않는 EncryptedData 포맷 버전
+	ErrUnsupportedDataFormat = errors.New("지원하지 않는 암호화 데이터 포맷 버전입니다")
+
+	// ErrPayloadTooLarge 메모리 암호화 한도를 넘는 평문 (EncryptStream 사용 필요)
+	ErrPayloadTooLarge = errors.New("메모리 암호화 한도를 넘는 데이터입니다")
 )
 
 // CryptoEngine AES 암복호화 엔진
 type CryptoEngine struct {
-	// 추후 확장을 위한 구조체
+	options CryptoOptions // 암호화 호출의 기본 옵션 (호출별 옵션이 덮어씀)
+	err     error         // 생성 시 잘못된 옵션 (암호화 호출마다 반환)
 }
 
 // NewCryptoEngine 새로운 암호화 엔진을 생성합니다
-func NewCryptoEngine() *CryptoEngine {
-	return &CryptoEngine{}
+//
+// 옵션은 이 엔진으로 하는 모든 암호화의 기본값이 되며, EncryptWithOptions 등에
+// 넘긴 호출별 옵션이 그 위에 적용됩니다. 옵션이 없으면 기존과 같은 기본값을
+// 사용합니다. 각 옵션의 적용 범위는 다음과 같습니다:
+//
+//   - WithChunkSize, WithCompression: 스트림 암호화에만 적용
+//   - WithMaxInMemoryEncryptSize: 메모리 암호화(Encrypt)에만 적용
+//   - WithIterations: 키 슬롯(스트림, 다중 패스워드 Encrypt)에만 적용. 단일
+//     패스워드 Encrypt는 반복 횟수를 기록하지 않으므로 PBKDF2Iterations를 사용
+//   - WithAlgorithm: 현재는 AES-256-GCM만 지원
+//
+// 잘못된 옵션의 에러는 모두 모아 ErrInvalidOption으로 감싸며, Err로 확인할 수
+// 있고 이후 암호화 호출에서도 그대로 반환됩니다. 복호화는 데이터에 기록된
+// 설정을 따르므로 옵션과 관계없이 동작합니다.
+func NewCryptoEngine(opts ...Option) *CryptoEngine {
+	ce := &CryptoEngine{options: defaultCryptoOptions()}
+
+	var errs []error
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(&ce.options); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		ce.err = fmt.Errorf("%w: %w", ErrInvalidOption, errors.Join(errs...))
+	}
+
+	return ce
+}
+
+// Err 엔진 생성 시 잘못된 옵션이 있었으면 에러를 반환합니다
+func (ce *CryptoEngine) Err() error {
+	return ce.err
+}
+
+// callOptions 엔진 옵션 위에 호출별 옵션을 적용합니다
+func (ce *CryptoEngine) callOptions(opts []Option) (CryptoOptions, error) {
+	if ce.err != nil {
+		return CryptoOptions{}, ce.err
+	}
+	return applyOptionsTo(ce.options, opts)
 }
 
 // EncryptedData 암호화된 데이터 구조체
+//
+// KeySlots가 비어 있으면 Salt로 유도한 키로 직접 암호화된 데이터이고,
+// KeySlots가 있으면 각 슬롯에 감싸진 데이터 키(DEK)로 암호화된 데이터입니다.
+// Version이 DataFormatSubKeys 이상이면 해당 키에서 HKDF로 유도한 암호화 서브키를
+// 사용하고, DataFormatRawKey(0)인 기존 데이터는 키를 그대로 사용합니다.
+// JSON으로는 바이트 필드가 표준 base64 문자열로 변환됩니다 (json.go 참고).
 type EncryptedData struct {
-	Salt       []byte `json:"salt"`       // PBKDF2 Salt
-	Nonce      []byte `json:"nonce"`      // GCM Nonce
-	Ciphertext []byte `json:"ciphertext"` // 암호화된 데이터
+	Version    byte       `json:"version,omitempty"`   // 포맷 버전 (DataFormat*)
+	Salt       []byte     `json:"salt"`                // PBKDF2 Salt (단일 패스워드)
+	Nonce      []byte     `json:"nonce"`               // GCM Nonce
+	Ciphertext []byte     `json:"ciphertext"`          // 암호화된 데이터
+	KeySlots   []*KeySlot `json:"key_slots,omitempty"` // 패스워드별 키 슬롯 (다중 패스워드)
 }
 
 // DeriveKey PBKDF2를 사용하여 패스워드에서 키를 유도합니다
 func (ce *CryptoEngine) DeriveKey(password string, salt []byte) []byte {
-	return pbkdf2.Key([]byte(password), salt, PBKDF2Iterations, KeySize, sha256.New)
+	secret := []byte(password)
+	defer ZeroBytes(secret)
+
+	return ce.deriveKeyWithIterations(secret, salt, PBKDF2Iterations)
+}
+
+// DeriveKeyBytes 바이트 패스워드에서 키를 유도합니다
+//
+// DeriveKey와 같은 키를 만들지만 패스워드를 복사하지 않습니다. 패스워드는
+// 호출자가 소유하므로 사용이 끝나면 ZeroBytes로 지워야 합니다.
+func (ce *CryptoEngine) DeriveKeyBytes(password, salt []byte) []byte {
+	return ce.deriveKeyWithIterations(password, salt, PBKDF2Iterations)
+}
+
+// deriveKeyWithIterations 지정한 반복 횟수로 키를 유도합니다
+func (ce *CryptoEngine) deriveKeyWithIterations(password, salt []byte, iterations int) []byte {
+	return pbkdf2.Key(password, salt, iterations, KeySize, sha256.New)
 }
 
 // GenerateSalt 새로운 랜덤 Salt를 생성합니다
@@ -86,13 +179,38 @@ func (ce *CryptoEngine) GenerateNonce() ([]byte, error) {
 }
 
 // Encrypt 데이터를 AES-256-GCM으로 암호화합니다
-func (ce *CryptoEngine) Encrypt(plaintext []byte, password string) (*EncryptedData, error) {
+//
+// 패스워드가 하나면 패스워드에서 유도한 키로 직접 암호화합니다.
+// 패스워드가 여러 개면 랜덤 데이터 키로 암호화하고, 패스워드마다 데이터 키를
+// 감싼 키 슬롯을 만들어 어느 패스워드로든 복호화할 수 있게 합니다.
+// 메모리 암호화 한도(기본값: DefaultMaxInMemoryEncryptSize)보다 큰 데이터는 ErrPayloadTooLarge를 반환합니다.
+func (ce *CryptoEngine) Encrypt(plaintext []byte, passwords ...string) (*EncryptedData, error) {
+	return ce.EncryptWithOptions(plaintext, passwords)
+}
+
+// EncryptWithOptions 옵션(메모리 암호화 한도 등)을 적용해 데이터를 암호화합니다
+func (ce *CryptoEngine) EncryptWithOptions(plaintext []byte, passwords []string, opts ...Option) (*EncryptedData, error) {
 	if len(plaintext) == 0 {
 		return nil, errors.New("빈 데이터는 암호화할 수 없습니다")
 	}
 
-	if password == "" {
-		return nil, errors.New("패스워드가 필요합니다")
+	options, err := ce.callOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// GCM Seal이 평문 크기만큼 새 버퍼를 만들므로 큰 데이터는 스트림으로 유도
+	if len(plaintext) > options.MaxInMemoryEncryptSize {
+		return nil, fmt.Errorf("%w: %d bytes (한도: %d bytes), 큰 데이터는 EncryptStream을 사용하세요",
+			ErrPayloadTooLarge, len(plaintext), options.MaxInMemoryEncryptSize)
+	}
+
+	if err := validatePasswords(passwords); err != nil {
+		return nil, err
+	}
+
+	if len(passwords) > 1 {
+		return ce.encryptWithKeySlots(plaintext, passwords, options.Iterations)
 	}
 
 	// Salt 생성
@@ -101,37 +219,75 @@ func (ce *CryptoEngine) Encrypt(plaintext []byte, password string) (*EncryptedDa
 		return nil, fmt.Errorf("salt 생성 실패: %w", err)
 	}
 
-	// 키 유도
-	key := ce.DeriveKey(password, salt)
+	// 키 유도 (PBKDF2 출력을 마스터 키로 암호화 서브키 유도)
+	masterKey := ce.DeriveKey(passwords[0], salt)
+	defer ZeroBytes(masterKey)
 
-	// AES 블록 암호 생성
-	block, err := aes.NewCipher(key)
+	key := deriveEncryptionKey(masterKey)
+	defer ZeroBytes(key)
+
+	nonce, ciphertext, err := ce.seal(key, plaintext)
 	if err != nil {
-		return nil, fmt.Errorf("AES 암호 생성 실패: %w", err)
+		return nil, err
 	}
 
-	// GCM 모드 생성
-	gcm, err := cipher.NewGCM(block)
+	return &EncryptedData{
+		Version:    CurrentDataFormat,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+// encryptWithKeySlots 데이터 키로 암호화하고 패스워드별 키 슬롯을 생성합니다
+func (ce *CryptoEngine) encryptWithKeySlots(plaintext []byte, passwords []string, iterations int) (*EncryptedData, error) {
+	dataKey, err := ce.GenerateDataKey()
 	if err != nil {
-		return nil, fmt.Errorf("GCM 모드 생성 실패: %w", err)
+		return nil, err
 	}
+	defer ZeroBytes(dataKey)
 
-	// Nonce 생성
-	nonce, err := ce.GenerateNonce()
+	secrets := passwordBytes(passwords)
+	defer zeroPasswords(secrets)
+
+	slots, err := ce.newKeySlots(dataKey, secrets, iterations)
 	if err != nil {
-		return nil, fmt.Errorf("nonce 생성 실패: %w", err)
+		return nil, err
 	}
 
-	// 암호화 수행
-	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+	key := deriveEncryptionKey(dataKey)
+	defer ZeroBytes(key)
+
+	nonce, ciphertext, err := ce.seal(key, plaintext)
+	if err != nil {
+		return nil, err
+	}
 
 	return &EncryptedData{
-		Salt:       salt,
+		Version:    CurrentDataFormat,
 		Nonce:      nonce,
 		Ciphertext: ciphertext,
+		KeySlots:   slots,
 	}, nil
 }
 
+// seal 새 nonce를 생성하여 데이터를 암호화합니다
+func (ce *CryptoEngine) seal(key, plaintext []byte) ([]byte, []byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Nonce 생성
+	nonce, err := ce.GenerateNonce()
+	if err != nil {
+		return nil, nil, fmt.Errorf("nonce 생성 실패: %w", err)
+	}
+
+	// 암호화 수행
+	return nonce, gcm.Seal(nil, nonce, plaintext, nil), nil
+}
+
 // Decrypt AES-256-GCM으로 암호화된 데이터를 복호화합니다
 func (ce *CryptoEngine) Decrypt(encData *EncryptedData, password string) ([]byte, error) {
 	if encData == nil {
@@ -143,7 +299,11 @@ func (ce *CryptoEngine) Decrypt(encData *EncryptedData, password string) ([]byte
 	}
 
 	// 데이터 유효성 검사
-	if len(encData.Salt) != SaltSize {
+	if encData.Version > CurrentDataFormat {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedDataFormat, encData.Version)
+	}
+
+	if len(encData.KeySlots) == 0 && len(encData.Salt) != SaltSize {
 		return nil, fmt.Errorf("잘못된 salt 크기: %d (예상: %d)", len(encData.Salt), SaltSize)
 	}
 
@@ -155,19 +315,29 @@ func (ce *CryptoEngine) Decrypt(encData *EncryptedData, password string) ([]byte
 		return nil, errors.New("암호화된 데이터가 비어있습니다")
 	}
 
-	// 키 유도
-	key := ce.DeriveKey(password, encData.Salt)
+	// 키 유도 (키 슬롯이 있으면 슬롯에서 데이터 키를 꺼냄)
+	var key []byte
+	if len(encData.KeySlots) > 0 {
+		dataKey, err := ce.OpenKeySlots(encData.KeySlots, password)
+		if err != nil {
+			return nil, err
+		}
+		key = dataKey
+	} else {
+		key = ce.DeriveKey(password, encData.Salt)
+	}
+	defer ZeroBytes(key)
 
-	// AES 블록 암호 생성
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, fmt.Errorf("AES 암호 생성 실패: %w", err)
+	// 서브키 포맷이면 암호화 서브키 사용 (기존 포맷은 키를 그대로 사용)
+	if encData.Version >= DataFormatSubKeys {
+		encKey := deriveEncryptionKey(key)
+		defer ZeroBytes(encKey)
+		key = encKey
 	}
 
-	// GCM 모드 생성
-	gcm, err := cipher.NewGCM(block)
+	gcm, err := newGCM(key)
 	if err != nil {
-		return nil, fmt.Errorf("GCM 모드 생성 실패: %w", err)
+		return nil, err
 	}
 
 	// 복호화 수행
@@ -179,160 +349,61 @@ func (ce *CryptoEngine) Decrypt(encData *EncryptedData, password string) ([]byte
 	return plaintext, nil
 }
 
-// EncryptStream 스트림 방식으로 대용량 데이터를 암호화합니다
-func (ce *CryptoEngine) EncryptStream(reader io.Reader, writer io.Writer, password string) error {
-	if password == "" {
-		return errors.New("패스워드가 필요합니다")
-	}
-
-	// Salt 생성 및 저장
-	salt, err := ce.GenerateSalt()
-	if err != nil {
-		return fmt.Errorf("salt 생성 실패: %w", err)
-	}
-
-	// Salt를 파일 시작 부분에 저장
-	if _, writeErr := writer.Write(salt); writeErr != nil {
-		return fmt.Errorf("salt 저장 실패: %w", writeErr)
-	}
-
-	// 키 유도
-	key := ce.DeriveKey(password, salt)
-
+// newGCM 키로 AES-256-GCM AEAD를 생성합니다
+func newGCM(key []byte) (cipher.AEAD, error) {
 	// AES 블록 암호 생성
 	block, err := aes.NewCipher(key)
 	if err != nil {
-		return fmt.Errorf("AES 암호 생성 실패: %w", err)
+		return nil, fmt.Errorf("AES 암호 생성 실패: %w", err)
 	}
 
 	// GCM 모드 생성
 	gcm, err := cipher.NewGCM(block)
 	if err != nil {
-		return fmt.Errorf("GCM 모드 생성 실패: %w", err)
+		return nil, fmt.Errorf("GCM 모드 생성 실패: %w", err)
 	}
 
-	// 청크 단위로 암호화
-	buffer := make([]byte, ChunkSize)
-	for {
-		n, readErr := reader.Read(buffer)
-		if readErr == io.EOF {
-			break
-		}
-		if readErr != nil {
-			return fmt.Errorf("데이터 읽기 실패: %w", readErr)
-		}
-
-		// 각 청크마다 새로운 nonce 생성
-		nonce, nonceErr := ce.GenerateNonce()
-		if nonceErr != nil {
-			return fmt.Errorf("nonce 생성 실패: %w", nonceErr)
-		}
-
-		// Nonce 저장
-		if _, writeErr := writer.Write(nonce); writeErr != nil {
-			return fmt.Errorf("nonce 저장 실패: %w", writeErr)
-		}
-
-		// 청크 암호화
-		chunk := buffer[:n]
-		ciphertext := gcm.Seal(nil, nonce, chunk, nil)
+	return gcm, nil
+}
 
-		// 암호화된 청크 크기 검증 및 저장
-		ciphertextLen := len(ciphertext)
-		if ciphertextLen > MaxChunkSize {
-			return fmt.Errorf("청크 크기가 너무 큽니다: %d bytes", ciphertextLen)
-		}
+// validatePasswords 패스워드 목록을 검증합니다
+func validatePasswords[P string | []byte](passwords []P) error {
+	if len(passwords) == 0 {
+		return errors.New("패스워드가 필요합니다")
+	}
 
-		chunkSize := uint32(ciphertextLen)
-		sizeBytes := []byte{
-			byte(chunkSize >> BitShift24),
-			byte(chunkSize >> BitShift16),
-			byte(chunkSize >> BitShift8),
-			byte(chunkSize),
-		}
-		if _, writeErr := writer.Write(sizeBytes); writeErr != nil {
-			return fmt.Errorf("청크 크기 저장 실패: %w", writeErr)
-		}
+	if len(passwords) > MaxKeySlots {
+		return fmt.Errorf("패스워드는 최대 %d개까지 지정할 수 있습니다", MaxKeySlots)
+	}
 
-		// 암호화된 데이터 저장
-		if _, writeErr := writer.Write(ciphertext); writeErr != nil {
-			return fmt.Errorf("암호화된 데이터 저장 실패: %w", writeErr)
+	for _, password := range passwords {
+		if len(password) == 0 {
+			return errors.New("패스워드가 필요합니다")
 		}
 	}
 
 	return nil
 }
 
-// DecryptStream 스트림 방식으로 대용량 데이터를 복호화합니다
-func (ce *CryptoEngine) DecryptStream(reader io.Reader, writer io.Writer, password string) error {
-	if password == "" {
-		return errors.New("패스워드가 필요합니다")
-	}
-
-	// Salt 읽기
-	salt := make([]byte, SaltSize)
-	if _, err := io.ReadFull(reader, salt); err != nil {
-		return fmt.Errorf("salt 읽기 실패: %w", err)
+// passwordBytes 문자열 패스워드를 바이트로 복사합니다 (사용 후 zeroPasswords로 지움)
+func passwordBytes(passwords []string) [][]byte {
+	secrets := make([][]byte, len(passwords))
+	for i, password := range passwords {
+		secrets[i] = []byte(password)
 	}
+	return secrets
+}
 
-	// 키 유도
-	key := ce.DeriveKey(password, salt)
-
-	// AES 블록 암호 생성
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return fmt.Errorf("AES 암호 생성 실패: %w", err)
+// zeroPasswords 바이트 패스워드를 모두 0으로 덮어씁니다
+func zeroPasswords(secrets [][]byte) {
+	for _, secret := range secrets {
+		ZeroBytes(secret)
 	}
+}
 
-	// GCM 모드 생성
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return fmt.Errorf("GCM 모드 생성 실패: %w", err)
-	}
-
-	// 청크 단위로 복호화
-	for {
-		// Nonce 읽기
-		nonce := make([]byte, NonceSize)
-		n, readErr := reader.Read(nonce)
-		if readErr == io.EOF {
-			break
-		}
-		if readErr != nil {
-			return fmt.Errorf("nonce 읽기 실패: %w", readErr)
-		}
-		if n != NonceSize {
-			return fmt.Errorf("잘못된 nonce 크기: %d", n)
-		}
-
-		// 청크 크기 읽기
-		sizeBytes := make([]byte, ChunkSizeBytes)
-		if _, readFullErr := io.ReadFull(reader, sizeBytes); readFullErr != nil {
-			return fmt.Errorf("청크 크기 읽기 실패: %w", readFullErr)
-		}
-
-		chunkSize := uint32(sizeBytes[0])<<BitShift24 |
-			uint32(sizeBytes[1])<<BitShift16 |
-			uint32(sizeBytes[2])<<BitShift8 |
-			uint32(sizeBytes[3])
-
-		// 암호화된 데이터 읽기
-		ciphertext := make([]byte, chunkSize)
-		if _, readFullErr := io.ReadFull(reader, ciphertext); readFullErr != nil {
-			return fmt.Errorf("암호화된 데이터 읽기 실패: %w", readFullErr)
-		}
-
-		// 복호화
-		plaintext, decryptErr := gcm.Open(nil, nonce, ciphertext, nil)
-		if decryptErr != nil {
-			return fmt.Errorf("복호화 실패: %w", decryptErr)
-		}
-
-		// 복호화된 데이터 저장
-		if _, writeErr := writer.Write(plaintext); writeErr != nil {
-			return fmt.Errorf("복호화된 데이터 저장 실패: %w", writeErr)
-		}
+// ZeroBytes 민감한 바이트 슬라이스를 0으로 덮어씁니다
+func ZeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
 	}
-
-	return nil
 }
@@ -71,6 +71,11 @@ func TestDeriveKey(t *testing.T) {
 	// 다른 패스워드로는 다른 키가 나와야 함
 	key3 := engine.DeriveKey("differentpassword", testSalt)
 	assert.NotEqual(t, key, key3)
+
+	// 바이트 패스워드로도 같은 키가 나오고 패스워드는 그대로 둠
+	password := []byte(TestPassword)
+	assert.Equal(t, key, engine.DeriveKeyBytes(password, testSalt))
+	assert.Equal(t, TestPassword, string(password))
 }
 
 func TestEncryptDecrypt_Success(t *testing.T) {
@@ -162,6 +167,30 @@ func TestEncrypt_ErrorCases(t *testing.T) {
 	}
 }
 
+func TestEncrypt_MaxInMemorySize(t *testing.T) {
+	engine := NewCryptoEngine()
+	const limit = 1024
+
+	// 한도와 정확히 같은 크기는 허용
+	encData, err := engine.EncryptWithOptions(make([]byte, limit), []string{TestPassword}, WithMaxInMemoryEncryptSize(limit))
+	require.NoError(t, err)
+	decrypted, err := engine.Decrypt(encData, TestPassword)
+	require.NoError(t, err)
+	assert.Len(t, decrypted, limit)
+
+	// 1바이트 초과는 거부
+	_, err = engine.EncryptWithOptions(make([]byte, limit+1), []string{TestPassword}, WithMaxInMemoryEncryptSize(limit))
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+	assert.Contains(t, err.Error(), "EncryptStream")
+
+	// 기본 한도
+	_, err = engine.Encrypt(make([]byte, DefaultMaxInMemoryEncryptSize+1), TestPassword)
+	assert.ErrorIs(t, err, ErrPayloadTooLarge)
+
+	_, err = applyOptions([]Option{WithMaxInMemoryEncryptSize(0)})
+	assert.Error(t, err)
+}
+
 func TestDecrypt_ErrorCases(t *testing.T) {
 	engine := NewCryptoEngine()
 
@@ -273,8 +302,16 @@ func TestEncryptStream_EmptyData(t *testing.T) {
 	err := engine.EncryptStream(reader, &encryptedBuf, "password")
 	require.NoError(t, err)
 
-	// Salt만 저장되어야 함
-	assert.Equal(t, SaltSize, encryptedBuf.Len())
+	// 헤더와 종료 레코드, MAC 트레일러만 저장되어야 함
+	headerSize := len(StreamMagic) + streamHeaderFixedSize + keySlotHeaderSize
+	finalRecordSize := ChunkSizeBytes + GCMTagSize
+	assert.Equal(t, headerSize+finalRecordSize+MACTrailerSize, encryptedBuf.Len())
+
+	// 빈 스트림도 복호화되어야 함
+	var decryptedBuf bytes.Buffer
+	err = engine.DecryptStream(bytes.NewReader(encryptedBuf.Bytes()), &decryptedBuf, "password")
+	require.NoError(t, err)
+	assert.Zero(t, decryptedBuf.Len())
 }
 
 func TestEncryptStream_ErrorCases(t *testing.T) {
@@ -0,0 +1,284 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements the text-safe (base64 armored) envelope for encrypted streams.
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// armored 포맷 관련 상수
+//
+// 암호화 스트림 전체를 base64로 인코딩해 봉투 안에 넣습니다:
+//
+//	-----BEGIN DATALOCKER-----
+//	base64 (ArmorLineLength자마다 줄바꿈)
+//	-----END DATALOCKER-----
+const (
+	// ArmorHeader armored 본문의 시작 줄
+	ArmorHeader = "-----BEGIN DATALOCKER-----"
+
+	// ArmorFooter armored 본문의 끝 줄
+	ArmorFooter = "-----END DATALOCKER-----"
+
+	// ArmorLineLength 본문 한 줄의 최대 문자 수 (MIME base64와 같은 76자)
+	ArmorLineLength = 76
+)
+
+// OutputFormat 암호화 결과를 내보내는 형식
+type OutputFormat string
+
+// 출력 형식
+const (
+	// OutputBinary 스트림 바이너리 그대로
+	OutputBinary OutputFormat = "binary"
+
+	// OutputArmored base64 봉투로 감싼 텍스트 (설정 파일, 메일 본문용)
+	OutputArmored OutputFormat = "armored"
+)
+
+// ErrInvalidArmor armored 봉투나 본문이 잘못된 경우
+var ErrInvalidArmor = errors.New("잘못된 armored 형식입니다")
+
+// ErrUnsupportedOutputFormat 지원하지 않는 출력 형식
+var ErrUnsupportedOutputFormat = errors.New("지원하지 않는 출력 형식입니다")
+
+// ParseOutputFormat 이름으로 출력 형식을 찾습니다 (빈 문자열은 binary)
+//
+// 다운로드 API의 ?format= 쿼리 값을 해석할 때 사용합니다.
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	switch OutputFormat(strings.ToLower(strings.TrimSpace(name))) {
+	case "", OutputBinary:
+		return OutputBinary, nil
+	case OutputArmored:
+		return OutputArmored, nil
+	default:
+		return OutputBinary, fmt.Errorf("%w: %s", ErrUnsupportedOutputFormat, name)
+	}
+}
+
+// EncryptStreamArmored 스트림을 암호화해 armored 텍스트로 기록합니다
+func (ce *CryptoEngine) EncryptStreamArmored(reader io.Reader, writer io.Writer, passwords []string, opts ...Option) error {
+	armorWriter, err := NewArmorWriter(writer)
+	if err != nil {
+		return err
+	}
+
+	if err := ce.EncryptStreamWithOptions(reader, armorWriter, passwords, opts...); err != nil {
+		return err
+	}
+
+	return armorWriter.Close()
+}
+
+// DecryptStreamArmored armored 텍스트를 풀어 복호화합니다
+//
+// 줄바꿈, 들여쓰기 등 공백은 무시하지만 봉투 밖에 다른 데이터가 있으면 거부합니다.
+func (ce *CryptoEngine) DecryptStreamArmored(reader io.Reader, writer io.Writer, password string) error {
+	armorReader, err := NewArmorReader(reader)
+	if err != nil {
+		return err
+	}
+
+	return ce.DecryptStream(armorReader, writer, password)
+}
+
+// armorWriter 바이너리를 base64 본문으로 인코딩하는 Writer
+type armorWriter struct {
+	lines   *lineWrapper
+	encoder io.WriteCloser
+	closed  bool
+}
+
+// NewArmorWriter 시작 줄을 기록하고 armored 본문 Writer를 반환합니다
+//
+// Close를 호출해야 남은 base64 블록과 끝 줄이 기록됩니다.
+func NewArmorWriter(writer io.Writer) (io.WriteCloser, error) {
+	if _, err := io.WriteString(writer, ArmorHeader+"\n"); err != nil {
+		return nil, fmt.Errorf("armored 시작 줄 기록 실패: %w", err)
+	}
+
+	lines := &lineWrapper{writer: writer}
+	return &armorWriter{
+		lines:   lines,
+		encoder: base64.NewEncoder(base64.StdEncoding, lines),
+	}, nil
+}
+
+// Write 바이너리를 base64로 인코딩해 기록합니다
+func (w *armorWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("닫힌 armored Writer에 쓸 수 없습니다")
+	}
+	return w.encoder.Write(p)
+}
+
+// Close 남은 블록을 기록하고 끝 줄로 봉투를 닫습니다
+func (w *armorWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if err := w.encoder.Close(); err != nil {
+		return fmt.Errorf("armored 본문 기록 실패: %w", err)
+	}
+
+	tail := ArmorFooter + "\n"
+	if w.lines.column > 0 {
+		tail = "\n" + tail
+	}
+	if _, err := io.WriteString(w.lines.writer, tail); err != nil {
+		return fmt.Errorf("armored 끝 줄 기록 실패: %w", err)
+	}
+
+	return nil
+}
+
+// lineWrapper ArmorLineLength자마다 줄바꿈을 넣는 Writer
+type lineWrapper struct {
+	writer io.Writer
+	column int
+}
+
+// Write 줄 길이를 넘지 않도록 나눠 기록합니다
+func (l *lineWrapper) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(ArmorLineLength-l.column, len(p))
+		if _, err := l.writer.Write(p[:n]); err != nil {
+			return written, err
+		}
+		written += n
+		l.column += n
+		p = p[n:]
+
+		if l.column == ArmorLineLength {
+			if _, err := io.WriteString(l.writer, "\n"); err != nil {
+				return written, err
+			}
+			l.column = 0
+		}
+	}
+	return written, nil
+}
+
+// armorTextReader 봉투 안의 base64 문자만 공백 없이 전달하는 Reader
+type armorTextReader struct {
+	reader  *bufio.Reader
+	pending []byte
+	midLine bool // 직전 읽기가 버퍼보다 긴 줄의 일부였는지
+	err     error
+}
+
+// NewArmorReader 시작 줄을 확인하고 본문을 디코딩하는 Reader를 반환합니다
+//
+// 시작 줄 앞에는 공백 줄만 허용합니다. 끝 줄이 없거나 끝 줄 뒤에 공백이 아닌
+// 데이터가 있으면 읽는 도중 ErrInvalidArmor를 반환합니다.
+func NewArmorReader(reader io.Reader) (io.Reader, error) {
+	text := &armorTextReader{reader: bufio.NewReader(reader)}
+	if err := text.readHeader(); err != nil {
+		return nil, err
+	}
+
+	return &armorDecodeReader{decoder: base64.NewDecoder(base64.StdEncoding, text)}, nil
+}
+
+// readHeader 공백 줄을 건너뛰고 시작 줄을 확인합니다
+func (a *armorTextReader) readHeader() error {
+	for {
+		line, err := a.reader.ReadString('\n')
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == ArmorHeader:
+			return nil
+		case trimmed != "":
+			return fmt.Errorf("%w: 시작 줄(%s)이 없습니다", ErrInvalidArmor, ArmorHeader)
+		case err == io.EOF:
+			return fmt.Errorf("%w: 빈 입력입니다", ErrInvalidArmor)
+		case err != nil:
+			return fmt.Errorf("armored 시작 줄 읽기 실패: %w", err)
+		}
+	}
+}
+
+// Read 본문 base64 문자를 전달합니다 (끝 줄에서 io.EOF)
+func (a *armorTextReader) Read(p []byte) (int, error) {
+	for len(a.pending) == 0 {
+		if a.err != nil {
+			return 0, a.err
+		}
+		a.fill()
+	}
+
+	n := copy(p, a.pending)
+	a.pending = a.pending[n:]
+	return n, nil
+}
+
+// fill 다음 줄(또는 긴 줄의 일부)을 읽어 공백을 제거합니다
+func (a *armorTextReader) fill() {
+	line, err := a.reader.ReadSlice('\n')
+
+	if !a.midLine && string(bytes.TrimSpace(line)) == ArmorFooter {
+		a.err = a.checkTrailer()
+		return
+	}
+
+	a.pending = bytes.Map(dropSpace, line)
+	a.midLine = err == bufio.ErrBufferFull
+
+	switch {
+	case err == nil, a.midLine:
+	case err == io.EOF:
+		a.err = fmt.Errorf("%w: 끝 줄(%s)이 없습니다", ErrInvalidArmor, ArmorFooter)
+	default:
+		a.err = fmt.Errorf("armored 본문 읽기 실패: %w", err)
+	}
+}
+
+// checkTrailer 끝 줄 뒤에 공백만 남았는지 확인합니다
+func (a *armorTextReader) checkTrailer() error {
+	for {
+		r, _, err := a.reader.ReadRune()
+		if err == io.EOF {
+			return io.EOF
+		}
+		if err != nil {
+			return fmt.Errorf("armored 끝 줄 뒤 읽기 실패: %w", err)
+		}
+		if !unicode.IsSpace(r) {
+			return fmt.Errorf("%w: 끝 줄 뒤에 데이터가 있습니다", ErrInvalidArmor)
+		}
+	}
+}
+
+// dropSpace bytes.Map에서 공백 문자를 제거합니다
+func dropSpace(r rune) rune {
+	if unicode.IsSpace(r) {
+		return -1
+	}
+	return r
+}
+
+// armorDecodeReader base64 디코딩 에러를 ErrInvalidArmor로 감싸는 Reader
+type armorDecodeReader struct {
+	decoder io.Reader
+}
+
+// Read 디코딩된 바이너리를 읽습니다
+func (r *armorDecodeReader) Read(p []byte) (int, error) {
+	n, err := r.decoder.Read(p)
+
+	var corrupt base64.CorruptInputError
+	if errors.As(err, &corrupt) {
+		return n, fmt.Errorf("%w: base64 본문이 손상되었습니다 (offset %d)", ErrInvalidArmor, int64(corrupt))
+	}
+	return n, err
+}
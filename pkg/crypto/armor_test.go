@@ -0,0 +1,107 @@
+package crypto
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptArmored 평문을 armored 텍스트로 암호화합니다
+func encryptArmored(t *testing.T, plaintext []byte) string {
+	t.Helper()
+	var out bytes.Buffer
+	require.NoError(t, NewCryptoEngine().EncryptStreamArmored(bytes.NewReader(plaintext), &out, []string{StreamPassword}))
+	return out.String()
+}
+
+// decryptArmored armored 텍스트를 복호화합니다
+func decryptArmored(text string) ([]byte, error) {
+	var out bytes.Buffer
+	err := NewCryptoEngine().DecryptStreamArmored(strings.NewReader(text), &out, StreamPassword)
+	return out.Bytes(), err
+}
+
+func TestEncryptStreamArmored_Envelope(t *testing.T) {
+	armored := encryptArmored(t, []byte(strings.Repeat(LongTestData, 20)))
+
+	lines := strings.Split(strings.TrimSuffix(armored, "\n"), "\n")
+	require.GreaterOrEqual(t, len(lines), 3)
+	assert.Equal(t, ArmorHeader, lines[0])
+	assert.Equal(t, ArmorFooter, lines[len(lines)-1])
+
+	body := lines[1 : len(lines)-1]
+	for i, line := range body {
+		assert.LessOrEqual(t, len(line), ArmorLineLength)
+		if i < len(body)-1 {
+			assert.Len(t, line, ArmorLineLength, "마지막 줄을 제외하면 꽉 찬 줄")
+		}
+	}
+}
+
+func TestDecryptStreamArmored_RoundTrip(t *testing.T) {
+	testCases := map[string][]byte{
+		"빈 데이터":  {},
+		"짧은 데이터": []byte(TestData),
+		"여러 청크":  bytes.Repeat([]byte("armored "), ChunkSize/4),
+	}
+
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			decrypted, err := decryptArmored(encryptArmored(t, data))
+			require.NoError(t, err)
+			assert.Equal(t, data, append([]byte{}, decrypted...))
+		})
+	}
+}
+
+func TestDecryptStreamArmored_WhitespaceTolerant(t *testing.T) {
+	armored := encryptArmored(t, []byte(TestData))
+
+	// 메일 클라이언트처럼 CRLF, 들여쓰기, 앞뒤 빈 줄이 섞여도 복호화
+	mangled := "\n\n  " + strings.ReplaceAll(armored, "\n", "\r\n    ") + "\n\n"
+	decrypted, err := decryptArmored(mangled)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(TestData), decrypted)
+
+	// 본문을 한 줄로 이어 붙여도 복호화
+	lines := strings.Split(strings.TrimSpace(armored), "\n")
+	joined := lines[0] + "\n" + strings.Join(lines[1:len(lines)-1], "") + "\n" + lines[len(lines)-1]
+	decrypted, err = decryptArmored(joined)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(TestData), decrypted)
+}
+
+func TestDecryptStreamArmored_RejectsInvalid(t *testing.T) {
+	armored := encryptArmored(t, []byte(TestData))
+	body := strings.TrimSuffix(strings.TrimPrefix(armored, ArmorHeader+"\n"), ArmorFooter+"\n")
+
+	testCases := map[string]string{
+		"빈 입력":       "",
+		"시작 줄 앞 데이터": "hello\n" + armored,
+		"시작 줄 없음":    body + ArmorFooter + "\n",
+		"끝 줄 없음":     ArmorHeader + "\n" + body,
+		"끝 줄 뒤 데이터":  armored + "trailing\n",
+		"base64 손상":  ArmorHeader + "\n" + "!!!!" + body + ArmorFooter + "\n",
+	}
+
+	for name, text := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := decryptArmored(text)
+			assert.ErrorIs(t, err, ErrInvalidArmor)
+		})
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	for input, want := range map[string]OutputFormat{"": OutputBinary, "binary": OutputBinary, "Armored": OutputArmored} {
+		got, err := ParseOutputFormat(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := ParseOutputFormat("hex")
+	assert.ErrorIs(t, err, ErrUnsupportedOutputFormat)
+}
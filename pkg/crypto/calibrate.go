@@ -0,0 +1,77 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements PBKDF2 cost calibration for the current host.
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 반복 횟수 보정 관련 상수
+const (
+	// MinIterations 허용하는 최소 PBKDF2 반복 횟수 (model.MinIterations와 일치)
+	MinIterations = 1000
+
+	// MaxIterations 허용하는 최대 PBKDF2 반복 횟수 (model.MaxIterations와 일치)
+	MaxIterations = 1000000
+
+	// calibrationProbeIterations 측정에 사용하는 반복 횟수
+	calibrationProbeIterations = 10000
+
+	// calibrationRounds 측정 횟수 (가장 빠른 측정값 사용)
+	calibrationRounds = 3
+)
+
+// ErrInvalidCalibrationTarget 보정 목표 시간이 잘못된 경우
+var ErrInvalidCalibrationTarget = errors.New("반복 횟수 보정 목표 시간은 0보다 커야 합니다")
+
+// CalibrateIterations 현재 호스트에서 키 유도 한 번이 목표 시간만큼 걸리는 반복 횟수를 구합니다
+//
+// PBKDF2를 여러 번 측정해 가장 빠른 값을 기준으로 계산하므로, 실제 소요 시간은
+// 목표 시간 이상이 됩니다. 결과는 MinIterations ~ MaxIterations로 제한됩니다.
+// 반복 횟수는 파일마다 스트림 헤더와 메타데이터에 기록되므로, 값이 바뀌어도
+// 기존 파일의 복호화에는 영향이 없습니다.
+func CalibrateIterations(targetDuration time.Duration) (int, error) {
+	if targetDuration <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidCalibrationTarget, targetDuration)
+	}
+
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return 0, fmt.Errorf("salt 생성 실패: %w", err)
+	}
+
+	engine := NewCryptoEngine()
+	var fastest time.Duration
+	for i := 0; i < calibrationRounds; i++ {
+		start := time.Now()
+		key := engine.deriveKeyWithIterations([]byte("datalocker-calibration"), salt, calibrationProbeIterations)
+		elapsed := time.Since(start)
+		ZeroBytes(key)
+
+		if i == 0 || elapsed < fastest {
+			fastest = elapsed
+		}
+	}
+
+	return scaleIterations(calibrationProbeIterations, fastest, targetDuration), nil
+}
+
+// scaleIterations 측정값을 목표 시간에 맞게 비례 환산하고 허용 범위로 제한합니다
+func scaleIterations(probeIterations int, elapsed, target time.Duration) int {
+	if elapsed <= 0 {
+		return MaxIterations
+	}
+
+	iterations := float64(probeIterations) * float64(target) / float64(elapsed)
+	switch {
+	case iterations < MinIterations:
+		return MinIterations
+	case iterations > MaxIterations:
+		return MaxIterations
+	default:
+		return int(iterations)
+	}
+}
@@ -0,0 +1,63 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaleIterations(t *testing.T) {
+	testCases := []struct {
+		name    string
+		elapsed time.Duration
+		target  time.Duration
+		want    int
+	}{
+		{name: "비례 환산", elapsed: 10 * time.Millisecond, target: 100 * time.Millisecond, want: 100000},
+		{name: "최소값 제한", elapsed: time.Second, target: time.Millisecond, want: MinIterations},
+		{name: "최대값 제한", elapsed: time.Microsecond, target: time.Second, want: MaxIterations},
+		{name: "측정값 0", elapsed: 0, target: time.Second, want: MaxIterations},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, scaleIterations(10000, tc.elapsed, tc.target))
+		})
+	}
+}
+
+func TestCalibrateIterations(t *testing.T) {
+	iterations, err := CalibrateIterations(5 * time.Millisecond)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, iterations, MinIterations)
+	assert.LessOrEqual(t, iterations, MaxIterations)
+
+	_, err = CalibrateIterations(0)
+	assert.ErrorIs(t, err, ErrInvalidCalibrationTarget)
+}
+
+func TestEncryptStream_WithIterations(t *testing.T) {
+	encrypted := encryptWithOptions(t, []byte(TestData), WithIterations(MinIterations))
+
+	info, err := ReadStreamInfo(bytes.NewReader(encrypted))
+	require.NoError(t, err)
+	assert.Equal(t, CurrentStreamFormat, info.Version)
+	require.Len(t, info.KeySlots, 1)
+	assert.Equal(t, MinIterations, info.KeySlots[0].Iterations)
+
+	// 반복 횟수는 헤더에서 읽으므로 복호화 시 지정할 필요 없음
+	assert.Equal(t, []byte(TestData), decryptToBytes(t, encrypted, StreamPassword))
+
+	_, err = applyOptions([]Option{WithIterations(MinIterations - 1)})
+	assert.Error(t, err)
+	_, err = applyOptions([]Option{WithIterations(MaxIterations + 1)})
+	assert.Error(t, err)
+}
+
+func TestReadStreamInfo_Legacy(t *testing.T) {
+	_, err := ReadStreamInfo(bytes.NewReader(make([]byte, SaltSize)))
+	assert.ErrorIs(t, err, ErrUnsupportedStreamFormat)
+}
@@ -0,0 +1,209 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements optional per-chunk compression applied before sealing.
+package crypto
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Compression 봉인 전에 청크에 적용하는 압축 알고리즘 (스트림 헤더에 기록)
+type Compression byte
+
+// 압축 알고리즘
+const (
+	// CompressionNone 압축하지 않음
+	CompressionNone Compression = 0
+
+	// CompressionGzip 청크마다 독립된 gzip 멤버로 압축
+	CompressionGzip Compression = 1
+)
+
+// 압축 스트림의 청크 평문 구조: flag(1) | 본문
+//
+// 압축해도 작아지지 않는 청크(이미 압축된 데이터 등)는 원본 그대로 저장하므로,
+// 플래그는 봉인된 평문 안에 있어 함께 인증됩니다.
+const (
+	chunkFlagSize = 1
+
+	// chunkStored 원본 그대로 저장된 청크
+	chunkStored byte = 0
+
+	// chunkCompressed 압축된 청크
+	chunkCompressed byte = 1
+)
+
+// ErrUnsupportedCompression 지원하지 않는 압축 알고리즘
+var ErrUnsupportedCompression = errors.New("지원하지 않는 압축 알고리즘입니다")
+
+// incompressibleMIMEPrefixes 이미 압축되어 있어 다시 압축해도 이득이 없는 MIME 타입
+var incompressibleMIMEPrefixes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"image/avif",
+	"image/heic",
+	"video/",
+	"audio/mpeg",
+	"audio/aac",
+	"audio/ogg",
+	"audio/flac",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-bzip2",
+	"application/x-xz",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/vnd.rar",
+	"application/zstd",
+	"application/pdf",
+	"application/vnd.openxmlformats-officedocument.",
+	"application/epub+zip",
+}
+
+// String 압축 알고리즘 이름을 반환합니다 (EncryptionMetadata.Compression 값과 일치)
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(c))
+	}
+}
+
+// valid 지원하는 압축 알고리즘인지 확인합니다
+func (c Compression) valid() bool {
+	return c == CompressionNone || c == CompressionGzip
+}
+
+// ParseCompression 이름으로 압축 알고리즘을 찾습니다 (빈 문자열은 none)
+func ParseCompression(name string) (Compression, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none":
+		return CompressionNone, nil
+	case "gzip":
+		return CompressionGzip, nil
+	default:
+		return CompressionNone, fmt.Errorf("%w: %s", ErrUnsupportedCompression, name)
+	}
+}
+
+// ShouldCompress MIME 타입으로 압축 이득이 있을지 추정합니다
+//
+// jpeg, zip처럼 이미 압축된 형식은 false를 반환합니다. 추정이 틀려도 압축
+// 결과가 원본보다 크면 청크 단위로 원본을 저장하므로 손해는 청크당 1바이트입니다.
+func ShouldCompress(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:i])
+	}
+
+	for _, prefix := range incompressibleMIMEPrefixes {
+		if strings.HasPrefix(mimeType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// CompressionForMIME MIME 타입에 맞는 압축 알고리즘을 반환합니다
+func CompressionForMIME(mimeType string) Compression {
+	if ShouldCompress(mimeType) {
+		return CompressionGzip
+	}
+	return CompressionNone
+}
+
+// chunkCompressor 청크를 압축해 flag | 본문 형태로 만드는 인코더
+type chunkCompressor struct {
+	zw  *gzip.Writer
+	buf bytes.Buffer
+}
+
+// newChunkCompressor 압축 알고리즘에 맞는 인코더를 생성합니다 (none이면 nil)
+func newChunkCompressor(compression Compression) *chunkCompressor {
+	if compression != CompressionGzip {
+		return nil
+	}
+	return &chunkCompressor{zw: gzip.NewWriter(io.Discard)}
+}
+
+// encode 청크를 압축하고, 작아지지 않으면 원본을 그대로 담습니다
+//
+// 반환값은 다음 encode 호출 전까지만 유효합니다.
+func (c *chunkCompressor) encode(chunk []byte) []byte {
+	c.buf.Reset()
+	c.buf.WriteByte(chunkCompressed)
+	c.zw.Reset(&c.buf)
+
+	_, writeErr := c.zw.Write(chunk)
+	closeErr := c.zw.Close()
+	if writeErr == nil && closeErr == nil && c.buf.Len()-chunkFlagSize < len(chunk) {
+		return c.buf.Bytes()
+	}
+
+	c.buf.Reset()
+	c.buf.WriteByte(chunkStored)
+	c.buf.Write(chunk)
+	return c.buf.Bytes()
+}
+
+// chunkDecompressor flag | 본문 형태의 청크를 원래 평문으로 되돌리는 디코더
+type chunkDecompressor struct {
+	zr  *gzip.Reader
+	src bytes.Reader
+	out bytes.Buffer
+}
+
+// decode 청크를 복원합니다 (limit 바이트를 넘는 청크는 거부)
+//
+// 반환값은 다음 decode 호출 전까지만 유효합니다.
+func (d *chunkDecompressor) decode(payload []byte, limit int) ([]byte, error) {
+	if len(payload) < chunkFlagSize {
+		return nil, errors.New("압축 청크에 플래그가 없습니다")
+	}
+
+	body := payload[chunkFlagSize:]
+	switch payload[0] {
+	case chunkStored:
+		if len(body) > limit {
+			return nil, fmt.Errorf("청크가 너무 큽니다: %d bytes", len(body))
+		}
+		return body, nil
+	case chunkCompressed:
+	default:
+		return nil, fmt.Errorf("알 수 없는 청크 플래그: %d", payload[0])
+	}
+
+	d.src.Reset(body)
+	if d.zr == nil {
+		zr, err := gzip.NewReader(&d.src)
+		if err != nil {
+			return nil, fmt.Errorf("압축 해제 실패: %w", err)
+		}
+		d.zr = zr
+	} else if err := d.zr.Reset(&d.src); err != nil {
+		return nil, fmt.Errorf("압축 해제 실패: %w", err)
+	}
+	d.zr.Multistream(false)
+
+	// 압축 폭탄 방지: 청크 크기 한도를 넘으면 중단
+	d.out.Reset()
+	n, err := d.out.ReadFrom(io.LimitReader(d.zr, int64(limit)+1))
+	if err != nil {
+		return nil, fmt.Errorf("압축 해제 실패: %w", err)
+	}
+	if n > int64(limit) {
+		return nil, fmt.Errorf("압축 해제된 청크가 너무 큽니다: %d bytes 초과", limit)
+	}
+
+	return d.out.Bytes(), nil
+}
@@ -0,0 +1,152 @@
+package crypto
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptWithOptions 옵션을 적용해 스트림을 암호화합니다
+func encryptWithOptions(t *testing.T, plaintext []byte, opts ...Option) []byte {
+	t.Helper()
+	var encrypted bytes.Buffer
+	err := NewCryptoEngine().EncryptStreamWithOptions(bytes.NewReader(plaintext), &encrypted, []string{StreamPassword}, opts...)
+	require.NoError(t, err)
+	return encrypted.Bytes()
+}
+
+func TestEncryptStream_CompressionShrinksText(t *testing.T) {
+	text := []byte(strings.Repeat(LongTestData, 50000)) // 약 3MB, 여러 청크
+
+	raw := encryptWithOptions(t, text)
+	compressed := encryptWithOptions(t, text, WithCompression(CompressionGzip))
+
+	assert.Less(t, len(compressed)*5, len(raw), "텍스트는 5배 이상 줄어야 함")
+	assert.Equal(t, text, decryptToBytes(t, compressed, StreamPassword))
+	assert.Equal(t, byte(CompressionGzip), compressed[len(StreamMagic)+1])
+}
+
+func TestEncryptStream_CompressionIncompressibleInput(t *testing.T) {
+	random := make([]byte, 2*ChunkSize+123)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+
+	raw := encryptWithOptions(t, random)
+	compressed := encryptWithOptions(t, random, WithCompression(CompressionGzip))
+
+	// 압축 이득이 없는 청크는 원본으로 저장되므로 청크당 플래그 1바이트만 늘어남
+	chunks := 3
+	assert.Equal(t, len(raw)+chunks*chunkFlagSize, len(compressed))
+	assert.Equal(t, random, decryptToBytes(t, compressed, StreamPassword))
+}
+
+func TestEncryptStream_CompressionRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name      string
+		data      []byte
+		chunkSize int
+	}{
+		{name: "빈 데이터", data: []byte{}, chunkSize: ChunkSize},
+		{name: "1바이트", data: []byte{'a'}, chunkSize: ChunkSize},
+		{name: "청크 경계", data: bytes.Repeat([]byte("x"), 4096), chunkSize: 1024},
+		{name: "작은 청크", data: []byte(strings.Repeat(TestData, 100)), chunkSize: 7},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encrypted := encryptWithOptions(t, tc.data, WithCompression(CompressionGzip), WithChunkSize(tc.chunkSize))
+
+			decrypted, err := readAllWithBuffer(mustDecryptReader(t, encrypted), 3)
+			require.NoError(t, err)
+			assert.Equal(t, tc.data, append([]byte{}, decrypted...))
+			require.NoError(t, VerifyStream(bytes.NewReader(encrypted), StreamPassword))
+		})
+	}
+}
+
+func TestDecryptStream_RejectsUnknownCompression(t *testing.T) {
+	encrypted := encryptWithOptions(t, []byte(TestData), WithCompression(CompressionGzip))
+	encrypted[len(StreamMagic)+1] = 9
+
+	var out bytes.Buffer
+	err := NewCryptoEngine().DecryptStream(bytes.NewReader(encrypted), &out, StreamPassword)
+	assert.ErrorIs(t, err, ErrUnsupportedCompression)
+}
+
+func TestDecryptStream_CompressionFlagIsAuthenticated(t *testing.T) {
+	// 헤더의 압축 바이트를 바꾸면 MAC 트레일러가 맞지 않아야 함
+	encrypted := encryptWithOptions(t, []byte(TestData))
+	encrypted[len(StreamMagic)+1] = byte(CompressionGzip)
+
+	err := VerifyStream(bytes.NewReader(encrypted), StreamPassword)
+	assert.Error(t, err)
+}
+
+func TestChunkDecompressor_Limit(t *testing.T) {
+	var payload bytes.Buffer
+	payload.WriteByte(chunkCompressed)
+	zw := gzip.NewWriter(&payload)
+	_, err := zw.Write(make([]byte, 4096))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	d := &chunkDecompressor{}
+	_, err = d.decode(payload.Bytes(), 1024)
+	assert.Error(t, err, "한도를 넘는 압축 해제는 거부해야 함")
+
+	out, err := d.decode(payload.Bytes(), 4096)
+	require.NoError(t, err)
+	assert.Len(t, out, 4096)
+
+	_, err = d.decode([]byte{7, 1, 2}, 4096)
+	assert.Error(t, err)
+}
+
+func TestShouldCompress(t *testing.T) {
+	testCases := map[string]bool{
+		"text/plain":               true,
+		"text/csv; charset=utf-8":  true,
+		"application/json":         true,
+		"image/jpeg":               false,
+		"IMAGE/PNG":                false,
+		"video/mp4":                false,
+		"application/zip":          false,
+		"application/x-gzip":       false,
+		"application/octet-stream": true,
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": false,
+	}
+
+	for mimeType, want := range testCases {
+		assert.Equal(t, want, ShouldCompress(mimeType), mimeType)
+	}
+
+	assert.Equal(t, CompressionNone, CompressionForMIME("image/jpeg"))
+	assert.Equal(t, CompressionGzip, CompressionForMIME("text/plain"))
+}
+
+func TestParseCompression(t *testing.T) {
+	for _, c := range []Compression{CompressionNone, CompressionGzip} {
+		parsed, err := ParseCompression(c.String())
+		require.NoError(t, err)
+		assert.Equal(t, c, parsed)
+	}
+
+	_, err := ParseCompression("zstd")
+	assert.ErrorIs(t, err, ErrUnsupportedCompression)
+
+	_, err = applyOptions([]Option{WithCompression(Compression(9))})
+	assert.ErrorIs(t, err, ErrUnsupportedCompression)
+}
+
+// mustDecryptReader 복호화 Reader를 생성합니다
+func mustDecryptReader(t *testing.T, encrypted []byte) *decryptReader {
+	t.Helper()
+	r, err := NewCryptoEngine().newDecryptReader(bytes.NewReader(encrypted), StreamPassword)
+	require.NoError(t, err)
+	return r
+}
@@ -0,0 +1,50 @@
+package crypto_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"DataLocker/pkg/crypto"
+)
+
+// 엔진 옵션은 이후 모든 암호화 호출의 기본값이 됩니다. 청크 크기와 압축은
+// 스트림 암호화에만 적용되고, 반복 횟수는 헤더의 키 슬롯에 기록됩니다.
+func ExampleNewCryptoEngine() {
+	engine := crypto.NewCryptoEngine(
+		crypto.WithIterations(crypto.MinIterations),
+		crypto.WithChunkSize(64*1024),
+		crypto.WithCompression(crypto.CompressionGzip),
+	)
+	if err := engine.Err(); err != nil {
+		fmt.Println("옵션 오류:", err)
+		return
+	}
+
+	var encrypted, decrypted bytes.Buffer
+	if err := engine.EncryptStream(strings.NewReader("hello, datalocker"), &encrypted, "password"); err != nil {
+		fmt.Println("암호화 실패:", err)
+		return
+	}
+	if err := engine.DecryptStream(&encrypted, &decrypted, "password"); err != nil {
+		fmt.Println("복호화 실패:", err)
+		return
+	}
+
+	fmt.Println(decrypted.String())
+	// Output: hello, datalocker
+}
+
+// 잘못된 옵션은 생성 시 모두 모아 Err와 이후 암호화 호출에서 보고됩니다.
+func ExampleNewCryptoEngine_invalidOptions() {
+	engine := crypto.NewCryptoEngine(crypto.WithAlgorithm("DES"))
+
+	fmt.Println(errors.Is(engine.Err(), crypto.ErrUnsupportedAlgorithm))
+
+	_, err := engine.Encrypt([]byte("data"), "password")
+	fmt.Println(errors.Is(err, crypto.ErrInvalidOption))
+	// Output:
+	// true
+	// true
+}
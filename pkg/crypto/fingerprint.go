@@ -0,0 +1,61 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements password fingerprints for password reuse detection.
+package crypto
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// 패스워드 지문 관련 상수 (Argon2id)
+const (
+	// 지문 Salt 최소 크기 (16 바이트)
+	FingerprintMinSaltSize = 16
+
+	// 지문 크기 (32 바이트)
+	FingerprintSize = 32
+
+	// Argon2id 반복 횟수
+	FingerprintTime = 3
+
+	// Argon2id 메모리 사용량 (KiB, 64MB)
+	FingerprintMemory = 64 * 1024
+
+	// Argon2id 병렬 처리 수
+	FingerprintThreads = 4
+)
+
+// ErrInvalidFingerprintSalt 지문 Salt가 너무 짧음
+var ErrInvalidFingerprintSalt = errors.New("패스워드 지문 salt가 너무 짧습니다")
+
+// PasswordFingerprint 패스워드 재사용 감지를 위한 지문을 계산합니다
+//
+// 지문은 파일 암호화 Salt와 독립된 전용 Salt와 Argon2id로 계산되므로
+// 같은 패스워드인지 비교하는 용도로만 쓸 수 있고, 원문 복원이나
+// 파일 복호화 키(PBKDF2) 유도에는 사용할 수 없습니다.
+func PasswordFingerprint(password string, salt []byte) (string, error) {
+	secret := []byte(password)
+	defer ZeroBytes(secret)
+
+	return PasswordFingerprintBytes(secret, salt)
+}
+
+// PasswordFingerprintBytes 바이트 패스워드의 지문을 계산합니다
+//
+// PasswordFingerprint와 같은 지문을 만들지만 패스워드를 복사하지 않습니다.
+// 패스워드는 호출자가 소유하므로 사용이 끝나면 ZeroBytes로 지워야 합니다.
+func PasswordFingerprintBytes(password, salt []byte) (string, error) {
+	if len(password) == 0 {
+		return "", errors.New("패스워드가 필요합니다")
+	}
+
+	if len(salt) < FingerprintMinSaltSize {
+		return "", fmt.Errorf("%w: %d (최소: %d)", ErrInvalidFingerprintSalt, len(salt), FingerprintMinSaltSize)
+	}
+
+	sum := argon2.IDKey(password, salt, FingerprintTime, FingerprintMemory, FingerprintThreads, FingerprintSize)
+	return hex.EncodeToString(sum), nil
+}
@@ -0,0 +1,49 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordFingerprint(t *testing.T) {
+	salt := bytes.Repeat([]byte{0x5a}, FingerprintMinSaltSize)
+	otherSalt := bytes.Repeat([]byte{0xa5}, FingerprintMinSaltSize)
+
+	// 같은 패스워드와 salt는 같은 지문
+	first, err := PasswordFingerprint("shared-password", salt)
+	require.NoError(t, err)
+	second, err := PasswordFingerprint("shared-password", salt)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Len(t, first, FingerprintSize*2)
+
+	// 다른 패스워드나 salt는 다른 지문
+	different, err := PasswordFingerprint("other-password", salt)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, different)
+
+	otherSalted, err := PasswordFingerprint("shared-password", otherSalt)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, otherSalted)
+
+	// 바이트 패스워드도 같은 지문
+	fromBytes, err := PasswordFingerprintBytes([]byte("shared-password"), salt)
+	require.NoError(t, err)
+	assert.Equal(t, first, fromBytes)
+
+	// 지문은 같은 salt로 유도한 복호화 키와 달라야 함
+	engine := NewCryptoEngine()
+	assert.NotEqual(t, first, hex.EncodeToString(engine.DeriveKey("shared-password", salt)))
+}
+
+func TestPasswordFingerprint_InvalidInput(t *testing.T) {
+	_, err := PasswordFingerprint("", bytes.Repeat([]byte{1}, FingerprintMinSaltSize))
+	assert.Error(t, err)
+
+	_, err = PasswordFingerprint("password", []byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidFingerprintSalt)
+}
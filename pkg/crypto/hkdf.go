@@ -0,0 +1,63 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements HKDF sub-key separation for encryption and authentication.
+package crypto
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"fmt"
+)
+
+// HKDF info 문자열 (다른 언어 구현과의 호환을 위해 변경 금지)
+//
+// 서브키는 HKDF-SHA256(salt 없음, ikm=마스터 키, info=아래 문자열, L=32)로 유도합니다.
+const (
+	// HKDFInfoEncryption 데이터 암호화(AES-GCM) 키 용도
+	HKDFInfoEncryption = "DataLocker/v2/encryption"
+
+	// HKDFInfoMetadataMAC 메타데이터(매니페스트) 인증(HMAC) 키 용도
+	HKDFInfoMetadataMAC = "DataLocker/v2/metadata-mac"
+
+	// HKDFInfoStreamEncryption 헤더에 묶인 스트림 청크 암호화 키 용도 (스트림 버전 6, 뒤에 nonce 방식과 헤더 해시가 붙음)
+	HKDFInfoStreamEncryption = "DataLocker/v6/stream-encryption"
+
+	// HKDFInfoStreamMAC 헤더에 묶인 스트림 MAC 트레일러 키 용도 (스트림 버전 6, 뒤에 nonce 방식과 헤더 해시가 붙음)
+	HKDFInfoStreamMAC = "DataLocker/v6/stream-mac"
+)
+
+// DeriveSubKeys 마스터 키에서 용도별 서브키를 유도합니다
+//
+// 하나의 키를 암호화와 인증에 함께 쓰지 않도록 PBKDF2 출력이나 데이터 키를
+// 마스터 키로 보고, info 문자열로 구분된 암호화 키와 MAC 키를 만듭니다.
+func DeriveSubKeys(masterKey []byte) (encKey, macKey []byte) {
+	return expandSubKey(masterKey, HKDFInfoEncryption), expandSubKey(masterKey, HKDFInfoMetadataMAC)
+}
+
+// deriveStreamSubKeys 데이터 키와 스트림 헤더 원문에서 청크 암호화 키와 MAC 키를 유도합니다
+//
+// info에 nonce 방식과 헤더(매직 바이트부터 키 슬롯까지)의 SHA-256을 덧붙이므로,
+// 버전 바이트나 압축 플래그, nonce prefix, 키 슬롯을 바꾸거나 레코드의 nonce를
+// 읽는 방식으로 해석한 스트림은 다른 키로 풀게 되어 첫 청크부터 인증에 실패합니다.
+// 버전을 낮춰 MAC 트레일러나 카운터 nonce(순서 변경 감지) 검사를 건너뛰는
+// 다운그레이드를 막습니다.
+func deriveStreamSubKeys(dataKey, header []byte, nonceMode string) (encKey, macKey []byte) {
+	digest := sha256.Sum256(header)
+	context := "/" + nonceMode + "/" + string(digest[:])
+	return expandSubKey(dataKey, HKDFInfoStreamEncryption+context),
+		expandSubKey(dataKey, HKDFInfoStreamMAC+context)
+}
+
+// deriveEncryptionKey 마스터 키에서 암호화 서브키만 유도합니다
+func deriveEncryptionKey(masterKey []byte) []byte {
+	return expandSubKey(masterKey, HKDFInfoEncryption)
+}
+
+// expandSubKey HKDF-SHA256으로 info 용도의 KeySize 바이트 키를 유도합니다
+func expandSubKey(masterKey []byte, info string) []byte {
+	key, err := hkdf.Key(sha256.New, masterKey, nil, info, KeySize)
+	if err != nil {
+		// KeySize는 HKDF-SHA256 출력 한도(255*32)보다 작으므로 발생하지 않음
+		panic(fmt.Sprintf("HKDF 서브키 유도 실패: %v", err))
+	}
+	return key
+}
@@ -0,0 +1,127 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveSubKeys_KnownAnswers(t *testing.T) {
+	// HKDF-SHA256(salt 없음, info=HKDFInfo*, L=32) 기준값 (다른 언어 구현 검증용)
+	testCases := []struct {
+		name      string
+		masterKey string
+		encKey    string
+		macKey    string
+	}{
+		{
+			name:      "순차 바이트",
+			masterKey: "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+			encKey:    "6ce22765738dfa8e9b57723a056eb3d945f1bd8531c31f69efe7fa40583c0fb5",
+			macKey:    "cae265874f3ee47785a51f0d81da0b424ee84ec11b876c4bc0e0b3ba8ae08ddb",
+		},
+		{
+			name:      "0 바이트",
+			masterKey: "0000000000000000000000000000000000000000000000000000000000000000",
+			encKey:    "99eb6ea7f3d180ba55ca2cac2393e85b218290e1c0366b382d0863c18a77d84c",
+			macKey:    "81b90b95c491e17954a4ca99378a6f67b85238c8106bfdad0d852a84eb351677",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			masterKey, err := hex.DecodeString(tc.masterKey)
+			require.NoError(t, err)
+
+			encKey, macKey := DeriveSubKeys(masterKey)
+			assert.Equal(t, tc.encKey, hex.EncodeToString(encKey))
+			assert.Equal(t, tc.macKey, hex.EncodeToString(macKey))
+			assert.Len(t, encKey, KeySize)
+			assert.Len(t, macKey, KeySize)
+		})
+	}
+}
+
+func TestEncrypt_UsesSubKeyFormat(t *testing.T) {
+	engine := NewCryptoEngine()
+
+	for _, passwords := range [][]string{{OwnerPassword}, {OwnerPassword, StreamPassword}} {
+		encData, err := engine.Encrypt([]byte(TestData), passwords...)
+		require.NoError(t, err)
+		assert.Equal(t, CurrentDataFormat, encData.Version)
+
+		decrypted, err := engine.Decrypt(encData, OwnerPassword)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(TestData), decrypted)
+
+		// 서브키로 암호화되었으므로 원래 키로는 열리지 않아야 함
+		rawFormat := *encData
+		rawFormat.Version = DataFormatRawKey
+		_, err = engine.Decrypt(&rawFormat, OwnerPassword)
+		assert.Error(t, err)
+	}
+}
+
+func TestDecrypt_RawKeyFormat(t *testing.T) {
+	engine := NewCryptoEngine()
+
+	// 기존 포맷: PBKDF2 출력으로 직접 암호화
+	salt, err := engine.GenerateSalt()
+	require.NoError(t, err)
+	nonce, ciphertext, err := engine.seal(engine.DeriveKey(OwnerPassword, salt), []byte(TestData))
+	require.NoError(t, err)
+
+	legacy := &EncryptedData{Salt: salt, Nonce: nonce, Ciphertext: ciphertext}
+	decrypted, err := engine.Decrypt(legacy, OwnerPassword)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(TestData), decrypted)
+
+	// 알 수 없는 버전은 거부
+	legacy.Version = CurrentDataFormat + 1
+	_, err = engine.Decrypt(legacy, OwnerPassword)
+	assert.ErrorIs(t, err, ErrUnsupportedDataFormat)
+}
+
+func TestDecryptStream_EnvelopeFormatWithRawKey(t *testing.T) {
+	engine := NewCryptoEngine()
+
+	// 버전 1 스트림: 데이터 키로 청크를 직접 봉인
+	dataKey, err := engine.GenerateDataKey()
+	require.NoError(t, err)
+	slots, err := engine.NewKeySlots(dataKey, []string{StreamPassword})
+	require.NoError(t, err)
+	gcm, err := newGCM(dataKey)
+	require.NoError(t, err)
+
+	var stream bytes.Buffer
+	require.NoError(t, writeStreamHeader(&stream, &streamHeader{version: StreamFormatEnvelope, slots: slots}))
+	require.NoError(t, engine.writeChunk(&stream, gcm, nil, []byte(TestData), nil))
+	require.NoError(t, engine.writeChunk(&stream, gcm, nil, nil, finalChunkAAD))
+
+	assert.Equal(t, []byte(TestData), decryptToBytes(t, stream.Bytes(), StreamPassword))
+
+	// 새 스트림은 서브키 포맷 이상으로 기록
+	encrypted := encryptToBytes(t, []byte(TestData), StreamPassword)
+	assert.GreaterOrEqual(t, encrypted[len(StreamMagic)], StreamFormatSubKeys)
+}
+
+func TestEncryptedDataJSON_Version(t *testing.T) {
+	encData, err := NewCryptoEngine().Encrypt([]byte(TestData), OwnerPassword)
+	require.NoError(t, err)
+
+	encoded, err := json.Marshal(encData)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"version":1`)
+
+	var decoded EncryptedData
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, encData.Version, decoded.Version)
+
+	// 지원하지 않는 버전
+	err = decoded.UnmarshalJSON(bytes.Replace(encoded, []byte(`"version":1`), []byte(`"version":9`), 1))
+	assert.ErrorIs(t, err, ErrUnsupportedDataFormat)
+}
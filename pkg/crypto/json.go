@@ -0,0 +1,133 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements JSON encoding of encrypted payloads for the REST API.
+package crypto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidEncryptedPayload 잘못된 암호화 페이로드(JSON)
+var ErrInvalidEncryptedPayload = errors.New("잘못된 암호화 페이로드입니다")
+
+// encryptedDataJSON EncryptedData의 JSON 표현 (바이트 필드는 표준 base64 문자열)
+type encryptedDataJSON struct {
+	Version    int            `json:"version,omitempty"`
+	Salt       string         `json:"salt"`
+	Nonce      string         `json:"nonce"`
+	Ciphertext string         `json:"ciphertext"`
+	KeySlots   []*keySlotJSON `json:"key_slots,omitempty"`
+}
+
+// keySlotJSON KeySlot의 JSON 표현
+type keySlotJSON struct {
+	Iterations int    `json:"iterations"`
+	Salt       string `json:"salt"`
+	WrappedKey string `json:"wrapped_key"`
+}
+
+// MarshalJSON EncryptedData를 base64 필드를 가진 JSON으로 변환합니다
+func (ed *EncryptedData) MarshalJSON() ([]byte, error) {
+	payload := encryptedDataJSON{
+		Version:    int(ed.Version),
+		Salt:       base64.StdEncoding.EncodeToString(ed.Salt),
+		Nonce:      base64.StdEncoding.EncodeToString(ed.Nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(ed.Ciphertext),
+	}
+
+	for _, slot := range ed.KeySlots {
+		if slot == nil {
+			return nil, fmt.Errorf("%w: 빈 키 슬롯", ErrInvalidEncryptedPayload)
+		}
+		payload.KeySlots = append(payload.KeySlots, &keySlotJSON{
+			Iterations: slot.Iterations,
+			Salt:       base64.StdEncoding.EncodeToString(slot.Salt),
+			WrappedKey: base64.StdEncoding.EncodeToString(slot.WrappedKey),
+		})
+	}
+
+	return json.Marshal(payload)
+}
+
+// UnmarshalJSON JSON을 EncryptedData로 변환하고 크기를 검증합니다
+func (ed *EncryptedData) UnmarshalJSON(data []byte) error {
+	var payload encryptedDataJSON
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEncryptedPayload, err)
+	}
+
+	if payload.Version < 0 || payload.Version > int(CurrentDataFormat) {
+		return fmt.Errorf("%w: %w: %d", ErrInvalidEncryptedPayload, ErrUnsupportedDataFormat, payload.Version)
+	}
+
+	decoded := EncryptedData{Version: byte(payload.Version)}
+	var err error
+
+	if decoded.Salt, err = decodeBase64Field("salt", payload.Salt); err != nil {
+		return err
+	}
+	if decoded.Nonce, err = decodeBase64Field("nonce", payload.Nonce); err != nil {
+		return err
+	}
+	if decoded.Ciphertext, err = decodeBase64Field("ciphertext", payload.Ciphertext); err != nil {
+		return err
+	}
+
+	for i, slotPayload := range payload.KeySlots {
+		if slotPayload == nil {
+			return fmt.Errorf("%w: 빈 키 슬롯 %d", ErrInvalidEncryptedPayload, i)
+		}
+
+		slot := &KeySlot{Iterations: slotPayload.Iterations}
+		if slot.Salt, err = decodeBase64Field("key_slots.salt", slotPayload.Salt); err != nil {
+			return err
+		}
+		if slot.WrappedKey, err = decodeBase64Field("key_slots.wrapped_key", slotPayload.WrappedKey); err != nil {
+			return err
+		}
+		if err := slot.validate(); err != nil {
+			return fmt.Errorf("%w: 키 슬롯 %d: %w", ErrInvalidEncryptedPayload, i, err)
+		}
+
+		decoded.KeySlots = append(decoded.KeySlots, slot)
+	}
+
+	if err := decoded.validateSizes(); err != nil {
+		return err
+	}
+
+	*ed = decoded
+	return nil
+}
+
+// validateSizes 역직렬화된 필드 크기를 검증합니다
+func (ed *EncryptedData) validateSizes() error {
+	if len(ed.KeySlots) > MaxKeySlots {
+		return fmt.Errorf("%w: 키 슬롯이 너무 많습니다 (%d)", ErrInvalidEncryptedPayload, len(ed.KeySlots))
+	}
+
+	if len(ed.KeySlots) == 0 && len(ed.Salt) != SaltSize {
+		return fmt.Errorf("%w: 잘못된 salt 크기 %d (예상: %d)", ErrInvalidEncryptedPayload, len(ed.Salt), SaltSize)
+	}
+
+	if len(ed.Nonce) != NonceSize {
+		return fmt.Errorf("%w: 잘못된 nonce 크기 %d (예상: %d)", ErrInvalidEncryptedPayload, len(ed.Nonce), NonceSize)
+	}
+
+	if len(ed.Ciphertext) < GCMTagSize {
+		return fmt.Errorf("%w: 암호문이 너무 짧습니다 (%d)", ErrInvalidEncryptedPayload, len(ed.Ciphertext))
+	}
+
+	return nil
+}
+
+// decodeBase64Field 표준 base64 필드를 디코딩합니다
+func decodeBase64Field(name, value string) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s base64 디코딩 실패: %w", ErrInvalidEncryptedPayload, name, err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,74 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedData_JSONRoundTrip(t *testing.T) {
+	engine := NewCryptoEngine()
+
+	testCases := []struct {
+		name      string
+		passwords []string
+	}{
+		{name: "단일 패스워드", passwords: []string{"json-password"}},
+		{name: "다중 패스워드", passwords: []string{"json-password", "recovery-password"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encData, err := engine.Encrypt([]byte("small secret"), tc.passwords...)
+			require.NoError(t, err)
+
+			raw, err := json.Marshal(encData)
+			require.NoError(t, err)
+
+			// 바이트 필드는 표준 base64 문자열
+			var fields map[string]interface{}
+			require.NoError(t, json.Unmarshal(raw, &fields))
+			assert.Equal(t, base64.StdEncoding.EncodeToString(encData.Nonce), fields["nonce"])
+			assert.Equal(t, base64.StdEncoding.EncodeToString(encData.Ciphertext), fields["ciphertext"])
+
+			var decoded EncryptedData
+			require.NoError(t, json.Unmarshal(raw, &decoded))
+
+			plaintext, err := engine.Decrypt(&decoded, tc.passwords[len(tc.passwords)-1])
+			require.NoError(t, err)
+			assert.Equal(t, "small secret", string(plaintext))
+		})
+	}
+}
+
+func TestEncryptedData_UnmarshalJSON_Invalid(t *testing.T) {
+	salt := base64.StdEncoding.EncodeToString(make([]byte, SaltSize))
+	nonce := base64.StdEncoding.EncodeToString(make([]byte, NonceSize))
+	ciphertext := base64.StdEncoding.EncodeToString(make([]byte, GCMTagSize))
+
+	testCases := []struct {
+		name    string
+		payload string
+	}{
+		{name: "JSON 형식 오류", payload: `{"salt":`},
+		{name: "base64 아님", payload: `{"salt":"***","nonce":"` + nonce + `","ciphertext":"` + ciphertext + `"}`},
+		{name: "salt 크기 오류", payload: `{"salt":"AAAA","nonce":"` + nonce + `","ciphertext":"` + ciphertext + `"}`},
+		{name: "nonce 누락", payload: `{"salt":"` + salt + `","ciphertext":"` + ciphertext + `"}`},
+		{name: "암호문 너무 짧음", payload: `{"salt":"` + salt + `","nonce":"` + nonce + `","ciphertext":"AAAA"}`},
+		{
+			name:    "잘못된 키 슬롯",
+			payload: `{"nonce":"` + nonce + `","ciphertext":"` + ciphertext + `","key_slots":[{"iterations":1,"salt":"AAAA","wrapped_key":"AAAA"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var decoded EncryptedData
+			err := decoded.UnmarshalJSON([]byte(tc.payload))
+			assert.ErrorIs(t, err, ErrInvalidEncryptedPayload)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements envelope encryption key slots for multi-password access.
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// ErrNoMatchingKeySlot 패스워드로 열 수 있는 키 슬롯이 없음
+var ErrNoMatchingKeySlot = errors.New("일치하는 키 슬롯이 없습니다")
+
+// KeySlot 패스워드로 감싼 데이터 키(DEK) 한 개
+//
+// 슬롯마다 고유한 salt와 반복 횟수로 키 암호화 키(KEK)를 유도하고,
+// WrappedKey에는 nonce와 GCM으로 봉인된 데이터 키가 이어서 저장됩니다.
+type KeySlot struct {
+	Iterations int    `json:"iterations"`  // PBKDF2 반복 횟수
+	Salt       []byte `json:"salt"`        // KEK 유도용 Salt
+	WrappedKey []byte `json:"wrapped_key"` // nonce || 봉인된 데이터 키
+}
+
+// GenerateDataKey 새로운 랜덤 데이터 키를 생성합니다
+func (ce *CryptoEngine) GenerateDataKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("데이터 키 생성 실패: %w", err)
+	}
+	return key, nil
+}
+
+// WrapKey 패스워드에서 유도한 키로 데이터 키를 감싸 키 슬롯을 만듭니다
+func (ce *CryptoEngine) WrapKey(dataKey []byte, password string) (*KeySlot, error) {
+	secret := []byte(password)
+	defer ZeroBytes(secret)
+
+	return ce.wrapKey(dataKey, secret, PBKDF2Iterations)
+}
+
+// wrapKey 지정한 반복 횟수로 유도한 키로 데이터 키를 감쌉니다
+func (ce *CryptoEngine) wrapKey(dataKey, password []byte, iterations int) (*KeySlot, error) {
+	if len(dataKey) != KeySize {
+		return nil, fmt.Errorf("잘못된 데이터 키 크기: %d (예상: %d)", len(dataKey), KeySize)
+	}
+
+	if len(password) == 0 {
+		return nil, errors.New("패스워드가 필요합니다")
+	}
+
+	salt, err := ce.GenerateSalt()
+	if err != nil {
+		return nil, err
+	}
+
+	kek := ce.deriveKeyWithIterations(password, salt, iterations)
+	defer ZeroBytes(kek)
+
+	nonce, sealed, err := ce.seal(kek, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("데이터 키 봉인 실패: %w", err)
+	}
+
+	return &KeySlot{
+		Iterations: iterations,
+		Salt:       salt,
+		WrappedKey: append(nonce, sealed...),
+	}, nil
+}
+
+// UnwrapKey 키 슬롯에서 데이터 키를 꺼냅니다
+func (ce *CryptoEngine) UnwrapKey(slot *KeySlot, password string) ([]byte, error) {
+	secret := []byte(password)
+	defer ZeroBytes(secret)
+
+	return ce.unwrapKey(slot, secret)
+}
+
+// unwrapKey 바이트 패스워드로 키 슬롯에서 데이터 키를 꺼냅니다
+func (ce *CryptoEngine) unwrapKey(slot *KeySlot, password []byte) ([]byte, error) {
+	if err := slot.validate(); err != nil {
+		return nil, err
+	}
+
+	kek := ce.deriveKeyWithIterations(password, slot.Salt, slot.Iterations)
+	defer ZeroBytes(kek)
+
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := gcm.Open(nil, slot.WrappedKey[:NonceSize], slot.WrappedKey[NonceSize:], nil)
+	if err != nil {
+		return nil, ErrNoMatchingKeySlot
+	}
+
+	return dataKey, nil
+}
+
+// NewKeySlots 패스워드마다 데이터 키를 감싼 키 슬롯 목록을 만듭니다
+func (ce *CryptoEngine) NewKeySlots(dataKey []byte, passwords []string) ([]*KeySlot, error) {
+	secrets := passwordBytes(passwords)
+	defer zeroPasswords(secrets)
+
+	return ce.newKeySlots(dataKey, secrets, PBKDF2Iterations)
+}
+
+// newKeySlots 지정한 반복 횟수로 패스워드마다 키 슬롯을 만듭니다
+func (ce *CryptoEngine) newKeySlots(dataKey []byte, passwords [][]byte, iterations int) ([]*KeySlot, error) {
+	if err := validatePasswords(passwords); err != nil {
+		return nil, err
+	}
+
+	slots := make([]*KeySlot, 0, len(passwords))
+	for i, password := range passwords {
+		slot, err := ce.wrapKey(dataKey, password, iterations)
+		if err != nil {
+			return nil, fmt.Errorf("키 슬롯 %d 생성 실패: %w", i, err)
+		}
+		slots = append(slots, slot)
+	}
+
+	return slots, nil
+}
+
+// OpenKeySlots 패스워드로 열리는 슬롯을 찾아 데이터 키를 반환합니다
+func (ce *CryptoEngine) OpenKeySlots(slots []*KeySlot, password string) ([]byte, error) {
+	if password == "" {
+		return nil, errors.New("패스워드가 필요합니다")
+	}
+
+	if len(slots) == 0 || len(slots) > MaxKeySlots {
+		return nil, fmt.Errorf("잘못된 키 슬롯 수: %d", len(slots))
+	}
+
+	secret := []byte(password)
+	defer ZeroBytes(secret)
+
+	for _, slot := range slots {
+		dataKey, err := ce.unwrapKey(slot, secret)
+		if err == nil {
+			return dataKey, nil
+		}
+		if !errors.Is(err, ErrNoMatchingKeySlot) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("복호화 실패 (잘못된 패스워드 또는 손상된 데이터): %w", ErrNoMatchingKeySlot)
+}
+
+// validate 키 슬롯 구조를 검증합니다
+func (slot *KeySlot) validate() error {
+	if slot == nil {
+		return errors.New("키 슬롯이 없습니다")
+	}
+
+	if len(slot.Salt) != SaltSize {
+		return fmt.Errorf("잘못된 키 슬롯 salt 크기: %d (예상: %d)", len(slot.Salt), SaltSize)
+	}
+
+	if len(slot.WrappedKey) != WrappedKeySize {
+		return fmt.Errorf("잘못된 감싸진 키 크기: %d (예상: %d)", len(slot.WrappedKey), WrappedKeySize)
+	}
+
+	if slot.Iterations <= 0 || slot.Iterations > MaxSlotIterations {
+		return fmt.Errorf("잘못된 키 슬롯 반복 횟수: %d", slot.Iterations)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// 다중 패스워드 테스트용 상수
+const (
+	OwnerPassword    = "owner-password"
+	RecoveryPassword = "recovery-password"
+	ThirdPassword    = "third-password"
+)
+
+func TestWrapUnwrapKey(t *testing.T) {
+	engine := NewCryptoEngine()
+
+	dataKey, err := engine.GenerateDataKey()
+	require.NoError(t, err)
+	assert.Len(t, dataKey, KeySize)
+
+	slot, err := engine.WrapKey(dataKey, OwnerPassword)
+	require.NoError(t, err)
+	assert.Len(t, slot.Salt, SaltSize)
+	assert.Len(t, slot.WrappedKey, WrappedKeySize)
+	assert.Equal(t, PBKDF2Iterations, slot.Iterations)
+
+	unwrapped, err := engine.UnwrapKey(slot, OwnerPassword)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	_, err = engine.UnwrapKey(slot, ThirdPassword)
+	assert.ErrorIs(t, err, ErrNoMatchingKeySlot)
+}
+
+func TestNewKeySlots_UniqueSalts(t *testing.T) {
+	engine := NewCryptoEngine()
+
+	dataKey, err := engine.GenerateDataKey()
+	require.NoError(t, err)
+
+	slots, err := engine.NewKeySlots(dataKey, []string{OwnerPassword, RecoveryPassword})
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+	assert.NotEqual(t, slots[0].Salt, slots[1].Salt)
+}
+
+func TestEncrypt_MultiplePasswords(t *testing.T) {
+	engine := NewCryptoEngine()
+	data := []byte(TestData)
+
+	encData, err := engine.Encrypt(data, OwnerPassword, RecoveryPassword)
+	require.NoError(t, err)
+	require.Len(t, encData.KeySlots, 2)
+
+	for _, password := range []string{OwnerPassword, RecoveryPassword} {
+		decrypted, decErr := engine.Decrypt(encData, password)
+		require.NoError(t, decErr)
+		assert.Equal(t, data, decrypted)
+	}
+
+	_, err = engine.Decrypt(encData, ThirdPassword)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNoMatchingKeySlot)
+}
+
+func TestEncrypt_PasswordValidation(t *testing.T) {
+	engine := NewCryptoEngine()
+
+	_, err := engine.Encrypt([]byte(TestData))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "패스워드가 필요합니다")
+
+	_, err = engine.Encrypt([]byte(TestData), OwnerPassword, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "패스워드가 필요합니다")
+
+	tooMany := make([]string, MaxKeySlots+1)
+	for i := range tooMany {
+		tooMany[i] = OwnerPassword
+	}
+	_, err = engine.Encrypt([]byte(TestData), tooMany...)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "최대")
+}
+
+func TestEncryptStream_MultiplePasswords(t *testing.T) {
+	engine := NewCryptoEngine()
+	testData := []byte(strings.Repeat("multi recipient stream ", StreamTestRepeat))
+
+	var encryptedBuf bytes.Buffer
+	err := engine.EncryptStream(bytes.NewReader(testData), &encryptedBuf, OwnerPassword, RecoveryPassword)
+	require.NoError(t, err)
+
+	for _, password := range []string{OwnerPassword, RecoveryPassword} {
+		var decryptedBuf bytes.Buffer
+		decErr := engine.DecryptStream(bytes.NewReader(encryptedBuf.Bytes()), &decryptedBuf, password)
+		require.NoError(t, decErr)
+		assert.Equal(t, testData, decryptedBuf.Bytes())
+	}
+
+	var decryptedBuf bytes.Buffer
+	err = engine.DecryptStream(bytes.NewReader(encryptedBuf.Bytes()), &decryptedBuf, ThirdPassword)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNoMatchingKeySlot)
+	assert.Zero(t, decryptedBuf.Len())
+}
+
+func TestDecryptStream_Truncated(t *testing.T) {
+	engine := NewCryptoEngine()
+	testData := bytes.Repeat([]byte{0xAB}, ChunkSize+100)
+
+	var encryptedBuf bytes.Buffer
+	err := engine.EncryptStream(bytes.NewReader(testData), &encryptedBuf, OwnerPassword)
+	require.NoError(t, err)
+
+	// 종료 레코드를 잘라내면 잘림 에러가 나야 함
+	finalRecordSize := ChunkSizeBytes + GCMTagSize
+	truncated := encryptedBuf.Bytes()[:encryptedBuf.Len()-finalRecordSize]
+
+	err = engine.DecryptStream(bytes.NewReader(truncated), io.Discard, OwnerPassword)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrTruncatedStream)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestDecryptStream_UnsupportedVersion(t *testing.T) {
+	engine := NewCryptoEngine()
+
+	var encryptedBuf bytes.Buffer
+	err := engine.EncryptStream(bytes.NewReader([]byte(TestData)), &encryptedBuf, OwnerPassword)
+	require.NoError(t, err)
+
+	data := encryptedBuf.Bytes()
+	data[len(StreamMagic)] = 0xFF
+
+	err = engine.DecryptStream(bytes.NewReader(data), io.Discard, OwnerPassword)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUnsupportedStreamFormat))
+}
+
+func TestDecryptStream_LegacyFormat(t *testing.T) {
+	engine := NewCryptoEngine()
+	testData := []byte(strings.Repeat("legacy stream ", StreamTestRepeat))
+
+	legacy := encryptLegacyStream(t, engine, testData, StreamPassword)
+
+	var decryptedBuf bytes.Buffer
+	err := engine.DecryptStream(bytes.NewReader(legacy), &decryptedBuf, StreamPassword)
+	require.NoError(t, err)
+	assert.Equal(t, testData, decryptedBuf.Bytes())
+}
+
+// encryptLegacyStream 헤더 없는 레거시 포맷으로 데이터를 암호화합니다
+func encryptLegacyStream(t *testing.T, engine *CryptoEngine, data []byte, password string) []byte {
+	t.Helper()
+
+	salt, err := engine.GenerateSalt()
+	require.NoError(t, err)
+
+	key := pbkdf2.Key([]byte(password), salt, PBKDF2Iterations, KeySize, sha256.New)
+	gcm, err := newGCM(key)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	buf.Write(salt)
+	for offset := 0; offset < len(data); offset += ChunkSize {
+		end := min(offset+ChunkSize, len(data))
+
+		nonce, nonceErr := engine.GenerateNonce()
+		require.NoError(t, nonceErr)
+
+		ciphertext := gcm.Seal(nil, nonce, data[offset:end], nil)
+		buf.Write(nonce)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(ciphertext))))
+		buf.Write(ciphertext)
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,153 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file defines functional options for tuning encryption behaviour.
+package crypto
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 옵션 관련 상수
+const (
+	// 최소 청크 크기 (1 바이트)
+	MinChunkSize = 1
+
+	// DefaultMaxInMemoryEncryptSize Encrypt로 한 번에 암호화할 수 있는 기본 최대 크기 (32MB)
+	DefaultMaxInMemoryEncryptSize = 32 * 1024 * 1024
+)
+
+// Algorithm 데이터 암호화 알고리즘
+type Algorithm string
+
+// AlgorithmAES256GCM AES-256-GCM (현재 유일하게 지원하는 알고리즘)
+const AlgorithmAES256GCM Algorithm = "AES-256-GCM"
+
+// 옵션 관련 에러
+var (
+	// ErrInvalidOption NewCryptoEngine에 잘못된 옵션이 전달됨
+	ErrInvalidOption = errors.New("잘못된 암호화 엔진 옵션입니다")
+
+	// ErrUnsupportedAlgorithm 지원하지 않는 암호화 알고리즘
+	ErrUnsupportedAlgorithm = errors.New("지원하지 않는 암호화 알고리즘입니다")
+)
+
+// CryptoOptions 암호화 동작 설정
+type CryptoOptions struct {
+	// Algorithm 데이터 암호화 알고리즘 (기본값: AlgorithmAES256GCM)
+	Algorithm Algorithm
+
+	// ChunkSize 스트림 청크당 평문 크기 (MinChunkSize ~ ChunkSize)
+	ChunkSize int
+
+	// Compression 봉인 전 청크 압축 알고리즘 (기본값: CompressionNone)
+	Compression Compression
+
+	// Iterations 키 슬롯의 PBKDF2 반복 횟수 (기본값: PBKDF2Iterations)
+	Iterations int
+
+	// MaxInMemoryEncryptSize 메모리 암호화(Encrypt)로 허용하는 최대 평문 크기
+	// (기본값: DefaultMaxInMemoryEncryptSize, 스트림 암호화에는 적용되지 않음)
+	MaxInMemoryEncryptSize int
+}
+
+// Option CryptoOptions를 변경하는 함수형 옵션
+type Option func(*CryptoOptions) error
+
+// WithChunkSize 스트림 청크 크기를 지정합니다
+//
+// 복호화 측은 ChunkSize보다 큰 청크를 거부하므로 최대값은 ChunkSize입니다.
+func WithChunkSize(size int) Option {
+	return func(o *CryptoOptions) error {
+		if size < MinChunkSize || size > ChunkSize {
+			return fmt.Errorf("잘못된 청크 크기: %d (허용: %d ~ %d)", size, MinChunkSize, ChunkSize)
+		}
+		o.ChunkSize = size
+		return nil
+	}
+}
+
+// WithCompression 봉인 전에 청크를 압축합니다
+//
+// 이미 압축된 입력은 CompressionForMIME으로 건너뛸 수 있으며, 압축해도
+// 작아지지 않는 청크는 자동으로 원본 그대로 저장됩니다.
+func WithCompression(compression Compression) Option {
+	return func(o *CryptoOptions) error {
+		if !compression.valid() {
+			return fmt.Errorf("%w: %s", ErrUnsupportedCompression, compression)
+		}
+		o.Compression = compression
+		return nil
+	}
+}
+
+// WithIterations 키 슬롯의 PBKDF2 반복 횟수를 지정합니다
+//
+// 반복 횟수는 슬롯마다 헤더에 기록되므로 복호화 측에서는 지정할 필요가 없습니다.
+// 호스트에 맞는 값은 CalibrateIterations로 구할 수 있습니다.
+func WithIterations(iterations int) Option {
+	return func(o *CryptoOptions) error {
+		if iterations < MinIterations || iterations > MaxIterations {
+			return fmt.Errorf("잘못된 반복 횟수: %d (허용: %d ~ %d)", iterations, MinIterations, MaxIterations)
+		}
+		o.Iterations = iterations
+		return nil
+	}
+}
+
+// WithAlgorithm 데이터 암호화 알고리즘을 지정합니다
+//
+// 스트림 헤더와 EncryptedData에는 알고리즘이 기록되지 않으므로, 다른 알고리즘은
+// 포맷 버전을 올려 지원하기 전까지 ErrUnsupportedAlgorithm으로 거부합니다.
+func WithAlgorithm(algorithm Algorithm) Option {
+	return func(o *CryptoOptions) error {
+		if algorithm != AlgorithmAES256GCM {
+			return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+		}
+		o.Algorithm = algorithm
+		return nil
+	}
+}
+
+// WithMaxInMemoryEncryptSize 메모리 암호화로 허용하는 최대 평문 크기를 지정합니다
+//
+// Encrypt는 평문과 암호문을 모두 메모리에 올리므로, 이보다 큰 데이터는
+// ErrPayloadTooLarge로 거부하고 EncryptStream을 사용하도록 합니다.
+func WithMaxInMemoryEncryptSize(size int) Option {
+	return func(o *CryptoOptions) error {
+		if size < 1 {
+			return fmt.Errorf("잘못된 메모리 암호화 한도: %d", size)
+		}
+		o.MaxInMemoryEncryptSize = size
+		return nil
+	}
+}
+
+// defaultCryptoOptions 기본 설정을 반환합니다
+func defaultCryptoOptions() CryptoOptions {
+	return CryptoOptions{
+		Algorithm:   AlgorithmAES256GCM,
+		ChunkSize:   ChunkSize,
+		Compression: CompressionNone,
+		Iterations:  PBKDF2Iterations,
+
+		MaxInMemoryEncryptSize: DefaultMaxInMemoryEncryptSize,
+	}
+}
+
+// applyOptions 기본 설정에 옵션을 순서대로 적용합니다
+func applyOptions(opts []Option) (CryptoOptions, error) {
+	return applyOptionsTo(defaultCryptoOptions(), opts)
+}
+
+// applyOptionsTo 주어진 설정에 옵션을 순서대로 적용합니다 (첫 에러에서 중단)
+func applyOptionsTo(options CryptoOptions, opts []Option) (CryptoOptions, error) {
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(&options); err != nil {
+			return CryptoOptions{}, err
+		}
+	}
+	return options, nil
+}
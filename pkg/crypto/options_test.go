@@ -0,0 +1,84 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCryptoEngine_Defaults(t *testing.T) {
+	engine := NewCryptoEngine()
+	require.NoError(t, engine.Err())
+	assert.Equal(t, defaultCryptoOptions(), engine.options)
+}
+
+func TestNewCryptoEngine_WithOptions(t *testing.T) {
+	engine := NewCryptoEngine(WithIterations(MinIterations), WithChunkSize(4096), WithAlgorithm(AlgorithmAES256GCM))
+	require.NoError(t, engine.Err())
+
+	plaintext := []byte(strings.Repeat("engine options ", 1000))
+	var encrypted bytes.Buffer
+	require.NoError(t, engine.EncryptStream(bytes.NewReader(plaintext), &encrypted, StreamPassword))
+
+	info, err := ReadStreamInfo(bytes.NewReader(encrypted.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, MinIterations, info.KeySlots[0].Iterations)
+
+	// 엔진 청크 크기(4096)로 나뉘어 첫 청크 레코드 크기가 4096 + 태그
+	headerSize := len(StreamMagic) + streamHeaderFixedSize + keySlotHeaderSize
+	sizeField := encrypted.Bytes()[headerSize : headerSize+ChunkSizeBytes]
+	assert.Equal(t, uint32(4096+GCMTagSize), binary.BigEndian.Uint32(sizeField))
+
+	assert.Equal(t, plaintext, decryptToBytes(t, encrypted.Bytes(), StreamPassword))
+}
+
+func TestNewCryptoEngine_CallOptionsOverrideEngine(t *testing.T) {
+	engine := NewCryptoEngine(WithMaxInMemoryEncryptSize(8))
+
+	_, err := engine.Encrypt([]byte("0123456789"), TestPassword)
+	assert.ErrorIs(t, err, ErrPayloadTooLarge)
+
+	_, err = engine.EncryptWithOptions([]byte("0123456789"), []string{TestPassword}, WithMaxInMemoryEncryptSize(16))
+	assert.NoError(t, err)
+}
+
+func TestNewCryptoEngine_MultiPasswordEncryptUsesIterations(t *testing.T) {
+	engine := NewCryptoEngine(WithIterations(MinIterations))
+
+	encData, err := engine.Encrypt([]byte(TestData), TestPassword, "second-password")
+	require.NoError(t, err)
+	require.Len(t, encData.KeySlots, 2)
+	assert.Equal(t, MinIterations, encData.KeySlots[0].Iterations)
+
+	decrypted, err := NewCryptoEngine().Decrypt(encData, "second-password")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(TestData), decrypted)
+}
+
+func TestNewCryptoEngine_InvalidOptions(t *testing.T) {
+	engine := NewCryptoEngine(WithChunkSize(0), WithAlgorithm("ChaCha20-Poly1305"), WithIterations(MinIterations))
+
+	// 잘못된 옵션의 에러를 모두 모음
+	err := engine.Err()
+	require.ErrorIs(t, err, ErrInvalidOption)
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	assert.Contains(t, err.Error(), "잘못된 청크 크기")
+
+	// 암호화 호출은 같은 에러를 반환
+	_, err = engine.Encrypt([]byte(TestData), TestPassword)
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	err = engine.EncryptStream(strings.NewReader(TestData), &bytes.Buffer{}, StreamPassword)
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	// 복호화는 옵션과 무관
+	encData, err := NewCryptoEngine().Encrypt([]byte(TestData), TestPassword)
+	require.NoError(t, err)
+	decrypted, err := engine.Decrypt(encData, TestPassword)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(TestData), decrypted)
+}
@@ -0,0 +1,337 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements an io.Reader that decrypts the stream format lazily.
+package crypto
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// 인증 실패 에러
+//
+// ErrWrongPassword와 ErrCorruptedChunk로 보고되는 에러는 모두
+// errors.Is(err, ErrAuthenticationFailed)도 성립합니다.
+var (
+	// ErrAuthenticationFailed 청크 또는 키 슬롯 인증 실패 (잘못된 패스워드 또는 변조)
+	ErrAuthenticationFailed = errors.New("인증 실패 (잘못된 패스워드 또는 손상된 데이터)")
+
+	// ErrWrongPassword 헤더의 키 슬롯 중 패스워드로 열리는 것이 없음
+	ErrWrongPassword = errors.New("패스워드가 일치하지 않습니다")
+
+	// ErrCorruptedChunk 패스워드는 맞지만 청크 인증에 실패함 (손상 또는 변조)
+	ErrCorruptedChunk = errors.New("손상된 청크입니다")
+)
+
+// authError 인증 실패의 종류(ErrWrongPassword, ErrCorruptedChunk)와 원인을 함께 담는 에러
+type authError struct {
+	kind  error
+	cause error
+}
+
+// Error 종류와 원인을 이어 붙인 메시지를 반환합니다
+func (e *authError) Error() string {
+	return e.kind.Error() + ": " + e.cause.Error()
+}
+
+// Unwrap 종류, ErrAuthenticationFailed, 원인을 모두 반환합니다
+func (e *authError) Unwrap() []error {
+	return []error{e.kind, ErrAuthenticationFailed, e.cause}
+}
+
+// decryptReader 원본 스트림에서 청크를 하나씩 읽어 검증 후 평문을 제공하는 Reader
+type decryptReader struct {
+	engine     *CryptoEngine
+	src        io.Reader // 레코드를 읽는 Reader (MAC 포맷이면 MAC 계산을 거침)
+	raw        io.Reader // 원본 Reader (MAC 트레일러 읽기용)
+	password   string
+	gcm        cipher.AEAD
+	mac        hash.Hash          // 버전 3 이상에서만 사용
+	unzip      *chunkDecompressor // 압축 스트림에서만 사용
+	nonces     *chunkNonces       // 버전 5 이상에서만 사용 (없으면 레코드에서 nonce를 읽음)
+	legacy     bool
+	started    bool
+	opened     bool // 청크를 하나 이상 인증했는지 (레거시 포맷의 패스워드 판별용)
+	nonce      []byte
+	sizeBytes  []byte
+	ciphertext []byte
+	plaintext  []byte
+	pending    []byte // 아직 반환하지 않은 평문
+	err        error  // 이후 Read에 계속 반환할 에러 (정상 종료 시 io.EOF)
+}
+
+// NewDecryptReader src의 암호화 스트림을 복호화해 제공하는 Reader를 생성합니다
+//
+// 헤더와 청크는 첫 Read부터 필요한 만큼만 읽으며, 인증을 통과한 청크의 평문만
+// 반환합니다. 잘못된 패스워드는 청크를 읽기 전 첫 Read에서 ErrWrongPassword로,
+// 인증에 실패한 청크는 ErrCorruptedChunk로, 종료 레코드 전에 끝난 스트림은
+// io.ErrUnexpectedEOF로 보고됩니다. MAC 트레일러는
+// 마지막 청크 이후에 확인하므로 불일치(ErrIntegrityCheckFailed)는 io.EOF 대신
+// 마지막 Read에서 보고됩니다.
+func NewDecryptReader(src io.Reader, password string) (io.Reader, error) {
+	return NewCryptoEngine().newDecryptReader(src, password)
+}
+
+// newDecryptReader 복호화 Reader를 생성합니다
+func (ce *CryptoEngine) newDecryptReader(src io.Reader, password string) (*decryptReader, error) {
+	if src == nil {
+		return nil, errors.New("입력 스트림이 필요합니다")
+	}
+
+	if password == "" {
+		return nil, errors.New("패스워드가 필요합니다")
+	}
+
+	return &decryptReader{
+		engine:    ce,
+		src:       src,
+		raw:       src,
+		password:  password,
+		nonce:     make([]byte, NonceSize),
+		sizeBytes: make([]byte, ChunkSizeBytes),
+	}, nil
+}
+
+// Read 복호화된 평문을 p에 채웁니다
+func (r *decryptReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		if !r.started {
+			r.started = true
+			r.err = r.init()
+			continue
+		}
+
+		r.err = r.nextChunk()
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// init 포맷을 판별하고 헤더를 읽어 청크 복호화를 준비합니다
+func (r *decryptReader) init() error {
+	prefix := make([]byte, len(StreamMagic))
+	if _, err := io.ReadFull(r.src, prefix); err != nil {
+		return fmt.Errorf("salt 읽기 실패: %w", err)
+	}
+
+	if string(prefix) != StreamMagic {
+		// 레거시 포맷: 읽은 바이트는 salt의 앞부분
+		return r.initLegacy(io.MultiReader(bytes.NewReader(prefix), r.src))
+	}
+
+	// MAC 계산을 위해 헤더 원문을 보관
+	var headerBytes bytes.Buffer
+	header, err := readStreamHeader(io.TeeReader(r.src, &headerBytes))
+	if err != nil {
+		return err
+	}
+
+	dataKey, err := r.engine.OpenKeySlots(header.slots, r.password)
+	if err != nil {
+		if errors.Is(err, ErrNoMatchingKeySlot) {
+			return &authError{kind: ErrWrongPassword, cause: err}
+		}
+		return err
+	}
+	defer ZeroBytes(dataKey)
+
+	// 버전 2부터는 암호화 서브키 사용 (버전 1은 데이터 키를 그대로 사용)
+	// 버전 6부터는 서브키가 헤더 원문에 묶여 헤더를 바꾸면 청크 인증에 실패함
+	key := dataKey
+	if header.version >= StreamFormatSubKeys {
+		rawHeader := append(prefix, headerBytes.Bytes()...)
+		encKey, macKey := streamSubKeys(dataKey, header, rawHeader)
+		defer ZeroBytes(encKey)
+		key = encKey
+
+		// 버전 3부터는 매직 바이트부터 종료 레코드까지 MAC 계산
+		if header.version >= StreamFormatMAC {
+			r.mac = hmac.New(sha256.New, macKey)
+			r.mac.Write(prefix)
+			r.mac.Write(headerBytes.Bytes())
+			r.src = io.TeeReader(r.raw, r.mac)
+		}
+		ZeroBytes(macKey)
+	}
+
+	if header.compression != CompressionNone {
+		r.unzip = &chunkDecompressor{}
+	}
+
+	if header.noncePrefix != nil {
+		r.nonces = newChunkNonces(header.noncePrefix)
+	}
+
+	r.gcm, err = newGCM(key)
+	return err
+}
+
+// initLegacy 헤더 없는 레거시 포맷의 salt로 키를 유도합니다
+func (r *decryptReader) initLegacy(src io.Reader) error {
+	salt := make([]byte, SaltSize)
+	if _, err := io.ReadFull(src, salt); err != nil {
+		return fmt.Errorf("salt 읽기 실패: %w", err)
+	}
+
+	key := r.engine.DeriveKey(r.password, salt)
+	defer ZeroBytes(key)
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	r.src = src
+	r.gcm = gcm
+	r.legacy = true
+	return nil
+}
+
+// nextChunk 다음 청크 레코드를 읽어 복호화합니다
+//
+// 스트림이 정상 종료되면 io.EOF를 반환합니다.
+func (r *decryptReader) nextChunk() error {
+	if err := r.readNonce(); err != nil {
+		return err
+	}
+
+	// 청크 크기 읽기
+	if _, err := io.ReadFull(r.src, r.sizeBytes); err != nil {
+		return r.readError(err, "청크 크기 읽기 실패")
+	}
+
+	chunkSize := binary.BigEndian.Uint32(r.sizeBytes)
+	if !r.legacy && (chunkSize < GCMTagSize || chunkSize > r.maxSealedChunk()) {
+		return fmt.Errorf("잘못된 청크 크기: %d", chunkSize)
+	}
+
+	// 암호화된 데이터 읽기
+	if uint32(cap(r.ciphertext)) < chunkSize {
+		r.ciphertext = make([]byte, chunkSize)
+	}
+	ciphertext := r.ciphertext[:chunkSize]
+	if _, err := io.ReadFull(r.src, ciphertext); err != nil {
+		return r.readError(err, "암호화된 데이터 읽기 실패")
+	}
+
+	// 종료 레코드 확인
+	if !r.legacy && chunkSize == GCMTagSize {
+		if _, err := r.gcm.Open(nil, r.nonce, ciphertext, finalChunkAAD); err == nil {
+			if err := r.verifyTrailer(); err != nil {
+				return err
+			}
+			if err := expectEOF(r.raw); err != nil {
+				return err
+			}
+			return io.EOF
+		}
+	}
+
+	// 복호화 (평문 버퍼 재사용)
+	plaintext, err := r.gcm.Open(r.plaintext[:0], r.nonce, ciphertext, nil)
+	if err != nil {
+		return r.chunkAuthError(err)
+	}
+	r.opened = true
+
+	r.plaintext = plaintext
+	r.pending = plaintext
+
+	if r.unzip != nil {
+		if r.pending, err = r.unzip.decode(plaintext, ChunkSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readNonce 다음 레코드의 nonce를 준비합니다
+//
+// 버전 5 이상은 카운터로 만들고, 그 외에는 레코드 앞의 nonce를 읽습니다.
+// 종료 레코드인지는 복호화해 봐야 알 수 있으므로 카운터의 마지막 값까지 허용합니다.
+func (r *decryptReader) readNonce() error {
+	if r.nonces != nil {
+		nonce, err := r.nonces.next(true)
+		if err != nil {
+			return err
+		}
+		copy(r.nonce, nonce)
+		return nil
+	}
+
+	if _, err := io.ReadFull(r.src, r.nonce); err != nil {
+		if r.legacy {
+			// 레거시 포맷은 종료 레코드가 없으므로 레코드 경계의 EOF가 정상 종료
+			if err == io.EOF {
+				return io.EOF
+			}
+			return fmt.Errorf("nonce 읽기 실패: %w", err)
+		}
+		return truncatedOr(err, "nonce 읽기 실패")
+	}
+	return nil
+}
+
+// chunkAuthError 청크 인증 실패를 에러 종류에 맞게 변환합니다
+//
+// 레거시 포맷은 헤더에 키 슬롯이 없어 첫 청크가 실패하면 패스워드 오류인지
+// 손상인지 구분할 수 없으므로 ErrAuthenticationFailed만 반환합니다.
+func (r *decryptReader) chunkAuthError(err error) error {
+	if r.legacy && !r.opened {
+		return fmt.Errorf("복호화 실패: %w: %w", ErrAuthenticationFailed, err)
+	}
+	return &authError{kind: ErrCorruptedChunk, cause: fmt.Errorf("복호화 실패: %w", err)}
+}
+
+// maxSealedChunk 봉인된 청크 레코드의 최대 크기를 반환합니다
+func (r *decryptReader) maxSealedChunk() uint32 {
+	if r.unzip != nil {
+		return ChunkSize + chunkFlagSize + GCMTagSize
+	}
+	return ChunkSize + GCMTagSize
+}
+
+// verifyTrailer 종료 레코드 뒤의 MAC 트레일러를 확인합니다 (MAC 포맷만 해당)
+func (r *decryptReader) verifyTrailer() error {
+	if r.mac == nil {
+		return nil
+	}
+
+	trailer := make([]byte, MACTrailerSize)
+	if _, err := io.ReadFull(r.raw, trailer); err != nil {
+		return truncatedOr(err, "MAC 트레일러 읽기 실패")
+	}
+
+	if !hmac.Equal(trailer, r.mac.Sum(nil)) {
+		return ErrIntegrityCheckFailed
+	}
+
+	return nil
+}
+
+// readError 레코드 중간의 읽기 에러를 포맷에 맞게 변환합니다
+func (r *decryptReader) readError(err error, message string) error {
+	if r.legacy {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("%s: %w", message, err)
+	}
+	return truncatedOr(err, message)
+}
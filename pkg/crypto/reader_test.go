@@ -0,0 +1,178 @@
+package crypto
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptToBytes 평문을 현재 스트림 포맷으로 암호화합니다
+func encryptToBytes(t *testing.T, plaintext []byte, password string) []byte {
+	t.Helper()
+	var encrypted bytes.Buffer
+	require.NoError(t, NewCryptoEngine().EncryptStream(bytes.NewReader(plaintext), &encrypted, password))
+	return encrypted.Bytes()
+}
+
+// readAllWithBuffer 고정 크기 버퍼로 Reader를 끝까지 읽습니다
+func readAllWithBuffer(r io.Reader, size int) ([]byte, error) {
+	var out bytes.Buffer
+	buf := make([]byte, size)
+	for {
+		n, err := r.Read(buf)
+		out.Write(buf[:n])
+		if err == io.EOF {
+			return out.Bytes(), nil
+		}
+		if err != nil {
+			return out.Bytes(), err
+		}
+	}
+}
+
+func TestNewDecryptReader_BufferSizes(t *testing.T) {
+	// 여러 청크에 걸치고 마지막 청크가 부분 청크인 평문
+	plaintext := []byte(strings.Repeat("decrypt reader payload ", ChunkSize/8))
+	encrypted := encryptToBytes(t, plaintext, StreamPassword)
+
+	for _, size := range []int{1, 4 * 1024, 8 * 1024 * 1024} {
+		decReader, err := NewDecryptReader(bytes.NewReader(encrypted), StreamPassword)
+		require.NoError(t, err)
+
+		decrypted, err := readAllWithBuffer(decReader, size)
+		require.NoError(t, err, "buffer size %d", size)
+		assert.Equal(t, plaintext, decrypted, "buffer size %d", size)
+
+		// 끝난 뒤에도 계속 EOF
+		n, err := decReader.Read(make([]byte, 1))
+		assert.Zero(t, n)
+		assert.Equal(t, io.EOF, err)
+	}
+}
+
+func TestNewDecryptReader_IOCopy(t *testing.T) {
+	plaintext := []byte(strings.Repeat("copy ", 1000))
+	decReader, err := NewDecryptReader(bytes.NewReader(encryptToBytes(t, plaintext, StreamPassword)), StreamPassword)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	_, err = io.Copy(&out, decReader)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, out.Bytes())
+}
+
+func TestNewDecryptReader_WrongPassword(t *testing.T) {
+	encrypted := encryptToBytes(t, []byte("secret data"), StreamPassword)
+
+	// 생성은 성공하고 첫 Read에서 인증 실패
+	decReader, err := NewDecryptReader(bytes.NewReader(encrypted), "wrongpassword")
+	require.NoError(t, err)
+
+	n, err := decReader.Read(make([]byte, 64))
+	assert.Zero(t, n)
+	assert.ErrorIs(t, err, ErrAuthenticationFailed)
+
+	assert.ErrorIs(t, err, ErrWrongPassword)
+	assert.NotErrorIs(t, err, ErrCorruptedChunk)
+
+	// 에러는 유지됨
+	_, err = decReader.Read(make([]byte, 64))
+	assert.ErrorIs(t, err, ErrAuthenticationFailed)
+}
+
+func TestNewDecryptReader_TamperedChunk(t *testing.T) {
+	encrypted := encryptToBytes(t, []byte("tamper with me"), StreamPassword)
+
+	// 첫 청크의 암호문 바이트 변조
+	headerSize := len(StreamMagic) + streamHeaderFixedSize + keySlotHeaderSize
+	encrypted[headerSize+ChunkSizeBytes] ^= 0xFF
+
+	decReader, err := NewDecryptReader(bytes.NewReader(encrypted), StreamPassword)
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(decReader)
+	assert.ErrorIs(t, err, ErrAuthenticationFailed)
+	assert.ErrorIs(t, err, ErrCorruptedChunk)
+	assert.NotErrorIs(t, err, ErrWrongPassword)
+}
+
+func TestNewDecryptReader_Truncated(t *testing.T) {
+	plaintext := []byte(strings.Repeat("truncate ", ChunkSize/4))
+	encrypted := encryptToBytes(t, plaintext, StreamPassword)
+	headerSize := len(StreamMagic) + streamHeaderFixedSize + keySlotHeaderSize
+	finalRecordSize := ChunkSizeBytes + GCMTagSize
+
+	testCases := []struct {
+		name   string
+		length int
+	}{
+		{name: "헤더 직후", length: headerSize},
+		{name: "청크 중간", length: headerSize + ChunkSizeBytes + 100},
+		{name: "종료 레코드 없음", length: len(encrypted) - finalRecordSize},
+		{name: "종료 레코드 중간", length: len(encrypted) - 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decReader, err := NewDecryptReader(bytes.NewReader(encrypted[:tc.length]), StreamPassword)
+			require.NoError(t, err)
+
+			_, err = readAllWithBuffer(decReader, 4*1024)
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+			assert.ErrorIs(t, err, ErrTruncatedStream)
+		})
+	}
+}
+
+func TestNewDecryptReader_LegacyFormat(t *testing.T) {
+	engine := NewCryptoEngine()
+	plaintext := []byte(strings.Repeat("legacy reader ", StreamTestRepeat))
+	legacy := encryptLegacyStream(t, engine, plaintext, StreamPassword)
+
+	decReader, err := NewDecryptReader(bytes.NewReader(legacy), StreamPassword)
+	require.NoError(t, err)
+
+	decrypted, err := readAllWithBuffer(decReader, 4*1024)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	// 레코드 중간에서 잘린 레거시 스트림
+	decReader, err = NewDecryptReader(bytes.NewReader(legacy[:len(legacy)-1]), StreamPassword)
+	require.NoError(t, err)
+	_, err = io.ReadAll(decReader)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestNewDecryptReader_LegacyAuthenticationErrors(t *testing.T) {
+	engine := NewCryptoEngine()
+	plaintext := []byte(strings.Repeat("legacy auth ", ChunkSize/4))
+	legacy := encryptLegacyStream(t, engine, plaintext, StreamPassword)
+
+	// 첫 청크 실패는 패스워드 오류와 손상을 구분할 수 없음
+	decReader, err := NewDecryptReader(bytes.NewReader(legacy), "wrongpassword")
+	require.NoError(t, err)
+	_, err = io.ReadAll(decReader)
+	assert.ErrorIs(t, err, ErrAuthenticationFailed)
+	assert.NotErrorIs(t, err, ErrWrongPassword)
+	assert.NotErrorIs(t, err, ErrCorruptedChunk)
+
+	// 첫 청크가 인증되면 이후 실패는 손상으로 판단
+	tampered := append([]byte(nil), legacy...)
+	tampered[len(tampered)-1] ^= 0xFF
+	decReader, err = NewDecryptReader(bytes.NewReader(tampered), StreamPassword)
+	require.NoError(t, err)
+	_, err = io.ReadAll(decReader)
+	assert.ErrorIs(t, err, ErrCorruptedChunk)
+}
+
+func TestNewDecryptReader_InvalidInput(t *testing.T) {
+	_, err := NewDecryptReader(nil, StreamPassword)
+	assert.Error(t, err)
+
+	_, err = NewDecryptReader(bytes.NewReader(nil), "")
+	assert.Error(t, err)
+}
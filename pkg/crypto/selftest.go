@@ -0,0 +1,100 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements the startup self-test of the crypto stack.
+package crypto
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// 자체 점검 관련 상수
+const (
+	// PBKDF2-HMAC-SHA256 기준값 (P="password", S="salt", c=4096, dkLen=32)
+	selfTestKDFPassword   = "password"
+	selfTestKDFSalt       = "salt"
+	selfTestKDFIterations = 4096
+	selfTestKDFExpected   = "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"
+
+	// 왕복 검사에 쓰는 패스워드 (키 슬롯 반복 횟수는 MinIterations)
+	selfTestPassword      = "datalocker-self-test"
+	selfTestWrongPassword = "datalocker-self-test-wrong"
+
+	// 왕복 검사 청크 크기 (여러 청크와 종료 레코드를 거치도록 작게 설정)
+	selfTestChunkSize = 64
+)
+
+// ErrSelfTestFailed 암호화 자체 점검 실패
+var ErrSelfTestFailed = errors.New("암호화 자체 점검에 실패했습니다")
+
+var (
+	selfTestOnce   sync.Once
+	selfTestResult error
+)
+
+// SelfTest 암호화 스택이 올바르게 동작하는지 점검합니다
+//
+// 다음을 차례로 확인하고, 실패하면 어느 단계인지 담은 ErrSelfTestFailed를 반환합니다:
+//
+//   - PBKDF2-HMAC-SHA256 기준값 유도 (RFC 7914 테스트 벡터)
+//   - 난수 생성기(crypto/rand)를 거치는 스트림 암호화/복호화 왕복
+//   - 틀린 패스워드가 ErrWrongPassword로 거부되는지
+//
+// 키 슬롯 반복 횟수를 MinIterations로 낮춰 수 밀리초 안에 끝납니다.
+func SelfTest() error {
+	engine := NewCryptoEngine(WithIterations(MinIterations), WithChunkSize(selfTestChunkSize))
+
+	if err := selfTestKDF(engine); err != nil {
+		return fmt.Errorf("%w: PBKDF2: %w", ErrSelfTestFailed, err)
+	}
+
+	plaintext := bytes.Repeat([]byte("DataLocker self-test "), 16)
+	var encrypted bytes.Buffer
+	if err := engine.EncryptStream(bytes.NewReader(plaintext), &encrypted, selfTestPassword); err != nil {
+		return fmt.Errorf("%w: 암호화: %w", ErrSelfTestFailed, err)
+	}
+
+	var decrypted bytes.Buffer
+	if err := engine.DecryptStream(bytes.NewReader(encrypted.Bytes()), &decrypted, selfTestPassword); err != nil {
+		return fmt.Errorf("%w: 복호화: %w", ErrSelfTestFailed, err)
+	}
+	if !bytes.Equal(decrypted.Bytes(), plaintext) {
+		return fmt.Errorf("%w: 복호화 결과가 원본과 다릅니다", ErrSelfTestFailed)
+	}
+
+	err := engine.DecryptStream(bytes.NewReader(encrypted.Bytes()), &bytes.Buffer{}, selfTestWrongPassword)
+	if !errors.Is(err, ErrWrongPassword) {
+		return fmt.Errorf("%w: 틀린 패스워드가 거부되지 않았습니다 (결과: %v)", ErrSelfTestFailed, err)
+	}
+
+	return nil
+}
+
+// CachedSelfTest 프로세스에서 처음 호출할 때 SelfTest를 실행하고 그 결과를 재사용합니다
+//
+// 서버 시작 시 점검과 준비 상태(/ready) 체크가 같은 결과를 공유하는 데 사용합니다.
+func CachedSelfTest() error {
+	selfTestOnce.Do(func() {
+		selfTestResult = SelfTest()
+	})
+	return selfTestResult
+}
+
+// selfTestKDF PBKDF2 유도 결과를 기준값과 비교합니다
+func selfTestKDF(engine *CryptoEngine) error {
+	expected, err := hex.DecodeString(selfTestKDFExpected)
+	if err != nil {
+		return fmt.Errorf("기준값 해석 실패: %w", err)
+	}
+
+	key := engine.deriveKeyWithIterations([]byte(selfTestKDFPassword), []byte(selfTestKDFSalt), selfTestKDFIterations)
+	defer ZeroBytes(key)
+
+	if subtle.ConstantTimeCompare(key, expected) != 1 {
+		return errors.New("유도한 키가 기준값과 다릅니다")
+	}
+	return nil
+}
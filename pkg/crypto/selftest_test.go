@@ -0,0 +1,26 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	require.NoError(t, SelfTest())
+
+	// 캐시된 결과는 여러 번 호출해도 같음
+	assert.NoError(t, CachedSelfTest())
+	assert.NoError(t, CachedSelfTest())
+}
+
+func TestSelfTestKDF(t *testing.T) {
+	engine := NewCryptoEngine()
+	require.NoError(t, selfTestKDF(engine))
+
+	// 기준값과 다른 반복 횟수로 유도하면 다른 키가 나와야 함
+	key := engine.deriveKeyWithIterations([]byte(selfTestKDFPassword), []byte(selfTestKDFSalt), selfTestKDFIterations+1)
+	assert.NotEqual(t, selfTestKDFExpected, hex.EncodeToString(key))
+}
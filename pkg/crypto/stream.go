@@ -0,0 +1,461 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements the chunked stream format used for large files.
+package crypto
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// 스트림 포맷 관련 상수
+//
+// 레거시 포맷 (헤더 없음):
+//
+//	salt(32) | [nonce(12) | len(4) | ciphertext]...
+//
+// 봉투 포맷 (버전 1, 2, 3, 4, 5, 6):
+//
+//	"DLKR" | version(1) | [compression(1)] | [noncePrefix(8)] | slotCount(1) | [iterations(4) | salt(32) | wrappedKey(60)]...
+//	[[nonce(12)] | len(4) | ciphertext]... | 종료 레코드 | [MAC 트레일러(32)]
+//
+// 버전 1은 데이터 키로 청크를 직접 봉인하고, 버전 2부터는 데이터 키에서 HKDF로
+// 유도한 암호화 서브키(DeriveSubKeys)로 봉인합니다. 버전 3은 매직 바이트부터
+// 종료 레코드까지 전체를 MAC 서브키로 계산한 HMAC-SHA256 트레일러를 덧붙여
+// 청크 순서 변경이나 헤더 교체까지 감지합니다. 버전 4는 헤더에 압축 알고리즘을
+// 기록하며, 압축을 사용하면 각 청크 평문이 flag(1) | 본문 형태가 됩니다.
+// 버전 5는 청크마다 랜덤 nonce를 기록하는 대신 헤더의 nonce prefix 뒤에 빅엔디안
+// 청크 카운터(4)를 붙여 nonce를 만듭니다. 레코드에서 nonce가 빠져 청크당 12바이트를
+// 줄이고, 청크 순서를 바꾸면 nonce가 달라져 인증에 실패합니다. 종료 레코드도 다음
+// 카운터를 사용하므로 한 스트림의 청크는 종료 레코드를 포함해 최대 2^32개입니다.
+// 버전 6은 버전 5와 배치가 같지만, 서브키를 헤더 원문의 해시를 info에 넣어
+// 유도합니다(deriveStreamSubKeys). 버전 5 이하는 서브키가 헤더와 무관해 버전
+// 바이트만 낮추면 MAC 트레일러나 카운터 nonce 검사를 건너뛸 수 있었지만, 버전 6
+// 스트림의 헤더를 바꾸면 키가 달라져 청크 인증에 실패합니다.
+//
+// 종료 레코드는 빈 평문을 finalChunkAAD로 봉인한 레코드이며, 이 레코드가 없으면
+// 스트림이 잘린 것으로 판단합니다.
+const (
+	// StreamMagic 버전 헤더가 있는 스트림의 시작 표식
+	StreamMagic = "DLKR"
+
+	// StreamFormatLegacy 헤더 없이 salt로 시작하는 초기 포맷
+	StreamFormatLegacy byte = 0
+
+	// StreamFormatEnvelope 키 슬롯 헤더와 종료 레코드를 갖는 봉투 암호화 포맷
+	StreamFormatEnvelope byte = 1
+
+	// StreamFormatSubKeys 데이터 키에서 HKDF로 유도한 암호화 서브키로 청크를 봉인하는 포맷
+	StreamFormatSubKeys byte = 2
+
+	// StreamFormatMAC 전체 암호문에 대한 HMAC 트레일러를 갖는 포맷
+	StreamFormatMAC byte = 3
+
+	// StreamFormatCompression 헤더에 청크 압축 알고리즘을 기록하는 포맷
+	StreamFormatCompression byte = 4
+
+	// StreamFormatCounterNonce 헤더의 nonce prefix와 청크 카운터로 nonce를 만드는 포맷
+	StreamFormatCounterNonce byte = 5
+
+	// StreamFormatHeaderBound 헤더 원문에 묶인 서브키로 청크와 MAC을 만드는 포맷
+	StreamFormatHeaderBound byte = 6
+
+	// CurrentStreamFormat 새로 암호화할 때 사용하는 포맷
+	CurrentStreamFormat = StreamFormatHeaderBound
+
+	// MACTrailerSize MAC 트레일러 크기 (HMAC-SHA256)
+	MACTrailerSize = sha256.Size
+
+	// NoncePrefixSize 버전 5 헤더에 기록되는 nonce prefix 크기 (나머지 4바이트는 청크 카운터)
+	NoncePrefixSize = 8
+
+	// maxChunkCounter 청크 카운터의 최댓값 (종료 레코드용)
+	maxChunkCounter = 1<<32 - 1
+
+	// streamHeaderFixedSize 현재 포맷 헤더의 고정 필드 크기 (version, compression, noncePrefix, slotCount)
+	streamHeaderFixedSize = 3 + NoncePrefixSize
+
+	// keySlotHeaderSize 헤더에 기록되는 키 슬롯 하나의 크기
+	keySlotHeaderSize = ChunkSizeBytes + SaltSize + WrappedKeySize
+)
+
+// 청크 nonce 방식 (버전 6 서브키 유도 info에 기록)
+const (
+	// nonceModeCounter 헤더의 nonce prefix와 청크 카운터로 nonce를 만듦 (버전 5 이상)
+	nonceModeCounter = "counter-nonce"
+
+	// nonceModeRecord 레코드마다 기록된 랜덤 nonce를 읽음 (버전 4 이하)
+	nonceModeRecord = "record-nonce"
+)
+
+// finalChunkAAD 종료 레코드를 일반 청크와 구분하기 위한 추가 인증 데이터
+var finalChunkAAD = []byte("DLKR-final")
+
+// 스트림 관련 에러
+var (
+	// ErrUnsupportedStreamFormat 지원하지 않는 스트림 포맷 버전
+	ErrUnsupportedStreamFormat = errors.New("지원하지 않는 스트림 포맷 버전입니다")
+
+	// ErrTruncatedStream 종료 레코드 전에 스트림이 끝남
+	ErrTruncatedStream = errors.New("스트림이 중간에 잘렸습니다")
+
+	// ErrIntegrityCheckFailed MAC 트레일러 불일치 (변조 또는 손상)
+	ErrIntegrityCheckFailed = errors.New("스트림 무결성 검증에 실패했습니다")
+
+	// ErrChunkCounterOverflow 청크 수가 카운터 nonce로 만들 수 있는 범위(2^32)를 넘음
+	ErrChunkCounterOverflow = errors.New("스트림의 청크 수가 너무 많습니다")
+)
+
+// streamHeader 버전 헤더의 내용
+type streamHeader struct {
+	version     byte
+	compression Compression // 버전 4 이상에서만 기록
+	noncePrefix []byte      // 버전 5 이상에서만 기록
+	slots       []*KeySlot
+}
+
+// chunkNonces 버전 5 스트림의 청크 nonce를 noncePrefix(8) | 카운터(4) 순서로 만듭니다
+type chunkNonces struct {
+	nonce   []byte
+	counter uint64
+}
+
+// newChunkNonces prefix로 카운터 0부터 시작하는 nonce 생성기를 만듭니다
+func newChunkNonces(prefix []byte) *chunkNonces {
+	nonce := make([]byte, NonceSize)
+	copy(nonce, prefix)
+	return &chunkNonces{nonce: nonce}
+}
+
+// next 다음 카운터의 nonce를 반환합니다 (다음 호출 전까지만 유효)
+//
+// 카운터의 마지막 값은 종료 레코드용으로 남겨 두므로, final이 아닌 청크가
+// 그 값에 이르면 ErrChunkCounterOverflow를 반환합니다.
+func (n *chunkNonces) next(final bool) ([]byte, error) {
+	limit := uint64(maxChunkCounter)
+	if !final {
+		limit--
+	}
+	if n.counter > limit {
+		return nil, ErrChunkCounterOverflow
+	}
+
+	binary.BigEndian.PutUint32(n.nonce[NoncePrefixSize:], uint32(n.counter))
+	n.counter++
+	return n.nonce, nil
+}
+
+// StreamInfo 패스워드 없이 읽을 수 있는 스트림 헤더 정보
+type StreamInfo struct {
+	Version     byte
+	Compression Compression
+	KeySlots    []*KeySlot
+}
+
+// EncryptStream 스트림 방식으로 대용량 데이터를 암호화합니다
+//
+// 랜덤 데이터 키로 청크를 암호화하고, 패스워드마다 데이터 키를 감싼 키 슬롯을
+// 헤더에 기록합니다. 어느 패스워드로든 DecryptStream으로 복호화할 수 있습니다.
+func (ce *CryptoEngine) EncryptStream(reader io.Reader, writer io.Writer, passwords ...string) error {
+	return ce.EncryptStreamWithOptions(reader, writer, passwords)
+}
+
+// EncryptStreamWithOptions 옵션(청크 크기, 압축 등)을 적용해 스트림을 암호화합니다
+func (ce *CryptoEngine) EncryptStreamWithOptions(reader io.Reader, writer io.Writer, passwords []string, opts ...Option) error {
+	secrets := passwordBytes(passwords)
+	encWriter, err := ce.newEncryptWriter(writer, secrets, opts...)
+	zeroPasswords(secrets)
+	if err != nil {
+		return err
+	}
+
+	// 청크 단위로 암호화
+	buffer := make([]byte, ChunkSize)
+	if _, err := io.CopyBuffer(encWriter, reader, buffer); err != nil {
+		// 입력 일부만 담은 스트림이 정상 종료된 것처럼 인증되지 않도록 종료 레코드를 쓰지 않음
+		err = fmt.Errorf("스트림 암호화 실패: %w", err)
+		encWriter.abort(err)
+		return err
+	}
+
+	// 남은 청크와 종료 레코드 저장
+	return encWriter.Close()
+}
+
+// DecryptStream 스트림 방식으로 대용량 데이터를 복호화합니다
+//
+// 버전 헤더가 있는 스트림과 헤더 없는 레거시 스트림을 모두 지원합니다.
+// 버전 헤더가 있으면 청크를 쓰기 전에 키 슬롯으로 패스워드를 확인해
+// ErrWrongPassword를 반환하고, 이후 청크 인증 실패는 ErrCorruptedChunk로 구분합니다.
+func (ce *CryptoEngine) DecryptStream(reader io.Reader, writer io.Writer, password string) error {
+	decReader, err := ce.newDecryptReader(reader, password)
+	if err != nil {
+		return err
+	}
+
+	// 청크 단위로 복호화
+	buffer := make([]byte, ChunkSize)
+	if _, err := io.CopyBuffer(writer, decReader, buffer); err != nil {
+		if decReader.err != nil && decReader.err != io.EOF {
+			return err
+		}
+		return fmt.Errorf("복호화된 데이터 저장 실패: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyStream 평문을 버리면서 스트림 전체를 검증합니다
+//
+// 모든 청크의 인증 태그와 종료 레코드, 버전 3 이상이면 MAC 트레일러까지
+// 확인하므로 복호화 결과를 메모리에 올리지 않고 무결성 검사에 사용할 수 있습니다.
+func VerifyStream(reader io.Reader, password string) error {
+	decReader, err := NewCryptoEngine().newDecryptReader(reader, password)
+	if err != nil {
+		return err
+	}
+
+	buffer := make([]byte, ChunkSize)
+	if _, err := io.CopyBuffer(io.Discard, decReader, buffer); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReadStreamInfo 스트림 헤더를 읽어 포맷 버전, 압축 알고리즘, 키 슬롯을 반환합니다
+//
+// 헤더만 읽으며 청크는 검증하지 않습니다. 헤더가 없는 레거시 스트림은
+// ErrUnsupportedStreamFormat을 반환합니다.
+func ReadStreamInfo(reader io.Reader) (*StreamInfo, error) {
+	prefix := make([]byte, len(StreamMagic))
+	if _, err := io.ReadFull(reader, prefix); err != nil {
+		return nil, fmt.Errorf("스트림 헤더 읽기 실패: %w", err)
+	}
+
+	if string(prefix) != StreamMagic {
+		return nil, fmt.Errorf("%w: 헤더가 없는 레거시 스트림입니다", ErrUnsupportedStreamFormat)
+	}
+
+	header, err := readStreamHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamInfo{
+		Version:     header.version,
+		Compression: header.compression,
+		KeySlots:    header.slots,
+	}, nil
+}
+
+// CheckPassword 스트림 헤더만 읽어 패스워드가 맞는지 확인합니다
+//
+// 키 슬롯의 감싸진 데이터 키가 GCM으로 봉인되어 있어 패스워드 검증값 역할을
+// 하므로, 청크를 읽지 않고 키 유도 비용만으로 판단할 수 있습니다. 패스워드가
+// 틀리면 (false, nil)을 반환합니다. 키 슬롯이 변조된 스트림도 틀린 패스워드로
+// 보입니다. 헤더가 없는 레거시 스트림은 ErrUnsupportedStreamFormat을 반환합니다.
+func CheckPassword(reader io.Reader, password string) (bool, error) {
+	info, err := ReadStreamInfo(reader)
+	if err != nil {
+		return false, err
+	}
+
+	dataKey, err := NewCryptoEngine().OpenKeySlots(info.KeySlots, password)
+	if err != nil {
+		if errors.Is(err, ErrNoMatchingKeySlot) {
+			return false, nil
+		}
+		return false, err
+	}
+	ZeroBytes(dataKey)
+
+	return true, nil
+}
+
+// writeChunk 청크 하나를 암호화하여 기록합니다
+//
+// nonce가 nil이면 (버전 4 이하) 새 랜덤 nonce를 만들어 레코드 앞에 기록하고,
+// 카운터 nonce를 받으면 (버전 5) 레코드에 기록하지 않습니다.
+func (ce *CryptoEngine) writeChunk(writer io.Writer, gcm cipher.AEAD, nonce, chunk, aad []byte) error {
+	if nonce == nil {
+		var err error
+		if nonce, err = ce.GenerateNonce(); err != nil {
+			return fmt.Errorf("nonce 생성 실패: %w", err)
+		}
+
+		if _, writeErr := writer.Write(nonce); writeErr != nil {
+			return fmt.Errorf("nonce 저장 실패: %w", writeErr)
+		}
+	}
+
+	// 청크 암호화
+	ciphertext := gcm.Seal(nil, nonce, chunk, aad)
+
+	// 암호화된 청크 크기 검증 및 저장
+	ciphertextLen := len(ciphertext)
+	if ciphertextLen > MaxChunkSize {
+		return fmt.Errorf("청크 크기가 너무 큽니다: %d bytes", ciphertextLen)
+	}
+
+	var sizeField [ChunkSizeBytes]byte
+	binary.BigEndian.PutUint32(sizeField[:], uint32(ciphertextLen))
+	if _, writeErr := writer.Write(sizeField[:]); writeErr != nil {
+		return fmt.Errorf("청크 크기 저장 실패: %w", writeErr)
+	}
+
+	// 암호화된 데이터 저장
+	if _, writeErr := writer.Write(ciphertext); writeErr != nil {
+		return fmt.Errorf("암호화된 데이터 저장 실패: %w", writeErr)
+	}
+
+	return nil
+}
+
+// writeStreamHeader 버전 헤더와 키 슬롯을 기록합니다
+func writeStreamHeader(writer io.Writer, header *streamHeader) error {
+	buf, err := encodeStreamHeader(header)
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(buf); err != nil {
+		return fmt.Errorf("스트림 헤더 저장 실패: %w", err)
+	}
+
+	return nil
+}
+
+// encodeStreamHeader 매직 바이트부터 키 슬롯까지의 헤더 원문을 만듭니다
+func encodeStreamHeader(header *streamHeader) ([]byte, error) {
+	if len(header.slots) == 0 || len(header.slots) > MaxKeySlots {
+		return nil, fmt.Errorf("잘못된 키 슬롯 수: %d", len(header.slots))
+	}
+
+	buf := make([]byte, 0, len(StreamMagic)+streamHeaderFixedSize+len(header.slots)*keySlotHeaderSize)
+	buf = append(buf, StreamMagic...)
+	buf = append(buf, header.version)
+	if header.version >= StreamFormatCompression {
+		buf = append(buf, byte(header.compression))
+	} else if header.compression != CompressionNone {
+		return nil, fmt.Errorf("%w: 버전 %d 스트림은 압축을 지원하지 않습니다", ErrUnsupportedCompression, header.version)
+	}
+	if header.version >= StreamFormatCounterNonce {
+		if len(header.noncePrefix) != NoncePrefixSize {
+			return nil, fmt.Errorf("잘못된 nonce prefix 크기: %d", len(header.noncePrefix))
+		}
+		buf = append(buf, header.noncePrefix...)
+	}
+	buf = append(buf, byte(len(header.slots)))
+	for _, slot := range header.slots {
+		if err := slot.validate(); err != nil {
+			return nil, err
+		}
+		buf = binary.BigEndian.AppendUint32(buf, uint32(slot.Iterations))
+		buf = append(buf, slot.Salt...)
+		buf = append(buf, slot.WrappedKey...)
+	}
+
+	return buf, nil
+}
+
+// streamSubKeys 서브키 포맷(버전 2 이상) 스트림의 청크 암호화 키와 MAC 키를 유도합니다
+//
+// rawHeader는 매직 바이트부터 키 슬롯까지의 헤더 원문이며, 버전 6부터 헤더가
+// 정하는 nonce 방식과 함께 키에 묶입니다.
+func streamSubKeys(dataKey []byte, header *streamHeader, rawHeader []byte) (encKey, macKey []byte) {
+	if header.version >= StreamFormatHeaderBound {
+		return deriveStreamSubKeys(dataKey, rawHeader, header.nonceMode())
+	}
+	return DeriveSubKeys(dataKey)
+}
+
+// nonceMode 헤더가 정하는 청크 nonce 방식을 반환합니다
+func (h *streamHeader) nonceMode() string {
+	if h.noncePrefix != nil {
+		return nonceModeCounter
+	}
+	return nonceModeRecord
+}
+
+// readStreamHeader 매직 바이트 이후의 버전 헤더를 읽습니다
+func readStreamHeader(reader io.Reader) (*streamHeader, error) {
+	fixed := make([]byte, 1)
+	if _, err := io.ReadFull(reader, fixed); err != nil {
+		return nil, fmt.Errorf("스트림 헤더 읽기 실패: %w", err)
+	}
+
+	header := &streamHeader{version: fixed[0]}
+	if header.version < StreamFormatEnvelope || header.version > StreamFormatHeaderBound {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedStreamFormat, header.version)
+	}
+
+	// 버전 4부터는 압축 알고리즘 바이트가 추가됨
+	if header.version >= StreamFormatCompression {
+		if _, err := io.ReadFull(reader, fixed); err != nil {
+			return nil, fmt.Errorf("스트림 헤더 읽기 실패: %w", err)
+		}
+		header.compression = Compression(fixed[0])
+		if !header.compression.valid() {
+			return nil, fmt.Errorf("%w: %d", ErrUnsupportedCompression, fixed[0])
+		}
+	}
+
+	// 버전 5부터는 청크 nonce prefix가 추가됨
+	if header.version >= StreamFormatCounterNonce {
+		header.noncePrefix = make([]byte, NoncePrefixSize)
+		if _, err := io.ReadFull(reader, header.noncePrefix); err != nil {
+			return nil, fmt.Errorf("스트림 헤더 읽기 실패: %w", err)
+		}
+	}
+
+	if _, err := io.ReadFull(reader, fixed); err != nil {
+		return nil, fmt.Errorf("스트림 헤더 읽기 실패: %w", err)
+	}
+
+	slotCount := int(fixed[0])
+	if slotCount == 0 || slotCount > MaxKeySlots {
+		return nil, fmt.Errorf("잘못된 키 슬롯 수: %d", slotCount)
+	}
+
+	raw := make([]byte, slotCount*keySlotHeaderSize)
+	if _, err := io.ReadFull(reader, raw); err != nil {
+		return nil, fmt.Errorf("키 슬롯 읽기 실패: %w", err)
+	}
+
+	header.slots = make([]*KeySlot, 0, slotCount)
+	for i := 0; i < slotCount; i++ {
+		entry := raw[i*keySlotHeaderSize : (i+1)*keySlotHeaderSize]
+		header.slots = append(header.slots, &KeySlot{
+			Iterations: int(binary.BigEndian.Uint32(entry[:ChunkSizeBytes])),
+			Salt:       append([]byte(nil), entry[ChunkSizeBytes:ChunkSizeBytes+SaltSize]...),
+			WrappedKey: append([]byte(nil), entry[ChunkSizeBytes+SaltSize:]...),
+		})
+	}
+
+	return header, nil
+}
+
+// truncatedOr 스트림 끝 관련 에러를 잘림 에러로 변환합니다
+func truncatedOr(err error, message string) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: %w", ErrTruncatedStream, io.ErrUnexpectedEOF)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// expectEOF 종료 레코드 뒤에 남은 데이터가 없는지 확인합니다
+func expectEOF(reader io.Reader) error {
+	var extra [1]byte
+	n, err := io.ReadFull(reader, extra[:])
+	if n > 0 {
+		return errors.New("종료 레코드 이후에 데이터가 남아 있습니다")
+	}
+	if err != io.EOF {
+		return fmt.Errorf("스트림 끝 확인 실패: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,422 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptWithChunkSize 지정한 청크 크기로 평문을 암호화합니다
+func encryptWithChunkSize(t *testing.T, plaintext []byte, chunkSize int) []byte {
+	t.Helper()
+	var encrypted bytes.Buffer
+	encWriter, err := NewEncryptWriter(&encrypted, StreamPassword, WithChunkSize(chunkSize))
+	require.NoError(t, err)
+	_, err = encWriter.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, encWriter.Close())
+	return encrypted.Bytes()
+}
+
+func TestVerifyStream_Valid(t *testing.T) {
+	plaintext := []byte(strings.Repeat("integrity scan ", ChunkSize/8))
+	encrypted := encryptToBytes(t, plaintext, StreamPassword)
+	assert.Equal(t, CurrentStreamFormat, encrypted[len(StreamMagic)])
+
+	require.NoError(t, VerifyStream(bytes.NewReader(encrypted), StreamPassword))
+}
+
+func TestVerifyStream_TamperedTrailer(t *testing.T) {
+	encrypted := encryptToBytes(t, []byte(TestData), StreamPassword)
+	encrypted[len(encrypted)-1] ^= 0x01
+
+	err := VerifyStream(bytes.NewReader(encrypted), StreamPassword)
+	assert.ErrorIs(t, err, ErrIntegrityCheckFailed)
+}
+
+func TestVerifyStream_ReorderedChunks(t *testing.T) {
+	// 같은 크기의 청크 두 개 (각 청크의 인증 태그는 그대로 유효)
+	encrypted := encryptWithChunkSize(t, []byte("AAAAAAAABBBBBBBB"), 8)
+	headerSize := len(StreamMagic) + streamHeaderFixedSize + keySlotHeaderSize
+	recordSize := ChunkSizeBytes + 8 + GCMTagSize
+
+	first := append([]byte(nil), encrypted[headerSize:headerSize+recordSize]...)
+	copy(encrypted[headerSize:], encrypted[headerSize+recordSize:headerSize+2*recordSize])
+	copy(encrypted[headerSize+recordSize:], first)
+
+	// 카운터 nonce가 위치와 맞지 않아 MAC 트레일러 전에 청크 인증에서 실패
+	err := VerifyStream(bytes.NewReader(encrypted), StreamPassword)
+	assert.ErrorIs(t, err, ErrCorruptedChunk)
+}
+
+func TestVerifyStream_TruncatedTrailer(t *testing.T) {
+	encrypted := encryptToBytes(t, []byte(TestData), StreamPassword)
+
+	err := VerifyStream(bytes.NewReader(encrypted[:len(encrypted)-MACTrailerSize/2]), StreamPassword)
+	assert.ErrorIs(t, err, ErrTruncatedStream)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// 트레일러 뒤에 데이터가 덧붙은 경우
+	err = VerifyStream(bytes.NewReader(append(encrypted, 0x00)), StreamPassword)
+	assert.Error(t, err)
+}
+
+func TestEncryptStream_SourceReadError(t *testing.T) {
+	// 청크 몇 개를 읽은 뒤 실패하는 입력
+	plaintext := []byte(strings.Repeat("partial source ", 64))
+	source := io.MultiReader(bytes.NewReader(plaintext), iotest.ErrReader(errors.New("disk read failed")))
+
+	var encrypted bytes.Buffer
+	err := NewCryptoEngine().EncryptStreamWithOptions(source, &encrypted, []string{StreamPassword}, WithChunkSize(64))
+	require.ErrorContains(t, err, "disk read failed")
+	require.NotZero(t, encrypted.Len(), "실패 전에 기록한 청크는 남음")
+
+	// 종료 레코드와 MAC 트레일러가 없으므로 정상 스트림으로 인증되지 않음
+	var decrypted bytes.Buffer
+	err = NewCryptoEngine().DecryptStream(bytes.NewReader(encrypted.Bytes()), &decrypted, StreamPassword)
+	assert.ErrorIs(t, err, ErrTruncatedStream)
+	assert.ErrorIs(t, VerifyStream(bytes.NewReader(encrypted.Bytes()), StreamPassword), ErrTruncatedStream)
+}
+
+func TestVerifyStream_TamperedChunk(t *testing.T) {
+	encrypted := encryptToBytes(t, []byte(TestData), StreamPassword)
+	headerSize := len(StreamMagic) + streamHeaderFixedSize + keySlotHeaderSize
+	encrypted[headerSize+ChunkSizeBytes] ^= 0xFF
+
+	err := VerifyStream(bytes.NewReader(encrypted), StreamPassword)
+	assert.ErrorIs(t, err, ErrAuthenticationFailed)
+}
+
+func TestVerifyStream_WrongPassword(t *testing.T) {
+	encrypted := encryptToBytes(t, []byte(TestData), StreamPassword)
+
+	err := VerifyStream(bytes.NewReader(encrypted), "wrongpassword")
+	assert.ErrorIs(t, err, ErrNoMatchingKeySlot)
+	assert.ErrorIs(t, err, ErrWrongPassword)
+}
+
+func TestCheckPassword(t *testing.T) {
+	engine := NewCryptoEngine()
+	var encrypted bytes.Buffer
+	require.NoError(t, engine.EncryptStream(strings.NewReader(TestData), &encrypted, StreamPassword, "second-password"))
+
+	// 헤더만 있어도 판단 가능
+	headerSize := len(StreamMagic) + streamHeaderFixedSize + 2*keySlotHeaderSize
+	header := encrypted.Bytes()[:headerSize]
+
+	ok, err := CheckPassword(bytes.NewReader(header), StreamPassword)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = CheckPassword(bytes.NewReader(header), "second-password")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = CheckPassword(bytes.NewReader(header), "wrongpassword")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// 헤더가 잘린 경우
+	_, err = CheckPassword(bytes.NewReader(header[:headerSize-1]), StreamPassword)
+	assert.Error(t, err)
+
+	_, err = CheckPassword(bytes.NewReader(header), "")
+	assert.Error(t, err)
+}
+
+func TestCheckPassword_LegacyStream(t *testing.T) {
+	legacy := encryptLegacyStream(t, NewCryptoEngine(), []byte(TestData), StreamPassword)
+
+	_, err := CheckPassword(bytes.NewReader(legacy), StreamPassword)
+	assert.ErrorIs(t, err, ErrUnsupportedStreamFormat)
+}
+
+func TestVerifyStream_SubKeyFormatWithoutTrailer(t *testing.T) {
+	engine := NewCryptoEngine()
+
+	// 버전 2 스트림: 서브키로 봉인, 트레일러 없음
+	dataKey, err := engine.GenerateDataKey()
+	require.NoError(t, err)
+	slots, err := engine.NewKeySlots(dataKey, []string{StreamPassword})
+	require.NoError(t, err)
+	gcm, err := newGCM(deriveEncryptionKey(dataKey))
+	require.NoError(t, err)
+
+	var stream bytes.Buffer
+	require.NoError(t, writeStreamHeader(&stream, &streamHeader{version: StreamFormatSubKeys, slots: slots}))
+	require.NoError(t, engine.writeChunk(&stream, gcm, nil, []byte(TestData), nil))
+	require.NoError(t, engine.writeChunk(&stream, gcm, nil, nil, finalChunkAAD))
+
+	require.NoError(t, VerifyStream(bytes.NewReader(stream.Bytes()), StreamPassword))
+	assert.Equal(t, []byte(TestData), decryptToBytes(t, stream.Bytes(), StreamPassword))
+}
+
+func TestVerifyStream_VersionDowngrade(t *testing.T) {
+	encrypted := encryptWithChunkSize(t, []byte("AAAAAAAABBBBBBBBCCCC"), 8)
+	require.Equal(t, CurrentStreamFormat, encrypted[len(StreamMagic)])
+
+	// 버전을 낮춰 MAC 트레일러나 카운터 nonce 검사를 건너뛰려 해도 검증 실패
+	for version := StreamFormatEnvelope; version < CurrentStreamFormat; version++ {
+		tampered := bytes.Clone(encrypted)
+		tampered[len(StreamMagic)] = version
+		assert.Error(t, VerifyStream(bytes.NewReader(tampered), StreamPassword), "버전 %d", version)
+	}
+
+	// 헤더 배치가 같은 버전 5로 낮추면 헤더에 묶인 서브키가 달라져 첫 청크부터 인증 실패
+	tampered := bytes.Clone(encrypted)
+	tampered[len(StreamMagic)] = StreamFormatCounterNonce
+	err := VerifyStream(bytes.NewReader(tampered), StreamPassword)
+	assert.ErrorIs(t, err, ErrAuthenticationFailed)
+	assert.ErrorIs(t, err, ErrCorruptedChunk)
+
+	// 헤더의 다른 필드(nonce prefix)를 바꿔도 마찬가지
+	tampered = bytes.Clone(encrypted)
+	tampered[len(StreamMagic)+2] ^= 0x01
+	assert.ErrorIs(t, VerifyStream(bytes.NewReader(tampered), StreamPassword), ErrAuthenticationFailed)
+}
+
+func TestDecryptStream_ReorderAfterDowngrade(t *testing.T) {
+	encrypted := encryptWithChunkSize(t, []byte("AAAAAAAABBBBBBBBCCCC"), 8)
+
+	// 첫 두 청크 레코드(len(4) | 암호문)의 위치
+	offset := len(StreamMagic) + streamHeaderFixedSize + keySlotHeaderSize
+	records := walkRecords(t, encrypted, offset, len(encrypted)-MACTrailerSize, 0)
+	require.GreaterOrEqual(t, len(records), 3)
+	first := ChunkSizeBytes + records[0]
+	second := ChunkSizeBytes + records[1]
+	require.Equal(t, first, second, "같은 크기의 청크끼리 교환")
+
+	swapped := bytes.Clone(encrypted)
+	copy(swapped[offset:], encrypted[offset+first:offset+first+second])
+	copy(swapped[offset+second:], encrypted[offset:offset+first])
+
+	// 레코드의 nonce를 읽는 포맷이나 같은 배치의 버전 5로 낮춰도 순서 변경이 드러남
+	for version := StreamFormatEnvelope; version < CurrentStreamFormat; version++ {
+		tampered := bytes.Clone(swapped)
+		tampered[len(StreamMagic)] = version
+		err := NewCryptoEngine().DecryptStream(bytes.NewReader(tampered), io.Discard, StreamPassword)
+		assert.Error(t, err, "버전 %d", version)
+	}
+
+	tampered := bytes.Clone(swapped)
+	tampered[len(StreamMagic)] = StreamFormatCounterNonce
+	err := NewCryptoEngine().DecryptStream(bytes.NewReader(tampered), io.Discard, StreamPassword)
+	assert.ErrorIs(t, err, ErrAuthenticationFailed)
+
+	// 버전을 그대로 두어도 카운터 nonce가 달라 인증 실패
+	err = NewCryptoEngine().DecryptStream(bytes.NewReader(swapped), io.Discard, StreamPassword)
+	assert.ErrorIs(t, err, ErrAuthenticationFailed)
+}
+
+func TestEncryptStream_CounterNonceOverhead(t *testing.T) {
+	// 청크 레코드는 len(4) | 암호문만 가지며, nonce prefix는 헤더에 한 번만 기록
+	plaintext := []byte("AAAAAAAABBBBBBBBCCCC")
+	encrypted := encryptWithChunkSize(t, plaintext, 8)
+
+	headerSize := len(StreamMagic) + streamHeaderFixedSize + keySlotHeaderSize
+	records := 3*(ChunkSizeBytes+GCMTagSize) + len(plaintext) + ChunkSizeBytes + GCMTagSize
+	assert.Len(t, encrypted, headerSize+records+MACTrailerSize)
+
+	info, err := ReadStreamInfo(bytes.NewReader(encrypted))
+	require.NoError(t, err)
+	assert.Equal(t, CurrentStreamFormat, info.Version)
+	assert.Equal(t, plaintext, decryptToBytes(t, encrypted, StreamPassword))
+}
+
+func TestDecryptStream_RandomNonceFormat(t *testing.T) {
+	engine := NewCryptoEngine()
+
+	// 버전 4 스트림: 청크마다 랜덤 nonce를 기록하고 MAC 트레일러를 덧붙임
+	dataKey, err := engine.GenerateDataKey()
+	require.NoError(t, err)
+	slots, err := engine.NewKeySlots(dataKey, []string{StreamPassword})
+	require.NoError(t, err)
+	encKey, macKey := DeriveSubKeys(dataKey)
+	gcm, err := newGCM(encKey)
+	require.NoError(t, err)
+
+	var stream bytes.Buffer
+	mac := hmac.New(sha256.New, macKey)
+	out := io.MultiWriter(&stream, mac)
+	require.NoError(t, writeStreamHeader(out, &streamHeader{version: StreamFormatCompression, slots: slots}))
+	require.NoError(t, engine.writeChunk(out, gcm, nil, []byte(TestData), nil))
+	require.NoError(t, engine.writeChunk(out, gcm, nil, nil, finalChunkAAD))
+	stream.Write(mac.Sum(nil))
+
+	require.NoError(t, VerifyStream(bytes.NewReader(stream.Bytes()), StreamPassword))
+	assert.Equal(t, []byte(TestData), decryptToBytes(t, stream.Bytes(), StreamPassword))
+}
+
+func TestChunkNonces_Overflow(t *testing.T) {
+	prefix := bytes.Repeat([]byte{0xAB}, NoncePrefixSize)
+	nonces := newChunkNonces(prefix)
+
+	nonce, err := nonces.next(false)
+	require.NoError(t, err)
+	assert.Equal(t, append(append([]byte(nil), prefix...), 0, 0, 0, 0), nonce)
+
+	nonce, err = nonces.next(false)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 1}, nonce[NoncePrefixSize:])
+
+	// 마지막 카운터는 종료 레코드만 사용할 수 있음
+	nonces.counter = maxChunkCounter
+	_, err = nonces.next(false)
+	assert.ErrorIs(t, err, ErrChunkCounterOverflow)
+
+	nonce, err = nonces.next(true)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xFF, 0xFF, 0xFF, 0xFF}, nonce[NoncePrefixSize:])
+
+	_, err = nonces.next(true)
+	assert.ErrorIs(t, err, ErrChunkCounterOverflow)
+}
+
+// 골든 파일 관련 값 (바꾸면 기존 골든 파일을 복호화할 수 없음)
+const (
+	goldenPassword  = "golden-password"
+	goldenPlaintext = "DataLocker golden stream: 포맷 호환성 고정용 평문\n"
+
+	// goldenChunkSize 현재 포맷 골든 파일이 여러 청크 레코드를 갖도록 작은 청크 크기 사용
+	goldenChunkSize = 16
+)
+
+// updateGolden 없는 골든 파일만 현재 코드로 생성합니다 (go test ./pkg/crypto -run Golden -update)
+//
+// 기존 파일은 이전에 암호화된 데이터의 표본이므로 덮어쓰지 않습니다. 포맷을
+// 바꾸면 새 버전의 골든 파일을 추가하고 기존 파일은 그대로 복호화되어야 합니다.
+var updateGolden = flag.Bool("update", false, "없는 골든 파일을 현재 코드로 생성합니다")
+
+// goldenStream testdata의 골든 파일을 읽고, -update이면 없을 때 create로 만듭니다
+func goldenStream(t *testing.T, name string, create func() []byte) []byte {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && *updateGolden {
+		data = create()
+		require.NoError(t, os.MkdirAll("testdata", 0o750))
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		return data
+	}
+	require.NoError(t, err, "골든 파일이 없으면 -update로 생성하세요")
+	return data
+}
+
+// streamLayout 골든 파일에서 읽은 청크 레코드
+type streamLayout struct {
+	headerSize int
+	records    []int // 레코드별 암호문 길이 (len 필드 값)
+}
+
+// walkRecords offset부터 len(4) | 암호문 레코드를 end까지 읽습니다 (nonceSize만큼 nonce가 앞에 붙음)
+func walkRecords(t *testing.T, stream []byte, offset, end, nonceSize int) []int {
+	t.Helper()
+
+	var records []int
+	for offset < end {
+		offset += nonceSize
+		require.LessOrEqual(t, offset+ChunkSizeBytes, end, "len 필드가 잘림")
+		size := int(binary.BigEndian.Uint32(stream[offset : offset+ChunkSizeBytes]))
+		offset += ChunkSizeBytes
+
+		require.GreaterOrEqual(t, size, GCMTagSize, "암호문은 인증 태그보다 짧을 수 없음")
+		require.LessOrEqual(t, offset+size, end, "암호문이 잘림")
+		records = append(records, size)
+		offset += size
+	}
+	require.Equal(t, end, offset)
+	return records
+}
+
+func TestGoldenStream_Legacy(t *testing.T) {
+	stream := goldenStream(t, "stream_legacy.golden", func() []byte {
+		return encryptLegacyStream(t, NewCryptoEngine(), []byte(goldenPlaintext), goldenPassword)
+	})
+
+	// salt(32) | [nonce(12) | len(4) | ct]...
+	layout := streamLayout{headerSize: SaltSize}
+	layout.records = walkRecords(t, stream, layout.headerSize, len(stream), NonceSize)
+	assert.Equal(t, []int{len(goldenPlaintext) + GCMTagSize}, layout.records)
+
+	assert.Equal(t, []byte(goldenPlaintext), decryptToBytes(t, stream, goldenPassword))
+}
+
+func TestGoldenStream_CounterNonce(t *testing.T) {
+	// 버전 5는 더 이상 기록하지 않으므로 기존 골든 파일로만 검증
+	stream := goldenStream(t, "stream_v5.golden", func() []byte {
+		t.Fatal("버전 5 골든 파일은 현재 코드로 만들 수 없습니다")
+		return nil
+	})
+
+	assertCounterNonceGolden(t, stream, StreamFormatCounterNonce)
+}
+
+func TestGoldenStream_Current(t *testing.T) {
+	stream := goldenStream(t, "stream_v6.golden", func() []byte {
+		var out bytes.Buffer
+		encWriter, err := NewEncryptWriter(&out, goldenPassword,
+			WithChunkSize(goldenChunkSize), WithIterations(MinIterations))
+		require.NoError(t, err)
+		_, err = encWriter.Write([]byte(goldenPlaintext))
+		require.NoError(t, err)
+		require.NoError(t, encWriter.Close())
+		return out.Bytes()
+	})
+
+	assertCounterNonceGolden(t, stream, StreamFormatHeaderBound)
+}
+
+// assertCounterNonceGolden 카운터 nonce 배치(버전 5, 6) 골든 파일의 구조와 복호화 결과를 확인합니다
+func assertCounterNonceGolden(t *testing.T, stream []byte, version byte) {
+	t.Helper()
+
+	// "DLKR" | version(1) | compression(1) | noncePrefix(8) | slotCount(1) | [iterations(4) | salt(32) | wrappedKey(60)]
+	require.Greater(t, len(stream), len(StreamMagic)+streamHeaderFixedSize)
+	assert.Equal(t, StreamMagic, string(stream[:len(StreamMagic)]))
+	offset := len(StreamMagic)
+	assert.Equal(t, version, stream[offset])
+	assert.Equal(t, byte(CompressionNone), stream[offset+1])
+	offset += 2 + NoncePrefixSize
+	require.Equal(t, byte(1), stream[offset])
+	offset++
+	assert.Equal(t, uint32(MinIterations), binary.BigEndian.Uint32(stream[offset:offset+ChunkSizeBytes]))
+	offset += keySlotHeaderSize
+
+	// [len(4) | ct]... | 종료 레코드(빈 평문) | MAC(32)
+	records := walkRecords(t, stream, offset, len(stream)-MACTrailerSize, 0)
+	require.NotEmpty(t, records)
+	assert.Equal(t, GCMTagSize, records[len(records)-1], "종료 레코드는 빈 평문")
+
+	plaintextSize := 0
+	for i, size := range records[:len(records)-1] {
+		chunk := size - GCMTagSize
+		if i < len(records)-2 {
+			assert.Equal(t, goldenChunkSize, chunk, "마지막이 아닌 청크는 청크 크기만큼 채움")
+		}
+		plaintextSize += chunk
+	}
+	assert.Equal(t, len(goldenPlaintext), plaintextSize)
+
+	info, err := ReadStreamInfo(bytes.NewReader(stream))
+	require.NoError(t, err)
+	assert.Equal(t, version, info.Version)
+	require.Len(t, info.KeySlots, 1)
+	assert.Equal(t, MinIterations, info.KeySlots[0].Iterations)
+
+	require.NoError(t, VerifyStream(bytes.NewReader(stream), goldenPassword))
+	assert.Equal(t, []byte(goldenPlaintext), decryptToBytes(t, stream, goldenPassword))
+}
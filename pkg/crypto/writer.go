@@ -0,0 +1,214 @@
+// Package crypto provides cryptographic utilities for DataLocker application.
+// This file implements an io.WriteCloser that encrypts into the stream format.
+package crypto
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// ErrWriterClosed 닫힌 암호화 Writer에 쓰기 시도
+var ErrWriterClosed = errors.New("암호화 Writer가 이미 닫혔습니다")
+
+// encryptWriter 쓰여진 데이터를 청크 단위로 암호화하는 Writer
+type encryptWriter struct {
+	engine *CryptoEngine
+	dst    io.Writer // 원본 출력 (MAC 트레일러 기록용)
+	out    io.Writer // 출력과 MAC에 함께 기록
+	gcm    cipher.AEAD
+	mac    hash.Hash
+	zip    *chunkCompressor // 압축 미사용 시 nil
+	nonces *chunkNonces
+	buf    []byte
+	err    error // 지연 보고할 쓰기 에러
+	closed bool
+}
+
+// NewEncryptWriter dst에 스트림 포맷으로 암호화해 기록하는 WriteCloser를 생성합니다
+//
+// 생성 시 헤더를 기록하고, Write는 청크가 가득 찰 때마다 봉인해 기록하며,
+// Close는 남은 부분 청크와 종료 레코드, MAC 트레일러를 기록합니다. Close를
+// 호출하지 않으면 복호화 시 잘린 스트림으로 판단됩니다.
+func NewEncryptWriter(dst io.Writer, password string, opts ...Option) (io.WriteCloser, error) {
+	secret := []byte(password)
+	defer ZeroBytes(secret)
+
+	return NewCryptoEngine().newEncryptWriter(dst, [][]byte{secret}, opts...)
+}
+
+// NewEncryptWriterBytes 바이트 패스워드로 NewEncryptWriter와 같은 WriteCloser를 생성합니다
+//
+// 패스워드는 생성 중에 키 슬롯을 만드는 데만 쓰고 보관하지 않으므로, 호출자는
+// 반환 직후 ZeroBytes로 지울 수 있습니다.
+func NewEncryptWriterBytes(dst io.Writer, password []byte, opts ...Option) (io.WriteCloser, error) {
+	return NewCryptoEngine().newEncryptWriter(dst, [][]byte{password}, opts...)
+}
+
+// newEncryptWriter 여러 패스워드의 키 슬롯을 갖는 암호화 Writer를 생성합니다
+func (ce *CryptoEngine) newEncryptWriter(dst io.Writer, passwords [][]byte, opts ...Option) (*encryptWriter, error) {
+	if dst == nil {
+		return nil, errors.New("출력 대상이 필요합니다")
+	}
+
+	if err := validatePasswords(passwords); err != nil {
+		return nil, err
+	}
+
+	options, err := ce.callOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 데이터 키 및 키 슬롯 생성
+	dataKey, err := ce.GenerateDataKey()
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(dataKey)
+
+	slots, err := ce.newKeySlots(dataKey, passwords, options.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	// 청크 nonce는 랜덤 prefix 뒤에 카운터를 붙여 생성
+	prefix, err := ce.GenerateNonce()
+	if err != nil {
+		return nil, fmt.Errorf("nonce prefix 생성 실패: %w", err)
+	}
+	prefix = prefix[:NoncePrefixSize]
+
+	header := &streamHeader{version: CurrentStreamFormat, compression: options.Compression, noncePrefix: prefix, slots: slots}
+	rawHeader, err := encodeStreamHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	// 청크는 암호화 서브키로 봉인하고, 전체 출력은 MAC 서브키로 인증 (둘 다 헤더에 묶임)
+	encKey, macKey := streamSubKeys(dataKey, header, rawHeader)
+	defer ZeroBytes(encKey)
+
+	mac := hmac.New(sha256.New, macKey)
+	ZeroBytes(macKey)
+
+	gcm, err := newGCM(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// 헤더 저장
+	out := io.MultiWriter(dst, mac)
+	if _, err := out.Write(rawHeader); err != nil {
+		return nil, fmt.Errorf("스트림 헤더 저장 실패: %w", err)
+	}
+
+	return &encryptWriter{
+		engine: ce,
+		dst:    dst,
+		out:    out,
+		gcm:    gcm,
+		mac:    mac,
+		zip:    newChunkCompressor(options.Compression),
+		nonces: newChunkNonces(prefix),
+		buf:    make([]byte, 0, options.ChunkSize),
+	}, nil
+}
+
+// Write 데이터를 버퍼에 모으고 가득 찬 청크를 암호화해 기록합니다
+func (w *encryptWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+
+		if len(w.buf) == cap(w.buf) {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// Close 남은 청크와 종료 레코드, MAC 트레일러를 기록합니다 (여러 번 호출해도 안전)
+func (w *encryptWriter) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+
+	if w.err != nil {
+		return w.err
+	}
+
+	if len(w.buf) > 0 {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.seal(nil, finalChunkAAD, true); err != nil {
+		return err
+	}
+
+	// 트레일러는 MAC 계산 대상에서 제외
+	if _, err := w.dst.Write(w.mac.Sum(nil)); err != nil {
+		w.err = fmt.Errorf("MAC 트레일러 저장 실패: %w", err)
+	}
+
+	return w.err
+}
+
+// abort 종료 레코드와 MAC 트레일러 없이 Writer를 닫습니다
+//
+// 입력을 끝까지 읽지 못했을 때 쓰며, 그때까지 기록한 청크만 남으므로 복호화하면
+// 잘린 스트림(ErrTruncatedStream)으로 판단됩니다. 이후 Write와 Close는 err를 반환합니다.
+func (w *encryptWriter) abort(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+	w.closed = true
+	w.buf = w.buf[:0]
+}
+
+// flush 버퍼의 청크를 암호화해 기록합니다
+func (w *encryptWriter) flush() error {
+	chunk := w.buf
+	if w.zip != nil {
+		chunk = w.zip.encode(w.buf)
+	}
+
+	if err := w.seal(chunk, nil, false); err != nil {
+		return err
+	}
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// seal 다음 카운터 nonce로 레코드 하나를 봉인해 기록합니다
+func (w *encryptWriter) seal(chunk, aad []byte, final bool) error {
+	nonce, err := w.nonces.next(final)
+	if err == nil {
+		err = w.engine.writeChunk(w.out, w.gcm, nonce, chunk, aad)
+	}
+	if err != nil {
+		w.err = err
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter limit 바이트 이후 쓰기를 실패시키는 Writer
+type failingWriter struct {
+	limit   int
+	written int
+}
+
+var errWriteFailed = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.written+len(p) > w.limit {
+		return 0, errWriteFailed
+	}
+	w.written += len(p)
+	return len(p), nil
+}
+
+// decryptToBytes 스트림을 복호화해 평문을 반환합니다
+func decryptToBytes(t *testing.T, encrypted []byte, password string) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	require.NoError(t, NewCryptoEngine().DecryptStream(bytes.NewReader(encrypted), &out, password))
+	return out.Bytes()
+}
+
+func TestNewEncryptWriter_MultipartCopy(t *testing.T) {
+	content := []byte(strings.Repeat("multipart upload body ", 100000))
+
+	// multipart 본문 생성
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "upload.txt")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	// multipart 파트를 그대로 암호화 Writer로 복사
+	reader := multipart.NewReader(&body, mw.Boundary())
+	filePart, err := reader.NextPart()
+	require.NoError(t, err)
+
+	var encrypted bytes.Buffer
+	encWriter, err := NewEncryptWriter(&encrypted, StreamPassword)
+	require.NoError(t, err)
+
+	copied, err := io.Copy(encWriter, filePart)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), copied)
+	require.NoError(t, encWriter.Close())
+
+	assert.Equal(t, content, decryptToBytes(t, encrypted.Bytes(), StreamPassword))
+}
+
+func TestNewEncryptWriter_SmallChunks(t *testing.T) {
+	content := []byte("chunk boundaries should not matter for the decrypted output")
+
+	for _, chunkSize := range []int{1, 7, len(content), ChunkSize} {
+		var encrypted bytes.Buffer
+		encWriter, err := NewEncryptWriter(&encrypted, StreamPassword, WithChunkSize(chunkSize))
+		require.NoError(t, err)
+
+		// 여러 번 나누어 쓰기
+		for i := 0; i < len(content); i += 5 {
+			end := min(i+5, len(content))
+			_, err = encWriter.Write(content[i:end])
+			require.NoError(t, err)
+		}
+		require.NoError(t, encWriter.Close())
+
+		assert.Equal(t, content, decryptToBytes(t, encrypted.Bytes(), StreamPassword), "chunk size %d", chunkSize)
+	}
+}
+
+func TestNewEncryptWriterBytes(t *testing.T) {
+	content := []byte("password bytes can be wiped right after the writer is created")
+
+	password := []byte(StreamPassword)
+	var encrypted bytes.Buffer
+	encWriter, err := NewEncryptWriterBytes(&encrypted, password)
+	require.NoError(t, err)
+	ZeroBytes(password)
+
+	_, err = encWriter.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, encWriter.Close())
+
+	assert.Equal(t, content, decryptToBytes(t, encrypted.Bytes(), StreamPassword))
+
+	_, err = NewEncryptWriterBytes(&bytes.Buffer{}, nil)
+	assert.Error(t, err)
+}
+
+func TestNewEncryptWriter_CloseSemantics(t *testing.T) {
+	var encrypted bytes.Buffer
+	encWriter, err := NewEncryptWriter(&encrypted, StreamPassword)
+	require.NoError(t, err)
+
+	_, err = encWriter.Write([]byte("data"))
+	require.NoError(t, err)
+
+	// 두 번 닫아도 안전
+	require.NoError(t, encWriter.Close())
+	require.NoError(t, encWriter.Close())
+
+	// 닫힌 뒤 쓰기는 실패
+	_, err = encWriter.Write([]byte("more"))
+	assert.ErrorIs(t, err, ErrWriterClosed)
+
+	// 종료 레코드가 한 번만 기록되어야 함
+	assert.Equal(t, []byte("data"), decryptToBytes(t, encrypted.Bytes(), StreamPassword))
+}
+
+func TestNewEncryptWriter_DeferredWriteError(t *testing.T) {
+	headerSize := len(StreamMagic) + streamHeaderFixedSize + keySlotHeaderSize
+	dst := &failingWriter{limit: headerSize}
+
+	encWriter, err := NewEncryptWriter(dst, StreamPassword, WithChunkSize(4))
+	require.NoError(t, err)
+
+	// 버퍼에만 쌓이는 쓰기는 성공
+	_, err = encWriter.Write([]byte("abc"))
+	require.NoError(t, err)
+
+	// Close 시 남은 청크 기록 실패가 보고되어야 함
+	err = encWriter.Close()
+	assert.ErrorIs(t, err, errWriteFailed)
+	assert.ErrorIs(t, encWriter.Close(), errWriteFailed)
+}
+
+func TestNewEncryptWriter_InvalidInput(t *testing.T) {
+	_, err := NewEncryptWriter(&bytes.Buffer{}, "")
+	assert.Error(t, err)
+
+	_, err = NewEncryptWriter(nil, StreamPassword)
+	assert.Error(t, err)
+
+	_, err = NewEncryptWriter(&bytes.Buffer{}, StreamPassword, WithChunkSize(0))
+	assert.Error(t, err)
+
+	_, err = NewEncryptWriter(&bytes.Buffer{}, StreamPassword, WithChunkSize(ChunkSize+1))
+	assert.Error(t, err)
+
+	// 헤더 기록 실패
+	_, err = NewEncryptWriter(&failingWriter{}, StreamPassword)
+	assert.ErrorIs(t, err, errWriteFailed)
+}
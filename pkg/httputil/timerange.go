@@ -0,0 +1,156 @@
+// Package httputil provides request parsing helpers shared by DataLocker HTTP handlers.
+// Time ranges are always returned in UTC as half-open intervals [from, to).
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 기간 파라미터 형식
+const (
+	// DateLayout 날짜만 지정한 경우의 형식 (UTC 기준 그 날의 00:00~24:00)
+	DateLayout = "2006-01-02"
+
+	// DefaultMaxTimeRange 시작과 끝을 모두 지정한 기간의 기본 최대 길이
+	DefaultMaxTimeRange = 366 * 24 * time.Hour
+
+	// 기간을 받는 쿼리 파라미터 이름
+	ParamFrom = "from"
+	ParamTo   = "to"
+)
+
+// 기간 파싱 에러
+var (
+	// ErrInvalidTime RFC3339, YYYY-MM-DD, 상대 표현(-7d), now 중 어느 형식도 아님
+	ErrInvalidTime = errors.New("시각은 RFC3339, YYYY-MM-DD, 상대 표현(예: -7d, -12h) 또는 now여야 합니다")
+
+	// ErrInvalidTimeRange 시작 시각이 종료 시각보다 늦음
+	ErrInvalidTimeRange = errors.New("from이 to보다 늦습니다")
+
+	// ErrTimeRangeTooLong 기간이 허용하는 최대 길이를 넘음
+	ErrTimeRangeTooLong = errors.New("기간이 허용하는 최대 길이를 넘습니다")
+)
+
+// relativeUnits 상대 표현에서 허용하는 단위
+var relativeUnits = map[byte]time.Duration{
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// TimeRangeParser 쿼리 파라미터의 기간(from/to)을 UTC 반개구간으로 해석하는 파서
+//
+// 날짜만 지정하면 from은 그 날 00:00, to는 다음 날 00:00(미포함)이 되어 to의 날짜까지
+// 포함합니다. 상대 표현은 현재 시각 기준이며, 타임존 오프셋이 있는 RFC3339 값은
+// 같은 순간의 UTC로 바꿉니다.
+type TimeRangeParser struct {
+	// MaxRange 시작과 끝을 모두 지정했을 때 허용하는 최대 길이 (0이면 제한 없음)
+	MaxRange time.Duration
+
+	// Now 상대 표현과 now의 기준 시각 (nil이면 time.Now)
+	Now func() time.Time
+}
+
+// ParseTimeRange 기본 최대 길이(DefaultMaxTimeRange)로 from/to 쿼리 파라미터를 해석합니다
+//
+// 지정하지 않은 쪽은 zero time으로 반환하며, 호출하는 쪽에서 열린 구간으로 취급합니다.
+func ParseTimeRange(c echo.Context) (from, to time.Time, err error) {
+	parser := TimeRangeParser{MaxRange: DefaultMaxTimeRange}
+	return parser.Parse(c)
+}
+
+// Parse from/to 쿼리 파라미터를 해석합니다
+func (p TimeRangeParser) Parse(c echo.Context) (from, to time.Time, err error) {
+	return p.ParseValues(c.QueryParam(ParamFrom), c.QueryParam(ParamTo))
+}
+
+// ParseValues from/to 문자열을 해석하고 순서와 최대 길이를 검증합니다
+func (p TimeRangeParser) ParseValues(fromValue, toValue string) (from, to time.Time, err error) {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	current := now().UTC()
+
+	if from, err = parseTime(fromValue, current, false); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%s: %w", ParamFrom, err)
+	}
+	if to, err = parseTime(toValue, current, true); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%s: %w", ParamTo, err)
+	}
+
+	if from.IsZero() || to.IsZero() {
+		return from, to, nil
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %s ~ %s", ErrInvalidTimeRange, fromValue, toValue)
+	}
+	if p.MaxRange > 0 && to.Sub(from) > p.MaxRange {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: 최대 %s", ErrTimeRangeTooLong, formatDuration(p.MaxRange))
+	}
+
+	return from, to, nil
+}
+
+// parseTime 시각 하나를 UTC로 해석합니다 (빈 값은 zero time)
+//
+// upper가 true이면 날짜만 지정한 값을 다음 날 00:00으로 올려 그 날 전체를 포함합니다.
+func parseTime(value string, now time.Time, upper bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "":
+		return time.Time{}, nil
+	case strings.EqualFold(value, "now"):
+		return now, nil
+	case strings.HasPrefix(value, "-"):
+		return parseRelative(value, now)
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+
+	parsed, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidTime, value)
+	}
+	if upper {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return parsed, nil
+}
+
+// parseRelative "-7d"처럼 현재 시각에서 뺄 기간을 해석합니다 (단위: m, h, d, w)
+func parseRelative(value string, now time.Time) (time.Time, error) {
+	if len(value) < 3 {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidTime, value)
+	}
+
+	unit, ok := relativeUnits[value[len(value)-1]]
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidTime, value)
+	}
+
+	n, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || n <= 0 || time.Duration(n) > time.Duration(1<<62)/unit {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidTime, value)
+	}
+
+	return now.Add(-time.Duration(n) * unit), nil
+}
+
+// formatDuration 에러 메시지용으로 일 단위 기간을 읽기 쉽게 표시합니다
+func formatDuration(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d일", d/(24*time.Hour))
+	}
+	return d.String()
+}
@@ -0,0 +1,120 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeRangeParser_ParseValues(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+	parser := TimeRangeParser{MaxRange: 31 * 24 * time.Hour, Now: func() time.Time { return now }}
+
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{name: "지정하지 않음"},
+		{
+			name: "날짜만 지정하면 그 날 00:00~24:00 UTC", from: "2024-03-01", to: "2024-03-01",
+			wantFrom: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "월말 다음 날로 넘어감", to: "2024-02-29",
+			wantTo: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "오프셋은 같은 순간의 UTC", from: "2024-03-10T00:00:00+09:00", to: "2024-03-10T09:00:00+09:00",
+			wantFrom: time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC), wantTo: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "소수 초", from: "2024-03-10T00:00:00.5Z",
+			wantFrom: time.Date(2024, 3, 10, 0, 0, 0, 500_000_000, time.UTC),
+		},
+		{
+			name: "상대 표현과 now", from: "-7d", to: "now",
+			wantFrom: now.AddDate(0, 0, -7), wantTo: now,
+		},
+		{
+			name: "시간/분/주 단위", from: "-2w", to: "-90m",
+			wantFrom: now.AddDate(0, 0, -14), wantTo: now.Add(-90 * time.Minute),
+		},
+		{
+			name: "같은 시각은 빈 구간", from: "2024-03-10T00:00:00Z", to: "2024-03-10T09:00:00+09:00",
+			wantFrom: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "최대 길이 경계는 허용", from: "2024-02-01", to: "2024-03-03T00:00:00Z",
+			wantFrom: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "한쪽만 지정하면 길이 제한 없음", from: "2000-01-01",
+			wantFrom: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := parser.ParseValues(tc.from, tc.to)
+			require.NoError(t, err)
+			assert.True(t, tc.wantFrom.Equal(from), "from: %s", from)
+			assert.True(t, tc.wantTo.Equal(to), "to: %s", to)
+			assert.Equal(t, time.UTC, from.Location())
+			assert.Equal(t, time.UTC, to.Location())
+		})
+	}
+}
+
+func TestTimeRangeParser_Errors(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+	parser := TimeRangeParser{MaxRange: 31 * 24 * time.Hour, Now: func() time.Time { return now }}
+
+	testCases := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr error
+	}{
+		{name: "알 수 없는 형식", from: "yesterday", wantErr: ErrInvalidTime},
+		{name: "타임존 없는 시각", from: "2024-03-10T00:00:00", wantErr: ErrInvalidTime},
+		{name: "없는 날짜", to: "2024-02-30", wantErr: ErrInvalidTime},
+		{name: "상대 표현 단위 없음", from: "-7", wantErr: ErrInvalidTime},
+		{name: "지원하지 않는 단위", from: "-7y", wantErr: ErrInvalidTime},
+		{name: "0 또는 음수 기간", from: "-0d", wantErr: ErrInvalidTime},
+		{name: "너무 큰 기간", from: "-99999999999999d", wantErr: ErrInvalidTime},
+		{name: "역전된 기간", from: "2024-03-02", to: "2024-03-01T23:59:59Z", wantErr: ErrInvalidTimeRange},
+		{name: "최대 길이 초과", from: "2024-02-01", to: "2024-03-03T00:00:01Z", wantErr: ErrTimeRangeTooLong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := parser.ParseValues(tc.from, tc.to)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+
+	_, _, err := parser.ParseValues("bad", "")
+	assert.ErrorContains(t, err, "from: ", "어느 파라미터인지 표시")
+}
+
+func TestParseTimeRange(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?from=2024-01-01&to=2024-12-31", http.NoBody)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	from, to, err := ParseTimeRange(c)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), to, "윤년 366일은 기본 최대 길이 이내")
+
+	req = httptest.NewRequest(http.MethodGet, "/?from=2023-01-01&to=2024-12-31", http.NoBody)
+	_, _, err = ParseTimeRange(echo.New().NewContext(req, httptest.NewRecorder()))
+	assert.ErrorIs(t, err, ErrTimeRangeTooLong)
+}
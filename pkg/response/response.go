@@ -8,6 +8,15 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// 응답 컨텍스트 키
+const (
+	// ContextKeyErrorDetails 응답에서 숨긴 에러 상세를 로그용으로 보관하는 컨텍스트 키
+	ContextKeyErrorDetails = "error_details"
+
+	// ContextKeyExposeDetails 에러 응답에 상세(details)를 포함할지 여부를 담는 컨텍스트 키 (없으면 숨김)
+	ContextKeyExposeDetails = "expose_error_details"
+)
+
 // Response 표준 API 응답 구조체
 type Response struct {
 	Success bool        `json:"success"`
@@ -21,6 +30,35 @@ type ErrorInfo struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
+	// RequestID 로그와 대조할 수 있는 요청 ID
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// newErrorInfo 노출 정책을 적용한 에러 정보를 생성합니다
+func newErrorInfo(c echo.Context, code, message, details string) *ErrorInfo {
+	info := &ErrorInfo{
+		Code:      code,
+		Message:   message,
+		RequestID: requestID(c),
+	}
+
+	if details != "" {
+		if expose, _ := c.Get(ContextKeyExposeDetails).(bool); expose {
+			info.Details = details
+		} else {
+			c.Set(ContextKeyErrorDetails, details)
+		}
+	}
+
+	return info
+}
+
+// requestID 응답 또는 요청 헤더에서 요청 ID를 찾습니다
+func requestID(c echo.Context) string {
+	if id := c.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+	return c.Request().Header.Get(echo.HeaderXRequestID)
 }
 
 // Success 성공 응답을 반환합니다
@@ -58,11 +96,7 @@ func BadRequest(c echo.Context, message string, details string) error {
 	return c.JSON(http.StatusBadRequest, Response{
 		Success: false,
 		Message: message,
-		Error: &ErrorInfo{
-			Code:    "BAD_REQUEST",
-			Message: message,
-			Details: details,
-		},
+		Error:   newErrorInfo(c, "BAD_REQUEST", message, details),
 	})
 }
 
@@ -75,16 +109,12 @@ func InternalError(c echo.Context, message string, details string) error {
 	return c.JSON(http.StatusInternalServerError, Response{
 		Success: false,
 		Message: message,
-		Error: &ErrorInfo{
-			Code:    "INTERNAL_ERROR",
-			Message: message,
-			Details: details,
-		},
+		Error:   newErrorInfo(c, "INTERNAL_ERROR", message, details),
 	})
 }
 
 // NotFound 리소스를 찾을 수 없음 응답을 반환합니다
-func NotFound(c echo.Context, message string) error {
+func NotFound(c echo.Context, message string, details string) error {
 	if message == "" {
 		message = "요청한 리소스를 찾을 수 없습니다"
 	}
@@ -92,10 +122,7 @@ func NotFound(c echo.Context, message string) error {
 	return c.JSON(http.StatusNotFound, Response{
 		Success: false,
 		Message: message,
-		Error: &ErrorInfo{
-			Code:    "NOT_FOUND",
-			Message: message,
-		},
+		Error:   newErrorInfo(c, "NOT_FOUND", message, details),
 	})
 }
 
@@ -108,10 +135,7 @@ func Unauthorized(c echo.Context, message string) error {
 	return c.JSON(http.StatusUnauthorized, Response{
 		Success: false,
 		Message: message,
-		Error: &ErrorInfo{
-			Code:    "UNAUTHORIZED",
-			Message: message,
-		},
+		Error:   newErrorInfo(c, "UNAUTHORIZED", message, ""),
 	})
 }
 
@@ -124,9 +148,60 @@ func Forbidden(c echo.Context, message string) error {
 	return c.JSON(http.StatusForbidden, Response{
 		Success: false,
 		Message: message,
-		Error: &ErrorInfo{
-			Code:    "FORBIDDEN",
-			Message: message,
-		},
+		Error:   newErrorInfo(c, "FORBIDDEN", message, ""),
+	})
+}
+
+// Conflict 리소스 상태 충돌 응답을 반환합니다
+func Conflict(c echo.Context, message string, details string) error {
+	if message == "" {
+		message = "요청이 현재 리소스 상태와 충돌합니다"
+	}
+
+	return c.JSON(http.StatusConflict, Response{
+		Success: false,
+		Message: message,
+		Error:   newErrorInfo(c, "CONFLICT", message, details),
+	})
+}
+
+// PayloadTooLarge 요청 데이터가 허용 크기를 넘음 응답을 반환합니다
+func PayloadTooLarge(c echo.Context, message string, details string) error {
+	if message == "" {
+		message = "요청 데이터가 너무 큽니다"
+	}
+
+	return c.JSON(http.StatusRequestEntityTooLarge, Response{
+		Success: false,
+		Message: message,
+		Error:   newErrorInfo(c, "PAYLOAD_TOO_LARGE", message, details),
+	})
+}
+
+// TooManyRequests 요청 횟수 제한 초과 응답을 반환합니다
+//
+// 재시도 시점은 호출자가 Retry-After 헤더로 설정합니다.
+func TooManyRequests(c echo.Context, message string, details string) error {
+	if message == "" {
+		message = "요청이 너무 많습니다"
+	}
+
+	return c.JSON(http.StatusTooManyRequests, Response{
+		Success: false,
+		Message: message,
+		Error:   newErrorInfo(c, "TOO_MANY_REQUESTS", message, details),
+	})
+}
+
+// ServiceUnavailable 서비스를 사용할 수 없음 응답을 반환합니다
+func ServiceUnavailable(c echo.Context, message string, details string) error {
+	if message == "" {
+		message = "서비스를 사용할 수 없습니다"
+	}
+
+	return c.JSON(http.StatusServiceUnavailable, Response{
+		Success: false,
+		Message: message,
+		Error:   newErrorInfo(c, "SERVICE_UNAVAILABLE", message, details),
 	})
 }
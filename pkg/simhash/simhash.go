@@ -0,0 +1,181 @@
+// Package simhash computes 64-bit SimHash signatures of byte streams for near-duplicate detection.
+// Features are rolling-hash windows sampled by content, so the signature is computed in one pass
+// with constant memory and small edits only change the few features around them.
+package simhash
+
+import "math/bits"
+
+// 시그니처 관련 상수
+const (
+	// Bits 시그니처 길이 (비트)
+	Bits = 64
+
+	// WindowSize 특징(feature)으로 사용하는 연속 바이트 수
+	WindowSize = 32
+
+	// MinFeatures 시그니처를 신뢰할 수 있는 최소 특징 수
+	//
+	// 특징이 적으면 몇 바이트 차이로도 시그니처가 크게 바뀌거나, 반대로 우연히
+	// 가까워질 수 있어 이보다 적으면 Signature가 시그니처를 반환하지 않습니다.
+	// 표본 비율상 대략 512바이트 이상의 입력이 필요합니다.
+	MinFeatures = 16
+
+	// MaxThreshold 유사 판정에 허용하는 최대 해밍 거리
+	//
+	// 서로 무관한 입력의 거리는 평균 32(표준편차 4)이므로 이보다 크면 오탐이
+	// 급격히 늘어납니다.
+	MaxThreshold = 16
+
+	// sampleMask 롤링 해시의 하위 비트가 모두 0인 창만 특징으로 사용 (1/32 표본)
+	//
+	// 위치가 아니라 내용으로 고르므로 앞부분에 바이트가 끼어들어도 뒤쪽 특징은 그대로입니다.
+	sampleMask = 1<<5 - 1
+
+	// planeCount 특징 비트를 세는 비트 슬라이스 카운터의 자릿수 (2^planeCount-1개마다 합산)
+	planeCount = 8
+)
+
+// table 바이트별 롤링 해시 값, outTable 창을 벗어나는 바이트의 기여분
+var table, outTable = buildTables()
+
+// Hasher 쓰여진 데이터의 SimHash를 계산하는 Writer
+//
+// 크기와 관계없이 고정된 메모리를 사용하므로 업로드 스트림에 io.MultiWriter로
+// 연결해 다른 해시와 함께 한 번에 계산합니다. 동시에 사용할 수 없습니다.
+type Hasher struct {
+	window   [WindowSize]byte
+	pos      uint
+	filled   int
+	rolling  uint64
+	features int
+
+	// 비트 위치별 1의 개수: 최근 특징은 planes(비트 슬라이스 이진 카운터)에,
+	// 나머지는 ones에 누적합니다. 특징마다 64개 카운터를 갱신하지 않아도 됩니다.
+	planes  [planeCount]uint64
+	pending int
+	ones    [Bits]int32
+}
+
+// New 새로운 SimHash 계산기를 생성합니다
+func New() *Hasher {
+	return &Hasher{}
+}
+
+// Write 데이터를 롤링 해시에 반영하고 표본으로 고른 창을 특징으로 누적합니다
+func (h *Hasher) Write(p []byte) (int, error) {
+	written := len(p)
+
+	// 창이 찰 때까지는 특징을 만들지 않음
+	for h.filled < WindowSize && len(p) > 0 {
+		h.window[h.pos%WindowSize] = p[0]
+		h.pos++
+		h.rolling = bits.RotateLeft64(h.rolling, 1) ^ table[p[0]]
+		h.filled++
+		p = p[1:]
+		if h.filled == WindowSize && h.rolling&sampleMask == 0 {
+			h.add(mix(h.rolling))
+		}
+	}
+
+	window, rolling, pos := &h.window, h.rolling, h.pos
+	for _, b := range p {
+		i := pos % WindowSize
+		rolling = bits.RotateLeft64(rolling, 1) ^ outTable[window[i]] ^ table[b]
+		window[i] = b
+		pos++
+
+		if rolling&sampleMask == 0 {
+			h.add(mix(rolling))
+		}
+	}
+	h.rolling, h.pos = rolling, pos
+
+	return written, nil
+}
+
+// Sum64 지금까지 쓰인 데이터의 시그니처를 반환합니다 (특징이 없으면 0)
+//
+// 비트 위치마다 1인 특징이 과반이면 그 비트를 1로 둡니다.
+func (h *Hasher) Sum64() uint64 {
+	var signature uint64
+	for i, ones := range h.ones {
+		if 2*(ones+h.pendingOnes(uint(i))) > int32(h.features) {
+			signature |= 1 << uint(i)
+		}
+	}
+	return signature
+}
+
+// Features 누적된 특징 수를 반환합니다
+func (h *Hasher) Features() int {
+	return h.features
+}
+
+// Signature 특징이 MinFeatures 이상일 때만 시그니처를 반환합니다
+func (h *Hasher) Signature() (uint64, bool) {
+	if h.features < MinFeatures {
+		return 0, false
+	}
+	return h.Sum64(), true
+}
+
+// Distance 두 시그니처의 해밍 거리(다른 비트 수)를 반환합니다
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// add 특징의 1인 비트를 비트 슬라이스 카운터에 더합니다 (평균 두 자리만 올림 처리)
+func (h *Hasher) add(feature uint64) {
+	carry := feature
+	for k := range h.planes {
+		next := h.planes[k] & carry
+		h.planes[k] ^= carry
+		carry = next
+		if carry == 0 {
+			break
+		}
+	}
+	h.features++
+
+	// 카운터가 넘치기 전에 비트 위치별 합계로 옮김
+	h.pending++
+	if h.pending == 1<<planeCount-1 {
+		for i := range h.ones {
+			h.ones[i] += h.pendingOnes(uint(i))
+		}
+		h.planes = [planeCount]uint64{}
+		h.pending = 0
+	}
+}
+
+// pendingOnes 비트 슬라이스 카운터에 남아 있는 비트 위치 i의 1의 개수
+func (h *Hasher) pendingOnes(i uint) int32 {
+	var count int32
+	for k, plane := range h.planes {
+		count |= int32(plane>>i&1) << uint(k)
+	}
+	return count
+}
+
+// mix 롤링 해시를 비트가 고르게 퍼진 특징 값으로 바꿉니다 (splitmix64 최종 단계)
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// buildTables 고정 시드로 바이트별 롤링 해시 값을 만듭니다
+//
+// 저장된 시그니처와 비교하려면 값이 실행마다 같아야 하므로 시드를 바꾸면 안 됩니다.
+func buildTables() (t, out [256]uint64) {
+	seed := uint64(0x5d4e4c6f636b6572)
+	for i := range t {
+		seed += 0x9e3779b97f4a7c15
+		t[i] = mix(seed)
+		out[i] = bits.RotateLeft64(t[i], WindowSize)
+	}
+	return t, out
+}
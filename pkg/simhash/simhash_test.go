@@ -0,0 +1,142 @@
+package simhash
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 유사 판정 테스트에 사용하는 임계값 (서버 기본값과 같음)
+const testThreshold = 3
+
+// generateDocument 고정 시드로 단어를 이어 만든 문서를 반환합니다
+func generateDocument(seed int64, words int) []byte {
+	vocabulary := strings.Fields("암호화 파일 저장소 revision draft contract 계약 조항 payment invoice " +
+		"the of and to in 업로드 검증 metadata volume 보고서 summary section clause amount date party")
+	rng := rand.New(rand.NewSource(seed)) //nolint:gosec // 테스트 데이터
+	var buf bytes.Buffer
+	for i := 0; i < words; i++ {
+		buf.WriteString(vocabulary[rng.Intn(len(vocabulary))])
+		if i%12 == 11 {
+			buf.WriteString(".\n")
+		} else {
+			buf.WriteByte(' ')
+		}
+	}
+	return buf.Bytes()
+}
+
+// signature 데이터 전체의 시그니처를 계산합니다
+func signature(t testing.TB, data []byte) uint64 {
+	t.Helper()
+	h := New()
+	_, _ = h.Write(data)
+	sig, ok := h.Signature()
+	require.True(t, ok, "특징 수: %d", h.Features())
+	return sig
+}
+
+func TestHasher_StreamingMatchesSingleWrite(t *testing.T) {
+	doc := generateDocument(1, 5000)
+	want := signature(t, doc)
+
+	for _, chunk := range []int{1, 7, WindowSize, 4096} {
+		h := New()
+		for rest := doc; len(rest) > 0; {
+			n := min(chunk, len(rest))
+			_, _ = h.Write(rest[:n])
+			rest = rest[n:]
+		}
+		assert.Equal(t, want, h.Sum64(), "청크 크기 %d", chunk)
+	}
+}
+
+func TestHasher_NearDuplicates(t *testing.T) {
+	original := generateDocument(1, 20000)
+	sig := signature(t, original)
+
+	revisions := map[string][]byte{
+		"단어 하나 수정":   bytes.Replace(original, []byte("contract"), []byte("contracts"), 1),
+		"앞부분에 문단 추가": append([]byte("개정 이력: 2판에서 지급 조항을 수정했습니다.\n"), original...),
+		"끝에 서명 추가":   append(append([]byte{}, original...), []byte("\n서명: ____\n")...),
+		"중간 문단 삭제":   append(append([]byte{}, original[:len(original)/2]...), original[len(original)/2+400:]...),
+	}
+	for name, revision := range revisions {
+		distance := Distance(sig, signature(t, revision))
+		assert.LessOrEqual(t, distance, testThreshold, name)
+	}
+}
+
+func TestHasher_UnrelatedDocumentsAreFar(t *testing.T) {
+	// 같은 어휘로 만든 다른 문서도 임계값을 크게 넘어야 함 (오탐 방지)
+	signatures := make([]uint64, 20)
+	for i := range signatures {
+		signatures[i] = signature(t, generateDocument(int64(100+i), 20000))
+	}
+
+	minDistance := Bits
+	for i := range signatures {
+		for j := i + 1; j < len(signatures); j++ {
+			minDistance = min(minDistance, Distance(signatures[i], signatures[j]))
+		}
+	}
+	assert.Greater(t, minDistance, MaxThreshold, "무관한 문서 사이의 최소 거리")
+}
+
+func TestHasher_SmallInputHasNoSignature(t *testing.T) {
+	h := New()
+	_, _ = h.Write([]byte("짧은 메모"))
+	_, ok := h.Signature()
+	assert.False(t, ok)
+	assert.Zero(t, h.Features())
+
+	// 창 크기보다 긴 입력은 표본으로 고른 창 수만큼 특징이 생김
+	h = New()
+	_, _ = h.Write(generateDocument(1, 40))
+	assert.Positive(t, h.Features())
+	assert.Less(t, h.Features(), MinFeatures)
+	_, ok = h.Signature()
+	assert.False(t, ok, "특징이 부족하면 시그니처 없음")
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, Distance(0xdeadbeef, 0xdeadbeef))
+	assert.Equal(t, 64, Distance(0, ^uint64(0)))
+	assert.Equal(t, 3, Distance(0b1011, 0b0000))
+}
+
+func BenchmarkHasher_Write(b *testing.B) {
+	data := generateDocument(1, 1<<18) // 약 2MB
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h := New()
+		_, _ = h.Write(data)
+		_ = h.Sum64()
+	}
+}
+
+func BenchmarkDistance(b *testing.B) {
+	signatures := make([]uint64, 1<<16)
+	rng := rand.New(rand.NewSource(1)) //nolint:gosec // 벤치마크 데이터
+	for i := range signatures {
+		signatures[i] = rng.Uint64()
+	}
+	target := signatures[0]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matches := 0
+		for _, sig := range signatures {
+			if Distance(target, sig) <= testThreshold {
+				matches++
+			}
+		}
+		if matches == 0 {
+			b.Fatal("자기 자신도 찾지 못함")
+		}
+	}
+}